@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 
 	"github.com/bytedance/sonic"
 
@@ -41,6 +42,7 @@ var (
 type AgentToolOptions struct {
 	fullChatHistoryAsInput bool
 	agentInputSchema       *schema.ParamsOneOf
+	streamOutput           bool
 }
 
 type AgentToolOption func(*AgentToolOptions)
@@ -57,17 +59,34 @@ func WithAgentInputSchema(schema *schema.ParamsOneOf) AgentToolOption {
 	}
 }
 
+// WithAgentToolStreamOutput makes the agent tool a tool.StreamableTool besides tool.InvokableTool,
+// which runs the agent in streaming mode and streams the content of the assistant and the tool messages produced by the agent
+// as they arrive, so the caller can see the progress of the agent, just like other streaming tools.
+// Unlike InvokableRun, which returns the last message only, the stream includes the intermediate messages.
+func WithAgentToolStreamOutput() AgentToolOption {
+	return func(options *AgentToolOptions) {
+		options.streamOutput = true
+	}
+}
+
+// NewAgentTool wraps the agent as a tool.InvokableTool, so the agent can be called by other agents just like other tools.
+// The output of the tool is the content of the last message of the agent.
+// Use WithAgentToolStreamOutput to make it a tool.StreamableTool as well.
 func NewAgentTool(_ context.Context, agent Agent, options ...AgentToolOption) tool.BaseTool {
 	opts := &AgentToolOptions{}
 	for _, opt := range options {
 		opt(opts)
 	}
 
-	return &agentTool{
+	at := &agentTool{
 		agent:                  agent,
 		fullChatHistoryAsInput: opts.fullChatHistoryAsInput,
 		inputSchema:            opts.agentInputSchema,
 	}
+	if opts.streamOutput {
+		return &streamableAgentTool{agentTool: at}
+	}
+	return at
 }
 
 type agentTool struct {
@@ -91,6 +110,55 @@ func (at *agentTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
 }
 
 func (at *agentTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	iter, ms, err := at.runAgent(ctx, argumentsInJSON, false, opts...)
+	if err != nil {
+		return "", err
+	}
+
+	var lastEvent *AgentEvent
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		if event.Err != nil {
+			return "", event.Err
+		}
+
+		lastEvent = event
+	}
+
+	if lastEvent != nil && lastEvent.Action != nil && lastEvent.Action.Interrupted != nil {
+		return "", at.saveInterrupt(ctx, lastEvent, ms)
+	}
+
+	if lastEvent == nil {
+		return "", errors.New("no event returned")
+	}
+
+	var ret string
+	if lastEvent.Output != nil {
+		if output := lastEvent.Output.MessageOutput; output != nil {
+			if !output.IsStreaming {
+				ret = output.Message.Content
+			} else {
+				msg, err := schema.ConcatMessageStream(output.MessageStream)
+				if err != nil {
+					return "", err
+				}
+				ret = msg.Content
+			}
+		}
+	}
+
+	return ret, nil
+}
+
+// runAgent runs or resumes the agent according to the interrupt data of the tool call saved in state.
+func (at *agentTool) runAgent(ctx context.Context, argumentsInJSON string, enableStreaming bool,
+	opts ...tool.Option) (*AsyncIterator[*AgentEvent], *mockStore, error) {
+
 	var intData *agentToolInterruptInfo
 	var bResume bool
 	err := compose.ProcessState(ctx, func(ctx context.Context, s *State) error {
@@ -106,102 +174,174 @@ func (at *agentTool) InvokableRun(ctx context.Context, argumentsInJSON string, o
 		bResume = false
 	}
 
-	var ms *mockStore
-	var iter *AsyncIterator[*AgentEvent]
 	if bResume {
-		ms = newResumeStore(intData.Data)
+		ms := newResumeStore(intData.Data)
 
-		iter, err = newInvokableAgentToolRunner(at.agent, ms).Resume(ctx, mockCheckPointID, getOptionsByAgentName(at.agent.Name(ctx), opts)...)
+		iter, err := newAgentToolRunner(at.agent, ms, enableStreaming).Resume(ctx, mockCheckPointID, getOptionsByAgentName(at.agent.Name(ctx), opts)...)
 		if err != nil {
-			return "", err
+			return nil, nil, err
 		}
-	} else {
-		ms = newEmptyStore()
-		var input []Message
-		if at.fullChatHistoryAsInput {
-			history, err := getReactChatHistory(ctx, at.agent.Name(ctx))
-			if err != nil {
-				return "", err
-			}
+		return iter, ms, nil
+	}
 
-			input = history
-		} else {
-			if at.inputSchema == nil {
-				// default input schema
-				type request struct {
-					Request string `json:"request"`
-				}
+	ms := newEmptyStore()
+	var input []Message
+	if at.fullChatHistoryAsInput {
+		history, err := getReactChatHistory(ctx, at.agent.Name(ctx))
+		if err != nil {
+			return nil, nil, err
+		}
 
-				req := &request{}
-				err = sonic.UnmarshalString(argumentsInJSON, req)
-				if err != nil {
-					return "", err
-				}
-				argumentsInJSON = req.Request
+		input = history
+	} else {
+		if at.inputSchema == nil {
+			// default input schema
+			type request struct {
+				Request string `json:"request"`
 			}
-			input = []Message{
-				schema.UserMessage(argumentsInJSON),
+
+			req := &request{}
+			err = sonic.UnmarshalString(argumentsInJSON, req)
+			if err != nil {
+				return nil, nil, err
 			}
+			argumentsInJSON = req.Request
+		}
+		input = []Message{
+			schema.UserMessage(argumentsInJSON),
 		}
-
-		iter = newInvokableAgentToolRunner(at.agent, ms).Run(ctx, input, append(getOptionsByAgentName(at.agent.Name(ctx), opts), WithCheckPointID(mockCheckPointID))...)
 	}
 
-	var lastEvent *AgentEvent
-	for {
-		event, ok := iter.Next()
-		if !ok {
-			break
-		}
+	iter := newAgentToolRunner(at.agent, ms, enableStreaming).Run(ctx, input, append(getOptionsByAgentName(at.agent.Name(ctx), opts), WithCheckPointID(mockCheckPointID))...)
+	return iter, ms, nil
+}
 
-		if event.Err != nil {
-			return "", event.Err
+// saveInterrupt saves the checkpoint of the interrupted agent to state, and returns the interrupt error of the tool.
+func (at *agentTool) saveInterrupt(ctx context.Context, lastEvent *AgentEvent, ms *mockStore) error {
+	data, existed, err := ms.Get(ctx, mockCheckPointID)
+	if err != nil {
+		return fmt.Errorf("failed to get interrupt info: %w", err)
+	}
+	if !existed {
+		return fmt.Errorf("interrupt has happened, but cannot find interrupt info")
+	}
+	err = compose.ProcessState(ctx, func(ctx context.Context, st *State) error {
+		st.AgentToolInterruptData[compose.GetToolCallID(ctx)] = &agentToolInterruptInfo{
+			LastEvent: lastEvent,
+			Data:      data,
 		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save agent tool checkpoint to state: %w", err)
+	}
+	return compose.InterruptAndRerun
+}
 
-		lastEvent = event
+// streamableAgentTool is the agent tool created with WithAgentToolStreamOutput.
+type streamableAgentTool struct {
+	*agentTool
+}
+
+// StreamableRun runs the agent in streaming mode, and streams the content of the assistant and the tool messages
+// produced by the agent, including the intermediate ones, as soon as they are generated.
+// If the agent is interrupted before any content is streamed, the tool is interrupted and can be resumed as InvokableRun.
+// An interrupt after the streaming starts cannot be propagated, and is reported as an error in the stream.
+func (at *streamableAgentTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	iter, ms, err := at.runAgent(ctx, argumentsInJSON, true, opts...)
+	if err != nil {
+		return nil, err
 	}
 
-	if lastEvent != nil && lastEvent.Action != nil && lastEvent.Action.Interrupted != nil {
-		data, existed, err_ := ms.Get(ctx, mockCheckPointID)
-		if err_ != nil {
-			return "", fmt.Errorf("failed to get interrupt info: %w", err_)
+	sr, sw := schema.Pipe[string](10)
+	started := make(chan error, 1)
+	go func() {
+		defer sw.Close()
+
+		streaming := false
+		notify := func(err error) {
+			if !streaming {
+				streaming = true
+				started <- err
+			}
 		}
-		if !existed {
-			return "", fmt.Errorf("interrupt has happened, but cannot find interrupt info")
+		defer notify(nil)
+
+		var lastEvent *AgentEvent
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if event.Err != nil {
+				if !streaming {
+					notify(event.Err)
+					return
+				}
+				sw.Send("", event.Err)
+				return
+			}
+			lastEvent = event
+
+			if event.Output == nil || event.Output.MessageOutput == nil {
+				continue
+			}
+			if role := event.Output.MessageOutput.Role; role != schema.Assistant && role != schema.Tool {
+				continue
+			}
+			if err := forwardAgentMessage(event.Output.MessageOutput, sw, notify); err != nil {
+				return
+			}
 		}
-		err = compose.ProcessState(ctx, func(ctx context.Context, st *State) error {
-			st.AgentToolInterruptData[compose.GetToolCallID(ctx)] = &agentToolInterruptInfo{
-				LastEvent: lastEvent,
-				Data:      data,
+
+		if lastEvent != nil && lastEvent.Action != nil && lastEvent.Action.Interrupted != nil {
+			if !streaming {
+				notify(at.saveInterrupt(ctx, lastEvent, ms))
+				return
 			}
-			return nil
-		})
-		if err != nil {
-			return "", fmt.Errorf("failed to save agent tool checkpoint to state: %w", err)
+			sw.Send("", errors.New("agent tool is interrupted after streaming started, which cannot be resumed"))
 		}
-		return "", compose.InterruptAndRerun
-	}
+	}()
 
-	if lastEvent == nil {
-		return "", errors.New("no event returned")
+	if err = <-started; err != nil {
+		sr.Close()
+		return nil, err
 	}
+	return sr, nil
+}
 
-	var ret string
-	if lastEvent.Output != nil {
-		if output := lastEvent.Output.MessageOutput; output != nil {
-			if !output.IsStreaming {
-				ret = output.Message.Content
-			} else {
-				msg, err := schema.ConcatMessageStream(output.MessageStream)
-				if err != nil {
-					return "", err
-				}
-				ret = msg.Content
-			}
+// forwardAgentMessage sends the content of the message to sw, notify is called before the first non-empty content is sent.
+func forwardAgentMessage(mv *MessageVariant, sw *schema.StreamWriter[string], notify func(error)) error {
+	if !mv.IsStreaming {
+		if mv.Message == nil || mv.Message.Content == "" {
+			return nil
+		}
+		notify(nil)
+		if sw.Send(mv.Message.Content, nil) {
+			return errors.New("stream closed")
 		}
+		return nil
 	}
 
-	return ret, nil
+	defer mv.MessageStream.Close()
+	for {
+		chunk, err := mv.MessageStream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			notify(nil)
+			sw.Send("", err)
+			return err
+		}
+		if chunk == nil || chunk.Content == "" {
+			continue
+		}
+		notify(nil)
+		if sw.Send(chunk.Content, nil) {
+			return errors.New("stream closed")
+		}
+	}
 }
 
 // agentToolOptions is a wrapper structure used to convert AgentRunOption slices to tool.Option.
@@ -257,10 +397,10 @@ func getReactChatHistory(ctx context.Context, destAgentName string) ([]Message,
 	return history, err
 }
 
-func newInvokableAgentToolRunner(agent Agent, store compose.CheckPointStore) *Runner {
+func newAgentToolRunner(agent Agent, store compose.CheckPointStore, enableStreaming bool) *Runner {
 	return &Runner{
 		a:               agent,
-		enableStreaming: false,
+		enableStreaming: enableStreaming,
 		store:           store,
 	}
 }
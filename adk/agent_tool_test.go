@@ -217,3 +217,57 @@ func TestGetReactHistory(t *testing.T) {
 		schema.UserMessage("For context: [MyAgent] `transfer_to_agent` tool returned result: successfully transferred to agent [DestAgentName]."),
 	}, result)
 }
+
+func TestAgentTool_StreamableRun(t *testing.T) {
+	ctx := context.Background()
+
+	mockAgent := newMockAgentForTool("TestAgent", "Test agent description", []*AgentEvent{
+		EventFromMessage(nil, schema.StreamReaderFromArray([]Message{
+			schema.AssistantMessage("thinking", nil),
+			schema.AssistantMessage("...", nil),
+		}), schema.Assistant, ""),
+		EventFromMessage(schema.ToolMessage("tool result", "1"), nil, schema.Tool, "tool"),
+		EventFromMessage(nil, schema.StreamReaderFromArray([]Message{
+			schema.AssistantMessage("do", nil),
+			schema.AssistantMessage("ne", nil),
+		}), schema.Assistant, ""),
+	})
+
+	_, ok := NewAgentTool(ctx, mockAgent).(tool.StreamableTool)
+	assert.False(t, ok)
+
+	agentTool := NewAgentTool(ctx, mockAgent, WithAgentToolStreamOutput())
+	st, ok := agentTool.(tool.StreamableTool)
+	assert.True(t, ok)
+	_, ok = agentTool.(tool.InvokableTool)
+	assert.True(t, ok)
+
+	sr, err := st.StreamableRun(ctx, `{"request":"hi"}`)
+	assert.NoError(t, err)
+	var chunks []string
+	for {
+		chunk, err := sr.Recv()
+		if err != nil {
+			break
+		}
+		chunks = append(chunks, chunk)
+	}
+	assert.Equal(t, []string{"thinking", "...", "tool result", "do", "ne"}, chunks)
+
+	errAgent := newMockAgentForTool("ErrAgent", "", []*AgentEvent{{Err: assert.AnError}})
+	_, err = NewAgentTool(ctx, errAgent, WithAgentToolStreamOutput()).(tool.StreamableTool).StreamableRun(ctx, `{"request":"hi"}`)
+	assert.ErrorIs(t, err, assert.AnError)
+
+	// an interrupt after the streaming started is reported in the stream
+	interruptAgent := newMockAgentForTool("InterruptAgent", "", []*AgentEvent{
+		EventFromMessage(schema.AssistantMessage("working", nil), nil, schema.Assistant, ""),
+		{Action: &AgentAction{Interrupted: &InterruptInfo{}}},
+	})
+	sr, err = NewAgentTool(ctx, interruptAgent, WithAgentToolStreamOutput()).(tool.StreamableTool).StreamableRun(ctx, `{"request":"hi"}`)
+	assert.NoError(t, err)
+	chunk, err := sr.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, "working", chunk)
+	_, err = sr.Recv()
+	assert.ErrorContains(t, err, "interrupted after streaming started")
+}
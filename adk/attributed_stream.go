@@ -0,0 +1,220 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"io"
+	"runtime/debug"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/internal/safe"
+)
+
+// AttributedChunk is a piece of the output of a run, attributed to the agent which produced it.
+// Message chunks of all the events, streaming or not, are flattened into AttributedChunks,
+// so the outputs of sub-agents streaming concurrently, e.g. in a parallel agent, can be told apart.
+type AttributedChunk struct {
+	AgentName string
+	RunPath   []RunStep
+	// AgentPath is the names of the agents in RunPath joined by "/", which identifies the agent in the run.
+	AgentPath string
+
+	// Seq is the order of the chunk in the merged stream, starting from 0.
+	Seq int
+	// EventSeq is the order of the event the chunk belongs to in the original event stream, starting from 0.
+	EventSeq int
+	// ChunkIndex is the order of the chunk within its event, starting from 0.
+	ChunkIndex int
+	// Last reports whether the chunk is the last one of its event.
+	Last bool
+
+	// Message is the message chunk, nil if the event carries no message output, or the chunk carries an error.
+	Message Message
+	// Err is the error of the event, or the error when receiving the message stream.
+	Err error
+	// Event is the original event, shared by all the chunks of the event.
+	// Its MessageStream has been consumed, so use Message instead.
+	Event *AgentEvent
+}
+
+// AgentPathOf returns the names of the agents in the run path joined by "/".
+func AgentPathOf(runPath []RunStep) string {
+	names := make([]string, 0, len(runPath))
+	for _, step := range runPath {
+		names = append(names, step.agentName)
+	}
+	return strings.Join(names, "/")
+}
+
+// MergeEventStreams flattens the events of a run into AttributedChunks.
+// The message streams of the events are received concurrently, and the chunks are emitted as soon as they arrive,
+// so chunks of different agents interleave in the merged stream as they are produced,
+// while chunks of the same event keep their order.
+// Events without message output, e.g. actions and errors, are emitted as a single chunk.
+// e.g.
+//
+//	chunks := adk.MergeEventStreams(runner.Query(ctx, query))
+//	for {
+//		chunk, ok := chunks.Next()
+//		if !ok {
+//			break
+//		}
+//		render(chunk.AgentPath, chunk.Message)
+//	}
+func MergeEventStreams(iter *AsyncIterator[*AgentEvent]) *AsyncIterator[*AttributedChunk] {
+	niter, gen := NewAsyncIteratorPair[*AttributedChunk]()
+
+	var mu sync.Mutex
+	seq := 0
+	send := func(c *AttributedChunk) {
+		mu.Lock()
+		defer mu.Unlock()
+		c.Seq = seq
+		seq++
+		gen.Send(c)
+	}
+
+	go func() {
+		var wg sync.WaitGroup
+		defer func() {
+			panicErr := recover()
+			if panicErr != nil {
+				send(&AttributedChunk{Err: safe.NewPanicErr(panicErr, debug.Stack()), Last: true})
+			}
+			wg.Wait()
+			gen.Close()
+		}()
+
+		for i := 0; ; i++ {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			eventSeq := i
+
+			newChunk := func(idx int) *AttributedChunk {
+				return &AttributedChunk{
+					AgentName:  event.AgentName,
+					RunPath:    event.RunPath,
+					AgentPath:  AgentPathOf(event.RunPath),
+					EventSeq:   eventSeq,
+					ChunkIndex: idx,
+					Event:      event,
+				}
+			}
+
+			if event.Output == nil || event.Output.MessageOutput == nil || !event.Output.MessageOutput.IsStreaming {
+				c := newChunk(0)
+				c.Err = event.Err
+				c.Last = true
+				if event.Output != nil && event.Output.MessageOutput != nil {
+					c.Message = event.Output.MessageOutput.Message
+				}
+				send(c)
+				continue
+			}
+
+			wg.Add(1)
+			go func(stream MessageStream) {
+				defer func() {
+					panicErr := recover()
+					if panicErr != nil {
+						c := newChunk(0)
+						c.Err = safe.NewPanicErr(panicErr, debug.Stack())
+						c.Last = true
+						send(c)
+					}
+					wg.Done()
+				}()
+				defer stream.Close()
+
+				// hold a chunk back so that the last one can be marked
+				var pending *AttributedChunk
+				for idx := 0; ; idx++ {
+					msg, err := stream.Recv()
+					if err == io.EOF {
+						break
+					}
+					if pending != nil {
+						send(pending)
+					}
+					pending = newChunk(idx)
+					if err != nil {
+						pending.Err = err
+						break
+					}
+					pending.Message = msg
+				}
+				if pending == nil {
+					pending = newChunk(0)
+				}
+				pending.Last = true
+				send(pending)
+			}(event.Output.MessageOutput.MessageStream)
+		}
+	}()
+
+	return niter
+}
+
+// AgentChunkStream is the chunks of one agent demultiplexed from a merged stream.
+type AgentChunkStream struct {
+	AgentName string
+	RunPath   []RunStep
+	AgentPath string
+	Chunks    *AsyncIterator[*AttributedChunk]
+}
+
+// DemuxAttributedChunks splits a merged stream back per agent, identified by AgentPath.
+// An AgentChunkStream is emitted when the first chunk of an agent arrives, and all of them are closed when the merged stream ends.
+// The streams are buffered, so they can be consumed in any order, e.g. rendered in a panel per agent.
+func DemuxAttributedChunks(iter *AsyncIterator[*AttributedChunk]) *AsyncIterator[*AgentChunkStream] {
+	niter, gen := NewAsyncIteratorPair[*AgentChunkStream]()
+
+	go func() {
+		gens := make(map[string]*AsyncGenerator[*AttributedChunk])
+		defer func() {
+			for _, g := range gens {
+				g.Close()
+			}
+			gen.Close()
+		}()
+
+		for {
+			chunk, ok := iter.Next()
+			if !ok {
+				return
+			}
+			g, ok := gens[chunk.AgentPath]
+			if !ok {
+				var chunks *AsyncIterator[*AttributedChunk]
+				chunks, g = NewAsyncIteratorPair[*AttributedChunk]()
+				gens[chunk.AgentPath] = g
+				gen.Send(&AgentChunkStream{
+					AgentName: chunk.AgentName,
+					RunPath:   chunk.RunPath,
+					AgentPath: chunk.AgentPath,
+					Chunks:    chunks,
+				})
+			}
+			g.Send(chunk)
+		}
+	}()
+
+	return niter
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestMergeEventStreams(t *testing.T) {
+	ctx := context.Background()
+
+	sa, wa := schema.Pipe[Message](10)
+	sb, wb := schema.Pipe[Message](10)
+	a := newMockRunnerAgent("a", "a", []*AgentEvent{EventFromMessage(nil, sa, schema.Assistant, "")})
+	b := newMockRunnerAgent("b", "b", []*AgentEvent{
+		EventFromMessage(nil, sb, schema.Assistant, ""),
+		{Err: errors.New("b failed")},
+	})
+	par, err := NewParallelAgent(ctx, &ParallelAgentConfig{Name: "par", Description: "par", SubAgents: []Agent{a, b}})
+	assert.NoError(t, err)
+
+	chunks := MergeEventStreams(NewRunner(ctx, RunnerConfig{Agent: par, EnableStreaming: true}).Query(ctx, "hi"))
+	var received []*AttributedChunk
+	next := func() *AttributedChunk {
+		c, ok := chunks.Next()
+		assert.True(t, ok)
+		received = append(received, c)
+		return c
+	}
+
+	wa.Send(schema.AssistantMessage("a1", nil), nil)
+	wa.Send(schema.AssistantMessage("a2", nil), nil)
+	// the chunks of b are received while the stream of a is still open
+	for c := next(); c.AgentPath != "par/a"; c = next() {
+	}
+	wb.Send(schema.AssistantMessage("b1", nil), nil)
+	wb.Close()
+	for c := next(); c.Message == nil || c.Message.Content != "b1"; c = next() {
+	}
+	wa.Close()
+	for {
+		c, ok := chunks.Next()
+		if !ok {
+			break
+		}
+		received = append(received, c)
+	}
+
+	contents := map[string][]string{}
+	for i, c := range received {
+		assert.Equal(t, i, c.Seq)
+		if c.Err != nil {
+			assert.Equal(t, "par/b", c.AgentPath)
+			assert.True(t, c.Last)
+			continue
+		}
+		contents[c.AgentPath] = append(contents[c.AgentPath], c.Message.Content)
+		assert.Equal(t, c.Message.Content == "a2" || c.Message.Content == "b1", c.Last)
+	}
+	assert.Equal(t, map[string][]string{"par/a": {"a1", "a2"}, "par/b": {"b1"}}, contents)
+
+	// demux the chunks back per agent
+	src, gen := NewAsyncIteratorPair[*AttributedChunk]()
+	for _, c := range received {
+		gen.Send(c)
+	}
+	gen.Close()
+	streams := DemuxAttributedChunks(src)
+	demuxed := map[string]int{}
+	for {
+		s, ok := streams.Next()
+		if !ok {
+			break
+		}
+		assert.Equal(t, "par", s.RunPath[0].agentName)
+		for {
+			c, ok := s.Chunks.Next()
+			if !ok {
+				break
+			}
+			assert.Equal(t, s.AgentPath, c.AgentPath)
+			demuxed[s.AgentPath]++
+		}
+	}
+	assert.Equal(t, map[string]int{"par/a": 2, "par/b": 2}, demuxed)
+}
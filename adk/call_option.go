@@ -20,6 +20,11 @@ type options struct {
 	sessionValues        map[string]any
 	checkPointID         *string
 	skipTransferMessages bool
+	session              *SessionScope
+	runID                *string
+	resumeRun            bool
+	budget               *RunBudget
+	budgetQuota          *budgetQuota
 }
 
 // AgentRunOption is the call option for adk Agent.
@@ -0,0 +1,252 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// Capability is a structured description of what an agent can do, advertised to routers through a CapabilityRegistry.
+type Capability struct {
+	Name        string
+	Description string
+	// InputSchema describes the input expected by the capability. Optional.
+	InputSchema *jsonschema.Schema
+	Tags        []string
+}
+
+// CapabilityProvider is implemented by agents advertising their own capabilities,
+// which are registered automatically by CapabilityRegistry.Register.
+type CapabilityProvider interface {
+	Capabilities(ctx context.Context) []*Capability
+}
+
+// AgentCapabilities are the capabilities of an agent in the registry.
+type AgentCapabilities struct {
+	AgentName    string
+	Description  string
+	Capabilities []*Capability
+}
+
+// CapabilityQuery filters the agents in a CapabilityRegistry, all conditions set must be met.
+type CapabilityQuery struct {
+	// AgentNames limits the agents to query. Optional.
+	AgentNames []string
+	// Tags requires the agent to have a capability with all the tags. Optional.
+	Tags []string
+	// Keyword requires the name or description of the agent or one of its capabilities to contain it, case-insensitively. Optional.
+	Keyword string
+}
+
+// CapabilityRegistry is a registry where agents advertise their capabilities, and routers, e.g. supervisors,
+// query it at runtime to decide where to route, so adding a specialist doesn't require editing router prompts.
+// It's safe for concurrent use.
+type CapabilityRegistry struct {
+	mu     sync.RWMutex
+	agents map[string]*AgentCapabilities
+}
+
+// NewCapabilityRegistry creates an empty CapabilityRegistry.
+func NewCapabilityRegistry() *CapabilityRegistry {
+	return &CapabilityRegistry{agents: make(map[string]*AgentCapabilities)}
+}
+
+// Register registers or replaces the capabilities of the agent,
+// the capabilities provided by the agent itself (see CapabilityProvider) are registered along with caps.
+func (r *CapabilityRegistry) Register(ctx context.Context, agent Agent, caps ...*Capability) error {
+	name := agent.Name(ctx)
+	if name == "" {
+		return fmt.Errorf("agent name is required to register capabilities")
+	}
+
+	var all []*Capability
+	if cp, ok := agent.(CapabilityProvider); ok {
+		all = append(all, cp.Capabilities(ctx)...)
+	}
+	all = append(all, caps...)
+	for _, c := range all {
+		if c == nil || c.Name == "" {
+			return fmt.Errorf("capability name of agent[%s] is required", name)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.agents[name] = &AgentCapabilities{
+		AgentName:    name,
+		Description:  agent.Description(ctx),
+		Capabilities: all,
+	}
+	return nil
+}
+
+// Unregister removes the agent from the registry.
+func (r *CapabilityRegistry) Unregister(agentName string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.agents, agentName)
+}
+
+// Has reports whether the agent is registered.
+func (r *CapabilityRegistry) Has(agentName string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.agents[agentName]
+	return ok
+}
+
+// Query returns the agents matching the query, sorted by agent name. A nil query returns all the agents.
+func (r *CapabilityRegistry) Query(q *CapabilityQuery) []*AgentCapabilities {
+	if q == nil {
+		q = &CapabilityQuery{}
+	}
+	var names map[string]bool
+	if len(q.AgentNames) > 0 {
+		names = make(map[string]bool, len(q.AgentNames))
+		for _, n := range q.AgentNames {
+			names[n] = true
+		}
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var ret []*AgentCapabilities
+	for name, ac := range r.agents {
+		if names != nil && !names[name] {
+			continue
+		}
+		if !ac.hasTags(q.Tags) || !ac.hasKeyword(q.Keyword) {
+			continue
+		}
+		ret = append(ret, ac)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i].AgentName < ret[j].AgentName
+	})
+	return ret
+}
+
+func (ac *AgentCapabilities) hasTags(tags []string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	for _, c := range ac.Capabilities {
+		if containsAll(c.Tags, tags) {
+			return true
+		}
+	}
+	return false
+}
+
+func (ac *AgentCapabilities) hasKeyword(keyword string) bool {
+	if keyword == "" {
+		return true
+	}
+	keyword = strings.ToLower(keyword)
+	contains := func(s string) bool {
+		return strings.Contains(strings.ToLower(s), keyword)
+	}
+	if contains(ac.AgentName) || contains(ac.Description) {
+		return true
+	}
+	for _, c := range ac.Capabilities {
+		if contains(c.Name) || contains(c.Description) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAll(set, subset []string) bool {
+	m := make(map[string]bool, len(set))
+	for _, s := range set {
+		m[s] = true
+	}
+	for _, s := range subset {
+		if !m[s] {
+			return false
+		}
+	}
+	return true
+}
+
+// Describe formats the agents matching the query for routing prompts.
+func (r *CapabilityRegistry) Describe(q *CapabilityQuery) string {
+	var sb strings.Builder
+	for _, ac := range r.Query(q) {
+		sb.WriteString(fmt.Sprintf("- Agent name: %s\n  Agent description: %s\n", ac.AgentName, ac.Description))
+		if len(ac.Capabilities) == 0 {
+			continue
+		}
+		sb.WriteString("  Capabilities:\n")
+		for _, c := range ac.Capabilities {
+			sb.WriteString(fmt.Sprintf("    - %s: %s\n", c.Name, c.Description))
+			if len(c.Tags) > 0 {
+				sb.WriteString(fmt.Sprintf("      Tags: %s\n", strings.Join(c.Tags, ", ")))
+			}
+			if c.InputSchema != nil {
+				if s, err := json.Marshal(c.InputSchema); err == nil {
+					sb.WriteString(fmt.Sprintf("      Input schema: %s\n", s))
+				}
+			}
+		}
+	}
+	return sb.String()
+}
+
+// GenModelInput returns a GenModelInput which appends the capabilities of the agents matching the query to the system instruction,
+// rendered at every run so that routing agents always see the latest registry. base is the default GenModelInput if nil.
+// e.g.
+//
+//	router, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+//		// ...
+//		GenModelInput: registry.GenModelInput(nil, &adk.CapabilityQuery{AgentNames: workerNames}),
+//	})
+func (r *CapabilityRegistry) GenModelInput(base GenModelInput, q *CapabilityQuery) GenModelInput {
+	if base == nil {
+		base = defaultGenModelInput
+	}
+	return func(ctx context.Context, instruction string, input *AgentInput) ([]Message, error) {
+		msgs, err := base(ctx, instruction, input)
+		if err != nil {
+			return nil, err
+		}
+
+		desc := r.Describe(q)
+		if desc == "" {
+			return msgs, nil
+		}
+		desc = "Capabilities of the available agents:\n" + desc
+		if len(msgs) > 0 && msgs[0].Role == schema.System {
+			sys := *msgs[0]
+			sys.Content = sys.Content + "\n\n" + desc
+			msgs[0] = &sys
+			return msgs, nil
+		}
+		return append([]Message{schema.SystemMessage(desc)}, msgs...), nil
+	}
+}
@@ -0,0 +1,92 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type capableAgent struct {
+	*mockRunnerAgent
+	caps []*Capability
+}
+
+func (c *capableAgent) Capabilities(_ context.Context) []*Capability {
+	return c.caps
+}
+
+func TestCapabilityRegistry(t *testing.T) {
+	ctx := context.Background()
+	r := NewCapabilityRegistry()
+
+	flights := &capableAgent{
+		mockRunnerAgent: newMockRunnerAgent("flights", "books flights", nil),
+		caps: []*Capability{{
+			Name:        "book_flight",
+			Description: "book a flight ticket",
+			InputSchema: &jsonschema.Schema{Type: "object"},
+			Tags:        []string{"travel", "booking"},
+		}},
+	}
+	assert.NoError(t, r.Register(ctx, flights))
+	assert.NoError(t, r.Register(ctx, newMockRunnerAgent("hotels", "books hotels", nil),
+		&Capability{Name: "book_hotel", Description: "book a hotel room", Tags: []string{"travel"}}))
+	assert.NoError(t, r.Register(ctx, newMockRunnerAgent("weather", "tells the weather", nil)))
+	assert.Error(t, r.Register(ctx, newMockRunnerAgent("bad", "bad", nil), &Capability{}))
+
+	names := func(acs []*AgentCapabilities) []string {
+		var ret []string
+		for _, ac := range acs {
+			ret = append(ret, ac.AgentName)
+		}
+		return ret
+	}
+	assert.Equal(t, []string{"flights", "hotels", "weather"}, names(r.Query(nil)))
+	assert.Equal(t, []string{"flights", "hotels"}, names(r.Query(&CapabilityQuery{Tags: []string{"travel"}})))
+	assert.Equal(t, []string{"flights"}, names(r.Query(&CapabilityQuery{Tags: []string{"travel", "booking"}})))
+	assert.Equal(t, []string{"hotels"}, names(r.Query(&CapabilityQuery{Keyword: "ROOM"})))
+	assert.Equal(t, []string{"weather"}, names(r.Query(&CapabilityQuery{AgentNames: []string{"weather", "unknown"}})))
+
+	r.Unregister("weather")
+	assert.False(t, r.Has("weather"))
+
+	assert.Equal(t, `- Agent name: flights
+  Agent description: books flights
+  Capabilities:
+    - book_flight: book a flight ticket
+      Tags: travel, booking
+      Input schema: {"type":"object"}
+`, r.Describe(&CapabilityQuery{AgentNames: []string{"flights"}}))
+
+	gen := r.GenModelInput(nil, &CapabilityQuery{AgentNames: []string{"hotels"}})
+	msgs, err := gen(ctx, "route the request", &AgentInput{Messages: []Message{schema.UserMessage("hi")}})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, schema.System, msgs[0].Role)
+	assert.Contains(t, msgs[0].Content, "route the request\n\nCapabilities of the available agents:\n- Agent name: hotels")
+
+	msgs, err = gen(ctx, "", &AgentInput{Messages: []Message{schema.UserMessage("hi")}})
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, schema.System, msgs[0].Role)
+}
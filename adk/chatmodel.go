@@ -48,6 +48,8 @@ type chatModelAgentRunOptions struct {
 
 	// resume
 	historyModifier func(context.Context, []Message) []Message
+	toolApprovals   map[string]*ToolApprovalResponse
+	toolResults     map[string]string
 }
 
 func WithChatModelOptions(opts []model.Option) AgentRunOption {
@@ -81,6 +83,28 @@ type ToolsConfig struct {
 	// If multiple listed tools are called simultaneously, only the first one triggers the return.
 	// The map keys are tool names indicate whether the tool should trigger immediate return.
 	ReturnDirectly map[string]bool
+
+	// RequireApproval specifies tools that need human approval before running.
+	// When such a tool is called, the agent interrupts with a ToolApprovalRequest, which can be got by GetToolApprovalRequests,
+	// and the decision is passed by WithToolApprovals when resuming. A rejected tool call isn't run,
+	// instead the model is told the call is rejected.
+	// Approval requires the Runner to be configured with a CheckPointStore, so that the run can be resumed, even after a long wait.
+	RequireApproval map[string]bool
+
+	// LongRunning specifies tools that may defer their results by returning NewToolPendingErr.
+	// When such a tool defers, the agent interrupts with a PendingToolCall, which can be got by GetPendingToolCalls,
+	// and the result is passed by Runner.CompleteToolCalls when the job is done.
+	// Like RequireApproval, it requires the Runner to be configured with a CheckPointStore.
+	LongRunning map[string]bool
+
+	// Policy is evaluated before each call to the tools, deciding whether the call is allowed, denied or requires approval,
+	// which governs what the agent may execute in one place. A call requiring approval follows the same flow as RequireApproval.
+	// Optional. Tools listed in RequireApproval always require approval, even if the policy allows the call.
+	Policy ToolPolicy
+
+	// DuplicateCalls enables suppressing the repeated calls to the same tool with the same arguments in a run of the agent,
+	// which models tend to make when confused. Optional.
+	DuplicateCalls *DuplicateToolCallConfig
 }
 
 // GenModelInput transforms agent instructions and input into a format suitable for the model.
@@ -133,9 +157,40 @@ type AgentMiddleware struct {
 	// AfterChatModel is called after each ChatModel invocation, allowing modification of the agent state.
 	AfterChatModel func(context.Context, *ChatModelAgentState) error
 
+	// RewriteModelInput is called before each ChatModel invocation, after BeforeChatModel,
+	// with the messages to be sent to the model, and returns the messages actually sent.
+	// Unlike BeforeChatModel, the rewritten messages only take effect on the current invocation and the agent state is kept,
+	// which makes it the place for context window management, e.g. summarizing old messages, dropping stale tool outputs
+	// or injecting retrieved memories. Rewriters of all middlewares are applied in order.
+	RewriteModelInput ModelInputRewriter
+
 	// WrapToolCall wraps tool calls with custom middleware logic.
 	// Each middleware contains Invokable and/or Streamable functions for tool calls.
 	WrapToolCall compose.ToolMiddleware
+
+	// Guardrails check the input and output of each ChatModel invocation, e.g. PII filters and policy checks.
+	// Guardrails of all middlewares are applied in order, a violation stops the agent with a refusal message.
+	Guardrails []Guardrail
+}
+
+// ModelInputRewriter rewrites the messages to be sent to the model.
+type ModelInputRewriter func(ctx context.Context, messages []Message) ([]Message, error)
+
+func rewriteModelInput(ctx context.Context, rewriters []ModelInputRewriter, messages []Message) ([]Message, error) {
+	if len(rewriters) == 0 {
+		return messages, nil
+	}
+	// copy the messages, so that rewriters can modify the slice in place without touching the agent state
+	ret := make([]Message, len(messages))
+	copy(ret, messages)
+	for _, r := range rewriters {
+		var err error
+		ret, err = r(ctx, ret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
 }
 
 type ChatModelAgentConfig struct {
@@ -168,11 +223,25 @@ type ChatModelAgentConfig struct {
 	// Optional. When set, stores output via AddSessionValue(ctx, outputKey, msg.Content).
 	OutputKey string
 
-	// MaxIterations defines the upper limit of ChatModel generation cycles.
-	// The agent will terminate with an error if this limit is exceeded.
+	// MaxIterations defines the upper limit of ChatModel generation cycles, i.e. the max steps of the agent.
+	// The agent will terminate with an error if this limit is exceeded, unless FinishAtMaxIterations is set.
 	// Optional. Defaults to 20.
 	MaxIterations int
 
+	// FinishAtMaxIterations makes the agent finish gracefully instead of failing with ErrExceedMaxIterations:
+	// when the last allowed ChatModel generation still calls tools, the tools are not called,
+	// the generation is taken as the final response, and a last event with
+	// AgentOutput.FinishReason{Status: FinishStatusIncomplete, Reason: "max_steps"} is emitted.
+	FinishAtMaxIterations bool
+
+	// ShouldTerminate is evaluated after each ChatModel generation, after the AfterChatModel middlewares,
+	// with the state whose last message is the generation.
+	// When it returns true, the agent stops with the generation as the final response without calling the tools in it,
+	// and emits a last event with AgentOutput.FinishReason{Status: FinishStatusCompleted, Reason: "terminated"}.
+	// It's useful to stop the loop on custom signals, e.g. a specific tool being called, or a confidence score in the output.
+	// Optional.
+	ShouldTerminate func(ctx context.Context, state *ChatModelAgentState) (bool, error)
+
 	// Middlewares configures agent middleware for extending functionality.
 	Middlewares []AgentMiddleware
 }
@@ -187,8 +256,10 @@ type ChatModelAgent struct {
 
 	genModelInput GenModelInput
 
-	outputKey     string
-	maxIterations int
+	outputKey             string
+	maxIterations         int
+	finishAtMaxIterations bool
+	shouldTerminate       func(ctx context.Context, state *ChatModelAgentState) (bool, error)
 
 	subAgents   []Agent
 	parentAgent Agent
@@ -198,6 +269,9 @@ type ChatModelAgent struct {
 	exit tool.BaseTool
 
 	beforeChatModels, afterChatModels []func(context.Context, *ChatModelAgentState) error
+	modelInputRewriters               []ModelInputRewriter
+
+	guardrails []Guardrail
 
 	// runner
 	once   sync.Once
@@ -225,6 +299,8 @@ func NewChatModelAgent(_ context.Context, config *ChatModelAgentConfig) (*ChatMo
 
 	beforeChatModels := make([]func(context.Context, *ChatModelAgentState) error, 0)
 	afterChatModels := make([]func(context.Context, *ChatModelAgentState) error, 0)
+	var modelInputRewriters []ModelInputRewriter
+	var guardrails []Guardrail
 	sb := &strings.Builder{}
 	sb.WriteString(config.Instruction)
 	tc := config.ToolsConfig
@@ -242,20 +318,28 @@ func NewChatModelAgent(_ context.Context, config *ChatModelAgentConfig) (*ChatMo
 		if m.AfterChatModel != nil {
 			afterChatModels = append(afterChatModels, m.AfterChatModel)
 		}
+		if m.RewriteModelInput != nil {
+			modelInputRewriters = append(modelInputRewriters, m.RewriteModelInput)
+		}
+		guardrails = append(guardrails, m.Guardrails...)
 	}
 
 	return &ChatModelAgent{
-		name:             config.Name,
-		description:      config.Description,
-		instruction:      sb.String(),
-		model:            config.Model,
-		toolsConfig:      tc,
-		genModelInput:    genInput,
-		exit:             config.Exit,
-		outputKey:        config.OutputKey,
-		maxIterations:    config.MaxIterations,
-		beforeChatModels: beforeChatModels,
-		afterChatModels:  afterChatModels,
+		name:                  config.Name,
+		description:           config.Description,
+		instruction:           sb.String(),
+		model:                 config.Model,
+		toolsConfig:           tc,
+		genModelInput:         genInput,
+		exit:                  config.Exit,
+		outputKey:             config.OutputKey,
+		maxIterations:         config.MaxIterations,
+		finishAtMaxIterations: config.FinishAtMaxIterations,
+		shouldTerminate:       config.ShouldTerminate,
+		beforeChatModels:      beforeChatModels,
+		afterChatModels:       afterChatModels,
+		guardrails:            guardrails,
+		modelInputRewriters:   modelInputRewriters,
 	}, nil
 }
 
@@ -445,7 +529,7 @@ func (h *cbHandler) onToolEndWithStreamOutput(ctx context.Context,
 
 	toolCallID := compose.GetToolCallID(ctx)
 	cvt := func(in *tool.CallbackOutput) (Message, error) {
-		return schema.ToolMessage(in.Response, toolCallID), nil
+		return schema.ToolMessage(in.Response, toolCallID, schema.WithToolName(runInfo.Name)), nil
 	}
 	out := schema.StreamReaderWithConvert(output, cvt)
 	event := EventFromMessage(nil, out, schema.Tool, runInfo.Name)
@@ -489,6 +573,11 @@ func init() {
 func (h *cbHandler) onGraphError(ctx context.Context,
 	_ *callbacks.RunInfo, err error) context.Context {
 
+	if event, ok := guardrailRefusalEvent(h.agentName, err); ok {
+		h.Send(event)
+		return ctx
+	}
+
 	info, ok := compose.ExtractInterruptInfo(err)
 	if !ok {
 		h.Send(&AgentEvent{Err: err})
@@ -566,6 +655,29 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 		toolsNodeConf := a.toolsConfig.ToolsNodeConfig
 		returnDirectly := copyMap(a.toolsConfig.ReturnDirectly)
 
+		tools, err := wrapToolsWithDuplicateCheck(ctx, toolsNodeConf.Tools, a.toolsConfig.DuplicateCalls)
+		if err != nil {
+			a.run = errFunc(err)
+			return
+		}
+		tools, err = wrapLongRunningTools(ctx, tools, a.toolsConfig.LongRunning)
+		if err != nil {
+			a.run = errFunc(err)
+			return
+		}
+		tools, err = wrapToolsWithApproval(ctx, tools, a.toolsConfig.RequireApproval)
+		if err != nil {
+			a.run = errFunc(err)
+			return
+		}
+		tools, err = wrapToolsWithPolicy(ctx, tools, a.toolsConfig.Policy, a.name)
+		if err != nil {
+			a.run = errFunc(err)
+			return
+		}
+		toolsNodeConf.Tools = tools
+		toolsNodeConf.ToolCallMiddlewares = append([]compose.ToolMiddleware{runBudgetToolMiddleware()}, toolsNodeConf.ToolCallMiddlewares...)
+
 		transferToAgents := a.subAgents
 		if a.parentAgent != nil && !a.disallowTransferToParent {
 			transferToAgents = append(transferToAgents, a.parentAgent)
@@ -593,9 +705,13 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 			a.run = func(ctx context.Context, input *AgentInput, generator *AsyncGenerator[*AgentEvent], store *mockStore, opts ...compose.Option) {
 				r, err := compose.NewChain[*AgentInput, Message]().
 					AppendLambda(compose.InvokableLambda(func(ctx context.Context, input *AgentInput) ([]Message, error) {
-						return a.genModelInput(ctx, instruction, input)
+						msgs, err := a.genModelInput(ctx, instruction, input)
+						if err != nil {
+							return nil, err
+						}
+						return rewriteModelInput(ctx, a.modelInputRewriters, msgs)
 					})).
-					AppendChatModel(a.model).
+					AppendChatModel(model.NewGuardedChatModel(a.model, a.guardrails...)).
 					Compile(ctx, compose.WithGraphName(a.name))
 				if err != nil {
 					generator.Send(&AgentEvent{Err: err})
@@ -631,6 +747,8 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 						event = EventFromMessage(msg, msgStream, schema.Assistant, "")
 						generator.Send(event)
 					}
+				} else if refusal, ok := guardrailRefusalEvent(a.name, err); ok {
+					generator.Send(refusal)
 				} else {
 					event = &AgentEvent{Err: err}
 					generator.Send(event)
@@ -644,13 +762,17 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 
 		// react
 		conf := &reactConfig{
-			model:               a.model,
-			toolsConfig:         &toolsNodeConf,
-			toolsReturnDirectly: returnDirectly,
-			agentName:           a.name,
-			maxIterations:       a.maxIterations,
-			beforeChatModel:     a.beforeChatModels,
-			afterChatModel:      a.afterChatModels,
+			model:                 a.model,
+			toolsConfig:           &toolsNodeConf,
+			toolsReturnDirectly:   returnDirectly,
+			agentName:             a.name,
+			maxIterations:         a.maxIterations,
+			finishAtMaxIterations: a.finishAtMaxIterations,
+			shouldTerminate:       a.shouldTerminate,
+			beforeChatModel:       a.beforeChatModels,
+			modelInputRewriters:   a.modelInputRewriters,
+			afterChatModel:        a.afterChatModels,
+			guardrails:            a.guardrails,
 		}
 
 		g, err := newReact(ctx, conf)
@@ -682,6 +804,7 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 			}
 
 			callOpt := genReactCallbacks(a.name, generator, input.EnableStreaming, store)
+			ctx, finish := withReactFinish(ctx)
 
 			var msg Message
 			var msgStream MessageStream
@@ -700,6 +823,9 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 				} else if msgStream != nil {
 					msgStream.Close()
 				}
+				if finish.reason != nil {
+					generator.Send(&AgentEvent{Output: &AgentOutput{FinishReason: finish.reason}})
+				}
 			}
 
 			generator.Close()
@@ -713,6 +839,9 @@ func (a *ChatModelAgent) buildRunFunc(ctx context.Context) runFunc {
 
 func (a *ChatModelAgent) Run(ctx context.Context, input *AgentInput, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 	run := a.buildRunFunc(ctx)
+	if a.toolsConfig.DuplicateCalls != nil {
+		ctx = withToolCallCache(ctx)
+	}
 
 	co := getComposeOptions(opts)
 	co = append(co, compose.WithCheckPointID(mockCheckPointID))
@@ -737,6 +866,9 @@ func (a *ChatModelAgent) Run(ctx context.Context, input *AgentInput, opts ...Age
 
 func (a *ChatModelAgent) Resume(ctx context.Context, info *ResumeInfo, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 	run := a.buildRunFunc(ctx)
+	if a.toolsConfig.DuplicateCalls != nil {
+		ctx = withToolCallCache(ctx)
+	}
 
 	co := getComposeOptions(opts)
 	co = append(co, compose.WithCheckPointID(mockCheckPointID))
@@ -772,6 +904,12 @@ func getComposeOptions(opts []AgentRunOption) []compose.Option {
 	for toolName, atos := range o.agentToolOptions {
 		to = append(to, withAgentToolOptions(toolName, atos))
 	}
+	if len(o.toolApprovals) > 0 {
+		to = append(to, withToolApprovalResponses(o.toolApprovals))
+	}
+	if len(o.toolResults) > 0 {
+		to = append(to, withToolResults(o.toolResults))
+	}
 	if len(to) > 0 {
 		co = append(co, compose.WithToolsNodeOption(compose.WithToolOption(to...)))
 	}
@@ -417,6 +417,144 @@ func TestParallelReturnDirectlyToolCall(t *testing.T) {
 	}
 }
 
+func TestChatModelAgentRewriteModelInput(t *testing.T) {
+	ctx := context.Background()
+
+	// injects a memory and drops the outputs of tools except the latest one
+	rewriter := func(_ context.Context, messages []Message) ([]Message, error) {
+		lastTool := -1
+		for i, m := range messages {
+			if m.Role == schema.Tool {
+				lastTool = i
+			}
+		}
+		for i, m := range messages {
+			if m.Role == schema.Tool && i != lastTool {
+				messages[i] = schema.ToolMessage("[dropped]", m.ToolCallID)
+			}
+		}
+		return append([]Message{schema.SystemMessage("memory")}, messages...), nil
+	}
+
+	for _, withTools := range []bool{false, true} {
+		var inputs [][]*schema.Message
+		m := &myModel{
+			validator: func(_ int, input []*schema.Message) bool {
+				inputs = append(inputs, input)
+				return true
+			},
+		}
+		conf := &ChatModelAgentConfig{
+			Name:        "agent",
+			Description: "agent",
+			Model:       m,
+			Middlewares: []AgentMiddleware{{RewriteModelInput: rewriter}},
+		}
+		if withTools {
+			m.messages = []*schema.Message{
+				schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"a"}`}}}),
+				schema.AssistantMessage("", []schema.ToolCall{{ID: "2", Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"b"}`}}}),
+				schema.AssistantMessage("done", nil),
+			}
+			conf.ToolsConfig.Tools = []tool.BaseTool{&fakeToolForTest{tarCount: 2}}
+		} else {
+			m.messages = []*schema.Message{schema.AssistantMessage("done", nil)}
+		}
+		a, err := NewChatModelAgent(ctx, conf)
+		assert.NoError(t, err)
+
+		iter := NewRunner(ctx, RunnerConfig{Agent: a}).Query(ctx, "hi")
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+		}
+
+		if !withTools {
+			assert.Len(t, inputs, 1)
+			assert.Equal(t, "memory", inputs[0][0].Content)
+			assert.Equal(t, schema.System, inputs[0][1].Role)
+			assert.Equal(t, "hi", inputs[0][2].Content)
+			continue
+		}
+		assert.Len(t, inputs, 3)
+		for _, input := range inputs {
+			// the rewritten messages aren't kept in the agent state
+			assert.Equal(t, "memory", input[0].Content)
+			assert.Equal(t, schema.System, input[1].Role)
+			assert.Equal(t, "hi", input[2].Content)
+		}
+		assert.Len(t, inputs[2], 7)
+		assert.Equal(t, "[dropped]", inputs[2][4].Content)
+		assert.Equal(t, `{"say": "hello b"}`, inputs[2][6].Content)
+	}
+}
+
+func TestChatModelAgentTermination(t *testing.T) {
+	ctx := context.Background()
+
+	toolCall := func(id, content string) *schema.Message {
+		return schema.AssistantMessage(content, []schema.ToolCall{{ID: id, Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"a"}`}}})
+	}
+
+	run := func(conf *ChatModelAgentConfig) (events []*AgentEvent) {
+		conf.Name = "agent"
+		conf.Description = "agent"
+		conf.ToolsConfig.Tools = []tool.BaseTool{&fakeToolForTest{tarCount: 10}}
+		a, err := NewChatModelAgent(ctx, conf)
+		assert.NoError(t, err)
+		iter := NewRunner(ctx, RunnerConfig{Agent: a}).Query(ctx, "hi")
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				return events
+			}
+			events = append(events, event)
+		}
+	}
+
+	t.Run("max steps", func(t *testing.T) {
+		events := run(&ChatModelAgentConfig{
+			Model:                 &myModel{messages: []*schema.Message{toolCall("1", ""), toolCall("2", "")}},
+			MaxIterations:         2,
+			FinishAtMaxIterations: true,
+		})
+		// model, tool, model, finish
+		assert.Len(t, events, 4)
+		for _, e := range events {
+			assert.NoError(t, e.Err)
+		}
+		assert.Equal(t, "2", events[2].Output.MessageOutput.Message.ToolCalls[0].ID)
+		assert.Equal(t, &FinishReason{Status: FinishStatusIncomplete, Reason: "max_steps"}, events[3].Output.FinishReason)
+	})
+
+	t.Run("max steps error", func(t *testing.T) {
+		events := run(&ChatModelAgentConfig{
+			Model:         &myModel{messages: []*schema.Message{toolCall("1", ""), toolCall("2", "")}},
+			MaxIterations: 1,
+		})
+		assert.ErrorIs(t, events[len(events)-1].Err, ErrExceedMaxIterations)
+	})
+
+	t.Run("should terminate", func(t *testing.T) {
+		var states []int
+		events := run(&ChatModelAgentConfig{
+			Model: &myModel{messages: []*schema.Message{toolCall("1", "thinking"), toolCall("2", "confident"), toolCall("3", "")}},
+			ShouldTerminate: func(ctx context.Context, state *ChatModelAgentState) (bool, error) {
+				states = append(states, len(state.Messages))
+				return state.Messages[len(state.Messages)-1].Content == "confident", nil
+			},
+		})
+		// model, tool, model, finish
+		assert.Len(t, events, 4)
+		assert.Equal(t, "confident", events[2].Output.MessageOutput.Message.Content)
+		assert.Equal(t, &FinishReason{Status: FinishStatusCompleted, Reason: "terminated"}, events[3].Output.FinishReason)
+		assert.Equal(t, []int{2, 4}, states)
+	})
+}
+
 type myTool struct {
 	name     string
 	desc     string
@@ -0,0 +1,300 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	icb "github.com/cloudwego/eino/internal/callbacks"
+	"github.com/cloudwego/eino/schema"
+)
+
+// CallbackExtraKeyCost is the key of the cost of a model call in model.CallbackOutput.Extra,
+// reported by the models wrapped by NewCostTrackingModel, the value is a *ModelCallCost.
+const CallbackExtraKeyCost = "_eino_adk_model_call_cost"
+
+// ModelCallCost is the cost of a model call.
+type ModelCallCost struct {
+	ModelName string
+	Usage     *model.TokenUsage
+	// Cost is the cost of the call, zero if the model has no price in the PriceTable.
+	Cost   float64
+	Priced bool
+	// RequestCost is the cost accumulated in the request so far, including this call.
+	RequestCost float64
+}
+
+// CostLimitExceededError is returned by the models wrapped by NewCostTrackingModel
+// when the cost of the request has reached CostTrackingConfig.MaxCost.
+type CostLimitExceededError struct {
+	Cost    float64
+	MaxCost float64
+}
+
+func (e *CostLimitExceededError) Error() string {
+	return fmt.Sprintf("cost limit exceeded: cost %f, max cost %f", e.Cost, e.MaxCost)
+}
+
+// CostTrackingConfig is the config of NewCostTrackingModel.
+type CostTrackingConfig struct {
+	// PriceTable provides the price of the model.
+	// Required.
+	PriceTable PriceTable
+	// ModelName is the name to look up in PriceTable, model.WithModel of a call overrides it.
+	ModelName string
+	// MaxCost is the hard ceiling of the cost of a request, i.e. a run of the Runner,
+	// accumulated by all the models wrapped by NewCostTrackingModel in the run, including the ones of nested agents.
+	// Once the ceiling is reached, further calls fail with *CostLimitExceededError without calling the model.
+	// Outside a Runner, each call is a request of its own, so a call is never refused.
+	// Optional. Zero means no limit.
+	MaxCost float64
+	// OnCost is called with the cost of each call, after the output stream is fully received in stream mode.
+	// Optional.
+	OnCost func(ctx context.Context, cost *ModelCallCost)
+}
+
+// NewCostTrackingModel wraps the model to convert the token usage of each call into cost by the PriceTable.
+// The cost is reported in the model callbacks, by the ModelCallCost in model.CallbackOutput.Extra with the key CallbackExtraKeyCost,
+// and counted in the RunUsage of the Runner with precedence over RunnerConfig.PriceTable.
+// e.g.
+//
+//	cm, err := adk.NewCostTrackingModel(chatModel, &adk.CostTrackingConfig{
+//		PriceTable: adk.StaticPriceTable{"gpt-4o": {Prompt: 2.5, CachedPrompt: 1.25, Completion: 10}},
+//		ModelName:  "gpt-4o",
+//		MaxCost:    0.5,
+//	})
+//	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{Model: cm, ...})
+func NewCostTrackingModel(m model.ToolCallingChatModel, config *CostTrackingConfig) (model.ToolCallingChatModel, error) {
+	if m == nil {
+		return nil, errors.New("model is required")
+	}
+	if config == nil || config.PriceTable == nil {
+		return nil, errors.New("price table is required")
+	}
+	return &costTrackingModel{inner: m, config: config}, nil
+}
+
+type costTrackingModel struct {
+	inner  model.ToolCallingChatModel
+	config *CostTrackingConfig
+}
+
+type costTrackingOptions struct {
+	model *string
+}
+
+func (c *costTrackingModel) GetType() string {
+	if typ, ok := components.GetType(c.inner); ok {
+		return typ
+	}
+	return "CostTrackingModel"
+}
+
+func (c *costTrackingModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+func (c *costTrackingModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	inner, err := c.inner.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &costTrackingModel{inner: inner, config: c.config}, nil
+}
+
+func (c *costTrackingModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (
+	output *schema.Message, err error) {
+
+	ctx, modelName, err := c.start(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err = c.inner.Generate(withoutCallbacks(ctx), input, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+
+	cbOutput := c.toCallbackOutput(output, modelName)
+	c.record(ctx, cbOutput)
+	callbacks.OnEnd(ctx, cbOutput)
+	return output, nil
+}
+
+func (c *costTrackingModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (
+	output *schema.StreamReader[*schema.Message], err error) {
+
+	ctx, modelName, err := c.start(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	sr, err := c.inner.Stream(withoutCallbacks(ctx), input, opts...)
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+
+	outSR, outSW := schema.Pipe[*schema.Message](1)
+	cbSR, cbSW := schema.Pipe[*model.CallbackOutput](1)
+	_, cbSR = callbacks.OnEndWithStreamOutput(ctx, cbSR)
+	cbSR.Close()
+
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				err := fmt.Errorf("panic in cost tracking model: %v", panicErr)
+				outSW.Send(nil, err)
+				cbSW.Send(nil, err)
+			}
+			sr.Close()
+			outSW.Close()
+			cbSW.Close()
+		}()
+
+		var chunks []*schema.Message
+		// the last chunk is held back, so that the cost can be reported with it when the stream ends
+		var held *schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				outSW.Send(nil, err)
+				cbSW.Send(nil, err)
+				return
+			}
+			chunks = append(chunks, chunk)
+			if held != nil {
+				cbSW.Send(&model.CallbackOutput{Message: held}, nil)
+			}
+			held = chunk
+			outSW.Send(chunk, nil)
+		}
+
+		msg, err := schema.ConcatMessages(chunks)
+		if err != nil {
+			cbSW.Send(nil, err)
+			return
+		}
+		cbOutput := c.toCallbackOutput(msg, modelName)
+		c.record(ctx, cbOutput)
+		cbOutput.Message = held
+		cbSW.Send(cbOutput, nil)
+	}()
+
+	return outSR, nil
+}
+
+// start checks the cost limit and triggers the start callback.
+func (c *costTrackingModel) start(ctx context.Context, input []*schema.Message, opts []model.Option) (context.Context, string, error) {
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+
+	modelName := c.config.ModelName
+	if o := model.GetCommonOptions(nil, opts...); o.Model != nil {
+		modelName = *o.Model
+	}
+
+	if c.config.MaxCost > 0 {
+		if cost := getSessionModelCost(ctx); cost >= c.config.MaxCost {
+			err := &CostLimitExceededError{Cost: cost, MaxCost: c.config.MaxCost}
+			callbacks.OnError(ctx, err)
+			return nil, "", err
+		}
+	}
+
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{
+		Messages: input,
+		Config:   &model.Config{Model: modelName},
+	})
+	return ctx, modelName, nil
+}
+
+func (c *costTrackingModel) toCallbackOutput(msg *schema.Message, modelName string) *model.CallbackOutput {
+	cost := &ModelCallCost{
+		ModelName: modelName,
+		Usage:     tokenUsageOf(&model.CallbackOutput{Message: msg}),
+	}
+	return &model.CallbackOutput{
+		Message:    msg,
+		Config:     &model.Config{Model: modelName},
+		TokenUsage: cost.Usage,
+		Extra:      map[string]any{CallbackExtraKeyCost: cost},
+	}
+}
+
+// record prices the usage of the output, and accumulates the cost into the session.
+func (c *costTrackingModel) record(ctx context.Context, output *model.CallbackOutput) {
+	cost := output.Extra[CallbackExtraKeyCost].(*ModelCallCost)
+	if cost.Usage != nil {
+		if price, ok := c.config.PriceTable.GetPrice(ctx, cost.ModelName); ok {
+			cost.Cost = price.cost(cost.Usage)
+			cost.Priced = true
+		}
+	}
+	cost.RequestCost = addSessionModelCost(ctx, cost.Cost)
+
+	if c.config.OnCost != nil {
+		c.config.OnCost(ctx, cost)
+	}
+}
+
+// reportedCost returns the cost reported by the models wrapped by NewCostTrackingModel.
+func reportedCost(output *model.CallbackOutput) (float64, bool) {
+	if output == nil {
+		return 0, false
+	}
+	cost, ok := output.Extra[CallbackExtraKeyCost].(*ModelCallCost)
+	if !ok || !cost.Priced {
+		return 0, false
+	}
+	return cost.Cost, true
+}
+
+func getSessionModelCost(ctx context.Context) float64 {
+	session := getSession(ctx)
+	if session == nil {
+		return 0
+	}
+	session.mtx.Lock()
+	defer session.mtx.Unlock()
+	return session.modelCost
+}
+
+func addSessionModelCost(ctx context.Context, cost float64) float64 {
+	session := getSession(ctx)
+	if session == nil {
+		return cost
+	}
+	session.mtx.Lock()
+	defer session.mtx.Unlock()
+	session.modelCost += cost
+	return session.modelCost
+}
+
+// withoutCallbacks hides the callback handlers from the inner model, whose calls are reported by the wrapper.
+func withoutCallbacks(ctx context.Context) context.Context {
+	return context.WithValue(ctx, icb.CtxManagerKey{}, nil)
+}
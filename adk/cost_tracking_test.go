@@ -0,0 +1,199 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+	ub "github.com/cloudwego/eino/utils/callbacks"
+)
+
+// pricedModel returns a tool call until the calls reach toolCalls, reporting the usage in the response meta.
+type pricedModel struct {
+	usage     *schema.TokenUsage
+	toolCalls int
+	calls     int
+}
+
+func (p *pricedModel) Generate(_ context.Context, _ []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	p.calls++
+	msg := schema.AssistantMessage("done", nil)
+	if p.calls <= p.toolCalls {
+		msg = schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "tool1", Arguments: "{}"}}})
+	}
+	msg.ResponseMeta = &schema.ResponseMeta{Usage: p.usage}
+	return msg, nil
+}
+
+func (p *pricedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := p.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	meta := msg.ResponseMeta
+	msg.ResponseMeta = nil
+	return schema.StreamReaderFromArray([]*schema.Message{msg, {Role: schema.Assistant, ResponseMeta: meta}}), nil
+}
+
+func (p *pricedModel) WithTools(_ []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return p, nil
+}
+
+func TestCostTrackingModel(t *testing.T) {
+	ctx := context.Background()
+	usage := &schema.TokenUsage{
+		PromptTokens:           1000,
+		PromptTokenDetails:     schema.PromptTokenDetails{CachedTokens: 200},
+		CompletionTokens:       500,
+		CompletionTokenDetails: schema.CompletionTokenDetails{ReasoningTokens: 100},
+		TotalTokens:            1500,
+	}
+	prices := StaticPriceTable{
+		"m": {Prompt: 1, CachedPrompt: 0.5, Completion: 2, Reasoning: 4},
+	}
+	expectedCost := (800*1 + 200*0.5 + 400*2 + 100*4) / 1e6
+
+	t.Run("callbacks", func(t *testing.T) {
+		for _, streaming := range []bool{false, true} {
+			var costs []*ModelCallCost
+			cm, err := NewCostTrackingModel(&pricedModel{usage: usage}, &CostTrackingConfig{
+				PriceTable: prices,
+				ModelName:  "unknown",
+				OnCost: func(_ context.Context, cost *ModelCallCost) {
+					costs = append(costs, cost)
+				},
+			})
+			assert.NoError(t, err)
+
+			var reported *model.CallbackOutput
+			done := make(chan struct{}, 1)
+			handler := ub.NewHandlerHelper().ChatModel(&ub.ModelCallbackHandler{
+				OnEnd: func(ctx context.Context, _ *callbacks.RunInfo, output *model.CallbackOutput) context.Context {
+					reported = output
+					return ctx
+				},
+				OnEndWithStreamOutput: func(ctx context.Context, _ *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+					go func() {
+						defer output.Close()
+						for {
+							chunk, err := output.Recv()
+							if err != nil {
+								assert.Equal(t, io.EOF, err)
+								done <- struct{}{}
+								return
+							}
+							reported = chunk
+						}
+					}()
+					return ctx
+				},
+			}).Handler()
+
+			r, err := compose.NewChain[[]*schema.Message, *schema.Message]().AppendChatModel(cm).Compile(ctx)
+			assert.NoError(t, err)
+			var msg *schema.Message
+			if streaming {
+				sr, err := r.Stream(ctx, []*schema.Message{schema.UserMessage("hi")},
+					compose.WithCallbacks(handler), compose.WithChatModelOption(model.WithModel("m")))
+				assert.NoError(t, err)
+				msg, err = schema.ConcatMessageStream(sr)
+				assert.NoError(t, err)
+			} else {
+				msg, err = r.Invoke(ctx, []*schema.Message{schema.UserMessage("hi")},
+					compose.WithCallbacks(handler), compose.WithChatModelOption(model.WithModel("m")))
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, "done", msg.Content)
+
+			assert.Len(t, costs, 1)
+			assert.Equal(t, "m", costs[0].ModelName)
+			assert.True(t, costs[0].Priced)
+			assert.InDelta(t, expectedCost, costs[0].Cost, 1e-12)
+			assert.Equal(t, costs[0].Cost, costs[0].RequestCost)
+			assert.Equal(t, 100, costs[0].Usage.CompletionTokenDetails.ReasoningTokens)
+
+			if streaming {
+				// the stream callbacks are received asynchronously
+				<-done
+			}
+			assert.Equal(t, costs[0], reported.Extra[CallbackExtraKeyCost])
+			assert.Equal(t, "m", reported.Config.Model)
+		}
+	})
+
+	t.Run("runner", func(t *testing.T) {
+		for _, streaming := range []bool{false, true} {
+			cm, err := NewCostTrackingModel(&pricedModel{usage: usage, toolCalls: 2}, &CostTrackingConfig{
+				PriceTable: prices,
+				ModelName:  "m",
+				MaxCost:    expectedCost * 1.5,
+			})
+			assert.NoError(t, err)
+			a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+				Name:        "a",
+				Description: "a",
+				Model:       cm,
+				ToolsConfig: ToolsConfig{
+					ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{&myTool{name: "tool1", desc: "tool1"}}},
+				},
+			})
+			assert.NoError(t, err)
+
+			var final *RunUsage
+			runner := NewRunner(ctx, RunnerConfig{
+				Agent:           a,
+				EnableStreaming: streaming,
+				OnRunUsage: func(_ context.Context, usage *RunUsage) {
+					final = usage
+				},
+			})
+			iter := runner.Query(ctx, "hi")
+			var lastErr error
+			for {
+				event, ok := iter.Next()
+				if !ok {
+					break
+				}
+				if event.Err != nil {
+					lastErr = event.Err
+				}
+				if event.Output != nil && event.Output.MessageOutput != nil && event.Output.MessageOutput.IsStreaming {
+					_, _ = schema.ConcatMessageStream(event.Output.MessageOutput.MessageStream)
+				}
+			}
+
+			// the third call is refused, the cost of the first two has exceeded the limit
+			var limitErr *CostLimitExceededError
+			assert.True(t, errors.As(lastErr, &limitErr))
+			assert.InDelta(t, expectedCost*2, limitErr.Cost, 1e-12)
+			assert.Equal(t, 2, final.ModelCalls)
+			assert.Equal(t, 200, final.ReasoningTokens)
+			assert.InDelta(t, expectedCost*2, final.Cost, 1e-12)
+			assert.Equal(t, 0, final.UnpricedModelCalls)
+		}
+	})
+}
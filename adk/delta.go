@@ -0,0 +1,90 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"github.com/cloudwego/eino/schema"
+)
+
+// AgentDelta is an incremental update of a streaming message produced by an agent,
+// split by kind so that UIs can render the thinking and the tool call progress live.
+type AgentDelta struct {
+	// AgentName and RunPath attribute the delta to the agent which produced it.
+	AgentName string
+	RunPath   []RunStep
+
+	Role schema.RoleType
+
+	// ReasoningContent is the delta of the thinking process.
+	ReasoningContent string
+	// Content is the delta of the answer.
+	Content string
+	// ToolCalls are the deltas of the tool calls, the arguments are partial JSON.
+	ToolCalls []ToolCallDelta
+}
+
+// ToolCallDelta is the incremental update of a tool call.
+type ToolCallDelta struct {
+	// Index identifies the tool call that the delta belongs to when there are multiple tool calls.
+	Index *int
+	// ID and Name are usually only present in the first delta of the tool call.
+	ID   string
+	Name string
+	// ArgumentsDelta is the partial arguments in JSON.
+	ArgumentsDelta string
+}
+
+// Deltas converts the MessageStream of the event to a stream of AgentDelta.
+// Chunks without any delta are skipped.
+// It returns nil if the event doesn't carry a streaming message.
+// NOTE: the returned stream consumes the MessageStream of the event, so the MessageStream should not be received any more,
+// make a copy of the event first if both are needed.
+func (e *AgentEvent) Deltas() *schema.StreamReader[*AgentDelta] {
+	if e.Output == nil || e.Output.MessageOutput == nil || !e.Output.MessageOutput.IsStreaming {
+		return nil
+	}
+
+	mv := e.Output.MessageOutput
+	agentName := e.AgentName
+	runPath := e.RunPath
+	return schema.StreamReaderWithConvert(mv.MessageStream, func(chunk Message) (*AgentDelta, error) {
+		if chunk == nil {
+			return nil, schema.ErrNoValue
+		}
+
+		delta := &AgentDelta{
+			AgentName:        agentName,
+			RunPath:          runPath,
+			Role:             mv.Role,
+			ReasoningContent: chunk.ReasoningContent,
+			Content:          chunk.Content,
+		}
+		for _, tc := range chunk.ToolCalls {
+			delta.ToolCalls = append(delta.ToolCalls, ToolCallDelta{
+				Index:          tc.Index,
+				ID:             tc.ID,
+				Name:           tc.Function.Name,
+				ArgumentsDelta: tc.Function.Arguments,
+			})
+		}
+
+		if delta.ReasoningContent == "" && delta.Content == "" && len(delta.ToolCalls) == 0 {
+			return nil, schema.ErrNoValue
+		}
+		return delta, nil
+	})
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestAgentEventDeltas(t *testing.T) {
+	assert.Nil(t, EventFromMessage(schema.AssistantMessage("hi", nil), nil, schema.Assistant, "").Deltas())
+
+	idx := 0
+	event := EventFromMessage(nil, schema.StreamReaderFromArray([]Message{
+		{Role: schema.Assistant, ReasoningContent: "let me "},
+		{Role: schema.Assistant, ReasoningContent: "think"},
+		{Role: schema.Assistant},
+		{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{Index: &idx, ID: "1", Function: schema.FunctionCall{Name: "search", Arguments: `{"q":`}}}},
+		{Role: schema.Assistant, ToolCalls: []schema.ToolCall{{Index: &idx, Function: schema.FunctionCall{Arguments: `"eino"}`}}}},
+		{Role: schema.Assistant, Content: "done"},
+	}), schema.Assistant, "")
+	event.AgentName = "agent"
+	event.RunPath = []RunStep{{agentName: "agent"}}
+
+	sr := event.Deltas()
+	var deltas []*AgentDelta
+	for {
+		d, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		deltas = append(deltas, d)
+	}
+
+	assert.Len(t, deltas, 5)
+	for _, d := range deltas {
+		assert.Equal(t, "agent", d.AgentName)
+		assert.Equal(t, event.RunPath, d.RunPath)
+		assert.Equal(t, schema.Assistant, d.Role)
+	}
+	assert.Equal(t, "let me ", deltas[0].ReasoningContent)
+	assert.Equal(t, "think", deltas[1].ReasoningContent)
+	assert.Equal(t, []ToolCallDelta{{Index: &idx, ID: "1", Name: "search", ArgumentsDelta: `{"q":`}}, deltas[2].ToolCalls)
+	assert.Equal(t, `"eino"}`, deltas[3].ToolCalls[0].ArgumentsDelta)
+	assert.Equal(t, "done", deltas[4].Content)
+}
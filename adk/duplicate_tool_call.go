@@ -0,0 +1,167 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// DuplicateToolCallPolicy decides how a tool call is handled when the same tool has been called with the same arguments in the run.
+type DuplicateToolCallPolicy string
+
+const (
+	// DuplicateToolCallReuseResult returns the cached result of the previous call without running the tool again.
+	DuplicateToolCallReuseResult DuplicateToolCallPolicy = "reuse_result"
+	// DuplicateToolCallNudge doesn't run the tool again, instead tells the model that it already has the result.
+	DuplicateToolCallNudge DuplicateToolCallPolicy = "nudge"
+)
+
+// DuplicateToolCallConfig configures the suppression of duplicate tool calls in a run of the ChatModelAgent.
+// Two calls are duplicates if they have the same tool name and the same arguments,
+// the arguments are compared after normalizing the JSON, so the order of keys and the whitespaces don't matter.
+// Only the calls which succeeded are remembered.
+type DuplicateToolCallConfig struct {
+	// Policy decides how a duplicate call is handled. Optional. Defaults to DuplicateToolCallReuseResult.
+	Policy DuplicateToolCallPolicy
+	// Tools specifies the tools to check. Optional. Defaults to all the tools,
+	// tools with side effects that are expected to be called repeatedly should be excluded.
+	Tools map[string]bool
+	// Nudge generates the result returned to the model for a duplicate call when Policy is DuplicateToolCallNudge.
+	// Optional. Defaults to a message telling the model to use the previous result.
+	Nudge func(ctx context.Context, toolName, argumentsInJSON string) string
+}
+
+func defaultDuplicateToolCallNudge(_ context.Context, toolName, _ string) string {
+	return fmt.Sprintf("You have already called tool '%s' with the same arguments in this conversation. "+
+		"Do not call it again, use the result of the previous call instead.", toolName)
+}
+
+// toolCallCache keeps the results of the tool calls in a run of the ChatModelAgent.
+type toolCallCache struct {
+	mu      sync.Mutex
+	results map[string]string
+}
+
+type toolCallCacheKey struct{}
+
+func withToolCallCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, toolCallCacheKey{}, &toolCallCache{results: make(map[string]string)})
+}
+
+func getToolCallCache(ctx context.Context) *toolCallCache {
+	c, _ := ctx.Value(toolCallCacheKey{}).(*toolCallCache)
+	return c
+}
+
+func (c *toolCallCache) get(key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	r, ok := c.results[key]
+	return r, ok
+}
+
+func (c *toolCallCache) set(key, result string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[key] = result
+}
+
+func toolCallCacheKeyOf(name, argumentsInJSON string) string {
+	return name + "\x00" + normalizeToolArguments(argumentsInJSON)
+}
+
+// normalizeToolArguments re-marshals the JSON arguments, which sorts the keys of objects and drops the whitespaces.
+// Arguments which aren't valid JSON are only trimmed.
+func normalizeToolArguments(argumentsInJSON string) string {
+	var v any
+	if err := json.Unmarshal([]byte(argumentsInJSON), &v); err != nil {
+		return strings.TrimSpace(argumentsInJSON)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return strings.TrimSpace(argumentsInJSON)
+	}
+	return string(b)
+}
+
+func wrapToolsWithDuplicateCheck(ctx context.Context, tools []tool.BaseTool, conf *DuplicateToolCallConfig) ([]tool.BaseTool, error) {
+	if conf == nil {
+		return tools, nil
+	}
+
+	names := conf.Tools
+	if len(names) == 0 {
+		names = make(map[string]bool, len(tools))
+		for _, t := range tools {
+			info, err := t.Info(ctx)
+			if err != nil {
+				return nil, err
+			}
+			names[info.Name] = true
+		}
+	}
+
+	nudge := conf.Nudge
+	if nudge == nil {
+		nudge = defaultDuplicateToolCallNudge
+	}
+
+	return interceptTools(ctx, tools, names, &toolInterceptor{
+		before: func(ctx context.Context, name, argumentsInJSON string, _ []tool.Option) (string, bool, error) {
+			cache := getToolCallCache(ctx)
+			if cache == nil {
+				return "", false, nil
+			}
+			result, ok := cache.get(toolCallCacheKeyOf(name, argumentsInJSON))
+			if !ok {
+				return "", false, nil
+			}
+			if conf.Policy == DuplicateToolCallNudge {
+				return nudge(ctx, name, argumentsInJSON), true, nil
+			}
+			return result, true, nil
+		},
+		after: func(ctx context.Context, name, argumentsInJSON string, result *schema.StreamReader[string]) {
+			cache := getToolCallCache(ctx)
+			if cache == nil {
+				result.Close()
+				return
+			}
+			var sb strings.Builder
+			for {
+				chunk, err := result.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					result.Close()
+					return
+				}
+				sb.WriteString(chunk)
+			}
+			cache.set(toolCallCacheKeyOf(name, argumentsInJSON), sb.String())
+		},
+	})
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestNormalizeToolArguments(t *testing.T) {
+	assert.Equal(t, `{"a":1,"b":{"c":"d","e":[1,2]}}`, normalizeToolArguments(`{ "b": {"e": [1, 2], "c": "d"}, "a": 1 }`))
+	assert.Equal(t, "not json", normalizeToolArguments(" not json\n"))
+}
+
+func TestDuplicateToolCalls(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tc := range []struct {
+		name     string
+		conf     *DuplicateToolCallConfig
+		expected string
+	}{
+		{name: "disabled", expected: `{"say": "bye"}`},
+		{name: "reuse_result", conf: &DuplicateToolCallConfig{}, expected: `{"say": "hello a"}`},
+		{
+			name:     "nudge",
+			conf:     &DuplicateToolCallConfig{Policy: DuplicateToolCallNudge},
+			expected: defaultDuplicateToolCallNudge(ctx, "test_tool", ""),
+		},
+		{name: "other_tools", conf: &DuplicateToolCallConfig{Tools: map[string]bool{"other": true}}, expected: `{"say": "bye"}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			m := &myModel{messages: []*schema.Message{
+				schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"a"}`}}}),
+				schema.AssistantMessage("", []schema.ToolCall{{ID: "2", Function: schema.FunctionCall{Name: "test_tool", Arguments: `{ "name": "a" }`}}}),
+				schema.AssistantMessage("done", nil),
+			}}
+			a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+				Name:        "agent",
+				Description: "agent",
+				Model:       m,
+				ToolsConfig: ToolsConfig{
+					ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolForTest{tarCount: 1}}},
+					DuplicateCalls:  tc.conf,
+				},
+			})
+			assert.NoError(t, err)
+
+			iter := NewRunner(ctx, RunnerConfig{Agent: a}).Query(ctx, "hi")
+			var toolResults []string
+			for {
+				event, ok := iter.Next()
+				if !ok {
+					break
+				}
+				assert.NoError(t, event.Err)
+				if event.Output != nil && event.Output.MessageOutput.Role == schema.Tool {
+					toolResults = append(toolResults, event.Output.MessageOutput.Message.Content)
+				}
+			}
+			assert.Equal(t, []string{`{"say": "hello a"}`, tc.expected}, toolResults)
+		})
+	}
+}
@@ -42,8 +42,11 @@ type flowAgent struct {
 	subAgents   []*flowAgent
 	parentAgent *flowAgent
 
+	description              string
 	disallowTransferToParent bool
 	historyRewriter          HistoryRewriter
+	transferInputBuilder     TransferInputBuilder
+	runMiddlewares           []AgentRunMiddleware
 
 	checkPointStore compose.CheckPointStore
 }
@@ -53,8 +56,11 @@ func (a *flowAgent) deepCopy() *flowAgent {
 		Agent:                    a.Agent,
 		subAgents:                make([]*flowAgent, 0, len(a.subAgents)),
 		parentAgent:              a.parentAgent,
+		description:              a.description,
 		disallowTransferToParent: a.disallowTransferToParent,
 		historyRewriter:          a.historyRewriter,
+		transferInputBuilder:     a.transferInputBuilder,
+		runMiddlewares:           a.runMiddlewares,
 		checkPointStore:          a.checkPointStore,
 	}
 
@@ -76,6 +82,14 @@ func WithDisallowTransferToParent() AgentOption {
 	}
 }
 
+// WithDescription overrides the description of the agent, e.g. to describe a sub-agent for the routing of its parent,
+// while the agent keeps its own behaviors as a sub-agent, such as transferring back to the parent.
+func WithDescription(desc string) AgentOption {
+	return func(fa *flowAgent) {
+		fa.description = desc
+	}
+}
+
 func WithHistoryRewriter(h HistoryRewriter) AgentOption {
 	return func(fa *flowAgent) {
 		fa.historyRewriter = h
@@ -101,6 +115,13 @@ func toFlowAgent(ctx context.Context, agent Agent, opts ...AgentOption) *flowAge
 	return fa
 }
 
+func (a *flowAgent) Description(ctx context.Context) string {
+	if a.description != "" {
+		return a.description
+	}
+	return a.Agent.Description(ctx)
+}
+
 func AgentWithOptions(ctx context.Context, agent Agent, opts ...AgentOption) Agent {
 	return toFlowAgent(ctx, agent, opts...)
 }
@@ -217,7 +238,8 @@ func (ai *AgentInput) deepCopy() *AgentInput {
 	return copied
 }
 
-func (a *flowAgent) genAgentInput(ctx context.Context, runCtx *runContext, skipTransferMessages bool) (*AgentInput, error) {
+func (a *flowAgent) genAgentInput(ctx context.Context, runCtx *runContext, skipTransferMessages bool,
+	ti *transferInput) (*AgentInput, error) {
 	input := runCtx.RootInput.deepCopy()
 	runPath := runCtx.RunPath
 
@@ -267,6 +289,17 @@ func (a *flowAgent) genAgentInput(ctx context.Context, runCtx *runContext, skipT
 	if err != nil {
 		return nil, err
 	}
+	if ti != nil {
+		messages, err = ti.builder(ctx, &TransferInfo{
+			FromAgent: ti.fromAgent,
+			ToAgent:   a.Name(ctx),
+			History:   historyEntries,
+			Messages:  messages,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to build transfer input: %w", err)
+		}
+	}
 	input.Messages = messages
 
 	return input, nil
@@ -295,13 +328,18 @@ func buildDefaultHistoryRewriter(agentName string) HistoryRewriter {
 }
 
 func (a *flowAgent) Run(ctx context.Context, input *AgentInput, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+	return chainAgentRunMiddlewares(a.runMiddlewares, a.runFlow)(ctx, input, opts...)
+}
+
+func (a *flowAgent) runFlow(ctx context.Context, input *AgentInput, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 	agentName := a.Name(ctx)
 
 	ctx, runCtx := initRunCtx(ctx, agentName, input)
+	ctx, ti := popTransferInput(ctx)
 
 	o := getCommonOptions(nil, opts...)
 
-	input, err := a.genAgentInput(ctx, runCtx, o.skipTransferMessages)
+	input, err := a.genAgentInput(ctx, runCtx, o.skipTransferMessages, ti)
 	if err != nil {
 		return genErrorIter(err)
 	}
@@ -320,6 +358,13 @@ func (a *flowAgent) Run(ctx context.Context, input *AgentInput, opts ...AgentRun
 }
 
 func (a *flowAgent) Resume(ctx context.Context, info *ResumeInfo, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+	resume := func(ctx context.Context, _ *AgentInput, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+		return a.resumeFlow(ctx, info, opts...)
+	}
+	return chainAgentRunMiddlewares(a.runMiddlewares, resume)(ctx, nil, opts...)
+}
+
+func (a *flowAgent) resumeFlow(ctx context.Context, info *ResumeInfo, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 	runCtx := getRunCtx(ctx)
 	if runCtx == nil {
 		return genErrorIter(fmt.Errorf("failed to resume agent: run context is empty"))
@@ -427,7 +472,11 @@ func (a *flowAgent) run(
 			return
 		}
 
-		subAIter := agentToRun.Run(ctx, nil /*subagents get input from runCtx*/, opts...)
+		subCtx := ctx
+		if a.transferInputBuilder != nil {
+			subCtx = withTransferInput(ctx, a.Name(ctx), a.transferInputBuilder)
+		}
+		subAIter := agentToRun.Run(subCtx, nil /*subagents get input from runCtx*/, opts...)
 		for {
 			subEvent, ok_ := subAIter.Next()
 			if !ok_ {
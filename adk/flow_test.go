@@ -142,3 +142,73 @@ func TestTransferToAgent(t *testing.T) {
 	_, ok = iterator.Next()
 	assert.False(t, ok)
 }
+
+func TestTransferInputBuilder(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	parentModel := mockModel.NewMockToolCallingChatModel(ctrl)
+	childModel := mockModel.NewMockToolCallingChatModel(ctrl)
+	parentModel.EXPECT().WithTools(gomock.Any()).Return(parentModel, nil).AnyTimes()
+	childModel.EXPECT().WithTools(gomock.Any()).Return(childModel, nil).AnyTimes()
+
+	parentModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("", []schema.ToolCall{{
+			ID:       "tool-call-1",
+			Function: schema.FunctionCall{Name: TransferToAgentToolName, Arguments: `{"agent_name": "ChildAgent"}`},
+		}}), nil).Times(1)
+	childModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, input []*schema.Message, _ ...any) (*schema.Message, error) {
+			// system instruction, the user input and the handoff message, without the transfer messages
+			assert.Len(t, input, 3)
+			assert.Equal(t, "book a flight", input[1].Content)
+			assert.Equal(t, "ParentAgent hands over: focus on the booking", input[2].Content)
+			return schema.AssistantMessage("booked", nil), nil
+		}).Times(1)
+
+	parentAgent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "ParentAgent",
+		Description: "parent",
+		Instruction: "You are a parent agent.",
+		Model:       parentModel,
+	})
+	assert.NoError(t, err)
+	childAgent, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "ChildAgent",
+		Description: "child",
+		Instruction: "You are a child agent.",
+		Model:       childModel,
+	})
+	assert.NoError(t, err)
+
+	var info *TransferInfo
+	builder := func(ctx context.Context, ti *TransferInfo) ([]Message, error) {
+		info = ti
+		var messages []Message
+		for _, entry := range ti.History {
+			if entry.IsUserInput {
+				messages = append(messages, entry.Message)
+			}
+		}
+		return append(messages, schema.UserMessage(ti.FromAgent+" hands over: focus on the booking")), nil
+	}
+	a, err := SetSubAgents(ctx, AgentWithOptions(ctx, parentAgent, WithTransferInputBuilder(builder)), []Agent{childAgent})
+	assert.NoError(t, err)
+
+	iter := a.Run(ctx, &AgentInput{Messages: []Message{schema.UserMessage("book a flight")}})
+	var contents []string
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		contents = append(contents, event.Output.MessageOutput.Message.Content)
+	}
+	assert.Equal(t, []string{"", "successfully transferred to agent [ChildAgent]", "booked"}, contents)
+
+	assert.Equal(t, "ParentAgent", info.FromAgent)
+	assert.Equal(t, "ChildAgent", info.ToAgent)
+	assert.Len(t, info.History, 3)
+	assert.Len(t, info.Messages, 3)
+}
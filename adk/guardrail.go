@@ -0,0 +1,46 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Guardrail checks the messages sent to and received from the chat model of a ChatModelAgent,
+// configured by AgentMiddleware.Guardrails. See model.Guardrail for details.
+type Guardrail = model.Guardrail
+
+// GuardrailViolation is the result of a guardrail blocking a message.
+// When a guardrail is violated, the ChatModelAgent stops and emits the refusal message of the violation,
+// whose Output.FinishReason is FinishStatusIncomplete with the violation as the reason.
+type GuardrailViolation = model.GuardrailViolation
+
+func guardrailRefusalEvent(agentName string, err error) (*AgentEvent, bool) {
+	v, ok := model.AsGuardrailViolation(err)
+	if !ok {
+		return nil, false
+	}
+
+	event := EventFromMessage(v.RefusalMessage(), nil, schema.Assistant, "")
+	event.AgentName = agentName
+	event.Output.FinishReason = &FinishReason{
+		Status: FinishStatusIncomplete,
+		Reason: v.Error(),
+	}
+	return event, true
+}
@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestChatModelAgentGuardrails(t *testing.T) {
+	ctx := context.Background()
+
+	maskPhone := Guardrail{
+		Name: "mask_phone",
+		CheckInput: func(ctx context.Context, input []*schema.Message) ([]*schema.Message, *GuardrailViolation, error) {
+			out := make([]*schema.Message, len(input))
+			for i, m := range input {
+				cp := *m
+				cp.Content = strings.ReplaceAll(cp.Content, "13800000000", "***")
+				out[i] = &cp
+			}
+			return out, nil, nil
+		},
+	}
+	noSecret := Guardrail{
+		Name: "no_secret",
+		CheckOutput: func(ctx context.Context, output *schema.Message) (*schema.Message, *GuardrailViolation, error) {
+			if strings.Contains(output.Content, "secret") {
+				return nil, &GuardrailViolation{Reason: "secret leaked", Refusal: "I can't share that."}, nil
+			}
+			return output, nil, nil
+		},
+	}
+
+	t.Run("modify input", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockToolCallingChatModel(ctrl)
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, input []*schema.Message, _ ...any) (*schema.Message, error) {
+				assert.Equal(t, "call me at ***", input[len(input)-1].Content)
+				return schema.AssistantMessage("ok", nil), nil
+			}).Times(1)
+
+		a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+			Name:        "agent",
+			Description: "agent",
+			Model:       cm,
+			Middlewares: []AgentMiddleware{{Guardrails: []Guardrail{maskPhone, noSecret}}},
+		})
+		assert.NoError(t, err)
+
+		iter := a.Run(ctx, &AgentInput{Messages: []Message{schema.UserMessage("call me at 13800000000")}})
+		event, ok := iter.Next()
+		assert.True(t, ok)
+		assert.NoError(t, event.Err)
+		assert.Equal(t, "ok", event.Output.MessageOutput.Message.Content)
+		_, ok = iter.Next()
+		assert.False(t, ok)
+	})
+
+	t.Run("block output", func(t *testing.T) {
+		for _, streaming := range []bool{false, true} {
+			ctrl := gomock.NewController(t)
+			cm := mockModel.NewMockToolCallingChatModel(ctrl)
+			cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+			if streaming {
+				cm.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(schema.StreamReaderFromArray([]*schema.Message{
+						schema.AssistantMessage("the ", nil),
+						schema.AssistantMessage("secret is 42", nil),
+					}), nil).Times(1)
+			} else {
+				cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(schema.AssistantMessage("the secret is 42", nil), nil).Times(1)
+			}
+
+			a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+				Name:        "agent",
+				Description: "agent",
+				Model:       cm,
+				ToolsConfig: ToolsConfig{
+					ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolForTest{tarCount: 1}}},
+				},
+				Middlewares: []AgentMiddleware{{Guardrails: []Guardrail{noSecret}}},
+			})
+			assert.NoError(t, err)
+
+			iter := a.Run(ctx, &AgentInput{Messages: []Message{schema.UserMessage("tell me")}, EnableStreaming: streaming})
+			event, ok := iter.Next()
+			assert.True(t, ok)
+			assert.NoError(t, event.Err)
+			msg, err := event.Output.MessageOutput.GetMessage()
+			assert.NoError(t, err)
+			assert.Equal(t, "I can't share that.", msg.Content)
+			assert.Equal(t, &FinishReason{
+				Status: FinishStatusIncomplete,
+				Reason: "blocked by guardrail[no_secret]: secret leaked",
+			}, event.Output.FinishReason)
+			_, ok = iter.Next()
+			assert.False(t, ok)
+		}
+	})
+}
@@ -129,6 +129,26 @@ type AgentOutput struct {
 	MessageOutput *MessageVariant
 
 	CustomizedOutput any
+
+	// FinishReason explains why the agent finished, set by agents which support it, e.g. the loop agent with ExitCondition.
+	FinishReason *FinishReason
+}
+
+// FinishStatus is the status of a finished agent.
+type FinishStatus string
+
+const (
+	// FinishStatusCompleted means the agent finished as expected.
+	FinishStatusCompleted FinishStatus = "completed"
+	// FinishStatusIncomplete means the agent was stopped before it completed, e.g. a limit was hit.
+	FinishStatusIncomplete FinishStatus = "incomplete"
+)
+
+// FinishReason explains why an agent finished.
+type FinishReason struct {
+	Status FinishStatus
+	// Reason is a human-readable explanation, e.g. "max iterations reached".
+	Reason string
 }
 
 func NewTransferToAgentAction(destAgentName string) *AgentAction {
@@ -197,6 +217,9 @@ type AgentEvent struct {
 	Action *AgentAction
 
 	Err error
+
+	// RunUsage is the token usage of the run so far, set by the Runner when RunnerConfig.TrackUsage is enabled.
+	RunUsage *RunUsage
 }
 
 type AgentInput struct {
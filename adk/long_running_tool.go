@@ -0,0 +1,131 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+func init() {
+	schema.RegisterName[*PendingToolCall]("_eino_adk_pending_tool_call")
+}
+
+// PendingToolCall is the payload of the interrupt raised when a long-running tool defers its result,
+// see ToolsConfig.LongRunning.
+type PendingToolCall struct {
+	ToolCallID string
+	ToolName   string
+	Arguments  string
+	// Handle is returned by the tool to track the job, e.g. the ID of an approval ticket or a batch job.
+	Handle string
+}
+
+type toolPendingError struct {
+	handle string
+}
+
+func (e *toolPendingError) Error() string {
+	return fmt.Sprintf("tool result is pending, handle: %s", e.handle)
+}
+
+// NewToolPendingErr is returned by a long-running tool, instead of the result, when the job it starts takes long to finish.
+// The agent then interrupts with a PendingToolCall carrying the handle, and the run is saved to the CheckPointStore.
+// Once the job finishes, call Runner.CompleteToolCalls with the result to resume the run.
+// e.g.
+//
+//	func (t *batchTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+//		jobID, err := t.client.Submit(ctx, argumentsInJSON)
+//		if err != nil {
+//			return "", err
+//		}
+//		return "", adk.NewToolPendingErr(jobID)
+//	}
+func NewToolPendingErr(handle string) error {
+	return &toolPendingError{handle: handle}
+}
+
+// WithToolResults passes the results of the pending tool calls when resuming, keyed by tool call ID.
+// The result is taken as the output of the tool call without running the tool again.
+// A pending tool call without a result is run again, so the long-running tool should be idempotent,
+// e.g. return the same handle for the same arguments.
+func WithToolResults(results map[string]string) AgentRunOption {
+	return WrapImplSpecificOptFn(func(t *chatModelAgentRunOptions) {
+		t.toolResults = results
+	})
+}
+
+// GetPendingToolCalls returns the pending calls of long-running tools within the interrupt info, sorted by tool call ID.
+func GetPendingToolCalls(info *InterruptInfo) []*PendingToolCall {
+	return getToolInterruptExtras(info, func(a, b *PendingToolCall) bool {
+		return a.ToolCallID < b.ToolCallID
+	})
+}
+
+// CompleteToolCalls resumes the run interrupted by long-running tools with their results, keyed by tool call ID.
+// It's a shortcut of Resume with WithToolResults.
+// e.g.
+//
+//	pending := adk.GetPendingToolCalls(event.Action.Interrupted)
+//	// ... days later, when the job of pending[0].Handle is done
+//	iter, err := runner.CompleteToolCalls(ctx, checkPointID, map[string]string{pending[0].ToolCallID: jobResult})
+func (r *Runner) CompleteToolCalls(ctx context.Context, checkPointID string, results map[string]string,
+	opts ...AgentRunOption) (*AsyncIterator[*AgentEvent], error) {
+
+	if len(results) == 0 {
+		return nil, errors.New("no tool result to complete")
+	}
+	return r.Resume(ctx, checkPointID, append(opts, WithToolResults(results))...)
+}
+
+type toolResultsOptions struct {
+	results map[string]string
+}
+
+func withToolResults(results map[string]string) tool.Option {
+	return tool.WrapImplSpecificOptFn(func(o *toolResultsOptions) {
+		o.results = results
+	})
+}
+
+// wrapLongRunningTools wraps the tools whose names are in longRunning, so they can defer their results by NewToolPendingErr.
+func wrapLongRunningTools(ctx context.Context, tools []tool.BaseTool, longRunning map[string]bool) ([]tool.BaseTool, error) {
+	return interceptTools(ctx, tools, longRunning, &toolInterceptor{
+		before: func(ctx context.Context, _, _ string, opts []tool.Option) (string, bool, error) {
+			o := tool.GetImplSpecificOptions(&toolResultsOptions{}, opts...)
+			result, ok := o.results[compose.GetToolCallID(ctx)]
+			return result, ok, nil
+		},
+		onError: func(ctx context.Context, name, argumentsInJSON string, err error) error {
+			var pe *toolPendingError
+			if !errors.As(err, &pe) {
+				return err
+			}
+			return compose.NewInterruptAndRerunErr(&PendingToolCall{
+				ToolCallID: compose.GetToolCallID(ctx),
+				ToolName:   name,
+				Arguments:  argumentsInJSON,
+				Handle:     pe.handle,
+			})
+		},
+	})
+}
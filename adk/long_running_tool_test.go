@@ -0,0 +1,110 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type batchJobTool struct {
+	calls int
+}
+
+func (b *batchJobTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "batch_job", Desc: "desc"}, nil
+}
+
+func (b *batchJobTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	b.calls++
+	return "", NewToolPendingErr("job_1")
+}
+
+func TestLongRunningTool(t *testing.T) {
+	ctx := context.Background()
+
+	bt := &batchJobTool{}
+	a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "name",
+		Description: "description",
+		Instruction: "instruction",
+		Model: &myModel{
+			validator: func(i int, messages []*schema.Message) bool {
+				if i == 1 {
+					return messages[len(messages)-1].Content == "1000 rows processed"
+				}
+				return true
+			},
+			messages: []*schema.Message{
+				schema.AssistantMessage("", []schema.ToolCall{
+					{ID: "call_1", Function: schema.FunctionCall{Name: "batch_job", Arguments: `{"table":"t"}`}},
+				}),
+				schema.AssistantMessage("completed", nil),
+			},
+		},
+		ToolsConfig: ToolsConfig{
+			ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{bt}},
+			LongRunning:     map[string]bool{"batch_job": true},
+		},
+	})
+	assert.NoError(t, err)
+	runner := NewRunner(ctx, RunnerConfig{Agent: a, CheckPointStore: newMyStore()})
+
+	iter := runner.Query(ctx, "process table t", WithCheckPointID("1"))
+	var interrupted *AgentEvent
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		if event.Action != nil && event.Action.Interrupted != nil {
+			interrupted = event
+		}
+	}
+	assert.NotNil(t, interrupted)
+	assert.Equal(t, 1, bt.calls)
+	assert.Equal(t, []*PendingToolCall{{
+		ToolCallID: "call_1",
+		ToolName:   "batch_job",
+		Arguments:  `{"table":"t"}`,
+		Handle:     "job_1",
+	}}, GetPendingToolCalls(interrupted.Action.Interrupted))
+
+	_, err = runner.CompleteToolCalls(ctx, "1", nil)
+	assert.Error(t, err)
+
+	iter, err = runner.CompleteToolCalls(ctx, "1", map[string]string{"call_1": "1000 rows processed"})
+	assert.NoError(t, err)
+	var contents []string
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		contents = append(contents, event.Output.MessageOutput.Message.Content)
+	}
+	assert.Equal(t, []string{"1000 rows processed", "completed"}, contents)
+	assert.Equal(t, 1, bt.calls)
+}
@@ -0,0 +1,399 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package reflection implements the reflection pattern, where the output of a generator agent is reviewed by a critic,
+// and the generator retries with the critique until the output is approved.
+package reflection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/bytedance/sonic"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Critique is the review of the generator output given by the critic.
+// It's emitted as AgentOutput.CustomizedOutput after each review.
+type Critique struct {
+	// Approved reports whether the output satisfies all the criteria.
+	Approved bool `json:"approved"`
+	// Feedback is the specific and actionable suggestion to improve the output, empty if approved.
+	Feedback string `json:"feedback"`
+}
+
+// Round is a finished round of generation and review.
+type Round struct {
+	Output   adk.Message
+	Critique *Critique
+}
+
+// ReviewContext is the input information for the critic and the retry of the generator.
+type ReviewContext struct {
+	UserInput []adk.Message
+	Criteria  []string
+	// Output is the output of the generator to be reviewed.
+	Output adk.Message
+	// Rounds are the finished rounds, the last one is the round of Output when generating the retry input.
+	Rounds []*Round
+}
+
+// GenInputFn generates the input messages for the critic or the retry of the generator.
+type GenInputFn func(ctx context.Context, in *ReviewContext) ([]adk.Message, error)
+
+var (
+	// CritiqueToolInfo defines the schema for the tool which the critic model calls to give the Critique.
+	CritiqueToolInfo = schema.ToolInfo{
+		Name: "Critique",
+		Desc: "Give the review of the response. Set approved to true only if the response satisfies all the criteria.",
+		ParamsOneOf: schema.NewParamsOneOfByParams(
+			map[string]*schema.ParameterInfo{
+				"approved": {
+					Type:     schema.Boolean,
+					Desc:     "whether the response satisfies all the criteria",
+					Required: true,
+				},
+				"feedback": {
+					Type: schema.String,
+					Desc: "specific and actionable suggestions to improve the response, required if not approved",
+				},
+			},
+		),
+	}
+
+	// CriticPrompt is the prompt template for the critic.
+	CriticPrompt = prompt.FromMessages(schema.FString,
+		schema.SystemMessage(`You are a strict and fair reviewer. Review the response to the request against the criteria below.
+
+## CRITERIA
+{criteria}
+
+## YOUR TASK
+Call '{critique_tool}' exactly once:
+- Set approved to true if the response satisfies ALL the criteria.
+- Otherwise set approved to false, and give specific, actionable feedback on what to change.`),
+		schema.UserMessage(`## REQUEST
+{input}
+## RESPONSE
+{output}`),
+	)
+
+	// RetryPrompt is the prompt template of the message appended to the generator input when retrying.
+	RetryPrompt = prompt.FromMessages(schema.FString,
+		schema.UserMessage(`Your response was reviewed and needs revision.
+## FEEDBACK
+{feedback}
+Please give the revised response in full.`),
+	)
+)
+
+// Config provides configuration options for creating a reflection agent.
+type Config struct {
+	// Name of the agent. Optional. Defaults to "reflection".
+	Name string
+	// Description of the agent. Optional. Defaults to the description of the Generator.
+	Description string
+
+	// Generator generates the output to be reviewed, its last assistant message without tool calls is reviewed.
+	Generator adk.Agent
+
+	// Critic is the model which reviews the output, it's configured with CritiqueToolInfo to give the Critique.
+	Critic model.ToolCallingChatModel
+
+	// Criteria are the requirements which the output must satisfy.
+	Criteria []string
+
+	// MaxRounds is the maximum number of generations, including the first one.
+	// Optional. Defaults to 3.
+	MaxRounds int
+
+	// GenCriticInputFn generates the input messages for the critic.
+	// Optional. If not provided, CriticPrompt will be used.
+	GenCriticInputFn GenInputFn
+
+	// GenRetryInputFn generates the input messages for the generator when retrying.
+	// Optional. If not provided, the user input followed by the output and the RetryPrompt message will be used.
+	GenRetryInputFn GenInputFn
+}
+
+// New creates a reflection agent.
+// In each round, the generator runs and its events are emitted as they are,
+// then the critic reviews the output and a Critique is emitted.
+// The agent stops when the output is approved, with a last event carrying AgentOutput.FinishReason:
+// FinishStatusCompleted if approved, or FinishStatusIncomplete if MaxRounds is reached before that.
+func New(ctx context.Context, cfg *Config) (adk.Agent, error) {
+	if cfg.Generator == nil {
+		return nil, errors.New("generator is required")
+	}
+	if cfg.Critic == nil {
+		return nil, errors.New("critic is required")
+	}
+
+	critic, err := cfg.Critic.WithTools([]*schema.ToolInfo{&CritiqueToolInfo})
+	if err != nil {
+		return nil, err
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = "reflection"
+	}
+	desc := cfg.Description
+	if desc == "" {
+		desc = cfg.Generator.Description(ctx)
+	}
+	maxRounds := cfg.MaxRounds
+	if maxRounds <= 0 {
+		maxRounds = 3
+	}
+	genCriticInput := cfg.GenCriticInputFn
+	if genCriticInput == nil {
+		genCriticInput = defaultGenCriticInputFn
+	}
+	genRetryInput := cfg.GenRetryInputFn
+	if genRetryInput == nil {
+		genRetryInput = defaultGenRetryInputFn
+	}
+
+	return &reflectionAgent{
+		name:           name,
+		description:    desc,
+		generator:      cfg.Generator,
+		critic:         critic,
+		criteria:       cfg.Criteria,
+		maxRounds:      maxRounds,
+		genCriticInput: genCriticInput,
+		genRetryInput:  genRetryInput,
+	}, nil
+}
+
+type reflectionAgent struct {
+	name        string
+	description string
+
+	generator adk.Agent
+	critic    model.BaseChatModel
+	criteria  []string
+	maxRounds int
+
+	genCriticInput GenInputFn
+	genRetryInput  GenInputFn
+}
+
+func (r *reflectionAgent) Name(_ context.Context) string {
+	return r.name
+}
+
+func (r *reflectionAgent) Description(_ context.Context) string {
+	return r.description
+}
+
+func (r *reflectionAgent) Run(ctx context.Context, input *adk.AgentInput, opts ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+	iterator, generator := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+
+	go func() {
+		defer func() {
+			panicErr := recover()
+			if panicErr != nil {
+				e := safe.NewPanicErr(panicErr, debug.Stack())
+				generator.Send(&adk.AgentEvent{Err: e})
+			}
+
+			generator.Close()
+		}()
+
+		rc := &ReviewContext{
+			UserInput: input.Messages,
+			Criteria:  r.criteria,
+		}
+		genInput := input
+		for round := 0; round < r.maxRounds; round++ {
+			if round > 0 {
+				msgs, err := r.genRetryInput(ctx, rc)
+				if err != nil {
+					generator.Send(&adk.AgentEvent{Err: fmt.Errorf("failed to generate retry input: %w", err)})
+					return
+				}
+				genInput = &adk.AgentInput{Messages: msgs, EnableStreaming: input.EnableStreaming}
+			}
+
+			output, ok := r.runGenerator(ctx, genInput, generator, opts...)
+			if !ok {
+				return
+			}
+			if output == nil {
+				generator.Send(&adk.AgentEvent{Err: errors.New("generator gives no output to review")})
+				return
+			}
+
+			rc.Output = output
+			critique, err := r.review(ctx, rc)
+			if err != nil {
+				generator.Send(&adk.AgentEvent{Err: err})
+				return
+			}
+			rc.Rounds = append(rc.Rounds, &Round{Output: output, Critique: critique})
+			generator.Send(&adk.AgentEvent{Output: &adk.AgentOutput{CustomizedOutput: critique}})
+
+			if critique.Approved {
+				generator.Send(finishEvent(adk.FinishStatusCompleted, fmt.Sprintf("output approved in round %d", round+1)))
+				return
+			}
+		}
+
+		generator.Send(finishEvent(adk.FinishStatusIncomplete, fmt.Sprintf("max rounds reached: %d", r.maxRounds)))
+	}()
+
+	return iterator
+}
+
+// runGenerator forwards the events of the generator and returns its last assistant message without tool calls.
+// It returns false if the generator ends with an error or an interrupt, which has been forwarded.
+func (r *reflectionAgent) runGenerator(ctx context.Context, input *adk.AgentInput,
+	generator *adk.AsyncGenerator[*adk.AgentEvent], opts ...adk.AgentRunOption) (adk.Message, bool) {
+
+	var output adk.Message
+	iter := r.generator.Run(ctx, input, opts...)
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if event.Err != nil {
+			generator.Send(event)
+			return nil, false
+		}
+		if event.Action != nil && event.Action.Interrupted != nil {
+			// the reflection agent doesn't support resuming
+			generator.Send(event)
+			return nil, false
+		}
+
+		if event.Output == nil || event.Output.MessageOutput == nil || event.Output.MessageOutput.Role != schema.Assistant {
+			generator.Send(event)
+			continue
+		}
+
+		mv := event.Output.MessageOutput
+		msg := mv.Message
+		if mv.IsStreaming {
+			ss := mv.MessageStream.Copy(2)
+			mv.MessageStream = ss[0]
+			generator.Send(event)
+
+			var err error
+			msg, err = schema.ConcatMessageStream(ss[1])
+			if err != nil {
+				// the error has been delivered to the caller along with the stream
+				return nil, false
+			}
+		} else {
+			generator.Send(event)
+		}
+		if msg != nil && len(msg.ToolCalls) == 0 {
+			output = msg
+		}
+	}
+
+	return output, true
+}
+
+func (r *reflectionAgent) review(ctx context.Context, rc *ReviewContext) (*Critique, error) {
+	msgs, err := r.genCriticInput(ctx, rc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate critic input: %w", err)
+	}
+
+	msg, err := r.critic.Generate(ctx, msgs, model.WithToolChoice(schema.ToolChoiceForced))
+	if err != nil {
+		return nil, fmt.Errorf("failed to review output: %w", err)
+	}
+	if len(msg.ToolCalls) == 0 {
+		return nil, errors.New("critic gives no critique")
+	}
+
+	critique := &Critique{}
+	if err = sonic.UnmarshalString(msg.ToolCalls[0].Function.Arguments, critique); err != nil {
+		return nil, fmt.Errorf("unmarshal critique error: %w", err)
+	}
+	return critique, nil
+}
+
+func finishEvent(status adk.FinishStatus, reason string) *adk.AgentEvent {
+	return &adk.AgentEvent{
+		Output: &adk.AgentOutput{
+			FinishReason: &adk.FinishReason{
+				Status: status,
+				Reason: reason,
+			},
+		},
+	}
+}
+
+func defaultGenCriticInputFn(ctx context.Context, in *ReviewContext) ([]adk.Message, error) {
+	return CriticPrompt.Format(ctx, map[string]any{
+		"criteria":      formatCriteria(in.Criteria),
+		"critique_tool": CritiqueToolInfo.Name,
+		"input":         formatInput(in.UserInput),
+		"output":        in.Output.Content,
+	})
+}
+
+func defaultGenRetryInputFn(ctx context.Context, in *ReviewContext) ([]adk.Message, error) {
+	last := in.Rounds[len(in.Rounds)-1]
+	retry, err := RetryPrompt.Format(ctx, map[string]any{
+		"feedback": last.Critique.Feedback,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]adk.Message, 0, len(in.UserInput)+1+len(retry))
+	msgs = append(msgs, in.UserInput...)
+	msgs = append(msgs, schema.AssistantMessage(last.Output.Content, nil))
+	return append(msgs, retry...), nil
+}
+
+func formatCriteria(criteria []string) string {
+	if len(criteria) == 0 {
+		return "- The response fully and correctly addresses the request."
+	}
+	var sb strings.Builder
+	for _, c := range criteria {
+		sb.WriteString("- ")
+		sb.WriteString(c)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// formatInput formats the input messages into a string.
+func formatInput(input []adk.Message) string {
+	var sb strings.Builder
+	for _, msg := range input {
+		sb.WriteString(msg.Content)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
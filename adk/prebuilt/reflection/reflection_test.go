@@ -0,0 +1,168 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reflection
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func critiqueMessage(args string) *schema.Message {
+	return schema.AssistantMessage("", []schema.ToolCall{{
+		ID:       "1",
+		Function: schema.FunctionCall{Name: CritiqueToolInfo.Name, Arguments: args},
+	}})
+}
+
+func TestReflection(t *testing.T) {
+	ctx := context.Background()
+
+	for _, streaming := range []bool{false, true} {
+		ctrl := gomock.NewController(t)
+
+		genModel := mockModel.NewMockToolCallingChatModel(ctrl)
+		times := 0
+		genOutput := func(input []*schema.Message) *schema.Message {
+			times++
+			if times == 1 {
+				return schema.AssistantMessage("draft", nil)
+			}
+			assert.Len(t, input, 3)
+			assert.Equal(t, "write a poem", input[0].Content)
+			assert.Equal(t, "draft", input[1].Content)
+			assert.Contains(t, input[2].Content, "make it rhyme")
+			return schema.AssistantMessage("revised", nil)
+		}
+		if streaming {
+			genModel.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+					return schema.StreamReaderFromArray([]*schema.Message{genOutput(input)}), nil
+				}).Times(2)
+		} else {
+			genModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+					return genOutput(input), nil
+				}).Times(2)
+		}
+
+		gen, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+			Name:        "writer",
+			Description: "writes poems",
+			Model:       genModel,
+		})
+		assert.NoError(t, err)
+
+		critic := mockModel.NewMockToolCallingChatModel(ctrl)
+		critic.EXPECT().WithTools(gomock.Any()).Return(critic, nil).Times(1)
+		critic.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				assert.Contains(t, input[0].Content, "- must rhyme")
+				assert.Contains(t, input[1].Content, "write a poem")
+				if strings.HasSuffix(input[1].Content, "draft") {
+					return critiqueMessage(`{"approved":false,"feedback":"make it rhyme"}`), nil
+				}
+				return critiqueMessage(`{"approved":true}`), nil
+			}).Times(2)
+
+		a, err := New(ctx, &Config{
+			Generator: gen,
+			Critic:    critic,
+			Criteria:  []string{"must rhyme"},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "reflection", a.Name(ctx))
+		assert.Equal(t, "writes poems", a.Description(ctx))
+
+		iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: a, EnableStreaming: streaming}).Query(ctx, "write a poem")
+		var outputs []string
+		var critiques []*Critique
+		var finish *adk.FinishReason
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+			if event.Output == nil {
+				continue
+			}
+			if event.Output.MessageOutput != nil {
+				msg, err := event.Output.MessageOutput.GetMessage()
+				assert.NoError(t, err)
+				outputs = append(outputs, msg.Content)
+			}
+			if c, ok := event.Output.CustomizedOutput.(*Critique); ok {
+				critiques = append(critiques, c)
+			}
+			if event.Output.FinishReason != nil {
+				finish = event.Output.FinishReason
+			}
+		}
+		assert.Equal(t, []string{"draft", "revised"}, outputs)
+		assert.Equal(t, []*Critique{{Feedback: "make it rhyme"}, {Approved: true}}, critiques)
+		assert.Equal(t, adk.FinishStatusCompleted, finish.Status)
+		ctrl.Finish()
+	}
+}
+
+func TestReflectionMaxRounds(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	genModel := mockModel.NewMockToolCallingChatModel(ctrl)
+	genModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("draft", nil), nil).Times(2)
+	gen, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "writer",
+		Description: "writes poems",
+		Model:       genModel,
+	})
+	assert.NoError(t, err)
+
+	critic := mockModel.NewMockToolCallingChatModel(ctrl)
+	critic.EXPECT().WithTools(gomock.Any()).Return(critic, nil).Times(1)
+	critic.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(critiqueMessage(`{"approved":false,"feedback":"try again"}`), nil).Times(2)
+
+	a, err := New(ctx, &Config{Generator: gen, Critic: critic, MaxRounds: 2})
+	assert.NoError(t, err)
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: a}).Query(ctx, "write a poem")
+	var finish *adk.FinishReason
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		if event.Output != nil && event.Output.FinishReason != nil {
+			finish = event.Output.FinishReason
+		}
+	}
+	assert.Equal(t, adk.FinishStatusIncomplete, finish.Status)
+	assert.Equal(t, "max rounds reached: 2", finish.Reason)
+}
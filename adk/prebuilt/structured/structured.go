@@ -0,0 +1,366 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package structured implements an agent which makes sure the final answer of another agent conforms to a JSON schema.
+// The answer is validated against the schema, and the agent is asked to repair it with the validation errors until it conforms,
+// then it's decoded into a typed go value.
+package structured
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strings"
+
+	"github.com/bytedance/sonic"
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Result is the final answer decoded into T.
+// It's emitted as AgentOutput.CustomizedOutput of the last event, use GetResult to get it.
+type Result[T any] struct {
+	Value T
+	// Raw is the JSON answer which Value is decoded from.
+	Raw string
+	// Repairs is the number of repair attempts taken before the answer conforms to the schema.
+	Repairs int
+}
+
+// GetResult returns the Result carried by the event, if any.
+func GetResult[T any](event *adk.AgentEvent) (*Result[T], bool) {
+	if event == nil || event.Output == nil {
+		return nil, false
+	}
+	r, ok := event.Output.CustomizedOutput.(*Result[T])
+	return r, ok
+}
+
+// SchemaViolationError is returned when the answer still doesn't conform to the schema after all the repair attempts.
+type SchemaViolationError struct {
+	// Output is the last answer.
+	Output string
+	Errors []*ValidationError
+}
+
+func (e *SchemaViolationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return fmt.Sprintf("output doesn't conform to the schema: %s", strings.Join(msgs, "; "))
+}
+
+// Attempt is an answer which doesn't conform to the schema.
+type Attempt struct {
+	Output adk.Message
+	Errors []*ValidationError
+}
+
+// RepairContext is the input information for generating the repair input.
+type RepairContext struct {
+	// Input is the input messages of the first run, including the schema prompt if enabled.
+	Input  []adk.Message
+	Schema *jsonschema.Schema
+	// Attempts are the failed answers so far, the last one is to be repaired.
+	Attempts []*Attempt
+}
+
+// GenRepairInputFn generates the input messages for the agent to repair its answer.
+type GenRepairInputFn func(ctx context.Context, rc *RepairContext) ([]adk.Message, error)
+
+var (
+	// SchemaPrompt is the prompt template of the system message prepended to the input, which tells the agent the schema.
+	SchemaPrompt = prompt.FromMessages(schema.FString,
+		schema.SystemMessage("Your final answer must be a single JSON value conforming to the following JSON schema, without any other text:\n{schema}"),
+	)
+
+	// RepairPrompt is the prompt template of the message appended to the input when repairing.
+	RepairPrompt = prompt.FromMessages(schema.FString,
+		schema.UserMessage(`Your answer doesn't conform to the JSON schema.
+## ERRORS
+{errors}
+Please fix the errors and give the whole answer again, as a single JSON value only.`),
+	)
+)
+
+// Config provides configuration options for creating a structured output agent.
+type Config[T any] struct {
+	// Name of the agent. Optional. Defaults to the name of Agent.
+	Name string
+	// Description of the agent. Optional. Defaults to the description of Agent.
+	Description string
+
+	// Agent gives the answer, its last assistant message without tool calls is taken as the answer.
+	Agent adk.Agent
+
+	// Schema is the JSON schema which the answer must conform to.
+	// Optional. If not provided, it's reflected from T.
+	Schema *jsonschema.Schema
+
+	// MaxRepairs is the maximum number of repair attempts after the first answer.
+	// Optional. Defaults to 2.
+	MaxRepairs int
+
+	// DisableSchemaPrompt disables prepending the SchemaPrompt message to the input,
+	// e.g. when the instruction of Agent already specifies the output format.
+	DisableSchemaPrompt bool
+
+	// GenRepairInputFn generates the input messages for the agent to repair its answer.
+	// Optional. If not provided, the input followed by the answer and the RepairPrompt message will be used.
+	GenRepairInputFn GenRepairInputFn
+}
+
+// New creates an agent which runs Agent and makes sure its answer conforms to the schema.
+// The events of Agent are emitted as they are. When the answer doesn't conform to the schema, Agent runs again
+// with the validation errors to repair it, for at most MaxRepairs times.
+// Once the answer conforms, it's decoded into T and emitted in a last event as a *Result[T],
+// otherwise an error event with *SchemaViolationError is emitted.
+// e.g.
+//
+//	agent, _ := structured.New(ctx, &structured.Config[Weather]{Agent: weatherAgent})
+//	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent}).Query(ctx, "weather in Beijing")
+//	for {
+//		event, ok := iter.Next()
+//		if !ok {
+//			break
+//		}
+//		if r, ok := structured.GetResult[Weather](event); ok {
+//			fmt.Println(r.Value.Temperature)
+//		}
+//	}
+func New[T any](ctx context.Context, cfg *Config[T]) (adk.Agent, error) {
+	if cfg.Agent == nil {
+		return nil, errors.New("agent is required")
+	}
+
+	s := cfg.Schema
+	if s == nil {
+		s = jsonschema.Reflect(new(T))
+	}
+	var schemaPrompt []adk.Message
+	if !cfg.DisableSchemaPrompt {
+		data, err := sonic.MarshalString(s)
+		if err != nil {
+			return nil, fmt.Errorf("marshal schema error: %w", err)
+		}
+		schemaPrompt, err = SchemaPrompt.Format(ctx, map[string]any{"schema": data})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = cfg.Agent.Name(ctx)
+	}
+	desc := cfg.Description
+	if desc == "" {
+		desc = cfg.Agent.Description(ctx)
+	}
+	maxRepairs := cfg.MaxRepairs
+	if maxRepairs <= 0 {
+		maxRepairs = 2
+	}
+	genRepairInput := cfg.GenRepairInputFn
+	if genRepairInput == nil {
+		genRepairInput = defaultGenRepairInputFn
+	}
+
+	return &structuredAgent[T]{
+		name:           name,
+		description:    desc,
+		agent:          cfg.Agent,
+		schema:         s,
+		schemaPrompt:   schemaPrompt,
+		maxRepairs:     maxRepairs,
+		genRepairInput: genRepairInput,
+	}, nil
+}
+
+type structuredAgent[T any] struct {
+	name        string
+	description string
+
+	agent        adk.Agent
+	schema       *jsonschema.Schema
+	schemaPrompt []adk.Message
+	maxRepairs   int
+
+	genRepairInput GenRepairInputFn
+}
+
+func (s *structuredAgent[T]) Name(_ context.Context) string {
+	return s.name
+}
+
+func (s *structuredAgent[T]) Description(_ context.Context) string {
+	return s.description
+}
+
+func (s *structuredAgent[T]) Run(ctx context.Context, input *adk.AgentInput, opts ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+	iterator, generator := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+
+	go func() {
+		defer func() {
+			panicErr := recover()
+			if panicErr != nil {
+				e := safe.NewPanicErr(panicErr, debug.Stack())
+				generator.Send(&adk.AgentEvent{Err: e})
+			}
+
+			generator.Close()
+		}()
+
+		msgs := make([]adk.Message, 0, len(s.schemaPrompt)+len(input.Messages))
+		msgs = append(msgs, s.schemaPrompt...)
+		msgs = append(msgs, input.Messages...)
+		rc := &RepairContext{Input: msgs, Schema: s.schema}
+
+		runInput := &adk.AgentInput{Messages: msgs, EnableStreaming: input.EnableStreaming}
+		for repairs := 0; ; repairs++ {
+			if repairs > 0 {
+				repairMsgs, err := s.genRepairInput(ctx, rc)
+				if err != nil {
+					generator.Send(&adk.AgentEvent{Err: fmt.Errorf("failed to generate repair input: %w", err)})
+					return
+				}
+				runInput = &adk.AgentInput{Messages: repairMsgs, EnableStreaming: input.EnableStreaming}
+			}
+
+			output, ok := s.runAgent(ctx, runInput, generator, opts...)
+			if !ok {
+				return
+			}
+			if output == nil {
+				generator.Send(&adk.AgentEvent{Err: errors.New("agent gives no answer")})
+				return
+			}
+
+			raw := extractJSON(output.Content)
+			result := &Result[T]{Raw: raw, Repairs: repairs}
+			verrs := Validate(s.schema, raw)
+			if len(verrs) == 0 {
+				if err := sonic.UnmarshalString(raw, &result.Value); err != nil {
+					verrs = []*ValidationError{{Path: "$", Message: fmt.Sprintf("failed to decode: %v", err)}}
+				}
+			}
+			if len(verrs) == 0 {
+				generator.Send(&adk.AgentEvent{Output: &adk.AgentOutput{CustomizedOutput: result}})
+				return
+			}
+
+			rc.Attempts = append(rc.Attempts, &Attempt{Output: output, Errors: verrs})
+			if repairs >= s.maxRepairs {
+				generator.Send(&adk.AgentEvent{Err: &SchemaViolationError{Output: output.Content, Errors: verrs}})
+				return
+			}
+		}
+	}()
+
+	return iterator
+}
+
+// runAgent forwards the events of the agent and returns its last assistant message without tool calls.
+// It returns false if the agent ends with an error or an interrupt, which has been forwarded.
+func (s *structuredAgent[T]) runAgent(ctx context.Context, input *adk.AgentInput,
+	generator *adk.AsyncGenerator[*adk.AgentEvent], opts ...adk.AgentRunOption) (adk.Message, bool) {
+
+	var output adk.Message
+	iter := s.agent.Run(ctx, input, opts...)
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if event.Err != nil {
+			generator.Send(event)
+			return nil, false
+		}
+		if event.Action != nil && event.Action.Interrupted != nil {
+			// the structured output agent doesn't support resuming
+			generator.Send(event)
+			return nil, false
+		}
+
+		if event.Output == nil || event.Output.MessageOutput == nil || event.Output.MessageOutput.Role != schema.Assistant {
+			generator.Send(event)
+			continue
+		}
+
+		mv := event.Output.MessageOutput
+		msg := mv.Message
+		if mv.IsStreaming {
+			ss := mv.MessageStream.Copy(2)
+			mv.MessageStream = ss[0]
+			generator.Send(event)
+
+			var err error
+			msg, err = schema.ConcatMessageStream(ss[1])
+			if err != nil {
+				// the error has been delivered to the caller along with the stream
+				return nil, false
+			}
+		} else {
+			generator.Send(event)
+		}
+		if msg != nil && len(msg.ToolCalls) == 0 {
+			output = msg
+		}
+	}
+
+	return output, true
+}
+
+// extractJSON strips the markdown code fence which models often wrap JSON answers in.
+func extractJSON(content string) string {
+	content = strings.TrimSpace(content)
+	if !strings.HasPrefix(content, "```") || !strings.HasSuffix(content, "```") || len(content) < 6 {
+		return content
+	}
+	content = strings.TrimSuffix(content, "```")
+	if i := strings.Index(content, "\n"); i >= 0 {
+		// the first line is the fence, with an optional language tag
+		return strings.TrimSpace(content[i+1:])
+	}
+	return strings.TrimSpace(strings.TrimPrefix(content, "```"))
+}
+
+func defaultGenRepairInputFn(ctx context.Context, rc *RepairContext) ([]adk.Message, error) {
+	last := rc.Attempts[len(rc.Attempts)-1]
+	var sb strings.Builder
+	for _, err := range last.Errors {
+		sb.WriteString("- ")
+		sb.WriteString(err.Error())
+		sb.WriteString("\n")
+	}
+	repair, err := RepairPrompt.Format(ctx, map[string]any{
+		"errors": sb.String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	msgs := make([]adk.Message, 0, len(rc.Input)+1+len(repair))
+	msgs = append(msgs, rc.Input...)
+	msgs = append(msgs, schema.AssistantMessage(last.Output.Content, nil))
+	return append(msgs, repair...), nil
+}
@@ -0,0 +1,147 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package structured
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type weather struct {
+	City        string `json:"city"`
+	Temperature int    `json:"temperature" jsonschema:"minimum=-50,maximum=60"`
+	Condition   string `json:"condition,omitempty" jsonschema:"enum=sunny,enum=rainy"`
+}
+
+func TestStructured(t *testing.T) {
+	ctx := context.Background()
+
+	for _, streaming := range []bool{false, true} {
+		ctrl := gomock.NewController(t)
+
+		cm := mockModel.NewMockToolCallingChatModel(ctrl)
+		times := 0
+		output := func(input []*schema.Message) *schema.Message {
+			times++
+			if times == 1 {
+				assert.Equal(t, schema.System, input[0].Role)
+				assert.Contains(t, input[0].Content, `"temperature"`)
+				return schema.AssistantMessage("```json\n{\"city\": \"Beijing\", \"temperature\": 99, \"condition\": \"windy\"}\n```", nil)
+			}
+			last := input[len(input)-1]
+			assert.Contains(t, last.Content, "$.temperature: 99 is greater than maximum 60")
+			assert.Contains(t, last.Content, "$.condition: value \"windy\" is not one of")
+			assert.Equal(t, schema.Assistant, input[len(input)-2].Role)
+			return schema.AssistantMessage(`{"city": "Beijing", "temperature": 25, "condition": "sunny"}`, nil)
+		}
+		if streaming {
+			cm.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+					return schema.StreamReaderFromArray([]*schema.Message{output(input)}), nil
+				}).Times(2)
+		} else {
+			cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+					return output(input), nil
+				}).Times(2)
+		}
+
+		inner, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+			Name:        "weather",
+			Description: "reports weather",
+			Model:       cm,
+		})
+		assert.NoError(t, err)
+		agent, err := New(ctx, &Config[weather]{Agent: inner})
+		assert.NoError(t, err)
+		assert.Equal(t, "weather", agent.Name(ctx))
+
+		iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent, EnableStreaming: streaming}).Query(ctx, "weather in Beijing")
+		var result *Result[weather]
+		answers := 0
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+			if r, ok := GetResult[weather](event); ok {
+				result = r
+				continue
+			}
+			if event.Output != nil && event.Output.MessageOutput != nil {
+				_, err = event.Output.MessageOutput.GetMessage()
+				assert.NoError(t, err)
+				answers++
+			}
+		}
+		assert.Equal(t, 2, answers)
+		if assert.NotNil(t, result) {
+			assert.Equal(t, weather{City: "Beijing", Temperature: 25, Condition: "sunny"}, result.Value)
+			assert.Equal(t, 1, result.Repairs)
+		}
+	}
+}
+
+func TestStructuredMaxRepairs(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("it's sunny", nil), nil).Times(2)
+	inner, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "weather",
+		Description: "reports weather",
+		Model:       cm,
+	})
+	assert.NoError(t, err)
+
+	agent, err := New(ctx, &Config[weather]{
+		Name:                "structured",
+		Agent:               inner,
+		MaxRepairs:          1,
+		DisableSchemaPrompt: true,
+	})
+	assert.NoError(t, err)
+
+	iter := agent.Run(ctx, &adk.AgentInput{Messages: []adk.Message{schema.UserMessage("weather in Beijing")}})
+	var lastErr error
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if event.Err != nil {
+			lastErr = event.Err
+		}
+	}
+	var sve *SchemaViolationError
+	if assert.True(t, errors.As(lastErr, &sve)) {
+		assert.Equal(t, "it's sunny", sve.Output)
+		assert.Contains(t, sve.Errors[0].Message, "invalid JSON")
+	}
+}
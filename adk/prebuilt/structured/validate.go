@@ -0,0 +1,34 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package structured
+
+import (
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/internal/schemavalidate"
+)
+
+// ValidationError is a violation of the schema found in the output.
+type ValidationError = schemavalidate.ValidationError
+
+// Validate checks the JSON data against the schema, and returns all the violations found.
+// It supports the commonly used keywords of JSON schema draft 2020-12, including types, enum, const,
+// object properties, array items, numeric and length bounds, patterns, local $ref and the combining keywords.
+// Annotations such as format are not checked.
+func Validate(s *jsonschema.Schema, data string) []*ValidationError {
+	return schemavalidate.Validate(s, data)
+}
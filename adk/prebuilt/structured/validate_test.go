@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package structured
+
+import (
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/eino-contrib/jsonschema"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	s := &jsonschema.Schema{}
+	assert.NoError(t, sonic.UnmarshalString(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1, "pattern": "^[a-z]+$"},
+			"age": {"type": "integer", "minimum": 0},
+			"tags": {"type": "array", "items": {"type": "string"}, "maxItems": 2, "uniqueItems": true},
+			"pet": {"$ref": "#/$defs/Pet"}
+		},
+		"required": ["name", "age"],
+		"additionalProperties": false,
+		"$defs": {
+			"Pet": {
+				"type": "object",
+				"properties": {"kind": {"enum": ["cat", "dog"]}},
+				"required": ["kind"]
+			}
+		}
+	}`, s))
+
+	errMsgs := func(data string) []string {
+		var msgs []string
+		for _, err := range Validate(s, data) {
+			msgs = append(msgs, err.Error())
+		}
+		return msgs
+	}
+
+	assert.Empty(t, errMsgs(`{"name": "tom", "age": 3, "tags": ["a", "b"], "pet": {"kind": "cat"}}`))
+	assert.Equal(t, []string{
+		`$: missing required property "name"`,
+		`$.age: expected integer, got number`,
+		`$: additional property "extra" is not allowed`,
+		`$.pet: missing required property "kind"`,
+		`$.tags: has 3 items, more than maxItems 2`,
+		`$.tags: items 0 and 1 are equal, but items must be unique`,
+		`$.tags[2]: expected string, got number`,
+	}, errMsgs(`{"age": 1.5, "tags": ["a", "a", 1], "pet": {}, "extra": true}`))
+	assert.Equal(t, []string{
+		`$.age: -1 is less than minimum 0`,
+		`$.name: "Tom" doesn't match pattern ^[a-z]+$`,
+		`$.pet.kind: value "fish" is not one of ["cat","dog"]`,
+	}, errMsgs(`{"name": "Tom", "age": -1, "pet": {"kind": "fish"}}`))
+	assert.Equal(t, []string{`$: expected object, got array`}, errMsgs(`[]`))
+	assert.Contains(t, errMsgs(`{"name": `)[0], "invalid JSON")
+	assert.Contains(t, errMsgs(`{} {}`)[0], "invalid JSON")
+
+	oneOf := &jsonschema.Schema{OneOf: []*jsonschema.Schema{{Type: "integer"}, {Type: "number"}}}
+	assert.Len(t, Validate(oneOf, `1`), 1)
+	assert.Empty(t, Validate(oneOf, `1.5`))
+}
+
+func TestExtractJSON(t *testing.T) {
+	assert.Equal(t, `{"a": 1}`, extractJSON("```json\n{\"a\": 1}\n```"))
+	assert.Equal(t, `{"a": 1}`, extractJSON(" ```\n{\"a\": 1}```\n"))
+	assert.Equal(t, `{"a": 1}`, extractJSON(`{"a": 1}`))
+}
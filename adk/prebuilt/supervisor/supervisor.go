@@ -18,16 +18,54 @@ package supervisor
 
 import (
 	"context"
+	"errors"
 
 	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
+)
+
+const (
+	defaultSupervisorName        = "supervisor"
+	defaultSupervisorDescription = "supervisor which routes requests among worker agents"
+
+	defaultSupervisorInstruction = `You are a supervisor managing a team of worker agents.
+Route each user request to the single most suitable worker by transferring to it, based on the descriptions of the workers.
+When a worker hands the conversation back to you, review its result: transfer to another worker if more work is needed, otherwise answer the user directly.
+Do not do the workers' jobs yourself.`
 )
 
 type Config struct {
 	// Supervisor specifies the agent that will act as the supervisor, coordinating and managing the sub-agents.
+	// Optional. If not set, a ChatModelAgent is built from Model, Name, Description and Instruction,
+	// which routes requests among the sub-agents by calling the transfer tool.
 	Supervisor adk.Agent
 
 	// SubAgents specifies the list of agents that will be supervised and coordinated by the supervisor agent.
 	SubAgents []adk.Agent
+
+	// Model is the tool calling model of the built-in supervisor, required when Supervisor is not set.
+	Model model.ToolCallingChatModel
+	// Name of the built-in supervisor. Optional, "supervisor" by default.
+	Name string
+	// Description of the built-in supervisor. Optional.
+	Description string
+	// Instruction is the routing prompt of the built-in supervisor.
+	// Optional, a default prompt which routes each request to the most suitable worker is used if empty.
+	// The descriptions of the sub-agents are appended to the instruction automatically.
+	Instruction string
+
+	// WorkerDescriptions overrides the descriptions of the sub-agents seen by the supervisor when routing, keyed by agent name.
+	// Optional, the sub-agent's own Description is used if not set.
+	WorkerDescriptions map[string]string
+
+	// CapabilityRegistry lets the built-in supervisor route by the capabilities of the sub-agents, which are appended to its instruction at every run.
+	// Sub-agents not in the registry are registered with their descriptions and self-advertised capabilities, see adk.CapabilityProvider.
+	// Optional.
+	CapabilityRegistry *adk.CapabilityRegistry
+
+	// DisableHandback disables the forced handback to the supervisor after each turn of the sub-agents.
+	// By default, a sub-agent always transfers back to the supervisor when it finishes, so the supervisor has the final say.
+	DisableHandback bool
 }
 
 // New creates a supervisor-based multi-agent system with the given configuration.
@@ -37,14 +75,83 @@ type Config struct {
 // sub-agents can only communicate with the supervisor (not with each other directly).
 // This hierarchical structure enables complex problem-solving through coordinated agent interactions.
 func New(ctx context.Context, conf *Config) (adk.Agent, error) {
+	if conf.CapabilityRegistry != nil {
+		for _, subAgent := range conf.SubAgents {
+			if conf.CapabilityRegistry.Has(subAgent.Name(ctx)) {
+				continue
+			}
+			agent := subAgent
+			var caps []*adk.Capability
+			if desc, ok := conf.WorkerDescriptions[subAgent.Name(ctx)]; ok {
+				// the described agent hides the capabilities provided by the sub-agent, so pass them explicitly
+				if cp, ok := subAgent.(adk.CapabilityProvider); ok {
+					caps = cp.Capabilities(ctx)
+				}
+				agent = adk.AgentWithOptions(ctx, subAgent, adk.WithDescription(desc))
+			}
+			if err := conf.CapabilityRegistry.Register(ctx, agent, caps...); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	supervisor, err := newSupervisorAgent(ctx, conf)
+	if err != nil {
+		return nil, err
+	}
+
 	subAgents := make([]adk.Agent, 0, len(conf.SubAgents))
-	supervisorName := conf.Supervisor.Name(ctx)
+	supervisorName := supervisor.Name(ctx)
 	for _, subAgent := range conf.SubAgents {
-		subAgents = append(subAgents, adk.AgentWithDeterministicTransferTo(ctx, &adk.DeterministicTransferConfig{
-			Agent:        subAgent,
-			ToAgentNames: []string{supervisorName},
-		}))
+		agent := subAgent
+		if !conf.DisableHandback {
+			agent = adk.AgentWithDeterministicTransferTo(ctx, &adk.DeterministicTransferConfig{
+				Agent:        subAgent,
+				ToAgentNames: []string{supervisorName},
+			})
+		}
+		if desc, ok := conf.WorkerDescriptions[subAgent.Name(ctx)]; ok {
+			agent = adk.AgentWithOptions(ctx, agent, adk.WithDescription(desc))
+		}
+		subAgents = append(subAgents, agent)
+	}
+
+	return adk.SetSubAgents(ctx, supervisor, subAgents)
+}
+
+func newSupervisorAgent(ctx context.Context, conf *Config) (adk.Agent, error) {
+	if conf.Supervisor != nil {
+		return conf.Supervisor, nil
+	}
+	if conf.Model == nil {
+		return nil, errors.New("either Supervisor or Model must be set")
 	}
 
-	return adk.SetSubAgents(ctx, conf.Supervisor, subAgents)
+	name := conf.Name
+	if name == "" {
+		name = defaultSupervisorName
+	}
+	description := conf.Description
+	if description == "" {
+		description = defaultSupervisorDescription
+	}
+	instruction := conf.Instruction
+	if instruction == "" {
+		instruction = defaultSupervisorInstruction
+	}
+	var genModelInput adk.GenModelInput
+	if conf.CapabilityRegistry != nil {
+		names := make([]string, 0, len(conf.SubAgents))
+		for _, subAgent := range conf.SubAgents {
+			names = append(names, subAgent.Name(ctx))
+		}
+		genModelInput = conf.CapabilityRegistry.GenModelInput(nil, &adk.CapabilityQuery{AgentNames: names})
+	}
+	return adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:          name,
+		Description:   description,
+		Instruction:   instruction,
+		Model:         conf.Model,
+		GenModelInput: genModelInput,
+	})
 }
@@ -24,7 +24,9 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model"
 	mockAdk "github.com/cloudwego/eino/internal/mock/adk"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -167,3 +169,224 @@ func TestNewSupervisor(t *testing.T) {
 	assert.Equal(t, schema.Assistant, event.Output.MessageOutput.Role)
 	assert.Equal(t, finishMsg.Content, event.Output.MessageOutput.Message.Content)
 }
+
+func TestNewSupervisorWithModel(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	_, err := New(ctx, &Config{})
+	assert.Error(t, err)
+
+	worker := mockAdk.NewMockAgent(ctrl)
+	worker.EXPECT().Name(gomock.Any()).Return("Worker").AnyTimes()
+	worker.EXPECT().Description(gomock.Any()).Return("original description").AnyTimes()
+	i, g := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	g.Send(adk.EventFromMessage(schema.AssistantMessage("worker result", nil), nil, schema.Assistant, ""))
+	g.Close()
+	worker.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(i).Times(1)
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			assert.Contains(t, input[0].Content, "route to me")
+			assert.Contains(t, input[0].Content, "custom description")
+			assert.NotContains(t, input[0].Content, "original description")
+			return schema.AssistantMessage("", []schema.ToolCall{{
+				ID:       "1",
+				Function: schema.FunctionCall{Name: adk.TransferToAgentToolName, Arguments: `{"agent_name":"Worker"}`},
+			}}), nil
+		}).Times(1)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			return schema.AssistantMessage("final answer", nil), nil
+		}).Times(1)
+
+	multiAgent, err := New(ctx, &Config{
+		Model:              cm,
+		Instruction:        "route to me",
+		SubAgents:          []adk.Agent{worker},
+		WorkerDescriptions: map[string]string{"Worker": "custom description"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "supervisor", multiAgent.Name(ctx))
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: multiAgent}).Query(ctx, "hi")
+	var contents []string
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		if event.Output != nil && event.Output.MessageOutput.Role == schema.Assistant && event.Output.MessageOutput.Message.Content != "" {
+			contents = append(contents, event.AgentName+":"+event.Output.MessageOutput.Message.Content)
+		}
+	}
+	assert.Equal(t, []string{"Worker:worker result", "supervisor:final answer"}, contents)
+}
+
+func TestNewSupervisorDisableHandback(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	supervisorAgent := mockAdk.NewMockAgent(ctrl)
+	supervisorAgent.EXPECT().Name(gomock.Any()).Return("SupervisorAgent").AnyTimes()
+	worker := mockAdk.NewMockAgent(ctrl)
+	worker.EXPECT().Name(gomock.Any()).Return("Worker").AnyTimes()
+
+	aMsg, tMsg := adk.GenTransferMessages(ctx, "Worker")
+	i, g := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	g.Send(adk.EventFromMessage(aMsg, nil, schema.Assistant, ""))
+	event := adk.EventFromMessage(tMsg, nil, schema.Tool, tMsg.ToolName)
+	event.Action = &adk.AgentAction{TransferToAgent: &adk.TransferToAgentAction{DestAgentName: "Worker"}}
+	g.Send(event)
+	g.Close()
+	supervisorAgent.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(i).Times(1)
+
+	i, g = adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	g.Send(adk.EventFromMessage(schema.AssistantMessage("worker result", nil), nil, schema.Assistant, ""))
+	g.Close()
+	worker.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(i).Times(1)
+
+	multiAgent, err := New(ctx, &Config{
+		Supervisor:      supervisorAgent,
+		SubAgents:       []adk.Agent{worker},
+		DisableHandback: true,
+	})
+	assert.NoError(t, err)
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: multiAgent}).Query(ctx, "hi")
+	var last *adk.AgentEvent
+	for {
+		e, ok := iter.Next()
+		if !ok {
+			break
+		}
+		last = e
+	}
+	assert.Equal(t, "Worker", last.AgentName)
+	assert.Nil(t, last.Action)
+}
+
+func TestNewSupervisorDisableHandbackWithDescription(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	transferTo := func(name string) *schema.Message {
+		return schema.AssistantMessage("", []schema.ToolCall{{
+			ID:       "transfer_to_" + name,
+			Function: schema.FunctionCall{Name: adk.TransferToAgentToolName, Arguments: `{"agent_name":"` + name + `"}`},
+		}})
+	}
+
+	supervisorModel := mockModel.NewMockToolCallingChatModel(ctrl)
+	supervisorModel.EXPECT().WithTools(gomock.Any()).Return(supervisorModel, nil).AnyTimes()
+	supervisorModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			assert.Contains(t, input[0].Content, "custom description")
+			return transferTo("Worker"), nil
+		}).Times(1)
+	supervisorModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("final answer", nil), nil).Times(1)
+
+	// the worker can still transfer back to the supervisor with its description overridden
+	var workerTools []string
+	workerModel := mockModel.NewMockToolCallingChatModel(ctrl)
+	workerModel.EXPECT().WithTools(gomock.Any()).DoAndReturn(
+		func(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+			for _, tl := range tools {
+				workerTools = append(workerTools, tl.Name)
+			}
+			return workerModel, nil
+		}).AnyTimes()
+	workerModel.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).Return(transferTo("supervisor"), nil).Times(1)
+	worker, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "Worker",
+		Description: "original description",
+		Instruction: "do the work",
+		Model:       workerModel,
+	})
+	assert.NoError(t, err)
+
+	multiAgent, err := New(ctx, &Config{
+		Model:              supervisorModel,
+		SubAgents:          []adk.Agent{worker},
+		WorkerDescriptions: map[string]string{"Worker": "custom description"},
+		DisableHandback:    true,
+	})
+	assert.NoError(t, err)
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: multiAgent}).Query(ctx, "hi")
+	var transfers []string
+	var last *adk.AgentEvent
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		if event.Action != nil && event.Action.TransferToAgent != nil {
+			transfers = append(transfers, event.AgentName+"->"+event.Action.TransferToAgent.DestAgentName)
+		}
+		last = event
+	}
+	assert.Contains(t, workerTools, adk.TransferToAgentToolName)
+	assert.Equal(t, []string{"supervisor->Worker", "Worker->supervisor"}, transfers)
+	assert.Equal(t, "supervisor", last.AgentName)
+	assert.Equal(t, "final answer", last.Output.MessageOutput.Message.Content)
+}
+
+func TestNewSupervisorWithCapabilityRegistry(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	worker := mockAdk.NewMockAgent(ctrl)
+	worker.EXPECT().Name(gomock.Any()).Return("Worker").AnyTimes()
+	worker.EXPECT().Description(gomock.Any()).Return("worker description").AnyTimes()
+	i, g := adk.NewAsyncIteratorPair[*adk.AgentEvent]()
+	g.Send(adk.EventFromMessage(schema.AssistantMessage("worker result", nil), nil, schema.Assistant, ""))
+	g.Close()
+	worker.EXPECT().Run(gomock.Any(), gomock.Any(), gomock.Any()).Return(i).Times(1)
+
+	registry := adk.NewCapabilityRegistry()
+	assert.NoError(t, registry.Register(ctx, worker, &adk.Capability{Name: "refund", Description: "refund an order", Tags: []string{"billing"}}))
+	other := mockAdk.NewMockAgent(ctrl)
+	other.EXPECT().Name(gomock.Any()).Return("Other").AnyTimes()
+	other.EXPECT().Description(gomock.Any()).Return("not a sub-agent").AnyTimes()
+	assert.NoError(t, registry.Register(ctx, other))
+
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			assert.Contains(t, input[0].Content, "- refund: refund an order")
+			assert.NotContains(t, input[0].Content, "not a sub-agent")
+			return schema.AssistantMessage("", []schema.ToolCall{{
+				ID:       "1",
+				Function: schema.FunctionCall{Name: adk.TransferToAgentToolName, Arguments: `{"agent_name":"Worker"}`},
+			}}), nil
+		}).Times(1)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("final answer", nil), nil).Times(1)
+
+	multiAgent, err := New(ctx, &Config{
+		Model:              cm,
+		SubAgents:          []adk.Agent{worker},
+		CapabilityRegistry: registry,
+	})
+	assert.NoError(t, err)
+
+	iter := adk.NewRunner(ctx, adk.RunnerConfig{Agent: multiAgent}).Query(ctx, "refund my order")
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+}
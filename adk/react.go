@@ -43,6 +43,31 @@ type State struct {
 	RemainingIterations int
 }
 
+type reactFinishKey struct{}
+
+// reactFinish holds the reason when the react loop is stopped before the model stops calling tools.
+type reactFinish struct {
+	reason *FinishReason
+}
+
+func withReactFinish(ctx context.Context) (context.Context, *reactFinish) {
+	f := &reactFinish{}
+	return context.WithValue(ctx, reactFinishKey{}, f), f
+}
+
+func setReactFinishReason(ctx context.Context, reason *FinishReason) {
+	if f, ok := ctx.Value(reactFinishKey{}).(*reactFinish); ok {
+		f.reason = reason
+	}
+}
+
+func getReactFinishReason(ctx context.Context) *FinishReason {
+	if f, ok := ctx.Value(reactFinishKey{}).(*reactFinish); ok {
+		return f.reason
+	}
+	return nil
+}
+
 type agentToolInterruptInfo struct {
 	LastEvent *AgentEvent
 	Data      []byte
@@ -83,9 +108,14 @@ type reactConfig struct {
 
 	agentName string
 
-	maxIterations int
+	maxIterations         int
+	finishAtMaxIterations bool
+	shouldTerminate       func(ctx context.Context, state *ChatModelAgentState) (bool, error)
 
 	beforeChatModel, afterChatModel []func(context.Context, *ChatModelAgentState) error
+	modelInputRewriters             []ModelInputRewriter
+
+	guardrails []Guardrail
 }
 
 func genToolInfos(ctx context.Context, config *compose.ToolsNodeConfig) ([]*schema.ToolInfo, error) {
@@ -172,7 +202,7 @@ func newReact(ctx context.Context, config *reactConfig) (reactGraph, error) {
 		}
 		st.Messages = s.Messages
 
-		return st.Messages, nil
+		return rewriteModelInput(ctx, config.modelInputRewriters, st.Messages)
 	}
 	modelPostHandle := func(ctx context.Context, input Message, st *State) (Message, error) {
 		s := &ChatModelAgentState{Messages: append(st.Messages, input)}
@@ -183,9 +213,21 @@ func newReact(ctx context.Context, config *reactConfig) (reactGraph, error) {
 			}
 		}
 		st.Messages = s.Messages
+
+		if len(input.ToolCalls) > 0 && config.finishAtMaxIterations && st.RemainingIterations <= 0 {
+			setReactFinishReason(ctx, &FinishReason{Status: FinishStatusIncomplete, Reason: "max_steps"})
+		} else if config.shouldTerminate != nil {
+			terminate, err := config.shouldTerminate(ctx, s)
+			if err != nil {
+				return nil, err
+			}
+			if terminate {
+				setReactFinishReason(ctx, &FinishReason{Status: FinishStatusCompleted, Reason: "terminated"})
+			}
+		}
 		return input, nil
 	}
-	_ = g.AddChatModelNode(chatModel_, chatModel,
+	_ = g.AddChatModelNode(chatModel_, model.NewGuardedChatModel(chatModel, config.guardrails...),
 		compose.WithStatePreHandler(modelPreHandle), compose.WithStatePostHandler(modelPostHandle), compose.WithNodeName(chatModel_))
 
 	toolPreHandle := func(ctx context.Context, input Message, st *State) (Message, error) {
@@ -210,6 +252,9 @@ func newReact(ctx context.Context, config *reactConfig) (reactGraph, error) {
 
 	toolCallCheck := func(ctx context.Context, sMsg MessageStream) (string, error) {
 		defer sMsg.Close()
+		if getReactFinishReason(ctx) != nil {
+			return compose.END, nil
+		}
 		for {
 			chunk, err_ := sMsg.Recv()
 			if err_ != nil {
@@ -0,0 +1,255 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+func init() {
+	schema.RegisterName[*RunBudgetExceeded]("_eino_adk_run_budget_exceeded")
+}
+
+// RunBudget is the budget of a run of the Runner, zero value of each field means no limit.
+// The budget is enforced before the tool calls of the ChatModelAgents only, the model calls are never stopped by it,
+// so a run without tool calls, e.g. an agent answering directly, runs to the end even if MaxTotalTokens or MaxCost is exceeded.
+type RunBudget struct {
+	// MaxTotalTokens limits the total tokens of all the model calls in the run, including the ones of nested agents.
+	MaxTotalTokens int
+	// MaxCost limits the estimated cost of all the model calls in the run, computed by RunnerConfig.PriceTable.
+	// Model calls without a price aren't counted.
+	MaxCost float64
+	// MaxToolCalls limits the number of tool calls in the run.
+	MaxToolCalls int
+}
+
+// RunBudgetExceeded is the payload of the interrupt raised when the RunBudget is exceeded.
+type RunBudgetExceeded struct {
+	ToolCallID string
+	ToolName   string
+	// Usage is the snapshot of the usage of the run when the budget was exceeded.
+	Usage     *RunUsage
+	ToolCalls int
+	// Exceeded lists which limits have been exceeded, the values could be "tokens", "cost" and "tool_calls",
+	// or "quota" for the exhausted quota of WithBudgetQuota.
+	Exceeded []string
+}
+
+func (r *RunBudgetExceeded) String() string {
+	return fmt.Sprintf("run budget exceeded: %v, total tokens: %d, cost: %f, tool calls: %d",
+		r.Exceeded, r.Usage.TotalTokens, r.Usage.Cost, r.ToolCalls)
+}
+
+// WithRunBudget caps the run of the Runner by total tokens, estimated cost or number of tool calls.
+// The budget is checked before each tool call of the ChatModelAgents in the run, once exceeded,
+// the agent interrupts with a RunBudgetExceeded, which can be got by GetRunBudgetExceeded.
+// The run can be continued by Runner.Resume, which requires the Runner to be configured with a CheckPointStore,
+// or aborted by not resuming it. The usage is counted from zero again in the resumed run,
+// so pass WithRunBudget again to grant a new budget, or leave it out to continue without limit.
+// Notice: usage of a stream output is collected when the stream is finished, so it may take effect later.
+// e.g.
+//
+//	iter := runner.Query(ctx, query, adk.WithCheckPointID("1"), adk.WithRunBudget(&adk.RunBudget{MaxTotalTokens: 10000}))
+//	// ...
+//	if exceeded := adk.GetRunBudgetExceeded(event.Action.Interrupted); len(exceeded) > 0 && approve(exceeded[0]) {
+//		iter, err = runner.Resume(ctx, "1", adk.WithRunBudget(&adk.RunBudget{MaxTotalTokens: 10000}))
+//	}
+func WithRunBudget(budget *RunBudget) AgentRunOption {
+	return WrapImplSpecificOptFn(func(o *options) {
+		o.budget = budget
+	})
+}
+
+// WithBudgetQuota charges the usage of the run to the key of the model.BudgetManager, e.g. the one created by budget.NewManager,
+// which tracks the usage across runs, e.g. per user or per tenant. The run is rejected with a *model.BudgetExhaustedError if the quota of the key has been exhausted,
+// otherwise the tokens and the cost of all the model calls in the run are charged to the key, the cost being estimated by
+// RunnerConfig.PriceTable or reported by the models wrapped by NewCostTrackingModel. Failures of charging are ignored.
+// Once the quota is exhausted in the run, the ChatModelAgents interrupt before the next tool calls with a RunBudgetExceeded,
+// whose Exceeded is ["quota"], which can be handled like the exceeded RunBudget.
+// e.g.
+//
+//	iter := runner.Query(ctx, query, adk.WithCheckPointID("1"), adk.WithBudgetQuota(mgr, tenantID))
+func WithBudgetQuota(manager model.BudgetManager, key string) AgentRunOption {
+	return WrapImplSpecificOptFn(func(o *options) {
+		o.budgetQuota = &budgetQuota{manager: manager, key: key}
+	})
+}
+
+// GetRunBudgetExceeded returns the RunBudgetExceeded payloads within the interrupt info, sorted by tool call ID.
+// There may be more than one payload if the model calls several tools at once.
+func GetRunBudgetExceeded(info *InterruptInfo) []*RunBudgetExceeded {
+	return getToolInterruptExtras(info, func(a, b *RunBudgetExceeded) bool {
+		return a.ToolCallID < b.ToolCallID
+	})
+}
+
+type runBudgetTracker struct {
+	budget *RunBudget
+	ut     *usageTracker
+
+	mu        sync.Mutex
+	toolCalls int
+}
+
+type runBudgetTrackerKey struct{}
+
+type budgetQuota struct {
+	manager model.BudgetManager
+	key     string
+	ut      *usageTracker
+}
+
+type budgetQuotaKey struct{}
+
+// withBudgetQuota rejects the run if the quota has been exhausted, otherwise puts the quota into ctx,
+// and charges the usage collected by the usage tracker of the run, which is created if absent.
+func withBudgetQuota(ctx context.Context, q *budgetQuota, ut *usageTracker, priceTable PriceTable) (context.Context, error) {
+	if q == nil || q.manager == nil {
+		return ctx, nil
+	}
+	if err := q.manager.Check(ctx, q.key); err != nil {
+		return ctx, err
+	}
+	if ut == nil {
+		ut = newUsageTracker(priceTable)
+		ctx = ut.withHandler(ctx)
+	}
+	ut.onAdd = func(ctx context.Context, usage *model.TokenUsage, cost float64) {
+		_, _ = q.manager.Charge(ctx, q.key, model.BudgetUsage{Tokens: int64(usage.TotalTokens), Cost: cost})
+	}
+	return context.WithValue(ctx, budgetQuotaKey{}, &budgetQuota{manager: q.manager, key: q.key, ut: ut}), nil
+}
+
+func getBudgetQuota(ctx context.Context) *budgetQuota {
+	q, _ := ctx.Value(budgetQuotaKey{}).(*budgetQuota)
+	return q
+}
+
+// check returns the usage of the run if the quota has been exhausted.
+func (q *budgetQuota) check(ctx context.Context) (*RunBudgetExceeded, error) {
+	err := q.manager.Check(ctx, q.key)
+	if err == nil {
+		return nil, nil
+	}
+	if !errors.Is(err, model.ErrBudgetExhausted) {
+		return nil, err
+	}
+	exceeded := &RunBudgetExceeded{Usage: q.ut.snapshot(), Exceeded: []string{"quota"}}
+	if t := getRunBudgetTracker(ctx); t != nil {
+		t.mu.Lock()
+		exceeded.ToolCalls = t.toolCalls
+		t.mu.Unlock()
+	}
+	return exceeded, nil
+}
+
+// withRunBudget puts the tracker of the budget into ctx, reusing the usage tracker of the run if any.
+func withRunBudget(ctx context.Context, budget *RunBudget, ut *usageTracker, priceTable PriceTable) context.Context {
+	if budget == nil {
+		return ctx
+	}
+	if ut == nil {
+		ut = newUsageTracker(priceTable)
+		ctx = ut.withHandler(ctx)
+	}
+	return context.WithValue(ctx, runBudgetTrackerKey{}, &runBudgetTracker{budget: budget, ut: ut})
+}
+
+func getRunBudgetTracker(ctx context.Context) *runBudgetTracker {
+	t, _ := ctx.Value(runBudgetTrackerKey{}).(*runBudgetTracker)
+	return t
+}
+
+// checkToolCall counts the tool call if the budget allows, otherwise returns the usage.
+func (t *runBudgetTracker) checkToolCall() *RunBudgetExceeded {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := t.ut.snapshot()
+	var exceeded []string
+	if t.budget.MaxTotalTokens > 0 && usage.TotalTokens > t.budget.MaxTotalTokens {
+		exceeded = append(exceeded, "tokens")
+	}
+	if t.budget.MaxCost > 0 && usage.Cost > t.budget.MaxCost {
+		exceeded = append(exceeded, "cost")
+	}
+	if t.budget.MaxToolCalls > 0 && t.toolCalls >= t.budget.MaxToolCalls {
+		exceeded = append(exceeded, "tool_calls")
+	}
+	if len(exceeded) > 0 {
+		return &RunBudgetExceeded{Usage: usage, ToolCalls: t.toolCalls, Exceeded: exceeded}
+	}
+
+	t.toolCalls++
+	return nil
+}
+
+// runBudgetToolMiddleware interrupts the tool calls once the budget of the run is exceeded.
+func runBudgetToolMiddleware() compose.ToolMiddleware {
+	check := func(ctx context.Context, input *compose.ToolInput) error {
+		t := getRunBudgetTracker(ctx)
+		if t == nil || input.Name == TransferToAgentToolName {
+			return nil
+		}
+		if exceeded := t.checkToolCall(); exceeded != nil {
+			exceeded.ToolCallID = input.CallID
+			exceeded.ToolName = input.Name
+			return compose.NewInterruptAndRerunErr(exceeded)
+		}
+		return nil
+	}
+	// checkQuota checks the quota of the key across runs before the budget of the run
+	checkQuota := func(ctx context.Context, input *compose.ToolInput) error {
+		if q := getBudgetQuota(ctx); q != nil && input.Name != TransferToAgentToolName {
+			exceeded, err := q.check(ctx)
+			if err != nil {
+				return err
+			}
+			if exceeded != nil {
+				exceeded.ToolCallID = input.CallID
+				exceeded.ToolName = input.Name
+				return compose.NewInterruptAndRerunErr(exceeded)
+			}
+		}
+		return check(ctx, input)
+	}
+	return compose.ToolMiddleware{
+		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+				if err := checkQuota(ctx, input); err != nil {
+					return nil, err
+				}
+				return next(ctx, input)
+			}
+		},
+		Streamable: func(next compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+				if err := checkQuota(ctx, input); err != nil {
+					return nil, err
+				}
+				return next(ctx, input)
+			}
+		},
+	}
+}
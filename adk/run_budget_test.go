@@ -0,0 +1,146 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/budget"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestRunBudgetTracker(t *testing.T) {
+	ctx := context.Background()
+	ut := newUsageTracker(StaticPriceTable{"m": {Prompt: 1e6}})
+	tracker := getRunBudgetTracker(withRunBudget(ctx, &RunBudget{MaxTotalTokens: 100, MaxCost: 50}, ut, nil))
+
+	assert.Nil(t, tracker.checkToolCall())
+	ut.add(context.WithValue(ctx, usageModelNameKey{}, "m"), &model.CallbackOutput{TokenUsage: &model.TokenUsage{PromptTokens: 60, TotalTokens: 120}})
+	exceeded := tracker.checkToolCall()
+	assert.Equal(t, []string{"tokens", "cost"}, exceeded.Exceeded)
+	assert.Equal(t, 120, exceeded.Usage.TotalTokens)
+	assert.Equal(t, 1, exceeded.ToolCalls)
+
+	assert.Nil(t, getRunBudgetTracker(withRunBudget(ctx, nil, ut, nil)))
+}
+
+func TestRunBudgetToolCalls(t *testing.T) {
+	ctx := context.Background()
+
+	m := &myModel{messages: []*schema.Message{
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"a"}`}}}),
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "2", Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"b"}`}}}),
+		schema.AssistantMessage("done", nil),
+	}}
+	ft := &fakeToolForTest{tarCount: 2}
+	a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "agent",
+		Description: "agent",
+		Model:       m,
+		ToolsConfig: ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{ft}}},
+	})
+	assert.NoError(t, err)
+
+	runner := NewRunner(ctx, RunnerConfig{Agent: a, CheckPointStore: newMyStore()})
+	iter := runner.Query(ctx, "hi", WithCheckPointID("1"), WithRunBudget(&RunBudget{MaxToolCalls: 1}))
+	var interrupted *InterruptInfo
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		if event.Action != nil && event.Action.Interrupted != nil {
+			interrupted = event.Action.Interrupted
+		}
+	}
+	assert.NotNil(t, interrupted)
+	assert.Equal(t, 1, ft.curCount)
+	exceeded := GetRunBudgetExceeded(interrupted)
+	assert.Len(t, exceeded, 1)
+	assert.Equal(t, "2", exceeded[0].ToolCallID)
+	assert.Equal(t, "test_tool", exceeded[0].ToolName)
+	assert.Equal(t, []string{"tool_calls"}, exceeded[0].Exceeded)
+	assert.Equal(t, 1, exceeded[0].ToolCalls)
+
+	// continue with a new budget
+	iter, err = runner.Resume(ctx, "1", WithRunBudget(&RunBudget{MaxToolCalls: 1}))
+	assert.NoError(t, err)
+	var outputs []string
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		assert.Nil(t, event.Action)
+		outputs = append(outputs, event.Output.MessageOutput.Message.Content)
+	}
+	assert.Equal(t, []string{`{"say": "hello b"}`, "done"}, outputs)
+	assert.Equal(t, 2, ft.curCount)
+}
+
+func TestBudgetQuota(t *testing.T) {
+	ctx := context.Background()
+	mgr, err := budget.NewManager(&budget.Config{
+		Quota: func(context.Context, string) (budget.Quota, bool) {
+			return budget.Quota{MaxTokens: 100}, true
+		},
+	})
+	assert.NoError(t, err)
+
+	qCtx, err := withBudgetQuota(ctx, &budgetQuota{manager: mgr, key: "user"}, nil, nil)
+	assert.NoError(t, err)
+	q := getBudgetQuota(qCtx)
+	exceeded, err := q.check(qCtx)
+	assert.NoError(t, err)
+	assert.Nil(t, exceeded)
+
+	q.ut.add(qCtx, &model.CallbackOutput{TokenUsage: &model.TokenUsage{TotalTokens: 120}})
+	u, err := mgr.Usage(ctx, "user")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(120), u.Tokens)
+	exceeded, err = q.check(qCtx)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"quota"}, exceeded.Exceeded)
+	assert.Equal(t, 120, exceeded.Usage.TotalTokens)
+
+	// the run is rejected once the quota is exhausted
+	a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "agent",
+		Description: "agent",
+		Model:       &myModel{messages: []*schema.Message{schema.AssistantMessage("done", nil)}},
+	})
+	assert.NoError(t, err)
+	runner := NewRunner(ctx, RunnerConfig{Agent: a})
+	iter := runner.Query(ctx, "hi", WithBudgetQuota(mgr, "user"))
+	event, ok := iter.Next()
+	assert.True(t, ok)
+	assert.ErrorIs(t, event.Err, budget.ErrExhausted)
+
+	iter = runner.Query(ctx, "hi", WithBudgetQuota(mgr, "another"))
+	event, ok = iter.Next()
+	assert.True(t, ok)
+	assert.NoError(t, event.Err)
+	assert.Equal(t, "done", event.Output.MessageOutput.Message.Content)
+}
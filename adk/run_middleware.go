@@ -0,0 +1,62 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import "context"
+
+// AgentRunFunc runs an agent, it has the same signature as Agent.Run.
+type AgentRunFunc func(ctx context.Context, input *AgentInput, opts ...AgentRunOption) *AsyncIterator[*AgentEvent]
+
+// AgentRunMiddleware wraps the run of an agent, e.g. for logging, caching, injecting auth into the context or rewriting the input and events.
+// It gets the input before next runs, and the events returned by next, which can be forwarded as they are or rewritten.
+// Events containing MessageStream must stay exclusive to the consumer, so copy the stream if it needs to be read in the middleware.
+// When the agent is resumed from an interrupt, the middleware runs with a nil input, which must be checked before use.
+// e.g.
+//
+//	logging := func(next adk.AgentRunFunc) adk.AgentRunFunc {
+//		return func(ctx context.Context, input *adk.AgentInput, opts ...adk.AgentRunOption) *adk.AsyncIterator[*adk.AgentEvent] {
+//			if input == nil {
+//				log.Printf("agent resumes")
+//			} else {
+//				log.Printf("agent starts with %d messages", len(input.Messages))
+//			}
+//			return next(ctx, input, opts...)
+//		}
+//	}
+type AgentRunMiddleware func(next AgentRunFunc) AgentRunFunc
+
+// WithRunMiddlewares wraps the run of the agent with middlewares, which works for any agent.
+// The first middleware is the outermost one: it gets the input first and the events last.
+// Middlewares of an agent also see the events of its sub-agents which are transferred to in the run.
+// Multiple calls of WithRunMiddlewares append the middlewares in order.
+// e.g.
+//
+//	agent = adk.AgentWithOptions(ctx, agent, adk.WithRunMiddlewares(logging, caching))
+func WithRunMiddlewares(middlewares ...AgentRunMiddleware) AgentOption {
+	return func(fa *flowAgent) {
+		mws := make([]AgentRunMiddleware, 0, len(fa.runMiddlewares)+len(middlewares))
+		mws = append(mws, fa.runMiddlewares...)
+		fa.runMiddlewares = append(mws, middlewares...)
+	}
+}
+
+func chainAgentRunMiddlewares(middlewares []AgentRunMiddleware, run AgentRunFunc) AgentRunFunc {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		run = middlewares[i](run)
+	}
+	return run
+}
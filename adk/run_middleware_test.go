@@ -0,0 +1,78 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestWithRunMiddlewares(t *testing.T) {
+	ctx := context.Background()
+
+	var trace []string
+	tracing := func(name string) AgentRunMiddleware {
+		return func(next AgentRunFunc) AgentRunFunc {
+			return func(ctx context.Context, input *AgentInput, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+				trace = append(trace, name+" input")
+				iter := next(ctx, input, opts...)
+				niter, gen := NewAsyncIteratorPair[*AgentEvent]()
+				go func() {
+					defer gen.Close()
+					for {
+						event, ok := iter.Next()
+						if !ok {
+							return
+						}
+						trace = append(trace, name+" event")
+						gen.Send(event)
+					}
+				}()
+				return niter
+			}
+		}
+	}
+	rewriting := func(next AgentRunFunc) AgentRunFunc {
+		return func(ctx context.Context, input *AgentInput, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			input.Messages = append(input.Messages, schema.UserMessage("be brief"))
+			return next(ctx, input, opts...)
+		}
+	}
+
+	agent := newMockRunnerAgent("agent", "agent", []*AgentEvent{EventFromMessage(schema.AssistantMessage("ok", nil), nil, schema.Assistant, "")})
+	wrapped := AgentWithOptions(ctx, agent, WithRunMiddlewares(tracing("outer")), WithRunMiddlewares(tracing("inner"), rewriting))
+
+	iter := NewRunner(ctx, RunnerConfig{Agent: wrapped}).Query(ctx, "hi")
+	var events []*AgentEvent
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		events = append(events, event)
+	}
+
+	assert.Len(t, events, 1)
+	assert.Equal(t, "agent", events[0].AgentName)
+	assert.Equal(t, []string{"outer input", "inner input", "inner event", "outer event"}, trace)
+	assert.Len(t, agent.lastInput.Messages, 2)
+	assert.Equal(t, "be brief", agent.lastInput.Messages[1].Content)
+}
@@ -0,0 +1,610 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RunStatus is the status of a persisted run, see compose.RunStatus.
+type RunStatus = compose.RunStatus
+
+const (
+	RunStatusRunning     = compose.RunStatusRunning
+	RunStatusInterrupted = compose.RunStatusInterrupted
+	RunStatusCompleted   = compose.RunStatusCompleted
+	RunStatusFailed      = compose.RunStatusFailed
+)
+
+// RunRecord is a persisted run.
+type RunRecord struct {
+	ID     string    `json:"id"`
+	Status RunStatus `json:"status"`
+	// Input is the input messages of the run, excluding the history loaded from the session.
+	Input []Message `json:"input,omitempty"`
+	// CheckPointID is the checkpoint designated by WithCheckPointID, used to resume an interrupted run.
+	CheckPointID string `json:"check_point_id,omitempty"`
+	// Session is the session designated by WithSession.
+	Session *SessionScope `json:"session,omitempty"`
+	// Output is the last message of the run, i.e. the final answer if the run has completed.
+	Output Message `json:"output,omitempty"`
+	// Err is the error message of the last event if the run has failed.
+	Err string `json:"err,omitempty"`
+	// Interrupts is the number of times the run has been interrupted.
+	Interrupts int `json:"interrupts,omitempty"`
+	// Resumes is the number of times the run has been continued by Runner.Resume or Runner.ResumeRun.
+	Resumes   int       `json:"resumes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RunEventRecord is a persisted AgentEvent.
+// Streamed messages are concatenated before being persisted.
+type RunEventRecord struct {
+	// Seq is the sequence of the event in the run, starting from 0.
+	Seq       int      `json:"seq"`
+	AgentName string   `json:"agent_name,omitempty"`
+	RunPath   []string `json:"run_path,omitempty"`
+
+	Message  Message         `json:"message,omitempty"`
+	Role     schema.RoleType `json:"role,omitempty"`
+	ToolName string          `json:"tool_name,omitempty"`
+
+	Exit            bool   `json:"exit,omitempty"`
+	TransferToAgent string `json:"transfer_to_agent,omitempty"`
+	Interrupted     bool   `json:"interrupted,omitempty"`
+
+	// Err is the error message of the event, empty if the event carries no error.
+	Err       string    `json:"err,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RunStore persists runs and their events as they happen, so a run can be audited,
+// and resumed by Runner.ResumeRun after the process restarts.
+// When set to RunnerConfig, Runner persists the runs designated by WithRunID automatically.
+type RunStore interface {
+	// SaveRun creates or replaces the run.
+	SaveRun(ctx context.Context, run *RunRecord) error
+	GetRun(ctx context.Context, runID string) (*RunRecord, bool, error)
+	AppendEvent(ctx context.Context, runID string, event *RunEventRecord) error
+	// ListEvents returns the events of the run in the order they are appended.
+	ListEvents(ctx context.Context, runID string) ([]*RunEventRecord, error)
+	// ListRuns returns the runs matching the query, the latest created first.
+	ListRuns(ctx context.Context, query *RunQuery) ([]*RunRecord, error)
+}
+
+// RunQuery filters the runs of RunStore.ListRuns, zero value of each field matches all the runs.
+type RunQuery struct {
+	// Statuses matches the runs in any of the statuses.
+	Statuses []RunStatus
+	// UserID matches the runs of the sessions of the user.
+	UserID string
+	// CreatedAfter and CreatedBefore match the runs created in the time range.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Limit is the max number of the runs returned.
+	Limit int
+}
+
+// Match reports whether the run matches the query, for RunStore implementations.
+func (q *RunQuery) Match(run *RunRecord) bool {
+	if q == nil {
+		return true
+	}
+	if len(q.Statuses) > 0 {
+		matched := false
+		for _, s := range q.Statuses {
+			if s == run.Status {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if q.UserID != "" && (run.Session == nil || run.Session.UserID != q.UserID) {
+		return false
+	}
+	if !q.CreatedAfter.IsZero() && run.CreatedAt.Before(q.CreatedAfter) {
+		return false
+	}
+	if !q.CreatedBefore.IsZero() && !run.CreatedAt.Before(q.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// filterRuns returns the runs matching the query, the latest created first.
+func filterRuns(runs []*RunRecord, query *RunQuery) []*RunRecord {
+	matched := make([]*RunRecord, 0, len(runs))
+	for _, run := range runs {
+		if query.Match(run) {
+			matched = append(matched, run)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		return matched[i].CreatedAt.After(matched[j].CreatedAt)
+	})
+	if query != nil && query.Limit > 0 && len(matched) > query.Limit {
+		matched = matched[:query.Limit]
+	}
+	return matched
+}
+
+// WithRunID designates the id of the run, which takes effect when Runner is configured with a RunStore.
+// Runs with the same id are persisted as one run, so the id should be unique for each Runner.Run.
+func WithRunID(runID string) AgentRunOption {
+	return WrapImplSpecificOptFn(func(o *options) {
+		o.runID = &runID
+	})
+}
+
+// ResumeRun continues a persisted run, e.g. after the process restarted.
+// An interrupted run is resumed from its checkpoint, which requires the CheckPointStore.
+// A run left in flight is run again with its input followed by the messages persisted so far,
+// tool calls without persisted results are dropped and called again.
+// The following events are appended to the same run.
+func (r *Runner) ResumeRun(ctx context.Context, runID string, opts ...AgentRunOption) (*AsyncIterator[*AgentEvent], error) {
+	if r.runStore == nil {
+		return nil, fmt.Errorf("failed to resume run: run store is nil")
+	}
+
+	run, existed, err := r.runStore.GetRun(ctx, runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run: %w", err)
+	}
+	if !existed {
+		return nil, fmt.Errorf("run[%s] is not existed", runID)
+	}
+
+	opts = append(opts, WithRunID(runID))
+	if run.Session != nil {
+		opts = append(opts, WithSession(run.Session.UserID, run.Session.SessionID))
+	}
+
+	switch run.Status {
+	case RunStatusInterrupted:
+		if run.CheckPointID == "" {
+			return nil, fmt.Errorf("run[%s] is interrupted without checkpoint", runID)
+		}
+		return r.Resume(ctx, run.CheckPointID, opts...)
+	case RunStatusRunning:
+		events, err := r.runStore.ListEvents(ctx, runID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list run events: %w", err)
+		}
+		messages := make([]Message, 0, len(run.Input)+len(events))
+		messages = append(messages, run.Input...)
+		for _, e := range events {
+			if e.Message != nil {
+				messages = append(messages, e.Message)
+			}
+		}
+		if run.CheckPointID != "" {
+			opts = append(opts, WithCheckPointID(run.CheckPointID))
+		}
+		opts = append(opts, WrapImplSpecificOptFn(func(o *options) {
+			o.resumeRun = true
+		}))
+		return r.Run(ctx, messages, opts...), nil
+	default:
+		return nil, fmt.Errorf("run[%s] has finished with status: %s", runID, run.Status)
+	}
+}
+
+// trimUnansweredToolCalls drops the messages starting from the first assistant message
+// whose tool calls are not all answered, so the agent can call the tools again.
+func trimUnansweredToolCalls(messages []Message) []Message {
+	answered := make(map[string]bool)
+	for _, m := range messages {
+		if m.Role == schema.Tool {
+			answered[m.ToolCallID] = true
+		}
+	}
+	for i, m := range messages {
+		if m.Role != schema.Assistant {
+			continue
+		}
+		for _, tc := range m.ToolCalls {
+			if !answered[tc.ID] {
+				return messages[:i]
+			}
+		}
+	}
+	return messages
+}
+
+// runRecorder persists the run and its events to the RunStore.
+type runRecorder struct {
+	store RunStore
+	run   *RunRecord
+
+	mu  sync.Mutex
+	seq int
+}
+
+func newRunRecorder(ctx context.Context, store RunStore, input []Message, o *options) (*runRecorder, error) {
+	if store == nil || o.runID == nil {
+		return nil, nil
+	}
+
+	run, existed, err := store.GetRun(ctx, *o.runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run: %w", err)
+	}
+
+	seq := 0
+	if existed {
+		events, err := store.ListEvents(ctx, run.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list run events: %w", err)
+		}
+		if len(events) > 0 {
+			seq = events[len(events)-1].Seq + 1
+		}
+		run.Status = RunStatusRunning
+		run.Err = ""
+		run.Resumes++
+		run.UpdatedAt = time.Now()
+	} else {
+		now := time.Now()
+		run = &RunRecord{
+			ID:        *o.runID,
+			Status:    RunStatusRunning,
+			Input:     input,
+			Session:   o.session,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	}
+	if o.checkPointID != nil {
+		run.CheckPointID = *o.checkPointID
+	}
+
+	if err = store.SaveRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to save run: %w", err)
+	}
+
+	return &runRecorder{store: store, run: run, seq: seq}, nil
+}
+
+// record persists the event, the MessageStream of the event is copied so that the caller can still receive it.
+// The returned function blocks until the event is persisted, it should be called after the event is sent.
+func (r *runRecorder) record(ctx context.Context, event *AgentEvent) func() error {
+	rec := &RunEventRecord{
+		AgentName: event.AgentName,
+		RunPath:   make([]string, 0, len(event.RunPath)),
+	}
+	for _, step := range event.RunPath {
+		rec.RunPath = append(rec.RunPath, step.agentName)
+	}
+	if event.Action != nil {
+		rec.Exit = event.Action.Exit
+		rec.Interrupted = event.Action.Interrupted != nil
+		if event.Action.TransferToAgent != nil {
+			rec.TransferToAgent = event.Action.TransferToAgent.DestAgentName
+		}
+	}
+	if event.Err != nil {
+		rec.Err = event.Err.Error()
+	}
+
+	var stream MessageStream
+	if event.Output != nil && event.Output.MessageOutput != nil {
+		mv := event.Output.MessageOutput
+		rec.Role = mv.Role
+		rec.ToolName = mv.ToolName
+		if mv.IsStreaming {
+			ss := mv.MessageStream.Copy(2)
+			mv.MessageStream = ss[0]
+			stream = ss[1]
+		} else {
+			rec.Message = mv.Message
+		}
+	}
+
+	return func() error {
+		if stream != nil {
+			msg, err := schema.ConcatMessageStream(stream)
+			if err != nil {
+				rec.Err = err.Error()
+			}
+			rec.Message = msg
+		}
+		return r.append(ctx, rec)
+	}
+}
+
+func (r *runRecorder) append(ctx context.Context, rec *RunEventRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rec.Seq = r.seq
+	rec.CreatedAt = time.Now()
+	if err := r.store.AppendEvent(ctx, r.run.ID, rec); err != nil {
+		return fmt.Errorf("failed to append run event: %w", err)
+	}
+	r.seq++
+	if rec.Message != nil {
+		r.run.Output = rec.Message
+	}
+	return nil
+}
+
+// finish persists the final status of the run, with the error of the last event if failed.
+func (r *runRecorder) finish(ctx context.Context, status RunStatus, lastErr error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.run.Status = status
+	switch status {
+	case RunStatusInterrupted:
+		r.run.Interrupts++
+	case RunStatusFailed:
+		r.run.Err = lastErr.Error()
+	}
+	r.run.UpdatedAt = time.Now()
+	if err := r.store.SaveRun(ctx, r.run); err != nil {
+		return fmt.Errorf("failed to save run: %w", err)
+	}
+	return nil
+}
+
+// NewInMemoryRunStore creates a RunStore which keeps runs in memory, mostly for tests and demos.
+func NewInMemoryRunStore() RunStore {
+	return &inMemoryRunStore{
+		runs:   make(map[string]*RunRecord),
+		events: make(map[string][]*RunEventRecord),
+	}
+}
+
+type inMemoryRunStore struct {
+	mu     sync.RWMutex
+	runs   map[string]*RunRecord
+	events map[string][]*RunEventRecord
+}
+
+func (s *inMemoryRunStore) SaveRun(_ context.Context, run *RunRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cp := *run
+	s.runs[run.ID] = &cp
+	return nil
+}
+
+func (s *inMemoryRunStore) GetRun(_ context.Context, runID string) (*RunRecord, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	run, ok := s.runs[runID]
+	if !ok {
+		return nil, false, nil
+	}
+	cp := *run
+	return &cp, true, nil
+}
+
+func (s *inMemoryRunStore) AppendEvent(_ context.Context, runID string, event *RunEventRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.runs[runID]; !ok {
+		return fmt.Errorf("run[%s] not found", runID)
+	}
+	s.events[runID] = append(s.events[runID], event)
+	return nil
+}
+
+func (s *inMemoryRunStore) ListRuns(_ context.Context, query *RunQuery) ([]*RunRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runs := make([]*RunRecord, 0, len(s.runs))
+	for _, run := range s.runs {
+		cp := *run
+		runs = append(runs, &cp)
+	}
+	return filterRuns(runs, query), nil
+}
+
+func (s *inMemoryRunStore) ListEvents(_ context.Context, runID string) ([]*RunEventRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, ok := s.runs[runID]; !ok {
+		return nil, fmt.Errorf("run[%s] not found", runID)
+	}
+	events := make([]*RunEventRecord, len(s.events[runID]))
+	copy(events, s.events[runID])
+	return events, nil
+}
+
+// NewFileRunStore creates a RunStore which stores runs in the directory,
+// one sub directory per run at <dir>/<run>, with the events appended to a JSON lines file.
+// It's a reference implementation for single process usage.
+func NewFileRunStore(dir string) RunStore {
+	return &fileRunStore{dir: dir}
+}
+
+const (
+	runFileName       = "run.json"
+	runEventsFileName = "events.jsonl"
+)
+
+type fileRunStore struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func (f *fileRunStore) runDir(runID string) string {
+	return filepath.Join(f.dir, url.PathEscape(runID))
+}
+
+func (f *fileRunStore) SaveRun(_ context.Context, run *RunRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dir := f.runDir(run.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create run dir: %w", err)
+	}
+	data, err := json.Marshal(run)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, runFileName), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write run file: %w", err)
+	}
+	return nil
+}
+
+func (f *fileRunStore) GetRun(_ context.Context, runID string) (*RunRecord, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.readRun(runID)
+}
+
+func (f *fileRunStore) readRun(runID string) (*RunRecord, bool, error) {
+	data, err := os.ReadFile(filepath.Join(f.runDir(runID), runFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read run file: %w", err)
+	}
+	run := &RunRecord{}
+	if err = json.Unmarshal(data, run); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal run: %w", err)
+	}
+	return run, true, nil
+}
+
+func (f *fileRunStore) AppendEvent(_ context.Context, runID string, event *RunEventRecord) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok, err := f.readRun(runID); err != nil {
+		return err
+	} else if !ok {
+		return fmt.Errorf("run[%s] not found", runID)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal run event: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(f.runDir(runID), runEventsFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open run events file: %w", err)
+	}
+	_, err = file.Write(append(data, '\n'))
+	if cErr := file.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write run event: %w", err)
+	}
+	return nil
+}
+
+func (f *fileRunStore) ListRuns(_ context.Context, query *RunQuery) ([]*RunRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read run store dir: %w", err)
+	}
+	runs := make([]*RunRecord, 0, len(entries))
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		runID, err := url.PathUnescape(entry.Name())
+		if err != nil {
+			continue
+		}
+		run, ok, err := f.readRun(runID)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			runs = append(runs, run)
+		}
+	}
+	return filterRuns(runs, query), nil
+}
+
+func (f *fileRunStore) ListEvents(_ context.Context, runID string) ([]*RunEventRecord, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, ok, err := f.readRun(runID); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("run[%s] not found", runID)
+	}
+
+	data, err := os.ReadFile(filepath.Join(f.runDir(runID), runEventsFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read run events file: %w", err)
+	}
+
+	var events []*RunEventRecord
+	reader := bufio.NewReader(bytes.NewReader(data))
+	for {
+		line, rErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			event := &RunEventRecord{}
+			if err = json.Unmarshal(line, event); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal run event: %w", err)
+			}
+			events = append(events, event)
+		}
+		if rErr == io.EOF {
+			break
+		}
+		if rErr != nil {
+			return nil, fmt.Errorf("failed to read run events file: %w", rErr)
+		}
+	}
+	return events, nil
+}
@@ -0,0 +1,209 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestRunnerRunStore(t *testing.T) {
+	ctx := context.Background()
+
+	for name, store := range map[string]RunStore{
+		"in_memory": NewInMemoryRunStore(),
+		"file":      NewFileRunStore(t.TempDir()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			m := &myModel{messages: []*schema.Message{
+				schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"a"}`}}}),
+				schema.AssistantMessage("done", nil),
+			}}
+			a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+				Name:        "agent",
+				Description: "agent",
+				Model:       m,
+				ToolsConfig: ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolForTest{tarCount: 1}}}},
+			})
+			assert.NoError(t, err)
+
+			runner := NewRunner(ctx, RunnerConfig{Agent: a, RunStore: store})
+			iter := runner.Query(ctx, "hi", WithRunID("run1"))
+			for {
+				event, ok := iter.Next()
+				if !ok {
+					break
+				}
+				assert.NoError(t, event.Err)
+			}
+
+			run, existed, err := store.GetRun(ctx, "run1")
+			assert.NoError(t, err)
+			assert.True(t, existed)
+			assert.Equal(t, RunStatusCompleted, run.Status)
+			assert.Len(t, run.Input, 1)
+			assert.Equal(t, "hi", run.Input[0].Content)
+
+			events, err := store.ListEvents(ctx, "run1")
+			assert.NoError(t, err)
+			assert.Len(t, events, 3)
+			for i, e := range events {
+				assert.Equal(t, i, e.Seq)
+				assert.Equal(t, "agent", e.AgentName)
+				assert.Equal(t, []string{"agent"}, e.RunPath)
+			}
+			assert.Equal(t, schema.Tool, events[1].Role)
+			assert.Equal(t, "test_tool", events[1].ToolName)
+			assert.Equal(t, "done", events[2].Message.Content)
+			assert.Equal(t, "done", run.Output.Content)
+
+			_, err = runner.ResumeRun(ctx, "run1")
+			assert.Error(t, err)
+
+			assert.NoError(t, store.SaveRun(ctx, &RunRecord{ID: "run0", Status: RunStatusFailed, CreatedAt: run.CreatedAt.Add(-time.Second)}))
+			runs, err := store.ListRuns(ctx, nil)
+			assert.NoError(t, err)
+			assert.Len(t, runs, 2)
+			assert.Equal(t, "run1", runs[0].ID)
+			runs, err = store.ListRuns(ctx, &RunQuery{Statuses: []RunStatus{RunStatusFailed}})
+			assert.NoError(t, err)
+			assert.Len(t, runs, 1)
+			assert.Equal(t, "run0", runs[0].ID)
+			runs, err = store.ListRuns(ctx, &RunQuery{CreatedAfter: run.CreatedAt.Add(-time.Millisecond), Limit: 1})
+			assert.NoError(t, err)
+			assert.Len(t, runs, 1)
+			assert.Equal(t, "run1", runs[0].ID)
+		})
+	}
+}
+
+func TestRunnerResumeRun(t *testing.T) {
+	ctx := context.Background()
+	store := NewFileRunStore(t.TempDir())
+
+	// a run left in flight by a previous process, the second tool call has no result yet
+	assert.NoError(t, store.SaveRun(ctx, &RunRecord{ID: "run1", Status: RunStatusRunning, Input: []Message{schema.UserMessage("hi")}}))
+	for i, msg := range []Message{
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"a"}`}}}),
+		schema.ToolMessage("ok", "1"),
+		schema.AssistantMessage("", []schema.ToolCall{{ID: "2", Function: schema.FunctionCall{Name: "test_tool", Arguments: `{"name":"b"}`}}}),
+	} {
+		assert.NoError(t, store.AppendEvent(ctx, "run1", &RunEventRecord{Seq: i, AgentName: "agent", Message: msg}))
+	}
+
+	m := &myModel{
+		messages: []*schema.Message{schema.AssistantMessage("done", nil)},
+		validator: func(_ int, input []*schema.Message) bool {
+			// instruction is empty, so the input is the input of the run followed by the answered tool call
+			return len(input) == 3 && input[0].Content == "hi" && input[2].ToolCallID == "1"
+		},
+	}
+	a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+		Name:        "agent",
+		Description: "agent",
+		Model:       m,
+		ToolsConfig: ToolsConfig{ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{&fakeToolForTest{tarCount: 1}}}},
+	})
+	assert.NoError(t, err)
+
+	runner := NewRunner(ctx, RunnerConfig{Agent: a, RunStore: store})
+	_, err = runner.ResumeRun(ctx, "unknown")
+	assert.Error(t, err)
+
+	iter, err := runner.ResumeRun(ctx, "run1")
+	assert.NoError(t, err)
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+	}
+
+	run, _, err := store.GetRun(ctx, "run1")
+	assert.NoError(t, err)
+	assert.Equal(t, RunStatusCompleted, run.Status)
+	assert.Len(t, run.Input, 1)
+	events, err := store.ListEvents(ctx, "run1")
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+	assert.Equal(t, 3, events[3].Seq)
+	assert.Equal(t, "done", events[3].Message.Content)
+}
+
+func TestRunnerResumeInterruptedRun(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryRunStore()
+
+	a := &myAgent{
+		runner: func(ctx context.Context, input *AgentInput, options ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			gen.Send(&AgentEvent{Action: &AgentAction{Interrupted: &InterruptInfo{Data: "interrupted"}}})
+			gen.Close()
+			return iter
+		},
+		resumer: func(ctx context.Context, info *ResumeInfo, opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+			iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+			gen.Send(EventFromMessage(schema.AssistantMessage("resumed", nil), nil, schema.Assistant, ""))
+			gen.Close()
+			return iter
+		},
+	}
+
+	runner := NewRunner(ctx, RunnerConfig{Agent: a, RunStore: store, CheckPointStore: newMyStore()})
+	iter := runner.Query(ctx, "hi", WithRunID("run1"), WithCheckPointID("cp1"))
+	for {
+		if _, ok := iter.Next(); !ok {
+			break
+		}
+	}
+	run, _, err := store.GetRun(ctx, "run1")
+	assert.NoError(t, err)
+	assert.Equal(t, RunStatusInterrupted, run.Status)
+	assert.Equal(t, "cp1", run.CheckPointID)
+	assert.Equal(t, 1, run.Interrupts)
+
+	iter, err = runner.ResumeRun(ctx, "run1")
+	assert.NoError(t, err)
+	var outputs []string
+	for {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+		assert.NoError(t, event.Err)
+		outputs = append(outputs, event.Output.MessageOutput.Message.Content)
+	}
+	assert.Equal(t, []string{"resumed"}, outputs)
+
+	run, _, err = store.GetRun(ctx, "run1")
+	assert.NoError(t, err)
+	assert.Equal(t, RunStatusCompleted, run.Status)
+	assert.Equal(t, 1, run.Resumes)
+	assert.Equal(t, "resumed", run.Output.Content)
+	events, err := store.ListEvents(ctx, "run1")
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.True(t, events[0].Interrupted)
+}
@@ -0,0 +1,250 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	icb "github.com/cloudwego/eino/internal/callbacks"
+	"github.com/cloudwego/eino/schema"
+	ub "github.com/cloudwego/eino/utils/callbacks"
+)
+
+// ModelPrice is the price of a model per million tokens, in any currency unit as long as it's consistent across the PriceTable.
+type ModelPrice struct {
+	Prompt float64
+	// CachedPrompt is the price of the cached prompt tokens. Optional, defaults to Prompt.
+	CachedPrompt float64
+	Completion   float64
+	// Reasoning is the price of the reasoning tokens, which are part of the completion tokens. Optional, defaults to Completion.
+	Reasoning float64
+}
+
+// PriceTable provides the prices of models to estimate the cost of a run.
+type PriceTable interface {
+	// GetPrice returns the price of the model, the model name is got from the config of the model callbacks.
+	GetPrice(ctx context.Context, modelName string) (price *ModelPrice, ok bool)
+}
+
+// StaticPriceTable is a PriceTable keyed by model name.
+type StaticPriceTable map[string]*ModelPrice
+
+func (s StaticPriceTable) GetPrice(_ context.Context, modelName string) (*ModelPrice, bool) {
+	p, ok := s[modelName]
+	return p, ok && p != nil
+}
+
+// UsageStats is the aggregated token usage of model calls.
+type UsageStats struct {
+	ModelCalls         int
+	PromptTokens       int
+	CachedPromptTokens int
+	CompletionTokens   int
+	ReasoningTokens    int
+	TotalTokens        int
+	// Cost is estimated by the PriceTable of the Runner, or reported by the models wrapped by NewCostTrackingModel.
+	// Model calls without a price are not counted.
+	Cost float64
+}
+
+func (u *UsageStats) add(usage *model.TokenUsage, cost float64) {
+	u.ModelCalls++
+	u.PromptTokens += usage.PromptTokens
+	u.CachedPromptTokens += usage.PromptTokenDetails.CachedTokens
+	u.CompletionTokens += usage.CompletionTokens
+	u.ReasoningTokens += usage.CompletionTokenDetails.ReasoningTokens
+	u.TotalTokens += usage.TotalTokens
+	u.Cost += cost
+}
+
+// RunUsage is the token usage of all model calls within a Runner run, including the ones of nested agents,
+// see RunnerConfig.TrackUsage.
+type RunUsage struct {
+	UsageStats
+	// ByAgent is the usage keyed by the name of the agent which calls the model.
+	ByAgent map[string]*UsageStats
+	// UnpricedModelCalls is the number of model calls without a price in the PriceTable, whose cost isn't estimated.
+	UnpricedModelCalls int
+}
+
+func (r *RunUsage) copy() *RunUsage {
+	c := &RunUsage{
+		UsageStats:         r.UsageStats,
+		ByAgent:            make(map[string]*UsageStats, len(r.ByAgent)),
+		UnpricedModelCalls: r.UnpricedModelCalls,
+	}
+	for k, v := range r.ByAgent {
+		s := *v
+		c.ByAgent[k] = &s
+	}
+	return c
+}
+
+type usageTracker struct {
+	priceTable PriceTable
+	// onAdd is called with the usage of each model call, e.g. to charge it to a budget key. Optional.
+	onAdd func(ctx context.Context, usage *model.TokenUsage, cost float64)
+
+	mu    sync.Mutex
+	usage *RunUsage
+	// wg waits for the streams of the model outputs to be fully received
+	wg sync.WaitGroup
+}
+
+func newUsageTracker(priceTable PriceTable) *usageTracker {
+	return &usageTracker{
+		priceTable: priceTable,
+		usage:      &RunUsage{ByAgent: make(map[string]*UsageStats)},
+	}
+}
+
+type usageModelNameKey struct{}
+
+// withHandler adds the callback handler collecting the token usage of chat models to ctx,
+// so that it's inherited by the graphs of all agents in the run.
+func (t *usageTracker) withHandler(ctx context.Context) context.Context {
+	h := &ub.ModelCallbackHandler{
+		OnStart: func(ctx context.Context, _ *callbacks.RunInfo, input *model.CallbackInput) context.Context {
+			if input != nil && input.Config != nil {
+				return context.WithValue(ctx, usageModelNameKey{}, input.Config.Model)
+			}
+			return ctx
+		},
+		OnEnd: func(ctx context.Context, _ *callbacks.RunInfo, output *model.CallbackOutput) context.Context {
+			t.add(ctx, output)
+			return ctx
+		},
+		OnEndWithStreamOutput: func(ctx context.Context, _ *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+			t.wg.Add(1)
+			go func() {
+				defer t.wg.Done()
+				defer output.Close()
+
+				var last *model.CallbackOutput
+				for {
+					chunk, err := output.Recv()
+					if err != nil {
+						if err == io.EOF {
+							t.add(ctx, last)
+						}
+						return
+					}
+					if chunk != nil && (chunk.TokenUsage != nil || (chunk.Message != nil && chunk.Message.ResponseMeta != nil && chunk.Message.ResponseMeta.Usage != nil)) {
+						last = chunk
+					}
+				}
+			}()
+			return ctx
+		},
+	}
+	return icb.AppendHandlers(ctx, nil, ub.NewHandlerHelper().ChatModel(h).Handler())
+}
+
+func (t *usageTracker) add(ctx context.Context, output *model.CallbackOutput) {
+	usage := tokenUsageOf(output)
+	if usage == nil {
+		return
+	}
+
+	modelName, _ := ctx.Value(usageModelNameKey{}).(string)
+	if output.Config != nil && output.Config.Model != "" {
+		modelName = output.Config.Model
+	}
+	var agentName string
+	if runCtx := getRunCtx(ctx); runCtx != nil && len(runCtx.RunPath) > 0 {
+		agentName = runCtx.RunPath[len(runCtx.RunPath)-1].agentName
+	}
+
+	cost, priced := reportedCost(output)
+	if !priced && t.priceTable != nil {
+		var price *ModelPrice
+		price, priced = t.priceTable.GetPrice(ctx, modelName)
+		if priced {
+			cost = price.cost(usage)
+		}
+	}
+
+	if t.onAdd != nil {
+		t.onAdd(ctx, usage, cost)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.usage.add(usage, cost)
+	if !priced {
+		t.usage.UnpricedModelCalls++
+	}
+	s, ok := t.usage.ByAgent[agentName]
+	if !ok {
+		s = &UsageStats{}
+		t.usage.ByAgent[agentName] = s
+	}
+	s.add(usage, cost)
+}
+
+func (t *usageTracker) snapshot() *RunUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.usage.copy()
+}
+
+// final waits for all the model output streams to be received and returns the usage of the run.
+func (t *usageTracker) final() *RunUsage {
+	t.wg.Wait()
+	return t.snapshot()
+}
+
+func (p *ModelPrice) cost(usage *model.TokenUsage) float64 {
+	cachedPrice := p.CachedPrompt
+	if cachedPrice == 0 {
+		cachedPrice = p.Prompt
+	}
+	reasoningPrice := p.Reasoning
+	if reasoningPrice == 0 {
+		reasoningPrice = p.Completion
+	}
+	cached := usage.PromptTokenDetails.CachedTokens
+	reasoning := usage.CompletionTokenDetails.ReasoningTokens
+	return (float64(usage.PromptTokens-cached)*p.Prompt +
+		float64(cached)*cachedPrice +
+		float64(usage.CompletionTokens-reasoning)*p.Completion +
+		float64(reasoning)*reasoningPrice) / 1e6
+}
+
+func tokenUsageOf(output *model.CallbackOutput) *model.TokenUsage {
+	if output == nil {
+		return nil
+	}
+	if output.TokenUsage != nil {
+		return output.TokenUsage
+	}
+	if output.Message == nil || output.Message.ResponseMeta == nil || output.Message.ResponseMeta.Usage == nil {
+		return nil
+	}
+	u := output.Message.ResponseMeta.Usage
+	return &model.TokenUsage{
+		PromptTokens:           u.PromptTokens,
+		PromptTokenDetails:     model.PromptTokenDetails{CachedTokens: u.PromptTokenDetails.CachedTokens},
+		CompletionTokens:       u.CompletionTokens,
+		CompletionTokenDetails: model.CompletionTokenDetails{ReasoningTokens: u.CompletionTokenDetails.ReasoningTokens},
+		TotalTokens:            u.TotalTokens,
+	}
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// usageModel reports its model name and token usage by callbacks, like most model implementations.
+type usageModel struct {
+	name  string
+	usage *model.TokenUsage
+}
+
+func (u *usageModel) Generate(ctx context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	ctx = callbacks.EnsureRunInfo(ctx, "UsageModel", components.ComponentOfChatModel)
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: input, Config: &model.Config{Model: u.name}})
+	msg := schema.AssistantMessage(u.name, nil)
+	callbacks.OnEnd(ctx, &model.CallbackOutput{Message: msg, TokenUsage: u.usage})
+	return msg, nil
+}
+
+func (u *usageModel) Stream(ctx context.Context, input []*schema.Message, _ ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	ctx = callbacks.EnsureRunInfo(ctx, "UsageModel", components.ComponentOfChatModel)
+	ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: input, Config: &model.Config{Model: u.name}})
+	_, sr := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderFromArray([]*model.CallbackOutput{
+		{Message: schema.AssistantMessage(u.name, nil)},
+		{Message: schema.AssistantMessage("", nil), TokenUsage: u.usage},
+	}))
+	return schema.StreamReaderWithConvert(sr, func(o *model.CallbackOutput) (*schema.Message, error) {
+		return o.Message, nil
+	}), nil
+}
+
+func (u *usageModel) WithTools(_ []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return u, nil
+}
+
+func (u *usageModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+func TestRunnerTrackUsage(t *testing.T) {
+	ctx := context.Background()
+
+	for _, streaming := range []bool{false, true} {
+		a1, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+			Name:        "a1",
+			Description: "a1",
+			Model: &usageModel{name: "big", usage: &model.TokenUsage{
+				PromptTokens:       1000,
+				PromptTokenDetails: model.PromptTokenDetails{CachedTokens: 500},
+				CompletionTokens:   100,
+				TotalTokens:        1100,
+			}},
+		})
+		assert.NoError(t, err)
+		a2, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+			Name:        "a2",
+			Description: "a2",
+			Model:       &usageModel{name: "unknown", usage: &model.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}},
+		})
+		assert.NoError(t, err)
+		seq, err := NewSequentialAgent(ctx, &SequentialAgentConfig{Name: "seq", Description: "seq", SubAgents: []Agent{a1, a2}})
+		assert.NoError(t, err)
+
+		var final *RunUsage
+		runner := NewRunner(ctx, RunnerConfig{
+			Agent:           seq,
+			EnableStreaming: streaming,
+			PriceTable: StaticPriceTable{
+				"big": {Prompt: 2, CachedPrompt: 1, Completion: 10},
+			},
+			OnRunUsage: func(ctx context.Context, usage *RunUsage) {
+				final = usage
+			},
+		})
+
+		iter := runner.Query(ctx, "hi")
+		var events []*AgentEvent
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+			assert.NotNil(t, event.RunUsage)
+			if event.Output != nil && event.Output.MessageOutput != nil && event.Output.MessageOutput.IsStreaming {
+				event.Output.MessageOutput.MessageStream.Close()
+			}
+			events = append(events, event)
+		}
+		assert.Len(t, events, 2)
+
+		expectedCost := (500*2.0 + 500*1.0 + 100*10.0) / 1e6
+		assert.Equal(t, &RunUsage{
+			UsageStats: UsageStats{
+				ModelCalls:         2,
+				PromptTokens:       1010,
+				CachedPromptTokens: 500,
+				CompletionTokens:   105,
+				TotalTokens:        1115,
+				Cost:               expectedCost,
+			},
+			ByAgent: map[string]*UsageStats{
+				"a1": {ModelCalls: 1, PromptTokens: 1000, CachedPromptTokens: 500, CompletionTokens: 100, TotalTokens: 1100, Cost: expectedCost},
+				"a2": {ModelCalls: 1, PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+			},
+			UnpricedModelCalls: 1,
+		}, final)
+	}
+}
@@ -32,6 +32,9 @@ type runSession struct {
 
 	interruptRunCtxs []*runContext // won't consider concurrency now
 
+	// modelCost is the cost accumulated by the models wrapped by NewCostTrackingModel, it's not persisted in checkpoints.
+	modelCost float64
+
 	mtx sync.Mutex
 }
 
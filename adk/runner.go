@@ -30,6 +30,13 @@ type Runner struct {
 	a               Agent
 	enableStreaming bool
 	store           compose.CheckPointStore
+	sessionService  SessionService
+	appName         string
+	runStore        RunStore
+
+	trackUsage bool
+	priceTable PriceTable
+	onRunUsage func(ctx context.Context, usage *RunUsage)
 }
 
 type RunnerConfig struct {
@@ -37,6 +44,24 @@ type RunnerConfig struct {
 	EnableStreaming bool
 
 	CheckPointStore compose.CheckPointStore
+
+	// SessionService persists the conversation of sessions designated by WithSession.
+	// The history of the session is prepended to the input messages, and the messages produced by agents are appended to the session.
+	SessionService SessionService
+	// AppName is the app scope of the sessions.
+	AppName string
+
+	// RunStore persists the runs designated by WithRunID and all their events as they happen,
+	// so the runs can be audited, and resumed by ResumeRun after the process restarts. Optional.
+	RunStore RunStore
+
+	// TrackUsage enables aggregating the token usage of all model calls in a run, including the ones of nested agents.
+	// The usage so far is set to AgentEvent.RunUsage of each event emitted by the runner.
+	TrackUsage bool
+	// PriceTable estimates the cost of the token usage. Optional.
+	PriceTable PriceTable
+	// OnRunUsage is called with the usage of the run when the run ends, it enables TrackUsage implicitly. Optional.
+	OnRunUsage func(ctx context.Context, usage *RunUsage)
 }
 
 func NewRunner(_ context.Context, conf RunnerConfig) *Runner {
@@ -44,6 +69,12 @@ func NewRunner(_ context.Context, conf RunnerConfig) *Runner {
 		enableStreaming: conf.EnableStreaming,
 		a:               conf.Agent,
 		store:           conf.CheckPointStore,
+		sessionService:  conf.SessionService,
+		appName:         conf.AppName,
+		runStore:        conf.RunStore,
+		trackUsage:      conf.TrackUsage || conf.OnRunUsage != nil,
+		priceTable:      conf.PriceTable,
+		onRunUsage:      conf.OnRunUsage,
 	}
 }
 
@@ -51,6 +82,32 @@ func (r *Runner) Run(ctx context.Context, messages []Message,
 	opts ...AgentRunOption) *AsyncIterator[*AgentEvent] {
 	o := getCommonOptions(nil, opts...)
 
+	sr, err := newSessionRecorder(ctx, r.sessionService, r.appName, o)
+	if err != nil {
+		return genErrorIter(err)
+	}
+	rr, err := newRunRecorder(ctx, r.runStore, messages, o)
+	if err != nil {
+		return genErrorIter(err)
+	}
+	if sr != nil {
+		if o.resumeRun {
+			// the session has already persisted the messages of the run
+			messages, err = sr.loadHistory(ctx, nil)
+		} else {
+			messages, err = sr.loadHistory(ctx, messages)
+		}
+		if err != nil {
+			return genErrorIter(err)
+		}
+		ctx = withScopedState(ctx, sr.state)
+	} else {
+		ctx = withScopedState(ctx, newScopedState())
+	}
+	if o.resumeRun {
+		messages = trimUnansweredToolCalls(messages)
+	}
+
 	fa := toFlowAgent(ctx, r.a)
 
 	input := &AgentInput{
@@ -62,14 +119,24 @@ func (r *Runner) Run(ctx context.Context, messages []Message,
 
 	AddSessionValues(ctx, o.sessionValues)
 
+	ut := r.newUsageTracker()
+	if ut != nil {
+		ctx = ut.withHandler(ctx)
+	}
+	ctx = withRunBudget(ctx, o.budget, ut, r.priceTable)
+	ctx, err = withBudgetQuota(ctx, o.budgetQuota, ut, r.priceTable)
+	if err != nil {
+		return genErrorIter(err)
+	}
+
 	iter := fa.Run(ctx, input, opts...)
-	if r.store == nil {
+	if r.store == nil && sr == nil && ut == nil && rr == nil {
 		return iter
 	}
 
 	niter, gen := NewAsyncIteratorPair[*AgentEvent]()
 
-	go r.handleIter(ctx, iter, gen, o.checkPointID)
+	go r.handleIter(ctx, iter, gen, o.checkPointID, sr, ut, rr)
 	return niter
 }
 
@@ -105,6 +172,31 @@ func (r *Runner) Resume(ctx context.Context, checkPointID string, opts ...AgentR
 	o := getCommonOptions(nil, opts...)
 	AddSessionValues(ctx, o.sessionValues)
 
+	sr, err := newSessionRecorder(ctx, r.sessionService, r.appName, o)
+	if err != nil {
+		return nil, err
+	}
+	o.checkPointID = &checkPointID
+	rr, err := newRunRecorder(ctx, r.runStore, nil, o)
+	if err != nil {
+		return nil, err
+	}
+	if sr != nil {
+		ctx = withScopedState(ctx, sr.state)
+	} else {
+		ctx = withScopedState(ctx, newScopedState())
+	}
+
+	ut := r.newUsageTracker()
+	if ut != nil {
+		ctx = ut.withHandler(ctx)
+	}
+	ctx = withRunBudget(ctx, o.budget, ut, r.priceTable)
+	ctx, err = withBudgetQuota(ctx, o.budgetQuota, ut, r.priceTable)
+	if err != nil {
+		return nil, err
+	}
+
 	aIter := toFlowAgent(ctx, r.a).Resume(ctx, info, opts...)
 	if r.store == nil {
 		return aIter, nil
@@ -112,11 +204,12 @@ func (r *Runner) Resume(ctx context.Context, checkPointID string, opts ...AgentR
 
 	niter, gen := NewAsyncIteratorPair[*AgentEvent]()
 
-	go r.handleIter(ctx, aIter, gen, &checkPointID)
+	go r.handleIter(ctx, aIter, gen, &checkPointID, sr, ut, rr)
 	return niter, nil
 }
 
-func (r *Runner) handleIter(ctx context.Context, aIter *AsyncIterator[*AgentEvent], gen *AsyncGenerator[*AgentEvent], checkPointID *string) {
+func (r *Runner) handleIter(ctx context.Context, aIter *AsyncIterator[*AgentEvent], gen *AsyncGenerator[*AgentEvent],
+	checkPointID *string, sr *sessionRecorder, ut *usageTracker, rr *runRecorder) {
 	defer func() {
 		panicErr := recover()
 		if panicErr != nil {
@@ -127,6 +220,7 @@ func (r *Runner) handleIter(ctx context.Context, aIter *AsyncIterator[*AgentEven
 		gen.Close()
 	}()
 	var interruptedInfo *InterruptInfo
+	var lastErr error
 	for {
 		event, ok := aIter.Next()
 		if !ok {
@@ -138,14 +232,72 @@ func (r *Runner) handleIter(ctx context.Context, aIter *AsyncIterator[*AgentEven
 		} else {
 			interruptedInfo = nil
 		}
+		lastErr = event.Err
+
+		if ut != nil {
+			event.RunUsage = ut.snapshot()
+		}
+
+		var persist, persistRun func() error
+		if sr != nil {
+			persist = sr.record(ctx, event)
+		}
+		if rr != nil {
+			persistRun = rr.record(ctx, event)
+		}
 
 		gen.Send(event)
+
+		if persist != nil {
+			if err := persist(); err != nil {
+				gen.Send(&AgentEvent{Err: err})
+			}
+		}
+		if persistRun != nil {
+			if err := persistRun(); err != nil {
+				gen.Send(&AgentEvent{Err: err})
+			}
+		}
+		if sr != nil {
+			if err := sr.flushState(ctx); err != nil {
+				gen.Send(&AgentEvent{Err: err})
+			}
+		}
 	}
 
-	if interruptedInfo != nil && checkPointID != nil {
+	if sr != nil {
+		if err := sr.flushState(ctx); err != nil {
+			gen.Send(&AgentEvent{Err: err})
+		}
+	}
+
+	if interruptedInfo != nil && checkPointID != nil && r.store != nil {
 		err := saveCheckPoint(ctx, r.store, *checkPointID, getInterruptRunCtx(ctx), interruptedInfo)
 		if err != nil {
 			gen.Send(&AgentEvent{Err: fmt.Errorf("failed to save checkpoint: %w", err)})
 		}
 	}
+
+	if rr != nil {
+		status := RunStatusCompleted
+		if interruptedInfo != nil {
+			status = RunStatusInterrupted
+		} else if lastErr != nil {
+			status = RunStatusFailed
+		}
+		if err := rr.finish(ctx, status, lastErr); err != nil {
+			gen.Send(&AgentEvent{Err: err})
+		}
+	}
+
+	if ut != nil && r.onRunUsage != nil {
+		r.onRunUsage(ctx, ut.final())
+	}
+}
+
+func (r *Runner) newUsageTracker() *usageTracker {
+	if !r.trackUsage {
+		return nil
+	}
+	return newUsageTracker(r.priceTable)
 }
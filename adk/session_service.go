@@ -0,0 +1,431 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// SessionScope identifies a session. Sessions are scoped by app, then by user.
+type SessionScope struct {
+	AppName   string
+	UserID    string
+	SessionID string
+}
+
+// Session is a conversation between a user and the agents of an app.
+type Session struct {
+	AppName   string    `json:"app_name"`
+	UserID    string    `json:"user_id"`
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SessionEvent is a persisted message of a session.
+type SessionEvent struct {
+	ID string `json:"id"`
+	// AgentName is the name of the agent which produced the message, empty for user input.
+	AgentName string    `json:"agent_name,omitempty"`
+	Message   Message   `json:"message"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SessionService stores sessions and their events, so multi-turn conversations can be continued across runs.
+// When set to RunnerConfig, Runner loads the history of the session designated by WithSession before running the agent,
+// and persists the input messages and the messages produced by agents automatically.
+type SessionService interface {
+	// CreateSession creates a session, a random session id is generated if scope.SessionID is empty.
+	CreateSession(ctx context.Context, scope SessionScope) (*Session, error)
+	GetSession(ctx context.Context, scope SessionScope) (*Session, bool, error)
+	AppendEvent(ctx context.Context, scope SessionScope, event *SessionEvent) error
+	// ListEvents returns the events of the session in the order they are appended.
+	ListEvents(ctx context.Context, scope SessionScope) ([]*SessionEvent, error)
+
+	// GetState returns the state of the scope, which is the state of the app if scope.UserID is empty,
+	// the state of the user if scope.SessionID is empty, or the state of the session. See GetScopedState.
+	GetState(ctx context.Context, scope SessionScope) (map[string]any, error)
+	// UpdateState merges the delta into the state of the scope, a nil value deletes the key.
+	UpdateState(ctx context.Context, scope SessionScope, delta map[string]any) error
+}
+
+// NewInMemorySessionService creates a SessionService which keeps sessions in memory, mostly for tests and demos.
+func NewInMemorySessionService() SessionService {
+	return &inMemorySessionService{
+		sessions: make(map[SessionScope]*inMemorySession),
+		states:   make(map[SessionScope]map[string]any),
+	}
+}
+
+type inMemorySession struct {
+	session *Session
+	events  []*SessionEvent
+}
+
+type inMemorySessionService struct {
+	mu       sync.RWMutex
+	sessions map[SessionScope]*inMemorySession
+	states   map[SessionScope]map[string]any
+}
+
+func (s *inMemorySessionService) CreateSession(_ context.Context, scope SessionScope) (*Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if scope.SessionID == "" {
+		scope.SessionID = uuid.NewString()
+	}
+	if _, ok := s.sessions[scope]; ok {
+		return nil, fmt.Errorf("session[%s] already exists", scope.SessionID)
+	}
+
+	session := newSession(scope)
+	s.sessions[scope] = &inMemorySession{session: session}
+	cp := *session
+	return &cp, nil
+}
+
+func (s *inMemorySessionService) GetSession(_ context.Context, scope SessionScope) (*Session, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, ok := s.sessions[scope]
+	if !ok {
+		return nil, false, nil
+	}
+	cp := *sess.session
+	return &cp, true, nil
+}
+
+func (s *inMemorySessionService) AppendEvent(_ context.Context, scope SessionScope, event *SessionEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.sessions[scope]
+	if !ok {
+		return fmt.Errorf("session[%s] not found", scope.SessionID)
+	}
+	fillSessionEvent(event)
+	sess.events = append(sess.events, event)
+	sess.session.UpdatedAt = event.CreatedAt
+	return nil
+}
+
+func (s *inMemorySessionService) ListEvents(_ context.Context, scope SessionScope) ([]*SessionEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sess, ok := s.sessions[scope]
+	if !ok {
+		return nil, fmt.Errorf("session[%s] not found", scope.SessionID)
+	}
+	events := make([]*SessionEvent, len(sess.events))
+	copy(events, sess.events)
+	return events, nil
+}
+
+// NewFileSessionService creates a SessionService which stores sessions in the directory,
+// one sub directory per session at <dir>/<app>/<user>/<session>, with the events appended to a JSON lines file.
+// It's a reference implementation for single process usage.
+func NewFileSessionService(dir string) SessionService {
+	return &fileSessionService{dir: dir}
+}
+
+const (
+	sessionFileName       = "session.json"
+	sessionEventsFileName = "events.jsonl"
+)
+
+type fileSessionService struct {
+	mu  sync.Mutex
+	dir string
+}
+
+func (f *fileSessionService) sessionDir(scope SessionScope) string {
+	return filepath.Join(f.dir, url.PathEscape(scope.AppName), url.PathEscape(scope.UserID), url.PathEscape(scope.SessionID))
+}
+
+func (f *fileSessionService) CreateSession(_ context.Context, scope SessionScope) (*Session, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if scope.SessionID == "" {
+		scope.SessionID = uuid.NewString()
+	}
+	dir := f.sessionDir(scope)
+	if _, err := os.Stat(filepath.Join(dir, sessionFileName)); err == nil {
+		return nil, fmt.Errorf("session[%s] already exists", scope.SessionID)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create session dir: %w", err)
+	}
+
+	session := newSession(scope)
+	if err := writeSessionFile(dir, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (f *fileSessionService) GetSession(_ context.Context, scope SessionScope) (*Session, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return readSessionFile(f.sessionDir(scope))
+}
+
+func (f *fileSessionService) AppendEvent(_ context.Context, scope SessionScope, event *SessionEvent) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dir := f.sessionDir(scope)
+	session, ok, err := readSessionFile(dir)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("session[%s] not found", scope.SessionID)
+	}
+
+	fillSessionEvent(event)
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session event: %w", err)
+	}
+	file, err := os.OpenFile(filepath.Join(dir, sessionEventsFileName), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open session events file: %w", err)
+	}
+	_, err = file.Write(append(data, '\n'))
+	if cErr := file.Close(); err == nil {
+		err = cErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write session event: %w", err)
+	}
+
+	session.UpdatedAt = event.CreatedAt
+	return writeSessionFile(dir, session)
+}
+
+func (f *fileSessionService) ListEvents(_ context.Context, scope SessionScope) ([]*SessionEvent, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	dir := f.sessionDir(scope)
+	if _, ok, err := readSessionFile(dir); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, fmt.Errorf("session[%s] not found", scope.SessionID)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, sessionEventsFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read session events file: %w", err)
+	}
+
+	var events []*SessionEvent
+	reader := bufio.NewReader(bytes.NewReader(data))
+	for {
+		line, rErr := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(line)) > 0 {
+			event := &SessionEvent{}
+			if err = json.Unmarshal(line, event); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal session event: %w", err)
+			}
+			events = append(events, event)
+		}
+		if rErr == io.EOF {
+			break
+		}
+		if rErr != nil {
+			return nil, fmt.Errorf("failed to read session events file: %w", rErr)
+		}
+	}
+	return events, nil
+}
+
+func readSessionFile(dir string) (*Session, bool, error) {
+	data, err := os.ReadFile(filepath.Join(dir, sessionFileName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to read session file: %w", err)
+	}
+	session := &Session{}
+	if err = json.Unmarshal(data, session); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return session, true, nil
+}
+
+func writeSessionFile(dir string, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err = os.WriteFile(filepath.Join(dir, sessionFileName), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	return nil
+}
+
+func newSession(scope SessionScope) *Session {
+	now := time.Now()
+	return &Session{
+		AppName:   scope.AppName,
+		UserID:    scope.UserID,
+		ID:        scope.SessionID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func fillSessionEvent(event *SessionEvent) {
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+}
+
+// WithSession designates the session of the run, which takes effect when Runner is configured with a SessionService.
+// The session is created if not exists.
+func WithSession(userID, sessionID string) AgentRunOption {
+	return WrapImplSpecificOptFn(func(o *options) {
+		o.session = &SessionScope{
+			UserID:    userID,
+			SessionID: sessionID,
+		}
+	})
+}
+
+// sessionRecorder loads the history of a session and persists the messages of a run.
+type sessionRecorder struct {
+	service SessionService
+	scope   SessionScope
+	state   *scopedState
+}
+
+func newSessionRecorder(ctx context.Context, service SessionService, appName string, o *options) (*sessionRecorder, error) {
+	if service == nil || o.session == nil {
+		return nil, nil
+	}
+
+	scope := *o.session
+	scope.AppName = appName
+	_, existed, err := service.GetSession(ctx, scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if !existed {
+		if _, err = service.CreateSession(ctx, scope); err != nil {
+			return nil, fmt.Errorf("failed to create session: %w", err)
+		}
+	}
+
+	state, err := loadScopedState(ctx, service, scope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sessionRecorder{service: service, scope: scope, state: state}, nil
+}
+
+// loadHistory returns the history messages followed by the input messages, and persists the input messages.
+func (s *sessionRecorder) loadHistory(ctx context.Context, messages []Message) ([]Message, error) {
+	events, err := s.service.ListEvents(ctx, s.scope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session events: %w", err)
+	}
+
+	history := make([]Message, 0, len(events)+len(messages))
+	for _, e := range events {
+		if e.Message != nil {
+			history = append(history, e.Message)
+		}
+	}
+
+	for _, m := range messages {
+		if err = s.service.AppendEvent(ctx, s.scope, &SessionEvent{Message: m}); err != nil {
+			return nil, fmt.Errorf("failed to append session event: %w", err)
+		}
+	}
+
+	return append(history, messages...), nil
+}
+
+// record persists the message of the event, the MessageStream of the event is copied so that the caller can still receive it.
+// The returned function blocks until the message is persisted, it should be called after the event is sent.
+func (s *sessionRecorder) record(ctx context.Context, event *AgentEvent) func() error {
+	if event.Output == nil || event.Output.MessageOutput == nil {
+		return nil
+	}
+
+	mv := event.Output.MessageOutput
+	if !mv.IsStreaming {
+		return func() error {
+			return s.append(ctx, event.AgentName, mv.Message)
+		}
+	}
+
+	ss := mv.MessageStream.Copy(2)
+	mv.MessageStream = ss[0]
+	return func() error {
+		msg, err := schema.ConcatMessageStream(ss[1])
+		if err != nil {
+			// the error has been delivered to the caller along with the stream
+			return nil
+		}
+		return s.append(ctx, event.AgentName, msg)
+	}
+}
+
+// flushState persists the changes of the scoped state.
+func (s *sessionRecorder) flushState(ctx context.Context) error {
+	return s.state.flush(ctx, s.service, s.scope)
+}
+
+func (s *sessionRecorder) append(ctx context.Context, agentName string, msg Message) error {
+	if msg == nil {
+		return nil
+	}
+	if err := s.service.AppendEvent(ctx, s.scope, &SessionEvent{AgentName: agentName, Message: msg}); err != nil {
+		return fmt.Errorf("failed to append session event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestSessionService(t *testing.T) {
+	ctx := context.Background()
+	services := map[string]SessionService{
+		"in_memory": NewInMemorySessionService(),
+		"file":      NewFileSessionService(t.TempDir()),
+	}
+
+	for name, service := range services {
+		t.Run(name, func(t *testing.T) {
+			scope := SessionScope{AppName: "app", UserID: "user/1"}
+			session, err := service.CreateSession(ctx, scope)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, session.ID)
+			assert.Equal(t, "user/1", session.UserID)
+			scope.SessionID = session.ID
+
+			_, err = service.CreateSession(ctx, scope)
+			assert.Error(t, err)
+
+			got, ok, err := service.GetSession(ctx, scope)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+			assert.Equal(t, session.ID, got.ID)
+
+			_, ok, err = service.GetSession(ctx, SessionScope{AppName: "app", UserID: "user2", SessionID: session.ID})
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			events, err := service.ListEvents(ctx, scope)
+			assert.NoError(t, err)
+			assert.Empty(t, events)
+
+			assert.NoError(t, service.AppendEvent(ctx, scope, &SessionEvent{Message: schema.UserMessage("hi")}))
+			assert.NoError(t, service.AppendEvent(ctx, scope, &SessionEvent{AgentName: "a", Message: schema.AssistantMessage("hello", nil)}))
+			events, err = service.ListEvents(ctx, scope)
+			assert.NoError(t, err)
+			assert.Len(t, events, 2)
+			assert.Equal(t, "hi", events[0].Message.Content)
+			assert.Equal(t, "a", events[1].AgentName)
+			assert.NotEmpty(t, events[1].ID)
+
+			assert.Error(t, service.AppendEvent(ctx, SessionScope{AppName: "app", UserID: "user", SessionID: "none"}, &SessionEvent{}))
+			_, err = service.ListEvents(ctx, SessionScope{AppName: "app", UserID: "user", SessionID: "none"})
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestRunnerWithSession(t *testing.T) {
+	ctx := context.Background()
+	service := NewInMemorySessionService()
+
+	agent := newMockRunnerAgent("agent", "", []*AgentEvent{
+		{AgentName: "agent", Output: &AgentOutput{MessageOutput: &MessageVariant{Message: schema.AssistantMessage("reply", nil), Role: schema.Assistant}}},
+	})
+	runner := NewRunner(ctx, RunnerConfig{Agent: agent, SessionService: service, AppName: "app"})
+
+	drain := func(iter *AsyncIterator[*AgentEvent]) {
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+		}
+	}
+
+	drain(runner.Query(ctx, "first", WithSession("u", "s")))
+	assert.Len(t, agent.lastInput.Messages, 1)
+
+	drain(runner.Query(ctx, "second", WithSession("u", "s")))
+	assert.Equal(t, []string{"first", "reply", "second"}, messageContents(agent.lastInput.Messages))
+
+	// no session designated, nothing is loaded
+	drain(runner.Query(ctx, "third"))
+	assert.Len(t, agent.lastInput.Messages, 1)
+
+	events, err := service.ListEvents(ctx, SessionScope{AppName: "app", UserID: "u", SessionID: "s"})
+	assert.NoError(t, err)
+	assert.Len(t, events, 4)
+	assert.Equal(t, "agent", events[3].AgentName)
+
+	// streaming output is persisted after being concatenated, and is still received by the caller
+	streamAgent := newMockRunnerAgent("stream_agent", "", []*AgentEvent{
+		EventFromMessage(nil, schema.StreamReaderFromArray([]Message{
+			schema.AssistantMessage("a", nil), schema.AssistantMessage("b", nil),
+		}), schema.Assistant, ""),
+	})
+	runner = NewRunner(ctx, RunnerConfig{Agent: streamAgent, SessionService: service, AppName: "app", EnableStreaming: true})
+	iter := runner.Query(ctx, "stream", WithSession("u", "s2"))
+	event, ok := iter.Next()
+	assert.True(t, ok)
+	msg, err := schema.ConcatMessageStream(event.Output.MessageOutput.MessageStream)
+	assert.NoError(t, err)
+	assert.Equal(t, "ab", msg.Content)
+	drain(iter)
+
+	events, err = service.ListEvents(ctx, SessionScope{AppName: "app", UserID: "u", SessionID: "s2"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"stream", "ab"}, messageContents([]Message{events[0].Message, events[1].Message}))
+}
+
+func messageContents(msgs []Message) []string {
+	contents := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		contents = append(contents, m.Content)
+	}
+	return contents
+}
@@ -0,0 +1,247 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// The prefixes of the keys of scoped state, which decide where the value is stored and how long it lives.
+// Keys without these prefixes are scoped to the session.
+const (
+	// StateAppPrefix marks keys shared by all users and sessions of the app, e.g. "app:feature_flags".
+	StateAppPrefix = "app:"
+	// StateUserPrefix marks keys shared by all sessions of the user, e.g. "user:preferred_language".
+	StateUserPrefix = "user:"
+	// StateTempPrefix marks keys only living in the current run, which are never persisted.
+	StateTempPrefix = "temp:"
+)
+
+// GetScopedState returns the value of the key in the scoped state of the run.
+// The scoped state is loaded from the SessionService of the Runner when running with WithSession,
+// see StateAppPrefix, StateUserPrefix and StateTempPrefix for the scopes.
+// Values are deserialized by the SessionService, e.g. numbers stored by the file session service are read back as float64.
+func GetScopedState(ctx context.Context, key string) (any, bool) {
+	st := getScopedState(ctx)
+	if st == nil {
+		return nil, false
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	v, ok := st.values[key]
+	return v, ok
+}
+
+// SetScopedState sets the value of the key in the scoped state of the run, a nil value deletes the key.
+// The change is persisted to the SessionService automatically by the Runner, unless the key is prefixed by StateTempPrefix.
+// It's a no-op outside of a Runner run.
+// e.g.
+//
+//	adk.SetScopedState(ctx, "user:preferred_language", "fr")
+func SetScopedState(ctx context.Context, key string, value any) {
+	st := getScopedState(ctx)
+	if st == nil {
+		return
+	}
+
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if value == nil {
+		delete(st.values, key)
+	} else {
+		st.values[key] = value
+	}
+	if !strings.HasPrefix(key, StateTempPrefix) {
+		st.delta[key] = value
+	}
+}
+
+type scopedStateKey struct{}
+
+type scopedState struct {
+	mu     sync.Mutex
+	values map[string]any
+	// delta are the changes to be persisted, a nil value means deletion
+	delta map[string]any
+}
+
+func newScopedState() *scopedState {
+	return &scopedState{
+		values: make(map[string]any),
+		delta:  make(map[string]any),
+	}
+}
+
+func withScopedState(ctx context.Context, st *scopedState) context.Context {
+	return context.WithValue(ctx, scopedStateKey{}, st)
+}
+
+func getScopedState(ctx context.Context) *scopedState {
+	st, _ := ctx.Value(scopedStateKey{}).(*scopedState)
+	return st
+}
+
+// stateScopes returns the scopes of the app, user and session states of the session.
+func stateScopes(scope SessionScope) (app, user, session SessionScope) {
+	return SessionScope{AppName: scope.AppName},
+		SessionScope{AppName: scope.AppName, UserID: scope.UserID},
+		scope
+}
+
+// loadScopedState loads the app, user and session states of the session.
+func loadScopedState(ctx context.Context, service SessionService, scope SessionScope) (*scopedState, error) {
+	st := newScopedState()
+	app, user, session := stateScopes(scope)
+	for _, s := range []SessionScope{app, user, session} {
+		values, err := service.GetState(ctx, s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get state: %w", err)
+		}
+		for k, v := range values {
+			st.values[k] = v
+		}
+	}
+	return st, nil
+}
+
+// flush persists the changes of the state to the scopes decided by the key prefixes.
+func (st *scopedState) flush(ctx context.Context, service SessionService, scope SessionScope) error {
+	st.mu.Lock()
+	delta := st.delta
+	st.delta = make(map[string]any)
+	st.mu.Unlock()
+
+	if len(delta) == 0 {
+		return nil
+	}
+
+	app, user, session := stateScopes(scope)
+	deltas := map[SessionScope]map[string]any{}
+	for k, v := range delta {
+		s := session
+		if strings.HasPrefix(k, StateAppPrefix) {
+			s = app
+		} else if strings.HasPrefix(k, StateUserPrefix) {
+			s = user
+		}
+		if deltas[s] == nil {
+			deltas[s] = make(map[string]any)
+		}
+		deltas[s][k] = v
+	}
+	for _, s := range []SessionScope{app, user, session} {
+		if d, ok := deltas[s]; ok {
+			if err := service.UpdateState(ctx, s, d); err != nil {
+				return fmt.Errorf("failed to update state: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func mergeState(state, delta map[string]any) map[string]any {
+	if state == nil {
+		state = make(map[string]any, len(delta))
+	}
+	for k, v := range delta {
+		if v == nil {
+			delete(state, k)
+		} else {
+			state[k] = v
+		}
+	}
+	return state
+}
+
+func (s *inMemorySessionService) GetState(_ context.Context, scope SessionScope) (map[string]any, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	state := make(map[string]any, len(s.states[scope]))
+	for k, v := range s.states[scope] {
+		state[k] = v
+	}
+	return state, nil
+}
+
+func (s *inMemorySessionService) UpdateState(_ context.Context, scope SessionScope, delta map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.states[scope] = mergeState(s.states[scope], delta)
+	return nil
+}
+
+// stateFileName is the name of the state file in the app, user and session directories.
+// '#' is always escaped in the names of the sub directories, so the file never collides with them.
+const stateFileName = "#state.json"
+
+func (f *fileSessionService) stateFile(scope SessionScope) string {
+	return filepath.Join(f.sessionDir(scope), stateFileName)
+}
+
+func (f *fileSessionService) GetState(_ context.Context, scope SessionScope) (map[string]any, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return readStateFile(f.stateFile(scope))
+}
+
+func (f *fileSessionService) UpdateState(_ context.Context, scope SessionScope, delta map[string]any) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := f.stateFile(scope)
+	state, err := readStateFile(path)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(mergeState(state, delta))
+	if err != nil {
+		return fmt.Errorf("failed to marshal state: %w", err)
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+	if err = os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write state file: %w", err)
+	}
+	return nil
+}
+
+func readStateFile(path string) (map[string]any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]any{}, nil
+		}
+		return nil, fmt.Errorf("failed to read state file: %w", err)
+	}
+	state := map[string]any{}
+	if err = json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal state: %w", err)
+	}
+	return state, nil
+}
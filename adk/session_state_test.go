@@ -0,0 +1,116 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// stateAgent runs fn with the ctx of the run, then replies "done".
+type stateAgent struct {
+	fn func(ctx context.Context)
+}
+
+func (s *stateAgent) Name(_ context.Context) string {
+	return "state_agent"
+}
+
+func (s *stateAgent) Description(_ context.Context) string {
+	return "state agent"
+}
+
+func (s *stateAgent) Run(ctx context.Context, _ *AgentInput, _ ...AgentRunOption) *AsyncIterator[*AgentEvent] {
+	iter, gen := NewAsyncIteratorPair[*AgentEvent]()
+	go func() {
+		defer gen.Close()
+		s.fn(ctx)
+		gen.Send(EventFromMessage(schema.AssistantMessage("done", nil), nil, schema.Assistant, ""))
+	}()
+	return iter
+}
+
+func TestScopedState(t *testing.T) {
+	ctx := context.Background()
+	services := map[string]SessionService{
+		"in_memory": NewInMemorySessionService(),
+		"file":      NewFileSessionService(t.TempDir()),
+	}
+
+	for name, service := range services {
+		t.Run(name, func(t *testing.T) {
+			run := func(userID, sessionID string, fn func(ctx context.Context)) {
+				runner := NewRunner(ctx, RunnerConfig{Agent: &stateAgent{fn: fn}, SessionService: service, AppName: "app"})
+				iter := runner.Query(ctx, "hi", WithSession(userID, sessionID))
+				for {
+					event, ok := iter.Next()
+					if !ok {
+						break
+					}
+					assert.NoError(t, event.Err)
+				}
+			}
+
+			run("u1", "s1", func(ctx context.Context) {
+				SetScopedState(ctx, "app:greeting", "hello")
+				SetScopedState(ctx, "user:lang", "fr")
+				SetScopedState(ctx, "topic", "travel")
+				SetScopedState(ctx, "temp:scratch", "x")
+				v, ok := GetScopedState(ctx, "temp:scratch")
+				assert.True(t, ok)
+				assert.Equal(t, "x", v)
+			})
+
+			get := func(ctx context.Context, key string) any {
+				v, _ := GetScopedState(ctx, key)
+				return v
+			}
+			run("u1", "s1", func(ctx context.Context) {
+				assert.Equal(t, "hello", get(ctx, "app:greeting"))
+				assert.Equal(t, "fr", get(ctx, "user:lang"))
+				assert.Equal(t, "travel", get(ctx, "topic"))
+				assert.Nil(t, get(ctx, "temp:scratch"))
+				SetScopedState(ctx, "topic", nil)
+			})
+			run("u1", "s2", func(ctx context.Context) {
+				assert.Equal(t, "hello", get(ctx, "app:greeting"))
+				assert.Equal(t, "fr", get(ctx, "user:lang"))
+				assert.Nil(t, get(ctx, "topic"))
+			})
+			run("u2", "s3", func(ctx context.Context) {
+				assert.Equal(t, "hello", get(ctx, "app:greeting"))
+				assert.Nil(t, get(ctx, "user:lang"))
+			})
+
+			state, err := service.GetState(ctx, SessionScope{AppName: "app", UserID: "u1", SessionID: "s1"})
+			assert.NoError(t, err)
+			assert.Empty(t, state)
+			state, err = service.GetState(ctx, SessionScope{AppName: "app", UserID: "u1"})
+			assert.NoError(t, err)
+			assert.Equal(t, map[string]any{"user:lang": "fr"}, state)
+		})
+	}
+
+	// outside of a Runner run, the scoped state is unavailable
+	SetScopedState(ctx, "k", "v")
+	_, ok := GetScopedState(ctx, "k")
+	assert.False(t, ok)
+}
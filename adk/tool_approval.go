@@ -0,0 +1,104 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+func init() {
+	schema.RegisterName[*ToolApprovalRequest]("_eino_adk_tool_approval_request")
+}
+
+// ToolApprovalRequest is the payload of the interrupt raised when a tool requiring approval is called,
+// see ToolsConfig.RequireApproval.
+type ToolApprovalRequest struct {
+	ToolCallID string
+	ToolName   string
+	Arguments  string
+}
+
+// ToolApprovalResponse is the decision of a human on a ToolApprovalRequest.
+type ToolApprovalResponse struct {
+	Approved bool
+	// Reason is told to the model when the tool call is rejected.
+	Reason string
+}
+
+// WithToolApprovals passes the decisions on the pending tool calls when resuming, keyed by tool call ID.
+// A tool call without a decision interrupts again.
+// e.g.
+//
+//	for _, req := range adk.GetToolApprovalRequests(event.Action.Interrupted) {
+//		approvals[req.ToolCallID] = &adk.ToolApprovalResponse{Approved: askUser(req)}
+//	}
+//	iter, err := runner.Resume(ctx, checkPointID, adk.WithToolApprovals(approvals))
+func WithToolApprovals(responses map[string]*ToolApprovalResponse) AgentRunOption {
+	return WrapImplSpecificOptFn(func(t *chatModelAgentRunOptions) {
+		t.toolApprovals = responses
+	})
+}
+
+// GetToolApprovalRequests returns the pending tool approval requests within the interrupt info, sorted by tool call ID.
+func GetToolApprovalRequests(info *InterruptInfo) []*ToolApprovalRequest {
+	return getToolInterruptExtras(info, func(a, b *ToolApprovalRequest) bool {
+		return a.ToolCallID < b.ToolCallID
+	})
+}
+
+type toolApprovalOptions struct {
+	responses map[string]*ToolApprovalResponse
+}
+
+func withToolApprovalResponses(responses map[string]*ToolApprovalResponse) tool.Option {
+	return tool.WrapImplSpecificOptFn(func(o *toolApprovalOptions) {
+		o.responses = responses
+	})
+}
+
+// wrapToolsWithApproval wraps the tools whose names are in requireApproval, so they interrupt for approval before running.
+func wrapToolsWithApproval(ctx context.Context, tools []tool.BaseTool, requireApproval map[string]bool) ([]tool.BaseTool, error) {
+	return interceptTools(ctx, tools, requireApproval, &toolInterceptor{before: checkToolApproval})
+}
+
+// checkToolApproval runs the tool call if approved, or interrupts for approval, or returns the result of the rejected call.
+func checkToolApproval(ctx context.Context, name, argumentsInJSON string, opts []tool.Option) (rejectedResult string, rejected bool, err error) {
+	callID := compose.GetToolCallID(ctx)
+	o := tool.GetImplSpecificOptions(&toolApprovalOptions{}, opts...)
+	resp, ok := o.responses[callID]
+	if !ok || resp == nil {
+		return "", false, compose.NewInterruptAndRerunErr(&ToolApprovalRequest{
+			ToolCallID: callID,
+			ToolName:   name,
+			Arguments:  argumentsInJSON,
+		})
+	}
+	if resp.Approved {
+		return "", false, nil
+	}
+
+	result := fmt.Sprintf("the call to tool '%s' was rejected by the user", name)
+	if resp.Reason != "" {
+		result += ", reason: " + resp.Reason
+	}
+	return result, true, nil
+}
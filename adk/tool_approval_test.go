@@ -0,0 +1,129 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type countingTool struct {
+	calls int
+}
+
+func (c *countingTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "delete_file", Desc: "desc"}, nil
+}
+
+func (c *countingTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	c.calls++
+	return "deleted", nil
+}
+
+func TestToolApproval(t *testing.T) {
+	ctx := context.Background()
+
+	run := func(t *testing.T, resp *ToolApprovalResponse, expectedToolResult string, expectedCalls int) {
+		ct := &countingTool{}
+		a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+			Name:        "name",
+			Description: "description",
+			Instruction: "instruction",
+			Model: &myModel{
+				validator: func(i int, messages []*schema.Message) bool {
+					if i == 1 {
+						return messages[len(messages)-1].Content == expectedToolResult
+					}
+					return true
+				},
+				messages: []*schema.Message{
+					schema.AssistantMessage("", []schema.ToolCall{
+						{ID: "call_1", Function: schema.FunctionCall{Name: "delete_file", Arguments: `{"path":"a.txt"}`}},
+					}),
+					schema.AssistantMessage("completed", nil),
+				},
+			},
+			ToolsConfig: ToolsConfig{
+				ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{ct}},
+				RequireApproval: map[string]bool{"delete_file": true},
+			},
+		})
+		assert.NoError(t, err)
+		runner := NewRunner(ctx, RunnerConfig{Agent: a, CheckPointStore: newMyStore()})
+
+		iter := runner.Query(ctx, "delete a.txt", WithCheckPointID("1"))
+		var interrupted *AgentEvent
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+			if event.Action != nil && event.Action.Interrupted != nil {
+				interrupted = event
+			}
+		}
+		assert.NotNil(t, interrupted)
+		assert.Equal(t, 0, ct.calls)
+		reqs := GetToolApprovalRequests(interrupted.Action.Interrupted)
+		assert.Equal(t, []*ToolApprovalRequest{{ToolCallID: "call_1", ToolName: "delete_file", Arguments: `{"path":"a.txt"}`}}, reqs)
+
+		// resuming without decision interrupts again
+		iter, err = runner.Resume(ctx, "1")
+		assert.NoError(t, err)
+		interrupted = nil
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if event.Action != nil && event.Action.Interrupted != nil {
+				interrupted = event
+			}
+		}
+		assert.NotNil(t, interrupted)
+		assert.Len(t, GetToolApprovalRequests(interrupted.Action.Interrupted), 1)
+
+		iter, err = runner.Resume(ctx, "1", WithToolApprovals(map[string]*ToolApprovalResponse{"call_1": resp}))
+		assert.NoError(t, err)
+		var contents []string
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+			contents = append(contents, event.Output.MessageOutput.Message.Content)
+		}
+		assert.Equal(t, []string{expectedToolResult, "completed"}, contents)
+		assert.Equal(t, expectedCalls, ct.calls)
+	}
+
+	t.Run("approved", func(t *testing.T) {
+		run(t, &ToolApprovalResponse{Approved: true}, "deleted", 1)
+	})
+	t.Run("rejected", func(t *testing.T) {
+		run(t, &ToolApprovalResponse{Reason: "important file"},
+			"the call to tool 'delete_file' was rejected by the user, reason: important file", 0)
+	})
+}
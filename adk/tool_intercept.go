@@ -0,0 +1,196 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// toolInterceptor intercepts the calls to a tool, it's used to implement tool features in the ChatModelAgent,
+// e.g. approval and long-running tools, without touching the tools themselves.
+type toolInterceptor struct {
+	// before is called before the tool is run. If done is true, result is taken as the result of the call and the tool isn't run.
+	before func(ctx context.Context, name, argumentsInJSON string, opts []tool.Option) (result string, done bool, err error)
+	// onError converts the error returned by the tool. Optional.
+	onError func(ctx context.Context, name, argumentsInJSON string, err error) error
+	// after receives a copy of the result of the tool run, it's called in a separate goroutine for streamable tools
+	// and must consume or close the stream. Optional.
+	after func(ctx context.Context, name, argumentsInJSON string, result *schema.StreamReader[string])
+}
+
+// interceptTools wraps the tools whose names are in names with the interceptor.
+func interceptTools(ctx context.Context, tools []tool.BaseTool, names map[string]bool, interceptor *toolInterceptor) ([]tool.BaseTool, error) {
+	if len(names) == 0 {
+		return tools, nil
+	}
+
+	ret := make([]tool.BaseTool, 0, len(tools))
+	for _, t := range tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !names[info.Name] {
+			ret = append(ret, t)
+			continue
+		}
+
+		it := &interceptedTool{BaseTool: t, name: info.Name, interceptor: interceptor}
+		invokable, isInvokable := t.(tool.InvokableTool)
+		streamable, isStreamable := t.(tool.StreamableTool)
+		switch {
+		case isInvokable && isStreamable:
+			ret = append(ret, &interceptedEnhancedTool{
+				interceptedInvokableTool:  &interceptedInvokableTool{interceptedTool: it, it: invokable},
+				interceptedStreamableTool: &interceptedStreamableTool{interceptedTool: it, st: streamable},
+			})
+		case isInvokable:
+			ret = append(ret, &interceptedInvokableTool{interceptedTool: it, it: invokable})
+		case isStreamable:
+			ret = append(ret, &interceptedStreamableTool{interceptedTool: it, st: streamable})
+		default:
+			return nil, fmt.Errorf("tool[%s] is neither invokable nor streamable", info.Name)
+		}
+	}
+	return ret, nil
+}
+
+type interceptedTool struct {
+	tool.BaseTool
+	name        string
+	interceptor *toolInterceptor
+}
+
+func (i *interceptedTool) convertErr(ctx context.Context, argumentsInJSON string, err error) error {
+	if err == nil || i.interceptor.onError == nil {
+		return err
+	}
+	return i.interceptor.onError(ctx, i.name, argumentsInJSON, err)
+}
+
+type interceptedInvokableTool struct {
+	*interceptedTool
+	it tool.InvokableTool
+}
+
+func (i *interceptedInvokableTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	result, done, err := i.interceptor.before(ctx, i.name, argumentsInJSON, opts)
+	if err != nil || done {
+		return result, err
+	}
+	result, err = i.it.InvokableRun(ctx, argumentsInJSON, opts...)
+	if err != nil {
+		return "", i.convertErr(ctx, argumentsInJSON, err)
+	}
+	if i.interceptor.after != nil {
+		i.interceptor.after(ctx, i.name, argumentsInJSON, schema.StreamReaderFromArray([]string{result}))
+	}
+	return result, nil
+}
+
+type interceptedStreamableTool struct {
+	*interceptedTool
+	st tool.StreamableTool
+}
+
+func (i *interceptedStreamableTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	result, done, err := i.interceptor.before(ctx, i.name, argumentsInJSON, opts)
+	if err != nil {
+		return nil, err
+	}
+	if done {
+		return schema.StreamReaderFromArray([]string{result}), nil
+	}
+	sr, err := i.st.StreamableRun(ctx, argumentsInJSON, opts...)
+	if err != nil {
+		return nil, i.convertErr(ctx, argumentsInJSON, err)
+	}
+	if i.interceptor.after != nil {
+		ss := sr.Copy(2)
+		sr = ss[0]
+		go func() {
+			defer func() {
+				_ = recover()
+			}()
+			i.interceptor.after(ctx, i.name, argumentsInJSON, ss[1])
+		}()
+	}
+	return sr, nil
+}
+
+type interceptedEnhancedTool struct {
+	*interceptedInvokableTool
+	*interceptedStreamableTool
+}
+
+func (i *interceptedEnhancedTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return i.interceptedInvokableTool.Info(ctx)
+}
+
+// getToolInterruptExtras returns the extras of type T of the interrupted tool calls within the interrupt info,
+// walking through ChatModelAgent, workflow agents and sub graphs.
+func getToolInterruptExtras[T any](info *InterruptInfo, less func(a, b T) bool) []T {
+	var ret []T
+	var walk func(info *InterruptInfo)
+	walk = func(info *InterruptInfo) {
+		if info == nil {
+			return
+		}
+		switch data := info.Data.(type) {
+		case *ChatModelAgentInterruptInfo:
+			ret = collectToolInterruptExtras(data.Info, ret)
+		case *WorkflowInterruptInfo:
+			walk(data.SequentialInterruptInfo)
+			for _, pi := range data.ParallelInterruptInfo {
+				walk(pi)
+			}
+		}
+	}
+	walk(info)
+
+	sort.Slice(ret, func(i, j int) bool {
+		return less(ret[i], ret[j])
+	})
+	return ret
+}
+
+func collectToolInterruptExtras[T any](info *compose.InterruptInfo, ret []T) []T {
+	if info == nil {
+		return ret
+	}
+	for _, extra := range info.RerunNodesExtra {
+		te, ok := extra.(*compose.ToolsInterruptAndRerunExtra)
+		if !ok {
+			continue
+		}
+		for _, e := range te.RerunExtraMap {
+			if t, ok := e.(T); ok {
+				ret = append(ret, t)
+			}
+		}
+	}
+	for _, sub := range info.SubGraphs {
+		ret = collectToolInterruptExtras(sub, ret)
+	}
+	return ret
+}
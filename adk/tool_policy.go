@@ -0,0 +1,138 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+)
+
+// ToolPolicyEffect is the effect of a ToolPolicyDecision.
+type ToolPolicyEffect string
+
+const (
+	// ToolPolicyAllow runs the tool call.
+	ToolPolicyAllow ToolPolicyEffect = "allow"
+	// ToolPolicyDeny doesn't run the tool call, and tells the model the call is denied.
+	ToolPolicyDeny ToolPolicyEffect = "deny"
+	// ToolPolicyRequireApproval interrupts for human approval before running the tool call,
+	// the same as the tools listed in ToolsConfig.RequireApproval.
+	ToolPolicyRequireApproval ToolPolicyEffect = "require_approval"
+)
+
+// ToolPolicyDecision is the decision of a ToolPolicy on a tool call.
+type ToolPolicyDecision struct {
+	Effect ToolPolicyEffect
+	// Message is told to the model when the tool call is denied, e.g. why it's denied and what to do instead.
+	Message string
+}
+
+// ToolPolicyRequest describes the tool call to evaluate.
+type ToolPolicyRequest struct {
+	// AgentName is the name of the agent calling the tool.
+	AgentName  string
+	ToolCallID string
+	ToolName   string
+	Arguments  string
+}
+
+// ToolPolicy decides whether a tool call of an agent is allowed, denied or requires approval, before the tool is run.
+// It centralizes the rules on what agents may execute, instead of spreading them over the tools.
+// The identity of the caller, e.g. the user on whose behalf the agent runs, can be got from the ctx,
+// as the ctx passed to Runner.Run is passed down to the policy.
+// Returning an error makes the tool call fail with it.
+type ToolPolicy interface {
+	Evaluate(ctx context.Context, req *ToolPolicyRequest) (*ToolPolicyDecision, error)
+}
+
+// ToolPolicyFunc is a function implementing ToolPolicy.
+type ToolPolicyFunc func(ctx context.Context, req *ToolPolicyRequest) (*ToolPolicyDecision, error)
+
+// Evaluate calls f.
+func (f ToolPolicyFunc) Evaluate(ctx context.Context, req *ToolPolicyRequest) (*ToolPolicyDecision, error) {
+	return f(ctx, req)
+}
+
+// AllowTool returns the decision allowing the tool call.
+func AllowTool() *ToolPolicyDecision {
+	return &ToolPolicyDecision{Effect: ToolPolicyAllow}
+}
+
+// DenyTool returns the decision denying the tool call, the message is told to the model.
+func DenyTool(message string) *ToolPolicyDecision {
+	return &ToolPolicyDecision{Effect: ToolPolicyDeny, Message: message}
+}
+
+// RequireToolApproval returns the decision requiring human approval for the tool call.
+func RequireToolApproval() *ToolPolicyDecision {
+	return &ToolPolicyDecision{Effect: ToolPolicyRequireApproval}
+}
+
+// wrapToolsWithPolicy wraps all the tools, so the policy is evaluated before they run.
+func wrapToolsWithPolicy(ctx context.Context, tools []tool.BaseTool, policy ToolPolicy, agentName string) ([]tool.BaseTool, error) {
+	if policy == nil {
+		return tools, nil
+	}
+
+	names := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		info, err := t.Info(ctx)
+		if err != nil {
+			return nil, err
+		}
+		names[info.Name] = true
+	}
+
+	return interceptTools(ctx, tools, names, &toolInterceptor{
+		before: func(ctx context.Context, name, argumentsInJSON string, opts []tool.Option) (string, bool, error) {
+			return checkToolPolicy(ctx, policy, &ToolPolicyRequest{
+				AgentName:  agentName,
+				ToolCallID: compose.GetToolCallID(ctx),
+				ToolName:   name,
+				Arguments:  argumentsInJSON,
+			}, opts)
+		},
+	})
+}
+
+func checkToolPolicy(ctx context.Context, policy ToolPolicy, req *ToolPolicyRequest, opts []tool.Option) (deniedResult string, denied bool, err error) {
+	decision, err := policy.Evaluate(ctx, req)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to evaluate the policy of tool[%s]: %w", req.ToolName, err)
+	}
+	if decision == nil {
+		return "", false, nil
+	}
+
+	switch decision.Effect {
+	case ToolPolicyAllow, "":
+		return "", false, nil
+	case ToolPolicyDeny:
+		result := fmt.Sprintf("the call to tool '%s' was denied by the policy", req.ToolName)
+		if decision.Message != "" {
+			result += ", reason: " + decision.Message
+		}
+		return result, true, nil
+	case ToolPolicyRequireApproval:
+		return checkToolApproval(ctx, req.ToolName, req.Arguments, opts)
+	default:
+		return "", false, fmt.Errorf("unknown effect of the policy of tool[%s]: %s", req.ToolName, decision.Effect)
+	}
+}
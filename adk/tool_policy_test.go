@@ -0,0 +1,121 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type policyUserKey struct{}
+
+func TestToolPolicy(t *testing.T) {
+	policy := ToolPolicyFunc(func(ctx context.Context, req *ToolPolicyRequest) (*ToolPolicyDecision, error) {
+		assert.Equal(t, "name", req.AgentName)
+		assert.Equal(t, "call_1", req.ToolCallID)
+		if ctx.Value(policyUserKey{}) != "admin" {
+			return DenyTool("only admins can delete files"), nil
+		}
+		if strings.Contains(req.Arguments, "/etc/") {
+			return RequireToolApproval(), nil
+		}
+		return AllowTool(), nil
+	})
+
+	run := func(t *testing.T, user, path, expectedToolResult string, expectedCalls int) {
+		ctx := context.WithValue(context.Background(), policyUserKey{}, user)
+		ct := &countingTool{}
+		a, err := NewChatModelAgent(ctx, &ChatModelAgentConfig{
+			Name:        "name",
+			Description: "description",
+			Instruction: "instruction",
+			Model: &myModel{
+				messages: []*schema.Message{
+					schema.AssistantMessage("", []schema.ToolCall{
+						{ID: "call_1", Function: schema.FunctionCall{Name: "delete_file", Arguments: `{"path":"` + path + `"}`}},
+					}),
+					schema.AssistantMessage("completed", nil),
+				},
+			},
+			ToolsConfig: ToolsConfig{
+				ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{ct}},
+				Policy:          policy,
+			},
+		})
+		assert.NoError(t, err)
+		runner := NewRunner(ctx, RunnerConfig{Agent: a, CheckPointStore: newMyStore()})
+
+		iter := runner.Query(ctx, "delete "+path, WithCheckPointID("1"))
+		var contents []string
+		var interrupted *AgentEvent
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+			if event.Action != nil && event.Action.Interrupted != nil {
+				interrupted = event
+				continue
+			}
+			if len(event.Output.MessageOutput.Message.ToolCalls) > 0 {
+				continue
+			}
+			contents = append(contents, event.Output.MessageOutput.Message.Content)
+		}
+
+		if interrupted != nil {
+			assert.Equal(t, 0, ct.calls)
+			reqs := GetToolApprovalRequests(interrupted.Action.Interrupted)
+			assert.Len(t, reqs, 1)
+
+			iter, err = runner.Resume(ctx, "1", WithToolApprovals(map[string]*ToolApprovalResponse{
+				reqs[0].ToolCallID: {Approved: true},
+			}))
+			assert.NoError(t, err)
+			contents = nil
+			for {
+				event, ok := iter.Next()
+				if !ok {
+					break
+				}
+				assert.NoError(t, event.Err)
+				contents = append(contents, event.Output.MessageOutput.Message.Content)
+			}
+		}
+		assert.Equal(t, []string{expectedToolResult, "completed"}, contents)
+		assert.Equal(t, expectedCalls, ct.calls)
+	}
+
+	t.Run("allowed", func(t *testing.T) {
+		run(t, "admin", "/tmp/a.txt", "deleted", 1)
+	})
+	t.Run("denied", func(t *testing.T) {
+		run(t, "guest", "/tmp/a.txt",
+			"the call to tool 'delete_file' was denied by the policy, reason: only admins can delete files", 0)
+	})
+	t.Run("approval required", func(t *testing.T) {
+		run(t, "admin", "/etc/hosts", "deleted", 1)
+	})
+}
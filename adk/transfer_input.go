@@ -0,0 +1,73 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package adk
+
+import (
+	"context"
+)
+
+// TransferInfo describes a transfer from one agent to another, see TransferInputBuilder.
+type TransferInfo struct {
+	FromAgent string
+	ToAgent   string
+	// History is the full history of the run visible to the target agent, including the transfer messages.
+	History []*HistoryEntry
+	// Messages are the messages the target agent receives by default, i.e. History rewritten by its HistoryRewriter.
+	Messages []Message
+}
+
+// TransferInputBuilder builds the input messages of the agent being transferred to,
+// e.g. to pass a filtered or summarized history, or to append a handoff instruction for the target agent.
+// e.g.
+//
+//	builder := func(ctx context.Context, info *adk.TransferInfo) ([]adk.Message, error) {
+//		summary, err := summarize(ctx, info.Messages)
+//		if err != nil {
+//			return nil, err
+//		}
+//		return []adk.Message{schema.UserMessage(fmt.Sprintf("%s hands the task over to you, summary: %s", info.FromAgent, summary))}, nil
+//	}
+//	agent := adk.AgentWithOptions(ctx, agent, adk.WithTransferInputBuilder(builder))
+type TransferInputBuilder func(ctx context.Context, info *TransferInfo) ([]Message, error)
+
+// WithTransferInputBuilder customizes the input of the agents which this agent transfers to, including its sub-agents and parent.
+// It only takes effect on the agent receiving the transfer, not on the agents it transfers to further.
+func WithTransferInputBuilder(b TransferInputBuilder) AgentOption {
+	return func(fa *flowAgent) {
+		fa.transferInputBuilder = b
+	}
+}
+
+type transferInputKey struct{}
+
+type transferInput struct {
+	fromAgent string
+	builder   TransferInputBuilder
+}
+
+func withTransferInput(ctx context.Context, fromAgent string, builder TransferInputBuilder) context.Context {
+	return context.WithValue(ctx, transferInputKey{}, &transferInput{fromAgent: fromAgent, builder: builder})
+}
+
+// popTransferInput gets the transfer input of the current agent, and removes it from the ctx so that it won't be passed on.
+func popTransferInput(ctx context.Context) (context.Context, *transferInput) {
+	ti, ok := ctx.Value(transferInputKey{}).(*transferInput)
+	if !ok || ti == nil {
+		return ctx, nil
+	}
+	return context.WithValue(ctx, transferInputKey{}, (*transferInput)(nil)), ti
+}
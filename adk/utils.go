@@ -163,6 +163,7 @@ func copyAgentEvent(ae *AgentEvent) *AgentEvent {
 		RunPath:   rp,
 		Action:    ae.Action,
 		Err:       ae.Err,
+		RunUsage:  ae.RunUsage,
 	}
 
 	if ae.Output == nil {
@@ -171,6 +172,7 @@ func copyAgentEvent(ae *AgentEvent) *AgentEvent {
 
 	copied.Output = &AgentOutput{
 		CustomizedOutput: ae.Output.CustomizedOutput,
+		FinishReason:     ae.Output.FinishReason,
 	}
 
 	mv := ae.Output.MessageOutput
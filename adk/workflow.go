@@ -43,6 +43,7 @@ type workflowAgent struct {
 	mode workflowAgentMode
 
 	maxIterations int
+	exitCondition LoopExitCondition
 }
 
 func (a *workflowAgent) Name(_ context.Context) string {
@@ -142,7 +143,7 @@ type WorkflowInterruptInfo struct {
 }
 
 func (a *workflowAgent) runSequential(ctx context.Context, input *AgentInput,
-	generator *AsyncGenerator[*AgentEvent], intInfo *WorkflowInterruptInfo, iterations int /*passed by loop agent*/, opts ...AgentRunOption) (exit, interrupted bool) {
+	generator *AsyncGenerator[*AgentEvent], intInfo *WorkflowInterruptInfo, iterations int /*passed by loop agent*/, opts ...AgentRunOption) (exit, interrupted bool, lastEvent *AgentEvent) {
 	var runPath []RunStep // reconstruct RunPath each loop
 	if iterations > 0 {
 		runPath = make([]RunStep, 0, (iterations+1)*len(a.subAgents))
@@ -166,6 +167,17 @@ func (a *workflowAgent) runSequential(ctx context.Context, input *AgentInput,
 		}
 	}
 
+	send := func(e *AgentEvent) {
+		lastEvent = e
+		if a.exitCondition != nil {
+			// the stream of the event goes to the consumer, so the exit condition reads a copy of it
+			lastEvent = copyAgentEvent(e)
+			setAutomaticClose(lastEvent)
+			setAutomaticClose(e)
+		}
+		generator.Send(e)
+	}
+
 	runCtx := getRunCtx(ctx)
 	nRunCtx := runCtx.deepCopy()
 	nRunCtx.RunPath = append(nRunCtx.RunPath, runPath...)
@@ -199,12 +211,12 @@ func (a *workflowAgent) runSequential(ctx context.Context, input *AgentInput,
 
 			if event.Err != nil {
 				// exit if report error
-				generator.Send(event)
-				return true, false
+				send(event)
+				return true, false, lastEvent
 			}
 
 			if lastActionEvent != nil {
-				generator.Send(lastActionEvent)
+				send(lastActionEvent)
 				lastActionEvent = nil
 			}
 
@@ -212,7 +224,7 @@ func (a *workflowAgent) runSequential(ctx context.Context, input *AgentInput,
 				lastActionEvent = event
 				continue
 			}
-			generator.Send(event)
+			send(event)
 		}
 
 		if lastActionEvent != nil {
@@ -224,27 +236,27 @@ func (a *workflowAgent) runSequential(ctx context.Context, input *AgentInput,
 				replaceInterruptRunCtx(nCtx, runCtx)
 
 				// Forward the event
-				generator.Send(newEvent)
-				return true, true
+				send(newEvent)
+				return true, true, lastEvent
 			}
 
 			if lastActionEvent.Action.Exit {
 				// Forward the event
-				generator.Send(lastActionEvent)
-				return true, false
+				send(lastActionEvent)
+				return true, false, lastEvent
 			}
 
 			if a.doBreakLoopIfNeeded(lastActionEvent.Action, iterations) {
 				lastActionEvent.Action.BreakLoop.CurrentIterations = iterations
-				generator.Send(lastActionEvent)
-				return true, false
+				send(lastActionEvent)
+				return true, false, lastEvent
 			}
 
-			generator.Send(lastActionEvent)
+			send(lastActionEvent)
 		}
 	}
 
-	return false, false
+	return false, false, lastEvent
 }
 
 func wrapWorkflowInterrupt(e *AgentEvent, origInput *AgentInput, seqIdx int, iterations int) *AgentEvent {
@@ -319,7 +331,7 @@ func (a *workflowAgent) runLoop(ctx context.Context, input *AgentInput,
 		iterations = intInfo.LoopIterations
 	}
 	for iterations < a.maxIterations || a.maxIterations == 0 {
-		exit, interrupted := a.runSequential(ctx, input, generator, intInfo, iterations, opts...)
+		exit, interrupted, lastEvent := a.runSequential(ctx, input, generator, intInfo, iterations, opts...)
 		if interrupted {
 			return
 		}
@@ -328,6 +340,34 @@ func (a *workflowAgent) runLoop(ctx context.Context, input *AgentInput,
 		}
 		intInfo = nil // only effect once
 		iterations++
+
+		if a.exitCondition != nil {
+			done, err := a.exitCondition(ctx, lastEvent, iterations)
+			if err != nil {
+				generator.Send(&AgentEvent{AgentName: a.name, Err: fmt.Errorf("failed to check loop exit condition: %w", err)})
+				return
+			}
+			if done {
+				generator.Send(a.finishEvent(FinishStatusCompleted, "exit condition satisfied"))
+				return
+			}
+		}
+	}
+
+	if a.exitCondition != nil {
+		generator.Send(a.finishEvent(FinishStatusIncomplete, fmt.Sprintf("max iterations(%d) reached before exit condition is satisfied", a.maxIterations)))
+	}
+}
+
+func (a *workflowAgent) finishEvent(status FinishStatus, reason string) *AgentEvent {
+	return &AgentEvent{
+		AgentName: a.name,
+		Output: &AgentOutput{
+			FinishReason: &FinishReason{
+				Status: status,
+				Reason: reason,
+			},
+		},
 	}
 }
 
@@ -465,10 +505,21 @@ type LoopAgentConfig struct {
 	SubAgents   []Agent
 
 	MaxIterations int
+
+	// ExitCondition is checked after each iteration, the loop exits when it returns true.
+	// Optional. When set, the loop agent emits a last event with AgentOutput.FinishReason:
+	// FinishStatusCompleted when the condition is satisfied,
+	// or FinishStatusIncomplete when MaxIterations is reached before the condition is satisfied.
+	ExitCondition LoopExitCondition
 }
 
+// LoopExitCondition decides whether the loop agent should exit after an iteration.
+// lastEvent is the last event emitted in the iteration, iterations is the number of finished iterations.
+// The MessageStream of lastEvent is a copy of the one sent to the consumer, so the condition is free to read it.
+type LoopExitCondition func(ctx context.Context, lastEvent *AgentEvent, iterations int) (bool, error)
+
 func newWorkflowAgent(ctx context.Context, name, desc string,
-	subAgents []Agent, mode workflowAgentMode, maxIterations int) (*flowAgent, error) {
+	subAgents []Agent, mode workflowAgentMode, maxIterations int, exitCondition LoopExitCondition) (*flowAgent, error) {
 
 	wa := &workflowAgent{
 		name:        name,
@@ -476,6 +527,7 @@ func newWorkflowAgent(ctx context.Context, name, desc string,
 		mode:        mode,
 
 		maxIterations: maxIterations,
+		exitCondition: exitCondition,
 	}
 
 	fas := make([]Agent, len(subAgents))
@@ -494,13 +546,14 @@ func newWorkflowAgent(ctx context.Context, name, desc string,
 }
 
 func NewSequentialAgent(ctx context.Context, config *SequentialAgentConfig) (Agent, error) {
-	return newWorkflowAgent(ctx, config.Name, config.Description, config.SubAgents, workflowAgentModeSequential, 0)
+	return newWorkflowAgent(ctx, config.Name, config.Description, config.SubAgents, workflowAgentModeSequential, 0, nil)
 }
 
 func NewParallelAgent(ctx context.Context, config *ParallelAgentConfig) (Agent, error) {
-	return newWorkflowAgent(ctx, config.Name, config.Description, config.SubAgents, workflowAgentModeParallel, 0)
+	return newWorkflowAgent(ctx, config.Name, config.Description, config.SubAgents, workflowAgentModeParallel, 0, nil)
 }
 
 func NewLoopAgent(ctx context.Context, config *LoopAgentConfig) (Agent, error) {
-	return newWorkflowAgent(ctx, config.Name, config.Description, config.SubAgents, workflowAgentModeLoop, config.MaxIterations)
+	return newWorkflowAgent(ctx, config.Name, config.Description, config.SubAgents, workflowAgentModeLoop,
+		config.MaxIterations, config.ExitCondition)
 }
@@ -374,6 +374,87 @@ func TestLoopAgent(t *testing.T) {
 	}
 }
 
+// TestLoopAgentWithExitCondition tests the loop workflow agent with a programmable exit condition
+func TestLoopAgentWithExitCondition(t *testing.T) {
+	ctx := context.Background()
+
+	newLoopAgent := func(maxIterations int, exitAt int) Agent {
+		agent := newMockAgent("Worker", "Worker agent", []*AgentEvent{
+			{
+				AgentName: "Worker",
+				Output: &AgentOutput{
+					MessageOutput: &MessageVariant{
+						Message: schema.AssistantMessage("Loop iteration", nil),
+						Role:    schema.Assistant,
+					},
+				},
+			},
+		})
+		loopAgent, err := NewLoopAgent(ctx, &LoopAgentConfig{
+			Name:          "LoopTestAgent",
+			Description:   "Test loop agent",
+			SubAgents:     []Agent{agent},
+			MaxIterations: maxIterations,
+			ExitCondition: func(ctx context.Context, lastEvent *AgentEvent, iterations int) (bool, error) {
+				assert.Equal(t, "Loop iteration", lastEvent.Output.MessageOutput.Message.Content)
+				return iterations == exitAt, nil
+			},
+		})
+		assert.NoError(t, err)
+		return loopAgent
+	}
+
+	collect := func(agent Agent) []*AgentEvent {
+		iterator := agent.Run(ctx, &AgentInput{Messages: []Message{schema.UserMessage("Test input")}})
+		var events []*AgentEvent
+		for {
+			event, ok := iterator.Next()
+			if !ok {
+				break
+			}
+			assert.NoError(t, event.Err)
+			events = append(events, event)
+		}
+		return events
+	}
+
+	events := collect(newLoopAgent(5, 2))
+	assert.Equal(t, 3, len(events))
+	assert.Equal(t, "LoopTestAgent", events[2].AgentName)
+	assert.Equal(t, FinishStatusCompleted, events[2].Output.FinishReason.Status)
+
+	events = collect(newLoopAgent(3, 10))
+	assert.Equal(t, 4, len(events))
+	assert.Equal(t, FinishStatusIncomplete, events[3].Output.FinishReason.Status)
+	assert.Contains(t, events[3].Output.FinishReason.Reason, "max iterations(3) reached")
+
+	// both the exit condition and the consumer read the whole stream of the last event
+	streamAgent := newMockAgent("Worker", "Worker agent", []*AgentEvent{
+		EventFromMessage(nil, schema.StreamReaderFromArray([]Message{
+			schema.AssistantMessage("Loop ", nil),
+			schema.AssistantMessage("iteration", nil),
+		}), schema.Assistant, ""),
+	})
+	loopAgent, err := NewLoopAgent(ctx, &LoopAgentConfig{
+		Name:          "LoopTestAgent",
+		SubAgents:     []Agent{streamAgent},
+		MaxIterations: 1,
+		ExitCondition: func(ctx context.Context, lastEvent *AgentEvent, iterations int) (bool, error) {
+			msg, err := schema.ConcatMessageStream(lastEvent.Output.MessageOutput.MessageStream)
+			assert.NoError(t, err)
+			assert.Equal(t, "Loop iteration", msg.Content)
+			return true, nil
+		},
+	})
+	assert.NoError(t, err)
+	events = collect(loopAgent)
+	assert.Equal(t, 2, len(events))
+	msg, err := schema.ConcatMessageStream(events[0].Output.MessageOutput.MessageStream)
+	assert.NoError(t, err)
+	assert.Equal(t, "Loop iteration", msg.Content)
+	assert.Equal(t, FinishStatusCompleted, events[1].Output.FinishReason.Status)
+}
+
 // TestLoopAgentWithBreakLoop tests the loop workflow agent with an break loop action
 func TestLoopAgentWithBreakLoop(t *testing.T) {
 	ctx := context.Background()
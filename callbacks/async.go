@@ -0,0 +1,263 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+// AsyncConfig is the config of the async handler.
+type AsyncConfig struct {
+	// QueueSize is the capacity of the queue of the pending events, 1024 by default.
+	QueueSize int
+	// Workers is the number of goroutines running the handler, 1 by default, which keeps the events in order.
+	Workers int
+	// BlockOnFull makes the callbacks wait for room in the queue, instead of dropping the event.
+	BlockOnFull bool
+	// OnDrop is called with the timing of each event dropped as the queue is full or the handler is closed. Optional.
+	OnDrop func(timing CallbackTiming)
+	// OnPanic is called with the panic recovered from the handler, the worker keeps running. Optional.
+	OnPanic func(err error)
+}
+
+// AsyncHandler runs a handler in background goroutines with a bounded queue, so slow handlers,
+// e.g. exporting to a remote log sink, don't add latency to the components.
+// The context returned by the handler is discarded, so handlers passing values through the context must stay synchronous.
+// The handler receives a context keeping the values, but not the cancellation, of the context of the callback.
+// Call Close to flush the pending events on shutdown.
+type AsyncHandler struct {
+	handler Handler
+	config  AsyncConfig
+	queue   chan *asyncEvent
+
+	closeMu sync.RWMutex
+	closed  bool
+	wg      sync.WaitGroup
+	dropped int64
+}
+
+type asyncEvent struct {
+	ctx    context.Context
+	info   *RunInfo
+	timing CallbackTiming
+	input  CallbackInput
+	output CallbackOutput
+	err    error
+
+	metrics      *StreamMetrics
+	inputStream  *schema.StreamReader[CallbackInput]
+	outputStream *schema.StreamReader[CallbackOutput]
+}
+
+// NewAsyncHandler creates an async handler running the handler, config is optional.
+// e.g.
+//
+//	exporter := callbacks.NewAsyncHandler(httpLogHandler, &callbacks.AsyncConfig{QueueSize: 4096})
+//	defer exporter.Close(shutdownCtx)
+//	callbacks.AppendGlobalHandlers(exporter)
+func NewAsyncHandler(handler Handler, config *AsyncConfig) *AsyncHandler {
+	var conf AsyncConfig
+	if config != nil {
+		conf = *config
+	}
+	if conf.QueueSize <= 0 {
+		conf.QueueSize = 1024
+	}
+	if conf.Workers <= 0 {
+		conf.Workers = 1
+	}
+
+	h := &AsyncHandler{
+		handler: handler,
+		config:  conf,
+		queue:   make(chan *asyncEvent, conf.QueueSize),
+	}
+	h.wg.Add(conf.Workers)
+	for i := 0; i < conf.Workers; i++ {
+		go h.work()
+	}
+	return h
+}
+
+func (h *AsyncHandler) OnStart(ctx context.Context, info *RunInfo, input CallbackInput) context.Context {
+	h.enqueue(&asyncEvent{ctx: ctx, info: info, timing: TimingOnStart, input: input})
+	return ctx
+}
+
+func (h *AsyncHandler) OnEnd(ctx context.Context, info *RunInfo, output CallbackOutput) context.Context {
+	h.enqueue(&asyncEvent{ctx: ctx, info: info, timing: TimingOnEnd, output: output})
+	return ctx
+}
+
+func (h *AsyncHandler) OnError(ctx context.Context, info *RunInfo, err error) context.Context {
+	h.enqueue(&asyncEvent{ctx: ctx, info: info, timing: TimingOnError, err: err})
+	return ctx
+}
+
+// OnStartWithStreamInput queues the stream, which is buffered until the handler reads it, or closed if the event is dropped.
+func (h *AsyncHandler) OnStartWithStreamInput(ctx context.Context, info *RunInfo,
+	input *schema.StreamReader[CallbackInput]) context.Context {
+
+	h.enqueue(&asyncEvent{ctx: ctx, info: info, timing: TimingOnStartWithStreamInput, inputStream: input})
+	return ctx
+}
+
+// OnEndWithStreamOutput queues the stream, which is buffered until the handler reads it, or closed if the event is dropped.
+func (h *AsyncHandler) OnEndWithStreamOutput(ctx context.Context, info *RunInfo,
+	output *schema.StreamReader[CallbackOutput]) context.Context {
+
+	h.enqueue(&asyncEvent{ctx: ctx, info: info, timing: TimingOnEndWithStreamOutput, outputStream: output})
+	return ctx
+}
+
+// OnStreamMetrics queues the metrics if the handler is a StreamMetricsHandler.
+func (h *AsyncHandler) OnStreamMetrics(ctx context.Context, info *RunInfo, metrics *StreamMetrics) {
+	if _, ok := h.handler.(StreamMetricsHandler); ok {
+		h.enqueue(&asyncEvent{ctx: ctx, info: info, timing: TimingOnStreamMetrics, metrics: metrics})
+	}
+}
+
+// Needed delegates to the handler if it's a TimingChecker, so the events not needed are not queued.
+func (h *AsyncHandler) Needed(ctx context.Context, info *RunInfo, timing CallbackTiming) bool {
+	if _, ok := h.handler.(StreamMetricsHandler); !ok && timing == TimingOnStreamMetrics {
+		return false
+	}
+	if checker, ok := h.handler.(TimingChecker); ok {
+		return checker.Needed(ctx, info, timing)
+	}
+	return true
+}
+
+// Dropped returns the number of the events dropped.
+func (h *AsyncHandler) Dropped() int64 {
+	return atomic.LoadInt64(&h.dropped)
+}
+
+// Close stops accepting events, and waits for the pending events to be handled until the ctx is done.
+// The events after Close are dropped.
+func (h *AsyncHandler) Close(ctx context.Context) error {
+	h.closeMu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.queue)
+	}
+	h.closeMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		h.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (h *AsyncHandler) enqueue(e *asyncEvent) {
+	e.ctx = detachedContext{e.ctx}
+
+	h.closeMu.RLock()
+	defer h.closeMu.RUnlock()
+	if h.closed {
+		h.drop(e)
+		return
+	}
+	if h.config.BlockOnFull {
+		h.queue <- e
+		return
+	}
+	select {
+	case h.queue <- e:
+	default:
+		h.drop(e)
+	}
+}
+
+func (h *AsyncHandler) drop(e *asyncEvent) {
+	if e.inputStream != nil {
+		e.inputStream.Close()
+	}
+	if e.outputStream != nil {
+		e.outputStream.Close()
+	}
+	atomic.AddInt64(&h.dropped, 1)
+	if h.config.OnDrop != nil {
+		h.config.OnDrop(e.timing)
+	}
+}
+
+func (h *AsyncHandler) work() {
+	defer h.wg.Done()
+	for e := range h.queue {
+		h.handle(e)
+	}
+}
+
+func (h *AsyncHandler) handle(e *asyncEvent) {
+	defer func() {
+		if panicInfo := recover(); panicInfo != nil && h.config.OnPanic != nil {
+			h.config.OnPanic(safe.NewPanicErr(panicInfo, debug.Stack()))
+		}
+	}()
+
+	switch e.timing {
+	case TimingOnStart:
+		h.handler.OnStart(e.ctx, e.info, e.input)
+	case TimingOnEnd:
+		h.handler.OnEnd(e.ctx, e.info, e.output)
+	case TimingOnError:
+		h.handler.OnError(e.ctx, e.info, e.err)
+	case TimingOnStartWithStreamInput:
+		h.handler.OnStartWithStreamInput(e.ctx, e.info, e.inputStream)
+	case TimingOnEndWithStreamOutput:
+		h.handler.OnEndWithStreamOutput(e.ctx, e.info, e.outputStream)
+	case TimingOnStreamMetrics:
+		h.handler.(StreamMetricsHandler).OnStreamMetrics(e.ctx, e.info, e.metrics)
+	}
+}
+
+// detachedContext keeps the values of the parent, but not its deadline and cancellation,
+// as the events are handled after the components return.
+type detachedContext struct {
+	parent context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key any) any {
+	return c.parent.Value(key)
+}
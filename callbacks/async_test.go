@@ -0,0 +1,137 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestAsyncHandler(t *testing.T) {
+	type ctxKey struct{}
+
+	t.Run("handle in background", func(t *testing.T) {
+		var mu sync.Mutex
+		var events []string
+		var chunks []CallbackOutput
+		record := func(e string) {
+			mu.Lock()
+			defer mu.Unlock()
+			events = append(events, e)
+		}
+		inner := NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *RunInfo, input CallbackInput) context.Context {
+				record("start:" + input.(string) + ":" + ctx.Value(ctxKey{}).(string))
+				assert.NoError(t, ctx.Err())
+				return ctx
+			}).
+			OnErrorFn(func(ctx context.Context, info *RunInfo, err error) context.Context {
+				record("error:" + err.Error())
+				return ctx
+			}).
+			OnEndWithStreamOutputFn(func(ctx context.Context, info *RunInfo, output *schema.StreamReader[CallbackOutput]) context.Context {
+				defer output.Close()
+				for {
+					chunk, err := output.Recv()
+					if err == io.EOF {
+						break
+					}
+					chunks = append(chunks, chunk)
+				}
+				record("stream")
+				return ctx
+			}).Build()
+
+		h := NewAsyncHandler(inner, nil)
+		assert.True(t, h.Needed(context.Background(), nil, TimingOnStart))
+		assert.False(t, h.Needed(context.Background(), nil, TimingOnEnd))
+
+		ctx, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "v"))
+		ctx = InitCallbacks(ctx, &RunInfo{Name: "node"}, h)
+		ctx = OnStart(ctx, "input")
+		_ = OnError(ctx, errors.New("failed"))
+		sr, sw := schema.Pipe[string](2)
+		sw.Send("a", nil)
+		sw.Send("b", nil)
+		sw.Close()
+		_, sr = OnEndWithStreamOutput(ctx, sr)
+		sr.Close()
+		cancel()
+
+		assert.NoError(t, h.Close(context.Background()))
+		assert.Equal(t, []string{"start:input:v", "error:failed", "stream"}, events)
+		assert.Equal(t, []CallbackOutput{"a", "b"}, chunks)
+		assert.Equal(t, int64(0), h.Dropped())
+
+		// the events after close are dropped
+		OnStart(ctx, "late")
+		assert.Equal(t, int64(1), h.Dropped())
+		assert.NoError(t, h.Close(context.Background()))
+	})
+
+	t.Run("drop on full", func(t *testing.T) {
+		block := make(chan struct{})
+		started := make(chan struct{}, 1)
+		inner := NewHandlerBuilder().
+			OnEndFn(func(ctx context.Context, info *RunInfo, output CallbackOutput) context.Context {
+				started <- struct{}{}
+				<-block
+				return ctx
+			}).Build()
+		var dropped []CallbackTiming
+		h := NewAsyncHandler(inner, &AsyncConfig{
+			QueueSize: 1,
+			OnDrop: func(timing CallbackTiming) {
+				dropped = append(dropped, timing)
+			},
+		})
+
+		ctx := InitCallbacks(context.Background(), &RunInfo{}, h)
+		OnEnd(ctx, 1)
+		<-started
+		OnEnd(ctx, 2) // queued
+		OnEnd(ctx, 3) // dropped
+		assert.Equal(t, []CallbackTiming{TimingOnEnd}, dropped)
+
+		closeCtx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, h.Close(closeCtx), context.Canceled)
+		close(block)
+		<-started
+		assert.NoError(t, h.Close(context.Background()))
+		assert.Equal(t, int64(1), h.Dropped())
+	})
+
+	t.Run("recover panic", func(t *testing.T) {
+		var panicErr error
+		inner := NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *RunInfo, input CallbackInput) context.Context {
+				panic("boom")
+			}).Build()
+		h := NewAsyncHandler(inner, &AsyncConfig{OnPanic: func(err error) { panicErr = err }})
+		OnStart(InitCallbacks(context.Background(), &RunInfo{}, h), "input")
+		assert.NoError(t, h.Close(context.Background()))
+		assert.ErrorContains(t, panicErr, "boom")
+	})
+}
@@ -83,6 +83,7 @@
 //   - Embedding components (via embedding.CallbackHandler)
 //   - Indexer components (via indexer.CallbackHandler)
 //   - Retriever components (via retriever.CallbackHandler)
+//   - Reranker components (via reranker.CallbackHandler)
 //   - Document loader components (via loader.CallbackHandler)
 //   - Document transformer components (via transformer.CallbackHandler)
 //   - Tool components (via tool.CallbackHandler)
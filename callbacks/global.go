@@ -0,0 +1,84 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/internal/callbacks"
+)
+
+// GlobalHandlerOption is the option of RegisterGlobalHandler.
+type GlobalHandlerOption func(o *globalHandlerOptions)
+
+type globalHandlerOptions struct {
+	order     int
+	mandatory bool
+}
+
+// WithGlobalHandlerOrder sets the order of the handler among the registered global handlers. Optional, defaults to 0.
+// The handlers are sorted by the order, then by the registration, and the later one wraps the former ones,
+// i.e. its OnStart runs before and its OnEnd runs after theirs.
+func WithGlobalHandlerOrder(order int) GlobalHandlerOption {
+	return func(o *globalHandlerOptions) {
+		o.order = order
+	}
+}
+
+// WithGlobalHandlerMandatory makes the handler ignore the per-run suppression and replacement,
+// e.g. for the baseline observability enforced by the platform.
+func WithGlobalHandlerMandatory() GlobalHandlerOption {
+	return func(o *globalHandlerOptions) {
+		o.mandatory = true
+	}
+}
+
+// RegisterGlobalHandler registers a named handler applied to every run of all the components and compiled Runnables,
+// replacing the handler registered with the same name.
+// Unlike AppendGlobalHandlers, it's safe to call concurrently, and takes effect on the runs started afterward.
+// The registered handlers run after the handlers added by AppendGlobalHandlers, and before the handlers of the run in OnStart.
+// The handler can be suppressed or replaced in a run by WithGlobalHandlersSuppressed and WithGlobalHandlerReplaced,
+// or compose.WithGlobalCallbacksSuppressed and compose.WithGlobalCallbackReplaced, unless it's mandatory.
+// e.g.
+//
+//	callbacks.RegisterGlobalHandler("tracing", tracingHandler, callbacks.WithGlobalHandlerMandatory())
+//	callbacks.RegisterGlobalHandler("metrics", metricsHandler, callbacks.WithGlobalHandlerOrder(10))
+func RegisterGlobalHandler(name string, handler Handler, opts ...GlobalHandlerOption) {
+	o := &globalHandlerOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	callbacks.RegisterGlobalHandler(name, handler, o.order, o.mandatory)
+}
+
+// UnregisterGlobalHandler removes the handler registered by RegisterGlobalHandler, returns false if not found.
+func UnregisterGlobalHandler(name string) bool {
+	return callbacks.UnregisterGlobalHandler(name)
+}
+
+// WithGlobalHandlersSuppressed returns a context in which the named global handlers don't run,
+// for the components and the Runnables whose callbacks are initialized with the ctx.
+func WithGlobalHandlersSuppressed(ctx context.Context, names ...string) context.Context {
+	return callbacks.WithGlobalHandlerOverrides(ctx, names, nil)
+}
+
+// WithGlobalHandlerReplaced returns a context in which handler runs in place of the named global handler,
+// for the components and the Runnables whose callbacks are initialized with the ctx.
+// It takes the position of the replaced one, and it's ignored if no handler is registered with the name.
+func WithGlobalHandlerReplaced(ctx context.Context, name string, handler Handler) context.Context {
+	return callbacks.WithGlobalHandlerOverrides(ctx, nil, map[string]Handler{name: handler})
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterGlobalHandler(t *testing.T) {
+	var started []string
+	record := func(name string) Handler {
+		return NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *RunInfo, input CallbackInput) context.Context {
+			started = append(started, name)
+			return ctx
+		}).Build()
+	}
+	run := func(ctx context.Context) []string {
+		started = nil
+		OnStart(InitCallbacks(ctx, &RunInfo{Name: "node"}, record("run")), "input")
+		return started
+	}
+
+	RegisterGlobalHandler("metrics", record("metrics"), WithGlobalHandlerOrder(10))
+	RegisterGlobalHandler("tracing", record("tracing"), WithGlobalHandlerMandatory())
+	RegisterGlobalHandler("audit", record("audit"))
+	defer func() {
+		UnregisterGlobalHandler("metrics")
+		UnregisterGlobalHandler("tracing")
+		UnregisterGlobalHandler("audit")
+	}()
+
+	ctx := context.Background()
+	// the later one in the order wraps the former ones
+	assert.Equal(t, []string{"metrics", "audit", "tracing", "run"}, run(ctx))
+
+	RegisterGlobalHandler("audit", record("audit2"), WithGlobalHandlerOrder(20))
+	assert.Equal(t, []string{"audit2", "metrics", "tracing", "run"}, run(ctx))
+
+	suppressed := WithGlobalHandlersSuppressed(ctx, "metrics", "tracing")
+	assert.Equal(t, []string{"audit2", "tracing", "run"}, run(suppressed))
+
+	replaced := WithGlobalHandlerReplaced(suppressed, "metrics", record("sampled"))
+	replaced = WithGlobalHandlerReplaced(replaced, "tracing", record("ignored"))
+	replaced = WithGlobalHandlerReplaced(replaced, "unknown", record("ignored"))
+	assert.Equal(t, []string{"audit2", "sampled", "tracing", "run"}, run(replaced))
+
+	assert.True(t, UnregisterGlobalHandler("audit"))
+	assert.False(t, UnregisterGlobalHandler("audit"))
+	assert.Equal(t, []string{"metrics", "tracing", "run"}, run(ctx))
+}
@@ -17,6 +17,10 @@
 package callbacks
 
 import (
+	"context"
+	"time"
+
+	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/internal/callbacks"
 )
 
@@ -69,11 +73,13 @@ func AppendGlobalHandlers(handlers ...Handler) {
 type CallbackTiming = callbacks.CallbackTiming
 
 const (
-	TimingOnStart CallbackTiming = iota
-	TimingOnEnd
-	TimingOnError
-	TimingOnStartWithStreamInput
-	TimingOnEndWithStreamOutput
+	TimingOnStart                = callbacks.TimingOnStart
+	TimingOnEnd                  = callbacks.TimingOnEnd
+	TimingOnError                = callbacks.TimingOnError
+	TimingOnStartWithStreamInput = callbacks.TimingOnStartWithStreamInput
+	TimingOnEndWithStreamOutput  = callbacks.TimingOnEndWithStreamOutput
+	// TimingOnStreamMetrics is the timing of StreamMetricsHandler.OnStreamMetrics.
+	TimingOnStreamMetrics = callbacks.TimingOnStreamMetrics
 )
 
 // TimingChecker checks if the handler is needed for the given callback aspect timing.
@@ -82,3 +88,68 @@ const (
 // Eino's callback mechanism will try to use this interface to determine whether any handlers are needed for the given timing.
 // Also, the callback handler that is not needed for that timing will be skipped.
 type TimingChecker = callbacks.TimingChecker
+
+// StreamMetrics is the metrics of a stream output, measured by the framework, see StreamMetricsHandler.
+type StreamMetrics = callbacks.StreamMetrics
+
+// StreamMetricsHandler is an optional interface of Handler, receiving the metrics of each stream output,
+// e.g. the time to first token of a chat model, without copying and consuming the streams by the handler itself.
+// OnStreamMetrics is called in a background goroutine once the stream output ends, the handler doesn't need to handle OnEndWithStreamOutput.
+// e.g.
+//
+//	type metricsHandler struct {
+//		callbacks.Handler // e.g. callbacks.NewHandlerBuilder().Build()
+//	}
+//
+//	func (h *metricsHandler) OnStreamMetrics(ctx context.Context, info *callbacks.RunInfo, metrics *callbacks.StreamMetrics) {
+//		ttft.WithLabelValues(info.Name).Observe(metrics.TimeToFirstChunk.Seconds())
+//	}
+type StreamMetricsHandler = callbacks.StreamMetricsHandler
+
+// StreamChunkSizer is implemented by the chunks of the stream outputs to count their sizes in StreamMetrics.Bytes,
+// the sizes of string, []byte and *schema.Message chunks are counted without it.
+type StreamChunkSizer = callbacks.StreamChunkSizer
+
+// RunTiming is the timing of a running component measured by the framework, consistent for all the handlers.
+type RunTiming = callbacks.RunTiming
+
+// GetRunTiming returns the timing of the running component in the handler.
+// StartTime, Attempt and QueueWait are set from OnStart, Duration is set on OnEnd, OnError and OnEndWithStreamOutput.
+// e.g.
+//
+//	func (h *handler) OnEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+//		if t, ok := callbacks.GetRunTiming(ctx); ok {
+//			h.record(info.Name, t.Duration, t.Attempt, t.QueueWait)
+//		}
+//		return ctx
+//	}
+func GetRunTiming(ctx context.Context) (*RunTiming, bool) {
+	return callbacks.GetRunTiming(ctx)
+}
+
+// WithRunAttempt reports the 1-based attempt number of the component to start with the ctx,
+// used by the components retrying the calls of other components.
+func WithRunAttempt(ctx context.Context, attempt int) context.Context {
+	return callbacks.WithRunAttempt(ctx, attempt)
+}
+
+// WithQueueWait reports the time waited before the component to start with the ctx, e.g. for a limiter.
+func WithQueueWait(ctx context.Context, d time.Duration) context.Context {
+	return callbacks.WithQueueWait(ctx, d)
+}
+
+// ErrorCategory is the standard category of the errors of components, see components.ClassifiedError.
+type ErrorCategory = components.ErrorCategory
+
+// GetErrorCategory returns the category of the error in OnError, classified by components.ClassifyError.
+// e.g.
+//
+//	func (h *handler) OnError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+//		if c, _ := callbacks.GetErrorCategory(ctx); c == components.ErrorCategoryRateLimited {
+//			h.alertThrottled(info.Name)
+//		}
+//		return ctx
+//	}
+func GetErrorCategory(ctx context.Context) (ErrorCategory, bool) {
+	return callbacks.GetErrorCategory(ctx)
+}
@@ -18,11 +18,15 @@ package callbacks
 
 import (
 	"context"
+	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/internal/callbacks"
+	"github.com/cloudwego/eino/schema"
 )
 
 func TestAppendGlobalHandlers(t *testing.T) {
@@ -54,3 +58,150 @@ func TestAppendGlobalHandlers(t *testing.T) {
 	AppendGlobalHandlers([]Handler{}...)
 	assert.Equal(t, 2, len(callbacks.GlobalHandlers))
 }
+
+type metricsOnlyHandler struct {
+	Handler
+	metrics chan *StreamMetrics
+}
+
+func (h *metricsOnlyHandler) OnStreamMetrics(_ context.Context, info *RunInfo, metrics *StreamMetrics) {
+	h.metrics <- metrics
+}
+
+func (h *metricsOnlyHandler) Needed(_ context.Context, _ *RunInfo, timing CallbackTiming) bool {
+	return timing == TimingOnStreamMetrics
+}
+
+func TestStreamMetrics(t *testing.T) {
+	var streamed []CallbackOutput
+	streamHandler := NewHandlerBuilder().
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *RunInfo, output *schema.StreamReader[CallbackOutput]) context.Context {
+			defer output.Close()
+			for {
+				chunk, err := output.Recv()
+				if err != nil {
+					break
+				}
+				streamed = append(streamed, chunk)
+			}
+			return ctx
+		}).Build()
+	h := &metricsOnlyHandler{Handler: NewHandlerBuilder().Build(), metrics: make(chan *StreamMetrics, 1)}
+
+	ctx := InitCallbacks(context.Background(), &RunInfo{Name: "model"}, streamHandler, h)
+	ctx = OnStart(ctx, "input")
+
+	sr, sw := schema.Pipe[*schema.Message](2)
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		sw.Send(schema.AssistantMessage("hello", nil), nil)
+		sw.Send(schema.AssistantMessage(" world", nil), nil)
+		sw.Close()
+	}()
+	_, sr = OnEndWithStreamOutput(ctx, sr)
+
+	var chunks []string
+	for {
+		chunk, err := sr.Recv()
+		if err != nil {
+			break
+		}
+		chunks = append(chunks, chunk.Content)
+	}
+	sr.Close()
+	assert.Equal(t, []string{"hello", " world"}, chunks)
+	assert.Len(t, streamed, 2)
+
+	metrics := <-h.metrics
+	assert.Equal(t, 2, metrics.ChunkCount)
+	assert.Equal(t, 11, metrics.Bytes)
+	assert.NoError(t, metrics.Err)
+	assert.GreaterOrEqual(t, metrics.TimeToFirstChunk, 10*time.Millisecond)
+	assert.GreaterOrEqual(t, metrics.Duration, metrics.TimeToFirstChunk)
+
+	t.Run("scoped and async", func(t *testing.T) {
+		h := &metricsOnlyHandler{Handler: NewHandlerBuilder().Build(), metrics: make(chan *StreamMetrics, 1)}
+		async := NewAsyncHandler(NewScopedHandler(h, Scope{Names: []string{"model"}}), nil)
+		ctx := InitCallbacks(context.Background(), &RunInfo{Name: "model"}, async)
+		_, sr := OnEndWithStreamOutput(ctx, schema.StreamReaderFromArray([]string{"a", "bc"}))
+		sr.Close()
+		metrics := <-h.metrics
+		assert.Equal(t, 2, metrics.ChunkCount)
+		assert.Equal(t, 3, metrics.Bytes)
+		assert.NoError(t, async.Close(context.Background()))
+
+		// the streams of the components out of the scope are not measured
+		ctx = InitCallbacks(context.Background(), &RunInfo{Name: "retriever"}, async)
+		assert.False(t, async.Needed(ctx, &RunInfo{Name: "retriever"}, TimingOnStreamMetrics))
+	})
+}
+
+func TestRunTiming(t *testing.T) {
+	var started, ended, nested *RunTiming
+	handler := NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *RunInfo, input CallbackInput) context.Context {
+			tm, ok := GetRunTiming(ctx)
+			assert.True(t, ok)
+			if info.Name == "nested" {
+				nested = tm
+			} else {
+				started = tm
+			}
+			return ctx
+		}).
+		OnErrorFn(func(ctx context.Context, info *RunInfo, err error) context.Context {
+			ended, _ = GetRunTiming(ctx)
+			return ctx
+		}).Build()
+
+	ctx := WithRunAttempt(context.Background(), 2)
+	ctx = WithQueueWait(ctx, 5*time.Millisecond)
+	ctx = WithQueueWait(ctx, 5*time.Millisecond)
+	ctx = InitCallbacks(ctx, &RunInfo{Name: "model"}, handler)
+	ctx = OnStart(ctx, "input")
+
+	// the pending timing is consumed by the first OnStart
+	nestedCtx := ReuseHandlers(ctx, &RunInfo{Name: "nested"})
+	OnStart(nestedCtx, "input")
+
+	time.Sleep(10 * time.Millisecond)
+	OnError(ctx, assert.AnError)
+
+	assert.Equal(t, 2, started.Attempt)
+	assert.Equal(t, 10*time.Millisecond, started.QueueWait)
+	assert.Zero(t, started.Duration)
+	assert.Equal(t, 0, nested.Attempt)
+	assert.Zero(t, nested.QueueWait)
+
+	assert.Equal(t, started.StartTime, ended.StartTime)
+	assert.Equal(t, 2, ended.Attempt)
+	assert.GreaterOrEqual(t, ended.Duration, 10*time.Millisecond)
+}
+
+func TestErrorCategory(t *testing.T) {
+	var categories []ErrorCategory
+	handler := NewHandlerBuilder().
+		OnErrorFn(func(ctx context.Context, info *RunInfo, err error) context.Context {
+			c, ok := GetErrorCategory(ctx)
+			assert.True(t, ok)
+			categories = append(categories, c)
+			return ctx
+		}).Build()
+
+	ctx := InitCallbacks(context.Background(), &RunInfo{Name: "model"}, handler)
+	OnError(ctx, components.NewClassifiedError(assert.AnError, components.ErrorCategoryContentFiltered))
+	OnError(ctx, fmt.Errorf("wrapped: %w", components.NewClassifiedError(assert.AnError, components.ErrorCategoryProviderUnavailable)))
+	OnError(ctx, fmt.Errorf("call: %w", context.DeadlineExceeded))
+	OnError(ctx, assert.AnError)
+
+	assert.Equal(t, []ErrorCategory{
+		components.ErrorCategoryContentFiltered,
+		components.ErrorCategoryProviderUnavailable,
+		components.ErrorCategoryTimeout,
+		components.ErrorCategoryUnknown,
+	}, categories)
+
+	err := components.NewClassifiedError(assert.AnError, components.ErrorCategoryInvalidInput)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Nil(t, components.NewClassifiedError(nil, components.ErrorCategoryInvalidInput))
+}
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/internal/callbacks"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Scope selects the components a scoped handler applies to.
+// A field matches if any of its values matches, and the empty fields match all, a component is selected if all the fields match.
+type Scope struct {
+	// Names are the globs of RunInfo.Name, i.e. the names set by compose.WithNodeName, e.g. "retrieve_*", see path.Match.
+	Names []string
+	// Components are the component kinds of RunInfo.Component, e.g. components.ComponentOfChatModel.
+	Components []components.Component
+	// Types are the implementation types of RunInfo.Type, e.g. "OpenAI".
+	Types []string
+	// Paths are the globs of the graph node paths, made of the node keys from the top graph joined by "/", see path.Match.
+	// A glob also matches everything inside the nodes it matches, e.g. "rag" matches the subgraph node "rag" and all the nodes in it.
+	Paths []string
+	// Labels are the labels of the running component, set by compose.WithNodeLabels or WithLabels.
+	Labels []string
+}
+
+// WithLabels returns a context labelling the components running with it, including the nodes of the graphs invoked with it,
+// to select the components of the handlers scoped by Scope.Labels.
+func WithLabels(ctx context.Context, labels ...string) context.Context {
+	return callbacks.WithLabels(ctx, labels...)
+}
+
+// NewScopedHandler creates a handler applying the handler only to the components in the scope, evaluated on each callback.
+// e.g.
+//
+//	// only the chat models in the subgraph node "rag"
+//	handler := callbacks.NewScopedHandler(tokenUsageHandler, callbacks.Scope{
+//		Components: []components.Component{components.ComponentOfChatModel},
+//		Paths:      []string{"rag"},
+//	})
+//	out, err := runnable.Invoke(ctx, input, compose.WithCallbacks(handler))
+func NewScopedHandler(handler Handler, scope Scope) Handler {
+	return &scopedHandler{handler: handler, scope: scope}
+}
+
+type scopedHandler struct {
+	handler Handler
+	scope   Scope
+}
+
+func (s *scopedHandler) OnStart(ctx context.Context, info *RunInfo, input CallbackInput) context.Context {
+	if !s.scope.match(ctx, info) {
+		return ctx
+	}
+	return s.handler.OnStart(ctx, info, input)
+}
+
+func (s *scopedHandler) OnEnd(ctx context.Context, info *RunInfo, output CallbackOutput) context.Context {
+	if !s.scope.match(ctx, info) {
+		return ctx
+	}
+	return s.handler.OnEnd(ctx, info, output)
+}
+
+func (s *scopedHandler) OnError(ctx context.Context, info *RunInfo, err error) context.Context {
+	if !s.scope.match(ctx, info) {
+		return ctx
+	}
+	return s.handler.OnError(ctx, info, err)
+}
+
+func (s *scopedHandler) OnStartWithStreamInput(ctx context.Context, info *RunInfo,
+	input *schema.StreamReader[CallbackInput]) context.Context {
+
+	if !s.scope.match(ctx, info) {
+		input.Close()
+		return ctx
+	}
+	return s.handler.OnStartWithStreamInput(ctx, info, input)
+}
+
+func (s *scopedHandler) OnEndWithStreamOutput(ctx context.Context, info *RunInfo,
+	output *schema.StreamReader[CallbackOutput]) context.Context {
+
+	if !s.scope.match(ctx, info) {
+		output.Close()
+		return ctx
+	}
+	return s.handler.OnEndWithStreamOutput(ctx, info, output)
+}
+
+// OnStreamMetrics delegates to the handler if it's a StreamMetricsHandler.
+func (s *scopedHandler) OnStreamMetrics(ctx context.Context, info *RunInfo, metrics *StreamMetrics) {
+	if mh, ok := s.handler.(StreamMetricsHandler); ok && s.scope.match(ctx, info) {
+		mh.OnStreamMetrics(ctx, info, metrics)
+	}
+}
+
+func (s *scopedHandler) Needed(ctx context.Context, info *RunInfo, timing CallbackTiming) bool {
+	if !s.scope.match(ctx, info) {
+		return false
+	}
+	if _, ok := s.handler.(StreamMetricsHandler); !ok && timing == TimingOnStreamMetrics {
+		return false
+	}
+	if checker, ok := s.handler.(TimingChecker); ok {
+		return checker.Needed(ctx, info, timing)
+	}
+	return true
+}
+
+func (s *Scope) match(ctx context.Context, info *RunInfo) bool {
+	if info == nil {
+		info = &RunInfo{}
+	}
+	if len(s.Names) > 0 && !matchAnyGlob(s.Names, info.Name) {
+		return false
+	}
+	if len(s.Components) > 0 && !containsAny(s.Components, []components.Component{info.Component}) {
+		return false
+	}
+	if len(s.Types) > 0 && !containsAny(s.Types, []string{info.Type}) {
+		return false
+	}
+	if len(s.Labels) > 0 && !containsAny(s.Labels, callbacks.LabelsFromCtx(ctx)) {
+		return false
+	}
+	if len(s.Paths) > 0 {
+		nodePath := callbacks.NodePathFromCtx(ctx)
+		for i := len(nodePath); i > 0; i-- {
+			if matchAnyGlob(s.Paths, strings.Join(nodePath[:i], "/")) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func matchAnyGlob(globs []string, name string) bool {
+	for _, glob := range globs {
+		if ok, _ := path.Match(glob, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny[T comparable](values, targets []T) bool {
+	for _, v := range values {
+		for _, t := range targets {
+			if v == t {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestScopedHandler(t *testing.T) {
+	var names []string
+	inner := NewHandlerBuilder().
+		OnStartFn(func(ctx context.Context, info *RunInfo, input CallbackInput) context.Context {
+			names = append(names, info.Name)
+			return ctx
+		}).Build()
+	h := NewScopedHandler(inner, Scope{
+		Components: []components.Component{components.ComponentOfChatModel},
+		Labels:     []string{"canary"},
+	})
+
+	ctx := context.Background()
+	model := &RunInfo{Name: "model", Component: components.ComponentOfChatModel}
+	OnStart(InitCallbacks(ctx, model, h), "input")
+	OnStart(InitCallbacks(WithLabels(ctx, "canary"), &RunInfo{Name: "retriever", Component: components.ComponentOfRetriever}, h), "input")
+	OnStart(InitCallbacks(WithLabels(WithLabels(ctx, "user"), "canary"), model, h), "input")
+	assert.Equal(t, []string{"model"}, names)
+
+	checker := h.(TimingChecker)
+	assert.False(t, checker.Needed(ctx, model, TimingOnStart))
+	assert.True(t, checker.Needed(WithLabels(ctx, "canary"), model, TimingOnStart))
+	assert.False(t, checker.Needed(WithLabels(ctx, "canary"), model, TimingOnEnd))
+
+	// the streams out of the scope are closed
+	sr, sw := schema.Pipe[CallbackOutput](1)
+	h.OnEndWithStreamOutput(ctx, model, sr)
+	closed := sw.Send("chunk", nil)
+	assert.True(t, closed)
+}
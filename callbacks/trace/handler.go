@@ -0,0 +1,397 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package trace provides a callback handler exporting the callback events as JSON lines,
+// and a loader reconstructing the timelines of the runs from the exported files.
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	icb "github.com/cloudwego/eino/internal/callbacks"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Timing is the callback timing of an Event.
+type Timing string
+
+const (
+	TimingStart Timing = "start"
+	TimingEnd   Timing = "end"
+	TimingError Timing = "error"
+	// TimingStartWithStreamInput is written when the input stream ends, with the chunks of the stream as the payload.
+	TimingStartWithStreamInput Timing = "start_with_stream_input"
+	// TimingEndWithStreamOutput is written when the output stream ends, with the chunks of the stream as the payload.
+	TimingEndWithStreamOutput Timing = "end_with_stream_output"
+)
+
+// Event is a line of the exported file, recording a callback of a component execution (span).
+type Event struct {
+	RunID string `json:"run_id"`
+	// SpanID identifies the execution of the component, shared by its start and end events.
+	SpanID string `json:"span_id"`
+	// ParentSpanID is the span of the component, e.g. the graph, invoking this component.
+	ParentSpanID string    `json:"parent_span_id,omitempty"`
+	Timing       Timing    `json:"timing"`
+	Time         time.Time `json:"time"`
+
+	Name      string   `json:"name,omitempty"`
+	Type      string   `json:"type,omitempty"`
+	Component string   `json:"component,omitempty"`
+	Path      []string `json:"path,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+
+	// Payload is the serialized input of the start events, or the output of the end events.
+	// The payload of the stream events is the array of the chunks, or the concatenated message if the chunks are messages.
+	Payload json.RawMessage `json:"payload,omitempty"`
+	// PayloadError is the reason the payload isn't serialized.
+	PayloadError string `json:"payload_error,omitempty"`
+	// Error is the error of the error events, or the error ending the stream.
+	Error string `json:"error,omitempty"`
+}
+
+// Config is the config of the trace handler.
+type Config struct {
+	// Writer receives the events, one JSON line per event. Required.
+	Writer io.Writer
+	// Redactor redacts the inputs and outputs before serializing, e.g. compose.RedactMedia. Optional.
+	Redactor func(value any) any
+	// RedactKeys are the keys of the JSON objects in the payloads whose values are replaced by "<redacted>", e.g. "api_key". Optional.
+	RedactKeys []string
+}
+
+// Handler is a callback handler writing the callback events as JSON lines, see Event.
+// The events of the concurrent components are written in the order they happen, and are not interleaved.
+type Handler struct {
+	callbacks.Handler
+
+	writer     io.Writer
+	redactor   func(value any) any
+	redactKeys map[string]bool
+
+	mu  sync.Mutex
+	err error
+}
+
+type runIDKey struct{}
+type spanKey struct{}
+
+type span struct {
+	runID string
+	id    string
+}
+
+// WithRunID designates the run id of the events of the components running with the ctx,
+// otherwise each top level component starts a run with a generated id.
+func WithRunID(ctx context.Context, runID string) context.Context {
+	return context.WithValue(ctx, runIDKey{}, runID)
+}
+
+// NewHandler creates a trace handler.
+// e.g.
+//
+//	f, _ := os.Create("trace.jsonl")
+//	handler, err := trace.NewHandler(&trace.Config{Writer: f, RedactKeys: []string{"api_key"}})
+//	out, err := runnable.Invoke(trace.WithRunID(ctx, requestID), input, compose.WithCallbacks(handler))
+func NewHandler(config *Config) (*Handler, error) {
+	if config == nil || config.Writer == nil {
+		return nil, errors.New("writer is required")
+	}
+	h := &Handler{
+		writer:     config.Writer,
+		redactor:   config.Redactor,
+		redactKeys: make(map[string]bool, len(config.RedactKeys)),
+	}
+	for _, k := range config.RedactKeys {
+		h.redactKeys[k] = true
+	}
+	h.Handler = callbacks.NewHandlerBuilder().
+		OnStartFn(h.onStart).
+		OnEndFn(h.onEnd).
+		OnErrorFn(h.onError).
+		OnStartWithStreamInputFn(h.onStartWithStreamInput).
+		OnEndWithStreamOutputFn(h.onEndWithStreamOutput).
+		Build()
+	return h, nil
+}
+
+// Err returns the first error writing the events, the events after it are discarded.
+func (h *Handler) Err() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.err
+}
+
+func (h *Handler) onStart(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+	ctx, s, parent := h.startSpan(ctx)
+	e := h.newEvent(ctx, s, parent, info, TimingStart)
+	h.setPayload(e, input)
+	h.write(e)
+	return ctx
+}
+
+func (h *Handler) onStartWithStreamInput(ctx context.Context, info *callbacks.RunInfo,
+	input *schema.StreamReader[callbacks.CallbackInput]) context.Context {
+
+	ctx, s, parent := h.startSpan(ctx)
+	startTime := time.Now()
+	go func() {
+		chunks, err := readAll(input)
+		e := h.newEvent(ctx, s, parent, info, TimingStartWithStreamInput)
+		// the span starts when the stream is passed in, not when it ends
+		e.Time = startTime
+		h.setStreamPayload(e, chunks, err)
+		h.write(e)
+	}()
+	return ctx
+}
+
+func (h *Handler) onEnd(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+	s, parent := spanFromCtx(ctx)
+	e := h.newEvent(ctx, s, parent, info, TimingEnd)
+	h.setPayload(e, output)
+	h.write(e)
+	return ctx
+}
+
+func (h *Handler) onEndWithStreamOutput(ctx context.Context, info *callbacks.RunInfo,
+	output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+
+	s, parent := spanFromCtx(ctx)
+	go func() {
+		chunks, err := readAll(output)
+		e := h.newEvent(ctx, s, parent, info, TimingEndWithStreamOutput)
+		h.setStreamPayload(e, chunks, err)
+		h.write(e)
+	}()
+	return ctx
+}
+
+func (h *Handler) onError(ctx context.Context, info *callbacks.RunInfo, err error) context.Context {
+	s, parent := spanFromCtx(ctx)
+	e := h.newEvent(ctx, s, parent, info, TimingError)
+	e.Error = err.Error()
+	h.write(e)
+	return ctx
+}
+
+// startSpan returns the ctx with a new span as the child of the span in the ctx.
+func (h *Handler) startSpan(ctx context.Context) (context.Context, *span, *span) {
+	var parent *span
+	if v, ok := ctx.Value(spanKey{}).(*spanWithParent); ok {
+		parent = v.span
+	}
+	s := &span{id: uuid.NewString()}
+	if parent != nil {
+		s.runID = parent.runID
+	} else if runID, ok := ctx.Value(runIDKey{}).(string); ok {
+		s.runID = runID
+	} else {
+		s.runID = uuid.NewString()
+	}
+	return context.WithValue(ctx, spanKey{}, &spanWithParent{span: s, parent: parent}), s, parent
+}
+
+// spanWithParent is the value of spanKey in the ctx returned by OnStart,
+// which the end events of the span find the span and its parent by.
+type spanWithParent struct {
+	*span
+	parent *span
+}
+
+func spanFromCtx(ctx context.Context) (*span, *span) {
+	switch v := ctx.Value(spanKey{}).(type) {
+	case *spanWithParent:
+		return v.span, v.parent
+	default:
+		// the component calls OnEnd without OnStart
+		s := &span{id: uuid.NewString(), runID: uuid.NewString()}
+		if runID, ok := ctx.Value(runIDKey{}).(string); ok {
+			s.runID = runID
+		}
+		return s, nil
+	}
+}
+
+func (h *Handler) newEvent(ctx context.Context, s, parent *span, info *callbacks.RunInfo, timing Timing) *Event {
+	e := &Event{
+		RunID:  s.runID,
+		SpanID: s.id,
+		Timing: timing,
+		Time:   time.Now(),
+		Path:   icb.NodePathFromCtx(ctx),
+		Labels: icb.LabelsFromCtx(ctx),
+	}
+	if parent != nil {
+		e.ParentSpanID = parent.id
+	}
+	if info != nil {
+		e.Name = info.Name
+		e.Type = info.Type
+		e.Component = string(info.Component)
+	}
+	return e
+}
+
+func (h *Handler) setPayload(e *Event, value any) {
+	if h.redactor != nil {
+		value = h.redactor(value)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		e.PayloadError = err.Error()
+		return
+	}
+	e.Payload = h.redactJSON(data)
+}
+
+func (h *Handler) setStreamPayload(e *Event, chunks []any, err error) {
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if len(chunks) > 0 {
+		if payload, ok := concatChunks(chunks); ok {
+			h.setPayload(e, payload)
+			return
+		}
+	}
+	h.setPayload(e, chunks)
+}
+
+// concatChunks concatenates the chunks of messages, or of model callback outputs by their messages,
+// ok is false if the chunks are of other types.
+func concatChunks(chunks []any) (any, bool) {
+	msgs := make([]*schema.Message, 0, len(chunks))
+	if _, ok := chunks[0].(*model.CallbackOutput); !ok {
+		for _, chunk := range chunks {
+			msg, ok := chunk.(*schema.Message)
+			if !ok {
+				return nil, false
+			}
+			msgs = append(msgs, msg)
+		}
+		msg, err := schema.ConcatMessages(msgs)
+		return msg, err == nil
+	}
+
+	out := &model.CallbackOutput{}
+	for _, chunk := range chunks {
+		c, ok := chunk.(*model.CallbackOutput)
+		if !ok {
+			return nil, false
+		}
+		if c.Message != nil {
+			msgs = append(msgs, c.Message)
+		}
+		// the config and the token usage are usually carried by the last chunks
+		if c.Config != nil {
+			out.Config = c.Config
+		}
+		if c.TokenUsage != nil {
+			out.TokenUsage = c.TokenUsage
+		}
+	}
+	msg, err := schema.ConcatMessages(msgs)
+	if err != nil {
+		return nil, false
+	}
+	out.Message = msg
+	return out, true
+}
+
+// redactJSON replaces the values of the redacted keys in the JSON.
+func (h *Handler) redactJSON(data []byte) json.RawMessage {
+	if len(h.redactKeys) == 0 {
+		return data
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return data
+	}
+	redacted, err := json.Marshal(h.redactValue(v))
+	if err != nil {
+		return data
+	}
+	return redacted
+}
+
+func (h *Handler) redactValue(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, vv := range t {
+			if h.redactKeys[k] {
+				t[k] = "<redacted>"
+			} else {
+				t[k] = h.redactValue(vv)
+			}
+		}
+		return t
+	case []any:
+		for i := range t {
+			t[i] = h.redactValue(t[i])
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func (h *Handler) write(e *Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		data, err = json.Marshal(&Event{RunID: e.RunID, SpanID: e.SpanID, ParentSpanID: e.ParentSpanID, Timing: e.Timing,
+			Time: e.Time, Name: e.Name, Type: e.Type, Component: e.Component, Path: e.Path,
+			PayloadError: fmt.Sprintf("marshal event fail: %v", err)})
+		if err != nil {
+			return
+		}
+	}
+	data = append(data, '\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.err != nil {
+		return
+	}
+	if _, err = h.writer.Write(data); err != nil {
+		h.err = err
+	}
+}
+
+func readAll[T any](sr *schema.StreamReader[T]) ([]any, error) {
+	defer sr.Close()
+	var chunks []any
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			return chunks, nil
+		}
+		if err != nil {
+			return chunks, err
+		}
+		chunks = append(chunks, chunk)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Run is the timeline of a run reconstructed from the events.
+type Run struct {
+	ID string
+	// Roots are the spans without parent, e.g. the top graph, ordered by the start time.
+	Roots []*Span
+	// Spans are all the spans of the run, ordered by the start time.
+	Spans []*Span
+
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// Span is an execution of a component.
+type Span struct {
+	ID       string
+	Parent   *Span
+	Children []*Span
+
+	Name      string
+	Type      string
+	Component string
+	Path      []string
+	Labels    []string
+
+	StartTime time.Time
+	// EndTime is zero if the span doesn't end, e.g. the process exits before it ends.
+	EndTime time.Time
+	// Stream tells whether the input or the output of the span is a stream.
+	Stream bool
+
+	Input  json.RawMessage
+	Output json.RawMessage
+	Error  string
+}
+
+// Duration returns the duration of the span, 0 if it doesn't end.
+func (s *Span) Duration() time.Duration {
+	if s.EndTime.IsZero() {
+		return 0
+	}
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// Load reads the events written by the Handler, and reconstructs the runs, ordered by the start time.
+func Load(r io.Reader) ([]*Run, error) {
+	runs := make(map[string]*Run)
+	spans := make(map[string]*Span)
+	parents := make(map[string]string)
+	var runOrder []*Run
+
+	dec := json.NewDecoder(r)
+	for line := 1; ; line++ {
+		e := &Event{}
+		if err := dec.Decode(e); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("decode event[%d] fail: %w", line, err)
+		}
+
+		run, ok := runs[e.RunID]
+		if !ok {
+			run = &Run{ID: e.RunID}
+			runs[e.RunID] = run
+			runOrder = append(runOrder, run)
+		}
+		s, ok := spans[e.SpanID]
+		if !ok {
+			s = &Span{ID: e.SpanID, Name: e.Name, Type: e.Type, Component: e.Component, Path: e.Path, Labels: e.Labels}
+			spans[e.SpanID] = s
+			run.Spans = append(run.Spans, s)
+			if e.ParentSpanID != "" {
+				parents[e.SpanID] = e.ParentSpanID
+			}
+		}
+
+		switch e.Timing {
+		case TimingStart, TimingStartWithStreamInput:
+			s.StartTime = e.Time
+			s.Input = e.Payload
+			s.Stream = s.Stream || e.Timing == TimingStartWithStreamInput
+		case TimingEnd, TimingEndWithStreamOutput:
+			s.EndTime = e.Time
+			s.Output = e.Payload
+			s.Error = e.Error
+			s.Stream = s.Stream || e.Timing == TimingEndWithStreamOutput
+		case TimingError:
+			s.EndTime = e.Time
+			s.Error = e.Error
+		default:
+			return nil, fmt.Errorf("unknown timing of event[%d]: %s", line, e.Timing)
+		}
+	}
+
+	for id, parentID := range parents {
+		if parent, ok := spans[parentID]; ok {
+			spans[id].Parent = parent
+		}
+	}
+	for _, run := range runOrder {
+		sortSpans(run.Spans)
+		for _, s := range run.Spans {
+			if s.Parent == nil {
+				run.Roots = append(run.Roots, s)
+			} else {
+				s.Parent.Children = append(s.Parent.Children, s)
+			}
+			if run.StartTime.IsZero() || (!s.StartTime.IsZero() && s.StartTime.Before(run.StartTime)) {
+				run.StartTime = s.StartTime
+			}
+			if s.EndTime.After(run.EndTime) {
+				run.EndTime = s.EndTime
+			}
+		}
+	}
+	sort.SliceStable(runOrder, func(i, j int) bool {
+		return runOrder[i].StartTime.Before(runOrder[j].StartTime)
+	})
+	return runOrder, nil
+}
+
+func sortSpans(spans []*Span) {
+	sort.SliceStable(spans, func(i, j int) bool {
+		return spans[i].StartTime.Before(spans[j].StartTime)
+	})
+}
+
+// Decode decodes the payload of a span, e.g. Decode[*schema.Message](span.Output).
+func Decode[T any](payload json.RawMessage) (T, error) {
+	var v T
+	if len(payload) == 0 {
+		return v, errors.New("payload is empty")
+	}
+	err := json.Unmarshal(payload, &v)
+	return v, err
+}
@@ -0,0 +1,168 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ReplayEntries extracts the executions of the chat model and tools nodes from the runs,
+// to reproduce the runs without calling the models and the tools by compose.WithTraceReplay.
+// The spans without the input, or not ended, are skipped. The spans whose payloads are redacted
+// don't match the inputs of the replayed run, unless the inputs are redacted the same.
+func ReplayEntries(runs ...*Run) ([]*compose.ReplayEntry, error) {
+	var entries []*compose.ReplayEntry
+	for _, run := range runs {
+		for _, s := range run.Spans {
+			if len(s.Path) == 0 || len(s.Input) == 0 || s.EndTime.IsZero() {
+				continue
+			}
+			var (
+				input, output any
+				err           error
+			)
+			switch s.Component {
+			case string(components.ComponentOfChatModel):
+				input, err = decodeModelInput(s.Input)
+				if err == nil && s.Error == "" {
+					output, err = decodeModelOutput(s.Output)
+				}
+			case string(compose.ComponentOfToolsNode):
+				input, err = Decode[*schema.Message](s.Input)
+				if err == nil && s.Error == "" {
+					output, err = decodeToolsOutput(s.Output)
+				}
+			default:
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("decode span[%s] of node[%v] fail: %w", s.ID, s.Path, err)
+			}
+
+			hash, err := compose.HashReplayInput(input)
+			if err != nil {
+				return nil, err
+			}
+			entry := &compose.ReplayEntry{Path: s.Path, InputHash: hash, Error: s.Error}
+			if s.Error == "" {
+				if entry.Output, err = json.Marshal(output); err != nil {
+					return nil, fmt.Errorf("marshal output of span[%s] fail: %w", s.ID, err)
+				}
+			}
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// modelPayload is the model.CallbackInput or model.CallbackOutput, recorded if the model runs the callbacks itself.
+type modelPayload struct {
+	Messages []*schema.Message
+	Message  *schema.Message
+}
+
+func decodeModelInput(payload json.RawMessage) ([]*schema.Message, error) {
+	if isJSONArray(payload) {
+		return Decode[[]*schema.Message](payload)
+	}
+	p, err := Decode[*modelPayload](payload)
+	if err != nil {
+		return nil, err
+	}
+	return p.Messages, nil
+}
+
+func decodeModelOutput(payload json.RawMessage) (*schema.Message, error) {
+	if !isJSONArray(payload) {
+		return decodeModelMessage(payload)
+	}
+	// the chunks of a stream not concatenated by the Handler
+	chunks, err := Decode[[]json.RawMessage](payload)
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]*schema.Message, 0, len(chunks))
+	for _, chunk := range chunks {
+		msg, err := decodeModelMessage(chunk)
+		if err != nil {
+			return nil, err
+		}
+		if msg != nil {
+			msgs = append(msgs, msg)
+		}
+	}
+	return schema.ConcatMessages(msgs)
+}
+
+func decodeModelMessage(payload json.RawMessage) (*schema.Message, error) {
+	p, err := Decode[*modelPayload](payload)
+	if err != nil {
+		return nil, err
+	}
+	if p.Message != nil {
+		return p.Message, nil
+	}
+	return Decode[*schema.Message](payload)
+}
+
+func decodeToolsOutput(payload json.RawMessage) ([]*schema.Message, error) {
+	chunks, err := Decode[[]json.RawMessage](payload)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 || !isJSONArray(chunks[0]) {
+		return Decode[[]*schema.Message](payload)
+	}
+
+	// the chunks of a stream, in each of which the results of the finished tools are set by the index of the tool calls
+	var parts [][]*schema.Message
+	for _, chunk := range chunks {
+		msgs, err := Decode[[]*schema.Message](chunk)
+		if err != nil {
+			return nil, err
+		}
+		for i, msg := range msgs {
+			for len(parts) <= i {
+				parts = append(parts, nil)
+			}
+			if msg != nil {
+				parts[i] = append(parts[i], msg)
+			}
+		}
+	}
+	ret := make([]*schema.Message, len(parts))
+	for i, part := range parts {
+		if len(part) == 0 {
+			continue
+		}
+		if ret[i], err = schema.ConcatMessages(part); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func isJSONArray(payload json.RawMessage) bool {
+	trimmed := bytes.TrimSpace(payload)
+	return len(trimmed) > 0 && trimmed[0] == '['
+}
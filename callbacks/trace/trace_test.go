@@ -0,0 +1,224 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/model/modeltest"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.buf.String()
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	g := compose.NewGraph[map[string]any, *schema.Message]()
+	assert.NoError(t, g.AddLambdaNode("prompt", compose.InvokableLambda(func(ctx context.Context, in map[string]any) ([]*schema.Message, error) {
+		return []*schema.Message{schema.UserMessage(in["query"].(string))}, nil
+	}), compose.WithNodeName("prompt")))
+	assert.NoError(t, g.AddLambdaNode("model", compose.InvokableLambda(func(ctx context.Context, in []*schema.Message) (*schema.Message, error) {
+		return schema.AssistantMessage("echo: "+in[0].Content, nil), nil
+	}), compose.WithNodeName("model")))
+	assert.NoError(t, g.AddEdge(compose.START, "prompt"))
+	assert.NoError(t, g.AddEdge("prompt", "model"))
+	assert.NoError(t, g.AddEdge("model", compose.END))
+	r, err := g.Compile(ctx, compose.WithGraphName("qa"))
+	assert.NoError(t, err)
+
+	_, err = NewHandler(nil)
+	assert.ErrorContains(t, err, "writer is required")
+	buf := &syncBuffer{}
+	h, err := NewHandler(&Config{Writer: buf, RedactKeys: []string{"api_key"}})
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(WithRunID(ctx, "run-1"), map[string]any{"query": "hi", "api_key": "secret"}, compose.WithCallbacks(h))
+	assert.NoError(t, err)
+	assert.Equal(t, "echo: hi", out.Content)
+	assert.NoError(t, h.Err())
+	assert.NotContains(t, buf.String(), "secret")
+	assert.Equal(t, 6, strings.Count(buf.String(), "\n"))
+
+	runs, err := Load(strings.NewReader(buf.String()))
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+	run := runs[0]
+	assert.Equal(t, "run-1", run.ID)
+	assert.Len(t, run.Spans, 3)
+	assert.Len(t, run.Roots, 1)
+	root := run.Roots[0]
+	assert.Equal(t, "qa", root.Name)
+	assert.Len(t, root.Children, 2)
+	assert.Equal(t, "prompt", root.Children[0].Name)
+	assert.Equal(t, []string{"prompt"}, root.Children[0].Path)
+	assert.Equal(t, "model", root.Children[1].Name)
+	assert.Equal(t, root, root.Children[1].Parent)
+	assert.GreaterOrEqual(t, root.Duration(), root.Children[1].Duration())
+	assert.Equal(t, run.StartTime, root.StartTime)
+
+	input, err := Decode[map[string]any](root.Input)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]any{"query": "hi", "api_key": "<redacted>"}, input)
+	msg, err := Decode[*schema.Message](root.Children[1].Output)
+	assert.NoError(t, err)
+	assert.Equal(t, "echo: hi", msg.Content)
+
+	t.Run("stream and error", func(t *testing.T) {
+		buf := &syncBuffer{}
+		h, err := NewHandler(&Config{Writer: buf})
+		assert.NoError(t, err)
+
+		ctx := callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: "model", Component: "ChatModel"}, h)
+		mctx := callbacks.OnStart(ctx, "input")
+		_, sr := callbacks.OnEndWithStreamOutput(mctx, schema.StreamReaderFromArray([]*schema.Message{
+			schema.AssistantMessage("hello", nil), schema.AssistantMessage(" world", nil),
+		}))
+		sr.Close()
+		rctx := callbacks.OnStart(ctx, "input")
+		callbacks.OnError(rctx, errors.New("failed"))
+
+		assert.Eventually(t, func() bool {
+			return strings.Count(buf.String(), "\n") == 4
+		}, time.Second, time.Millisecond)
+
+		runs, err := Load(strings.NewReader(buf.String()))
+		assert.NoError(t, err)
+		assert.Len(t, runs, 2)
+		spans := append(runs[0].Spans, runs[1].Spans...)
+		var streamed, failed *Span
+		for _, s := range spans {
+			if s.Stream {
+				streamed = s
+			} else {
+				failed = s
+			}
+		}
+		msg, err := Decode[*schema.Message](streamed.Output)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", msg.Content)
+		assert.Equal(t, "failed", failed.Error)
+		assert.False(t, failed.EndTime.IsZero())
+
+		_, err = Load(strings.NewReader("{invalid"))
+		assert.Error(t, err)
+	})
+
+	t.Run("stream of model callback outputs", func(t *testing.T) {
+		buf := &syncBuffer{}
+		h, err := NewHandler(&Config{Writer: buf})
+		assert.NoError(t, err)
+
+		ctx := callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: "model", Component: "ChatModel"}, h)
+		ctx = callbacks.OnStart(ctx, &model.CallbackInput{Messages: []*schema.Message{schema.UserMessage("hi")}})
+		_, sr := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderFromArray([]*model.CallbackOutput{
+			{Message: schema.AssistantMessage("hello", nil)},
+			{Message: schema.AssistantMessage(" world", nil), TokenUsage: &model.TokenUsage{TotalTokens: 3}},
+		}))
+		sr.Close()
+
+		assert.Eventually(t, func() bool {
+			return strings.Count(buf.String(), "\n") == 2
+		}, time.Second, time.Millisecond)
+		runs, err := Load(strings.NewReader(buf.String()))
+		assert.NoError(t, err)
+		out, err := Decode[*model.CallbackOutput](runs[0].Spans[0].Output)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", out.Message.Content)
+		assert.Equal(t, 3, out.TokenUsage.TotalTokens)
+	})
+}
+
+func TestReplayEntries(t *testing.T) {
+	ctx := context.Background()
+
+	newRunnable := func(cm model.BaseChatModel, result string) compose.Runnable[[]*schema.Message, []*schema.Message] {
+		search := utils.NewTool(&schema.ToolInfo{Name: "search"}, func(ctx context.Context, _ map[string]any) (string, error) {
+			return result, nil
+		})
+		tn, err := compose.NewToolNode(ctx, &compose.ToolsNodeConfig{Tools: []tool.BaseTool{search}})
+		assert.NoError(t, err)
+		g := compose.NewGraph[[]*schema.Message, []*schema.Message]()
+		assert.NoError(t, g.AddChatModelNode("model", cm))
+		assert.NoError(t, g.AddToolsNode("tools", tn))
+		assert.NoError(t, g.AddEdge(compose.START, "model"))
+		assert.NoError(t, g.AddEdge("model", "tools"))
+		assert.NoError(t, g.AddEdge("tools", compose.END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+		return r
+	}
+
+	for _, stream := range []bool{false, true} {
+		buf := &syncBuffer{}
+		h, err := NewHandler(&Config{Writer: buf})
+		assert.NoError(t, err)
+
+		cm := modeltest.New(modeltest.When(modeltest.Any(), modeltest.ToolCalls(modeltest.ToolCall("search", `{"q":"eino"}`))))
+		input := []*schema.Message{schema.UserMessage("search eino")}
+		if stream {
+			sr, err := newRunnable(cm, "recorded").Stream(ctx, input, compose.WithCallbacks(h))
+			assert.NoError(t, err)
+			sr.Close()
+		} else {
+			_, err = newRunnable(cm, "recorded").Invoke(ctx, input, compose.WithCallbacks(h))
+			assert.NoError(t, err)
+		}
+
+		var runs []*Run
+		assert.Eventually(t, func() bool {
+			runs, err = Load(strings.NewReader(buf.String()))
+			return err == nil && len(runs) == 1 && !runs[0].Roots[0].EndTime.IsZero() && len(runs[0].Spans) >= 3
+		}, time.Second, time.Millisecond)
+		entries, err := ReplayEntries(runs...)
+		assert.NoError(t, err)
+		assert.Len(t, entries, 2)
+
+		// the model has no response left and the tool returns another result, both are replayed from the trace
+		out, err := newRunnable(cm, "live").Invoke(ctx, input, compose.WithTraceReplay(entries))
+		assert.NoError(t, err)
+		assert.Equal(t, "recorded", out[0].Content)
+		assert.Len(t, cm.Calls(), 1)
+	}
+}
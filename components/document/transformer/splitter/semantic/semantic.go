@@ -0,0 +1,339 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semantic
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/document/transformer/splitter"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultBufferSize           = 1
+	defaultBreakpointPercentile = 95
+)
+
+var defaultSeparators = []string{"\n", ". ", "? ", "! ", "。", "？", "！"}
+
+// Config is the config of the semantic splitter.
+type Config struct {
+	// Embedding is used to embed the sentences to find the breakpoints. Required.
+	Embedding embedding.Embedder
+	// Separators split the content into sentences, the separator is kept at the end of the sentence.
+	// Optional. Default is newlines and the common sentence endings.
+	Separators []string
+	// BufferSize is the number of the sentences before and after a sentence combined with it when embedded,
+	// which reduces the noise of short sentences. Optional. Default is 1, set a negative value to embed the sentences alone.
+	BufferSize int
+	// BreakpointPercentile is the percentile of the distances between adjacent sentences above which the content is split.
+	// A lower value produces more chunks. Optional. Default is 95, should be in (0, 100].
+	BreakpointPercentile float64
+	// MinChunkSize is the min number of characters of a chunk, a breakpoint in a smaller chunk is ignored. Optional.
+	MinChunkSize int
+	// MaxChunkSize is the max number of characters of a chunk, which is split before exceeding it even without a breakpoint.
+	// A single sentence longer than MaxChunkSize is kept as a chunk. Optional, zero means no limit.
+	MaxChunkSize int
+	// OverlapSentences is the number of the last sentences of a chunk repeated at the beginning of the next chunk. Optional.
+	// The overlap is reduced if it makes the next chunk exceed MaxChunkSize.
+	OverlapSentences int
+	// IDGenerator generates the ID of a chunk. Optional.
+	// By default, the chunks keep the ID of the parent document, e.g. for the parent indexer to generate the sub IDs.
+	IDGenerator func(ctx context.Context, parentID string, chunkIndex int) string
+}
+
+// NewSplitter creates a transformer splitting documents at the semantic breakpoints,
+// where the embedding distance between adjacent sentences is among the largest of the document,
+// as an alternative to the splitters by length.
+// Each chunk keeps the metadata of its parent document, together with
+// splitter.MetaKeyParentID, splitter.MetaKeyChunkIndex, splitter.MetaKeyStartOffset and splitter.MetaKeyEndOffset.
+// The content of a chunk is the content of the parent document between the offsets.
+// e.g.
+//
+//	splitter, err := semantic.NewSplitter(ctx, &semantic.Config{
+//		Embedding:    embedder,
+//		MinChunkSize: 100,
+//		MaxChunkSize: 1000,
+//	})
+//	chunks, err := splitter.Transform(ctx, docs)
+func NewSplitter(ctx context.Context, config *Config) (document.Transformer, error) {
+	if config.Embedding == nil {
+		return nil, errors.New("embedding is required")
+	}
+	separators := config.Separators
+	if len(separators) == 0 {
+		separators = defaultSeparators
+	}
+	bufferSize := config.BufferSize
+	if bufferSize == 0 {
+		bufferSize = defaultBufferSize
+	} else if bufferSize < 0 {
+		bufferSize = 0
+	}
+	percentile := config.BreakpointPercentile
+	if percentile == 0 {
+		percentile = defaultBreakpointPercentile
+	}
+	if percentile < 0 || percentile > 100 {
+		return nil, fmt.Errorf("invalid breakpoint percentile: %v", percentile)
+	}
+	if config.MaxChunkSize > 0 && config.MinChunkSize > config.MaxChunkSize {
+		return nil, fmt.Errorf("min chunk size %d is larger than max chunk size %d", config.MinChunkSize, config.MaxChunkSize)
+	}
+
+	return &semanticSplitter{
+		embedding:   config.Embedding,
+		separators:  separators,
+		bufferSize:  bufferSize,
+		percentile:  percentile,
+		minSize:     config.MinChunkSize,
+		maxSize:     config.MaxChunkSize,
+		overlap:     config.OverlapSentences,
+		idGenerator: config.IDGenerator,
+	}, nil
+}
+
+type semanticSplitter struct {
+	embedding   embedding.Embedder
+	separators  []string
+	bufferSize  int
+	percentile  float64
+	minSize     int
+	maxSize     int
+	overlap     int
+	idGenerator func(ctx context.Context, parentID string, chunkIndex int) string
+}
+
+// sentence is the span [start, end) of the parent content.
+type sentence struct {
+	start, end int
+}
+
+func (s *semanticSplitter) Transform(ctx context.Context, src []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	// embed the sentences of all the documents in one call
+	sentences := make([][]sentence, len(src))
+	var texts []string
+	for i, doc := range src {
+		sentences[i] = s.splitSentences(doc.Content)
+		if len(sentences[i]) > 1 {
+			texts = append(texts, s.combine(doc.Content, sentences[i])...)
+		}
+	}
+	var vectors [][]float64
+	if len(texts) > 0 {
+		var err error
+		vectors, err = s.embedding.EmbedStrings(ctx, texts)
+		if err != nil {
+			return nil, fmt.Errorf("embed sentences fail: %w", err)
+		}
+		if len(vectors) != len(texts) {
+			return nil, fmt.Errorf("unexpected number of vectors, expected: %d, actual: %d", len(texts), len(vectors))
+		}
+	}
+
+	var ret []*schema.Document
+	for i, doc := range src {
+		var breakpoints map[int]bool
+		if n := len(sentences[i]); n > 1 {
+			breakpoints = s.breakpoints(vectors[:n])
+			vectors = vectors[n:]
+		}
+		for idx, span := range s.group(doc.Content, sentences[i], breakpoints) {
+			ret = append(ret, s.newChunk(ctx, doc, idx, span))
+		}
+	}
+	return ret, nil
+}
+
+func (s *semanticSplitter) GetType() string {
+	return "SemanticSplitter"
+}
+
+// splitSentences splits the content at the separators, with the surrounding whitespaces trimmed and the empty sentences dropped.
+func (s *semanticSplitter) splitSentences(content string) []sentence {
+	var ret []sentence
+	appendSentence := func(start, end int) {
+		for start < end {
+			r, size := utf8.DecodeRuneInString(content[start:])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			start += size
+		}
+		for end > start {
+			r, size := utf8.DecodeLastRuneInString(content[:end])
+			if !unicode.IsSpace(r) {
+				break
+			}
+			end -= size
+		}
+		if start < end {
+			ret = append(ret, sentence{start: start, end: end})
+		}
+	}
+
+	start := 0
+	for i := 0; i < len(content); {
+		matched := 0
+		for _, sep := range s.separators {
+			if sep != "" && strings.HasPrefix(content[i:], sep) {
+				matched = len(sep)
+				break
+			}
+		}
+		if matched == 0 {
+			_, size := utf8.DecodeRuneInString(content[i:])
+			i += size
+			continue
+		}
+		i += matched
+		appendSentence(start, i)
+		start = i
+	}
+	appendSentence(start, len(content))
+	return ret
+}
+
+// combine returns the text of each sentence together with its neighbors within the buffer size.
+func (s *semanticSplitter) combine(content string, sentences []sentence) []string {
+	ret := make([]string, len(sentences))
+	for i := range sentences {
+		lo, hi := i-s.bufferSize, i+s.bufferSize
+		if lo < 0 {
+			lo = 0
+		}
+		if hi >= len(sentences) {
+			hi = len(sentences) - 1
+		}
+		ret[i] = content[sentences[lo].start:sentences[hi].end]
+	}
+	return ret
+}
+
+// breakpoints returns the indexes of the sentences after which the content is split.
+func (s *semanticSplitter) breakpoints(vectors [][]float64) map[int]bool {
+	distances := make([]float64, len(vectors)-1)
+	for i := range distances {
+		distances[i] = 1 - cosineSimilarity(vectors[i], vectors[i+1])
+	}
+	threshold := percentileOf(distances, s.percentile)
+
+	ret := make(map[int]bool)
+	for i, d := range distances {
+		if d > threshold {
+			ret[i] = true
+		}
+	}
+	return ret
+}
+
+// group groups the sentences into chunks, returns the sentence index ranges [first, last] of the chunks, including the overlaps.
+func (s *semanticSplitter) group(content string, sentences []sentence, breakpoints map[int]bool) [][2]int {
+	size := func(first, last int) int {
+		return utf8.RuneCountInString(content[sentences[first].start:sentences[last].end])
+	}
+
+	var groups [][2]int
+	first := 0
+	for i := range sentences {
+		if i > first && s.maxSize > 0 && size(first, i) > s.maxSize {
+			groups = append(groups, [2]int{first, i - 1})
+			first = i
+		}
+		if breakpoints[i] && size(first, i) >= s.minSize {
+			groups = append(groups, [2]int{first, i})
+			first = i + 1
+		}
+	}
+	if first < len(sentences) {
+		groups = append(groups, [2]int{first, len(sentences) - 1})
+	}
+
+	if s.overlap > 0 {
+		for i := len(groups) - 1; i > 0; i-- {
+			start := groups[i][0] - s.overlap
+			if start < groups[i-1][0] {
+				start = groups[i-1][0]
+			}
+			for s.maxSize > 0 && start < groups[i][0] && size(start, groups[i][1]) > s.maxSize {
+				start++
+			}
+			groups[i][0] = start
+		}
+	}
+
+	ret := make([][2]int, len(groups))
+	for i, g := range groups {
+		ret[i] = [2]int{sentences[g[0]].start, sentences[g[1]].end}
+	}
+	return ret
+}
+
+func (s *semanticSplitter) newChunk(ctx context.Context, parent *schema.Document, index int, span [2]int) *schema.Document {
+	metaData := make(map[string]any, len(parent.MetaData)+4)
+	for k, v := range parent.MetaData {
+		metaData[k] = v
+	}
+	metaData[splitter.MetaKeyParentID] = parent.ID
+	metaData[splitter.MetaKeyChunkIndex] = index
+	metaData[splitter.MetaKeyStartOffset] = span[0]
+	metaData[splitter.MetaKeyEndOffset] = span[1]
+
+	id := parent.ID
+	if s.idGenerator != nil {
+		id = s.idGenerator(ctx, parent.ID, index)
+	}
+	return &schema.Document{
+		ID:       id,
+		Content:  parent.Content[span[0]:span[1]],
+		MetaData: metaData,
+	}
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// percentileOf returns the percentile of the values with linear interpolation.
+func percentileOf(values []float64, percentile float64) float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+
+	rank := percentile / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
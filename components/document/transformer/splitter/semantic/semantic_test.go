@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package semantic
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/document/transformer/splitter"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+)
+
+// topicEmbedder embeds the texts about cats and cars into orthogonal vectors.
+type topicEmbedder struct {
+	calls int
+}
+
+func (t *topicEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	t.calls++
+	ret := make([][]float64, len(texts))
+	for i, text := range texts {
+		ret[i] = []float64{float64(strings.Count(text, "Cat")), float64(strings.Count(text, "Car"))}
+	}
+	return ret, nil
+}
+
+func contents(docs []*schema.Document) []string {
+	ret := make([]string, len(docs))
+	for i := range docs {
+		ret[i] = docs[i].Content
+	}
+	return ret
+}
+
+func TestSemanticSplitter(t *testing.T) {
+	ctx := context.Background()
+	content := "Cats purr. Cats meow.\n\nCars honk. Cars drive. Cars park."
+	doc := &schema.Document{ID: "doc", Content: content, MetaData: map[string]any{"source": "test"}}
+
+	t.Run("breakpoints", func(t *testing.T) {
+		emb := &topicEmbedder{}
+		s, err := NewSplitter(ctx, &Config{Embedding: emb, BufferSize: -1})
+		assert.NoError(t, err)
+		docs, err := s.Transform(ctx, []*schema.Document{doc, {ID: "short", Content: "Cats."}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Cats purr. Cats meow.", "Cars honk. Cars drive. Cars park.", "Cats."}, contents(docs))
+		assert.Equal(t, 1, emb.calls)
+
+		assert.Equal(t, "doc", docs[1].ID)
+		assert.Equal(t, map[string]any{
+			"source":                    "test",
+			splitter.MetaKeyParentID:    "doc",
+			splitter.MetaKeyChunkIndex:  1,
+			splitter.MetaKeyStartOffset: 23,
+			splitter.MetaKeyEndOffset:   len(content),
+		}, docs[1].MetaData)
+		assert.Equal(t, content[23:], docs[1].Content)
+		// the metadata of the parent is not modified
+		assert.Equal(t, map[string]any{"source": "test"}, doc.MetaData)
+	})
+
+	t.Run("size limits and overlap", func(t *testing.T) {
+		s, err := NewSplitter(ctx, &Config{
+			Embedding:        &topicEmbedder{},
+			BufferSize:       -1,
+			MaxChunkSize:     25,
+			OverlapSentences: 1,
+			IDGenerator: func(ctx context.Context, parentID string, chunkIndex int) string {
+				return parentID + "_" + string(rune('a'+chunkIndex))
+			},
+		})
+		assert.NoError(t, err)
+		docs, err := s.Transform(ctx, []*schema.Document{doc})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"Cats purr. Cats meow.", "Cars honk. Cars drive.", "Cars drive. Cars park."}, contents(docs))
+		assert.Equal(t, "doc_c", docs[2].ID)
+	})
+
+	t.Run("min size", func(t *testing.T) {
+		s, err := NewSplitter(ctx, &Config{Embedding: &topicEmbedder{}, BufferSize: -1, MinChunkSize: 30})
+		assert.NoError(t, err)
+		docs, err := s.Transform(ctx, []*schema.Document{doc})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{content}, contents(docs))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewSplitter(ctx, &Config{})
+		assert.Error(t, err)
+		_, err = NewSplitter(ctx, &Config{Embedding: &topicEmbedder{}, BreakpointPercentile: 101})
+		assert.Error(t, err)
+		_, err = NewSplitter(ctx, &Config{Embedding: &topicEmbedder{}, MinChunkSize: 10, MaxChunkSize: 5})
+		assert.Error(t, err)
+	})
+}
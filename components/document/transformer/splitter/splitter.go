@@ -0,0 +1,30 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package splitter holds what is shared by the document splitters in its sub packages,
+// e.g. the metadata keys linking a chunk to the document it is split from.
+package splitter
+
+const (
+	// MetaKeyParentID is the metadata key of the ID of the document a chunk is split from.
+	MetaKeyParentID = "_parent_id"
+	// MetaKeyChunkIndex is the metadata key of the index of a chunk in its parent document, starting from 0.
+	MetaKeyChunkIndex = "_chunk_index"
+	// MetaKeyStartOffset is the metadata key of the byte offset in the parent content where a chunk starts.
+	MetaKeyStartOffset = "_start_offset"
+	// MetaKeyEndOffset is the metadata key of the byte offset in the parent content where a chunk ends, exclusive.
+	MetaKeyEndOffset = "_end_offset"
+)
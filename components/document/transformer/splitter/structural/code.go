@@ -0,0 +1,326 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package structural
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Language is the programming language of the code to split.
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguagePython     Language = "python"
+	LanguageJava       Language = "java"
+	LanguageJavaScript Language = "javascript"
+	LanguageTypeScript Language = "typescript"
+)
+
+var (
+	goFuncRegexp = regexp.MustCompile(`^func\s+(?:\(\s*(?:\w+\s+)?\*?\s*(\w+)[^)]*\)\s*)?(\w+)`)
+	goTypeRegexp = regexp.MustCompile(`^type\s+(\w+)`)
+
+	javaDeclRegexp = regexp.MustCompile(`^(?:(?:public|protected|private|abstract|final|static|sealed|non-sealed|strictfp)\s+)*(?:class|interface|enum|record|@interface)\s+(\w+)`)
+
+	jsDeclRegexp  = regexp.MustCompile(`^(?:export\s+)?(?:default\s+)?(?:declare\s+)?(?:abstract\s+)?(?:async\s+)?(?:function\*?|class|interface|enum|namespace|type)\s+(\w+)`)
+	jsArrowRegexp = regexp.MustCompile(`^(?:export\s+)?(?:const|let|var)\s+(\w+)\s*(?::[^=]+)?=\s*(?:async\s*)?(?:function\b|\([^)]*\)\s*(?::[^=]+)?=>|\w+\s*=>)`)
+
+	pythonDeclRegexp = regexp.MustCompile(`^(?:async\s+)?(?:def|class)\s+(\w+)`)
+)
+
+// CodeConfig is the config of the code splitter.
+type CodeConfig struct {
+	// Language is the programming language of the code. Required.
+	Language Language
+	// IDGenerator generates the ID of a chunk. Optional. By default, the chunks keep the ID of the parent document.
+	IDGenerator IDGenerator
+}
+
+// NewCodeSplitter creates a transformer splitting source code into its top level declarations, such as functions, types and classes,
+// together with their leading comments, annotations or decorators. The code between the declarations, e.g. the imports, forms chunks of its own.
+// Each chunk records the name of its declaration in MetaKeyPath, e.g. ["Retrieve"], or ["parentRetriever", "Retrieve"] for a Go method.
+// The code is scanned lightly by the brackets or the indentation rather than fully parsed,
+// so a declaration is recognized only if it starts at the beginning of a line.
+func NewCodeSplitter(ctx context.Context, config *CodeConfig) (document.Transformer, error) {
+	switch config.Language {
+	case LanguageGo, LanguagePython, LanguageJava, LanguageJavaScript, LanguageTypeScript:
+	default:
+		return nil, fmt.Errorf("unsupported language: %q", config.Language)
+	}
+	return &codeSplitter{language: config.Language, idGenerator: config.IDGenerator}, nil
+}
+
+type codeSplitter struct {
+	language    Language
+	idGenerator IDGenerator
+}
+
+func (c *codeSplitter) Transform(ctx context.Context, src []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	var ret []*schema.Document
+	for _, doc := range src {
+		ret = append(ret, newChunks(ctx, doc, c.split(doc.Content), c.idGenerator)...)
+	}
+	return ret, nil
+}
+
+func (c *codeSplitter) GetType() string {
+	return "CodeSplitter"
+}
+
+// declaration is the lines [first, last] of a top level declaration.
+type declaration struct {
+	first, last int
+	path        []string
+}
+
+func (c *codeSplitter) split(content string) []section {
+	lines := splitLines(content)
+	var decls []declaration
+	if c.language == LanguagePython {
+		decls = splitIndented(lines)
+	} else {
+		decls = c.splitBracketed(content, lines)
+	}
+
+	var sections []section
+	prevEnd := 0
+	for _, decl := range decls {
+		start := lines[decl.first].offset
+		end := lines[decl.last].offset + len(lines[decl.last].text)
+		sections = append(sections, section{start: prevEnd, end: start}, section{start: start, end: end, path: decl.path})
+		prevEnd = end
+	}
+	return append(sections, section{start: prevEnd, end: len(content)})
+}
+
+// match returns the path of the declaration starting at the line, or nil if it's not a declaration.
+func (c *codeSplitter) match(text string) []string {
+	switch c.language {
+	case LanguageGo:
+		if m := goFuncRegexp.FindStringSubmatch(text); m != nil {
+			if m[1] != "" {
+				return []string{m[1], m[2]}
+			}
+			return []string{m[2]}
+		}
+		if m := goTypeRegexp.FindStringSubmatch(text); m != nil {
+			return []string{m[1]}
+		}
+	case LanguageJava:
+		if m := javaDeclRegexp.FindStringSubmatch(text); m != nil {
+			return []string{m[1]}
+		}
+	case LanguageJavaScript, LanguageTypeScript:
+		if m := jsDeclRegexp.FindStringSubmatch(text); m != nil {
+			return []string{m[1]}
+		}
+		if m := jsArrowRegexp.FindStringSubmatch(text); m != nil {
+			return []string{m[1]}
+		}
+	}
+	return nil
+}
+
+// splitBracketed finds the declarations at the top level of the brackets, each of which ends where its brackets are closed.
+func (c *codeSplitter) splitBracketed(content string, lines []line) []declaration {
+	depths := bracketDepths(content, lines, c.language)
+
+	var decls []declaration
+	lowerBound := 0
+	for i := 0; i < len(lines); i++ {
+		if depths[i].start != 0 || !depths[i].inCode {
+			continue
+		}
+		path := c.match(lines[i].text)
+		if path == nil {
+			continue
+		}
+
+		first := attachLeading(lines, i, lowerBound, func(trimmed string) bool {
+			return strings.HasPrefix(trimmed, "//") || strings.HasPrefix(trimmed, "/*") ||
+				strings.HasPrefix(trimmed, "*") || strings.HasPrefix(trimmed, "@")
+		})
+
+		last := i
+		opened := false
+		for last = i; last < len(lines); last++ {
+			if depths[last].opened {
+				opened = true
+			}
+			if depths[last].end != 0 {
+				continue
+			}
+			if !opened && last == i {
+				// the body may start on the next line, e.g. a Java class with the brace on its own line
+				if next := nextNonBlank(lines, i+1); next >= 0 && strings.HasPrefix(strings.TrimSpace(lines[next].text), "{") {
+					continue
+				}
+			}
+			if opened || last == i {
+				break
+			}
+		}
+		if last >= len(lines) {
+			last = len(lines) - 1
+		}
+
+		decls = append(decls, declaration{first: first, last: last, path: path})
+		i = last
+		lowerBound = last + 1
+	}
+	return decls
+}
+
+// splitIndented finds the declarations without indentation, each of which ends before the next line without indentation.
+func splitIndented(lines []line) []declaration {
+	var decls []declaration
+	lowerBound := 0
+	for i := 0; i < len(lines); i++ {
+		m := pythonDeclRegexp.FindStringSubmatch(lines[i].text)
+		if m == nil {
+			continue
+		}
+		first := attachLeading(lines, i, lowerBound, func(trimmed string) bool {
+			return strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "@")
+		})
+
+		last := i
+		for j := i + 1; j < len(lines); j++ {
+			text := lines[j].text
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+			indented := text[0] == ' ' || text[0] == '\t'
+			// the closing brackets of a multi-line signature
+			closing := text[0] == ')' || text[0] == ']' || text[0] == '}'
+			if !indented && !closing {
+				break
+			}
+			last = j
+		}
+
+		decls = append(decls, declaration{first: first, last: last, path: []string{m[1]}})
+		i = last
+		lowerBound = last + 1
+	}
+	return decls
+}
+
+// attachLeading returns the first of the leading lines directly above the declaration, e.g. the doc comments and annotations.
+func attachLeading(lines []line, decl, lowerBound int, leading func(trimmed string) bool) int {
+	first := decl
+	for first > lowerBound {
+		trimmed := strings.TrimSpace(lines[first-1].text)
+		if trimmed == "" || !leading(trimmed) {
+			break
+		}
+		first--
+	}
+	return first
+}
+
+func nextNonBlank(lines []line, from int) int {
+	for i := from; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i].text) != "" {
+			return i
+		}
+	}
+	return -1
+}
+
+// lineDepth is the bracket depth at the start and the end of a line.
+type lineDepth struct {
+	start, end int
+	// opened reports whether any bracket is opened in the line
+	opened bool
+	// inCode reports whether the line starts outside comments and strings
+	inCode bool
+}
+
+// bracketDepths counts the brackets of each line, skipping the comments and the string literals.
+func bracketDepths(content string, lines []line, language Language) []lineDepth {
+	const (
+		stateCode = iota
+		stateLineComment
+		stateBlockComment
+		stateString
+	)
+	state := stateCode
+	var quote byte
+	depth := 0
+
+	ret := make([]lineDepth, len(lines))
+	for i, l := range lines {
+		if state == stateLineComment {
+			state = stateCode
+		}
+		ret[i].start = depth
+		ret[i].inCode = state == stateCode
+
+		text := content[l.offset : l.offset+len(l.text)]
+		for j := 0; j < len(text); j++ {
+			ch := text[j]
+			switch state {
+			case stateCode:
+				switch {
+				case ch == '/' && j+1 < len(text) && text[j+1] == '/':
+					state = stateLineComment
+				case ch == '/' && j+1 < len(text) && text[j+1] == '*':
+					state = stateBlockComment
+					j++
+				case ch == '"' || ch == '\'' || ch == '`':
+					state = stateString
+					quote = ch
+				case ch == '{' || ch == '(' || ch == '[':
+					depth++
+					ret[i].opened = true
+				case ch == '}' || ch == ')' || ch == ']':
+					if depth > 0 {
+						depth--
+					}
+				}
+			case stateBlockComment:
+				if ch == '*' && j+1 < len(text) && text[j+1] == '/' {
+					state = stateCode
+					j++
+				}
+			case stateString:
+				if ch == '\\' && !(language == LanguageGo && quote == '`') {
+					j++
+				} else if ch == quote {
+					state = stateCode
+				}
+			}
+			if state == stateLineComment {
+				break
+			}
+		}
+		// only the raw strings of Go and the template literals of JavaScript span lines
+		if state == stateString && quote != '`' {
+			state = stateCode
+		}
+		ret[i].end = depth
+	}
+	return ret
+}
@@ -0,0 +1,136 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package structural
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+var (
+	htmlHeadingRegexp = regexp.MustCompile(`(?is)<h([1-6])\b[^>]*>(.*?)</h[1-6]\s*>`)
+	htmlIgnoredRegexp = regexp.MustCompile(`(?is)<!--.*?-->|<script\b.*?</script\s*>|<style\b.*?</style\s*>|<head\b.*?</head\s*>`)
+	htmlTagRegexp     = regexp.MustCompile(`(?s)<[^>]*>`)
+)
+
+// HTMLConfig is the config of the HTML splitter.
+type HTMLConfig struct {
+	// Headers maps the heading tags to split at to the metadata keys of their titles, e.g. {"h1": "h1", "h2": "h2"}.
+	// Optional. Default is all the six levels, mapped to "h1" to "h6".
+	Headers map[string]string
+	// IDGenerator generates the ID of a chunk. Optional. By default, the chunks keep the ID of the parent document.
+	IDGenerator IDGenerator
+}
+
+// NewHTMLSplitter creates a transformer splitting HTML documents into sections at the heading tags, i.e. <h1> to <h6>.
+// Like NewMarkdownSplitter, each chunk records the titles of its heading and the enclosing headings in the metadata and in MetaKeyPath.
+// The content of a chunk is the raw HTML of the section, sections without any text, e.g. the <head> of the page, are dropped.
+// The HTML is scanned lightly rather than fully parsed, so headings in comments, <script>, <style> or <head> are ignored,
+// but the sections don't follow the nesting of the elements.
+func NewHTMLSplitter(ctx context.Context, config *HTMLConfig) (document.Transformer, error) {
+	headers := make(map[int]string, 6)
+	if len(config.Headers) == 0 {
+		for level := 1; level <= 6; level++ {
+			headers[level] = fmt.Sprintf("h%d", level)
+		}
+	}
+	for tag, metaKey := range config.Headers {
+		tag = strings.ToLower(tag)
+		level, err := strconv.Atoi(strings.TrimPrefix(tag, "h"))
+		if !strings.HasPrefix(tag, "h") || err != nil || level < 1 || level > 6 {
+			return nil, fmt.Errorf("invalid html heading tag: %q", tag)
+		}
+		headers[level] = metaKey
+	}
+	return &htmlSplitter{headers: headers, idGenerator: config.IDGenerator}, nil
+}
+
+type htmlSplitter struct {
+	headers     map[int]string
+	idGenerator IDGenerator
+}
+
+func (h *htmlSplitter) Transform(ctx context.Context, src []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	var ret []*schema.Document
+	for _, doc := range src {
+		var sections []section
+		for _, sec := range h.split(doc.Content) {
+			if htmlText(doc.Content[sec.start:sec.end]) != "" {
+				sections = append(sections, sec)
+			}
+		}
+		ret = append(ret, newChunks(ctx, doc, sections, h.idGenerator)...)
+	}
+	return ret, nil
+}
+
+func (h *htmlSplitter) GetType() string {
+	return "HTMLSplitter"
+}
+
+func (h *htmlSplitter) split(content string) []section {
+	// blank out the ignored parts, keeping the offsets
+	masked := []byte(content)
+	for _, loc := range htmlIgnoredRegexp.FindAllStringIndex(content, -1) {
+		for i := loc[0]; i < loc[1]; i++ {
+			masked[i] = ' '
+		}
+	}
+
+	var (
+		sections []section
+		stack    []header
+		current  = section{start: 0}
+	)
+	for _, loc := range htmlHeadingRegexp.FindAllSubmatchIndex(masked, -1) {
+		level := int(masked[loc[2]] - '0')
+		metaKey, ok := h.headers[level]
+		if !ok {
+			continue
+		}
+
+		current.end = loc[0]
+		sections = append(sections, current)
+
+		for len(stack) > 0 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, header{level: level, title: htmlText(content[loc[4]:loc[5]]), metaKey: metaKey})
+
+		current = section{start: loc[0], metaData: make(map[string]any, len(stack))}
+		for _, heading := range stack {
+			current.path = append(current.path, heading.title)
+			current.metaData[heading.metaKey] = heading.title
+		}
+	}
+	current.end = len(content)
+	return append(sections, current)
+}
+
+// htmlText returns the text of the HTML with the tags removed, the entities unescaped and the whitespaces collapsed.
+func htmlText(s string) string {
+	s = htmlIgnoredRegexp.ReplaceAllString(s, " ")
+	s = htmlTagRegexp.ReplaceAllString(s, " ")
+	return strings.Join(strings.Fields(html.UnescapeString(s)), " ")
+}
@@ -0,0 +1,132 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package structural
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/schema"
+)
+
+var (
+	markdownHeaderRegexp = regexp.MustCompile(`^ {0,3}(#{1,6})(?:[ \t]+(.*?))?(?:[ \t]+#+)?[ \t]*$`)
+	markdownFenceRegexp  = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})")
+)
+
+// MarkdownConfig is the config of the Markdown splitter.
+type MarkdownConfig struct {
+	// Headers maps the header markers to split at to the metadata keys of their titles, e.g. {"#": "h1", "##": "h2"}.
+	// Headers not in the map are kept in the content of the chunk. Optional. Default is all the six levels, mapped to "h1" to "h6".
+	Headers map[string]string
+	// TrimHeaders removes the header lines from the content of the chunks, as their titles are recorded in the metadata.
+	TrimHeaders bool
+	// IDGenerator generates the ID of a chunk. Optional. By default, the chunks keep the ID of the parent document.
+	IDGenerator IDGenerator
+}
+
+// NewMarkdownSplitter creates a transformer splitting Markdown documents at the ATX headers, i.e. the lines starting with '#'.
+// Each chunk records the titles of its header and the enclosing headers in the metadata, keyed by MarkdownConfig.Headers,
+// and in MetaKeyPath as the path from the top level. Headers in fenced code blocks are ignored.
+// e.g. the chunk under "## Install" of "# Eino" has the metadata {"h1": "Eino", "h2": "Install", "_path": ["Eino", "Install"]}.
+func NewMarkdownSplitter(ctx context.Context, config *MarkdownConfig) (document.Transformer, error) {
+	headers := config.Headers
+	if len(headers) == 0 {
+		headers = make(map[string]string, 6)
+		for level := 1; level <= 6; level++ {
+			headers[strings.Repeat("#", level)] = fmt.Sprintf("h%d", level)
+		}
+	}
+	for marker := range headers {
+		if marker == "" || len(marker) > 6 || strings.Trim(marker, "#") != "" {
+			return nil, fmt.Errorf("invalid markdown header: %q", marker)
+		}
+	}
+	return &markdownSplitter{headers: headers, trimHeaders: config.TrimHeaders, idGenerator: config.IDGenerator}, nil
+}
+
+type markdownSplitter struct {
+	headers     map[string]string
+	trimHeaders bool
+	idGenerator IDGenerator
+}
+
+func (m *markdownSplitter) Transform(ctx context.Context, src []*schema.Document, opts ...document.TransformerOption) ([]*schema.Document, error) {
+	var ret []*schema.Document
+	for _, doc := range src {
+		ret = append(ret, newChunks(ctx, doc, m.split(doc.Content), m.idGenerator)...)
+	}
+	return ret, nil
+}
+
+func (m *markdownSplitter) GetType() string {
+	return "MarkdownSplitter"
+}
+
+func (m *markdownSplitter) split(content string) []section {
+	var (
+		sections []section
+		stack    []header
+		fence    string
+		current  = section{start: 0}
+	)
+	for _, l := range splitLines(content) {
+		if match := markdownFenceRegexp.FindStringSubmatch(l.text); match != nil {
+			if fence == "" {
+				fence = match[1]
+			} else if match[1][0] == fence[0] && len(match[1]) >= len(fence) {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			continue
+		}
+
+		match := markdownHeaderRegexp.FindStringSubmatch(l.text)
+		if match == nil {
+			continue
+		}
+		metaKey, ok := m.headers[match[1]]
+		if !ok {
+			continue
+		}
+
+		current.end = l.offset
+		sections = append(sections, current)
+
+		level := len(match[1])
+		for len(stack) > 0 && stack[len(stack)-1].level >= level {
+			stack = stack[:len(stack)-1]
+		}
+		stack = append(stack, header{level: level, title: strings.TrimSpace(match[2]), metaKey: metaKey})
+
+		current = section{start: l.offset, metaData: make(map[string]any, len(stack))}
+		if m.trimHeaders {
+			current.start = l.offset + len(l.text)
+		}
+		for _, h := range stack {
+			current.path = append(current.path, h.title)
+			current.metaData[h.metaKey] = h.title
+		}
+	}
+	current.end = len(content)
+	return append(sections, current)
+}
@@ -0,0 +1,130 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package structural provides document transformers splitting documents along their structural boundaries,
+// such as the headers of Markdown, the headings of HTML and the top level declarations of code.
+// Each chunk records its structural path, e.g. the titles of the enclosing headers, in the metadata for rendering citations.
+package structural
+
+import (
+	"context"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/document/transformer/splitter"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// MetaKeyPath is the metadata key of the structural path of a chunk as []string,
+	// e.g. the titles of the enclosing headers from the top level, or the names of the enclosing declarations of code.
+	MetaKeyPath = "_path"
+)
+
+// IDGenerator generates the ID of a chunk.
+type IDGenerator func(ctx context.Context, parentID string, chunkIndex int) string
+
+// section is the span [start, end) of the parent content, with its structural path and extra metadata.
+type section struct {
+	start, end int
+	path       []string
+	metaData   map[string]any
+}
+
+// newChunks creates the chunks of the sections, with the surrounding whitespaces trimmed and the empty sections dropped.
+// The chunks keep the ID of the parent document if idGenerator is nil, e.g. for the parent indexer to generate the sub IDs.
+func newChunks(ctx context.Context, parent *schema.Document, sections []section, idGenerator IDGenerator) []*schema.Document {
+	var ret []*schema.Document
+	for _, sec := range sections {
+		start, end := trimSpace(parent.Content, sec.start, sec.end)
+		if start == end {
+			continue
+		}
+
+		index := len(ret)
+		path := make([]string, len(sec.path))
+		copy(path, sec.path)
+		metaData := make(map[string]any, len(parent.MetaData)+len(sec.metaData)+5)
+		for k, v := range parent.MetaData {
+			metaData[k] = v
+		}
+		for k, v := range sec.metaData {
+			metaData[k] = v
+		}
+		metaData[MetaKeyPath] = path
+		metaData[splitter.MetaKeyParentID] = parent.ID
+		metaData[splitter.MetaKeyChunkIndex] = index
+		metaData[splitter.MetaKeyStartOffset] = start
+		metaData[splitter.MetaKeyEndOffset] = end
+
+		id := parent.ID
+		if idGenerator != nil {
+			id = idGenerator(ctx, parent.ID, index)
+		}
+		ret = append(ret, &schema.Document{
+			ID:       id,
+			Content:  parent.Content[start:end],
+			MetaData: metaData,
+		})
+	}
+	return ret
+}
+
+func trimSpace(content string, start, end int) (int, int) {
+	for start < end {
+		r, size := utf8.DecodeRuneInString(content[start:end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		start += size
+	}
+	for end > start {
+		r, size := utf8.DecodeLastRuneInString(content[start:end])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		end -= size
+	}
+	return start, end
+}
+
+// header is a header of Markdown or a heading of HTML, whose title is recorded in the metadata of the chunks under it.
+type header struct {
+	level   int
+	title   string
+	metaKey string
+}
+
+// line is a line of the content without the line break, starting at the byte offset.
+type line struct {
+	text   string
+	offset int
+}
+
+func splitLines(content string) []line {
+	var ret []line
+	start := 0
+	for i := 0; i < len(content); i++ {
+		if content[i] == '\n' {
+			ret = append(ret, line{text: content[start:i], offset: start})
+			start = i + 1
+		}
+	}
+	if start < len(content) {
+		ret = append(ret, line{text: content[start:], offset: start})
+	}
+	return ret
+}
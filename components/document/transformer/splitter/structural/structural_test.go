@@ -0,0 +1,182 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package structural
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/document/transformer/splitter"
+	"github.com/cloudwego/eino/schema"
+)
+
+func paths(docs []*schema.Document) [][]string {
+	ret := make([][]string, len(docs))
+	for i := range docs {
+		ret[i] = docs[i].MetaData[MetaKeyPath].([]string)
+	}
+	return ret
+}
+
+func contents(docs []*schema.Document) []string {
+	ret := make([]string, len(docs))
+	for i := range docs {
+		ret[i] = docs[i].Content
+	}
+	return ret
+}
+
+func TestMarkdownSplitter(t *testing.T) {
+	ctx := context.Background()
+	content := "Preface.\n\n# Eino\n\nIntro.\n\n## Install\n\n```sh\n# not a header\ngo get eino\n```\n\n### Go Version ###\n\nGo 1.18.\n\n## Usage\n\nUse it.\n"
+	doc := &schema.Document{ID: "md", Content: content, MetaData: map[string]any{"source": "README.md"}}
+
+	t.Run("default", func(t *testing.T) {
+		s, err := NewMarkdownSplitter(ctx, &MarkdownConfig{})
+		assert.NoError(t, err)
+		docs, err := s.Transform(ctx, []*schema.Document{doc})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{}, {"Eino"}, {"Eino", "Install"}, {"Eino", "Install", "Go Version"}, {"Eino", "Usage"}}, paths(docs))
+		assert.Equal(t, "## Install\n\n```sh\n# not a header\ngo get eino\n```", docs[2].Content)
+
+		assert.Equal(t, "md", docs[4].ID)
+		assert.Equal(t, map[string]any{
+			"source":                    "README.md",
+			"h1":                        "Eino",
+			"h2":                        "Usage",
+			MetaKeyPath:                 []string{"Eino", "Usage"},
+			splitter.MetaKeyParentID:    "md",
+			splitter.MetaKeyChunkIndex:  4,
+			splitter.MetaKeyStartOffset: strings.Index(content, "## Usage"),
+			splitter.MetaKeyEndOffset:   len(content) - 1,
+		}, docs[4].MetaData)
+	})
+
+	t.Run("headers and trim", func(t *testing.T) {
+		s, err := NewMarkdownSplitter(ctx, &MarkdownConfig{
+			Headers:     map[string]string{"#": "title", "##": "section"},
+			TrimHeaders: true,
+			IDGenerator: func(ctx context.Context, parentID string, chunkIndex int) string {
+				return parentID + "_" + string(rune('0'+chunkIndex))
+			},
+		})
+		assert.NoError(t, err)
+		docs, err := s.Transform(ctx, []*schema.Document{doc})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"Preface.",
+			"Intro.",
+			"```sh\n# not a header\ngo get eino\n```\n\n### Go Version ###\n\nGo 1.18.",
+			"Use it.",
+		}, contents(docs))
+		assert.Equal(t, "Install", docs[2].MetaData["section"])
+		assert.Equal(t, "md_3", docs[3].ID)
+
+		_, err = NewMarkdownSplitter(ctx, &MarkdownConfig{Headers: map[string]string{"h1": "h1"}})
+		assert.Error(t, err)
+	})
+}
+
+func TestHTMLSplitter(t *testing.T) {
+	ctx := context.Background()
+	content := `<html><head><title>Eino</title></head><body>
+<!-- <h1>commented</h1> -->
+<h1 class="title">Eino &amp; <em>Agents</em></h1>
+<p>Intro.</p>
+<h2>Install</h2><p>go get</p>
+<script>document.write("<h2>script</h2>")</script>
+<h3>Go</h3><p>1.18</p>
+<h2>Usage</h2><p>Use it.</p>
+</body></html>`
+	doc := &schema.Document{ID: "html", Content: content}
+
+	s, err := NewHTMLSplitter(ctx, &HTMLConfig{})
+	assert.NoError(t, err)
+	docs, err := s.Transform(ctx, []*schema.Document{doc})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"Eino & Agents"}, {"Eino & Agents", "Install"}, {"Eino & Agents", "Install", "Go"}, {"Eino & Agents", "Usage"}}, paths(docs))
+	assert.Equal(t, "Eino & Agents", docs[3].MetaData["h1"])
+	assert.Equal(t, "<h2>Usage</h2><p>Use it.</p>\n</body></html>", docs[3].Content)
+
+	s, err = NewHTMLSplitter(ctx, &HTMLConfig{Headers: map[string]string{"H2": "section"}})
+	assert.NoError(t, err)
+	docs, err = s.Transform(ctx, []*schema.Document{doc})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{}, {"Install"}, {"Usage"}}, paths(docs))
+	assert.Equal(t, "Install", docs[1].MetaData["section"])
+
+	_, err = NewHTMLSplitter(ctx, &HTMLConfig{Headers: map[string]string{"h7": "h7"}})
+	assert.Error(t, err)
+}
+
+func TestCodeSplitter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("go", func(t *testing.T) {
+		content := "package main\n\nimport \"fmt\"\n\n// T is a type.\ntype T struct {\n\ts string // }\n}\n\ntype ID int\n\n// String returns\n// the string.\nfunc (t *T) String() string {\n\tif t == nil {\n\t\treturn `}\n`\n\t}\n\treturn t.s\n}\n\nvar x = 1\n\nfunc main() {\n\tfmt.Println(\"{\")\n}\n"
+		s, err := NewCodeSplitter(ctx, &CodeConfig{Language: LanguageGo})
+		assert.NoError(t, err)
+		docs, err := s.Transform(ctx, []*schema.Document{{ID: "go", Content: content}})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{}, {"T"}, {"ID"}, {"T", "String"}, {}, {"main"}}, paths(docs))
+		assert.Equal(t, []string{
+			"package main\n\nimport \"fmt\"",
+			"// T is a type.\ntype T struct {\n\ts string // }\n}",
+			"type ID int",
+			"// String returns\n// the string.\nfunc (t *T) String() string {\n\tif t == nil {\n\t\treturn `}\n`\n\t}\n\treturn t.s\n}",
+			"var x = 1",
+			"func main() {\n\tfmt.Println(\"{\")\n}",
+		}, contents(docs))
+	})
+
+	t.Run("python", func(t *testing.T) {
+		content := "import os\n\n\n# A class.\n@dataclass\nclass A:\n    x: int\n\n    def f(self):\n        return 1\n\n\nasync def g(\n    a,\n):\n    pass\n\nprint(g)\n"
+		s, err := NewCodeSplitter(ctx, &CodeConfig{Language: LanguagePython})
+		assert.NoError(t, err)
+		docs, err := s.Transform(ctx, []*schema.Document{{ID: "py", Content: content}})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{}, {"A"}, {"g"}, {}}, paths(docs))
+		assert.Equal(t, "# A class.\n@dataclass\nclass A:\n    x: int\n\n    def f(self):\n        return 1", docs[1].Content)
+		assert.Equal(t, "async def g(\n    a,\n):\n    pass", docs[2].Content)
+	})
+
+	t.Run("typescript", func(t *testing.T) {
+		content := "import { a } from 'a';\n\n/** Doc. */\nexport class A\n{\n  f() { return '}'; }\n}\n\nexport const g = async (x: number): Promise<void> => {\n  /* } */\n};\n\ninterface I {\n  x: string;\n}\n"
+		s, err := NewCodeSplitter(ctx, &CodeConfig{Language: LanguageTypeScript})
+		assert.NoError(t, err)
+		docs, err := s.Transform(ctx, []*schema.Document{{ID: "ts", Content: content}})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{{}, {"A"}, {"g"}, {"I"}}, paths(docs))
+		assert.Equal(t, "/** Doc. */\nexport class A\n{\n  f() { return '}'; }\n}", docs[1].Content)
+		assert.Equal(t, "export const g = async (x: number): Promise<void> => {\n  /* } */\n};", docs[2].Content)
+	})
+
+	t.Run("java", func(t *testing.T) {
+		content := "package a;\n\n@Deprecated\npublic final class A {\n  void f() {}\n}\n"
+		s, err := NewCodeSplitter(ctx, &CodeConfig{Language: LanguageJava})
+		assert.NoError(t, err)
+		docs, err := s.Transform(ctx, []*schema.Document{{ID: "java", Content: content}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"package a;", "@Deprecated\npublic final class A {\n  void f() {}\n}"}, contents(docs))
+	})
+
+	_, err := NewCodeSplitter(ctx, &CodeConfig{Language: "cobol"})
+	assert.Error(t, err)
+}
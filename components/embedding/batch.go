@@ -0,0 +1,338 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/internal/safe"
+)
+
+// Cache stores the embedding vectors, keyed by the hash of the model name and the text.
+type Cache interface {
+	// MGet returns the vectors of the keys in order, the vector of an absent key is nil.
+	MGet(ctx context.Context, keys []string) ([][]float64, error)
+	// MSet stores the vectors of the keys.
+	MSet(ctx context.Context, keys []string, vectors [][]float64) error
+}
+
+// NewInMemoryCache creates a Cache in memory, which is unbounded and lives as long as the process.
+func NewInMemoryCache() Cache {
+	return &inMemoryCache{vectors: make(map[string][]float64)}
+}
+
+type inMemoryCache struct {
+	mu      sync.RWMutex
+	vectors map[string][]float64
+}
+
+func (c *inMemoryCache) MGet(_ context.Context, keys []string) ([][]float64, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	ret := make([][]float64, len(keys))
+	for i, key := range keys {
+		ret[i] = c.vectors[key]
+	}
+	return ret, nil
+}
+
+func (c *inMemoryCache) MSet(_ context.Context, keys []string, vectors [][]float64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, key := range keys {
+		c.vectors[key] = vectors[i]
+	}
+	return nil
+}
+
+// BatchStats is the counts of the texts embedded by a BatchedEmbedder.
+type BatchStats struct {
+	// Texts is the number of the texts passed to EmbedStrings, including the duplicated ones.
+	Texts int64
+	// CacheHits is the number of the distinct texts served by the cache.
+	CacheHits int64
+	// Embedded is the number of the distinct texts sent to the inner embedder.
+	Embedded int64
+	// Batches is the number of the calls to the inner embedder.
+	Batches int64
+}
+
+// BatchOption is the option of NewBatched.
+type BatchOption func(o *batchOptions)
+
+type batchOptions struct {
+	batchSize      int
+	maxConcurrency int
+	cache          Cache
+	model          string
+}
+
+// WithBatchSize sets the max number of texts sent to the inner embedder in one call, e.g. the limit of the provider.
+// Zero means no limit.
+func WithBatchSize(size int) BatchOption {
+	return func(o *batchOptions) {
+		o.batchSize = size
+	}
+}
+
+// WithMaxConcurrency sets the max number of the concurrent calls to the inner embedder of one EmbedStrings, 1 by default.
+func WithMaxConcurrency(n int) BatchOption {
+	return func(o *batchOptions) {
+		o.maxConcurrency = n
+	}
+}
+
+// WithVectorCache serves the texts embedded before from the cache, and stores the newly embedded vectors into it.
+func WithVectorCache(cache Cache) BatchOption {
+	return func(o *batchOptions) {
+		o.cache = cache
+	}
+}
+
+// WithCacheModel sets the model name in the cache keys of the calls without WithModel,
+// which should be the default model of the inner embedder, so that embedders of different models can share a cache.
+func WithCacheModel(model string) BatchOption {
+	return func(o *batchOptions) {
+		o.model = model
+	}
+}
+
+// NewBatched wraps the embedder to reduce the cost and latency of embedding large amounts of texts, e.g. in ingestion jobs.
+// The identical texts of a call are embedded only once, the texts cached before are served by the cache if WithVectorCache is set,
+// and the rest are split into batches of WithBatchSize, which are sent to the inner embedder with at most WithMaxConcurrency calls at a time.
+// The vectors are returned in the order of the texts. If any batch fails, the call fails, while the vectors of the succeeded batches are still cached.
+// e.g.
+//
+//	emb, err := embedding.NewBatched(embedder,
+//		embedding.WithBatchSize(256),
+//		embedding.WithMaxConcurrency(4),
+//		embedding.WithVectorCache(embedding.NewInMemoryCache()),
+//		embedding.WithCacheModel("text-embedding-3-small"),
+//	)
+//	vectors, err := emb.EmbedStrings(ctx, texts)
+func NewBatched(inner Embedder, opts ...BatchOption) (*BatchedEmbedder, error) {
+	if inner == nil {
+		return nil, errors.New("embedder is required")
+	}
+	o := &batchOptions{maxConcurrency: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.batchSize < 0 {
+		return nil, fmt.Errorf("invalid batch size: %d", o.batchSize)
+	}
+	if o.maxConcurrency <= 0 {
+		return nil, fmt.Errorf("invalid max concurrency: %d", o.maxConcurrency)
+	}
+	return &BatchedEmbedder{inner: inner, options: o}, nil
+}
+
+// BatchedEmbedder is an embedder batching, deduplicating and caching the texts, see NewBatched.
+type BatchedEmbedder struct {
+	inner   Embedder
+	options *batchOptions
+
+	texts, cacheHits, embedded, batches int64
+}
+
+// Stats returns the counts of the texts embedded so far.
+func (b *BatchedEmbedder) Stats() BatchStats {
+	return BatchStats{
+		Texts:     atomic.LoadInt64(&b.texts),
+		CacheHits: atomic.LoadInt64(&b.cacheHits),
+		Embedded:  atomic.LoadInt64(&b.embedded),
+		Batches:   atomic.LoadInt64(&b.batches),
+	}
+}
+
+func (b *BatchedEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...Option) ([][]float64, error) {
+	atomic.AddInt64(&b.texts, int64(len(texts)))
+
+	// deduplicate the texts
+	var uniques []string
+	positions := make(map[string]int, len(texts))
+	for _, text := range texts {
+		if _, ok := positions[text]; !ok {
+			positions[text] = len(uniques)
+			uniques = append(uniques, text)
+		}
+	}
+
+	vectors := make([][]float64, len(uniques))
+	var keys []string
+	if b.options.cache != nil {
+		model := b.options.model
+		if m := GetCommonOptions(&Options{}, opts...).Model; m != nil {
+			model = *m
+		}
+		keys = make([]string, len(uniques))
+		for i, text := range uniques {
+			keys[i] = cacheKey(model, text)
+		}
+		cached, err := b.options.cache.MGet(ctx, keys)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get cache: %w", err)
+		}
+		if len(cached) != len(keys) {
+			return nil, fmt.Errorf("unexpected number of cached vectors, expected: %d, actual: %d", len(keys), len(cached))
+		}
+		copy(vectors, cached)
+	}
+
+	var missing []int
+	for i := range vectors {
+		if vectors[i] == nil {
+			missing = append(missing, i)
+		}
+	}
+	atomic.AddInt64(&b.cacheHits, int64(len(uniques)-len(missing)))
+
+	if err := b.embed(ctx, uniques, missing, vectors, keys, opts); err != nil {
+		return nil, err
+	}
+
+	ret := make([][]float64, len(texts))
+	for i, text := range texts {
+		ret[i] = vectors[positions[text]]
+	}
+	return ret, nil
+}
+
+// embed embeds the texts of the missing indexes in batches, and fills the vectors in place.
+func (b *BatchedEmbedder) embed(ctx context.Context, texts []string, missing []int, vectors [][]float64, keys []string, opts []Option) error {
+	if len(missing) == 0 {
+		return nil
+	}
+	batchSize := b.options.batchSize
+	if batchSize == 0 {
+		batchSize = len(missing)
+	}
+	var batches [][]int
+	for start := 0; start < len(missing); start += batchSize {
+		end := start + batchSize
+		if end > len(missing) {
+			end = len(missing)
+		}
+		batches = append(batches, missing[start:end])
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		sem      = make(chan struct{}, b.options.maxConcurrency)
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for _, batch := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(batch []int) {
+			defer func() {
+				if e := recover(); e != nil {
+					fail(safe.NewPanicErr(e, debug.Stack()))
+				}
+				<-sem
+				wg.Done()
+			}()
+			if err := b.embedBatch(ctx, texts, batch, vectors, keys, opts); err != nil {
+				fail(err)
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+func (b *BatchedEmbedder) embedBatch(ctx context.Context, texts []string, batch []int, vectors [][]float64, keys []string, opts []Option) error {
+	input := make([]string, len(batch))
+	for i, idx := range batch {
+		input[i] = texts[idx]
+	}
+
+	atomic.AddInt64(&b.batches, 1)
+	output, err := b.inner.EmbedStrings(ctx, input, opts...)
+	if err != nil {
+		return err
+	}
+	if len(output) != len(input) {
+		return fmt.Errorf("unexpected number of vectors, expected: %d, actual: %d", len(input), len(output))
+	}
+	atomic.AddInt64(&b.embedded, int64(len(input)))
+
+	// each batch writes distinct indexes of vectors
+	for i, idx := range batch {
+		vectors[idx] = output[i]
+	}
+
+	if b.options.cache == nil {
+		return nil
+	}
+	batchKeys := make([]string, len(batch))
+	for i, idx := range batch {
+		batchKeys[i] = keys[idx]
+	}
+	if err = b.options.cache.MSet(ctx, batchKeys, output); err != nil {
+		return fmt.Errorf("failed to set cache: %w", err)
+	}
+	return nil
+}
+
+func (b *BatchedEmbedder) GetType() string {
+	if typ, ok := components.GetType(b.inner); ok {
+		return typ
+	}
+	return "BatchedEmbedder"
+}
+
+func (b *BatchedEmbedder) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(b.inner)
+}
+
+func cacheKey(model, text string) string {
+	h := sha256.New()
+	h.Write([]byte(model))
+	h.Write([]byte{0})
+	h.Write([]byte(text))
+	return hex.EncodeToString(h.Sum(nil))
+}
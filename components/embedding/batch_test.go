@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package embedding
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingEmbedder struct {
+	mu      sync.Mutex
+	calls   [][]string
+	running int32
+	peak    int32
+	fail    string
+}
+
+func (c *countingEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...Option) ([][]float64, error) {
+	n := atomic.AddInt32(&c.running, 1)
+	defer atomic.AddInt32(&c.running, -1)
+	for {
+		p := atomic.LoadInt32(&c.peak)
+		if n <= p || atomic.CompareAndSwapInt32(&c.peak, p, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	c.mu.Lock()
+	c.calls = append(c.calls, texts)
+	c.mu.Unlock()
+
+	ret := make([][]float64, len(texts))
+	for i, text := range texts {
+		if c.fail != "" && text == c.fail {
+			return nil, errors.New("mock error")
+		}
+		ret[i] = []float64{float64(len(text))}
+	}
+	return ret, nil
+}
+
+func TestBatchedEmbedder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("batch and dedup", func(t *testing.T) {
+		inner := &countingEmbedder{}
+		emb, err := NewBatched(inner, WithBatchSize(2), WithMaxConcurrency(2))
+		assert.NoError(t, err)
+
+		vectors, err := emb.EmbedStrings(ctx, []string{"a", "bb", "a", "ccc", "dddd", "bb", "eeeee"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{1}, {2}, {1}, {3}, {4}, {2}, {5}}, vectors)
+
+		assert.Len(t, inner.calls, 3)
+		for _, call := range inner.calls {
+			assert.LessOrEqual(t, len(call), 2)
+		}
+		assert.LessOrEqual(t, atomic.LoadInt32(&inner.peak), int32(2))
+		assert.Equal(t, BatchStats{Texts: 7, Embedded: 5, Batches: 3}, emb.Stats())
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		inner := &countingEmbedder{}
+		cache := NewInMemoryCache()
+		emb, err := NewBatched(inner, WithVectorCache(cache), WithCacheModel("m1"))
+		assert.NoError(t, err)
+
+		_, err = emb.EmbedStrings(ctx, []string{"a", "bb"})
+		assert.NoError(t, err)
+		vectors, err := emb.EmbedStrings(ctx, []string{"bb", "ccc", "a"})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{2}, {3}, {1}}, vectors)
+		assert.Equal(t, [][]string{{"a", "bb"}, {"ccc"}}, inner.calls)
+		assert.Equal(t, BatchStats{Texts: 5, CacheHits: 2, Embedded: 3, Batches: 2}, emb.Stats())
+
+		// texts of another model are not served by the cache
+		_, err = emb.EmbedStrings(ctx, []string{"a"}, WithModel("m2"))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a"}, inner.calls[2])
+
+		// the model of the call equal to the cache model shares the cache entries
+		_, err = emb.EmbedStrings(ctx, []string{"a"}, WithModel("m1"))
+		assert.NoError(t, err)
+		assert.Len(t, inner.calls, 3)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		inner := &countingEmbedder{fail: "bad"}
+		cache := NewInMemoryCache()
+		emb, err := NewBatched(inner, WithBatchSize(1), WithVectorCache(cache))
+		assert.NoError(t, err)
+
+		_, err = emb.EmbedStrings(ctx, []string{"good", "bad", "other"})
+		assert.ErrorContains(t, err, "mock error")
+		// batches are sent sequentially and stop at the failed one
+		assert.Equal(t, [][]string{{"good"}, {"bad"}}, inner.calls)
+
+		// the succeeded batch is cached
+		vectors, err := cache.MGet(ctx, []string{cacheKey("", "good")})
+		assert.NoError(t, err)
+		assert.Equal(t, [][]float64{{4}}, vectors)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		_, err := NewBatched(nil)
+		assert.Error(t, err)
+		_, err = NewBatched(&countingEmbedder{}, WithBatchSize(-1))
+		assert.Error(t, err)
+		_, err = NewBatched(&countingEmbedder{}, WithMaxConcurrency(0))
+		assert.ErrorContains(t, err, "max concurrency")
+	})
+}
@@ -0,0 +1,150 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package embedding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/cloudwego/eino/components"
+)
+
+// VectorProcessor transforms an embedding vector, e.g. to meet the requirements of an indexer.
+// It must not modify the input vector in place.
+type VectorProcessor func(vector []float64) ([]float64, error)
+
+// L2Normalize scales the vector to unit length, so that the dot product equals the cosine similarity.
+// A zero vector is returned as is.
+func L2Normalize() VectorProcessor {
+	return func(vector []float64) ([]float64, error) {
+		var sum float64
+		for _, v := range vector {
+			sum += v * v
+		}
+		ret := make([]float64, len(vector))
+		if sum == 0 {
+			copy(ret, vector)
+			return ret, nil
+		}
+		norm := math.Sqrt(sum)
+		for i, v := range vector {
+			ret[i] = v / norm
+		}
+		return ret, nil
+	}
+}
+
+// TruncateDimensions keeps the first dims dimensions of the vector, e.g. for the models trained with Matryoshka Representation Learning.
+// It fails if the vector has fewer dimensions. The truncated vector is no longer normalized, follow it with L2Normalize if needed.
+func TruncateDimensions(dims int) VectorProcessor {
+	return func(vector []float64) ([]float64, error) {
+		if dims <= 0 {
+			return nil, fmt.Errorf("invalid dimensions to truncate to: %d", dims)
+		}
+		if len(vector) < dims {
+			return nil, fmt.Errorf("vector has %d dimensions, fewer than %d", len(vector), dims)
+		}
+		ret := make([]float64, dims)
+		copy(ret, vector[:dims])
+		return ret, nil
+	}
+}
+
+// QuantizeInt8 maps the values in [min, max] linearly to the integers in [-128, 127], values out of the range are clamped.
+// The quantized values are still float64 to fit the Embedder interface, use ToInt8 to get the int8 vector for indexing.
+// e.g. QuantizeInt8(-1, 1) for the normalized vectors.
+func QuantizeInt8(min, max float64) VectorProcessor {
+	return func(vector []float64) ([]float64, error) {
+		if !(max > min) {
+			return nil, fmt.Errorf("invalid quantization range: [%v, %v]", min, max)
+		}
+		scale := (math.MaxInt8 - math.MinInt8) / (max - min)
+		ret := make([]float64, len(vector))
+		for i, v := range vector {
+			q := math.Round((v-min)*scale) + math.MinInt8
+			ret[i] = math.Max(math.MinInt8, math.Min(math.MaxInt8, q))
+		}
+		return ret, nil
+	}
+}
+
+// ToInt8 converts the vector quantized by QuantizeInt8 to int8, values out of the int8 range are clamped.
+func ToInt8(vector []float64) []int8 {
+	ret := make([]int8, len(vector))
+	for i, v := range vector {
+		ret[i] = int8(math.Max(math.MinInt8, math.Min(math.MaxInt8, math.Round(v))))
+	}
+	return ret
+}
+
+// NewPostProcessed wraps the embedder to apply the processors in order to every vector it returns,
+// so that the vectors of any embedder meet the requirements of the indexer uniformly.
+// e.g.
+//
+//	emb, err := embedding.NewPostProcessed(embedder,
+//		embedding.TruncateDimensions(256),
+//		embedding.L2Normalize(),
+//		embedding.QuantizeInt8(-1, 1),
+//	)
+//	vectors, err := emb.EmbedStrings(ctx, texts)
+func NewPostProcessed(inner Embedder, processors ...VectorProcessor) (*PostProcessedEmbedder, error) {
+	if inner == nil {
+		return nil, errors.New("embedder is required")
+	}
+	for i, p := range processors {
+		if p == nil {
+			return nil, fmt.Errorf("processor[%d] is nil", i)
+		}
+	}
+	return &PostProcessedEmbedder{inner: inner, processors: processors}, nil
+}
+
+// PostProcessedEmbedder is an embedder transforming the vectors of the inner embedder, see NewPostProcessed.
+type PostProcessedEmbedder struct {
+	inner      Embedder
+	processors []VectorProcessor
+}
+
+func (p *PostProcessedEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...Option) ([][]float64, error) {
+	vectors, err := p.inner.EmbedStrings(ctx, texts, opts...)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([][]float64, len(vectors))
+	for i, vector := range vectors {
+		for _, process := range p.processors {
+			if vector, err = process(vector); err != nil {
+				return nil, fmt.Errorf("failed to process vector[%d]: %w", i, err)
+			}
+		}
+		ret[i] = vector
+	}
+	return ret, nil
+}
+
+func (p *PostProcessedEmbedder) GetType() string {
+	if typ, ok := components.GetType(p.inner); ok {
+		return typ
+	}
+	return "PostProcessedEmbedder"
+}
+
+func (p *PostProcessedEmbedder) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(p.inner)
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package embedding
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fixedEmbedder struct {
+	vectors [][]float64
+}
+
+func (f *fixedEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...Option) ([][]float64, error) {
+	return f.vectors, nil
+}
+
+func TestPostProcessedEmbedder(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("processors", func(t *testing.T) {
+		v, err := L2Normalize()([]float64{3, 4})
+		assert.NoError(t, err)
+		assert.InDeltaSlice(t, []float64{0.6, 0.8}, v, 1e-9)
+
+		v, err = L2Normalize()([]float64{0, 0})
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{0, 0}, v)
+
+		v, err = TruncateDimensions(2)([]float64{1, 2, 3})
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{1, 2}, v)
+		_, err = TruncateDimensions(4)([]float64{1, 2, 3})
+		assert.Error(t, err)
+
+		v, err = QuantizeInt8(-1, 1)([]float64{-1, 0, 1, 2, -0.5})
+		assert.NoError(t, err)
+		assert.Equal(t, []float64{-128, 0, 127, 127, -64}, v)
+		assert.Equal(t, []int8{-128, 0, 127, 127, -64}, ToInt8(v))
+		_, err = QuantizeInt8(1, 1)([]float64{1})
+		assert.Error(t, err)
+	})
+
+	t.Run("embedder", func(t *testing.T) {
+		input := [][]float64{{3, 4, 12}, {0, 5, 1}}
+		emb, err := NewPostProcessed(&fixedEmbedder{vectors: input}, TruncateDimensions(2), L2Normalize())
+		assert.NoError(t, err)
+		vectors, err := emb.EmbedStrings(ctx, []string{"a", "b"})
+		assert.NoError(t, err)
+		assert.InDeltaSlice(t, []float64{0.6, 0.8}, vectors[0], 1e-9)
+		assert.InDeltaSlice(t, []float64{0, 1}, vectors[1], 1e-9)
+		// the vectors of the inner embedder are not modified
+		assert.Equal(t, [][]float64{{3, 4, 12}, {0, 5, 1}}, input)
+
+		emb, err = NewPostProcessed(&fixedEmbedder{vectors: input}, TruncateDimensions(5))
+		assert.NoError(t, err)
+		_, err = emb.EmbedStrings(ctx, []string{"a", "b"})
+		assert.ErrorContains(t, err, "failed to process vector[0]")
+
+		_, err = NewPostProcessed(nil)
+		assert.Error(t, err)
+		_, err = NewPostProcessed(&fixedEmbedder{}, nil)
+		assert.Error(t, err)
+	})
+}
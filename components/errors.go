@@ -0,0 +1,103 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package components
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrorCategory is the standard category of the errors of components,
+// so that alerting and fallback logic can branch on the category instead of matching the error messages.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryUnknown is the category of the errors not classified.
+	ErrorCategoryUnknown ErrorCategory = ""
+	// ErrorCategoryRateLimited is the category of the requests throttled by the provider.
+	ErrorCategoryRateLimited ErrorCategory = "RateLimited"
+	// ErrorCategoryTimeout is the category of the requests timed out, including context.DeadlineExceeded.
+	ErrorCategoryTimeout ErrorCategory = "Timeout"
+	// ErrorCategoryInvalidInput is the category of the requests rejected for the input, e.g. invalid arguments or too many tokens.
+	ErrorCategoryInvalidInput ErrorCategory = "InvalidInput"
+	// ErrorCategoryProviderUnavailable is the category of the failures of the provider, e.g. 5xx responses or connection failures.
+	ErrorCategoryProviderUnavailable ErrorCategory = "ProviderUnavailable"
+	// ErrorCategoryContentFiltered is the category of the requests or the responses blocked by the content filter of the provider.
+	ErrorCategoryContentFiltered ErrorCategory = "ContentFiltered"
+)
+
+// ClassifiedError is implemented by the errors of component implementations to report their category.
+// Implementations can wrap their errors by NewClassifiedError instead of implementing it.
+type ClassifiedError interface {
+	error
+	ErrorCategory() ErrorCategory
+}
+
+// NewClassifiedError wraps err with the category, errors.Is and errors.As still match err.
+// e.g.
+//
+//	if resp.StatusCode >= 500 {
+//		return nil, components.NewClassifiedError(err, components.ErrorCategoryProviderUnavailable)
+//	}
+func NewClassifiedError(err error, category ErrorCategory) error {
+	if err == nil {
+		return nil
+	}
+	return &classifiedError{err: err, category: category}
+}
+
+type classifiedError struct {
+	err      error
+	category ErrorCategory
+}
+
+func (c *classifiedError) Error() string {
+	return fmt.Sprintf("%s: %v", c.category, c.err)
+}
+
+func (c *classifiedError) Unwrap() error {
+	return c.err
+}
+
+func (c *classifiedError) ErrorCategory() ErrorCategory {
+	return c.category
+}
+
+// ClassifyError returns the category of the outermost ClassifiedError in the chain of err.
+// Without it, deadline exceeded and timeout errors, i.e. implementing Timeout() bool like net.Error, are ErrorCategoryTimeout,
+// others are ErrorCategoryUnknown.
+func ClassifyError(err error) ErrorCategory {
+	if err == nil {
+		return ErrorCategoryUnknown
+	}
+
+	var ce ClassifiedError
+	if errors.As(err, &ce) {
+		return ce.ErrorCategory()
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCategoryTimeout
+	}
+	var te interface{ Timeout() bool }
+	if errors.As(err, &te) && te.Timeout() {
+		return ErrorCategoryTimeout
+	}
+
+	return ErrorCategoryUnknown
+}
@@ -30,3 +30,10 @@ type Indexer interface {
 	// Store stores the documents.
 	Store(ctx context.Context, docs []*schema.Document, opts ...Option) (ids []string, err error) // invoke
 }
+
+// Deleter is implemented by the indexers that can delete the stored documents by their IDs,
+// which is needed to keep the index in sync with changing sources, e.g. by the incremental indexer.
+type Deleter interface {
+	// Delete deletes the documents of the ids, IDs not found are ignored.
+	Delete(ctx context.Context, ids []string, opts ...Option) error
+}
@@ -16,7 +16,12 @@
 
 package indexer
 
-import "github.com/cloudwego/eino/components/embedding"
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/schema"
+)
 
 // Options is the options for the indexer.
 type Options struct {
@@ -24,6 +29,11 @@ type Options struct {
 	SubIndexes []string
 	// Embedding is the embedding component.
 	Embedding embedding.Embedder
+	// Upsert requires the documents to replace the stored ones with the same IDs, instead of being added as duplicates or failing.
+	Upsert bool
+	// IDGenerator generates the IDs of the documents without IDs.
+	// Stable IDs, e.g. derived from the source and the position of the document, make upserting the same document replace it.
+	IDGenerator func(ctx context.Context, doc *schema.Document) string
 }
 
 // WithSubIndexes is the option to set the sub indexes for the indexer.
@@ -44,6 +54,24 @@ func WithEmbedding(emb embedding.Embedder) Option {
 	}
 }
 
+// WithUpsert is the option to replace the stored documents with the same IDs, see Options.Upsert.
+func WithUpsert() Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Upsert = true
+		},
+	}
+}
+
+// WithIDGenerator is the option to generate the IDs of the documents without IDs, see Options.IDGenerator.
+func WithIDGenerator(gen func(ctx context.Context, doc *schema.Document) string) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.IDGenerator = gen
+		},
+	}
+}
+
 // Option is the call option for Indexer component.
 type Option struct {
 	apply func(opts *Options)
@@ -17,11 +17,13 @@
 package indexer
 
 import (
+	"context"
 	"testing"
 
 	"github.com/smartystreets/goconvey/convey"
 
 	"github.com/cloudwego/eino/internal/mock/components/embedding"
+	"github.com/cloudwego/eino/schema"
 )
 
 func TestOptions(t *testing.T) {
@@ -35,11 +37,18 @@ func TestOptions(t *testing.T) {
 			&Options{},
 			WithSubIndexes(subIndexes),
 			WithEmbedding(e),
+			WithUpsert(),
 		)
 
 		convey.So(opts, convey.ShouldResemble, &Options{
 			SubIndexes: subIndexes,
 			Embedding:  e,
+			Upsert:     true,
 		})
+
+		opts = GetCommonOptions(nil, WithIDGenerator(func(ctx context.Context, doc *schema.Document) string {
+			return "id_" + doc.Content
+		}))
+		convey.So(opts.IDGenerator(context.Background(), &schema.Document{Content: "a"}), convey.ShouldEqual, "id_a")
 	})
 }
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/components"
+)
+
+// ErrBudgetExhausted is matched by the errors of the exhausted quotas, see BudgetExhaustedError.
+var ErrBudgetExhausted = errors.New("budget exhausted")
+
+// BudgetUsage is the consumption of the chat models charged to a key.
+type BudgetUsage struct {
+	Tokens int64
+	Cost   float64
+}
+
+// BudgetQuota is the limit of the consumption of a key, zero value of each field means no limit.
+type BudgetQuota struct {
+	MaxTokens int64
+	MaxCost   float64
+}
+
+// BudgetExhaustedError is returned when the quota of the key has been exhausted.
+// errors.Is(err, ErrBudgetExhausted) reports true for it.
+type BudgetExhaustedError struct {
+	Key   string
+	Usage BudgetUsage
+	Quota BudgetQuota
+	// Exceeded lists which limits have been reached, the values could be "tokens" and "cost".
+	Exceeded []string
+}
+
+func (e *BudgetExhaustedError) Error() string {
+	return fmt.Sprintf("budget of key[%s] exhausted: %v, tokens: %d, cost: %f", e.Key, e.Exceeded, e.Usage.Tokens, e.Usage.Cost)
+}
+
+func (e *BudgetExhaustedError) Unwrap() error {
+	return ErrBudgetExhausted
+}
+
+func (e *BudgetExhaustedError) ErrorCategory() components.ErrorCategory {
+	return components.ErrorCategoryRateLimited
+}
+
+// BudgetManager tracks the consumption of the chat models per key, e.g. a user or a tenant, across runs,
+// and enforces the quotas of the keys.
+// It's shared by all the runs in the process, or across processes with a distributed storage.
+// See flow/budget for the implementations.
+type BudgetManager interface {
+	// Check returns a *BudgetExhaustedError if the quota of the key has been exhausted.
+	Check(ctx context.Context, key string) error
+	// Charge adds the usage to the key, and returns the total usage of the key.
+	Charge(ctx context.Context, key string, usage BudgetUsage) (BudgetUsage, error)
+	// Usage returns the total usage of the key.
+	Usage(ctx context.Context, key string) (BudgetUsage, error)
+	// Reset clears the usage of the key, e.g. at the start of a billing period.
+	Reset(ctx context.Context, key string) error
+}
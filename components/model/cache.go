@@ -0,0 +1,370 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Cache stores the outputs of chat model calls, keyed by the hash of the input and the options.
+type Cache interface {
+	// Get returns the recorded output chunks of the key, ok is false if the key is absent or expired.
+	// The output of Generate is recorded as a single chunk.
+	Get(ctx context.Context, key string) (chunks []*schema.Message, ok bool, err error)
+	// Set records the output chunks of the key, ttl is zero if the entry never expires.
+	Set(ctx context.Context, key string, chunks []*schema.Message, ttl time.Duration) error
+}
+
+// NewInMemoryCache creates a Cache in memory, expired entries are evicted on access.
+func NewInMemoryCache() Cache {
+	return &inMemoryCache{entries: make(map[string]*inMemoryCacheEntry)}
+}
+
+type inMemoryCacheEntry struct {
+	chunks   []*schema.Message
+	expireAt time.Time
+}
+
+type inMemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]*inMemoryCacheEntry
+}
+
+func (c *inMemoryCache) Get(_ context.Context, key string) ([]*schema.Message, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expireAt.IsZero() && time.Now().After(entry.expireAt) {
+		delete(c.entries, key)
+		return nil, false, nil
+	}
+	return entry.chunks, true, nil
+}
+
+func (c *inMemoryCache) Set(_ context.Context, key string, chunks []*schema.Message, ttl time.Duration) error {
+	entry := &inMemoryCacheEntry{chunks: chunks}
+	if ttl > 0 {
+		entry.expireAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
+
+// CacheStats is the hit and miss counts of a CachedChatModel.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+	// Bypasses is the number of calls with WithCacheBypass, which are not counted in Hits or Misses.
+	Bypasses int64
+}
+
+// CacheOption is the option of NewCached.
+type CacheOption func(o *cacheOptions)
+
+type cacheOptions struct {
+	ttl     time.Duration
+	keyFunc func(ctx context.Context, input []*schema.Message, opts *Options) (string, error)
+}
+
+// WithCacheTTL sets the time to live of the cache entries, zero means never expires.
+func WithCacheTTL(ttl time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.ttl = ttl
+	}
+}
+
+// WithCacheKeyFunc replaces the default cache key, which is the hash of the normalized input messages and the common options.
+// Use it when the implementation specific options of the model, which aren't part of the default key, affect the output.
+func WithCacheKeyFunc(keyFunc func(ctx context.Context, input []*schema.Message, opts *Options) (string, error)) CacheOption {
+	return func(o *cacheOptions) {
+		o.keyFunc = keyFunc
+	}
+}
+
+type cacheCallOptions struct {
+	bypass bool
+}
+
+// WithCacheBypass makes the call skip the cache lookup of a CachedChatModel, the fresh output still replaces the cached one.
+func WithCacheBypass() Option {
+	return WrapImplSpecificOptFn(func(o *cacheCallOptions) {
+		o.bypass = true
+	})
+}
+
+// NewCached wraps the model to serve repeated calls from the cache, e.g. for evaluation re-runs or workloads with many duplicated requests.
+// The cache key is the hash of the input messages, normalized by dropping the response meta, and the common options of the call,
+// including the bound tools. Generate is served by the recorded output, and Stream replays the recorded chunks.
+// Calls that fail, or whose output stream isn't fully received, are not cached.
+// The callbacks are run for the calls served from the cache as well, with the recorded output.
+// e.g.
+//
+//	cm, err := model.NewCached(chatModel, model.NewInMemoryCache(), model.WithCacheTTL(time.Hour))
+//	msg, err := cm.Generate(ctx, input)
+//	msg, err = cm.Generate(ctx, input) // served from the cache
+//	msg, err = cm.Generate(ctx, input, model.WithCacheBypass()) // calls the model
+func NewCached(inner BaseChatModel, cache Cache, opts ...CacheOption) (*CachedChatModel, error) {
+	if inner == nil {
+		return nil, errors.New("model is required")
+	}
+	if cache == nil {
+		return nil, errors.New("cache is required")
+	}
+	o := &cacheOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.keyFunc == nil {
+		o.keyFunc = defaultCacheKey
+	}
+	return &CachedChatModel{inner: inner, cache: cache, options: o, stats: &cacheStats{}}, nil
+}
+
+// CachedChatModel is a chat model serving repeated calls from a Cache, see NewCached.
+type CachedChatModel struct {
+	inner   BaseChatModel
+	cache   Cache
+	options *cacheOptions
+	tools   []*schema.ToolInfo
+
+	// stats is shared by the models derived by WithTools
+	stats *cacheStats
+}
+
+type cacheStats struct {
+	hits, misses, bypasses int64
+}
+
+// Stats returns the hit and miss counts of the model, including the ones of the models derived by WithTools.
+func (c *CachedChatModel) Stats() CacheStats {
+	return CacheStats{
+		Hits:     atomic.LoadInt64(&c.stats.hits),
+		Misses:   atomic.LoadInt64(&c.stats.misses),
+		Bypasses: atomic.LoadInt64(&c.stats.bypasses),
+	}
+}
+
+func (c *CachedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+	key, chunks, err := c.lookup(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+	if chunks == nil && components.IsCallbacksEnabled(c.inner) {
+		// the inner model runs the callbacks itself
+		return c.generate(ctx, key, input, opts)
+	}
+
+	cbInput := c.callbackInput(input, opts)
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+	ctx = callbacks.OnStart(ctx, cbInput)
+	var msg *schema.Message
+	if chunks != nil {
+		msg, err = schema.ConcatMessages(copyMessages(chunks))
+	} else {
+		msg, err = c.generate(ctx, key, input, opts)
+	}
+	if err != nil {
+		callbacks.OnError(ctx, err)
+		return nil, err
+	}
+	callbacks.OnEnd(ctx, newCallbackOutput(msg, cbInput.Config))
+	return msg, nil
+}
+
+func (c *CachedChatModel) generate(ctx context.Context, key string, input []*schema.Message, opts []Option) (*schema.Message, error) {
+	msg, err := c.inner.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = c.cache.Set(ctx, key, copyMessages([]*schema.Message{msg}), c.options.ttl); err != nil {
+		return nil, fmt.Errorf("failed to set cache: %w", err)
+	}
+	return msg, nil
+}
+
+func (c *CachedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.StreamReader[*schema.Message], error) {
+	key, chunks, err := c.lookup(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+	if chunks == nil && components.IsCallbacksEnabled(c.inner) {
+		// the inner model runs the callbacks itself
+		return c.stream(ctx, key, input, opts)
+	}
+
+	cbInput := c.callbackInput(input, opts)
+	ctx = callbacks.EnsureRunInfo(ctx, c.GetType(), components.ComponentOfChatModel)
+	ctx = callbacks.OnStart(ctx, cbInput)
+	var sr *schema.StreamReader[*schema.Message]
+	if chunks != nil {
+		sr = schema.StreamReaderFromArray(copyMessages(chunks))
+	} else {
+		sr, err = c.stream(ctx, key, input, opts)
+		if err != nil {
+			callbacks.OnError(ctx, err)
+			return nil, err
+		}
+	}
+	_, cbOutput := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderWithConvert(sr, func(msg *schema.Message) (*CallbackOutput, error) {
+		return newCallbackOutput(msg, cbInput.Config), nil
+	}))
+	return schema.StreamReaderWithConvert(cbOutput, func(out *CallbackOutput) (*schema.Message, error) {
+		return out.Message, nil
+	}), nil
+}
+
+func (c *CachedChatModel) stream(ctx context.Context, key string, input []*schema.Message, opts []Option) (*schema.StreamReader[*schema.Message], error) {
+	sr, err := c.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	outSR, outSW := schema.Pipe[*schema.Message](1)
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				outSW.Send(nil, fmt.Errorf("panic in cached chat model: %v", panicErr))
+			}
+			sr.Close()
+			outSW.Close()
+		}()
+
+		var recorded []*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				outSW.Send(nil, err)
+				return
+			}
+			cp := *chunk
+			recorded = append(recorded, &cp)
+			if closed := outSW.Send(chunk, nil); closed {
+				// the output isn't fully received
+				return
+			}
+		}
+		if err := c.cache.Set(ctx, key, recorded, c.options.ttl); err != nil {
+			outSW.Send(nil, fmt.Errorf("failed to set cache: %w", err))
+		}
+	}()
+
+	return outSR, nil
+}
+
+func (c *CachedChatModel) WithTools(tools []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	tcm, ok := c.inner.(ToolCallingChatModel)
+	if !ok {
+		return nil, fmt.Errorf("model[%T] isn't a ToolCallingChatModel", c.inner)
+	}
+	inner, err := tcm.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &CachedChatModel{inner: inner, cache: c.cache, options: c.options, tools: tools, stats: c.stats}, nil
+}
+
+func (c *CachedChatModel) GetType() string {
+	if typ, ok := components.GetType(c.inner); ok {
+		return typ
+	}
+	return "CachedChatModel"
+}
+
+// IsCallbacksEnabled returns true, the callbacks of the calls served from the cache are run by the model,
+// and so are the ones of the other calls if the inner model doesn't run them itself.
+func (c *CachedChatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+func (c *CachedChatModel) callbackInput(input []*schema.Message, opts []Option) *CallbackInput {
+	o := GetCommonOptions(&Options{Tools: c.tools}, opts...)
+	return &CallbackInput{Messages: input, Tools: o.Tools, ToolChoice: o.ToolChoice, Config: newCallbackConfig(o)}
+}
+
+// lookup returns the cache key, and the cached chunks if hit.
+func (c *CachedChatModel) lookup(ctx context.Context, input []*schema.Message, opts []Option) (string, []*schema.Message, error) {
+	key, err := c.options.keyFunc(ctx, input, GetCommonOptions(&Options{Tools: c.tools}, opts...))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate cache key: %w", err)
+	}
+
+	if GetImplSpecificOptions(&cacheCallOptions{}, opts...).bypass {
+		atomic.AddInt64(&c.stats.bypasses, 1)
+		return key, nil, nil
+	}
+
+	chunks, ok, err := c.cache.Get(ctx, key)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get cache: %w", err)
+	}
+	if !ok || len(chunks) == 0 {
+		atomic.AddInt64(&c.stats.misses, 1)
+		return key, nil, nil
+	}
+	atomic.AddInt64(&c.stats.hits, 1)
+	return key, chunks, nil
+}
+
+func defaultCacheKey(_ context.Context, input []*schema.Message, opts *Options) (string, error) {
+	normalized := make([]*schema.Message, len(input))
+	for i, msg := range input {
+		m := *msg
+		// the response meta, e.g. the token usage, varies between identical outputs
+		m.ResponseMeta = nil
+		normalized[i] = &m
+	}
+	b, err := json.Marshal(struct {
+		Messages []*schema.Message `json:"messages"`
+		Options  *Options          `json:"options"`
+	}{normalized, opts})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// copyMessages copies the messages in and out of the cache, so that modifications of the callers don't pollute the cache.
+func copyMessages(chunks []*schema.Message) []*schema.Message {
+	ret := make([]*schema.Message, len(chunks))
+	for i, chunk := range chunks {
+		c := *chunk
+		ret[i] = &c
+	}
+	return ret
+}
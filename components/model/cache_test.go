@@ -0,0 +1,228 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/schema"
+)
+
+type countingModel struct {
+	calls int
+	tools []*schema.ToolInfo
+}
+
+func (c *countingModel) Generate(_ context.Context, input []*schema.Message, _ ...Option) (*schema.Message, error) {
+	c.calls++
+	return &schema.Message{
+		Role:         schema.Assistant,
+		Content:      input[len(input)-1].Content + " answer",
+		ResponseMeta: &schema.ResponseMeta{Usage: &schema.TokenUsage{TotalTokens: c.calls}},
+	}, nil
+}
+
+func (c *countingModel) Stream(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := c.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{
+		schema.AssistantMessage(input[len(input)-1].Content, nil),
+		schema.AssistantMessage(" answer", nil),
+		{Role: schema.Assistant, ResponseMeta: msg.ResponseMeta},
+	}), nil
+}
+
+func (c *countingModel) WithTools(tools []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	return &countingModel{tools: tools}, nil
+}
+
+// callbackModel runs the callbacks itself, which are left out in the tests.
+type callbackModel struct {
+	countingModel
+}
+
+func (c *callbackModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+func TestCachedChatModel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("generate and stream", func(t *testing.T) {
+		inner := &countingModel{}
+		cm, err := NewCached(inner, NewInMemoryCache())
+		assert.NoError(t, err)
+
+		msg, err := cm.Generate(ctx, []*schema.Message{schema.UserMessage("q1")})
+		assert.NoError(t, err)
+		assert.Equal(t, "q1 answer", msg.Content)
+		msg.Content = "modified"
+
+		// the response meta of the history doesn't affect the key
+		msg, err = cm.Generate(ctx, []*schema.Message{{Role: schema.User, Content: "q1", ResponseMeta: &schema.ResponseMeta{}}})
+		assert.NoError(t, err)
+		assert.Equal(t, "q1 answer", msg.Content)
+		assert.Equal(t, 1, inner.calls)
+
+		// the recorded output of Generate is replayed by Stream
+		sr, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("q1")})
+		assert.NoError(t, err)
+		msg, err = schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "q1 answer", msg.Content)
+
+		// different options are different keys
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("q1")}, WithTemperature(0.5))
+		assert.NoError(t, err)
+		assert.Equal(t, 2, inner.calls)
+
+		// the chunks of a stream are recorded
+		sr, err = cm.Stream(ctx, []*schema.Message{schema.UserMessage("q2")})
+		assert.NoError(t, err)
+		msg, err = schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "q2 answer", msg.Content)
+		sr, err = cm.Stream(ctx, []*schema.Message{schema.UserMessage("q2")})
+		assert.NoError(t, err)
+		chunks := 0
+		for {
+			_, err = sr.Recv()
+			if err != nil {
+				break
+			}
+			chunks++
+		}
+		assert.Equal(t, 3, chunks)
+		assert.Equal(t, 3, inner.calls)
+
+		// bypass
+		msg, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("q2")}, WithCacheBypass())
+		assert.NoError(t, err)
+		assert.Equal(t, 4, inner.calls)
+		assert.Equal(t, 4, msg.ResponseMeta.Usage.TotalTokens)
+		// the fresh output replaces the cached one
+		msg, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("q2")})
+		assert.NoError(t, err)
+		assert.Equal(t, 4, msg.ResponseMeta.Usage.TotalTokens)
+
+		assert.Equal(t, CacheStats{Hits: 4, Misses: 3, Bypasses: 1}, cm.Stats())
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		inner := &countingModel{}
+		cm, err := NewCached(inner, NewInMemoryCache(), WithCacheTTL(10*time.Millisecond))
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, inner.calls)
+		time.Sleep(20 * time.Millisecond)
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("tools", func(t *testing.T) {
+		cm, err := NewCached(&countingModel{}, NewInMemoryCache())
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+
+		tcm, err := cm.WithTools([]*schema.ToolInfo{{Name: "search"}})
+		assert.NoError(t, err)
+		_, err = tcm.Generate(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+		_, err = tcm.Generate(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+		assert.Equal(t, CacheStats{Hits: 1, Misses: 2}, cm.Stats())
+	})
+
+	t.Run("unfinished stream", func(t *testing.T) {
+		inner := &countingModel{}
+		cm, err := NewCached(inner, NewInMemoryCache())
+		assert.NoError(t, err)
+		sr, err := cm.Stream(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+		_, err = sr.Recv()
+		assert.NoError(t, err)
+		sr.Close()
+
+		_, err = cm.Generate(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("callbacks", func(t *testing.T) {
+		var starts, ends, streamEnds int
+		var config *Config
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, _ *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				starts++
+				config = ConvCallbackInput(input).Config
+				return ctx
+			}).
+			OnEndFn(func(ctx context.Context, _ *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				ends++
+				assert.Equal(t, "q answer", ConvCallbackOutput(output).Message.Content)
+				return ctx
+			}).
+			OnEndWithStreamOutputFn(func(ctx context.Context, _ *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+				streamEnds++
+				output.Close()
+				return ctx
+			}).Build()
+		cbCtx := callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, handler)
+
+		// the callbacks of the inner model without callbacks are run by the cached model
+		cm, err := NewCached(&countingModel{}, NewInMemoryCache())
+		assert.NoError(t, err)
+		assert.True(t, cm.IsCallbacksEnabled())
+		for i := 0; i < 2; i++ {
+			_, err = cm.Generate(cbCtx, []*schema.Message{schema.UserMessage("q")}, WithModel("m"))
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 2, starts)
+		assert.Equal(t, 2, ends)
+		assert.Equal(t, "m", config.Model)
+		sr, err := cm.Stream(cbCtx, []*schema.Message{schema.UserMessage("q")}, WithModel("m"))
+		assert.NoError(t, err)
+		msg, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "q answer", msg.Content)
+		assert.Equal(t, 3, starts)
+		assert.Equal(t, 1, streamEnds)
+
+		// the inner model with callbacks runs them on misses, the cached model runs them on hits
+		starts, ends = 0, 0
+		cm, err = NewCached(&callbackModel{}, NewInMemoryCache())
+		assert.NoError(t, err)
+		for i := 0; i < 2; i++ {
+			_, err = cm.Generate(cbCtx, []*schema.Message{schema.UserMessage("q")})
+			assert.NoError(t, err)
+		}
+		assert.Equal(t, 1, starts)
+		assert.Equal(t, 1, ends)
+	})
+}
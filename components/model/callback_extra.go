@@ -18,6 +18,7 @@ package model
 
 import (
 	"github.com/cloudwego/eino/callbacks"
+	icb "github.com/cloudwego/eino/internal/callbacks"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -29,15 +30,22 @@ type TokenUsage struct {
 	PromptTokenDetails PromptTokenDetails
 	// CompletionTokens is the number of completion tokens.
 	CompletionTokens int
+	// CompletionTokenDetails is a breakdown of the completion tokens.
+	CompletionTokenDetails CompletionTokenDetails
 	// TotalTokens is the total number of tokens.
 	TotalTokens int
 }
 
 type PromptTokenDetails struct {
-	// Cached tokens present in the prompt.
+	// Cached tokens present in the prompt, which are counted in PromptTokens as well.
 	CachedTokens int
 }
 
+type CompletionTokenDetails struct {
+	// Reasoning tokens present in the completion.
+	ReasoningTokens int
+}
+
 // Config is the config for the model.
 type Config struct {
 	// Model is the model name.
@@ -78,6 +86,11 @@ type CallbackOutput struct {
 	Extra map[string]any
 }
 
+// StreamChunkSize returns the size of the message, counted in callbacks.StreamMetrics.Bytes.
+func (co *CallbackOutput) StreamChunkSize() int {
+	return icb.MessageSize(co.Message)
+}
+
 // ConvCallbackInput converts the callback input to the model callback input.
 func ConvCallbackInput(src callbacks.CallbackInput) *CallbackInput {
 	switch t := src.(type) {
@@ -105,3 +118,38 @@ func ConvCallbackOutput(src callbacks.CallbackOutput) *CallbackOutput {
 		return nil
 	}
 }
+
+// newCallbackConfig is the config of the call reported by the callbacks triggered in place of the inner model,
+// e.g. by the wrappers of the models, so that the handlers, e.g. the ones tracking the cost, still know the model.
+func newCallbackConfig(o *Options) *Config {
+	config := &Config{Stop: o.Stop}
+	if o.Model != nil {
+		config.Model = *o.Model
+	}
+	if o.MaxTokens != nil {
+		config.MaxTokens = *o.MaxTokens
+	}
+	if o.Temperature != nil {
+		config.Temperature = *o.Temperature
+	}
+	if o.TopP != nil {
+		config.TopP = *o.TopP
+	}
+	return config
+}
+
+// newCallbackOutput reports the message with the token usage in its response meta.
+func newCallbackOutput(msg *schema.Message, config *Config) *CallbackOutput {
+	out := &CallbackOutput{Message: msg, Config: config}
+	if msg != nil && msg.ResponseMeta != nil && msg.ResponseMeta.Usage != nil {
+		usage := msg.ResponseMeta.Usage
+		out.TokenUsage = &TokenUsage{
+			PromptTokens:           usage.PromptTokens,
+			PromptTokenDetails:     PromptTokenDetails{CachedTokens: usage.PromptTokenDetails.CachedTokens},
+			CompletionTokens:       usage.CompletionTokens,
+			CompletionTokenDetails: CompletionTokenDetails{ReasoningTokens: usage.CompletionTokenDetails.ReasoningTokens},
+			TotalTokens:            usage.TotalTokens,
+		}
+	}
+	return out
+}
@@ -0,0 +1,253 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	icb "github.com/cloudwego/eino/internal/callbacks"
+	"github.com/cloudwego/eino/schema"
+)
+
+// DefaultGuardrailRefusal is the content of the refusal message when GuardrailViolation.Refusal is empty.
+const DefaultGuardrailRefusal = "Sorry, I can't help with that."
+
+// GuardrailViolation is the result of a guardrail blocking the model input or output.
+// It's returned as the error of the guarded chat model, so that the agent run stops there,
+// agents may turn it into a refusal message by AsGuardrailViolation.
+type GuardrailViolation struct {
+	// Guardrail is the name of the guardrail which blocks the message.
+	Guardrail string
+	// Reason is why the message is blocked, for logging and auditing, it won't be shown to the user.
+	Reason string
+	// Refusal is the content of the assistant message to be shown to the user instead.
+	// Optional. Defaults to DefaultGuardrailRefusal.
+	Refusal string
+}
+
+func (v *GuardrailViolation) Error() string {
+	return fmt.Sprintf("blocked by guardrail[%s]: %s", v.Guardrail, v.Reason)
+}
+
+// RefusalMessage returns the assistant message which replaces the blocked message.
+func (v *GuardrailViolation) RefusalMessage() *schema.Message {
+	if v.Refusal == "" {
+		return schema.AssistantMessage(DefaultGuardrailRefusal, nil)
+	}
+	return schema.AssistantMessage(v.Refusal, nil)
+}
+
+// AsGuardrailViolation finds the GuardrailViolation in the error chain.
+func AsGuardrailViolation(err error) (*GuardrailViolation, bool) {
+	var v *GuardrailViolation
+	if errors.As(err, &v) {
+		return v, true
+	}
+	return nil, false
+}
+
+// Guardrail inspects the messages sent to and received from the chat model.
+// Either check can modify the messages, e.g. masking PII, or block them by returning a violation.
+// A returned error aborts the run as a normal error.
+// e.g.
+//
+//	noSecret := model.Guardrail{
+//		Name: "no_secret",
+//		CheckOutput: func(ctx context.Context, output *schema.Message) (*schema.Message, *model.GuardrailViolation, error) {
+//			if strings.Contains(output.Content, secret) {
+//				return nil, &model.GuardrailViolation{Reason: "secret leaked"}, nil
+//			}
+//			return output, nil, nil
+//		},
+//	}
+type Guardrail struct {
+	// Name identifies the guardrail in GuardrailViolation.
+	Name string
+	// CheckInput is called with the messages before they are sent to the chat model. Optional.
+	CheckInput func(ctx context.Context, input []*schema.Message) ([]*schema.Message, *GuardrailViolation, error)
+	// CheckOutput is called with the message generated by the chat model. Optional.
+	// In stream mode the output is concatenated before checking, and sent on as a single chunk stream,
+	// so the output is streamed chunk by chunk only if no guardrail checks the output.
+	CheckOutput func(ctx context.Context, output *schema.Message) (*schema.Message, *GuardrailViolation, error)
+}
+
+// NewGuardedChatModel wraps the chat model with guardrails, which are applied in order.
+// The guarded model triggers callbacks itself with the checked messages,
+// so callback handlers, e.g. the ones emitting agent events, never see the blocked content.
+func NewGuardedChatModel(m BaseChatModel, guardrails ...Guardrail) BaseChatModel {
+	if len(guardrails) == 0 {
+		return m
+	}
+	return &guardedChatModel{inner: m, guardrails: guardrails}
+}
+
+type guardedChatModel struct {
+	inner      BaseChatModel
+	guardrails []Guardrail
+}
+
+func (g *guardedChatModel) GetType() string {
+	if typ, ok := components.GetType(g.inner); ok {
+		return typ
+	}
+	return "GuardedChatModel"
+}
+
+func (g *guardedChatModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+func (g *guardedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...Option) (
+	output *schema.Message, err error) {
+
+	ctx = callbacks.EnsureRunInfo(ctx, g.GetType(), components.ComponentOfChatModel)
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	input, err = g.checkInput(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	config := newCallbackConfig(GetCommonOptions(nil, opts...))
+	ctx = callbacks.OnStart(ctx, &CallbackInput{Messages: input, Config: config})
+
+	output, err = g.inner.Generate(withoutCallbacks(ctx), input, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err = g.checkOutput(ctx, output)
+	if err != nil {
+		return nil, err
+	}
+	callbacks.OnEnd(ctx, newCallbackOutput(output, config))
+
+	return output, nil
+}
+
+func (g *guardedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...Option) (
+	output *schema.StreamReader[*schema.Message], err error) {
+
+	ctx = callbacks.EnsureRunInfo(ctx, g.GetType(), components.ComponentOfChatModel)
+	defer func() {
+		if err != nil {
+			callbacks.OnError(ctx, err)
+		}
+	}()
+
+	input, err = g.checkInput(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	config := newCallbackConfig(GetCommonOptions(nil, opts...))
+	ctx = callbacks.OnStart(ctx, &CallbackInput{Messages: input, Config: config})
+
+	sr, err := g.inner.Stream(withoutCallbacks(ctx), input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if !g.checksOutput() {
+		// nothing to check, keep streaming the chunks
+		srs := sr.Copy(2)
+		_, cbOutput := callbacks.OnEndWithStreamOutput(ctx, schema.StreamReaderWithConvert(srs[1],
+			func(msg *schema.Message) (*CallbackOutput, error) {
+				return newCallbackOutput(msg, config), nil
+			}))
+		cbOutput.Close()
+		return srs[0], nil
+	}
+
+	msg, err := schema.ConcatMessageStream(sr)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err = g.checkOutput(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	_, cbOutput := callbacks.OnEndWithStreamOutput(ctx,
+		schema.StreamReaderFromArray([]*CallbackOutput{newCallbackOutput(msg, config)}))
+	cbOutput.Close()
+
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func (g *guardedChatModel) checkInput(ctx context.Context, input []*schema.Message) ([]*schema.Message, error) {
+	for _, gr := range g.guardrails {
+		if gr.CheckInput == nil {
+			continue
+		}
+		checked, violation, err := gr.CheckInput(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail[%s] failed to check input: %w", gr.Name, err)
+		}
+		if violation != nil {
+			return nil, withGuardrailName(violation, gr.Name)
+		}
+		input = checked
+	}
+	return input, nil
+}
+
+func (g *guardedChatModel) checksOutput() bool {
+	for _, gr := range g.guardrails {
+		if gr.CheckOutput != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (g *guardedChatModel) checkOutput(ctx context.Context, output *schema.Message) (*schema.Message, error) {
+	for _, gr := range g.guardrails {
+		if gr.CheckOutput == nil {
+			continue
+		}
+		checked, violation, err := gr.CheckOutput(ctx, output)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail[%s] failed to check output: %w", gr.Name, err)
+		}
+		if violation != nil {
+			return nil, withGuardrailName(violation, gr.Name)
+		}
+		output = checked
+	}
+	return output, nil
+}
+
+func withGuardrailName(violation *GuardrailViolation, name string) *GuardrailViolation {
+	if violation.Guardrail != "" {
+		return violation
+	}
+	v := *violation
+	v.Guardrail = name
+	return &v
+}
+
+// withoutCallbacks hides the callback handlers from the inner model,
+// otherwise the unchecked messages would be reported by the inner model's own callbacks.
+func withoutCallbacks(ctx context.Context) context.Context {
+	return context.WithValue(ctx, icb.CtxManagerKey{}, nil)
+}
@@ -0,0 +1,181 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+type guardrailTestModel struct {
+	output string
+	chunks []string
+}
+
+func (m *guardrailTestModel) Generate(ctx context.Context, _ []*schema.Message, _ ...Option) (*schema.Message, error) {
+	out := schema.AssistantMessage(m.output, nil)
+	callbacks.OnEnd(ctx, &CallbackOutput{Message: out})
+	return out, nil
+}
+
+func (m *guardrailTestModel) Stream(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.StreamReader[*schema.Message], error) {
+	if len(m.chunks) > 0 {
+		msgs := make([]*schema.Message, 0, len(m.chunks))
+		for _, c := range m.chunks {
+			msgs = append(msgs, schema.AssistantMessage(c, nil))
+		}
+		return schema.StreamReaderFromArray(msgs), nil
+	}
+	out, err := m.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{out}), nil
+}
+
+func (m *guardrailTestModel) IsCallbacksEnabled() bool {
+	return true
+}
+
+func TestGuardedChatModel(t *testing.T) {
+	ctx := context.Background()
+
+	redact := Guardrail{
+		Name: "redact",
+		CheckOutput: func(ctx context.Context, output *schema.Message) (*schema.Message, *GuardrailViolation, error) {
+			return schema.AssistantMessage("[redacted]", nil), nil, nil
+		},
+	}
+
+	t.Run("callbacks see checked output only", func(t *testing.T) {
+		var outputs []string
+		var starts int
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				starts++
+				assert.Equal(t, components.ComponentOfChatModel, info.Component)
+				return ctx
+			}).
+			OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+				outputs = append(outputs, ConvCallbackOutput(output).Message.Content)
+				return ctx
+			}).Build()
+
+		m := NewGuardedChatModel(&guardrailTestModel{output: "my phone is 13800000000"}, redact)
+		cbCtx := callbacks.InitCallbacks(ctx, nil, handler)
+		out, err := m.Generate(cbCtx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, "[redacted]", out.Content)
+		assert.Equal(t, 1, starts)
+		assert.Equal(t, []string{"[redacted]"}, outputs)
+	})
+
+	t.Run("violation", func(t *testing.T) {
+		var inputChecked, outputChecked bool
+		block := Guardrail{
+			Name: "block",
+			CheckInput: func(ctx context.Context, input []*schema.Message) ([]*schema.Message, *GuardrailViolation, error) {
+				inputChecked = true
+				return input, nil, nil
+			},
+			CheckOutput: func(ctx context.Context, output *schema.Message) (*schema.Message, *GuardrailViolation, error) {
+				return nil, &GuardrailViolation{Reason: "not allowed"}, nil
+			},
+		}
+		never := Guardrail{
+			Name: "never",
+			CheckOutput: func(ctx context.Context, output *schema.Message) (*schema.Message, *GuardrailViolation, error) {
+				outputChecked = true
+				return output, nil, nil
+			},
+		}
+
+		m := NewGuardedChatModel(&guardrailTestModel{output: "hello"}, block, never)
+		_, err := m.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		v, ok := AsGuardrailViolation(err)
+		assert.True(t, ok)
+		assert.Equal(t, "block", v.Guardrail)
+		assert.Equal(t, DefaultGuardrailRefusal, v.RefusalMessage().Content)
+		assert.True(t, inputChecked)
+		assert.False(t, outputChecked)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		m := NewGuardedChatModel(&guardrailTestModel{output: "hello"}, Guardrail{
+			Name: "broken",
+			CheckInput: func(ctx context.Context, input []*schema.Message) ([]*schema.Message, *GuardrailViolation, error) {
+				return nil, nil, errors.New("checker unavailable")
+			},
+		})
+		_, err := m.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.EqualError(t, err, "guardrail[broken] failed to check input: checker unavailable")
+		_, ok := AsGuardrailViolation(err)
+		assert.False(t, ok)
+	})
+
+	t.Run("model config and streaming", func(t *testing.T) {
+		var models []string
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+				models = append(models, ConvCallbackInput(input).Config.Model)
+				return ctx
+			}).
+			OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+				defer output.Close()
+				for {
+					chunk, err := output.Recv()
+					if err != nil {
+						return ctx
+					}
+					models = append(models, ConvCallbackOutput(chunk).Config.Model)
+				}
+			}).Build()
+
+		inputOnly := Guardrail{
+			Name: "input_only",
+			CheckInput: func(ctx context.Context, input []*schema.Message) ([]*schema.Message, *GuardrailViolation, error) {
+				return input, nil, nil
+			},
+		}
+		m := NewGuardedChatModel(&guardrailTestModel{chunks: []string{"he", "llo"}}, inputOnly)
+		sr, err := m.Stream(callbacks.InitCallbacks(ctx, nil, handler), []*schema.Message{schema.UserMessage("hi")},
+			WithModel("gpt-4o"))
+		assert.NoError(t, err)
+		var chunks []string
+		for {
+			chunk, err := sr.Recv()
+			if err != nil {
+				break
+			}
+			chunks = append(chunks, chunk.Content)
+		}
+		assert.Equal(t, []string{"he", "llo"}, chunks)
+		assert.Equal(t, []string{"gpt-4o", "gpt-4o", "gpt-4o"}, models)
+	})
+
+	t.Run("no guardrails", func(t *testing.T) {
+		inner := &guardrailTestModel{}
+		assert.Equal(t, BaseChatModel(inner), NewGuardedChatModel(inner))
+	})
+}
@@ -0,0 +1,293 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package modeltest provides a scriptable chat model for the tests of agents and graphs,
+// which responds deterministically without network access.
+package modeltest
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Response is a scripted response of the model.
+type Response struct {
+	// Message is the output of the call, for Stream it's returned as a single chunk if Chunks is empty.
+	Message *schema.Message
+	// Chunks are the chunks of the output stream, for Generate they are concatenated as the output.
+	Chunks []*schema.Message
+	// Delay is the time before the call returns.
+	Delay time.Duration
+	// ChunkInterval is the time before each chunk of the output stream.
+	ChunkInterval time.Duration
+	// Err fails the call.
+	Err error
+	// StreamErr fails the output stream after all the chunks are sent, for Generate it fails the call.
+	StreamErr error
+}
+
+// Text is a response with an assistant message of the content.
+func Text(content string) *Response {
+	return &Response{Message: schema.AssistantMessage(content, nil)}
+}
+
+// ToolCalls is a response with an assistant message calling the tools.
+func ToolCalls(calls ...schema.ToolCall) *Response {
+	return &Response{Message: schema.AssistantMessage("", calls)}
+}
+
+// ToolCall is a tool call with the name and the arguments in JSON, its ID is derived from the name.
+func ToolCall(name, arguments string) schema.ToolCall {
+	return schema.ToolCall{ID: "call_" + name, Function: schema.FunctionCall{Name: name, Arguments: arguments}}
+}
+
+// Stream is a response streaming the content in chunks, with the interval before each chunk.
+func Stream(interval time.Duration, contents ...string) *Response {
+	chunks := make([]*schema.Message, len(contents))
+	for i, c := range contents {
+		chunks[i] = schema.AssistantMessage(c, nil)
+	}
+	return &Response{Chunks: chunks, ChunkInterval: interval}
+}
+
+// Error is a response failing the call with err.
+func Error(err error) *Response {
+	return &Response{Err: err}
+}
+
+// Matcher matches the input of a call.
+type Matcher func(input []*schema.Message) bool
+
+// Any matches any input.
+func Any() Matcher {
+	return func([]*schema.Message) bool { return true }
+}
+
+// LastMessageContains matches the input whose last message contains s.
+func LastMessageContains(s string) Matcher {
+	return func(input []*schema.Message) bool {
+		return len(input) > 0 && strings.Contains(input[len(input)-1].Content, s)
+	}
+}
+
+// LastRoleIs matches the input whose last message is of the role, e.g. schema.Tool after tool calls.
+func LastRoleIs(role schema.RoleType) Matcher {
+	return func(input []*schema.Message) bool {
+		return len(input) > 0 && input[len(input)-1].Role == role
+	}
+}
+
+// HasToolResult matches the input containing the result of the tool.
+func HasToolResult(toolName string) Matcher {
+	return func(input []*schema.Message) bool {
+		for _, msg := range input {
+			if msg.Role == schema.Tool && msg.ToolName == toolName {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Rule responds to the calls whose input is matched, with the responses in order.
+type Rule struct {
+	matcher   Matcher
+	responses []*Response
+	repeat    bool
+	next      int
+}
+
+// When creates a rule responding to the matched calls with the responses in order.
+// Once the responses are used up, the rule no longer matches, unless Repeatedly is set.
+func When(matcher Matcher, responses ...*Response) *Rule {
+	return &Rule{matcher: matcher, responses: responses}
+}
+
+// Repeatedly makes the rule respond with the last response once the others are used up.
+func (r *Rule) Repeatedly() *Rule {
+	r.repeat = true
+	return r
+}
+
+// Call is a recorded call of the model.
+type Call struct {
+	Input []*schema.Message
+	// Tools are the tools bound by WithTools, or passed by model.WithTools.
+	Tools   []*schema.ToolInfo
+	Options *model.Options
+	Stream  bool
+}
+
+// ChatModel is a scripted chat model, which responds to each call by the first rule matching its input and having responses left.
+// It's safe for concurrent use, and the models derived by WithTools share the rules and the recorded calls.
+// e.g.
+//
+//	m := modeltest.New(
+//		modeltest.When(modeltest.LastRoleIs(schema.User), modeltest.ToolCalls(modeltest.ToolCall("search", `{"q":"eino"}`))),
+//		modeltest.When(modeltest.HasToolResult("search"), modeltest.Text("eino is a framework")),
+//	)
+//	agent, err := react.NewAgent(ctx, &react.AgentConfig{ToolCallingModel: m, ...})
+type ChatModel struct {
+	script *script
+	tools  []*schema.ToolInfo
+}
+
+type script struct {
+	mu    sync.Mutex
+	rules []*Rule
+	calls []*Call
+}
+
+// New creates a scripted chat model with the rules.
+func New(rules ...*Rule) *ChatModel {
+	return &ChatModel{script: &script{rules: rules}}
+}
+
+// Append adds the rules after the existing ones.
+func (m *ChatModel) Append(rules ...*Rule) {
+	m.script.mu.Lock()
+	defer m.script.mu.Unlock()
+	m.script.rules = append(m.script.rules, rules...)
+}
+
+// Calls returns the recorded calls in order.
+func (m *ChatModel) Calls() []*Call {
+	m.script.mu.Lock()
+	defer m.script.mu.Unlock()
+	return append([]*Call{}, m.script.calls...)
+}
+
+// Generate responds by the matched rule.
+func (m *ChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	resp, err := m.respond(ctx, input, opts, false)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StreamErr != nil {
+		return nil, resp.StreamErr
+	}
+	if len(resp.Chunks) == 0 {
+		return copyMessage(resp.Message), nil
+	}
+	return schema.ConcatMessages(resp.Chunks)
+}
+
+// Stream responds by the matched rule.
+func (m *ChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	resp, err := m.respond(ctx, input, opts, true)
+	if err != nil {
+		return nil, err
+	}
+	chunks := resp.Chunks
+	if len(chunks) == 0 && resp.Message != nil {
+		chunks = []*schema.Message{resp.Message}
+	}
+
+	sr, sw := schema.Pipe[*schema.Message](0)
+	go func() {
+		defer sw.Close()
+		for _, chunk := range chunks {
+			if err := sleep(ctx, resp.ChunkInterval); err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if closed := sw.Send(copyMessage(chunk), nil); closed {
+				return
+			}
+		}
+		if resp.StreamErr != nil {
+			sw.Send(nil, resp.StreamErr)
+		}
+	}()
+	return sr, nil
+}
+
+// WithTools returns a model sharing the rules and the recorded calls, with the tools recorded in the calls.
+func (m *ChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	return &ChatModel{script: m.script, tools: tools}, nil
+}
+
+func (m *ChatModel) GetType() string {
+	return "Scripted"
+}
+
+func (m *ChatModel) respond(ctx context.Context, input []*schema.Message, opts []model.Option, stream bool) (*Response, error) {
+	options := model.GetCommonOptions(&model.Options{Tools: m.tools}, opts...)
+	resp := m.script.match(&Call{Input: input, Tools: options.Tools, Options: options, Stream: stream})
+	if resp == nil {
+		return nil, fmt.Errorf("no scripted response for the input, last message: %v", lastMessage(input))
+	}
+	if err := sleep(ctx, resp.Delay); err != nil {
+		return nil, err
+	}
+	if resp.Err != nil {
+		return nil, resp.Err
+	}
+	return resp, nil
+}
+
+func (s *script) match(call *Call) *Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+	for _, r := range s.rules {
+		if len(r.responses) == 0 || (r.next >= len(r.responses) && !r.repeat) || !r.matcher(call.Input) {
+			continue
+		}
+		if r.next >= len(r.responses) {
+			return r.responses[len(r.responses)-1]
+		}
+		r.next++
+		return r.responses[r.next-1]
+	}
+	return nil
+}
+
+func lastMessage(input []*schema.Message) *schema.Message {
+	if len(input) == 0 {
+		return nil
+	}
+	return input[len(input)-1]
+}
+
+// copyMessage copies the scripted message, so that the modifications of the callers don't affect the repeated responses.
+func copyMessage(msg *schema.Message) *schema.Message {
+	if msg == nil {
+		return nil
+	}
+	c := *msg
+	return &c
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package modeltest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent/react"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestChatModel(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rules", func(t *testing.T) {
+		failure := errors.New("failure")
+		m := New(
+			When(LastMessageContains("fail"), Error(failure)),
+			When(LastMessageContains("hi"), Text("hello"), Text("hello again")),
+			When(Any(), Text("default")).Repeatedly(),
+		)
+
+		_, err := m.Generate(ctx, []*schema.Message{schema.UserMessage("fail")})
+		assert.Equal(t, failure, err)
+		// the only response of the first rule is used up
+		msg, err := m.Generate(ctx, []*schema.Message{schema.UserMessage("fail")})
+		assert.NoError(t, err)
+		assert.Equal(t, "default", msg.Content)
+
+		for _, expected := range []string{"hello", "hello again", "default", "default"} {
+			msg, err = m.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+			assert.NoError(t, err)
+			assert.Equal(t, expected, msg.Content)
+		}
+
+		empty := New(When(Any(), Text("once")))
+		_, err = empty.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		_, err = empty.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.ErrorContains(t, err, "no scripted response")
+		empty.Append(When(Any(), Text("appended")))
+		msg, err = empty.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, "appended", msg.Content)
+	})
+
+	t.Run("stream", func(t *testing.T) {
+		streamErr := errors.New("broken stream")
+		resp := Stream(10*time.Millisecond, "a", "b", "c")
+		m := New(
+			When(Any(), resp, &Response{Chunks: resp.Chunks, StreamErr: streamErr}, Text("single")),
+		)
+
+		start := time.Now()
+		sr, err := m.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		msg, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "abc", msg.Content)
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+		sr, err = m.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		var chunks int
+		for {
+			_, err = sr.Recv()
+			if err != nil {
+				break
+			}
+			chunks++
+		}
+		assert.Equal(t, 3, chunks)
+		assert.Equal(t, streamErr, err)
+
+		sr, err = m.Stream(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		chunk, err := sr.Recv()
+		assert.NoError(t, err)
+		assert.Equal(t, "single", chunk.Content)
+		_, err = sr.Recv()
+		assert.Equal(t, io.EOF, err)
+
+		assert.Len(t, m.Calls(), 3)
+		assert.True(t, m.Calls()[0].Stream)
+	})
+
+	t.Run("react agent", func(t *testing.T) {
+		type searchInput struct {
+			Query string `json:"query"`
+		}
+		search, err := utils.InferTool("search", "search the web", func(_ context.Context, in *searchInput) (string, error) {
+			return "eino is a framework for " + in.Query, nil
+		})
+		assert.NoError(t, err)
+
+		m := New(
+			When(LastRoleIs(schema.User), ToolCalls(ToolCall("search", `{"query":"llm apps"}`))),
+			When(HasToolResult("search"), Text("eino builds llm apps")),
+		)
+		agent, err := react.NewAgent(ctx, &react.AgentConfig{
+			ToolCallingModel: m,
+			ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{search}},
+		})
+		assert.NoError(t, err)
+
+		msg, err := agent.Generate(ctx, []*schema.Message{schema.UserMessage("what is eino")})
+		assert.NoError(t, err)
+		assert.Equal(t, "eino builds llm apps", msg.Content)
+
+		calls := m.Calls()
+		assert.Len(t, calls, 2)
+		assert.Equal(t, "search", calls[0].Tools[0].Name)
+		assert.Equal(t, "eino is a framework for llm apps", calls[1].Input[2].Content)
+	})
+
+	t.Run("options", func(t *testing.T) {
+		m := New(When(Any(), &Response{Message: schema.AssistantMessage("slow", nil), Delay: time.Hour}))
+		cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err := m.Generate(cctx, []*schema.Message{schema.UserMessage("hi")}, model.WithTemperature(0.5))
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, float32(0.5), *m.Calls()[0].Options.Temperature)
+	})
+}
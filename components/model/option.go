@@ -16,7 +16,12 @@
 
 package model
 
-import "github.com/cloudwego/eino/schema"
+import (
+	"sort"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
 
 // Options is the common options for the model.
 type Options struct {
@@ -34,6 +39,100 @@ type Options struct {
 	Tools []*schema.ToolInfo
 	// ToolChoice controls which tool is called by the model.
 	ToolChoice *schema.ToolChoice
+	// PromptCacheHint marks the prefixes of the input to be cached by the provider.
+	PromptCacheHint *PromptCacheHint
+	// Seed makes the sampling deterministic on a best-effort basis, repeated requests with the same seed and parameters should return the same result.
+	Seed *int64
+	// FrequencyPenalty penalizes new tokens based on their frequency in the text so far, decreasing the likelihood to repeat the same line.
+	FrequencyPenalty *float32
+	// PresencePenalty penalizes new tokens based on whether they appear in the text so far, increasing the likelihood to talk about new topics.
+	PresencePenalty *float32
+	// LogitBias modifies the likelihood of the specified tokens appearing in the output, keyed by the token id of the tokenizer of the model.
+	LogitBias map[string]int
+	// ParallelToolCalls controls whether the model may call multiple tools in a single output.
+	ParallelToolCalls *bool
+	// ReasoningEffort constrains the effort on reasoning of reasoning models.
+	ReasoningEffort *ReasoningEffort
+}
+
+// ReasoningEffort is the effort on reasoning of reasoning models.
+type ReasoningEffort string
+
+const (
+	ReasoningEffortLow    ReasoningEffort = "low"
+	ReasoningEffortMedium ReasoningEffort = "medium"
+	ReasoningEffortHigh   ReasoningEffort = "high"
+)
+
+// PromptCacheHint marks the prefixes of the input to be cached by the provider,
+// which implementations map to the provider features, e.g. the cache_control of Anthropic or the prompt caching of OpenAI.
+// The tokens read from the cache are reported by PromptTokenDetails.CachedTokens of the token usage.
+type PromptCacheHint struct {
+	// Breakpoints mark the ends of the cacheable prefixes, providers with implicit caching may ignore them.
+	Breakpoints []PromptCacheBreakpoint
+	// CacheTools marks the tool definitions as cacheable, which precede the messages for most providers.
+	CacheTools bool
+	// TTL is the expected lifetime of the cache, providers may only support specific values, e.g. 5 minutes or 1 hour.
+	// Zero means the default of the provider.
+	TTL time.Duration
+	// Key groups the requests sharing the prefixes to hit the same cache, e.g. the prompt_cache_key of OpenAI.
+	Key string
+}
+
+// PromptCacheBreakpoint marks the end of a cacheable prefix, i.e. the input up to and including the position.
+type PromptCacheBreakpoint struct {
+	// MessageIndex is the index of the message in the input, negative index counts from the end, e.g. -1 is the last message.
+	MessageIndex int
+	// PartIndex is the index of the part in the UserInputMultiContent of the message, counted in the same way as MessageIndex.
+	// nil means the end of the whole message.
+	PartIndex *int
+}
+
+// ResolveBreakpoints returns the breakpoints of the input with non-negative indexes, in the order of the positions.
+// Breakpoints out of range are dropped, and duplicated ones are merged.
+func (h *PromptCacheHint) ResolveBreakpoints(input []*schema.Message) []PromptCacheBreakpoint {
+	if h == nil {
+		return nil
+	}
+	resolve := func(i, n int) (int, bool) {
+		if i < 0 {
+			i += n
+		}
+		return i, i >= 0 && i < n
+	}
+	// position of the whole message is after all its parts
+	pos := func(b PromptCacheBreakpoint) [2]int {
+		if b.PartIndex == nil {
+			return [2]int{b.MessageIndex, len(input[b.MessageIndex].UserInputMultiContent)}
+		}
+		return [2]int{b.MessageIndex, *b.PartIndex}
+	}
+
+	seen := make(map[[2]int]bool, len(h.Breakpoints))
+	ret := make([]PromptCacheBreakpoint, 0, len(h.Breakpoints))
+	for _, b := range h.Breakpoints {
+		mi, ok := resolve(b.MessageIndex, len(input))
+		if !ok {
+			continue
+		}
+		rb := PromptCacheBreakpoint{MessageIndex: mi}
+		if b.PartIndex != nil {
+			pi, ok := resolve(*b.PartIndex, len(input[mi].UserInputMultiContent))
+			if !ok {
+				continue
+			}
+			rb.PartIndex = &pi
+		}
+		if p := pos(rb); !seen[p] {
+			seen[p] = true
+			ret = append(ret, rb)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		pi, pj := pos(ret[i]), pos(ret[j])
+		return pi[0] < pj[0] || (pi[0] == pj[0] && pi[1] < pj[1])
+	})
+	return ret
 }
 
 // Option is the call option for ChatModel component.
@@ -109,6 +208,76 @@ func WithToolChoice(toolChoice schema.ToolChoice) Option {
 	}
 }
 
+// WithPromptCacheHint is the option to mark the prefixes of the input to be cached by the provider.
+// e.g.
+//
+//	// cache the tools, the system prompt and the conversation history before the latest message
+//	model.WithPromptCacheHint(&model.PromptCacheHint{
+//		CacheTools:  true,
+//		Breakpoints: []model.PromptCacheBreakpoint{{MessageIndex: 0}, {MessageIndex: -2}},
+//	})
+func WithPromptCacheHint(hint *PromptCacheHint) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.PromptCacheHint = hint
+		},
+	}
+}
+
+// WithSeed is the option to set the seed for deterministic sampling.
+func WithSeed(seed int64) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Seed = &seed
+		},
+	}
+}
+
+// WithFrequencyPenalty is the option to set the frequency penalty for the model.
+func WithFrequencyPenalty(penalty float32) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.FrequencyPenalty = &penalty
+		},
+	}
+}
+
+// WithPresencePenalty is the option to set the presence penalty for the model.
+func WithPresencePenalty(penalty float32) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.PresencePenalty = &penalty
+		},
+	}
+}
+
+// WithLogitBias is the option to set the logit bias of tokens for the model.
+func WithLogitBias(logitBias map[string]int) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.LogitBias = logitBias
+		},
+	}
+}
+
+// WithParallelToolCalls is the option to set whether the model may call multiple tools in a single output.
+func WithParallelToolCalls(parallel bool) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.ParallelToolCalls = &parallel
+		},
+	}
+}
+
+// WithReasoningEffort is the option to set the reasoning effort for reasoning models.
+func WithReasoningEffort(effort ReasoningEffort) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.ReasoningEffort = &effort
+		},
+	}
+}
+
 // WrapImplSpecificOptFn is the option to wrap the implementation specific option function.
 func WrapImplSpecificOptFn[T any](optFn func(*T)) Option {
 	return Option{
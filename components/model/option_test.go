@@ -17,6 +17,7 @@
 package model
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/cloudwego/eino/schema"
@@ -36,6 +37,11 @@ func TestOptions(t *testing.T) {
 			defaultTopP        float32 = 0.5
 			tools                      = []*schema.ToolInfo{{Name: "asd"}, {Name: "qwe"}}
 			toolChoice                 = schema.ToolChoiceForced
+			seed               int64   = 42
+			frequencyPenalty   float32 = 0.5
+			presencePenalty    float32 = -0.5
+			parallelToolCalls          = false
+			reasoningEffort            = ReasoningEffortLow
 		)
 
 		opts := GetCommonOptions(
@@ -52,6 +58,12 @@ func TestOptions(t *testing.T) {
 			WithStop([]string{"hello", "bye"}),
 			WithTools(tools),
 			WithToolChoice(toolChoice),
+			WithSeed(seed),
+			WithFrequencyPenalty(0.5),
+			WithPresencePenalty(-0.5),
+			WithLogitBias(map[string]int{"1": -100}),
+			WithParallelToolCalls(false),
+			WithReasoningEffort(ReasoningEffortLow),
 		)
 
 		convey.So(opts, convey.ShouldResemble, &Options{
@@ -62,6 +74,13 @@ func TestOptions(t *testing.T) {
 			Stop:        []string{"hello", "bye"},
 			Tools:       tools,
 			ToolChoice:  &toolChoice,
+
+			Seed:              &seed,
+			FrequencyPenalty:  &frequencyPenalty,
+			PresencePenalty:   &presencePenalty,
+			LogitBias:         map[string]int{"1": -100},
+			ParallelToolCalls: &parallelToolCalls,
+			ReasoningEffort:   &reasoningEffort,
 		})
 	})
 
@@ -108,3 +127,55 @@ func TestImplSpecificOption(t *testing.T) {
 		})
 	})
 }
+
+func TestCheckSupportedOptions(t *testing.T) {
+	convey.Convey("check supported options", t, func() {
+		convey.So(CheckSupportedOptions("m", nil), convey.ShouldBeNil)
+
+		opts := GetCommonOptions(nil, WithTemperature(0.5), WithSeed(1), WithLogitBias(map[string]int{}))
+		convey.So(CheckSupportedOptions("m", opts, "Temperature", "Seed", "LogitBias"), convey.ShouldBeNil)
+
+		err := CheckSupportedOptions("m", opts, "Temperature")
+		convey.So(errors.Is(err, ErrUnsupportedOption), convey.ShouldBeTrue)
+		var uErr *UnsupportedOptionError
+		convey.So(errors.As(err, &uErr), convey.ShouldBeTrue)
+		convey.So(uErr.Options, convey.ShouldResemble, []string{"Seed", "LogitBias"})
+		convey.So(err.Error(), convey.ShouldEqual, "model[m] doesn't support options: Seed, LogitBias")
+	})
+}
+
+func TestPromptCacheHint(t *testing.T) {
+	convey.Convey("prompt cache hint", t, func() {
+		hint := &PromptCacheHint{CacheTools: true, Key: "user_1"}
+		opts := GetCommonOptions(nil, WithPromptCacheHint(hint))
+		convey.So(opts.PromptCacheHint, convey.ShouldEqual, hint)
+
+		input := []*schema.Message{
+			schema.SystemMessage("system"),
+			{Role: schema.User, UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "document"},
+				{Type: schema.ChatMessagePartTypeText, Text: "question"},
+			}},
+			schema.AssistantMessage("answer", nil),
+		}
+		first, last, outOfRange := 0, -1, 5
+		hint.Breakpoints = []PromptCacheBreakpoint{
+			{MessageIndex: -1},
+			{MessageIndex: 1, PartIndex: &first},
+			{MessageIndex: 0},
+			{MessageIndex: -2, PartIndex: &last},
+			{MessageIndex: 1},
+			{MessageIndex: 1, PartIndex: &outOfRange},
+			{MessageIndex: -4},
+		}
+		one := 1
+		convey.So(hint.ResolveBreakpoints(input), convey.ShouldResemble, []PromptCacheBreakpoint{
+			{MessageIndex: 0},
+			{MessageIndex: 1, PartIndex: &first},
+			{MessageIndex: 1, PartIndex: &one},
+			{MessageIndex: 1},
+			{MessageIndex: 2},
+		})
+		convey.So((*PromptCacheHint)(nil).ResolveBreakpoints(input), convey.ShouldBeNil)
+	})
+}
@@ -0,0 +1,274 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RateLimitError is implemented by the errors of model implementations when the request is throttled by the provider,
+// so that NewRetrying can tell throttling apart from other errors and honor the wait time suggested by the provider.
+type RateLimitError interface {
+	error
+	// RetryAfter returns the wait time suggested by the provider, e.g. from the Retry-After header, ok is false if absent.
+	RetryAfter() (d time.Duration, ok bool)
+}
+
+// NewRateLimitError wraps err as a RateLimitError, retryAfter is zero if the provider doesn't suggest a wait time.
+func NewRateLimitError(err error, retryAfter time.Duration) error {
+	return &rateLimitError{err: err, retryAfter: retryAfter}
+}
+
+type rateLimitError struct {
+	err        error
+	retryAfter time.Duration
+}
+
+func (r *rateLimitError) Error() string {
+	return fmt.Sprintf("rate limited: %v", r.err)
+}
+
+func (r *rateLimitError) Unwrap() error {
+	return r.err
+}
+
+func (r *rateLimitError) RetryAfter() (time.Duration, bool) {
+	return r.retryAfter, r.retryAfter > 0
+}
+
+func (r *rateLimitError) ErrorCategory() components.ErrorCategory {
+	return components.ErrorCategoryRateLimited
+}
+
+// Limiter smooths the calls of a RetryingChatModel.
+// compose.NewTokenBucketLimiter, compose.NewSemaphoreLimiter and compose.NewResourceNodeLimiter can be used as a Limiter.
+type Limiter interface {
+	// Acquire blocks until the call is allowed or ctx is done, release is called once the call returns.
+	Acquire(ctx context.Context) (release func(), err error)
+}
+
+// RetryOption is the option of NewRetrying.
+type RetryOption func(o *retryOptions)
+
+type retryOptions struct {
+	maxRetries  int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	isRetryable func(err error) bool
+	limiter     Limiter
+}
+
+// WithMaxRetries sets the max times of retry of a call. Optional, defaults to 3.
+func WithMaxRetries(maxRetries int) RetryOption {
+	return func(o *retryOptions) {
+		o.maxRetries = maxRetries
+	}
+}
+
+// WithRetryBackoff sets the exponential backoff used when the provider doesn't suggest a wait time,
+// the n-th retry waits for a jittered baseDelay*2^(n-1), capped by maxDelay. Optional, defaults to 1s and 30s.
+func WithRetryBackoff(baseDelay, maxDelay time.Duration) RetryOption {
+	return func(o *retryOptions) {
+		o.baseDelay = baseDelay
+		o.maxDelay = maxDelay
+	}
+}
+
+// WithRetryableFunc additionally retries the errors for which isRetryable returns true, e.g. transient network errors.
+// A RateLimitError is always retried.
+func WithRetryableFunc(isRetryable func(err error) bool) RetryOption {
+	return func(o *retryOptions) {
+		o.isRetryable = isRetryable
+	}
+}
+
+// WithLimiter sets the limiter every attempt acquires before calling the model, e.g. a token bucket matching the quota of the provider.
+// Share the same RetryingChatModel, or the same limiter, across goroutines to smooth their calls together.
+func WithLimiter(limiter Limiter) RetryOption {
+	return func(o *retryOptions) {
+		o.limiter = limiter
+	}
+}
+
+// NewRetrying wraps the model to retry the calls throttled by the provider, i.e. failed by a RateLimitError.
+// A wait time suggested by the provider is honored, otherwise an exponential backoff with jitter is used.
+// The throttling pauses all the calls of the model, including the ones of other goroutines and of the models derived by WithTools,
+// until the suggested wait time has passed, instead of each of them hitting the provider again.
+// For Stream, only the errors before the stream is returned are retried.
+// e.g.
+//
+//	cm, err := model.NewRetrying(chatModel,
+//		model.WithMaxRetries(5),
+//		model.WithLimiter(compose.NewTokenBucketLimiter(10, 20)))
+func NewRetrying(inner BaseChatModel, opts ...RetryOption) (*RetryingChatModel, error) {
+	if inner == nil {
+		return nil, errors.New("model is required")
+	}
+	o := &retryOptions{
+		maxRetries: 3,
+		baseDelay:  time.Second,
+		maxDelay:   30 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &RetryingChatModel{inner: inner, options: o, gate: &throttleGate{}}, nil
+}
+
+// RetryingChatModel is a chat model retrying the throttled calls, see NewRetrying.
+type RetryingChatModel struct {
+	inner   BaseChatModel
+	options *retryOptions
+	// gate is shared by the models derived by WithTools
+	gate *throttleGate
+}
+
+// throttleGate holds the calls until the wait time of the latest throttling has passed.
+type throttleGate struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+func (g *throttleGate) wait(ctx context.Context) error {
+	g.mu.Lock()
+	d := time.Until(g.until)
+	g.mu.Unlock()
+	return sleep(ctx, d)
+}
+
+func (g *throttleGate) hold(d time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if until := time.Now().Add(d); until.After(g.until) {
+		g.until = until
+	}
+}
+
+func (r *RetryingChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+	return retryCall(ctx, r, func(ctx context.Context) (*schema.Message, error) {
+		return r.inner.Generate(ctx, input, opts...)
+	})
+}
+
+func (r *RetryingChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.StreamReader[*schema.Message], error) {
+	return retryCall(ctx, r, func(ctx context.Context) (*schema.StreamReader[*schema.Message], error) {
+		return r.inner.Stream(ctx, input, opts...)
+	})
+}
+
+func (r *RetryingChatModel) WithTools(tools []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	tcm, ok := r.inner.(ToolCallingChatModel)
+	if !ok {
+		return nil, fmt.Errorf("model[%T] isn't a ToolCallingChatModel", r.inner)
+	}
+	inner, err := tcm.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &RetryingChatModel{inner: inner, options: r.options, gate: r.gate}, nil
+}
+
+func (r *RetryingChatModel) GetType() string {
+	if typ, ok := components.GetType(r.inner); ok {
+		return typ
+	}
+	return "RetryingChatModel"
+}
+
+func (r *RetryingChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(r.inner)
+}
+
+func retryCall[T any](ctx context.Context, r *RetryingChatModel, call func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	for attempt := 0; ; attempt++ {
+		waitStart := time.Now()
+		if err := r.gate.wait(ctx); err != nil {
+			return zero, err
+		}
+		release := func() {}
+		if r.options.limiter != nil {
+			var err error
+			if release, err = r.options.limiter.Acquire(ctx); err != nil {
+				return zero, err
+			}
+		}
+		// the callbacks of the inner model see the attempt and the time waited for the gate and the limiter
+		callCtx := callbacks.WithRunAttempt(ctx, attempt+1)
+		callCtx = callbacks.WithQueueWait(callCtx, time.Since(waitStart))
+		ret, err := call(callCtx)
+		release()
+		if err == nil {
+			return ret, nil
+		}
+
+		var rlErr RateLimitError
+		throttled := errors.As(err, &rlErr)
+		if attempt >= r.options.maxRetries || (!throttled && (r.options.isRetryable == nil || !r.options.isRetryable(err))) {
+			return zero, err
+		}
+
+		if throttled {
+			if d, ok := rlErr.RetryAfter(); ok {
+				r.gate.hold(d)
+				continue
+			}
+		}
+		if err = sleep(ctx, backoff(r.options.baseDelay, r.options.maxDelay, attempt)); err != nil {
+			return zero, err
+		}
+	}
+}
+
+// backoff returns the jittered delay of the attempt, in [d/2, d) where d = baseDelay*2^attempt capped by maxDelay.
+func backoff(baseDelay, maxDelay time.Duration, attempt int) time.Duration {
+	d := baseDelay
+	for i := 0; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	if d > maxDelay {
+		d = maxDelay
+	}
+	half := d / 2
+	if half <= 0 {
+		return d
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
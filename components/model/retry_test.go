@@ -0,0 +1,216 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// flakyModel fails with the errors in order, then succeeds.
+type flakyModel struct {
+	mu    sync.Mutex
+	errs  []error
+	calls []time.Time
+}
+
+func (f *flakyModel) call() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls = append(f.calls, time.Now())
+	if len(f.errs) == 0 {
+		return nil
+	}
+	err := f.errs[0]
+	f.errs = f.errs[1:]
+	return err
+}
+
+func (f *flakyModel) Generate(_ context.Context, _ []*schema.Message, _ ...Option) (*schema.Message, error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	return schema.AssistantMessage("ok", nil), nil
+}
+
+func (f *flakyModel) WithTools(_ []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	return f, nil
+}
+
+func (f *flakyModel) Stream(_ context.Context, _ []*schema.Message, _ ...Option) (*schema.StreamReader[*schema.Message], error) {
+	if err := f.call(); err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("ok", nil)}), nil
+}
+
+// timedModel runs the callbacks of flakyModel.
+type timedModel struct {
+	*flakyModel
+	handler callbacks.Handler
+}
+
+func (m *timedModel) Generate(ctx context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+	ctx = callbacks.OnStart(callbacks.InitCallbacks(ctx, &callbacks.RunInfo{Name: "flaky"}, m.handler), input)
+	return m.flakyModel.Generate(ctx, input, opts...)
+}
+
+type countingLimiter struct {
+	acquired int32
+}
+
+func (c *countingLimiter) Acquire(_ context.Context) (func(), error) {
+	atomic.AddInt32(&c.acquired, 1)
+	return func() {}, nil
+}
+
+func TestRetryingChatModel(t *testing.T) {
+	ctx := context.Background()
+	throttled := errors.New("429 too many requests")
+	input := []*schema.Message{schema.UserMessage("hi")}
+
+	t.Run("retry after", func(t *testing.T) {
+		inner := &flakyModel{errs: []error{
+			NewRateLimitError(throttled, 30*time.Millisecond),
+			NewRateLimitError(throttled, 0),
+		}}
+		limiter := &countingLimiter{}
+		cm, err := NewRetrying(inner, WithRetryBackoff(10*time.Millisecond, 20*time.Millisecond), WithLimiter(limiter))
+		assert.NoError(t, err)
+
+		msg, err := cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", msg.Content)
+		assert.Len(t, inner.calls, 3)
+		assert.GreaterOrEqual(t, inner.calls[1].Sub(inner.calls[0]), 30*time.Millisecond)
+		// backoff of the first retry without a suggested wait time is in [5ms, 10ms)
+		assert.GreaterOrEqual(t, inner.calls[2].Sub(inner.calls[1]), 5*time.Millisecond)
+		assert.Equal(t, int32(3), limiter.acquired)
+	})
+
+	t.Run("error category", func(t *testing.T) {
+		assert.Equal(t, components.ErrorCategoryRateLimited, components.ClassifyError(NewRateLimitError(throttled, 0)))
+	})
+
+	t.Run("shared pause", func(t *testing.T) {
+		inner := &flakyModel{errs: []error{NewRateLimitError(throttled, 50*time.Millisecond)}}
+		cm, err := NewRetrying(inner)
+		assert.NoError(t, err)
+		tcm, err := cm.WithTools(nil)
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := cm.Stream(ctx, input)
+			assert.NoError(t, err)
+		}()
+		// wait for the throttling of the first call
+		assert.Eventually(t, func() bool {
+			cm.gate.mu.Lock()
+			defer cm.gate.mu.Unlock()
+			return !cm.gate.until.IsZero()
+		}, time.Second, time.Millisecond)
+
+		// the calls started during the pause wait for it too, including the ones of the derived model
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := tcm.Generate(ctx, input)
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		assert.Len(t, inner.calls, 5)
+		for _, c := range inner.calls[1:] {
+			assert.GreaterOrEqual(t, c.Sub(inner.calls[0]), 50*time.Millisecond)
+		}
+	})
+
+	t.Run("not retryable", func(t *testing.T) {
+		other := errors.New("bad request")
+		inner := &flakyModel{errs: []error{other}}
+		cm, err := NewRetrying(inner)
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, input)
+		assert.Equal(t, other, err)
+		assert.Len(t, inner.calls, 1)
+
+		inner = &flakyModel{errs: []error{other}}
+		cm, err = NewRetrying(inner, WithRetryableFunc(func(err error) bool { return err == other }), WithRetryBackoff(time.Millisecond, time.Millisecond))
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Len(t, inner.calls, 2)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		inner := &flakyModel{errs: []error{
+			NewRateLimitError(throttled, time.Millisecond),
+			NewRateLimitError(throttled, time.Millisecond),
+		}}
+		cm, err := NewRetrying(inner, WithMaxRetries(1))
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, input)
+		var rlErr RateLimitError
+		assert.True(t, errors.As(err, &rlErr))
+		assert.True(t, errors.Is(err, throttled))
+		assert.Len(t, inner.calls, 2)
+	})
+
+	t.Run("attempt timing", func(t *testing.T) {
+		inner := &flakyModel{errs: []error{NewRateLimitError(throttled, 20*time.Millisecond)}}
+		var timings []callbacks.RunTiming
+		handler := callbacks.NewHandlerBuilder().
+			OnStartFn(func(ctx context.Context, _ *callbacks.RunInfo, _ callbacks.CallbackInput) context.Context {
+				tm, ok := callbacks.GetRunTiming(ctx)
+				assert.True(t, ok)
+				timings = append(timings, *tm)
+				return ctx
+			}).Build()
+		cm, err := NewRetrying(&timedModel{flakyModel: inner, handler: handler})
+		assert.NoError(t, err)
+		_, err = cm.Generate(ctx, input)
+		assert.NoError(t, err)
+		assert.Len(t, timings, 2)
+		assert.Equal(t, 1, timings[0].Attempt)
+		assert.Equal(t, 2, timings[1].Attempt)
+		assert.GreaterOrEqual(t, timings[1].QueueWait, 20*time.Millisecond)
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		inner := &flakyModel{errs: []error{NewRateLimitError(throttled, time.Hour)}}
+		cm, err := NewRetrying(inner)
+		assert.NoError(t, err)
+		cctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err = cm.Generate(cctx, input)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,226 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/internal/schemavalidate"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultStructuredOutputName       = "structured_output"
+	defaultStructuredOutputMaxRetries = 2
+)
+
+// StructuredOption is the option of GenerateStructured.
+type StructuredOption func(o *structuredOptions)
+
+type structuredOptions struct {
+	name         string
+	desc         string
+	nativeOption func(name string, js *jsonschema.Schema) Option
+	maxRetries   int
+	validator    func(v any) error
+	modelOptions []Option
+}
+
+// WithStructuredOutputName sets the name and the description of the output schema, which is the tool name in the tool call fallback.
+// Optional. Defaults to "structured_output".
+func WithStructuredOutputName(name, desc string) StructuredOption {
+	return func(o *structuredOptions) {
+		o.name = name
+		o.desc = desc
+	}
+}
+
+// WithNativeStructuredOutput sets the constructor of the structured output option of the model implementation,
+// e.g. the response format option of an OpenAI compatible model.
+// Without it, the output is obtained by forcing the model to call a tool whose parameters are the output schema.
+func WithNativeStructuredOutput(newOption func(name string, js *jsonschema.Schema) Option) StructuredOption {
+	return func(o *structuredOptions) {
+		o.nativeOption = newOption
+	}
+}
+
+// WithStructuredMaxRetries sets the max times of retry when the output is invalid, each retry feeds the error back to the model.
+// Optional. Defaults to 2, negative means no retry.
+func WithStructuredMaxRetries(maxRetries int) StructuredOption {
+	return func(o *structuredOptions) {
+		o.maxRetries = maxRetries
+	}
+}
+
+// WithStructuredValidator adds a validation of the parsed output, which receives a *T.
+// A T implementing interface{ Validate() error } is validated by it as well.
+func WithStructuredValidator(validator func(v any) error) StructuredOption {
+	return func(o *structuredOptions) {
+		o.validator = validator
+	}
+}
+
+// WithStructuredModelOptions sets the options passed to the model in each call.
+func WithStructuredModelOptions(opts ...Option) StructuredOption {
+	return func(o *structuredOptions) {
+		o.modelOptions = append(o.modelOptions, opts...)
+	}
+}
+
+// StructuredOutputError is returned by GenerateStructured when the output is still invalid after all the retries.
+type StructuredOutputError struct {
+	// Output is the last output of the model.
+	Output *schema.Message
+	Err    error
+}
+
+func (e *StructuredOutputError) Error() string {
+	return fmt.Sprintf("invalid structured output: %v", e.Err)
+}
+
+func (e *StructuredOutputError) Unwrap() error {
+	return e.Err
+}
+
+// GenerateStructured generates an output of type T by the model.
+// T must be a struct, the JSON schema of the output is derived from it in the same way as the parameters of the tools inferred by utils.InferTool.
+// The schema is applied by the native structured output option of the model if set by WithNativeStructuredOutput,
+// otherwise by forcing the model to call a tool whose parameters are the schema.
+// The output is validated against the schema, then parsed and validated by WithStructuredValidator if set,
+// if invalid, the error is fed back to the model to retry.
+// e.g.
+//
+//	type Weather struct {
+//		City        string  `json:"city" jsonschema:"required"`
+//		Temperature float64 `json:"temperature" jsonschema:"required,description=in celsius"`
+//	}
+//	w, err := model.GenerateStructured[Weather](ctx, chatModel, []*schema.Message{schema.UserMessage("it's 25 degrees in Beijing")})
+func GenerateStructured[T any](ctx context.Context, m BaseChatModel, input []*schema.Message, opts ...StructuredOption) (T, error) {
+	var zero T
+	o := &structuredOptions{
+		name:       defaultStructuredOutputName,
+		maxRetries: defaultStructuredOutputMaxRetries,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	params, err := utils.GoStruct2ParamsOneOf[T]()
+	if err != nil {
+		return zero, err
+	}
+	js, err := params.ToJSONSchema()
+	if err != nil {
+		return zero, err
+	}
+
+	callOpts := append([]Option{}, o.modelOptions...)
+	if o.nativeOption != nil {
+		callOpts = append(callOpts, o.nativeOption(o.name, js))
+	} else {
+		toolInfo := &schema.ToolInfo{Name: o.name, Desc: o.desc, ParamsOneOf: params}
+		if toolInfo.Desc == "" {
+			toolInfo.Desc = "Responds with the output in the structured format."
+		}
+		if tcm, ok := m.(ToolCallingChatModel); ok {
+			if m, err = tcm.WithTools([]*schema.ToolInfo{toolInfo}); err != nil {
+				return zero, err
+			}
+		} else {
+			callOpts = append(callOpts, WithTools([]*schema.ToolInfo{toolInfo}))
+		}
+		callOpts = append(callOpts, WithToolChoice(schema.ToolChoiceForced))
+	}
+
+	messages := append(make([]*schema.Message, 0, len(input)+2*(o.maxRetries+1)), input...)
+	for i := 0; ; i++ {
+		output, err := m.Generate(ctx, messages, callOpts...)
+		if err != nil {
+			return zero, err
+		}
+
+		var result T
+		var toolCallID string
+		if o.nativeOption != nil {
+			err = parseStructuredOutput(output.Content, js, &result)
+		} else if len(output.ToolCalls) == 0 {
+			err = fmt.Errorf("the output must be returned by calling the tool[%s]", o.name)
+		} else {
+			toolCallID = output.ToolCalls[0].ID
+			err = parseStructuredOutput(output.ToolCalls[0].Function.Arguments, js, &result)
+		}
+		if err == nil {
+			err = validateStructuredOutput(&result, o.validator)
+		}
+		if err == nil {
+			return result, nil
+		}
+		if i >= o.maxRetries {
+			return zero, &StructuredOutputError{Output: output, Err: err}
+		}
+
+		feedback := fmt.Sprintf("The output is invalid: %v\nPlease fix it and respond again.", err)
+		messages = append(messages, output)
+		if toolCallID != "" {
+			// every tool call must be followed by a tool message
+			messages = append(messages, schema.ToolMessage(feedback, toolCallID, schema.WithToolName(o.name)))
+		} else {
+			messages = append(messages, schema.UserMessage(feedback))
+		}
+	}
+}
+
+func parseStructuredOutput(s string, js *jsonschema.Schema, result any) error {
+	s = strings.TrimSpace(s)
+	// models may wrap the json in a markdown code block
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimPrefix(s, "```json")
+		s = strings.TrimPrefix(s, "```")
+		s = strings.TrimSuffix(s, "```")
+	}
+
+	if violations := schemavalidate.Validate(js, s); len(violations) > 0 {
+		msgs := make([]string, len(violations))
+		for i, v := range violations {
+			msgs[i] = v.Error()
+		}
+		return fmt.Errorf("the output doesn't match the schema: %s", strings.Join(msgs, "; "))
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(result)
+}
+
+func validateStructuredOutput(result any, validator func(v any) error) error {
+	if v, ok := result.(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	if validator != nil {
+		return validator(result)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/eino-contrib/jsonschema"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type weather struct {
+	City        string  `json:"city"`
+	Temperature float64 `json:"temperature"`
+	Note        string  `json:"note,omitempty"`
+}
+
+func (w *weather) Validate() error {
+	if w.Temperature < -100 {
+		return errors.New("temperature is too low")
+	}
+	return nil
+}
+
+type nativeFormatOptions struct {
+	name string
+	js   *jsonschema.Schema
+}
+
+func withNativeFormat(name string, js *jsonschema.Schema) Option {
+	return WrapImplSpecificOptFn(func(o *nativeFormatOptions) {
+		o.name = name
+		o.js = js
+	})
+}
+
+// scriptedModel returns the outputs in order, and records the inputs and options of each call.
+type scriptedModel struct {
+	outputs []*schema.Message
+	inputs  [][]*schema.Message
+	options []*Options
+	native  []*nativeFormatOptions
+	tools   []*schema.ToolInfo
+}
+
+func (s *scriptedModel) Generate(_ context.Context, input []*schema.Message, opts ...Option) (*schema.Message, error) {
+	s.inputs = append(s.inputs, input)
+	s.options = append(s.options, GetCommonOptions(nil, opts...))
+	s.native = append(s.native, GetImplSpecificOptions(&nativeFormatOptions{}, opts...))
+	out := s.outputs[0]
+	s.outputs = s.outputs[1:]
+	return out, nil
+}
+
+func (s *scriptedModel) Stream(_ context.Context, _ []*schema.Message, _ ...Option) (*schema.StreamReader[*schema.Message], error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *scriptedModel) WithTools(tools []*schema.ToolInfo) (ToolCallingChatModel, error) {
+	s.tools = tools
+	return s, nil
+}
+
+func toolCallOutput(args string) *schema.Message {
+	return schema.AssistantMessage("", []schema.ToolCall{{ID: "call", Function: schema.FunctionCall{Name: "structured_output", Arguments: args}}})
+}
+
+func TestGenerateStructured(t *testing.T) {
+	ctx := context.Background()
+	input := []*schema.Message{schema.UserMessage("weather in Beijing")}
+
+	t.Run("tool call", func(t *testing.T) {
+		m := &scriptedModel{outputs: []*schema.Message{
+			toolCallOutput(`{"city": "Beijing"}`),
+			toolCallOutput(`{"city": "Beijing", "temperature": -200}`),
+			toolCallOutput(`{"city": "Beijing", "temperature": 25}`),
+		}}
+		w, err := GenerateStructured[weather](ctx, m, input)
+		assert.NoError(t, err)
+		assert.Equal(t, weather{City: "Beijing", Temperature: 25}, w)
+
+		assert.Len(t, m.tools, 1)
+		assert.Equal(t, "structured_output", m.tools[0].Name)
+		js, err := m.tools[0].ParamsOneOf.ToJSONSchema()
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"city", "temperature"}, js.Required)
+		assert.Equal(t, schema.ToolChoiceForced, *m.options[0].ToolChoice)
+
+		assert.Len(t, m.inputs, 3)
+		assert.Len(t, m.inputs[2], 5)
+		assert.Equal(t, schema.Tool, m.inputs[1][2].Role)
+		assert.Equal(t, "call", m.inputs[1][2].ToolCallID)
+		assert.Contains(t, m.inputs[1][2].Content, `missing required property "temperature"`)
+		assert.Contains(t, m.inputs[2][4].Content, "temperature is too low")
+	})
+
+	t.Run("native", func(t *testing.T) {
+		m := &scriptedModel{outputs: []*schema.Message{
+			schema.AssistantMessage(`{"city": "Beijing", "temperature": 25, "unknown": 1}`, nil),
+			schema.AssistantMessage("```json\n{\"city\": \"Beijing\", \"temperature\": 25}\n```", nil),
+		}}
+		w, err := GenerateStructured[weather](ctx, m, input,
+			WithNativeStructuredOutput(withNativeFormat),
+			WithStructuredOutputName("weather", "the weather"),
+			WithStructuredModelOptions(WithTemperature(0)))
+		assert.NoError(t, err)
+		assert.Equal(t, weather{City: "Beijing", Temperature: 25}, w)
+
+		assert.Nil(t, m.tools)
+		assert.Nil(t, m.options[0].ToolChoice)
+		assert.Equal(t, float32(0), *m.options[0].Temperature)
+		assert.Equal(t, "weather", m.native[0].name)
+		assert.NotNil(t, m.native[0].js)
+		assert.Equal(t, schema.User, m.inputs[1][2].Role)
+		assert.Contains(t, m.inputs[1][2].Content, `additional property "unknown" is not allowed`)
+	})
+
+	t.Run("exhausted", func(t *testing.T) {
+		m := &scriptedModel{outputs: []*schema.Message{
+			schema.AssistantMessage("no tool call", nil),
+			toolCallOutput(`{"city": "Beijing", "temperature": 25}`),
+		}}
+		_, err := GenerateStructured[weather](ctx, m, input,
+			WithStructuredMaxRetries(1),
+			WithStructuredValidator(func(v any) error {
+				return errors.New("always invalid")
+			}))
+		var soErr *StructuredOutputError
+		assert.True(t, errors.As(err, &soErr))
+		assert.EqualError(t, soErr.Err, "always invalid")
+		assert.Len(t, m.inputs, 2)
+		assert.Equal(t, schema.User, m.inputs[1][2].Role)
+	})
+}
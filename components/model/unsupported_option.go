@@ -0,0 +1,81 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/cloudwego/eino/components"
+)
+
+// ErrUnsupportedOption is matched by the errors of the common options unsupported by the model, see UnsupportedOptionError.
+var ErrUnsupportedOption = errors.New("unsupported model option")
+
+// UnsupportedOptionError is returned by implementations when the call sets common options the model doesn't support,
+// instead of ignoring them silently. errors.Is(err, ErrUnsupportedOption) reports true for it.
+type UnsupportedOptionError struct {
+	// Model is the type or the name of the model.
+	Model string
+	// Options are the field names in Options, e.g. "Seed" and "LogitBias".
+	Options []string
+}
+
+func (e *UnsupportedOptionError) Error() string {
+	return fmt.Sprintf("model[%s] doesn't support options: %s", e.Model, strings.Join(e.Options, ", "))
+}
+
+func (e *UnsupportedOptionError) Unwrap() error {
+	return ErrUnsupportedOption
+}
+
+func (e *UnsupportedOptionError) ErrorCategory() components.ErrorCategory {
+	return components.ErrorCategoryInvalidInput
+}
+
+// CheckSupportedOptions returns an *UnsupportedOptionError if opts sets any common option not in supported,
+// which are the field names in Options. It's meant for implementations to validate the common options of a call.
+// e.g.
+//
+//	options := model.GetCommonOptions(&model.Options{Model: &cm.model}, opts...)
+//	if err := model.CheckSupportedOptions("MyModel", options, "Model", "Temperature", "MaxTokens", "Tools", "ToolChoice"); err != nil {
+//		return nil, err
+//	}
+func CheckSupportedOptions(modelName string, opts *Options, supported ...string) error {
+	if opts == nil {
+		return nil
+	}
+	supportedSet := make(map[string]bool, len(supported))
+	for _, name := range supported {
+		supportedSet[name] = true
+	}
+
+	var unsupported []string
+	v := reflect.ValueOf(opts).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := v.Type().Field(i).Name
+		if !v.Field(i).IsNil() && !supportedSet[name] {
+			unsupported = append(unsupported, name)
+		}
+	}
+	if len(unsupported) == 0 {
+		return nil
+	}
+	return &UnsupportedOptionError{Model: modelName, Options: unsupported}
+}
@@ -30,6 +30,8 @@ type DefaultChatTemplate struct {
 	templates []schema.MessagesTemplate
 	// formatType is the format type for the chat template.
 	formatType schema.FormatType
+	// name is the name of the chat template in the errors.
+	name string
 }
 
 // FromMessages creates a new DefaultChatTemplate from the given templates and format type.
@@ -46,9 +48,51 @@ func FromMessages(formatType schema.FormatType, templates ...schema.MessagesTemp
 	}
 }
 
+// WithName returns a copy of the chat template with the name, which identifies the template in the errors of the strict variables check.
+func (t *DefaultChatTemplate) WithName(name string) *DefaultChatTemplate {
+	copied := *t
+	copied.name = name
+	return &copied
+}
+
+type chatTemplateOptions struct {
+	jinja2Loader    schema.Jinja2Loader
+	strictVariables bool
+}
+
+// WithJinja2Loader enables the include, extends, import and from statements of the Jinja2 templates,
+// which load the referenced templates by the loader, see schema.WithJinja2Loader.
+// e.g.
+//
+//	loader := schema.MapJinja2Loader{"base.j2": baseTemplate}
+//	msgs, err := template.Format(ctx, vs, prompt.WithJinja2Loader(loader))
+//	// in chain, or graph
+//	out, err := runnable.Invoke(ctx, vs, compose.WithChatTemplateOption(prompt.WithJinja2Loader(loader)))
+func WithJinja2Loader(loader schema.Jinja2Loader) Option {
+	return WrapImplSpecificOptFn(func(o *chatTemplateOptions) {
+		o.jinja2Loader = loader
+	})
+}
+
+// WithStrictVariables checks the variables before formatting, and fails with a *VariablesError reporting all the missing variables,
+// and the extra ones if all the templates can be inspected, see DefaultChatTemplate.Variables.
+// Without it, a missing variable fails at the first reference, or renders nothing in Jinja2.
+// e.g.
+//
+//	msgs, err := template.Format(ctx, vs, prompt.WithStrictVariables())
+//	var vErr *prompt.VariablesError
+//	if errors.As(err, &vErr) {
+//		// vErr.Missing, vErr.Extra
+//	}
+func WithStrictVariables() Option {
+	return WrapImplSpecificOptFn(func(o *chatTemplateOptions) {
+		o.strictVariables = true
+	})
+}
+
 // Format formats the chat template with the given context and variables.
 func (t *DefaultChatTemplate) Format(ctx context.Context,
-	vs map[string]any, _ ...Option) (result []*schema.Message, err error) {
+	vs map[string]any, opts ...Option) (result []*schema.Message, err error) {
 	ctx = callbacks.EnsureRunInfo(ctx, t.GetType(), components.ComponentOfPrompt)
 	ctx = callbacks.OnStart(ctx, &CallbackInput{
 		Variables: vs,
@@ -60,9 +104,21 @@ func (t *DefaultChatTemplate) Format(ctx context.Context,
 		}
 	}()
 
+	options := GetImplSpecificOptions(&chatTemplateOptions{}, opts...)
+	if options.strictVariables {
+		if err = t.checkVariables(vs); err != nil {
+			return nil, err
+		}
+	}
+
+	formatCtx := ctx
+	if options.jinja2Loader != nil {
+		formatCtx = schema.WithJinja2Loader(ctx, options.jinja2Loader)
+	}
+
 	result = make([]*schema.Message, 0, len(t.templates))
 	for _, template := range t.templates {
-		msgs, err := template.Format(ctx, vs, t.formatType)
+		msgs, err := template.Format(formatCtx, vs, t.formatType)
 		if err != nil {
 			return nil, err
 		}
@@ -199,3 +199,25 @@ func TestMultiContentFormat(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, expected, result)
 }
+
+func TestJinja2LoaderOption(t *testing.T) {
+	ctx := context.Background()
+	loader := schema.MapJinja2Loader{
+		"system.j2": "You are {{ role }}.{% block extra %}{% endblock %}",
+	}
+	tpl := FromMessages(schema.Jinja2,
+		schema.SystemMessage(`{% extends "system.j2" %}{% block extra %} Be brief.{% endblock %}`),
+		schema.UserMessage("{{ question }}"),
+	)
+	vs := map[string]any{"role": "an assistant", "question": "hi"}
+
+	msgs, err := tpl.Format(ctx, vs, WithJinja2Loader(loader))
+	assert.NoError(t, err)
+	assert.Equal(t, []*schema.Message{
+		schema.SystemMessage("You are an assistant. Be brief."),
+		schema.UserMessage("hi"),
+	}, msgs)
+
+	_, err = tpl.Format(ctx, vs)
+	assert.ErrorContains(t, err, "keyword[extends] has been disabled")
+}
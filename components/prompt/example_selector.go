@@ -0,0 +1,297 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Example is a few-shot example, whose entries are the variables to render its messages, e.g. {"input": "hi", "output": "hello"}.
+type Example map[string]any
+
+// ExampleSelector selects the few-shot examples for the variables of a format.
+type ExampleSelector interface {
+	Select(ctx context.Context, vs map[string]any) ([]Example, error)
+}
+
+// ExampleSelectorFunc is the function adapter of ExampleSelector.
+type ExampleSelectorFunc func(ctx context.Context, vs map[string]any) ([]Example, error)
+
+// Select calls f(ctx, vs).
+func (f ExampleSelectorFunc) Select(ctx context.Context, vs map[string]any) ([]Example, error) {
+	return f(ctx, vs)
+}
+
+// FewShot creates a messages template rendering the examples selected at format time,
+// each example is rendered by the templates with its entries as the variables, in the format type of the chat template.
+// e.g.
+//
+//	template := prompt.FromMessages(schema.FString,
+//		schema.SystemMessage("Translate the text into French."),
+//		prompt.FewShot(selector, schema.UserMessage("{input}"), schema.AssistantMessage("{output}", nil)),
+//		schema.UserMessage("{input}"),
+//	)
+func FewShot(selector ExampleSelector, templates ...schema.MessagesTemplate) schema.MessagesTemplate {
+	return &fewShotTemplate{selector: selector, templates: templates}
+}
+
+type fewShotTemplate struct {
+	selector  ExampleSelector
+	templates []schema.MessagesTemplate
+}
+
+func (f *fewShotTemplate) Format(ctx context.Context, vs map[string]any, formatType schema.FormatType) ([]*schema.Message, error) {
+	examples, err := f.selector.Select(ctx, vs)
+	if err != nil {
+		return nil, fmt.Errorf("select examples fail: %w", err)
+	}
+	var ret []*schema.Message
+	for i, example := range examples {
+		for _, tpl := range f.templates {
+			msgs, err := tpl.Format(ctx, example, formatType)
+			if err != nil {
+				return nil, fmt.Errorf("format example[%d] fail: %w", i, err)
+			}
+			ret = append(ret, msgs...)
+		}
+	}
+	return ret, nil
+}
+
+// NewRoundRobinExampleSelector creates a selector taking the next k examples on each selection, wrapping around at the end,
+// which spreads the examples evenly across the calls.
+func NewRoundRobinExampleSelector(examples []Example, k int) (ExampleSelector, error) {
+	if len(examples) == 0 {
+		return nil, errors.New("examples are empty")
+	}
+	if k <= 0 || k > len(examples) {
+		return nil, fmt.Errorf("invalid number of examples to select: %d", k)
+	}
+	var next uint64
+	return ExampleSelectorFunc(func(ctx context.Context, vs map[string]any) ([]Example, error) {
+		start := int((atomic.AddUint64(&next, uint64(k)) - uint64(k)) % uint64(len(examples)))
+		ret := make([]Example, k)
+		for i := range ret {
+			ret[i] = examples[(start+i)%len(examples)]
+		}
+		return ret, nil
+	}), nil
+}
+
+// LengthExampleSelectorConfig is the config of the length based example selector.
+type LengthExampleSelectorConfig struct {
+	// Examples are the candidates in the order of preference. Required.
+	Examples []Example
+	// MaxLength is the budget of the total length of the selected examples and the input variables. Required.
+	MaxLength int
+	// InputKeys are the variables of the format counted in the budget, e.g. a long input leaves less room for the examples. Optional.
+	InputKeys []string
+	// Length measures a text, the number of characters by default.
+	Length func(text string) int
+}
+
+// NewLengthExampleSelector creates a selector taking the examples in order until the next one exceeds the remaining budget,
+// the length of an example is the sum of the lengths of its string entries.
+func NewLengthExampleSelector(config *LengthExampleSelectorConfig) (ExampleSelector, error) {
+	if len(config.Examples) == 0 {
+		return nil, errors.New("examples are empty")
+	}
+	if config.MaxLength <= 0 {
+		return nil, fmt.Errorf("invalid max length: %d", config.MaxLength)
+	}
+	length := config.Length
+	if length == nil {
+		length = utf8.RuneCountInString
+	}
+	lengths := make([]int, len(config.Examples))
+	for i, example := range config.Examples {
+		for _, v := range example {
+			if s, ok := v.(string); ok {
+				lengths[i] += length(s)
+			}
+		}
+	}
+
+	return ExampleSelectorFunc(func(ctx context.Context, vs map[string]any) ([]Example, error) {
+		remaining := config.MaxLength - length(exampleText(vs, config.InputKeys))
+		var ret []Example
+		for i, example := range config.Examples {
+			if lengths[i] > remaining {
+				break
+			}
+			remaining -= lengths[i]
+			ret = append(ret, example)
+		}
+		return ret, nil
+	}), nil
+}
+
+// SemanticExampleSelectorConfig is the config of the semantic similarity example selector.
+type SemanticExampleSelectorConfig struct {
+	// Select from the examples in memory, or by a retriever, exactly one way is required.
+	// 1. Examples are embedded by Embedding on the first selection.
+	Examples  []Example
+	Embedding embedding.Embedder
+	// 2. Retriever retrieves the examples indexed in a vector store, the metadata of each document is an example.
+	Retriever retriever.Retriever
+
+	// InputKeys are the entries of the examples, and the variables of the format, compared by similarity. Required.
+	InputKeys []string
+	// K is the number of the examples to select. Required.
+	K int
+}
+
+// NewSemanticExampleSelector creates a selector taking the k examples most similar to the input variables, the most similar first.
+// The text to compare is the values of the InputKeys joined by newlines.
+func NewSemanticExampleSelector(config *SemanticExampleSelectorConfig) (ExampleSelector, error) {
+	if len(config.InputKeys) == 0 {
+		return nil, errors.New("input keys are empty")
+	}
+	if config.K <= 0 {
+		return nil, fmt.Errorf("invalid number of examples to select: %d", config.K)
+	}
+	if config.Retriever != nil {
+		if config.Embedding != nil || len(config.Examples) > 0 {
+			return nil, errors.New("retriever can't be used with examples and embedding")
+		}
+		return ExampleSelectorFunc(func(ctx context.Context, vs map[string]any) ([]Example, error) {
+			docs, err := config.Retriever.Retrieve(ctx, exampleText(vs, config.InputKeys), retriever.WithTopK(config.K))
+			if err != nil {
+				return nil, err
+			}
+			if len(docs) > config.K {
+				docs = docs[:config.K]
+			}
+			ret := make([]Example, len(docs))
+			for i, doc := range docs {
+				ret[i] = doc.MetaData
+			}
+			return ret, nil
+		}), nil
+	}
+
+	if config.Embedding == nil || len(config.Examples) == 0 {
+		return nil, errors.New("either retriever, or examples and embedding are required")
+	}
+	return &semanticExampleSelector{
+		examples:  config.Examples,
+		embedding: config.Embedding,
+		inputKeys: config.InputKeys,
+		k:         config.K,
+	}, nil
+}
+
+type semanticExampleSelector struct {
+	examples  []Example
+	embedding embedding.Embedder
+	inputKeys []string
+	k         int
+
+	mu      sync.Mutex
+	vectors [][]float64
+}
+
+func (s *semanticExampleSelector) Select(ctx context.Context, vs map[string]any) ([]Example, error) {
+	vectors, err := s.exampleVectors(ctx)
+	if err != nil {
+		return nil, err
+	}
+	queryVectors, err := s.embedding.EmbedStrings(ctx, []string{exampleText(vs, s.inputKeys)})
+	if err != nil {
+		return nil, fmt.Errorf("embed input fail: %w", err)
+	}
+	if len(queryVectors) != 1 {
+		return nil, fmt.Errorf("unexpected number of vectors, expected: 1, actual: %d", len(queryVectors))
+	}
+
+	indexes := make([]int, len(s.examples))
+	similarities := make([]float64, len(s.examples))
+	for i := range indexes {
+		indexes[i] = i
+		similarities[i] = cosineSimilarity(queryVectors[0], vectors[i])
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return similarities[indexes[i]] > similarities[indexes[j]]
+	})
+
+	k := s.k
+	if k > len(indexes) {
+		k = len(indexes)
+	}
+	ret := make([]Example, k)
+	for i := range ret {
+		ret[i] = s.examples[indexes[i]]
+	}
+	return ret, nil
+}
+
+// exampleVectors embeds the examples once, a failed embedding is retried on the next selection.
+func (s *semanticExampleSelector) exampleVectors(ctx context.Context) ([][]float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.vectors != nil {
+		return s.vectors, nil
+	}
+	texts := make([]string, len(s.examples))
+	for i, example := range s.examples {
+		texts[i] = exampleText(example, s.inputKeys)
+	}
+	vectors, err := s.embedding.EmbedStrings(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed examples fail: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("unexpected number of vectors, expected: %d, actual: %d", len(texts), len(vectors))
+	}
+	s.vectors = vectors
+	return vectors, nil
+}
+
+func exampleText(vs map[string]any, keys []string) string {
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if v, ok := vs[key]; ok {
+			parts = append(parts, fmt.Sprint(v))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
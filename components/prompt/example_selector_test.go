@@ -0,0 +1,191 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+type keywordEmbedder struct {
+	calls int
+}
+
+// EmbedStrings maps a text to the counts of the words "cat", "dog" and "bird".
+func (k *keywordEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	k.calls++
+	ret := make([][]float64, len(texts))
+	for i, text := range texts {
+		ret[i] = []float64{
+			float64(strings.Count(text, "cat")),
+			float64(strings.Count(text, "dog")),
+			float64(strings.Count(text, "bird")),
+		}
+	}
+	return ret, nil
+}
+
+type exampleRetriever struct {
+	query string
+	topK  int
+}
+
+func (e *exampleRetriever) Retrieve(_ context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	e.query = query
+	e.topK = *retriever.GetCommonOptions(nil, opts...).TopK
+	return []*schema.Document{
+		{ID: "1", MetaData: map[string]any{"input": "a", "output": "A"}},
+		{ID: "2", MetaData: map[string]any{"input": "b", "output": "B"}},
+	}, nil
+}
+
+func TestFewShot(t *testing.T) {
+	ctx := context.Background()
+	selector, err := NewRoundRobinExampleSelector([]Example{
+		{"input": "a", "output": "A"},
+		{"input": "b", "output": "B"},
+		{"input": "c", "output": "C"},
+	}, 2)
+	assert.NoError(t, err)
+
+	template := FromMessages(schema.FString,
+		schema.SystemMessage("uppercase the text"),
+		FewShot(selector, schema.UserMessage("{input}"), schema.AssistantMessage("{output}", nil)),
+		schema.UserMessage("{input}"),
+	)
+
+	msgs, err := template.Format(ctx, map[string]any{"input": "d"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*schema.Message{
+		schema.SystemMessage("uppercase the text"),
+		schema.UserMessage("a"),
+		schema.AssistantMessage("A", nil),
+		schema.UserMessage("b"),
+		schema.AssistantMessage("B", nil),
+		schema.UserMessage("d"),
+	}, msgs)
+
+	msgs, err = template.Format(ctx, map[string]any{"input": "d"})
+	assert.NoError(t, err)
+	assert.Equal(t, "c", msgs[1].Content)
+	assert.Equal(t, "a", msgs[3].Content)
+
+	t.Run("missing example variable", func(t *testing.T) {
+		tpl := FromMessages(schema.FString, FewShot(selector, schema.UserMessage("{question}")))
+		_, err := tpl.Format(ctx, map[string]any{})
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid round robin config", func(t *testing.T) {
+		_, err := NewRoundRobinExampleSelector(nil, 1)
+		assert.Error(t, err)
+		_, err = NewRoundRobinExampleSelector([]Example{{}}, 2)
+		assert.Error(t, err)
+	})
+}
+
+func TestLengthExampleSelector(t *testing.T) {
+	ctx := context.Background()
+	selector, err := NewLengthExampleSelector(&LengthExampleSelectorConfig{
+		Examples: []Example{
+			{"input": "aaaa", "output": "AAAA"},
+			{"input": "bb", "output": "BB"},
+			{"input": "c", "output": "C"},
+		},
+		MaxLength: 14,
+		InputKeys: []string{"input"},
+	})
+	assert.NoError(t, err)
+
+	examples, err := selector.Select(ctx, map[string]any{"input": "xx"})
+	assert.NoError(t, err)
+	assert.Len(t, examples, 2)
+
+	examples, err = selector.Select(ctx, map[string]any{"input": "xxxxxxx"})
+	assert.NoError(t, err)
+	assert.Len(t, examples, 0)
+
+	_, err = NewLengthExampleSelector(&LengthExampleSelectorConfig{Examples: []Example{{}}})
+	assert.Error(t, err)
+}
+
+func TestSemanticExampleSelector(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("embedding", func(t *testing.T) {
+		emb := &keywordEmbedder{}
+		selector, err := NewSemanticExampleSelector(&SemanticExampleSelectorConfig{
+			Examples: []Example{
+				{"input": "the cat sleeps", "output": "1"},
+				{"input": "the dog barks", "output": "2"},
+				{"input": "the bird sings", "output": "3"},
+			},
+			Embedding: emb,
+			InputKeys: []string{"input"},
+			K:         2,
+		})
+		assert.NoError(t, err)
+
+		examples, err := selector.Select(ctx, map[string]any{"input": "a dog and a bird"})
+		assert.NoError(t, err)
+		assert.Len(t, examples, 2)
+		assert.ElementsMatch(t, []string{"2", "3"}, []string{examples[0]["output"].(string), examples[1]["output"].(string)})
+
+		examples, err = selector.Select(ctx, map[string]any{"input": "my cat"})
+		assert.NoError(t, err)
+		assert.Equal(t, "1", examples[0]["output"])
+		// examples are embedded once, plus one embedding per selection
+		assert.Equal(t, 3, emb.calls)
+	})
+
+	t.Run("retriever", func(t *testing.T) {
+		r := &exampleRetriever{}
+		selector, err := NewSemanticExampleSelector(&SemanticExampleSelectorConfig{
+			Retriever: r,
+			InputKeys: []string{"input", "lang"},
+			K:         1,
+		})
+		assert.NoError(t, err)
+
+		examples, err := selector.Select(ctx, map[string]any{"input": "x", "lang": "en"})
+		assert.NoError(t, err)
+		assert.Equal(t, []Example{{"input": "a", "output": "A"}}, examples)
+		assert.Equal(t, "x\nen", r.query)
+		assert.Equal(t, 1, r.topK)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewSemanticExampleSelector(&SemanticExampleSelectorConfig{InputKeys: []string{"input"}, K: 1})
+		assert.Error(t, err)
+		_, err = NewSemanticExampleSelector(&SemanticExampleSelectorConfig{
+			Retriever: &exampleRetriever{},
+			Embedding: &keywordEmbedder{},
+			InputKeys: []string{"input"},
+			K:         1,
+		})
+		assert.Error(t, err)
+		_, err = NewSemanticExampleSelector(&SemanticExampleSelectorConfig{Retriever: &exampleRetriever{}, K: 1})
+		assert.Error(t, err)
+	})
+}
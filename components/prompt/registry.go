@@ -0,0 +1,326 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// PromptVersion is a version of a prompt managed by the Registry.
+// e.g. the JSON file of the file source:
+//
+//	{
+//	  "name": "qa",
+//	  "version": "v2",
+//	  "labels": ["canary"],
+//	  "format": "fstring",
+//	  "messages": [
+//	    {"role": "system", "content": "answer the question with the context: {context}"},
+//	    {"placeholder": "history", "optional": true},
+//	    {"role": "user", "content": "{question}"}
+//	  ]
+//	}
+type PromptVersion struct {
+	// Name is the name of the prompt. Required.
+	Name string `json:"name"`
+	// Version identifies the version in the prompt, unique in the prompt. Required.
+	Version string `json:"version"`
+	// Labels are the rollout labels pointing to the version, e.g. "prod" and "canary", a label points to at most one version of a prompt.
+	Labels []string `json:"labels,omitempty"`
+	// Format is the format type of the messages, one of "fstring" (default), "gotemplate" and "jinja2".
+	Format string `json:"format,omitempty"`
+	// Messages are the templates of the prompt. Required.
+	Messages []*PromptMessage `json:"messages"`
+}
+
+// PromptMessage is a message template of the PromptVersion, either a message or a placeholder of messages.
+type PromptMessage struct {
+	Role    schema.RoleType `json:"role,omitempty"`
+	Content string          `json:"content,omitempty"`
+
+	// Placeholder is the variable of the messages to insert, see schema.MessagesPlaceholder.
+	Placeholder string `json:"placeholder,omitempty"`
+	Optional    bool   `json:"optional,omitempty"`
+}
+
+// RegistrySource provides all the prompt versions of the Registry, e.g. the files of a directory, or a config service.
+type RegistrySource interface {
+	Load(ctx context.Context) ([]*PromptVersion, error)
+}
+
+// RegistrySourceFunc is the function adapter of RegistrySource.
+type RegistrySourceFunc func(ctx context.Context) ([]*PromptVersion, error)
+
+// Load calls f(ctx).
+func (f RegistrySourceFunc) Load(ctx context.Context) ([]*PromptVersion, error) {
+	return f(ctx)
+}
+
+// NewFileRegistrySource creates a source loading the prompt versions from the *.json files of the dir, one version per file,
+// in the order of the file names.
+func NewFileRegistrySource(dir string) RegistrySource {
+	return RegistrySourceFunc(func(ctx context.Context) ([]*PromptVersion, error) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		var ret []*PromptVersion
+		for _, entry := range entries {
+			if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+				continue
+			}
+			data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+			if err != nil {
+				return nil, err
+			}
+			v := &PromptVersion{}
+			if err = json.Unmarshal(data, v); err != nil {
+				return nil, fmt.Errorf("unmarshal prompt file[%s] fail: %w", entry.Name(), err)
+			}
+			ret = append(ret, v)
+		}
+		return ret, nil
+	})
+}
+
+// RegistryConfig is the config of the Registry.
+type RegistryConfig struct {
+	// Source provides the prompt versions. Required.
+	Source RegistrySource
+	// DefaultLabel is the label resolved by a reference without label, "prod" by default.
+	DefaultLabel string
+	// OnReloadError is called when a reload of Watch fails, the registry keeps serving the prompts loaded before. Optional.
+	OnReloadError func(ctx context.Context, err error)
+}
+
+// Registry manages the versions of the prompts loaded from a source, and resolves the references of the prompts:
+//   - "name@label" resolves the version with the label, or the version named label if no version has the label, e.g. "qa@canary", "qa@v2".
+//   - "name" resolves the default label, or the latest version in the order of the source if no version has the default label.
+//
+// Reload replaces all the prompts at once, the prompts of a failed reload are discarded.
+type Registry struct {
+	source        RegistrySource
+	defaultLabel  string
+	onReloadError func(ctx context.Context, err error)
+
+	reloadMu sync.Mutex
+	snapshot atomic.Value // *registrySnapshot
+}
+
+type registrySnapshot struct {
+	prompts map[string]*registryPrompt
+}
+
+type registryPrompt struct {
+	versions map[string]*registryVersion
+	labels   map[string]*registryVersion
+	latest   *registryVersion
+}
+
+type registryVersion struct {
+	version  *PromptVersion
+	template *DefaultChatTemplate
+}
+
+// NewRegistry creates a registry with the prompts loaded from the source.
+// e.g.
+//
+//	registry, err := prompt.NewRegistry(ctx, &prompt.RegistryConfig{Source: prompt.NewFileRegistrySource("./prompts")})
+//	go registry.Watch(ctx, time.Minute)
+//	// resolves the prod version on each format, following the reloads
+//	chain.AppendChatTemplate(registry.ChatTemplate("qa@prod"))
+func NewRegistry(ctx context.Context, config *RegistryConfig) (*Registry, error) {
+	if config.Source == nil {
+		return nil, errors.New("registry source is required")
+	}
+	r := &Registry{
+		source:        config.Source,
+		defaultLabel:  config.DefaultLabel,
+		onReloadError: config.OnReloadError,
+	}
+	if r.defaultLabel == "" {
+		r.defaultLabel = "prod"
+	}
+	if err := r.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload loads the prompts from the source, and swaps them in if all of them are valid.
+func (r *Registry) Reload(ctx context.Context) error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	versions, err := r.source.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("load prompts fail: %w", err)
+	}
+	snapshot, err := newRegistrySnapshot(versions)
+	if err != nil {
+		return err
+	}
+	r.snapshot.Store(snapshot)
+	return nil
+}
+
+// Watch reloads the prompts every interval until the ctx is done, the failures are reported to OnReloadError.
+func (r *Registry) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := r.Reload(ctx); err != nil && r.onReloadError != nil {
+				r.onReloadError(ctx, err)
+			}
+		}
+	}
+}
+
+// Resolve returns the prompt version and its chat template of the reference.
+func (r *Registry) Resolve(ref string) (*PromptVersion, *DefaultChatTemplate, error) {
+	name, label := ref, ""
+	if i := strings.LastIndex(ref, "@"); i >= 0 {
+		name, label = ref[:i], ref[i+1:]
+	}
+	p, ok := r.snapshot.Load().(*registrySnapshot).prompts[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("prompt[%s] not found", name)
+	}
+
+	var v *registryVersion
+	if label == "" {
+		if v, ok = p.labels[r.defaultLabel]; !ok {
+			v = p.latest
+		}
+	} else if v, ok = p.labels[label]; !ok {
+		if v, ok = p.versions[label]; !ok {
+			return nil, nil, fmt.Errorf("label or version[%s] of prompt[%s] not found", label, name)
+		}
+	}
+	return v.version, v.template, nil
+}
+
+// ChatTemplate returns a chat template resolving the reference on each format, so the reloads take effect without rebuilding the chains or graphs.
+func (r *Registry) ChatTemplate(ref string) ChatTemplate {
+	return &registryTemplate{registry: r, ref: ref}
+}
+
+type registryTemplate struct {
+	registry *Registry
+	ref      string
+}
+
+func (t *registryTemplate) Format(ctx context.Context, vs map[string]any, opts ...Option) ([]*schema.Message, error) {
+	_, template, err := t.registry.Resolve(t.ref)
+	if err != nil {
+		return nil, err
+	}
+	return template.Format(ctx, vs, opts...)
+}
+
+func (t *registryTemplate) GetType() string {
+	return "Registry"
+}
+
+// IsCallbacksEnabled returns true, as the callbacks are run by the resolved template.
+func (t *registryTemplate) IsCallbacksEnabled() bool {
+	return true
+}
+
+func newRegistrySnapshot(versions []*PromptVersion) (*registrySnapshot, error) {
+	s := &registrySnapshot{prompts: make(map[string]*registryPrompt)}
+	for _, v := range versions {
+		if v.Name == "" || v.Version == "" {
+			return nil, fmt.Errorf("name and version of prompt are required, name: %q, version: %q", v.Name, v.Version)
+		}
+		if strings.Contains(v.Name, "@") {
+			return nil, fmt.Errorf("name of prompt[%s] contains '@'", v.Name)
+		}
+		template, err := newRegistryTemplate(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid prompt[%s@%s]: %w", v.Name, v.Version, err)
+		}
+
+		p, ok := s.prompts[v.Name]
+		if !ok {
+			p = &registryPrompt{
+				versions: make(map[string]*registryVersion),
+				labels:   make(map[string]*registryVersion),
+			}
+			s.prompts[v.Name] = p
+		}
+		if _, ok = p.versions[v.Version]; ok {
+			return nil, fmt.Errorf("duplicate version[%s] of prompt[%s]", v.Version, v.Name)
+		}
+		rv := &registryVersion{version: v, template: template}
+		p.versions[v.Version] = rv
+		p.latest = rv
+		for _, label := range v.Labels {
+			if prev, ok := p.labels[label]; ok {
+				return nil, fmt.Errorf("label[%s] of prompt[%s] points to both version[%s] and version[%s]",
+					label, v.Name, prev.version.Version, v.Version)
+			}
+			p.labels[label] = rv
+		}
+	}
+	return s, nil
+}
+
+func newRegistryTemplate(v *PromptVersion) (*DefaultChatTemplate, error) {
+	var formatType schema.FormatType
+	switch v.Format {
+	case "", "fstring":
+		formatType = schema.FString
+	case "gotemplate":
+		formatType = schema.GoTemplate
+	case "jinja2":
+		formatType = schema.Jinja2
+	default:
+		return nil, fmt.Errorf("unknown format: %s", v.Format)
+	}
+	if len(v.Messages) == 0 {
+		return nil, errors.New("messages are empty")
+	}
+
+	templates := make([]schema.MessagesTemplate, 0, len(v.Messages))
+	for i, m := range v.Messages {
+		switch {
+		case m.Placeholder != "":
+			templates = append(templates, schema.MessagesPlaceholder(m.Placeholder, m.Optional))
+		case m.Role != "":
+			templates = append(templates, &schema.Message{Role: m.Role, Content: m.Content})
+		default:
+			return nil, fmt.Errorf("either role or placeholder of message[%d] is required", i)
+		}
+	}
+	return FromMessages(formatType, templates...).WithName(v.Name + "@" + v.Version), nil
+}
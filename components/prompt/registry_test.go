@@ -0,0 +1,138 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestRegistry(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writePrompt := func(file, content string) {
+		assert.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(content), 0o644))
+	}
+	writePrompt("qa_v1.json", `{"name": "qa", "version": "v1", "labels": ["prod"],
+		"messages": [{"role": "system", "content": "v1"}, {"placeholder": "history", "optional": true}, {"role": "user", "content": "{question}"}]}`)
+	writePrompt("qa_v2.json", `{"name": "qa", "version": "v2", "labels": ["canary"], "format": "gotemplate",
+		"messages": [{"role": "system", "content": "v2"}, {"role": "user", "content": "{{.question}}"}]}`)
+	writePrompt("README.md", "not a prompt")
+
+	registry, err := NewRegistry(ctx, &RegistryConfig{Source: NewFileRegistrySource(dir)})
+	assert.NoError(t, err)
+
+	for ref, version := range map[string]string{"qa": "v1", "qa@prod": "v1", "qa@canary": "v2", "qa@v2": "v2"} {
+		v, _, err := registry.Resolve(ref)
+		assert.NoError(t, err)
+		assert.Equal(t, version, v.Version, ref)
+	}
+	_, _, err = registry.Resolve("qa@beta")
+	assert.Error(t, err)
+	_, _, err = registry.Resolve("chat")
+	assert.Error(t, err)
+
+	prod := registry.ChatTemplate("qa@prod")
+	msgs, err := prod.Format(ctx, map[string]any{"question": "why"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*schema.Message{schema.SystemMessage("v1"), schema.UserMessage("why")}, msgs)
+	msgs, err = registry.ChatTemplate("qa@canary").Format(ctx, map[string]any{"question": "why"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*schema.Message{schema.SystemMessage("v2"), schema.UserMessage("why")}, msgs)
+
+	t.Run("reload", func(t *testing.T) {
+		// promote v2 to prod
+		writePrompt("qa_v1.json", `{"name": "qa", "version": "v1", "messages": [{"role": "system", "content": "v1"}]}`)
+		writePrompt("qa_v2.json", `{"name": "qa", "version": "v2", "labels": ["prod"], "format": "gotemplate",
+			"messages": [{"role": "system", "content": "v2"}, {"role": "user", "content": "{{.question}}"}]}`)
+		assert.NoError(t, registry.Reload(ctx))
+		msgs, err := prod.Format(ctx, map[string]any{"question": "why"})
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", msgs[0].Content)
+
+		// the invalid prompts are discarded
+		writePrompt("qa_v3.json", `{"name": "qa", "version": "v3", "labels": ["prod"], "messages": [{"role": "system", "content": "v3"}]}`)
+		assert.Error(t, registry.Reload(ctx))
+		v, _, err := registry.Resolve("qa")
+		assert.NoError(t, err)
+		assert.Equal(t, "v2", v.Version)
+	})
+
+	t.Run("watch", func(t *testing.T) {
+		loads := 0
+		reloadErr := make(chan error, 1)
+		registry, err := NewRegistry(ctx, &RegistryConfig{
+			Source: RegistrySourceFunc(func(ctx context.Context) ([]*PromptVersion, error) {
+				loads++
+				if loads > 1 {
+					return nil, errors.New("unavailable")
+				}
+				return []*PromptVersion{{Name: "chat", Version: "v1", Messages: []*PromptMessage{{Role: schema.System, Content: "hi"}}}}, nil
+			}),
+			OnReloadError: func(ctx context.Context, err error) {
+				select {
+				case reloadErr <- err:
+				default:
+				}
+			},
+		})
+		assert.NoError(t, err)
+
+		watchCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			registry.Watch(watchCtx, time.Millisecond)
+			close(done)
+		}()
+		assert.ErrorContains(t, <-reloadErr, "unavailable")
+		cancel()
+		<-done
+
+		// no default label, resolves the latest version
+		v, _, err := registry.Resolve("chat")
+		assert.NoError(t, err)
+		assert.Equal(t, "v1", v.Version)
+	})
+
+	t.Run("invalid prompts", func(t *testing.T) {
+		for _, versions := range [][]*PromptVersion{
+			{{Name: "a", Messages: []*PromptMessage{{Role: schema.User}}}},
+			{{Name: "a@b", Version: "v1", Messages: []*PromptMessage{{Role: schema.User}}}},
+			{{Name: "a", Version: "v1"}},
+			{{Name: "a", Version: "v1", Messages: []*PromptMessage{{Content: "hi"}}}},
+			{{Name: "a", Version: "v1", Format: "mustache", Messages: []*PromptMessage{{Role: schema.User}}}},
+			{
+				{Name: "a", Version: "v1", Messages: []*PromptMessage{{Role: schema.User}}},
+				{Name: "a", Version: "v1", Messages: []*PromptMessage{{Role: schema.User}}},
+			},
+		} {
+			versions := versions
+			_, err := NewRegistry(ctx, &RegistryConfig{Source: RegistrySourceFunc(func(ctx context.Context) ([]*PromptVersion, error) {
+				return versions, nil
+			})})
+			assert.Error(t, err)
+		}
+	})
+}
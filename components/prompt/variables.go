@@ -0,0 +1,155 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Variable is a reference to a variable in the chat template.
+type Variable struct {
+	schema.TemplateVariable
+	// TemplateIndex is the index of the messages template referencing the variable.
+	TemplateIndex int
+}
+
+// String returns the variable with its location, e.g. "question (template[2].content at 0)".
+func (v Variable) String() string {
+	return fmt.Sprintf("%s (template[%d].%s at %d)", v.Name, v.TemplateIndex, v.Field, v.Position)
+}
+
+// VariablesError is the error of the strict variables check, reporting all the missing and extra variables at once.
+type VariablesError struct {
+	// Template is the name of the chat template, see DefaultChatTemplate.WithName.
+	Template string
+	// Missing are the references of the required variables not provided.
+	Missing []Variable
+	// Extra are the provided variables not referenced by the chat template, sorted by name.
+	Extra []string
+}
+
+func (e *VariablesError) Error() string {
+	sb := &strings.Builder{}
+	if e.Template != "" {
+		sb.WriteString(fmt.Sprintf("template[%s] ", e.Template))
+	}
+	sb.WriteString("variables check fail")
+	if len(e.Missing) > 0 {
+		missing := make([]string, len(e.Missing))
+		for i, v := range e.Missing {
+			missing[i] = v.String()
+		}
+		sb.WriteString(", missing: ")
+		sb.WriteString(strings.Join(missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		sb.WriteString(", extra: ")
+		sb.WriteString(strings.Join(e.Extra, ", "))
+	}
+	return sb.String()
+}
+
+func (e *VariablesError) ErrorCategory() components.ErrorCategory {
+	return components.ErrorCategoryInvalidInput
+}
+
+// Variables returns the references of the variables in the templates, to check the variables before runtime.
+// The templates not implementing schema.VariablesTemplate, e.g. FewShot, are not inspected.
+// e.g.
+//
+//	vars, err := template.Variables()
+//	for _, v := range vars {
+//		fmt.Println(v) // <= e.g. question (template[2].content at 0)
+//	}
+func (t *DefaultChatTemplate) Variables() ([]Variable, error) {
+	vars, _, err := t.variables()
+	return vars, err
+}
+
+// RequiredVariables returns the sorted names of the variables the format requires, i.e. the ones not only referenced by optional placeholders.
+func (t *DefaultChatTemplate) RequiredVariables() ([]string, error) {
+	vars, _, err := t.variables()
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool)
+	for _, v := range vars {
+		if !v.Optional {
+			set[v.Name] = true
+		}
+	}
+	ret := make([]string, 0, len(set))
+	for name := range set {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret, nil
+}
+
+// variables returns the references of the variables, and whether all the templates are inspected.
+func (t *DefaultChatTemplate) variables() ([]Variable, bool, error) {
+	var ret []Variable
+	complete := true
+	for i, tpl := range t.templates {
+		vt, ok := tpl.(schema.VariablesTemplate)
+		if !ok {
+			complete = false
+			continue
+		}
+		vars, err := vt.Variables(t.formatType)
+		if err != nil {
+			return nil, false, fmt.Errorf("inspect variables of template[%d] fail: %w", i, err)
+		}
+		for _, v := range vars {
+			ret = append(ret, Variable{TemplateVariable: v, TemplateIndex: i})
+		}
+	}
+	return ret, complete, nil
+}
+
+// checkVariables reports the missing variables, and the extra ones if all the templates are inspected.
+func (t *DefaultChatTemplate) checkVariables(vs map[string]any) error {
+	vars, complete, err := t.variables()
+	if err != nil {
+		return err
+	}
+	vErr := &VariablesError{Template: t.name}
+	referenced := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		referenced[v.Name] = true
+		if _, ok := vs[v.Name]; !ok && !v.Optional {
+			vErr.Missing = append(vErr.Missing, v)
+		}
+	}
+	if complete {
+		for k := range vs {
+			if !referenced[k] {
+				vErr.Extra = append(vErr.Extra, k)
+			}
+		}
+		sort.Strings(vErr.Extra)
+	}
+	if len(vErr.Missing) > 0 || len(vErr.Extra) > 0 {
+		return vErr
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package prompt
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestStrictVariables(t *testing.T) {
+	ctx := context.Background()
+	template := FromMessages(schema.FString,
+		schema.SystemMessage("answer with the context: {context}"),
+		schema.MessagesPlaceholder("history", true),
+		schema.UserMessage("{question} in {lang}"),
+	).WithName("qa")
+
+	vars, err := template.Variables()
+	assert.NoError(t, err)
+	assert.Len(t, vars, 4)
+	assert.Equal(t, "question (template[2].content at 0)", vars[2].String())
+
+	required, err := template.RequiredVariables()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"context", "lang", "question"}, required)
+
+	_, err = template.Format(ctx, map[string]any{"question": "why", "topic": "go", "debug": true}, WithStrictVariables())
+	var vErr *VariablesError
+	assert.True(t, errors.As(err, &vErr))
+	assert.Equal(t, "qa", vErr.Template)
+	assert.Len(t, vErr.Missing, 2)
+	assert.Equal(t, []string{"debug", "topic"}, vErr.Extra)
+	assert.Equal(t, "template[qa] variables check fail, missing: context (template[0].content at 25), "+
+		"lang (template[2].content at 14), extra: debug, topic", err.Error())
+
+	msgs, err := template.Format(ctx, map[string]any{"question": "why", "context": "none", "lang": "en"}, WithStrictVariables())
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 2)
+
+	t.Run("not inspected template", func(t *testing.T) {
+		selector, err := NewRoundRobinExampleSelector([]Example{{"input": "a"}}, 1)
+		assert.NoError(t, err)
+		template := FromMessages(schema.FString,
+			FewShot(selector, schema.UserMessage("{input}")),
+			schema.UserMessage("{question}"),
+		)
+		// the extra variables are not reported, as the few shot template isn't inspected
+		_, err = template.Format(ctx, map[string]any{"question": "why", "topic": "go"}, WithStrictVariables())
+		assert.NoError(t, err)
+		_, err = template.Format(ctx, map[string]any{"topic": "go"}, WithStrictVariables())
+		assert.True(t, errors.As(err, &vErr))
+		assert.Empty(t, vErr.Extra)
+	})
+}
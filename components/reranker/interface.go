@@ -0,0 +1,53 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reranker
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+//go:generate mockgen -destination ../../internal/mock/components/reranker/reranker_mock.go --package reranker -source interface.go
+
+// Reranker is the interface for reranker.
+// It reorders the documents by their relevance to the query, most relevant first,
+// and sets the relevance scores by Document.WithScore. The documents may be cut by the TopN and ScoreThreshold options.
+//
+// e.g.
+//
+//		reranker, err := cohere.NewReranker(ctx, &cohere.RerankerConfig{})
+//		if err != nil {...}
+//		docs, err := reranker.Rerank(ctx, "query", docs) // <= using directly
+//		docs, err := reranker.Rerank(ctx, "query", docs, reranker.WithTopN(3)) // <= using options
+//
+//	 	graph := compose.NewGraph[inputType, outputType](compose.RunTypeDAG)
+//		graph.AddRerankerNode("reranker_node_key", reranker) // <= using in graph, the input of the node is *reranker.Input
+type Reranker interface {
+	Rerank(ctx context.Context, query string, docs []*schema.Document, opts ...Option) ([]*schema.Document, error)
+}
+
+// Input is the input of the reranker node in a graph.
+// In a workflow, the fields can be mapped from different predecessors, e.g. Query from START and Docs from a retriever:
+//
+//	wf.AddRerankerNode("reranker", reranker).
+//		AddInput(compose.START, compose.ToField("Query")).
+//		AddInput("retriever", compose.ToField("Docs"))
+type Input struct {
+	Query string
+	Docs  []*schema.Document
+}
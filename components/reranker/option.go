@@ -0,0 +1,109 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reranker
+
+// Options is the options for the reranker.
+type Options struct {
+	// TopN is the number of the most relevant documents to return.
+	TopN *int
+	// ScoreThreshold is the score threshold for the reranker, eg 0.5 means the score of the returned documents must be greater than 0.5.
+	ScoreThreshold *float64
+	// Model is the model used by the reranker, e.g. "rerank-v3.5" or "bge-reranker-v2-m3".
+	Model *string
+}
+
+// WithTopN wraps the top n option.
+func WithTopN(topN int) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.TopN = &topN
+		},
+	}
+}
+
+// WithScoreThreshold wraps the score threshold option.
+func WithScoreThreshold(threshold float64) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.ScoreThreshold = &threshold
+		},
+	}
+}
+
+// WithModel wraps the model option.
+func WithModel(model string) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Model = &model
+		},
+	}
+}
+
+// Option is the call option for Reranker component.
+type Option struct {
+	apply func(opts *Options)
+
+	implSpecificOptFn any
+}
+
+// GetCommonOptions extract reranker Options from Option list, optionally providing a base Options with default values.
+func GetCommonOptions(base *Options, opts ...Option) *Options {
+	if base == nil {
+		base = &Options{}
+	}
+
+	for i := range opts {
+		if opts[i].apply != nil {
+			opts[i].apply(base)
+		}
+	}
+
+	return base
+}
+
+// WrapImplSpecificOptFn is the option to wrap the implementation specific option function.
+func WrapImplSpecificOptFn[T any](optFn func(*T)) Option {
+	return Option{
+		implSpecificOptFn: optFn,
+	}
+}
+
+// GetImplSpecificOptions extract the implementation specific options from Option list, optionally providing a base options with default values.
+// e.g.
+//
+//	myOption := &MyOption{
+//		Field1: "default_value",
+//	}
+//
+//	myOption := reranker.GetImplSpecificOptions(myOption, opts...)
+func GetImplSpecificOptions[T any](base *T, opts ...Option) *T {
+	if base == nil {
+		base = new(T)
+	}
+
+	for i := range opts {
+		opt := opts[i]
+		if opt.implSpecificOptFn != nil {
+			optFn, ok := opt.implSpecificOptFn.(func(*T))
+			if ok {
+				optFn(base)
+			}
+		}
+	}
+
+	return base
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package reranker
+
+import (
+	"testing"
+
+	"github.com/smartystreets/goconvey/convey"
+)
+
+func TestOptions(t *testing.T) {
+	convey.Convey("test options", t, func() {
+		var (
+			topN           = 2
+			scoreThreshold = 0.5
+			model          = "bge-reranker-v2-m3"
+			defaultTopN    = 1
+		)
+
+		opts := GetCommonOptions(
+			&Options{
+				TopN: &defaultTopN,
+			},
+			WithTopN(topN),
+			WithScoreThreshold(scoreThreshold),
+			WithModel(model),
+		)
+
+		convey.So(opts, convey.ShouldResemble, &Options{
+			TopN:           &topN,
+			ScoreThreshold: &scoreThreshold,
+			Model:          &model,
+		})
+	})
+
+	convey.Convey("test impl specific options", t, func() {
+		type implOptions struct {
+			ReturnDocuments bool
+		}
+
+		opts := GetImplSpecificOptions(&implOptions{}, WithTopN(1), WrapImplSpecificOptFn(func(o *implOptions) {
+			o.ReturnDocuments = true
+		}))
+
+		convey.So(opts.ReturnDocuments, convey.ShouldBeTrue)
+	})
+}
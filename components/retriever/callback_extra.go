@@ -28,7 +28,7 @@ type CallbackInput struct {
 
 	// TopK is the top k for the retriever, which means the top number of documents to retrieve.
 	TopK int
-	// Filter is the filter for the retriever.
+	// Filter is the filter for the retriever, e.g. the String of Options.Filter.
 	Filter string
 	// ScoreThreshold is the score threshold for the retriever, eg 0.5 means the score of the document must be greater than 0.5.
 	ScoreThreshold *float64
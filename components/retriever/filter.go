@@ -0,0 +1,341 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retriever
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FilterOp is the operator of a Filter.
+type FilterOp string
+
+const (
+	FilterOpEq    FilterOp = "eq"
+	FilterOpNe    FilterOp = "ne"
+	FilterOpGt    FilterOp = "gt"
+	FilterOpGte   FilterOp = "gte"
+	FilterOpLt    FilterOp = "lt"
+	FilterOpLte   FilterOp = "lte"
+	FilterOpIn    FilterOp = "in"
+	FilterOpNotIn FilterOp = "not_in"
+	FilterOpAnd   FilterOp = "and"
+	FilterOpOr    FilterOp = "or"
+	FilterOpNot   FilterOp = "not"
+)
+
+// Filter is a provider-agnostic filter expression on the metadata fields of the documents,
+// which retriever implementations translate to the filter syntax of their backends, see WithFilter.
+// A comparison (Eq, Ne, Gt, Gte, Lt, Lte) compares Field with Value, a set operation (In, NotIn) checks Field against Values,
+// and a logical operation (And, Or, Not) combines Filters.
+// e.g. tenant_id == "t1" AND created_at > yesterday:
+//
+//	filter := retriever.And(
+//		retriever.Eq("tenant_id", "t1"),
+//		retriever.Gt("created_at", time.Now().Add(-24*time.Hour)),
+//	)
+//
+// Implementations translate the filter recursively by the operator, e.g.
+//
+//	func translate(f *retriever.Filter) (string, error) {
+//		switch f.Op {
+//		case retriever.FilterOpEq:
+//			return fmt.Sprintf("%s == %s", f.Field, quote(f.Value)), nil
+//		case retriever.FilterOpAnd:
+//			...
+//		default:
+//			return "", fmt.Errorf("unsupported filter op: %s", f.Op)
+//		}
+//	}
+type Filter struct {
+	Op      FilterOp  `json:"op"`
+	Field   string    `json:"field,omitempty"`
+	Value   any       `json:"value,omitempty"`
+	Values  []any     `json:"values,omitempty"`
+	Filters []*Filter `json:"filters,omitempty"`
+}
+
+// Eq matches the documents whose field equals the value.
+func Eq(field string, value any) *Filter {
+	return &Filter{Op: FilterOpEq, Field: field, Value: value}
+}
+
+// Ne matches the documents whose field doesn't equal the value, including the ones without the field.
+func Ne(field string, value any) *Filter {
+	return &Filter{Op: FilterOpNe, Field: field, Value: value}
+}
+
+// Gt matches the documents whose field is greater than the value.
+func Gt(field string, value any) *Filter {
+	return &Filter{Op: FilterOpGt, Field: field, Value: value}
+}
+
+// Gte matches the documents whose field is greater than or equal to the value.
+func Gte(field string, value any) *Filter {
+	return &Filter{Op: FilterOpGte, Field: field, Value: value}
+}
+
+// Lt matches the documents whose field is less than the value.
+func Lt(field string, value any) *Filter {
+	return &Filter{Op: FilterOpLt, Field: field, Value: value}
+}
+
+// Lte matches the documents whose field is less than or equal to the value.
+func Lte(field string, value any) *Filter {
+	return &Filter{Op: FilterOpLte, Field: field, Value: value}
+}
+
+// In matches the documents whose field equals any of the values.
+func In(field string, values ...any) *Filter {
+	return &Filter{Op: FilterOpIn, Field: field, Values: values}
+}
+
+// NotIn matches the documents whose field equals none of the values, including the ones without the field.
+func NotIn(field string, values ...any) *Filter {
+	return &Filter{Op: FilterOpNotIn, Field: field, Values: values}
+}
+
+// And matches the documents matched by all the filters.
+func And(filters ...*Filter) *Filter {
+	return &Filter{Op: FilterOpAnd, Filters: filters}
+}
+
+// Or matches the documents matched by any of the filters.
+func Or(filters ...*Filter) *Filter {
+	return &Filter{Op: FilterOpOr, Filters: filters}
+}
+
+// Not matches the documents not matched by the filter.
+func Not(filter *Filter) *Filter {
+	return &Filter{Op: FilterOpNot, Filters: []*Filter{filter}}
+}
+
+// Validate checks that the filter is well-formed, e.g. comparisons have fields and logical operations have operands.
+func (f *Filter) Validate() error {
+	if f == nil {
+		return errors.New("filter is nil")
+	}
+	switch f.Op {
+	case FilterOpEq, FilterOpNe, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		if f.Field == "" {
+			return fmt.Errorf("field of filter op %s is empty", f.Op)
+		}
+		if f.Value == nil {
+			return fmt.Errorf("value of filter op %s on field %s is nil", f.Op, f.Field)
+		}
+	case FilterOpIn, FilterOpNotIn:
+		if f.Field == "" {
+			return fmt.Errorf("field of filter op %s is empty", f.Op)
+		}
+		if len(f.Values) == 0 {
+			return fmt.Errorf("values of filter op %s on field %s are empty", f.Op, f.Field)
+		}
+	case FilterOpAnd, FilterOpOr:
+		if len(f.Filters) == 0 {
+			return fmt.Errorf("filters of filter op %s are empty", f.Op)
+		}
+		for _, sub := range f.Filters {
+			if err := sub.Validate(); err != nil {
+				return err
+			}
+		}
+	case FilterOpNot:
+		if len(f.Filters) != 1 {
+			return fmt.Errorf("filter op not expects 1 filter, got %d", len(f.Filters))
+		}
+		return f.Filters[0].Validate()
+	default:
+		return fmt.Errorf("unknown filter op: %q", f.Op)
+	}
+	return nil
+}
+
+var filterOpSymbols = map[FilterOp]string{
+	FilterOpEq:    "==",
+	FilterOpNe:    "!=",
+	FilterOpGt:    ">",
+	FilterOpGte:   ">=",
+	FilterOpLt:    "<",
+	FilterOpLte:   "<=",
+	FilterOpIn:    "IN",
+	FilterOpNotIn: "NOT IN",
+}
+
+// String returns the readable form of the filter for logging, e.g. (tenant_id == "t1" AND age > 18).
+func (f *Filter) String() string {
+	if f == nil {
+		return "<nil>"
+	}
+	switch f.Op {
+	case FilterOpEq, FilterOpNe, FilterOpGt, FilterOpGte, FilterOpLt, FilterOpLte:
+		return fmt.Sprintf("%s %s %s", f.Field, filterOpSymbols[f.Op], formatFilterValue(f.Value))
+	case FilterOpIn, FilterOpNotIn:
+		values := make([]string, len(f.Values))
+		for i, v := range f.Values {
+			values[i] = formatFilterValue(v)
+		}
+		return fmt.Sprintf("%s %s [%s]", f.Field, filterOpSymbols[f.Op], strings.Join(values, ", "))
+	case FilterOpAnd, FilterOpOr:
+		subs := make([]string, len(f.Filters))
+		for i, sub := range f.Filters {
+			subs[i] = sub.String()
+		}
+		return "(" + strings.Join(subs, " "+strings.ToUpper(string(f.Op))+" ") + ")"
+	case FilterOpNot:
+		if len(f.Filters) == 1 {
+			return "NOT " + f.Filters[0].String()
+		}
+	}
+	return fmt.Sprintf("<invalid filter op %q>", f.Op)
+}
+
+func formatFilterValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return fmt.Sprintf("%q", val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
+
+// Match evaluates the filter against the metadata of a document, for the backends without native filtering,
+// or to filter the documents retrieved in memory.
+// Numbers of different types are compared by their values, strings lexically and time.Time chronologically.
+// A comparison on an absent field doesn't match, except Ne and NotIn. Ordering values of different kinds is an error.
+func (f *Filter) Match(metaData map[string]any) (bool, error) {
+	if err := f.Validate(); err != nil {
+		return false, err
+	}
+	return f.match(metaData)
+}
+
+func (f *Filter) match(metaData map[string]any) (bool, error) {
+	switch f.Op {
+	case FilterOpAnd:
+		for _, sub := range f.Filters {
+			if ok, err := sub.match(metaData); err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case FilterOpOr:
+		for _, sub := range f.Filters {
+			if ok, err := sub.match(metaData); err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case FilterOpNot:
+		ok, err := f.Filters[0].match(metaData)
+		return !ok && err == nil, err
+	}
+
+	actual, exists := metaData[f.Field]
+	switch f.Op {
+	case FilterOpEq:
+		return exists && filterValueEqual(actual, f.Value), nil
+	case FilterOpNe:
+		return !exists || !filterValueEqual(actual, f.Value), nil
+	case FilterOpIn, FilterOpNotIn:
+		in := false
+		for _, v := range f.Values {
+			if exists && filterValueEqual(actual, v) {
+				in = true
+				break
+			}
+		}
+		return in == (f.Op == FilterOpIn), nil
+	}
+
+	if !exists {
+		return false, nil
+	}
+	c, err := compareFilterValues(actual, f.Value)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare field %s: %w", f.Field, err)
+	}
+	switch f.Op {
+	case FilterOpGt:
+		return c > 0, nil
+	case FilterOpGte:
+		return c >= 0, nil
+	case FilterOpLt:
+		return c < 0, nil
+	default:
+		return c <= 0, nil
+	}
+}
+
+func filterValueEqual(a, b any) bool {
+	if c, err := compareFilterValues(a, b); err == nil {
+		return c == 0
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// compareFilterValues returns -1, 0 or 1 as a is less than, equal to or greater than b.
+func compareFilterValues(a, b any) (int, error) {
+	if fa, ok := toFloat64(a); ok {
+		if fb, okk := toFloat64(b); okk {
+			return sortOrder(fa < fb, fa > fb), nil
+		}
+	}
+	switch va := a.(type) {
+	case string:
+		if vb, ok := b.(string); ok {
+			return strings.Compare(va, vb), nil
+		}
+	case time.Time:
+		if vb, ok := b.(time.Time); ok {
+			return sortOrder(va.Before(vb), va.After(vb)), nil
+		}
+	case bool:
+		if vb, ok := b.(bool); ok {
+			return sortOrder(!va && vb, va && !vb), nil
+		}
+	}
+	return 0, fmt.Errorf("can't compare %T with %T", a, b)
+}
+
+func sortOrder(less, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
+}
+
+func toFloat64(v any) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	default:
+		return 0, false
+	}
+}
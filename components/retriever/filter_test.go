@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package retriever
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	filter := And(
+		Eq("tenant_id", "t1"),
+		Gt("created_at", now),
+		Or(In("lang", "go", "python"), Not(Lte("stars", 100))),
+		NotIn("status", "deleted"),
+	)
+	assert.NoError(t, filter.Validate())
+	assert.Equal(t, `(tenant_id == "t1" AND created_at > 2025-01-02T03:04:05Z AND (lang IN ["go", "python"] OR NOT stars <= 100) AND status NOT IN ["deleted"])`, filter.String())
+
+	t.Run("match", func(t *testing.T) {
+		base := func() map[string]any {
+			return map[string]any{"tenant_id": "t1", "created_at": now.Add(time.Hour), "lang": "go", "stars": int64(10)}
+		}
+		ok, err := filter.Match(base())
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		for name, modify := range map[string]func(m map[string]any){
+			"other tenant":  func(m map[string]any) { m["tenant_id"] = "t2" },
+			"no tenant":     func(m map[string]any) { delete(m, "tenant_id") },
+			"too early":     func(m map[string]any) { m["created_at"] = now },
+			"other lang":    func(m map[string]any) { m["lang"] = "java" },
+			"deleted":       func(m map[string]any) { m["status"] = "deleted" },
+			"no created_at": func(m map[string]any) { delete(m, "created_at") },
+		} {
+			m := base()
+			modify(m)
+			ok, err = filter.Match(m)
+			assert.NoError(t, err, name)
+			assert.False(t, ok, name)
+		}
+
+		// numbers of different types
+		m := base()
+		m["lang"] = "java"
+		m["stars"] = float64(101)
+		ok, err = filter.Match(m)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		_, err = Gt("stars", "many").Match(m)
+		assert.ErrorContains(t, err, "can't compare float64 with string")
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		for _, f := range []*Filter{
+			nil,
+			Eq("", 1),
+			Eq("a", nil),
+			In("a"),
+			And(),
+			Or(Eq("a", 1), Gt("", 1)),
+			{Op: FilterOpNot},
+			{Op: "like", Field: "a", Value: "b"},
+		} {
+			assert.Error(t, f.Validate(), f.String())
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(Or(Eq("a", "x"), In("b", 1.0, 2.0)))
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"op":"or","filters":[{"op":"eq","field":"a","value":"x"},{"op":"in","field":"b","values":[1,2]}]}`, string(data))
+
+		var f *Filter
+		assert.NoError(t, json.Unmarshal(data, &f))
+		ok, err := f.Match(map[string]any{"b": 2})
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
@@ -34,6 +34,10 @@ type Options struct {
 	// DSLInfo is the dsl info for the retriever, which is used to retrieve the documents from the retriever.
 	// viking only
 	DSLInfo map[string]interface{}
+
+	// Filter is the provider-agnostic filter on the metadata of the documents,
+	// which the retriever translates to the filter syntax of its backend.
+	Filter *Filter
 }
 
 // WithIndex wraps the index option.
@@ -90,6 +94,21 @@ func WithDSLInfo(dsl map[string]any) Option {
 	}
 }
 
+// WithFilter wraps the metadata filter option.
+// e.g.
+//
+//	docs, err := r.Retrieve(ctx, query, retriever.WithFilter(retriever.And(
+//		retriever.Eq("tenant_id", tenantID),
+//		retriever.Gt("created_at", since),
+//	)))
+func WithFilter(filter *Filter) Option {
+	return Option{
+		apply: func(opts *Options) {
+			opts.Filter = filter
+		},
+	}
+}
+
 // Option is the call option for Retriever component.
 type Option struct {
 	apply func(opts *Options)
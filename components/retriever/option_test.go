@@ -34,6 +34,7 @@ func TestOptions(t *testing.T) {
 			dslInfo        = map[string]any{"dsl": "dsl"}
 			e              = &embedding.MockEmbedder{}
 			defaultTopK    = 1
+			filter         = Eq("tenant_id", "t1")
 		)
 
 		opts := GetCommonOptions(
@@ -46,6 +47,7 @@ func TestOptions(t *testing.T) {
 			WithSubIndex(subIndex),
 			WithDSLInfo(dslInfo),
 			WithEmbedding(e),
+			WithFilter(filter),
 		)
 
 		convey.So(opts, convey.ShouldResemble, &Options{
@@ -55,6 +57,7 @@ func TestOptions(t *testing.T) {
 			SubIndex:       &subIndex,
 			DSLInfo:        dslInfo,
 			Embedding:      e,
+			Filter:         filter,
 		})
 	})
 }
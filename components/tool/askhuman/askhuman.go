@@ -0,0 +1,158 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package askhuman
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+func init() {
+	schema.RegisterName[*Question]("_eino_ask_human_question")
+}
+
+const (
+	defaultToolName = "ask_human"
+	defaultToolDesc = "Ask the user a question when the request is ambiguous or some information is missing, " +
+		"instead of guessing. The answer of the user is returned."
+)
+
+// Config is the config of the ask-human tool.
+type Config struct {
+	// Name is the name of the tool. Optional, "ask_human" by default.
+	Name string
+	// Desc is the description of the tool, which tells the model when to ask. Optional.
+	Desc string
+}
+
+// Question is the payload of the interrupt raised when the tool is called.
+type Question struct {
+	ToolCallID string
+	Question   string
+	// Options are the answers suggested by the model, if any.
+	Options []string
+}
+
+type arguments struct {
+	Question string   `json:"question"`
+	Options  []string `json:"options,omitempty"`
+}
+
+type options struct {
+	answers map[string]string
+}
+
+// WithAnswers passes the answers of the user when resuming, keyed by tool call ID.
+// A tool call without an answer interrupts again.
+func WithAnswers(answers map[string]string) tool.Option {
+	return tool.WrapImplSpecificOptFn(func(o *options) {
+		o.answers = answers
+	})
+}
+
+// NewTool creates the ask-human tool.
+// The graph running the tool must be compiled with a CheckPointStore so that it can be resumed.
+func NewTool(ctx context.Context, conf *Config) (tool.InvokableTool, error) {
+	t := &askHuman{name: defaultToolName, desc: defaultToolDesc}
+	if conf != nil {
+		if conf.Name != "" {
+			t.name = conf.Name
+		}
+		if conf.Desc != "" {
+			t.desc = conf.Desc
+		}
+	}
+	return t, nil
+}
+
+type askHuman struct {
+	name string
+	desc string
+}
+
+func (a *askHuman) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{
+		Name: a.name,
+		Desc: a.desc,
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"question": {
+				Type:     schema.String,
+				Desc:     "the question to ask the user",
+				Required: true,
+			},
+			"options": {
+				Type:     schema.Array,
+				ElemInfo: &schema.ParameterInfo{Type: schema.String},
+				Desc:     "the answers to suggest to the user, if the question is a choice",
+			},
+		}),
+	}, nil
+}
+
+func (a *askHuman) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	callID := compose.GetToolCallID(ctx)
+	o := tool.GetImplSpecificOptions(&options{}, opts...)
+	if answer, ok := o.answers[callID]; ok {
+		return answer, nil
+	}
+
+	args := &arguments{}
+	if err := json.Unmarshal([]byte(argumentsInJSON), args); err != nil {
+		return "", fmt.Errorf("failed to unmarshal arguments of tool[%s]: %w", a.name, err)
+	}
+	return "", compose.NewInterruptAndRerunErr(&Question{
+		ToolCallID: callID,
+		Question:   args.Question,
+		Options:    args.Options,
+	})
+}
+
+// GetQuestions returns the pending questions within the interrupt info, including the ones of the sub graphs, sorted by tool call ID.
+func GetQuestions(info *compose.InterruptInfo) []*Question {
+	questions := collectQuestions(info, nil)
+	sort.Slice(questions, func(i, j int) bool {
+		return questions[i].ToolCallID < questions[j].ToolCallID
+	})
+	return questions
+}
+
+func collectQuestions(info *compose.InterruptInfo, ret []*Question) []*Question {
+	if info == nil {
+		return ret
+	}
+	for _, extra := range info.RerunNodesExtra {
+		te, ok := extra.(*compose.ToolsInterruptAndRerunExtra)
+		if !ok {
+			continue
+		}
+		for _, e := range te.RerunExtraMap {
+			if q, ok := e.(*Question); ok {
+				ret = append(ret, q)
+			}
+		}
+	}
+	for _, sub := range info.SubGraphs {
+		ret = collectQuestions(sub, ret)
+	}
+	return ret
+}
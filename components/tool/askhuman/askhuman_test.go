@@ -0,0 +1,99 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package askhuman
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/agent/react"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type inMemoryStore struct {
+	m map[string][]byte
+}
+
+func (i *inMemoryStore) Get(ctx context.Context, checkPointID string) ([]byte, bool, error) {
+	v, ok := i.m[checkPointID]
+	return v, ok, nil
+}
+
+func (i *inMemoryStore) Set(ctx context.Context, checkPointID string, checkPoint []byte) error {
+	i.m[checkPointID] = checkPoint
+	return nil
+}
+
+func TestAskHumanInReactAgent(t *testing.T) {
+	ctx := context.Background()
+
+	ask, err := NewTool(ctx, nil)
+	assert.NoError(t, err)
+	info, err := ask.Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "ask_human", info.Name)
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			if last := input[len(input)-1]; last.Role == schema.Tool {
+				return schema.AssistantMessage("booked for "+last.Content, nil), nil
+			}
+			return schema.AssistantMessage("", []schema.ToolCall{{ID: "call_1", Function: schema.FunctionCall{
+				Name:      "ask_human",
+				Arguments: `{"question": "which day?", "options": ["monday", "tuesday"]}`,
+			}}}), nil
+		}).Times(2)
+
+	a, err := react.NewAgent(ctx, &react.AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig:      compose.ToolsNodeConfig{Tools: []tool.BaseTool{ask}},
+	})
+	assert.NoError(t, err)
+	g, opts := a.ExportGraph()
+	r, err := compose.NewChain[[]*schema.Message, *schema.Message]().
+		AppendGraph(g, opts...).
+		Compile(ctx, compose.WithCheckPointStore(&inMemoryStore{m: map[string][]byte{}}))
+	assert.NoError(t, err)
+
+	input := []*schema.Message{schema.UserMessage("book a meeting room")}
+	_, err = r.Invoke(ctx, input, compose.WithCheckPointID("1"))
+	interruptInfo, ok := compose.ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	questions := GetQuestions(interruptInfo)
+	assert.Equal(t, []*Question{{ToolCallID: "call_1", Question: "which day?", Options: []string{"monday", "tuesday"}}}, questions)
+
+	// resuming without answer asks again
+	_, err = r.Invoke(ctx, input, compose.WithCheckPointID("1"))
+	interruptInfo, ok = compose.ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	assert.Len(t, GetQuestions(interruptInfo), 1)
+
+	out, err := r.Invoke(ctx, input, compose.WithCheckPointID("1"),
+		compose.WithToolsNodeOption(compose.WithToolOption(WithAnswers(map[string]string{"call_1": "tuesday"}))))
+	assert.NoError(t, err)
+	assert.Equal(t, "booked for tuesday", out.Content)
+}
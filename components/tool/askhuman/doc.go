@@ -0,0 +1,33 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package askhuman provides a tool for the model to ask the user a clarification question.
+// Calling the tool interrupts the graph with the question, and the answer passed on resume becomes the result of the tool call.
+// e.g. with a react agent whose graph is compiled with a CheckPointStore:
+//
+//	_, err := runnable.Invoke(ctx, input, compose.WithCheckPointID(id))
+//	if info, ok := compose.ExtractInterruptInfo(err); ok {
+//		answers := map[string]string{}
+//		for _, q := range askhuman.GetQuestions(info) {
+//			answers[q.ToolCallID] = readFromUser(q.Question)
+//		}
+//		out, err = runnable.Invoke(ctx, input, compose.WithCheckPointID(id),
+//			compose.WithToolsNodeOption(compose.WithToolOption(askhuman.WithAnswers(answers))))
+//	}
+//
+// With adk, the questions are got from the compose.InterruptInfo in the ChatModelAgentInterruptInfo,
+// and the answers are passed by adk.WithToolOptions when resuming.
+package askhuman
@@ -41,3 +41,14 @@ type StreamableTool interface {
 
 	StreamableRun(ctx context.Context, argumentsInJSON string, opts ...Option) (*schema.StreamReader[string], error)
 }
+
+// StreamingInputTool the tool which can start running before the ChatModel finishes generating its arguments,
+// e.g. a tool that starts executing code or SQL while it's still being generated, to cut the end-to-end latency for long arguments.
+// The arguments stream yields the fragments of the arguments in JSON format in order, which concat to the complete arguments.
+// When the ToolsNode runs in stream mode, it feeds the fragments to the tool as soon as the ChatModel emits them,
+// otherwise, the complete arguments are sent as a single chunk.
+type StreamingInputTool interface {
+	BaseTool
+
+	StreamingInputRun(ctx context.Context, arguments *schema.StreamReader[string], opts ...Option) (string, error)
+}
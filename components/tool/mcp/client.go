@@ -0,0 +1,202 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// ProtocolVersion is the version of the MCP protocol requested by the client.
+const ProtocolVersion = "2025-03-26"
+
+// Implementation describes the name and version of an MCP client or server.
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// Tool is a tool listed by the MCP server.
+type Tool struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// InputSchema is the JSON schema of the arguments of the tool.
+	InputSchema json.RawMessage `json:"inputSchema,omitempty"`
+}
+
+// Content is an item of the result of a tool call.
+// Type is one of text, image, audio, resource and resource_link, the other fields are set according to it.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text,omitempty"`
+	// Data is the base64 encoded data of image and audio.
+	Data     string          `json:"data,omitempty"`
+	MimeType string          `json:"mimeType,omitempty"`
+	Resource json.RawMessage `json:"resource,omitempty"`
+	URI      string          `json:"uri,omitempty"`
+}
+
+// CallToolResult is the result of a tool call.
+type CallToolResult struct {
+	Content           []*Content      `json:"content"`
+	StructuredContent json.RawMessage `json:"structuredContent,omitempty"`
+	// IsError means the tool failed, and the content describes the failure.
+	IsError bool `json:"isError,omitempty"`
+}
+
+// Client is an initialized connection to an MCP server.
+type Client struct {
+	transport Transport
+
+	serverInfo   Implementation
+	instructions string
+}
+
+// ClientOption is the option of NewClient.
+type ClientOption func(o *clientOptions)
+
+type clientOptions struct {
+	info Implementation
+}
+
+// WithClientInfo sets the name and version of the client reported to the server, which is eino by default.
+func WithClientInfo(name, version string) ClientOption {
+	return func(o *clientOptions) {
+		o.info = Implementation{Name: name, Version: version}
+	}
+}
+
+// NewClient initializes the session with the MCP server through the transport.
+// The client owns the transport, which is closed by Client.Close, or once the initialization fails.
+// e.g.
+//
+//	t, err := mcp.NewStdioTransport(exec.Command("my-mcp-server"))
+//	cli, err := mcp.NewClient(ctx, t)
+//	defer cli.Close()
+func NewClient(ctx context.Context, transport Transport, opts ...ClientOption) (*Client, error) {
+	o := &clientOptions{info: Implementation{Name: "eino"}}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	result, err := initialize(ctx, transport, o.info)
+	if err != nil {
+		_ = transport.Close()
+		return nil, err
+	}
+
+	return &Client{
+		transport:    transport,
+		serverInfo:   result.ServerInfo,
+		instructions: result.Instructions,
+	}, nil
+}
+
+type initializeResult struct {
+	ProtocolVersion string         `json:"protocolVersion"`
+	ServerInfo      Implementation `json:"serverInfo"`
+	Instructions    string         `json:"instructions"`
+}
+
+func initialize(ctx context.Context, transport Transport, info Implementation) (*initializeResult, error) {
+	raw, err := transport.Call(ctx, "initialize", map[string]any{
+		"protocolVersion": ProtocolVersion,
+		"capabilities":    map[string]any{},
+		"clientInfo":      info,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize mcp session: %w", err)
+	}
+	result := &initializeResult{}
+	if err = json.Unmarshal(raw, result); err != nil {
+		return nil, fmt.Errorf("failed to decode the initialize result: %w", err)
+	}
+	if err = transport.Notify(ctx, "notifications/initialized", nil); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ServerInfo returns the name and version of the server.
+func (c *Client) ServerInfo() Implementation {
+	return c.serverInfo
+}
+
+// Instructions returns the instructions of the server on how to use its tools, which can be added to the system prompt.
+func (c *Client) Instructions() string {
+	return c.instructions
+}
+
+// ListTools lists all the tools of the server, following the pagination.
+func (c *Client) ListTools(ctx context.Context) ([]*Tool, error) {
+	var (
+		tools  []*Tool
+		cursor string
+	)
+	for {
+		var params any
+		if cursor != "" {
+			params = map[string]any{"cursor": cursor}
+		}
+		raw, err := c.transport.Call(ctx, "tools/list", params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list mcp tools: %w", err)
+		}
+		var result struct {
+			Tools      []*Tool `json:"tools"`
+			NextCursor string  `json:"nextCursor"`
+		}
+		if err = json.Unmarshal(raw, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode the tools/list result: %w", err)
+		}
+		tools = append(tools, result.Tools...)
+		if result.NextCursor == "" || result.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// CallTool calls the tool with the arguments in JSON format.
+// A failure of the tool is reported by CallToolResult.IsError rather than the returned error.
+func (c *Client) CallTool(ctx context.Context, name string, argumentsInJSON string) (*CallToolResult, error) {
+	args := json.RawMessage("{}")
+	if argumentsInJSON != "" {
+		args = json.RawMessage(argumentsInJSON)
+		if !json.Valid(args) {
+			return nil, fmt.Errorf("arguments of mcp tool %s are not valid JSON: %s", name, argumentsInJSON)
+		}
+	}
+	raw, err := c.transport.Call(ctx, "tools/call", map[string]any{
+		"name":      name,
+		"arguments": args,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to call mcp tool %s: %w", name, err)
+	}
+	result := &CallToolResult{}
+	if err = json.Unmarshal(raw, result); err != nil {
+		return nil, fmt.Errorf("failed to decode the result of mcp tool %s: %w", name, err)
+	}
+	return result, nil
+}
+
+// Close closes the transport.
+func (c *Client) Close() error {
+	return c.transport.Close()
+}
@@ -0,0 +1,187 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+const headerSessionID = "Mcp-Session-Id"
+
+// NewStreamableHTTPTransport connects to a remote MCP server with the streamable HTTP transport of protocol version 2025-03-26:
+// each message is posted to serverURL, and the response is either a JSON object or an SSE stream ending with the response.
+// The session id assigned by the server is kept and sent back, and the session is terminated when the transport is closed.
+// client can be nil to use http.DefaultClient, and header is added to all the requests, e.g. for authorization.
+func NewStreamableHTTPTransport(serverURL string, client *http.Client, header http.Header) Transport {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &streamableHTTPTransport{url: serverURL, client: client, header: header}
+}
+
+type streamableHTTPTransport struct {
+	url    string
+	client *http.Client
+	header http.Header
+
+	nextID int64
+
+	mu        sync.Mutex
+	sessionID string
+	closed    bool
+}
+
+func (t *streamableHTTPTransport) Call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	id := json.RawMessage(strconv.FormatInt(atomic.AddInt64(&t.nextID, 1), 10))
+	data, err := newMessage(id, method, params)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.post(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/event-stream" {
+		msg := &jsonrpcMessage{}
+		if err = json.NewDecoder(resp.Body).Decode(msg); err != nil {
+			return nil, fmt.Errorf("failed to decode the response of %s: %w", method, err)
+		}
+		return msg.result()
+	}
+
+	var result *jsonrpcMessage
+	err = readSSE(resp.Body, func(_, data string) bool {
+		msg := &jsonrpcMessage{}
+		if json.Unmarshal([]byte(data), msg) != nil {
+			return true
+		}
+		switch {
+		case msg.isResponse() && bytes.Equal(msg.ID, id):
+			result = msg
+			return false
+		case msg.isRequest():
+			go t.reply(msg)
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response stream of %s: %w", method, err)
+	}
+	if result == nil {
+		return nil, fmt.Errorf("the response stream of %s ended without a response", method)
+	}
+	return result.result()
+}
+
+func (t *streamableHTTPTransport) Notify(ctx context.Context, method string, params any) error {
+	data, err := newMessage(nil, method, params)
+	if err != nil {
+		return err
+	}
+	resp, err := t.post(ctx, data)
+	if err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", method, err)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return resp.Body.Close()
+}
+
+func (t *streamableHTTPTransport) reply(req *jsonrpcMessage) {
+	data, err := replyToServer(req)
+	if err != nil {
+		return
+	}
+	if resp, err := t.post(context.Background(), data); err == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+}
+
+// post sends the message, and returns the successful response, whose body must be closed by the caller.
+func (t *streamableHTTPTransport) post(ctx context.Context, data []byte) (*http.Response, error) {
+	t.mu.Lock()
+	sessionID, closed := t.sessionID, t.closed
+	t.mu.Unlock()
+	if closed {
+		return nil, errClosed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	setHeader(req, t.header)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if sessionID != "" {
+		req.Header.Set(headerSessionID, sessionID)
+	}
+
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		return nil, newHTTPError(resp)
+	}
+	if id := resp.Header.Get(headerSessionID); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
+	}
+	return resp, nil
+}
+
+// Close terminates the session on the server, if the server has assigned one.
+func (t *streamableHTTPTransport) Close() error {
+	t.mu.Lock()
+	sessionID, closed := t.sessionID, t.closed
+	t.closed = true
+	t.mu.Unlock()
+	if closed || sessionID == "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	setHeader(req, t.header)
+	req.Header.Set(headerSessionID, sessionID)
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to terminate mcp session: %w", err)
+	}
+	defer resp.Body.Close()
+	// servers not allowing clients to terminate sessions respond 405
+	if resp.StatusCode/100 != 2 && resp.StatusCode != http.StatusMethodNotAllowed {
+		return newHTTPError(resp)
+	}
+	return nil
+}
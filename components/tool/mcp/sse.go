@@ -0,0 +1,214 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// readSSE parses the server-sent events from r, and calls fn with each of them until fn returns false or r ends.
+func readSSE(r io.Reader, fn func(event, data string) bool) error {
+	br := bufio.NewReader(r)
+	var (
+		event string
+		data  []string
+	)
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				if event == "" {
+					event = "message"
+				}
+				if !fn(event, strings.Join(data, "\n")) {
+					return nil
+				}
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, ":"):
+			// comment, e.g. keep-alive
+		default:
+			field, value, _ := cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				data = append(data, value)
+			}
+		}
+	}
+}
+
+// cut is strings.Cut, which is not available in go1.18.
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// NewSSETransport connects to a remote MCP server with the HTTP+SSE transport of protocol version 2024-11-05,
+// which is still served by many servers: the messages from the server are received from a long-lived SSE stream at sseURL,
+// and the messages to the server are posted to the endpoint announced by the stream.
+// ctx only bounds the time to wait for the endpoint, client can be nil to use http.DefaultClient,
+// and header is added to all the requests, e.g. for authorization.
+// Servers implementing protocol version 2025-03-26 or later should be connected with NewStreamableHTTPTransport instead.
+func NewSSETransport(ctx context.Context, sseURL string, client *http.Client, header http.Header) (Transport, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	base, err := url.Parse(sseURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid mcp sse url: %w", err)
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(streamCtx, http.MethodGet, sseURL, nil)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	setHeader(req, header)
+	req.Header.Set("Accept", "text/event-stream")
+	resp, err := client.Do(req)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to connect to mcp sse stream: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		cancel()
+		return nil, newHTTPError(resp)
+	}
+
+	t := &sseTransport{client: client, header: header, cancel: cancel, done: make(chan struct{})}
+	t.rpcConn = newRPCConn(t.post)
+
+	endpointCh := make(chan string, 1)
+	go func() {
+		defer close(t.done)
+		defer resp.Body.Close()
+		err := readSSE(resp.Body, func(event, data string) bool {
+			switch event {
+			case "endpoint":
+				select {
+				case endpointCh <- data:
+				default:
+				}
+			case "message":
+				t.handle([]byte(data))
+			}
+			return true
+		})
+		if err == nil {
+			err = errors.New("mcp sse stream ended")
+		}
+		t.close(err)
+	}()
+
+	select {
+	case endpoint := <-endpointCh:
+		ref, err := url.Parse(endpoint)
+		if err != nil {
+			_ = t.Close()
+			return nil, fmt.Errorf("invalid mcp message endpoint %q: %w", endpoint, err)
+		}
+		t.endpoint = base.ResolveReference(ref).String()
+	case <-t.done:
+		_ = t.Close()
+		return nil, fmt.Errorf("mcp sse stream ended before the endpoint is received: %w", t.closedErr())
+	case <-ctx.Done():
+		_ = t.Close()
+		return nil, ctx.Err()
+	}
+	return t, nil
+}
+
+type sseTransport struct {
+	*rpcConn
+
+	client   *http.Client
+	header   http.Header
+	endpoint string
+
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (t *sseTransport) post(ctx context.Context, data []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	setHeader(req, t.header)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return newHTTPError(resp)
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+func (t *sseTransport) Close() error {
+	t.closeOnce.Do(func() {
+		t.close(errClosed)
+		t.cancel()
+		<-t.done
+	})
+	return nil
+}
+
+func setHeader(req *http.Request, header http.Header) {
+	for k, vs := range header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+}
+
+// maxErrorBodySize limits the size of the response body kept in the error.
+const maxErrorBodySize = 1024
+
+func newHTTPError(resp *http.Response) error {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	return fmt.Errorf("unexpected http status of mcp server: %s, body: %s", resp.Status, bytes.TrimSpace(body))
+}
@@ -0,0 +1,109 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// stdioCloseTimeout is the time waited for the server to exit after its stdin is closed, before it's killed.
+const stdioCloseTimeout = 2 * time.Second
+
+// NewStdioTransport starts the command as a local MCP server, and talks to it with newline delimited JSON-RPC messages
+// through its stdin and stdout. The stderr of the command is left as is, set cmd.Stderr to collect the logs of the server.
+// Closing the transport closes the stdin of the server, and kills it if it doesn't exit in time.
+// e.g.
+//
+//	t, err := mcp.NewStdioTransport(exec.Command("npx", "-y", "@modelcontextprotocol/server-filesystem", "/data"))
+func NewStdioTransport(cmd *exec.Cmd) (Transport, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stdin of mcp server: %w", err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stdout of mcp server: %w", err)
+	}
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start mcp server: %w", err)
+	}
+
+	t := &stdioTransport{cmd: cmd, stdin: stdin, exited: make(chan struct{})}
+	t.rpcConn = newRPCConn(t.write)
+	go t.read(stdout)
+	return t, nil
+}
+
+type stdioTransport struct {
+	*rpcConn
+
+	cmd *exec.Cmd
+
+	writeMu sync.Mutex
+	stdin   io.WriteCloser
+
+	closeOnce sync.Once
+	exited    chan struct{}
+}
+
+func (t *stdioTransport) write(_ context.Context, data []byte) error {
+	t.writeMu.Lock()
+	defer t.writeMu.Unlock()
+	_, err := t.stdin.Write(append(data, '\n'))
+	return err
+}
+
+func (t *stdioTransport) read(stdout io.Reader) {
+	r := bufio.NewReader(stdout)
+	for {
+		line, err := r.ReadBytes('\n')
+		if line = bytes.TrimSpace(line); len(line) > 0 {
+			t.handle(line)
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = errors.New("mcp server exited")
+			}
+			t.close(err)
+			break
+		}
+	}
+	_ = t.cmd.Wait()
+	close(t.exited)
+}
+
+func (t *stdioTransport) Close() error {
+	t.closeOnce.Do(func() {
+		t.close(errClosed)
+		_ = t.stdin.Close()
+		select {
+		case <-t.exited:
+		case <-time.After(stdioCloseTimeout):
+			_ = t.cmd.Process.Kill()
+			<-t.exited
+		}
+	})
+	return nil
+}
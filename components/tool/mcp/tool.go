@@ -0,0 +1,190 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Config is the config of GetTools.
+type Config struct {
+	// Client is the initialized client of the MCP server. Required.
+	Client *Client
+
+	// ToolNameList specifies the tools to get from the server. All the tools are got if it's empty.
+	ToolNameList []string
+
+	// RequireApproval specifies the tools that need approval before running, keyed by tool name.
+	RequireApproval map[string]bool
+	// Approve decides whether a call to a tool in RequireApproval can run. Required if RequireApproval is set.
+	// A rejected call isn't sent to the server, instead the model is told the call is rejected.
+	// To wait for the decision of a human, return an interrupt error, e.g. compose.NewInterruptAndRerunErr(req),
+	// and pass the decision to the resumed run, or use adk.ToolsConfig.RequireApproval with the tools instead.
+	Approve func(ctx context.Context, req *ApprovalRequest) (*ApprovalResponse, error)
+
+	// OnListTools is called with the tools listed from the server, e.g. to log or to record them for observability.
+	// It's called before ToolNameList is applied.
+	OnListTools func(ctx context.Context, server Implementation, tools []*Tool)
+}
+
+// ApprovalRequest is a call to a tool that requires approval, see Config.RequireApproval.
+type ApprovalRequest struct {
+	Server    Implementation
+	ToolName  string
+	Arguments string
+}
+
+// ApprovalResponse is the decision on an ApprovalRequest.
+type ApprovalResponse struct {
+	Approved bool
+	// Reason is told to the model when the tool call is rejected.
+	Reason string
+}
+
+// GetTools lists the tools of the MCP server as eino tools, whose parameters are the input schemas of the MCP tools.
+// The result of a call is the text content of the MCP tool result, the other kinds of contents are kept as JSON,
+// and a result with IsError is returned as an error.
+// e.g.
+//
+//	tools, err := mcp.GetTools(ctx, &mcp.Config{Client: cli})
+//	agent, err := react.NewAgent(ctx, &react.AgentConfig{ToolsConfig: compose.ToolsNodeConfig{Tools: tools}})
+func GetTools(ctx context.Context, conf *Config) ([]tool.BaseTool, error) {
+	if conf == nil || conf.Client == nil {
+		return nil, fmt.Errorf("mcp client is required")
+	}
+	if len(conf.RequireApproval) > 0 && conf.Approve == nil {
+		return nil, fmt.Errorf("approve func is required when RequireApproval is set")
+	}
+
+	mcpTools, err := conf.Client.ListTools(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if conf.OnListTools != nil {
+		conf.OnListTools(ctx, conf.Client.ServerInfo(), mcpTools)
+	}
+
+	var wanted map[string]bool
+	if len(conf.ToolNameList) > 0 {
+		wanted = make(map[string]bool, len(conf.ToolNameList))
+		for _, name := range conf.ToolNameList {
+			wanted[name] = true
+		}
+	}
+
+	ret := make([]tool.BaseTool, 0, len(mcpTools))
+	for _, t := range mcpTools {
+		if wanted != nil && !wanted[t.Name] {
+			continue
+		}
+		info := &schema.ToolInfo{
+			Name: t.Name,
+			Desc: t.Description,
+		}
+		if len(t.InputSchema) > 0 {
+			js := &jsonschema.Schema{}
+			if err = json.Unmarshal(t.InputSchema, js); err != nil {
+				return nil, fmt.Errorf("failed to decode the input schema of mcp tool %s: %w", t.Name, err)
+			}
+			info.ParamsOneOf = schema.NewParamsOneOfByJSONSchema(js)
+		}
+		ret = append(ret, &mcpTool{
+			info:            info,
+			conf:            conf,
+			requireApproval: conf.RequireApproval[t.Name],
+		})
+		delete(wanted, t.Name)
+	}
+	if len(wanted) > 0 {
+		missing := make([]string, 0, len(wanted))
+		for _, name := range conf.ToolNameList {
+			if wanted[name] {
+				missing = append(missing, name)
+			}
+		}
+		return nil, fmt.Errorf("mcp tools not found on the server: %s", strings.Join(missing, ", "))
+	}
+
+	return ret, nil
+}
+
+type mcpTool struct {
+	info            *schema.ToolInfo
+	conf            *Config
+	requireApproval bool
+}
+
+func (m *mcpTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return m.info, nil
+}
+
+func (m *mcpTool) InvokableRun(ctx context.Context, argumentsInJSON string, _ ...tool.Option) (string, error) {
+	if m.requireApproval {
+		resp, err := m.conf.Approve(ctx, &ApprovalRequest{
+			Server:    m.conf.Client.ServerInfo(),
+			ToolName:  m.info.Name,
+			Arguments: argumentsInJSON,
+		})
+		if err != nil {
+			return "", err
+		}
+		if resp == nil || !resp.Approved {
+			result := fmt.Sprintf("the call to tool '%s' was rejected by the user", m.info.Name)
+			if resp != nil && resp.Reason != "" {
+				result += ", reason: " + resp.Reason
+			}
+			return result, nil
+		}
+	}
+
+	result, err := m.conf.Client.CallTool(ctx, m.info.Name, argumentsInJSON)
+	if err != nil {
+		return "", err
+	}
+	output, err := contentToString(result.Content)
+	if err != nil {
+		return "", err
+	}
+	if result.IsError {
+		return "", fmt.Errorf("mcp tool %s failed: %s", m.info.Name, output)
+	}
+	return output, nil
+}
+
+func contentToString(contents []*Content) (string, error) {
+	parts := make([]string, 0, len(contents))
+	for _, c := range contents {
+		if c.Type == "text" {
+			parts = append(parts, c.Text)
+			continue
+		}
+		data, err := json.Marshal(c)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal mcp tool result content: %w", err)
+		}
+		parts = append(parts, string(data))
+	}
+	return strings.Join(parts, "\n"), nil
+}
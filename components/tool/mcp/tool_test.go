@@ -0,0 +1,127 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+)
+
+func newTestClient(t *testing.T) *Client {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		resp := serveTestMessage(data)
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(resp)
+	}))
+	t.Cleanup(srv.Close)
+
+	cli, err := NewClient(context.Background(), NewStreamableHTTPTransport(srv.URL, nil, nil))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = cli.Close() })
+	return cli
+}
+
+func TestGetTools(t *testing.T) {
+	ctx := context.Background()
+	cli := newTestClient(t)
+
+	var listed []string
+	tools, err := GetTools(ctx, &Config{
+		Client: cli,
+		OnListTools: func(ctx context.Context, server Implementation, tools []*Tool) {
+			assert.Equal(t, "test", server.Name)
+			for _, t := range tools {
+				listed = append(listed, t.Name)
+			}
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"echo", "fail"}, listed)
+	assert.Len(t, tools, 2)
+
+	info, err := tools[0].Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "echo", info.Name)
+	assert.Equal(t, "echo the text", info.Desc)
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"text"}, js.Required)
+
+	result, err := tools[0].(tool.InvokableTool).InvokableRun(ctx, `{"text": "hello"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\n{\"type\":\"image\",\"data\":\"aGk=\",\"mimeType\":\"image/png\"}", result)
+
+	_, err = tools[1].(tool.InvokableTool).InvokableRun(ctx, `{}`)
+	assert.ErrorContains(t, err, "mcp tool fail failed: boom")
+
+	tools, err = GetTools(ctx, &Config{Client: cli, ToolNameList: []string{"echo"}})
+	assert.NoError(t, err)
+	assert.Len(t, tools, 1)
+
+	_, err = GetTools(ctx, &Config{Client: cli, ToolNameList: []string{"echo", "unknown"}})
+	assert.ErrorContains(t, err, "mcp tools not found on the server: unknown")
+}
+
+func TestGetToolsWithApproval(t *testing.T) {
+	ctx := context.Background()
+	cli := newTestClient(t)
+
+	_, err := GetTools(ctx, &Config{Client: cli, RequireApproval: map[string]bool{"echo": true}})
+	assert.Error(t, err)
+
+	var requests []*ApprovalRequest
+	approved := false
+	tools, err := GetTools(ctx, &Config{
+		Client:          cli,
+		ToolNameList:    []string{"echo"},
+		RequireApproval: map[string]bool{"echo": true},
+		Approve: func(ctx context.Context, req *ApprovalRequest) (*ApprovalResponse, error) {
+			requests = append(requests, req)
+			return &ApprovalResponse{Approved: approved, Reason: "not now"}, nil
+		},
+	})
+	assert.NoError(t, err)
+	echo := tools[0].(tool.InvokableTool)
+
+	result, err := echo.InvokableRun(ctx, `{"text": "hello"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "the call to tool 'echo' was rejected by the user, reason: not now", result)
+
+	approved = true
+	result, err = echo.InvokableRun(ctx, `{"text": "hello"}`)
+	assert.NoError(t, err)
+	assert.Contains(t, result, "hello")
+
+	assert.Len(t, requests, 2)
+	assert.Equal(t, &ApprovalRequest{
+		Server:    Implementation{Name: "test", Version: "1.0"},
+		ToolName:  "echo",
+		Arguments: `{"text": "hello"}`,
+	}, requests[0])
+}
@@ -0,0 +1,220 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// Transport exchanges JSON-RPC messages with an MCP server.
+// NewStdioTransport, NewSSETransport and NewStreamableHTTPTransport cover the standard transports of MCP,
+// implement it to talk to a server in other ways.
+type Transport interface {
+	// Call sends a request and waits for its result.
+	// An error returned by the server is an *RPCError.
+	Call(ctx context.Context, method string, params any) (json.RawMessage, error)
+	// Notify sends a notification, which has no response.
+	Notify(ctx context.Context, method string, params any) error
+	// Close releases the connection to the server.
+	Close() error
+}
+
+// RPCError is the error returned by the MCP server for a request.
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("mcp rpc error, code=%d, message=%s", e.Code, e.Message)
+}
+
+const (
+	jsonrpcVersion = "2.0"
+
+	codeMethodNotFound = -32601
+)
+
+type jsonrpcMessage struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
+}
+
+func (m *jsonrpcMessage) isResponse() bool {
+	return len(m.ID) > 0 && m.Method == ""
+}
+
+func (m *jsonrpcMessage) isRequest() bool {
+	return len(m.ID) > 0 && m.Method != ""
+}
+
+func (m *jsonrpcMessage) result() (json.RawMessage, error) {
+	if m.Error != nil {
+		return nil, m.Error
+	}
+	return m.Result, nil
+}
+
+func newMessage(id json.RawMessage, method string, params any) ([]byte, error) {
+	msg := &jsonrpcMessage{JSONRPC: jsonrpcVersion, ID: id, Method: method}
+	if params != nil {
+		p, err := json.Marshal(params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal params of %s: %w", method, err)
+		}
+		msg.Params = p
+	}
+	return json.Marshal(msg)
+}
+
+// replyToServer answers the requests sent by the server, only ping is supported.
+func replyToServer(req *jsonrpcMessage) ([]byte, error) {
+	resp := &jsonrpcMessage{JSONRPC: jsonrpcVersion, ID: req.ID}
+	if req.Method == "ping" {
+		resp.Result = json.RawMessage("{}")
+	} else {
+		resp.Error = &RPCError{Code: codeMethodNotFound, Message: "method not found: " + req.Method}
+	}
+	return json.Marshal(resp)
+}
+
+// rpcConn matches the responses to the requests for the transports with a long-lived connection,
+// where the messages from the server are read by a separate goroutine and passed to handle.
+type rpcConn struct {
+	send func(ctx context.Context, data []byte) error
+
+	nextID int64
+
+	mu      sync.Mutex
+	pending map[string]chan *jsonrpcMessage
+	err     error
+}
+
+func newRPCConn(send func(ctx context.Context, data []byte) error) *rpcConn {
+	return &rpcConn{send: send, pending: make(map[string]chan *jsonrpcMessage)}
+}
+
+func (c *rpcConn) Call(ctx context.Context, method string, params any) (json.RawMessage, error) {
+	id := json.RawMessage(strconv.FormatInt(atomic.AddInt64(&c.nextID, 1), 10))
+	data, err := newMessage(id, method, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan *jsonrpcMessage, 1)
+	c.mu.Lock()
+	if c.err != nil {
+		c.mu.Unlock()
+		return nil, c.err
+	}
+	c.pending[string(id)] = ch
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		delete(c.pending, string(id))
+		c.mu.Unlock()
+	}()
+
+	if err = c.send(ctx, data); err != nil {
+		return nil, fmt.Errorf("failed to send %s request: %w", method, err)
+	}
+
+	select {
+	case msg, ok := <-ch:
+		if !ok {
+			return nil, c.closedErr()
+		}
+		return msg.result()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (c *rpcConn) Notify(ctx context.Context, method string, params any) error {
+	if err := c.closedErr(); err != nil {
+		return err
+	}
+	data, err := newMessage(nil, method, params)
+	if err != nil {
+		return err
+	}
+	if err = c.send(ctx, data); err != nil {
+		return fmt.Errorf("failed to send %s notification: %w", method, err)
+	}
+	return nil
+}
+
+// handle processes a message from the server.
+func (c *rpcConn) handle(data []byte) {
+	msg := &jsonrpcMessage{}
+	if err := json.Unmarshal(data, msg); err != nil {
+		// not a JSON-RPC message, e.g. a log line of the server
+		return
+	}
+	switch {
+	case msg.isResponse():
+		c.mu.Lock()
+		if ch, ok := c.pending[string(msg.ID)]; ok {
+			ch <- msg
+			delete(c.pending, string(msg.ID))
+		}
+		c.mu.Unlock()
+	case msg.isRequest():
+		reply, err := replyToServer(msg)
+		if err != nil {
+			return
+		}
+		go func() {
+			_ = c.send(context.Background(), reply)
+		}()
+	}
+	// notifications from the server are ignored
+}
+
+// close fails the pending and subsequent calls with err.
+func (c *rpcConn) close(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return
+	}
+	c.err = err
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+}
+
+func (c *rpcConn) closedErr() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.err
+}
+
+// errClosed is returned by the calls after the transport is closed.
+var errClosed = errors.New("mcp transport is closed")
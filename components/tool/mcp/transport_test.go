@@ -0,0 +1,275 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// serveTestMessage is a fake MCP server, it returns the response of the message, or nil for notifications.
+func serveTestMessage(data []byte) []byte {
+	msg := &jsonrpcMessage{}
+	if err := json.Unmarshal(data, msg); err != nil || !msg.isRequest() {
+		return nil
+	}
+	resp := &jsonrpcMessage{JSONRPC: jsonrpcVersion, ID: msg.ID}
+	var result any
+	switch msg.Method {
+	case "initialize":
+		result = map[string]any{
+			"protocolVersion": ProtocolVersion,
+			"capabilities":    map[string]any{"tools": map[string]any{}},
+			"serverInfo":      Implementation{Name: "test", Version: "1.0"},
+			"instructions":    "use echo to echo",
+		}
+	case "tools/list":
+		var params struct {
+			Cursor string `json:"cursor"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		if params.Cursor == "" {
+			result = map[string]any{
+				"tools": []any{map[string]any{
+					"name":        "echo",
+					"description": "echo the text",
+					"inputSchema": map[string]any{
+						"type":       "object",
+						"properties": map[string]any{"text": map[string]any{"type": "string"}},
+						"required":   []string{"text"},
+					},
+				}},
+				"nextCursor": "2",
+			}
+		} else {
+			result = map[string]any{
+				"tools": []any{map[string]any{"name": "fail", "inputSchema": map[string]any{"type": "object"}}},
+			}
+		}
+	case "tools/call":
+		var params struct {
+			Name      string `json:"name"`
+			Arguments struct {
+				Text string `json:"text"`
+			} `json:"arguments"`
+		}
+		_ = json.Unmarshal(msg.Params, &params)
+		switch params.Name {
+		case "echo":
+			result = &CallToolResult{Content: []*Content{
+				{Type: "text", Text: params.Arguments.Text},
+				{Type: "image", Data: "aGk=", MimeType: "image/png"},
+			}}
+		case "fail":
+			result = &CallToolResult{Content: []*Content{{Type: "text", Text: "boom"}}, IsError: true}
+		default:
+			resp.Error = &RPCError{Code: -32602, Message: "unknown tool " + params.Name}
+		}
+	default:
+		resp.Error = &RPCError{Code: codeMethodNotFound, Message: "method not found"}
+	}
+	if result != nil {
+		resp.Result, _ = json.Marshal(result)
+	}
+	data, _ = json.Marshal(resp)
+	return data
+}
+
+func testClient(t *testing.T, transport Transport) {
+	ctx := context.Background()
+	cli, err := NewClient(ctx, transport, WithClientInfo("test-client", "0.1"))
+	assert.NoError(t, err)
+	assert.Equal(t, Implementation{Name: "test", Version: "1.0"}, cli.ServerInfo())
+	assert.Equal(t, "use echo to echo", cli.Instructions())
+
+	tools, err := cli.ListTools(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, tools, 2)
+	assert.Equal(t, "echo", tools[0].Name)
+	assert.Equal(t, "fail", tools[1].Name)
+
+	result, err := cli.CallTool(ctx, "echo", `{"text": "hello"}`)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", result.Content[0].Text)
+
+	_, err = cli.CallTool(ctx, "unknown", "")
+	rpcErr := &RPCError{}
+	assert.ErrorAs(t, err, &rpcErr)
+	assert.Equal(t, -32602, rpcErr.Code)
+
+	assert.NoError(t, cli.Close())
+	_, err = cli.CallTool(ctx, "echo", `{"text": "hello"}`)
+	assert.Error(t, err)
+}
+
+type failingTransport struct {
+	closed bool
+}
+
+func (f *failingTransport) Call(_ context.Context, _ string, _ any) (json.RawMessage, error) {
+	return nil, &RPCError{Code: -32603, Message: "internal error"}
+}
+
+func (f *failingTransport) Notify(_ context.Context, _ string, _ any) error {
+	return nil
+}
+
+func (f *failingTransport) Close() error {
+	f.closed = true
+	return nil
+}
+
+func TestNewClientFail(t *testing.T) {
+	transport := &failingTransport{}
+	_, err := NewClient(context.Background(), transport)
+	assert.ErrorContains(t, err, "failed to initialize mcp session")
+	assert.True(t, transport.closed)
+}
+
+func TestStreamableHTTPTransport(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		sessions []string
+		deleted  bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			mu.Lock()
+			deleted = r.Header.Get(headerSessionID) == "s1"
+			mu.Unlock()
+			return
+		}
+		mu.Lock()
+		sessions = append(sessions, r.Header.Get(headerSessionID))
+		mu.Unlock()
+
+		data, _ := io.ReadAll(r.Body)
+		resp := serveTestMessage(data)
+		w.Header().Set(headerSessionID, "s1")
+		if resp == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if strings.Contains(string(data), "tools/call") {
+			// answer by a stream, with a ping request from the server before the response
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, ": keep-alive\n\nevent: message\ndata: {\"jsonrpc\":\"2.0\",\"id\":\"srv-1\",\"method\":\"ping\"}\n\n")
+			_, _ = fmt.Fprintf(w, "data: %s\n\n", resp)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(resp)
+	}))
+	defer srv.Close()
+
+	testClient(t, NewStreamableHTTPTransport(srv.URL, nil, http.Header{"Authorization": []string{"Bearer x"}}))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, "", sessions[0])
+	assert.Equal(t, "s1", sessions[len(sessions)-1])
+	assert.True(t, deleted)
+}
+
+func TestSSETransport(t *testing.T) {
+	messages := make(chan []byte, 10)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/sse":
+			w.Header().Set("Content-Type", "text/event-stream")
+			_, _ = fmt.Fprintf(w, "event: endpoint\ndata: /messages?session=1\n\n")
+			w.(http.Flusher).Flush()
+			for {
+				select {
+				case msg := <-messages:
+					_, _ = fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+					w.(http.Flusher).Flush()
+				case <-r.Context().Done():
+					return
+				}
+			}
+		case "/messages":
+			assert.Equal(t, "1", r.URL.Query().Get("session"))
+			data, _ := io.ReadAll(r.Body)
+			if resp := serveTestMessage(data); resp != nil {
+				messages <- resp
+			}
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	transport, err := NewSSETransport(context.Background(), srv.URL+"/sse", nil, nil)
+	assert.NoError(t, err)
+	testClient(t, transport)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = NewSSETransport(ctx, srv.URL+"/unknown", nil, nil)
+	assert.Error(t, err)
+}
+
+func TestStdioTransport(t *testing.T) {
+	cmd := exec.Command(os.Args[0], "-test.run=^TestStdioServerProcess$")
+	cmd.Env = append(os.Environ(), "EINO_MCP_TEST_STDIO_SERVER=1")
+	transport, err := NewStdioTransport(cmd)
+	assert.NoError(t, err)
+	testClient(t, transport)
+}
+
+// TestStdioServerProcess is not a real test, it runs the fake server over stdio in the process started by TestStdioTransport.
+func TestStdioServerProcess(t *testing.T) {
+	if os.Getenv("EINO_MCP_TEST_STDIO_SERVER") != "1" {
+		return
+	}
+	r := bufio.NewReader(os.Stdin)
+	for {
+		line, err := r.ReadBytes('\n')
+		if resp := serveTestMessage(line); resp != nil {
+			_, _ = os.Stdout.Write(append(resp, '\n'))
+		}
+		if err != nil {
+			os.Exit(0)
+		}
+	}
+}
+
+func TestReadSSE(t *testing.T) {
+	var events []string
+	err := readSSE(strings.NewReader("event: a\ndata: 1\ndata: 2\n\n: comment\r\ndata:3\r\n\r\ndata: 4"), func(event, data string) bool {
+		events = append(events, event+"="+data)
+		return true
+	})
+	assert.NoError(t, err)
+	// the last event is dropped since it's not terminated by a blank line
+	assert.Equal(t, []string{"a=1\n2", "message=3"}, events)
+}
@@ -0,0 +1,328 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+// CallInput is the input of a tool call passing through the middlewares.
+type CallInput struct {
+	// Name is the name of the tool.
+	Name string
+	// Arguments are the arguments of the call in JSON format, a middleware can modify them before calling the next endpoint.
+	Arguments string
+	// Options are the call options of the tool.
+	Options []Option
+}
+
+// InvokableEndpoint runs an invokable tool call, it's the target wrapped by the middlewares.
+type InvokableEndpoint func(ctx context.Context, input *CallInput) (string, error)
+
+// StreamableEndpoint runs a streamable tool call, it's the target wrapped by the middlewares.
+type StreamableEndpoint func(ctx context.Context, input *CallInput) (*schema.StreamReader[string], error)
+
+// Middleware wraps the runs of tools to handle cross-cutting concerns uniformly,
+// e.g. checking or rewriting arguments, enforcing timeouts, recording metrics and post-processing results.
+// Invokable wraps InvokableRun, and Streamable wraps StreamableRun, either can be nil if the middleware doesn't care about it.
+// Apply it to a tool with WrapWithMiddleware, or to all the tools of a ToolsNode with compose.ToolMiddlewareFrom.
+type Middleware struct {
+	Invokable  func(next InvokableEndpoint) InvokableEndpoint
+	Streamable func(next StreamableEndpoint) StreamableEndpoint
+}
+
+// WrapWithMiddleware wraps the tool with the middlewares, the first middleware is the outermost one.
+// The wrapped tool has the same info, and implements InvokableTool and StreamableTool the same as t.
+// Tools implementing neither, e.g. StreamingInputTools, are returned as is.
+// e.g.
+//
+//	t = tool.WrapWithMiddleware(t, tool.NewTimeoutMiddleware(10*time.Second), tool.NewObserveMiddleware(recordMetrics))
+func WrapWithMiddleware(t BaseTool, mws ...Middleware) BaseTool {
+	if len(mws) == 0 {
+		return t
+	}
+
+	it, isInvokable := t.(InvokableTool)
+	st, isStreamable := t.(StreamableTool)
+
+	var (
+		invokable  *wrappedInvokableTool
+		streamable *wrappedStreamableTool
+	)
+	if isInvokable {
+		endpoint := InvokableEndpoint(func(ctx context.Context, input *CallInput) (string, error) {
+			return it.InvokableRun(ctx, input.Arguments, input.Options...)
+		})
+		for i := len(mws) - 1; i >= 0; i-- {
+			if mws[i].Invokable != nil {
+				endpoint = mws[i].Invokable(endpoint)
+			}
+		}
+		invokable = &wrappedInvokableTool{BaseTool: t, endpoint: endpoint}
+	}
+	if isStreamable {
+		endpoint := StreamableEndpoint(func(ctx context.Context, input *CallInput) (*schema.StreamReader[string], error) {
+			return st.StreamableRun(ctx, input.Arguments, input.Options...)
+		})
+		for i := len(mws) - 1; i >= 0; i-- {
+			if mws[i].Streamable != nil {
+				endpoint = mws[i].Streamable(endpoint)
+			}
+		}
+		streamable = &wrappedStreamableTool{BaseTool: t, endpoint: endpoint}
+	}
+
+	switch {
+	case isInvokable && isStreamable:
+		return &wrappedEnhancedTool{wrappedInvokableTool: invokable, wrappedStreamableTool: streamable}
+	case isInvokable:
+		return invokable
+	case isStreamable:
+		return streamable
+	default:
+		return t
+	}
+}
+
+type wrappedInvokableTool struct {
+	BaseTool
+	endpoint InvokableEndpoint
+}
+
+func (w *wrappedInvokableTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...Option) (string, error) {
+	input, err := newCallInput(ctx, w.BaseTool, argumentsInJSON, opts)
+	if err != nil {
+		return "", err
+	}
+	return w.endpoint(ctx, input)
+}
+
+type wrappedStreamableTool struct {
+	BaseTool
+	endpoint StreamableEndpoint
+}
+
+func (w *wrappedStreamableTool) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...Option) (*schema.StreamReader[string], error) {
+	input, err := newCallInput(ctx, w.BaseTool, argumentsInJSON, opts)
+	if err != nil {
+		return nil, err
+	}
+	return w.endpoint(ctx, input)
+}
+
+type wrappedEnhancedTool struct {
+	*wrappedInvokableTool
+	*wrappedStreamableTool
+}
+
+func (w *wrappedEnhancedTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return w.wrappedInvokableTool.Info(ctx)
+}
+
+func newCallInput(ctx context.Context, t BaseTool, argumentsInJSON string, opts []Option) (*CallInput, error) {
+	info, err := t.Info(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &CallInput{Name: info.Name, Arguments: argumentsInJSON, Options: opts}, nil
+}
+
+// ErrTimeout is returned by the tool calls exceeding the timeout set by NewTimeoutMiddleware.
+var ErrTimeout = errors.New("tool call timeout")
+
+// NewTimeoutMiddleware bounds the time of a tool call, including reading the whole output stream of streamable tools.
+// The context passed to the tool is canceled when the time is up, and ErrTimeout is returned,
+// even if the tool ignores the context, in which case it keeps running in the background until it returns.
+func NewTimeoutMiddleware(timeout time.Duration) Middleware {
+	return Middleware{
+		Invokable: func(next InvokableEndpoint) InvokableEndpoint {
+			return func(ctx context.Context, input *CallInput) (string, error) {
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+				defer cancel()
+
+				type result struct {
+					output string
+					err    error
+				}
+				done := make(chan result, 1)
+				go func() {
+					defer func() {
+						if panicErr := recover(); panicErr != nil {
+							done <- result{err: safe.NewPanicErr(panicErr, debug.Stack())}
+						}
+					}()
+					output, err := next(ctx, input)
+					done <- result{output: output, err: err}
+				}()
+
+				select {
+				case r := <-done:
+					return r.output, r.err
+				case <-ctx.Done():
+					return "", timeoutErr(ctx, input.Name, timeout)
+				}
+			}
+		},
+		Streamable: func(next StreamableEndpoint) StreamableEndpoint {
+			return func(ctx context.Context, input *CallInput) (*schema.StreamReader[string], error) {
+				ctx, cancel := context.WithTimeout(ctx, timeout)
+				sr, err := next(ctx, input)
+				if err != nil {
+					cancel()
+					if ctx.Err() != nil {
+						return nil, timeoutErr(ctx, input.Name, timeout)
+					}
+					return nil, err
+				}
+
+				out, sw := schema.Pipe[string](0)
+				chunks := make(chan streamChunk)
+				go func() {
+					defer func() {
+						close(chunks)
+						sr.Close()
+					}()
+					for {
+						chunk, err := sr.Recv()
+						select {
+						case chunks <- streamChunk{chunk: chunk, err: err}:
+						case <-ctx.Done():
+							return
+						}
+						if err != nil {
+							return
+						}
+					}
+				}()
+				go func() {
+					defer func() {
+						sw.Close()
+						cancel()
+					}()
+					for {
+						select {
+						case c, ok := <-chunks:
+							if !ok {
+								// the reading is stopped by the timeout
+								sw.Send("", timeoutErr(ctx, input.Name, timeout))
+								return
+							}
+							if c.err == io.EOF {
+								return
+							}
+							if closed := sw.Send(c.chunk, c.err); closed || c.err != nil {
+								return
+							}
+						case <-ctx.Done():
+							sw.Send("", timeoutErr(ctx, input.Name, timeout))
+							return
+						}
+					}
+				}()
+				return out, nil
+			}
+		},
+	}
+}
+
+type streamChunk struct {
+	chunk string
+	err   error
+}
+
+func timeoutErr(ctx context.Context, name string, timeout time.Duration) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ctx.Err()
+	}
+	return fmt.Errorf("%w: tool[%s] didn't finish in %s", ErrTimeout, name, timeout)
+}
+
+// CallRecord describes a finished tool call, see NewObserveMiddleware.
+type CallRecord struct {
+	Name      string
+	Arguments string
+	// Result is the output of the call, the chunks are concatenated for streamable tools.
+	Result string
+	// Err is the error of the call, including the error in the output stream.
+	Err      error
+	Start    time.Time
+	Duration time.Duration
+}
+
+// NewObserveMiddleware calls observe after each tool call finishes, e.g. to write logs or record metrics.
+// For streamable tools, it's called when the output stream ends, in the goroutine reading the stream.
+// e.g.
+//
+//	tool.NewObserveMiddleware(func(ctx context.Context, rec *tool.CallRecord) {
+//		log.Printf("tool %s took %s, err=%v", rec.Name, rec.Duration, rec.Err)
+//	})
+func NewObserveMiddleware(observe func(ctx context.Context, rec *CallRecord)) Middleware {
+	return Middleware{
+		Invokable: func(next InvokableEndpoint) InvokableEndpoint {
+			return func(ctx context.Context, input *CallInput) (string, error) {
+				rec := &CallRecord{Name: input.Name, Arguments: input.Arguments, Start: time.Now()}
+				output, err := next(ctx, input)
+				rec.Result, rec.Err, rec.Duration = output, err, time.Since(rec.Start)
+				observe(ctx, rec)
+				return output, err
+			}
+		},
+		Streamable: func(next StreamableEndpoint) StreamableEndpoint {
+			return func(ctx context.Context, input *CallInput) (*schema.StreamReader[string], error) {
+				rec := &CallRecord{Name: input.Name, Arguments: input.Arguments, Start: time.Now()}
+				sr, err := next(ctx, input)
+				if err != nil {
+					rec.Err, rec.Duration = err, time.Since(rec.Start)
+					observe(ctx, rec)
+					return nil, err
+				}
+
+				ss := sr.Copy(2)
+				go func() {
+					defer func() {
+						_ = recover()
+					}()
+					defer ss[1].Close()
+					var sb strings.Builder
+					for {
+						chunk, err := ss[1].Recv()
+						if err == io.EOF {
+							break
+						}
+						if err != nil {
+							rec.Err = err
+							break
+						}
+						sb.WriteString(chunk)
+					}
+					rec.Result, rec.Duration = sb.String(), time.Since(rec.Start)
+					observe(ctx, rec)
+				}()
+				return ss[0], nil
+			}
+		},
+	}
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package tool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type echoToolForTest struct {
+	delay time.Duration
+}
+
+func (e *echoToolForTest) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "echo"}, nil
+}
+
+func (e *echoToolForTest) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...Option) (string, error) {
+	time.Sleep(e.delay)
+	return argumentsInJSON, nil
+}
+
+func (e *echoToolForTest) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...Option) (*schema.StreamReader[string], error) {
+	sr, sw := schema.Pipe[string](0)
+	go func() {
+		defer sw.Close()
+		for _, c := range argumentsInJSON {
+			select {
+			case <-time.After(e.delay):
+			case <-ctx.Done():
+				sw.Send("", ctx.Err())
+				return
+			}
+			if sw.Send(string(c), nil) {
+				return
+			}
+		}
+	}()
+	return sr, nil
+}
+
+type invokableOnlyToolForTest struct {
+	InvokableTool
+}
+
+func readAll(t *testing.T, sr *schema.StreamReader[string]) (string, error) {
+	defer sr.Close()
+	var sb strings.Builder
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			return sb.String(), nil
+		}
+		if err != nil {
+			return sb.String(), err
+		}
+		sb.WriteString(chunk)
+	}
+}
+
+func TestWrapWithMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	var trace []string
+	mw := func(name string) Middleware {
+		return Middleware{
+			Invokable: func(next InvokableEndpoint) InvokableEndpoint {
+				return func(ctx context.Context, input *CallInput) (string, error) {
+					trace = append(trace, name+":"+input.Name)
+					input.Arguments += name
+					output, err := next(ctx, input)
+					return output + "|" + name, err
+				}
+			},
+		}
+	}
+
+	wrapped := WrapWithMiddleware(&echoToolForTest{}, mw("a"), mw("b"), Middleware{})
+	info, err := wrapped.Info(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "echo", info.Name)
+
+	output, err := wrapped.(InvokableTool).InvokableRun(ctx, "x")
+	assert.NoError(t, err)
+	assert.Equal(t, "xab|b|a", output)
+	assert.Equal(t, []string{"a:echo", "b:echo"}, trace)
+
+	// streamable runs are not affected by invokable middlewares
+	output, err = readAll(t, mustStream(t, wrapped, "xy"))
+	assert.NoError(t, err)
+	assert.Equal(t, "xy", output)
+
+	wrapped = WrapWithMiddleware(&invokableOnlyToolForTest{InvokableTool: &echoToolForTest{}}, mw("a"))
+	_, isStreamable := wrapped.(StreamableTool)
+	assert.False(t, isStreamable)
+}
+
+func mustStream(t *testing.T, bt BaseTool, args string) *schema.StreamReader[string] {
+	sr, err := bt.(StreamableTool).StreamableRun(context.Background(), args)
+	assert.NoError(t, err)
+	return sr
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	fast := WrapWithMiddleware(&echoToolForTest{}, NewTimeoutMiddleware(time.Second))
+	output, err := fast.(InvokableTool).InvokableRun(ctx, "abc")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", output)
+	output, err = readAll(t, mustStream(t, fast, "abc"))
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", output)
+
+	slow := WrapWithMiddleware(&echoToolForTest{delay: 200 * time.Millisecond}, NewTimeoutMiddleware(50*time.Millisecond))
+	_, err = slow.(InvokableTool).InvokableRun(ctx, "a")
+	assert.True(t, errors.Is(err, ErrTimeout))
+
+	// each chunk is fast, but the whole stream is not
+	slow = WrapWithMiddleware(&echoToolForTest{delay: 20 * time.Millisecond}, NewTimeoutMiddleware(50*time.Millisecond))
+	output, err = readAll(t, mustStream(t, slow, "abcdefghij"))
+	assert.True(t, errors.Is(err, ErrTimeout))
+	assert.True(t, len(output) < 10)
+}
+
+func TestObserveMiddleware(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu      sync.Mutex
+		records []*CallRecord
+	)
+	done := make(chan struct{}, 2)
+	wrapped := WrapWithMiddleware(&echoToolForTest{}, NewObserveMiddleware(func(ctx context.Context, rec *CallRecord) {
+		mu.Lock()
+		records = append(records, rec)
+		mu.Unlock()
+		done <- struct{}{}
+	}))
+
+	_, err := wrapped.(InvokableTool).InvokableRun(ctx, "abc")
+	assert.NoError(t, err)
+	output, err := readAll(t, mustStream(t, wrapped, "xyz"))
+	assert.NoError(t, err)
+	assert.Equal(t, "xyz", output)
+	<-done
+	<-done
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Len(t, records, 2)
+	assert.Equal(t, "echo", records[0].Name)
+	assert.Equal(t, "abc", records[0].Result)
+	assert.Equal(t, "xyz", records[1].Arguments)
+	assert.Equal(t, "xyz", records[1].Result)
+	assert.NoError(t, records[1].Err)
+}
@@ -18,7 +18,9 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
 	"reflect"
+	"strings"
 
 	"github.com/eino-contrib/jsonschema"
 )
@@ -33,6 +35,8 @@ type toolOptions struct {
 	um         UnmarshalArguments
 	m          MarshalOutput
 	scModifier SchemaModifierFn
+
+	typeSchemas map[reflect.Type]func(r *jsonschema.Reflector) *jsonschema.Schema
 }
 
 // Option is the option func for the tool.
@@ -70,6 +74,82 @@ func WithSchemaModifier(modifier SchemaModifierFn) Option {
 	}
 }
 
+// WithOneOf registers the implementations of the interface I for inferring tool parameter from go struct,
+// so that the fields of type I are inferred as a oneOf of the schemas of the implementations, instead of accepting any value.
+// e.g.
+//
+//	type Shape interface{ Area() float64 }
+//	t, err := utils.InferTool("draw", "draw a shape", draw, utils.WithOneOf[Shape](&Circle{}, &Rect{}))
+//
+// Note that the arguments can't be unmarshalled into a field of interface type without knowing the implementation,
+// so the struct containing the field should implement json.Unmarshaler, or use WithUnmarshalArguments to do so.
+func WithOneOf[I any](impls ...I) Option {
+	return func(o *toolOptions) {
+		o.setTypeSchema(reflect.TypeOf((*I)(nil)).Elem(), func(r *jsonschema.Reflector) *jsonschema.Schema {
+			s := &jsonschema.Schema{}
+			for _, impl := range impls {
+				implSchema := r.Reflect(impl)
+				implSchema.Version = ""
+				s.OneOf = append(s.OneOf, implSchema)
+			}
+			return s
+		})
+	}
+}
+
+// WithEnum registers the values of the type E for inferring tool parameter from go struct,
+// so that the fields of type E are inferred with the enum of the values, without repeating the enum tags on each field.
+// E should be a named type of string, integer, number or boolean.
+// e.g.
+//
+//	type Unit string
+//	t, err := utils.InferTool("weather", "get the weather", getWeather, utils.WithEnum(Celsius, Fahrenheit))
+func WithEnum[E any](values ...E) Option {
+	return func(o *toolOptions) {
+		t := reflect.TypeOf((*E)(nil)).Elem()
+		o.setTypeSchema(t, func(r *jsonschema.Reflector) *jsonschema.Schema {
+			s := &jsonschema.Schema{}
+			switch t.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				s.Type = "integer"
+			case reflect.Float32, reflect.Float64:
+				s.Type = "number"
+			case reflect.Bool:
+				s.Type = "boolean"
+			default:
+				s.Type = "string"
+			}
+			for _, v := range values {
+				s.Enum = append(s.Enum, enumValue(v))
+			}
+			return s
+		})
+	}
+}
+
+// enumValue converts the value to the type decoded from json with UseNumber, the same as the values of the enum tags.
+func enumValue(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	d := json.NewDecoder(strings.NewReader(string(b)))
+	d.UseNumber()
+	var ret any
+	if err = d.Decode(&ret); err != nil {
+		return v
+	}
+	return ret
+}
+
+func (o *toolOptions) setTypeSchema(t reflect.Type, fn func(r *jsonschema.Reflector) *jsonschema.Schema) {
+	if o.typeSchemas == nil {
+		o.typeSchemas = make(map[reflect.Type]func(r *jsonschema.Reflector) *jsonschema.Schema)
+	}
+	o.typeSchemas[t] = fn
+}
+
 func getToolOptions(opt ...Option) *toolOptions {
 	opts := &toolOptions{
 		um: nil,
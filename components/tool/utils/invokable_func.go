@@ -19,6 +19,7 @@ package utils
 import (
 	"context"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/bytedance/sonic"
@@ -88,6 +89,15 @@ func goStruct2ParamsOneOf[T any](opts ...Option) (*schema.ParamsOneOf, error) {
 		DoNotReference: true,
 		SchemaModifier: jsonschema.SchemaModifierFn(options.scModifier),
 	}
+	if len(options.typeSchemas) > 0 {
+		r.Mapper = func(t reflect.Type) *jsonschema.Schema {
+			if fn, ok := options.typeSchemas[t]; ok {
+				// a new schema for each field, as the tags of the field are applied to it
+				return fn(r)
+			}
+			return nil
+		}
+	}
 
 	js := r.Reflect(generic.NewInstance[T]())
 	js.Version = ""
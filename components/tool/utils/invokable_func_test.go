@@ -403,3 +403,78 @@ func TestEnumTag(t *testing.T) {
 	_, err = goStruct2ParamsOneOf[testEnumStruct3]()
 	assert.NoError(t, err)
 }
+
+type testShape interface {
+	Area() float64
+}
+
+type testCircle struct {
+	Radius float64 `json:"radius" jsonschema:"description=the radius of the circle"`
+}
+
+func (c *testCircle) Area() float64 { return 3.14 * c.Radius * c.Radius }
+
+type testRect struct {
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+func (r testRect) Area() float64 { return r.Width * r.Height }
+
+type testUnit string
+
+type testLevel int
+
+type testDrawInput struct {
+	Shape  testShape   `json:"shape" jsonschema:"description=the shape to draw"`
+	Shapes []testShape `json:"shapes,omitempty"`
+	Unit   testUnit    `json:"unit" jsonschema:"description=the unit of the size"`
+	Level  testLevel   `json:"level,omitempty"`
+}
+
+func TestOneOfAndEnumOptions(t *testing.T) {
+	info, err := goStruct2ParamsOneOf[testDrawInput](
+		WithOneOf[testShape](&testCircle{}, testRect{}),
+		WithEnum[testUnit]("cm", "inch"),
+		WithEnum[testLevel](1, 2, 3),
+	)
+	assert.NoError(t, err)
+	s, err := info.ToJSONSchema()
+	assert.NoError(t, err)
+
+	shape, ok := s.Properties.Get("shape")
+	assert.True(t, ok)
+	assert.Equal(t, "the shape to draw", shape.Description)
+	assert.Len(t, shape.OneOf, 2)
+	radius, ok := shape.OneOf[0].Properties.Get("radius")
+	assert.True(t, ok)
+	assert.Equal(t, "the radius of the circle", radius.Description)
+	assert.Equal(t, []string{"width", "height"}, shape.OneOf[1].Required)
+	assert.Empty(t, shape.OneOf[0].Version)
+
+	shapes, ok := s.Properties.Get("shapes")
+	assert.True(t, ok)
+	assert.Equal(t, "array", shapes.Type)
+	assert.Len(t, shapes.Items.OneOf, 2)
+
+	unit, ok := s.Properties.Get("unit")
+	assert.True(t, ok)
+	assert.Equal(t, "string", unit.Type)
+	assert.Equal(t, "the unit of the size", unit.Description)
+	assert.Equal(t, []any{"cm", "inch"}, unit.Enum)
+
+	level, ok := s.Properties.Get("level")
+	assert.True(t, ok)
+	assert.Equal(t, "integer", level.Type)
+	assert.Equal(t, []any{json.Number("1"), json.Number("2"), json.Number("3")}, level.Enum)
+
+	// without the options, the interface accepts any value and the enum isn't inferred
+	info, err = goStruct2ParamsOneOf[testDrawInput]()
+	assert.NoError(t, err)
+	s, err = info.ToJSONSchema()
+	assert.NoError(t, err)
+	shape, _ = s.Properties.Get("shape")
+	assert.Empty(t, shape.OneOf)
+	unit, _ = s.Properties.Get("unit")
+	assert.Empty(t, unit.Enum)
+}
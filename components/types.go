@@ -56,6 +56,7 @@ const (
 	ComponentOfEmbedding   Component = "Embedding"
 	ComponentOfIndexer     Component = "Indexer"
 	ComponentOfRetriever   Component = "Retriever"
+	ComponentOfReranker    Component = "Reranker"
 	ComponentOfLoader      Component = "Loader"
 	ComponentOfTransformer Component = "DocumentTransformer"
 	ComponentOfTool        Component = "Tool"
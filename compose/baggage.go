@@ -0,0 +1,96 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+)
+
+type baggageKey struct{}
+
+// WithBaggage attaches request-scoped metadata (e.g. tenant id, trace id, feature flags) to a graph run.
+// The baggage is visible to every node of the graph, including nodes of nested graphs, through GetBaggage.
+// Baggage is persisted together with the checkpoint when the graph is interrupted, and restored when the graph is resumed.
+// Baggage passed on resume is merged over the restored one, so the same key can be overridden.
+// e.g.
+//
+//	runnable.Invoke(ctx, "input", compose.WithBaggage(map[string]string{"tenant_id": "t1"}))
+func WithBaggage(baggage map[string]string) Option {
+	return Option{
+		baggage: baggage,
+	}
+}
+
+// GetBaggage returns the baggage value of the key set by WithBaggage.
+// e.g.
+//
+//	lambda := compose.InvokableLambda(func(ctx context.Context, in string) (string, error) {
+//		tenantID, ok := compose.GetBaggage(ctx, "tenant_id")
+//		...
+//	})
+func GetBaggage(ctx context.Context, key string) (string, bool) {
+	b, ok := ctx.Value(baggageKey{}).(map[string]string)
+	if !ok {
+		return "", false
+	}
+	v, ok := b[key]
+	return v, ok
+}
+
+// GetAllBaggage returns a copy of all baggage set by WithBaggage.
+func GetAllBaggage(ctx context.Context) map[string]string {
+	b, ok := ctx.Value(baggageKey{}).(map[string]string)
+	if !ok {
+		return nil
+	}
+	ret := make(map[string]string, len(b))
+	for k, v := range b {
+		ret[k] = v
+	}
+	return ret
+}
+
+// setBaggage merges the baggage into the one already existing in ctx.
+func setBaggage(ctx context.Context, baggage map[string]string) context.Context {
+	if len(baggage) == 0 {
+		return ctx
+	}
+	nb := GetAllBaggage(ctx)
+	if nb == nil {
+		nb = make(map[string]string, len(baggage))
+	}
+	for k, v := range baggage {
+		nb[k] = v
+	}
+	return context.WithValue(ctx, baggageKey{}, nb)
+}
+
+func getBaggageFromOptions(opts ...Option) map[string]string {
+	var ret map[string]string
+	for _, opt := range opts {
+		if len(opt.baggage) == 0 {
+			continue
+		}
+		if ret == nil {
+			ret = make(map[string]string, len(opt.baggage))
+		}
+		for k, v := range opt.baggage {
+			ret[k] = v
+		}
+	}
+	return ret
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaggage(t *testing.T) {
+	ctx := context.Background()
+
+	readTenant := InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		v, _ := GetBaggage(ctx, "tenant")
+		return input + "-" + v, nil
+	})
+
+	sub := NewGraph[string, string]()
+	assert.NoError(t, sub.AddLambdaNode("sub", readTenant))
+	assert.NoError(t, sub.AddEdge(START, "sub"))
+	assert.NoError(t, sub.AddEdge("sub", END))
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", readTenant))
+	assert.NoError(t, g.AddGraphNode("2", sub))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", "2"))
+	assert.NoError(t, g.AddEdge("2", END))
+
+	store := newInMemoryStore()
+	r, err := g.Compile(ctx, WithCheckPointStore(store), WithInterruptBeforeNodes([]string{"2"}))
+	assert.NoError(t, err)
+
+	// baggage is visible in nested graph and in stream mode
+	_, err = r.Invoke(ctx, "in", WithBaggage(map[string]string{"tenant": "t1"}), WithCheckPointID("1"))
+	_, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+
+	// baggage is restored from checkpoint
+	out, err := r.Invoke(ctx, "in", WithCheckPointID("1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "in-t1-t1", out)
+
+	// baggage on resume overrides the restored one
+	_, err = r.Invoke(ctx, "in", WithBaggage(map[string]string{"tenant": "t1"}), WithCheckPointID("2"))
+	_, ok = ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	sr, err := r.Stream(ctx, "in", WithCheckPointID("2"), WithBaggage(map[string]string{"tenant": "t2"}))
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "in-t1-t2", out)
+
+	_, ok = GetBaggage(ctx, "tenant")
+	assert.False(t, ok)
+	assert.Nil(t, GetAllBaggage(ctx))
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/internal/gmap"
@@ -262,6 +263,18 @@ func (c *Chain[I, O]) AppendRetriever(node retriever.Retriever, opts ...GraphAdd
 	return c
 }
 
+// AppendReranker add a Reranker node to the chain, the input of the node is *reranker.Input.
+// e.g.
+//
+//	reranker, err := cohere.NewReranker(ctx, config)
+//	if err != nil {...}
+//	chain.AppendReranker(reranker)
+func (c *Chain[I, O]) AppendReranker(node reranker.Reranker, opts ...GraphAddNodeOpt) *Chain[I, O] {
+	gNode, options := toRerankerNode(node, opts...)
+	c.addNode(gNode, options)
+	return c
+}
+
 // AppendLoader adds a Loader node to the chain.
 // e.g.
 //
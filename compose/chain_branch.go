@@ -25,6 +25,7 @@ import (
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/schema"
@@ -216,6 +217,17 @@ func (cb *ChainBranch) AddRetriever(key string, node retriever.Retriever, opts .
 	return cb.addNode(key, gNode, options)
 }
 
+// AddReranker adds a Reranker node to the branch, the input of the node is *reranker.Input.
+// eg.
+//
+//	reranker, err := cohere.NewReranker(ctx, &cohere.RerankerConfig{})
+//
+//	cb.AddReranker("reranker_node_key", reranker)
+func (cb *ChainBranch) AddReranker(key string, node reranker.Reranker, opts ...GraphAddNodeOpt) *ChainBranch {
+	gNode, options := toRerankerNode(node, opts...)
+	return cb.addNode(key, gNode, options)
+}
+
 // AddLoader adds a Loader node to the branch.
 // eg.
 //
@@ -24,6 +24,7 @@ import (
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
 	"github.com/cloudwego/eino/components/retriever"
 )
 
@@ -134,6 +135,17 @@ func (p *Parallel) AddRetriever(outputKey string, node retriever.Retriever, opts
 	return p.addNode(outputKey, gNode, options)
 }
 
+// AddReranker adds a reranker node to the parallel, the input of the node is *reranker.Input.
+// eg.
+//
+//	reranker, err := cohere.NewReranker(ctx, &cohere.RerankerConfig{})
+//
+//	p.AddReranker("output_key01", reranker)
+func (p *Parallel) AddReranker(outputKey string, node reranker.Reranker, opts ...GraphAddNodeOpt) *Parallel {
+	gNode, options := toRerankerNode(node, append(opts, WithOutputKey(outputKey))...)
+	return p.addNode(outputKey, gNode, options)
+}
+
 // AddLoader adds a loader node to the parallel.
 // eg.
 //
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 
+	icb "github.com/cloudwego/eino/internal/callbacks"
 	"github.com/cloudwego/eino/internal/serialization"
 	"github.com/cloudwego/eino/schema"
 )
@@ -126,9 +127,13 @@ type checkpoint struct {
 	ToolsNodeExecutedTools map[string] /*tool node key*/ map[string] /*tool call id*/ string
 
 	SubGraphs map[string]*checkpoint
+
+	Baggage map[string]string
+
+	RemoteTaskCounts map[string]int
 }
 
-type nodePathKey struct{}
+type nodePathKey = icb.CtxNodePathKey
 type stateModifierKey struct{}
 type checkPointKey struct{} // *checkpoint
 
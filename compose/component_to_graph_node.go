@@ -17,13 +17,17 @@
 package compose
 
 import (
+	"context"
+
 	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
 	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
 )
 
 func toComponentNode[I, O, TOption any](
@@ -68,6 +72,19 @@ func toRetrieverNode(node retriever.Retriever, opts ...GraphAddNodeOpt) (*graphN
 		opts...)
 }
 
+func toRerankerNode(node reranker.Reranker, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+	return toComponentNode(
+		node,
+		components.ComponentOfReranker,
+		func(ctx context.Context, input *reranker.Input, opts ...reranker.Option) ([]*schema.Document, error) {
+			return node.Rerank(ctx, input.Query, input.Docs, opts...)
+		},
+		nil,
+		nil,
+		nil,
+		opts...)
+}
+
 func toLoaderNode(node document.Loader, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
 	return toComponentNode(
 		node,
@@ -124,13 +141,18 @@ func toDocumentTransformerNode(node document.Transformer, opts ...GraphAddNodeOp
 }
 
 func toToolsNode(node *ToolsNode, opts ...GraphAddNodeOpt) (*graphNode, *graphAddNodeOpts) {
+	var transform Transform[*schema.Message, []*schema.Message, ToolsNodeOption]
+	if node.canStreamArguments(node.tuple) {
+		// only take the stream input when it helps, so the other ToolsNodes keep receiving the complete message
+		transform = node.Transform
+	}
 	return toComponentNode(
 		node,
 		ComponentOfToolsNode,
 		node.Invoke,
 		node.Stream,
 		nil,
-		nil,
+		transform,
 		opts...)
 }
 
@@ -18,6 +18,7 @@ package compose
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"testing"
@@ -221,3 +222,76 @@ func TestDAG(t *testing.T) {
 		t.Fatal("cannot validate loop")
 	}
 }
+
+func TestPregelSubGraphInDAG(t *testing.T) {
+	ctx := context.Background()
+
+	// pregel sub graph with a loop: append "a" until length reaches 5
+	loop := NewGraph[string, string]()
+	if err := loop.AddLambdaNode("append", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "a", nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if err := loop.AddEdge(START, "append"); err != nil {
+		t.Fatal(err)
+	}
+	if err := loop.AddBranch("append", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+		if len(in) >= 5 {
+			return END, nil
+		}
+		return "append", nil
+	}, map[string]bool{"append": true, END: true})); err != nil {
+		t.Fatal(err)
+	}
+
+	g := NewGraph[string, string]()
+	if err := g.AddLambdaNode("pre", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "p", nil
+	})); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddGraphNode("loop", loop, WithGraphCompileOptions(WithNodeTriggerMode(AnyPredecessor))); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdge(START, "pre"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdge("pre", "loop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.AddEdge("loop", END); err != nil {
+		t.Fatal(err)
+	}
+	r, err := g.Compile(ctx, WithNodeTriggerMode(AllPredecessor))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := r.Invoke(ctx, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "xpaaa" {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	// runtime max steps designated to the pregel sub graph is allowed in dag
+	_, err = r.Invoke(ctx, "x", WithRuntimeMaxSteps(2).DesignateNode("loop"))
+	if err == nil || !errors.Is(err, ErrExceedMaxSteps) {
+		t.Fatalf("expect exceed max steps error, got: %v", err)
+	}
+	out, err = r.Invoke(ctx, "x", WithRuntimeMaxSteps(3).DesignateNode("loop"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "xpaaa" {
+		t.Fatalf("unexpected output: %s", out)
+	}
+
+	// but not to the dag itself
+	_, err = r.Invoke(ctx, "x", WithRuntimeMaxSteps(3))
+	if err == nil {
+		t.Fatal("expect error when setting max run steps in dag")
+	}
+}
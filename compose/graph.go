@@ -28,6 +28,7 @@ import (
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/internal/gmap"
@@ -317,6 +318,17 @@ func (g *graph) AddRetrieverNode(key string, node retriever.Retriever, opts ...G
 	return g.addNode(key, gNode, options)
 }
 
+// AddRerankerNode adds a node that implements reranker.Reranker, the input of the node is *reranker.Input.
+// e.g.
+//
+//	reranker, err := cohere.NewReranker(ctx, &cohere.RerankerConfig{})
+//
+//	graph.AddRerankerNode("reranker_node_key", reranker)
+func (g *graph) AddRerankerNode(key string, node reranker.Reranker, opts ...GraphAddNodeOpt) error {
+	gNode, options := toRerankerNode(node, opts...)
+	return g.addNode(key, gNode, options)
+}
+
 // AddLoaderNode adds a node that implements document.Loader.
 // e.g.
 //
@@ -677,6 +689,12 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		}
 	}
 
+	if opt != nil && opt.diagnosticsAsErrors {
+		if diagnostics := g.Diagnostics(); len(diagnostics) > 0 {
+			return nil, newDiagnosticsError(diagnostics)
+		}
+	}
+
 	for key := range g.fieldMappingRecords {
 		// not allowed to map multiple fields to the same field
 		toMap := make(map[string]bool)
@@ -721,6 +739,14 @@ func (g *graph) compile(ctx context.Context, opt *graphCompileOptions) (*composa
 		chanSubscribeTo[name] = chCall
 	}
 
+	if opt != nil {
+		for _, ref := range opt.nodeRefs {
+			if err := g.validateNodeRef(ref.nodeRef()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	dataPredecessors := make(map[string][]string)
 	controlPredecessors := make(map[string][]string)
 	for start, ends := range g.controlEdges {
@@ -915,6 +941,7 @@ func (g *graph) toGraphInfo(opt *graphCompileOptions, key2SubGraphs map[string]*
 		Name:            opt.graphName,
 		GenStateFn:      g.stateGenerator,
 		NewGraphOptions: g.newOpts,
+		Diagnostics:     g.Diagnostics(),
 	}
 
 	for key := range g.nodes {
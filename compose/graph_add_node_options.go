@@ -44,6 +44,12 @@ type nodeOptions struct {
 	outputKey string
 
 	graphCompileOption []GraphCompileOption // when this node is itself an AnyGraph, this option will be used to compile the node as a nested graph
+
+	limiter          NodeLimiter
+	resourceLimiters []NodeLimiter
+	remote           *remoteExecution
+
+	labels []string
 }
 
 // WithNodeName sets the name of the node.
@@ -53,6 +59,17 @@ func WithNodeName(n string) GraphAddNodeOpt {
 	}
 }
 
+// WithNodeLabels sets the labels of the node, which are inherited by the nodes of the node if it's a graph,
+// to select the nodes of the handlers scoped by callbacks.Scope.Labels.
+// e.g.
+//
+//	graph.AddRetrieverNode("retrieve", retriever, compose.WithNodeLabels("rag"))
+func WithNodeLabels(labels ...string) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.labels = append(o.nodeOptions.labels, labels...)
+	}
+}
+
 // WithNodeKey set the node key, which is used to identify the node in the chain.
 // only for use in Chain/StateChain.
 func WithNodeKey(key string) GraphAddNodeOpt {
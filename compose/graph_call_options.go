@@ -28,6 +28,7 @@ import (
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
 	"github.com/cloudwego/eino/components/retriever"
 )
 
@@ -81,6 +82,9 @@ type Option struct {
 	options []any
 	handler []callbacks.Handler
 
+	suppressedGlobalHandlers []string
+	replacedGlobalHandlers   map[string]callbacks.Handler
+
 	paths []*NodePath
 
 	maxRunSteps         int
@@ -88,6 +92,15 @@ type Option struct {
 	writeToCheckPointID *string
 	forceNewRun         bool
 	stateModifier       StateModifier
+
+	baggage map[string]string
+
+	runEventEmitter  *runEventEmitter
+	runBudgetTracker *runBudgetTracker
+	runRecording     *runRecording
+	runReplay        *runReplay
+	traceReplay      *traceReplay
+	runStore         *runStoreOption
 }
 
 func (o Option) deepCopy() Option {
@@ -110,6 +123,7 @@ func (o Option) deepCopy() Option {
 
 // DesignateNode sets the key of the node to which the option will be applied.
 // notice: only effective at the top graph.
+// To check the option type against the node at compile time, use DesignateOptions with a NodeRef instead.
 // e.g.
 //
 // embeddingOption := compose.WithEmbeddingOption(embedding.WithModel("text-embedding-3-small"))
@@ -151,6 +165,15 @@ func WithRetrieverOption(opts ...retriever.Option) Option {
 	return withComponentOption(opts...)
 }
 
+// WithRerankerOption is a functional option type for reranker component.
+// e.g.
+//
+//	rerankerOption := compose.WithRerankerOption(reranker.WithTopN(3))
+//	runnable.Invoke(ctx, "input", rerankerOption)
+func WithRerankerOption(opts ...reranker.Option) Option {
+	return withComponentOption(opts...)
+}
+
 // WithLoaderOption is a functional option type for loader component.
 // e.g.
 //
@@ -211,10 +234,35 @@ func WithCallbacks(cbs ...callbacks.Handler) Option {
 	}
 }
 
+// WithGlobalCallbacksSuppressed suppresses the global handlers registered by callbacks.RegisterGlobalHandler with the names in the run,
+// except the mandatory ones. It takes effect on the whole run, DesignateNode doesn't apply.
+// e.g.
+//
+//	runnable.Invoke(ctx, input, compose.WithGlobalCallbacksSuppressed("metrics"))
+func WithGlobalCallbacksSuppressed(names ...string) Option {
+	return Option{
+		suppressedGlobalHandlers: names,
+	}
+}
+
+// WithGlobalCallbackReplaced runs handler in place of the global handler registered by callbacks.RegisterGlobalHandler with the name in the run,
+// unless it's mandatory. It takes effect on the whole run, DesignateNode doesn't apply.
+// e.g.
+//
+//	runnable.Invoke(ctx, input, compose.WithGlobalCallbackReplaced("tracing", sampledTracingHandler))
+func WithGlobalCallbackReplaced(name string, handler callbacks.Handler) Option {
+	return Option{
+		replacedGlobalHandlers: map[string]callbacks.Handler{name: handler},
+	}
+}
+
 // WithRuntimeMaxSteps sets the maximum number of steps for the graph runtime.
+// It only takes effect on graphs running in pregel mode (AnyPredecessor).
+// To set the max steps of a pregel sub graph, e.g. an agent loop nested in a DAG graph, designate the option to the sub graph node.
 // e.g.
 //
 //	runnable.Invoke(ctx, "input", compose.WithRuntimeMaxSteps(20))
+//	runnable.Invoke(ctx, "input", compose.WithRuntimeMaxSteps(20).DesignateNode("agent_loop_sub_graph"))
 func WithRuntimeMaxSteps(maxSteps int) Option {
 	return Option{
 		maxRunSteps: maxSteps,
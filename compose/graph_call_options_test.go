@@ -24,6 +24,7 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/embedding"
 	"github.com/cloudwego/eino/components/model"
@@ -494,3 +495,85 @@ func TestCallOptionInSubGraph(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, result, "input grandparent-1 parent-1 child1-1 child2-1")
 }
+
+func TestScopedCallbacks(t *testing.T) {
+	ctx := context.Background()
+	appendLambda := func(g *Graph[string, string], key, suffix string, opts ...GraphAddNodeOpt) {
+		assert.NoError(t, g.AddLambdaNode(key, InvokableLambda(func(ctx context.Context, input string) (string, error) {
+			return input + suffix, nil
+		}), opts...))
+	}
+
+	rag := NewGraph[string, string]()
+	appendLambda(rag, "retrieve", "r", WithNodeName("retrieve"))
+	appendLambda(rag, "rerank", "k", WithNodeName("rerank"), WithNodeLabels("rerank"))
+	assert.NoError(t, rag.AddEdge(START, "retrieve"))
+	assert.NoError(t, rag.AddEdge("retrieve", "rerank"))
+	assert.NoError(t, rag.AddEdge("rerank", END))
+
+	g := NewGraph[string, string]()
+	appendLambda(g, "prepare", "p", WithNodeName("prepare"))
+	assert.NoError(t, g.AddGraphNode("rag", rag, WithNodeName("rag"), WithNodeLabels("knowledge")))
+	assert.NoError(t, g.AddEdge(START, "prepare"))
+	assert.NoError(t, g.AddEdge("prepare", "rag"))
+	assert.NoError(t, g.AddEdge("rag", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	record := func(names *[]string) callbacks.Handler {
+		return callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			*names = append(*names, info.Name)
+			return ctx
+		}).Build()
+	}
+	var inRAG, labeled, reranks, lambdas []string
+	out, err := r.Invoke(ctx, "", WithCallbacks(
+		callbacks.NewScopedHandler(record(&inRAG), callbacks.Scope{Paths: []string{"rag"}}),
+		callbacks.NewScopedHandler(record(&labeled), callbacks.Scope{Labels: []string{"knowledge"}}),
+		callbacks.NewScopedHandler(record(&reranks), callbacks.Scope{Labels: []string{"rerank"}, Paths: []string{"rag/*"}}),
+		callbacks.NewScopedHandler(record(&lambdas), callbacks.Scope{Components: []components.Component{ComponentOfLambda}, Names: []string{"re*"}}),
+	))
+	assert.NoError(t, err)
+	assert.Equal(t, "prk", out)
+	assert.Equal(t, []string{"rag", "retrieve", "rerank"}, inRAG)
+	assert.Equal(t, []string{"rag", "retrieve", "rerank"}, labeled)
+	assert.Equal(t, []string{"rerank"}, reranks)
+	assert.Equal(t, []string{"retrieve", "rerank"}, lambdas)
+}
+
+func TestGlobalCallbacksOverrides(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("echo", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input, nil
+	}), WithNodeName("echo")))
+	assert.NoError(t, g.AddEdge(START, "echo"))
+	assert.NoError(t, g.AddEdge("echo", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	counts := map[string]int{}
+	count := func(name string) callbacks.Handler {
+		return callbacks.NewHandlerBuilder().OnStartFn(func(ctx context.Context, info *callbacks.RunInfo, input callbacks.CallbackInput) context.Context {
+			counts[name]++
+			return ctx
+		}).Build()
+	}
+	callbacks.RegisterGlobalHandler("test_metrics", count("metrics"))
+	callbacks.RegisterGlobalHandler("test_tracing", count("tracing"))
+	defer func() {
+		callbacks.UnregisterGlobalHandler("test_metrics")
+		callbacks.UnregisterGlobalHandler("test_tracing")
+	}()
+
+	_, err = r.Invoke(ctx, "hi")
+	assert.NoError(t, err)
+	// the graph and the node
+	assert.Equal(t, map[string]int{"metrics": 2, "tracing": 2}, counts)
+
+	counts = map[string]int{}
+	_, err = r.Invoke(ctx, "hi", WithGlobalCallbacksSuppressed("test_metrics"),
+		WithGlobalCallbackReplaced("test_tracing", count("sampled")))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"sampled": 2}, counts)
+}
@@ -33,6 +33,10 @@ type graphCompileOptions struct {
 	eagerDisabled bool
 
 	mergeConfigs map[string]FanInMergeConfig
+
+	diagnosticsAsErrors bool
+
+	nodeRefs []AnyNodeRef
 }
 
 func newGraphCompileOptions(opts ...GraphCompileOption) *graphCompileOptions {
@@ -94,6 +98,14 @@ func WithEagerExecutionDisabled() GraphCompileOption {
 // WithNodeTriggerMode sets the trigger mode for nodes in the graph.
 // The trigger mode determines when a node is triggered during graph execution, ref: https://www.cloudwego.io/docs/eino/core_modules/chain_and_graph_orchestration/orchestration_design_principles/#runtime-engine
 // AnyPredecessor by default.
+// The trigger mode only affects the graph being compiled, so a DAG graph (AllPredecessor) can embed a pregel sub graph (AnyPredecessor) and vice versa,
+// by passing the option to the sub graph through WithGraphCompileOptions when adding it:
+//
+//	dag.AddGraphNode("agent_loop", loopGraph, compose.WithGraphCompileOptions(compose.WithNodeTriggerMode(compose.AnyPredecessor)))
+//
+// At the boundary, the sub graph is regarded as a single node of the parent graph:
+// it is triggered according to the parent's trigger mode, and runs its own nodes according to its own trigger mode.
+// Steps of the sub graph are not counted into the max run steps of the parent graph.
 func WithNodeTriggerMode(triggerMode NodeTriggerMode) GraphCompileOption {
 	return func(o *graphCompileOptions) {
 		o.nodeTriggerMode = triggerMode
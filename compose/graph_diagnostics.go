@@ -0,0 +1,220 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// DiagnosticKind is the kind of a structural warning found in a graph.
+type DiagnosticKind string
+
+const (
+	// DiagnosticUnreachableNode means there is no path from START to the node, so it will never run.
+	DiagnosticUnreachableNode DiagnosticKind = "unreachable_node"
+	// DiagnosticDanglingOutput means there is no path from the node to END, so its output is never consumed.
+	DiagnosticDanglingOutput DiagnosticKind = "dangling_output"
+	// DiagnosticDeadEndBranch means the branch of a node reachable from START can select end nodes
+	// from which there is no path to END, so the run can never finish through them.
+	DiagnosticDeadEndBranch DiagnosticKind = "dead_end_branch"
+	// DiagnosticCollapsiblePassthrough means the passthrough node has exactly one predecessor and one successor,
+	// so it could be replaced by a direct edge.
+	DiagnosticCollapsiblePassthrough DiagnosticKind = "collapsible_passthrough"
+)
+
+// Diagnostic is a structural warning of a graph.
+// Diagnostics don't prevent a graph from compiling unless WithDiagnosticsAsErrors is set.
+type Diagnostic struct {
+	Kind DiagnosticKind
+	// Nodes are the keys of the nodes involved, e.g. the unreachable node,
+	// or the start node of the branch followed by its dead-end nodes.
+	Nodes   []string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("[%s] %s", d.Kind, d.Message)
+}
+
+// WithDiagnosticsAsErrors makes the compilation fail if any diagnostic is found in the graph,
+// which is useful to guard graph definitions in CI.
+func WithDiagnosticsAsErrors() GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.diagnosticsAsErrors = true
+	}
+}
+
+// Diagnostics returns the structural warnings of the graph, such as nodes unreachable from START,
+// nodes whose output never reaches END, branches selecting nodes which never reach END and passthrough nodes that could be collapsed.
+// The result is sorted and stable, it's also available in GraphInfo.Diagnostics when the graph is compiled.
+// e.g.
+//
+//	for _, d := range g.Diagnostics() {
+//		log.Printf("graph warning: %s", d)
+//	}
+func (g *graph) Diagnostics() []Diagnostic {
+	successors := make(map[string]map[string]bool)
+	predecessors := make(map[string]map[string]bool)
+	link := func(from, to string) {
+		if successors[from] == nil {
+			successors[from] = make(map[string]bool)
+		}
+		successors[from][to] = true
+		if predecessors[to] == nil {
+			predecessors[to] = make(map[string]bool)
+		}
+		predecessors[to][from] = true
+	}
+	for from, tos := range g.controlEdges {
+		for _, to := range tos {
+			link(from, to)
+		}
+	}
+	for from, tos := range g.dataEdges {
+		for _, to := range tos {
+			link(from, to)
+		}
+	}
+	for from, branches := range g.branches {
+		for _, branch := range branches {
+			for to := range branch.endNodes {
+				link(from, to)
+			}
+		}
+	}
+
+	fromStart := traverseNodes(START, successors)
+	toEnd := traverseNodes(END, predecessors)
+
+	var diagnostics []Diagnostic
+	for _, key := range sortedNodeKeys(g.nodes) {
+		if !fromStart[key] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Kind:    DiagnosticUnreachableNode,
+				Nodes:   []string{key},
+				Message: fmt.Sprintf("node[%s] is unreachable from START", key),
+			})
+		}
+		if !toEnd[key] {
+			diagnostics = append(diagnostics, Diagnostic{
+				Kind:    DiagnosticDanglingOutput,
+				Nodes:   []string{key},
+				Message: fmt.Sprintf("output of node[%s] never reaches END", key),
+			})
+		}
+		if deadEnds := g.deadEndBranchNodes(key, toEnd); fromStart[key] && len(deadEnds) > 0 {
+			diagnostics = append(diagnostics, Diagnostic{
+				Kind:    DiagnosticDeadEndBranch,
+				Nodes:   append([]string{key}, deadEnds...),
+				Message: fmt.Sprintf("branch(es) of node[%s] can select node(s)%v which never reach END", key, deadEnds),
+			})
+		}
+		if pre, suc, ok := g.collapsiblePassthrough(key, predecessors, successors); ok {
+			diagnostics = append(diagnostics, Diagnostic{
+				Kind:    DiagnosticCollapsiblePassthrough,
+				Nodes:   []string{key},
+				Message: fmt.Sprintf("passthrough node[%s] can be replaced by an edge from [%s] to [%s]", key, pre, suc),
+			})
+		}
+	}
+
+	return diagnostics
+}
+
+// deadEndBranchNodes returns the sorted end nodes of the branches of the node, from which END is unreachable.
+func (g *graph) deadEndBranchNodes(key string, toEnd map[string]bool) []string {
+	dead := make(map[string]bool)
+	for _, branch := range g.branches[key] {
+		for to := range branch.endNodes {
+			if !toEnd[to] {
+				dead[to] = true
+			}
+		}
+	}
+	keys := make([]string, 0, len(dead))
+	for k := range dead {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// collapsiblePassthrough reports whether the node is a passthrough node with a single predecessor and a single successor,
+// linked by plain edges, and returns the two of them.
+func (g *graph) collapsiblePassthrough(key string, predecessors, successors map[string]map[string]bool) (pre, suc string, ok bool) {
+	node := g.nodes[key]
+	if node.executorMeta.component != ComponentOfPassthrough {
+		return "", "", false
+	}
+	if len(g.branches[key]) > 0 || len(g.fieldMappingRecords[key]) > 0 {
+		return "", "", false
+	}
+	if len(predecessors[key]) != 1 || len(successors[key]) != 1 {
+		return "", "", false
+	}
+	for k := range predecessors[key] {
+		pre = k
+	}
+	for k := range successors[key] {
+		suc = k
+	}
+	for _, branch := range g.branches[pre] {
+		if branch.endNodes[key] {
+			// the passthrough is a branch target, removing it changes the branch
+			return "", "", false
+		}
+	}
+	return pre, suc, true
+}
+
+func traverseNodes(from string, next map[string]map[string]bool) map[string]bool {
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		for n := range next[cur] {
+			if !visited[n] {
+				visited[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return visited
+}
+
+func sortedNodeKeys(nodes map[string]*graphNode) []string {
+	keys := make([]string, 0, len(nodes))
+	for key := range nodes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func newDiagnosticsError(diagnostics []Diagnostic) error {
+	sb := strings.Builder{}
+	for i, d := range diagnostics {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(d.String())
+	}
+	return fmt.Errorf("graph has %d diagnostic(s): %s", len(diagnostics), sb.String())
+}
@@ -0,0 +1,102 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type diagnosticsCollector struct {
+	diagnostics []Diagnostic
+}
+
+func (d *diagnosticsCollector) OnFinish(_ context.Context, info *GraphInfo) {
+	d.diagnostics = info.Diagnostics
+}
+
+func TestGraphDiagnostics(t *testing.T) {
+	ctx := context.Background()
+	echo := InvokableLambda(func(ctx context.Context, in string) (string, error) { return in, nil })
+
+	t.Run("clean graph", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("1", echo))
+		assert.NoError(t, g.AddLambdaNode("2", echo))
+		assert.NoError(t, g.AddEdge(START, "1"))
+		assert.NoError(t, g.AddBranch("1", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+			return END, nil
+		}, map[string]bool{"2": true, END: true})))
+		assert.NoError(t, g.AddEdge("2", END))
+
+		assert.Empty(t, g.Diagnostics())
+		_, err := g.Compile(ctx, WithDiagnosticsAsErrors())
+		assert.NoError(t, err)
+	})
+
+	t.Run("problematic graph", func(t *testing.T) {
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("1", echo))
+		assert.NoError(t, g.AddPassthroughNode("p"))
+		assert.NoError(t, g.AddLambdaNode("dangling", echo))
+		assert.NoError(t, g.AddLambdaNode("orphan", echo))
+		assert.NoError(t, g.AddLambdaNode("dead", echo))
+		assert.NoError(t, g.AddEdge(START, "1"))
+		assert.NoError(t, g.AddEdge("1", "p"))
+		assert.NoError(t, g.AddEdge("p", END))
+		assert.NoError(t, g.AddEdge("1", "dangling"))
+		assert.NoError(t, g.AddBranch("dangling", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+			return "dead", nil
+		}, map[string]bool{"dead": true})))
+		assert.NoError(t, g.AddBranch("orphan", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+			return END, nil
+		}, map[string]bool{END: true})))
+
+		expected := []Diagnostic{
+			{Kind: DiagnosticDanglingOutput, Nodes: []string{"dangling"}, Message: "output of node[dangling] never reaches END"},
+			{Kind: DiagnosticDeadEndBranch, Nodes: []string{"dangling", "dead"}, Message: "branch(es) of node[dangling] can select node(s)[dead] which never reach END"},
+			{Kind: DiagnosticDanglingOutput, Nodes: []string{"dead"}, Message: "output of node[dead] never reaches END"},
+			{Kind: DiagnosticUnreachableNode, Nodes: []string{"orphan"}, Message: "node[orphan] is unreachable from START"},
+			{Kind: DiagnosticCollapsiblePassthrough, Nodes: []string{"p"}, Message: "passthrough node[p] can be replaced by an edge from [1] to [end]"},
+		}
+		assert.Equal(t, expected, g.Diagnostics())
+
+		collector := &diagnosticsCollector{}
+		_, err := g.Compile(ctx, WithGraphCompileCallbacks(collector))
+		assert.NoError(t, err)
+		assert.Equal(t, expected, collector.diagnostics)
+
+		_, err = g.Compile(ctx, WithDiagnosticsAsErrors())
+		assert.ErrorContains(t, err, "graph has 5 diagnostic(s)")
+	})
+
+	t.Run("workflow", func(t *testing.T) {
+		wf := NewWorkflow[string, string]()
+		wf.AddLambdaNode("1", echo).AddInput(START)
+		wf.AddLambdaNode("unused", echo).AddInput(START)
+		wf.End().AddInput("1")
+
+		collector := &diagnosticsCollector{}
+		_, err := wf.Compile(ctx, WithGraphCompileCallbacks(collector))
+		assert.NoError(t, err)
+		assert.Equal(t, []Diagnostic{
+			{Kind: DiagnosticDanglingOutput, Nodes: []string{"unused"}, Message: "output of node[unused] never reaches END"},
+		}, collector.diagnostics)
+	})
+}
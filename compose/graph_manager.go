@@ -278,11 +278,24 @@ func (t *taskManager) execute(currentTask *task) {
 			currentTask.err = safe.NewPanicErr(panicInfo, debug.Stack())
 		}
 
+		if currentTask.err != nil {
+			emitRunEvent(currentTask.ctx, &RunEvent{Type: RunEventNodeError, Err: currentTask.err})
+		} else {
+			emitRunEvent(currentTask.ctx, &RunEvent{Type: RunEventNodeEnd})
+		}
+
 		t.done.Send(currentTask)
 	}()
 
-	ctx := initNodeCallbacks(currentTask.ctx, currentTask.nodeKey, currentTask.call.action.nodeInfo, currentTask.call.action.meta, t.opts...)
-	currentTask.output, currentTask.err = t.runWrapper(ctx, currentTask.call.action, currentTask.input, currentTask.option...)
+	emitRunEvent(currentTask.ctx, &RunEvent{Type: RunEventNodeStart})
+
+	recordTask(currentTask, func() {
+		if replayTask(currentTask) || traceReplayTask(currentTask) {
+			return
+		}
+		ctx := initNodeCallbacks(currentTask.ctx, currentTask.nodeKey, currentTask.call.action.nodeInfo, currentTask.call.action.meta, t.opts...)
+		currentTask.output, currentTask.err = t.runWrapper(ctx, currentTask.call.action, currentTask.input, currentTask.option...)
+	})
 }
 
 func (t *taskManager) submit(tasks []*task) error {
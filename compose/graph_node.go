@@ -54,6 +54,12 @@ type nodeInfo struct {
 	preProcessor, postProcessor *composableRunnable
 
 	compileOption *graphCompileOptions // if the node is an AnyGraph, it will need compile options of its own
+
+	limiter NodeLimiter
+	remote  *remoteExecution
+
+	// labels of the node, passed from WithNodeLabels()
+	labels []string
 }
 
 // graphNode the complete information of the node in graph
@@ -137,6 +143,14 @@ func (gn *graphNode) compileIfNeeded(ctx context.Context) (*composableRunnable,
 	r.meta = gn.executorMeta
 	r.nodeInfo = gn.nodeInfo
 
+	if gn.nodeInfo.remote != nil {
+		r = remoteComposableRunnable(gn.nodeInfo.remote, r)
+	}
+
+	if gn.nodeInfo.limiter != nil {
+		r = limitedComposableRunnable(gn.nodeInfo.limiter, r)
+	}
+
 	if gn.nodeInfo.outputKey != "" {
 		r = outputKeyedComposableRunnable(gn.nodeInfo.outputKey, r)
 	}
@@ -173,5 +187,8 @@ func getNodeInfo(opts ...GraphAddNodeOpt) (*nodeInfo, *graphAddNodeOpts) {
 		preProcessor:  opt.processor.statePreHandler,
 		postProcessor: opt.processor.statePostHandler,
 		compileOption: newGraphCompileOptions(opt.nodeOptions.graphCompileOption...),
+		limiter:       nodeLimiterOf(opt.nodeOptions.limiter, opt.nodeOptions.resourceLimiters),
+		remote:        opt.nodeOptions.remote,
+		labels:        opt.nodeOptions.labels,
 	}, opt
 }
@@ -105,6 +105,32 @@ func runnableTransform(ctx context.Context, r *composableRunnable, input any, op
 }
 
 func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Option) (result any, err error) {
+	if e := getRunEventEmitterFromOptions(opts...); e != nil {
+		ctx = setRunEventEmitter(ctx, e)
+		defer e.close()
+	}
+	ctx = setRunRecordingAndReplay(ctx, opts...)
+	if rs := getRunStoreFromOptions(opts...); rs != nil {
+		checkPointID, _, _, _ := getCheckPointInfo(opts...)
+		run, sErr := rs.start(ctx, input, isStream, checkPointID)
+		if sErr != nil {
+			return nil, newGraphRunError(sErr)
+		}
+		defer func() {
+			if fErr := rs.finish(ctx, run, result, isStream, err); fErr != nil && err == nil {
+				if sr, ok := result.(streamReader); ok {
+					sr.close()
+				}
+				result, err = nil, newGraphRunError(fErr)
+			}
+		}()
+	}
+	defer func() {
+		if info, ok := ExtractInterruptInfo(err); ok {
+			emitRunEvent(ctx, &RunEvent{Type: RunEventInterrupt, InterruptInfo: info})
+		}
+	}()
+
 	haveOnStart := false // delay triggering onGraphStart until state initialization is complete, so that the state can be accessed within onGraphStart.
 	defer func() {
 		if !haveOnStart {
@@ -128,16 +154,17 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 	tm := r.initTaskManager(runWrapper, getGraphCancel(ctx), opts...)
 	maxSteps := r.options.maxRunSteps
 
+	// max run steps designated to nodes is forwarded to sub graphs, see extractOption
 	if r.dag {
 		for i := range opts {
-			if opts[i].maxRunSteps > 0 {
+			if opts[i].maxRunSteps > 0 && len(opts[i].paths) == 0 {
 				return nil, newGraphRunError(fmt.Errorf("cannot set max run steps in dag"))
 			}
 		}
 	} else {
 		// Update maxSteps if provided in options.
 		for i := range opts {
-			if opts[i].maxRunSteps > 0 {
+			if opts[i].maxRunSteps > 0 && len(opts[i].paths) == 0 {
 				maxSteps = opts[i].maxRunSteps
 			}
 		}
@@ -157,10 +184,20 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 	if checkPointID != nil && r.checkPointer.store == nil {
 		return nil, newGraphRunError(fmt.Errorf("receive checkpoint id but have not set checkpoint store"))
 	}
+	if writeToCheckPointID != nil {
+		ctx = setRemoteTaskScope(ctx, *writeToCheckPointID)
+	}
 
 	// Extract subgraph
 	path, isSubGraph := getNodeKey(ctx)
 
+	baggage := getBaggageFromOptions(opts...)
+
+	budgetTracker := getRunBudgetTrackerFromOptions(opts...)
+	if budgetTracker != nil {
+		budgetTracker.begin()
+	}
+
 	// load checkpoint from ctx/store or init graph
 	initialized := false
 	var nextTasks []*task
@@ -181,6 +218,8 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 
 			ctx = setStateModifier(ctx, stateModifier)
 			ctx = setCheckPointToCtx(ctx, cp)
+			ctx = setBaggage(setBaggage(ctx, cp.Baggage), baggage)
+			restoreRemoteTaskCounts(ctx, cp.RemoteTaskCounts)
 
 			ctx, nextTasks, err = r.restoreFromCheckPoint(ctx, *NewNodePath(), stateModifier, cp, isStream, cm, optMap)
 			ctx, input = onGraphStart(ctx, input, isStream)
@@ -189,6 +228,7 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 	}
 	if !initialized {
 		// have not inited from checkpoint
+		ctx = setBaggage(ctx, baggage)
 		if r.runCtx != nil {
 			ctx = r.runCtx(ctx)
 		}
@@ -319,6 +359,13 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 		}
 
 		tempInfo.interruptBeforeNodes = getHitKey(nextTasks, r.interruptBeforeNodes)
+		if budgetTracker != nil {
+			if usage := budgetTracker.check(); usage != nil {
+				// interrupt before all the next tasks
+				tempInfo.budgetExceeded = usage
+				tempInfo.interruptBeforeNodes = getTaskKeys(nextTasks)
+			}
+		}
 
 		if len(tempInfo.interruptBeforeNodes) > 0 || len(tempInfo.interruptAfterNodes) > 0 {
 			var newCompletedTasks []*task
@@ -355,7 +402,11 @@ func (r *runner) run(ctx context.Context, isStream bool, input any, opts ...Opti
 				return result, nil
 			}
 
-			tempInfo.interruptBeforeNodes = append(tempInfo.interruptBeforeNodes, getHitKey(newNextTasks, r.interruptBeforeNodes)...)
+			if tempInfo.budgetExceeded != nil {
+				tempInfo.interruptBeforeNodes = append(tempInfo.interruptBeforeNodes, getTaskKeys(newNextTasks)...)
+			} else {
+				tempInfo.interruptBeforeNodes = append(tempInfo.interruptBeforeNodes, getHitKey(newNextTasks, r.interruptBeforeNodes)...)
+			}
 
 			// simple interrupt
 			return nil, r.handleInterrupt(ctx, tempInfo, append(nextTasks, newNextTasks...), cm.channels, isStream, isSubGraph, writeToCheckPointID)
@@ -413,6 +464,7 @@ type interruptTempInfo struct {
 	interruptAfterNodes    []string
 	interruptRerunExtra    map[string]any
 	interruptExecutedTools map[string]map[string]string
+	budgetExceeded         *RunBudgetUsage
 }
 
 func (r *runner) resolveInterruptCompletedTasks(tempInfo *interruptTempInfo, completedTasks []*task) (err error) {
@@ -450,6 +502,14 @@ func (r *runner) resolveInterruptCompletedTasks(tempInfo *interruptTempInfo, com
 	return nil
 }
 
+func getTaskKeys(tasks []*task) []string {
+	ret := make([]string, 0, len(tasks))
+	for _, t := range tasks {
+		ret = append(ret, t.nodeKey)
+	}
+	return ret
+}
+
 func getHitKey(tasks []*task, keys []string) []string {
 	var ret []string
 	for _, t := range tasks {
@@ -475,6 +535,7 @@ func (r *runner) handleInterrupt(
 		Channels:       channels,
 		Inputs:         make(map[string]any),
 		SkipPreHandler: map[string]bool{},
+		Baggage:        GetAllBaggage(ctx),
 	}
 	if r.runCtx != nil {
 		// current graph has enable state
@@ -489,6 +550,7 @@ func (r *runner) handleInterrupt(
 		RerunNodes:      tempInfo.interruptRerunNodes,
 		RerunNodesExtra: tempInfo.interruptRerunExtra,
 		SubGraphs:       make(map[string]*InterruptInfo),
+		BudgetExceeded:  tempInfo.budgetExceeded,
 	}
 	for _, t := range nextTasks {
 		cp.Inputs[t.nodeKey] = t.input
@@ -503,6 +565,7 @@ func (r *runner) handleInterrupt(
 			CheckPoint: cp,
 		}
 	} else if checkPointID != nil {
+		cp.RemoteTaskCounts = getRemoteTaskCounts(ctx)
 		err := r.checkPointer.set(ctx, *checkPointID, cp)
 		if err != nil {
 			return fmt.Errorf("failed to set checkpoint: %w, checkPointID: %s", err, *checkPointID)
@@ -561,6 +624,7 @@ func (r *runner) handleInterruptWithSubGraphAndRerunNodes(
 		SkipPreHandler:         skipPreHandler,
 		ToolsNodeExecutedTools: tempInfo.interruptExecutedTools,
 		SubGraphs:              make(map[string]*checkpoint),
+		Baggage:                GetAllBaggage(ctx),
 	}
 	if r.runCtx != nil {
 		// current graph has enable state
@@ -576,6 +640,7 @@ func (r *runner) handleInterruptWithSubGraphAndRerunNodes(
 		RerunNodes:      tempInfo.interruptRerunNodes,
 		RerunNodesExtra: tempInfo.interruptRerunExtra,
 		SubGraphs:       make(map[string]*InterruptInfo),
+		BudgetExceeded:  tempInfo.budgetExceeded,
 	}
 	for _, t := range subgraphTasks {
 		cp.RerunNodes = append(cp.RerunNodes, t.nodeKey)
@@ -595,6 +660,7 @@ func (r *runner) handleInterruptWithSubGraphAndRerunNodes(
 			CheckPoint: cp,
 		}
 	} else if checkPointID != nil {
+		cp.RemoteTaskCounts = getRemoteTaskCounts(ctx)
 		err = r.checkPointer.set(ctx, *checkPointID, cp)
 		if err != nil {
 			return fmt.Errorf("failed to set checkpoint: %w, checkPointID: %s", err, *checkPointID)
@@ -803,6 +869,8 @@ func (r *runner) calculateBranch(ctx context.Context, curNodeKey string, startCh
 			}
 		}
 
+		emitRunEvent(ctx, &RunEvent{Type: RunEventBranch, Path: *NewNodePath(curNodeKey), BranchTargets: ws})
+
 		ret = append(ret, ws...)
 	}
 
@@ -67,6 +67,9 @@ type InterruptInfo struct {
 	RerunNodes      []string
 	RerunNodesExtra map[string]any
 	SubGraphs       map[string]*InterruptInfo
+
+	// BudgetExceeded is set if the interrupt is caused by the budget set by WithRunBudget being exceeded.
+	BudgetExceeded *RunBudgetUsage
 }
 
 func init() {
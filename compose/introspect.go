@@ -49,6 +49,9 @@ type GraphInfo struct {
 
 	NewGraphOptions []NewGraphOption
 	GenStateFn      func(context.Context) any
+
+	// Diagnostics are the structural warnings found when compiling the graph, see Graph.Diagnostics.
+	Diagnostics []Diagnostic
 }
 
 // GraphCompileCallback is the callback which will be called when graph compilation finishes.
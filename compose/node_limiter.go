@@ -0,0 +1,175 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	icb "github.com/cloudwego/eino/internal/callbacks"
+)
+
+// NodeLimiter limits the executions of a node, e.g. to match the quota of the provider behind a ChatModel node.
+// Both a token bucket and a semaphore can be adapted to it.
+type NodeLimiter interface {
+	// Acquire blocks until the execution is allowed or ctx is done.
+	// release is called once the execution finishes, it can be a no-op for rate limiters such as a token bucket.
+	Acquire(ctx context.Context) (release func(), err error)
+}
+
+// WithNodeLimiter sets the limiter for the node.
+// The limiter is bound to the node when it's added, so all executions of the node,
+// including those from concurrent runs of the compiled graph, share the same limiter.
+// To share a limiter across nodes or graphs, pass the same limiter instance to each of them.
+// For streaming output, the execution is regarded as finished once the node returns its output stream.
+// e.g.
+//
+//	limiter := compose.NewSemaphoreLimiter(10)
+//	graph.AddChatModelNode("model", chatModel, compose.WithNodeLimiter(limiter))
+func WithNodeLimiter(limiter NodeLimiter) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.limiter = limiter
+	}
+}
+
+// NewSemaphoreLimiter creates a NodeLimiter which allows at most n concurrent executions.
+func NewSemaphoreLimiter(n int) NodeLimiter {
+	if n <= 0 {
+		n = 1
+	}
+	return &semaphoreLimiter{sem: make(chan struct{}, n)}
+}
+
+type semaphoreLimiter struct {
+	sem chan struct{}
+}
+
+func (s *semaphoreLimiter) Acquire(ctx context.Context) (func(), error) {
+	select {
+	case s.sem <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { <-s.sem })
+	}, nil
+}
+
+// NewTokenBucketLimiter creates a NodeLimiter which allows executions at the rate of ratePerSecond,
+// with bursts of at most burst executions.
+func NewTokenBucketLimiter(ratePerSecond float64, burst int) NodeLimiter {
+	if burst <= 0 {
+		burst = 1
+	}
+	return &tokenBucketLimiter{
+		rate:   ratePerSecond,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+type tokenBucketLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (t *tokenBucketLimiter) Acquire(ctx context.Context) (func(), error) {
+	wait := t.reserve()
+	if wait <= 0 {
+		return func() {}, nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return func() {}, nil
+	case <-ctx.Done():
+		t.cancelReservation()
+		return nil, ctx.Err()
+	}
+}
+
+// reserve takes a token from the bucket, and returns how long to wait until the token is actually available.
+func (t *tokenBucketLimiter) reserve() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	t.tokens += now.Sub(t.last).Seconds() * t.rate
+	if t.tokens > t.burst {
+		t.tokens = t.burst
+	}
+	t.last = now
+	t.tokens--
+
+	if t.tokens >= 0 {
+		return 0
+	}
+	if t.rate <= 0 {
+		// no refill, the reservation can never be satisfied
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(-t.tokens / t.rate * float64(time.Second))
+}
+
+func (t *tokenBucketLimiter) cancelReservation() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tokens++
+}
+
+func limitedComposableRunnable(limiter NodeLimiter, r *composableRunnable) *composableRunnable {
+	wrapper := *r
+
+	i := r.i
+	wrapper.i = func(ctx context.Context, input any, opts ...any) (output any, err error) {
+		waitStart := time.Now()
+		release, err := limiter.Acquire(ctx)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		ctx = icb.WithQueueWait(ctx, time.Since(waitStart))
+
+		return i(ctx, input, opts...)
+	}
+
+	t := r.t
+	wrapper.t = func(ctx context.Context, input streamReader, opts ...any) (output streamReader, err error) {
+		waitStart := time.Now()
+		release, err := limiter.Acquire(ctx)
+		if err != nil {
+			input.close()
+			return nil, err
+		}
+		defer release()
+		ctx = icb.WithQueueWait(ctx, time.Since(waitStart))
+
+		return t(ctx, input, opts...)
+	}
+
+	return &wrapper
+}
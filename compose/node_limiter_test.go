@@ -0,0 +1,111 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestNodeLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("semaphore shared across runs", func(t *testing.T) {
+		var running, maxRunning int32
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			cur := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&maxRunning)
+				if cur <= m || atomic.CompareAndSwapInt32(&maxRunning, m, cur) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return in, nil
+		}), WithNodeLimiter(NewSemaphoreLimiter(2))))
+		assert.NoError(t, g.AddEdge(START, "1"))
+		assert.NoError(t, g.AddEdge("1", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				out, err := r.Invoke(ctx, "hi")
+				assert.NoError(t, err)
+				assert.Equal(t, "hi", out)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+	})
+
+	t.Run("token bucket", func(t *testing.T) {
+		limiter := NewTokenBucketLimiter(50, 1)
+		c := NewChain[string, string]()
+		c.AppendLambda(InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return in, nil
+		}), WithNodeLimiter(limiter))
+		r, err := c.Compile(ctx)
+		assert.NoError(t, err)
+
+		start := time.Now()
+		for i := 0; i < 3; i++ {
+			_, err = r.Invoke(ctx, "hi")
+			assert.NoError(t, err)
+		}
+		assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond)
+
+		sr, err := r.Stream(ctx, "hi")
+		assert.NoError(t, err)
+		out, err := concatStreamReader(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "hi", out)
+	})
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		limiter := NewSemaphoreLimiter(1)
+		release, err := limiter.Acquire(ctx)
+		assert.NoError(t, err)
+		defer release()
+
+		g := NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("1", StreamableLambda(func(ctx context.Context, in string) (*schema.StreamReader[string], error) {
+			return schema.StreamReaderFromArray([]string{in}), nil
+		}), WithNodeLimiter(limiter)))
+		assert.NoError(t, g.AddEdge(START, "1"))
+		assert.NoError(t, g.AddEdge("1", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		tCtx, cancel := context.WithTimeout(ctx, 20*time.Millisecond)
+		defer cancel()
+		_, err = r.Invoke(tCtx, "hi")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
@@ -0,0 +1,215 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RaceLambda creates a Lambda with "first-wins" parallel semantics:
+// all the runnables are run concurrently with the same input, and the output of the first one that succeeds is returned.
+// Once a winner appears, the contexts of the others are canceled and their outputs are discarded, with streams closed.
+// It's useful for racing a fast/cheap model against a slow/accurate one.
+// The Lambda fails only if all the runnables fail.
+//
+// In invoke mode, the winner is the first runnable that returns without error.
+// In stream mode, the winner is the first runnable whose output stream yields its first chunk without error,
+// and its context is canceled after the returned stream has been consumed or closed.
+// The options passed to the Lambda by WithLambdaOption, e.g. WithCallbacks, are passed to each of the runnables.
+// e.g.
+//
+//	fast, _ := compose.NewChain[[]*schema.Message, *schema.Message]().AppendChatModel(fastModel).Compile(ctx)
+//	accurate, _ := compose.NewChain[[]*schema.Message, *schema.Message]().AppendChatModel(accurateModel).Compile(ctx)
+//	graph.AddLambdaNode("model", compose.RaceLambda([]compose.Runnable[[]*schema.Message, *schema.Message]{fast, accurate}))
+func RaceLambda[I, O any](runnables []Runnable[I, O], opts ...LambdaOpt) *Lambda {
+	i := func(ctx context.Context, input I, opts ...Option) (O, error) {
+		return raceInvoke(ctx, input, runnables, opts)
+	}
+	s := func(ctx context.Context, input I, opts ...Option) (*schema.StreamReader[O], error) {
+		return raceStream(ctx, input, runnables, opts)
+	}
+	return anyLambda(i, s, nil, nil, opts...)
+}
+
+type raceResult[T any] struct {
+	idx    int
+	output T
+	err    error
+}
+
+func raceInvoke[I, O any](ctx context.Context, input I, runnables []Runnable[I, O], opts []Option) (O, error) {
+	if len(runnables) == 0 {
+		var o O
+		return o, errors.New("race has no runnable")
+	}
+
+	cancels := make([]context.CancelFunc, len(runnables))
+	results := make(chan *raceResult[O], len(runnables))
+	for idx, r := range runnables {
+		var rCtx context.Context
+		rCtx, cancels[idx] = context.WithCancel(ctx)
+		go func(idx int, r Runnable[I, O]) {
+			ret := &raceResult[O]{idx: idx}
+			defer func() {
+				if e := recover(); e != nil {
+					ret.err = safe.NewPanicErr(e, debug.Stack())
+				}
+				results <- ret
+			}()
+			ret.output, ret.err = r.Invoke(rCtx, input, opts...)
+		}(idx, r)
+	}
+	defer func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}()
+
+	errs := make([]error, len(runnables))
+	for range runnables {
+		ret := <-results
+		if ret.err == nil {
+			return ret.output, nil
+		}
+		errs[ret.idx] = ret.err
+	}
+	var o O
+	return o, newRaceError(errs)
+}
+
+func raceStream[I, O any](ctx context.Context, input I, runnables []Runnable[I, O], opts []Option) (*schema.StreamReader[O], error) {
+	if len(runnables) == 0 {
+		return nil, errors.New("race has no runnable")
+	}
+
+	type firstChunk struct {
+		sr    *schema.StreamReader[O]
+		chunk O
+		eof   bool
+	}
+
+	cancels := make([]context.CancelFunc, len(runnables))
+	results := make(chan *raceResult[*firstChunk], len(runnables))
+	for idx, r := range runnables {
+		var rCtx context.Context
+		rCtx, cancels[idx] = context.WithCancel(ctx)
+		go func(idx int, r Runnable[I, O]) {
+			ret := &raceResult[*firstChunk]{idx: idx}
+			defer func() {
+				if e := recover(); e != nil {
+					ret.err = safe.NewPanicErr(e, debug.Stack())
+				}
+				results <- ret
+			}()
+			sr, err := r.Stream(rCtx, input, opts...)
+			if err != nil {
+				ret.err = err
+				return
+			}
+			chunk, err := sr.Recv()
+			if err != nil && err != io.EOF {
+				sr.Close()
+				ret.err = err
+				return
+			}
+			ret.output = &firstChunk{sr: sr, chunk: chunk, eof: err == io.EOF}
+		}(idx, r)
+	}
+
+	errs := make([]error, len(runnables))
+	var winner *raceResult[*firstChunk]
+	received := 0
+	for ; received < len(runnables); received++ {
+		ret := <-results
+		if ret.err == nil {
+			winner = ret
+			received++
+			break
+		}
+		errs[ret.idx] = ret.err
+	}
+
+	// cancel and close the losers
+	for idx, cancel := range cancels {
+		if winner == nil || idx != winner.idx {
+			cancel()
+		}
+	}
+	go func(remain int) {
+		for i := 0; i < remain; i++ {
+			if ret := <-results; ret.err == nil {
+				ret.output.sr.Close()
+			}
+		}
+	}(len(runnables) - received)
+
+	if winner == nil {
+		return nil, newRaceError(errs)
+	}
+
+	fc := winner.output
+	cancel := cancels[winner.idx]
+	if fc.eof {
+		fc.sr.Close()
+		cancel()
+		return schema.StreamReaderFromArray([]O{}), nil
+	}
+
+	sr, sw := schema.Pipe[O](0)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				sw.Send(*new(O), safe.NewPanicErr(e, debug.Stack()))
+			}
+			fc.sr.Close()
+			sw.Close()
+			cancel()
+		}()
+		if sw.Send(fc.chunk, nil) {
+			return
+		}
+		for {
+			chunk, err := fc.sr.Recv()
+			if err == io.EOF {
+				return
+			}
+			if sw.Send(chunk, err) || err != nil {
+				return
+			}
+		}
+	}()
+	return sr, nil
+}
+
+func newRaceError(errs []error) error {
+	sb := strings.Builder{}
+	for i, err := range errs {
+		if i > 0 {
+			sb.WriteString("; ")
+		}
+		sb.WriteString(fmt.Sprintf("[%d]: %v", i, err))
+	}
+	return fmt.Errorf("all runnables in race failed: %s", sb.String())
+}
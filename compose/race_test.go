@@ -0,0 +1,122 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestRaceLambda(t *testing.T) {
+	ctx := context.Background()
+
+	started := make(chan struct{}, 1)
+	canceled := make(chan struct{}, 1)
+	slow, err := AnyLambda[string, string, any](func(ctx context.Context, input string, opts ...any) (string, error) {
+		started <- struct{}{}
+		<-ctx.Done()
+		canceled <- struct{}{}
+		return "", ctx.Err()
+	}, func(ctx context.Context, input string, opts ...any) (*schema.StreamReader[string], error) {
+		sr, sw := schema.Pipe[string](0)
+		started <- struct{}{}
+		go func() {
+			defer sw.Close()
+			<-ctx.Done()
+			canceled <- struct{}{}
+		}()
+		return sr, nil
+	}, nil, nil)
+	assert.NoError(t, err)
+
+	compileLambda := func(l *Lambda, err error) Runnable[string, string] {
+		assert.NoError(t, err)
+		r, err := NewChain[string, string]().AppendLambda(l).Compile(ctx)
+		assert.NoError(t, err)
+		return r
+	}
+	slowR := compileLambda(slow, nil)
+	// fast waits until slow has started, otherwise slow may be canceled before running
+	fastR := compileLambda(AnyLambda[string, string, any](func(ctx context.Context, input string, opts ...any) (string, error) {
+		<-started
+		return input + "_fast", nil
+	}, func(ctx context.Context, input string, opts ...any) (*schema.StreamReader[string], error) {
+		<-started
+		return schema.StreamReaderFromArray([]string{input, "_fast"}), nil
+	}, nil, nil))
+	failR := compileLambda(AnyLambda[string, string, any](func(ctx context.Context, input string, opts ...any) (string, error) {
+		return "", errors.New("fail")
+	}, nil, nil, nil))
+
+	race, err := NewChain[string, string]().
+		AppendLambda(RaceLambda([]Runnable[string, string]{slowR, failR, fastR})).
+		Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := race.Invoke(ctx, "in")
+	assert.NoError(t, err)
+	assert.Equal(t, "in_fast", out)
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("slow runnable not canceled")
+	}
+
+	sr, err := race.Stream(ctx, "in")
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "in_fast", out)
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("slow runnable not canceled")
+	}
+
+	allFail, err := NewChain[string, string]().
+		AppendLambda(RaceLambda([]Runnable[string, string]{failR, failR})).
+		Compile(ctx)
+	assert.NoError(t, err)
+	_, err = allFail.Invoke(ctx, "in")
+	assert.ErrorContains(t, err, "all runnables in race failed")
+
+	// the options of the lambda are passed to the runnables
+	tagR := compileLambda(AnyLambda[string, string, string](func(ctx context.Context, input string, opts ...string) (string, error) {
+		return input + "_" + strings.Join(opts, "_"), nil
+	}, func(ctx context.Context, input string, opts ...string) (*schema.StreamReader[string], error) {
+		return schema.StreamReaderFromArray([]string{input, "_" + strings.Join(opts, "_")}), nil
+	}, nil, nil))
+	tagged, err := NewChain[string, string]().
+		AppendLambda(RaceLambda([]Runnable[string, string]{tagR})).
+		Compile(ctx)
+	assert.NoError(t, err)
+	out, err = tagged.Invoke(ctx, "in", WithLambdaOption(WithLambdaOption("tag")))
+	assert.NoError(t, err)
+	assert.Equal(t, "in_tag", out)
+	sr, err = tagged.Stream(ctx, "in", WithLambdaOption(WithLambdaOption("tag")))
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "in_tag", out)
+}
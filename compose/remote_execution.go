@@ -0,0 +1,292 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+
+	"github.com/cloudwego/eino/internal/serialization"
+)
+
+// RemoteTask is a node execution dispatched to remote workers by an ExecutionBackend.
+type RemoteTask struct {
+	// ID identifies the node execution.
+	// When the graph runs with a checkpoint ID, the ID is derived from the checkpoint ID, the node path, the input,
+	// and the number of the completed executions of the node with the same input in the run, e.g. in loops,
+	// which is saved to the checkpoint when interrupted.
+	// So the re-execution of the node after resuming from the checkpoint gets the same ID,
+	// and the backend can deduplicate it or return the result of the previous attempt, which gives at-least-once semantics.
+	// Otherwise, the ID is random.
+	ID string
+	// NodeKey is the key used to find the node implementation registered in RemoteWorker.
+	NodeKey string
+	// Path is the full path of the node in the orchestrator graph.
+	Path []string
+	// Input is the serialized input of the node.
+	Input []byte
+}
+
+// ExecutionBackend dispatches node executions to remote workers, e.g. by a task queue.
+type ExecutionBackend interface {
+	// Execute sends the task to a remote worker, and blocks until the serialized output is returned or ctx is done.
+	// Tasks with the same ID are the same execution, the backend can reuse the result of a previous attempt.
+	Execute(ctx context.Context, task *RemoteTask) (output []byte, err error)
+}
+
+type remoteExecutionOptions struct {
+	nodeKey    string
+	serializer Serializer
+}
+
+// RemoteExecutionOption is the option for WithRemoteExecution.
+type RemoteExecutionOption func(o *remoteExecutionOptions)
+
+// WithRemoteNodeKey sets the key of the node on the remote worker, defaults to the key of the node in the graph.
+func WithRemoteNodeKey(key string) RemoteExecutionOption {
+	return func(o *remoteExecutionOptions) {
+		o.nodeKey = key
+	}
+}
+
+// WithRemoteSerializer sets the serializer of node input and output, defaults to the serializer used by checkpoints.
+// The remote worker must use the same serializer.
+func WithRemoteSerializer(serializer Serializer) RemoteExecutionOption {
+	return func(o *remoteExecutionOptions) {
+		o.serializer = serializer
+	}
+}
+
+// WithRemoteExecution dispatches the executions of the node to remote workers through the backend,
+// instead of running it in the orchestrator process. It's useful for heavy nodes such as long-running tools,
+// which can then be scaled independently.
+// The node added to the graph only declares the types, its implementation runs on the worker, see RemoteWorker.
+// Input and output types of the node must be serializable, custom types should be registered by schema.RegisterName.
+// Streaming input is concatenated before dispatching, and streaming output is restored from the whole output.
+// e.g.
+//
+//	graph.AddLambdaNode("search", compose.InvokableLambda(search), compose.WithRemoteExecution(queueBackend))
+func WithRemoteExecution(backend ExecutionBackend, opts ...RemoteExecutionOption) GraphAddNodeOpt {
+	o := &remoteExecutionOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.serializer == nil {
+		o.serializer = &serialization.InternalSerializer{}
+	}
+
+	return func(opt *graphAddNodeOpts) {
+		opt.nodeOptions.remote = &remoteExecution{
+			backend: backend,
+			opts:    o,
+		}
+	}
+}
+
+type remoteExecution struct {
+	backend ExecutionBackend
+	opts    *remoteExecutionOptions
+}
+
+func (re *remoteExecution) execute(ctx context.Context, input any) (any, error) {
+	in, err := re.opts.serializer.Marshal(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize remote node input: %w", err)
+	}
+
+	var path []string
+	if p, ok := getNodeKey(ctx); ok {
+		path = p.GetPath()
+	}
+	nodeKey := re.opts.nodeKey
+	if nodeKey == "" && len(path) > 0 {
+		nodeKey = path[len(path)-1]
+	}
+
+	id, complete := newRemoteTaskID(ctx, path, in)
+	task := &RemoteTask{
+		ID:      id,
+		NodeKey: nodeKey,
+		Path:    path,
+		Input:   in,
+	}
+	out, err := re.backend.Execute(ctx, task)
+	if err != nil {
+		return nil, fmt.Errorf("remote execution of node[%s] fail, task id: %s: %w", nodeKey, task.ID, err)
+	}
+	complete()
+
+	var output any
+	if err = re.opts.serializer.Unmarshal(out, &output); err != nil {
+		return nil, fmt.Errorf("failed to deserialize remote node output: %w", err)
+	}
+	return output, nil
+}
+
+func remoteComposableRunnable(re *remoteExecution, r *composableRunnable) *composableRunnable {
+	wrapper := *r
+
+	wrapper.i = func(ctx context.Context, input any, opts ...any) (output any, err error) {
+		return re.execute(ctx, input)
+	}
+
+	wrapper.t = func(ctx context.Context, input streamReader, opts ...any) (output streamReader, err error) {
+		in, err := r.inputStreamConvertPair.concatStream(input)
+		if err != nil {
+			return nil, err
+		}
+		out, err := re.execute(ctx, in)
+		if err != nil {
+			return nil, err
+		}
+		return r.outputStreamConvertPair.restoreStream(out)
+	}
+
+	return &wrapper
+}
+
+type remoteTaskScopeKey struct{}
+
+// remoteTaskScope derives the remote task IDs of a run with checkpoint ID.
+type remoteTaskScope struct {
+	checkPointID string
+
+	mu sync.Mutex
+	// counts are the numbers of the completed remote executions, keyed by the node path and the input digest,
+	// so that the executions of the same node with the same input, e.g. in loops, get different task IDs.
+	counts map[string]int
+}
+
+func setRemoteTaskScope(ctx context.Context, checkPointID string) context.Context {
+	if _, ok := ctx.Value(remoteTaskScopeKey{}).(*remoteTaskScope); ok {
+		return ctx
+	}
+	return context.WithValue(ctx, remoteTaskScopeKey{}, &remoteTaskScope{
+		checkPointID: checkPointID,
+		counts:       make(map[string]int),
+	})
+}
+
+// restoreRemoteTaskCounts restores the counts saved in the checkpoint, so the executions after resuming
+// continue the sequence instead of reusing the task IDs of the executions before the interrupt.
+func restoreRemoteTaskCounts(ctx context.Context, counts map[string]int) {
+	scope, ok := ctx.Value(remoteTaskScopeKey{}).(*remoteTaskScope)
+	if !ok {
+		return
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	for k, v := range counts {
+		scope.counts[k] = v
+	}
+}
+
+func getRemoteTaskCounts(ctx context.Context) map[string]int {
+	scope, ok := ctx.Value(remoteTaskScopeKey{}).(*remoteTaskScope)
+	if !ok {
+		return nil
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	if len(scope.counts) == 0 {
+		return nil
+	}
+	counts := make(map[string]int, len(scope.counts))
+	for k, v := range scope.counts {
+		counts[k] = v
+	}
+	return counts
+}
+
+// newRemoteTaskID returns the ID of the task and a function to call once the execution completes.
+func newRemoteTaskID(ctx context.Context, path []string, input []byte) (string, func()) {
+	scope, ok := ctx.Value(remoteTaskScopeKey{}).(*remoteTaskScope)
+	if !ok {
+		return uuid.NewString(), func() {}
+	}
+	sum := sha256.Sum256(input)
+	key := fmt.Sprintf("%s/%s", strings.Join(path, "/"), hex.EncodeToString(sum[:8]))
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	id := fmt.Sprintf("%s/%s/%d", scope.checkPointID, key, scope.counts[key])
+	return id, func() {
+		scope.mu.Lock()
+		defer scope.mu.Unlock()
+		scope.counts[key]++
+	}
+}
+
+// RemoteWorker executes the RemoteTask dispatched by ExecutionBackend on the worker side.
+type RemoteWorker struct {
+	mu         sync.RWMutex
+	serializer Serializer
+	handlers   map[string]func(ctx context.Context, input []byte) ([]byte, error)
+}
+
+// NewRemoteWorker creates a RemoteWorker, the serializer should be the same as the one used in WithRemoteExecution,
+// nil means the default one.
+func NewRemoteWorker(serializer Serializer) *RemoteWorker {
+	if serializer == nil {
+		serializer = &serialization.InternalSerializer{}
+	}
+	return &RemoteWorker{
+		serializer: serializer,
+		handlers:   make(map[string]func(ctx context.Context, input []byte) ([]byte, error)),
+	}
+}
+
+// RegisterRemoteNode registers the implementation of the remote node to the worker.
+// e.g.
+//
+//	worker := compose.NewRemoteWorker(nil)
+//	compose.RegisterRemoteNode(worker, "search", search)
+//	// in the consumer of the task queue
+//	output, err := worker.Handle(ctx, task)
+func RegisterRemoteNode[I, O any](w *RemoteWorker, nodeKey string, fn InvokeWOOpt[I, O]) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.handlers[nodeKey] = func(ctx context.Context, in []byte) ([]byte, error) {
+		var input I
+		if err := w.serializer.Unmarshal(in, &input); err != nil {
+			return nil, fmt.Errorf("failed to deserialize remote node input: %w", err)
+		}
+		output, err := fn(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+		return w.serializer.Marshal(output)
+	}
+}
+
+// Handle executes the task with the registered node implementation, and returns the serialized output.
+func (w *RemoteWorker) Handle(ctx context.Context, task *RemoteTask) ([]byte, error) {
+	w.mu.RLock()
+	h, ok := w.handlers[task.NodeKey]
+	w.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("remote node[%s] not registered", task.NodeKey)
+	}
+	return h(ctx, task.Input)
+}
@@ -0,0 +1,160 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type queueBackend struct {
+	mu      sync.Mutex
+	worker  *RemoteWorker
+	tasks   []*RemoteTask
+	results map[string][]byte
+}
+
+func (q *queueBackend) Execute(ctx context.Context, task *RemoteTask) ([]byte, error) {
+	q.mu.Lock()
+	q.tasks = append(q.tasks, task)
+	if out, ok := q.results[task.ID]; ok {
+		q.mu.Unlock()
+		return out, nil
+	}
+	q.mu.Unlock()
+
+	type result struct {
+		out []byte
+		err error
+	}
+	ch := make(chan result, 1)
+	go func() {
+		out, err := q.worker.Handle(ctx, task)
+		ch <- result{out, err}
+	}()
+	res := <-ch
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	q.mu.Lock()
+	q.results[task.ID] = res.out
+	q.mu.Unlock()
+	return res.out, nil
+}
+
+func TestRemoteExecution(t *testing.T) {
+	ctx := context.Background()
+
+	var workerCalls int
+	worker := NewRemoteWorker(nil)
+	RegisterRemoteNode(worker, "upper", func(ctx context.Context, in string) (string, error) {
+		workerCalls++
+		return strings.ToUpper(in), nil
+	})
+	backend := &queueBackend{worker: worker, results: make(map[string][]byte)}
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("remote", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		t.Fatal("remote node should not run locally")
+		return "", nil
+	}), WithRemoteExecution(backend, WithRemoteNodeKey("upper"))))
+	assert.NoError(t, g.AddLambdaNode("local", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		return in + "!", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "remote"))
+	assert.NoError(t, g.AddEdge("remote", "local"))
+	assert.NoError(t, g.AddEdge("local", END))
+	r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()))
+	assert.NoError(t, err)
+
+	out, err := r.Invoke(ctx, "hi")
+	assert.NoError(t, err)
+	assert.Equal(t, "HI!", out)
+	sr, err := r.Stream(ctx, "hi")
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "HI!", out)
+	assert.Len(t, backend.tasks, 2)
+	assert.NotEqual(t, backend.tasks[0].ID, backend.tasks[1].ID)
+	assert.Equal(t, []string{"remote"}, backend.tasks[0].Path)
+	assert.Equal(t, 2, workerCalls)
+
+	// with checkpoint id, re-execution of the same node gets the same task id, so the backend can reuse the result
+	for i := 0; i < 2; i++ {
+		out, err = r.Invoke(ctx, "hello", WithCheckPointID("cp"))
+		assert.NoError(t, err)
+		assert.Equal(t, "HELLO!", out)
+	}
+	assert.Len(t, backend.tasks, 4)
+	assert.Equal(t, backend.tasks[2].ID, backend.tasks[3].ID)
+	assert.True(t, strings.HasPrefix(backend.tasks[2].ID, "cp/remote/"))
+	assert.Equal(t, 3, workerCalls)
+
+	// executions of the same node with the same input in a loop get different task ids
+	RegisterRemoteNode(worker, "echo", func(ctx context.Context, in string) (string, error) {
+		workerCalls++
+		return in, nil
+	})
+	loop := NewGraph[string, string]()
+	assert.NoError(t, loop.AddLambdaNode("echo", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+		t.Fatal("remote node should not run locally")
+		return "", nil
+	}), WithRemoteExecution(backend)))
+	assert.NoError(t, loop.AddEdge(START, "echo"))
+	var rounds int
+	assert.NoError(t, loop.AddBranch("echo", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+		rounds++
+		if rounds < 2 {
+			return "echo", nil
+		}
+		return END, nil
+	}, map[string]bool{"echo": true, END: true})))
+	lr, err := loop.Compile(ctx, WithCheckPointStore(newInMemoryStore()))
+	assert.NoError(t, err)
+	out, err = lr.Invoke(ctx, "hi", WithCheckPointID("loop"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", out)
+	assert.Len(t, backend.tasks, 6)
+	assert.NotEqual(t, backend.tasks[4].ID, backend.tasks[5].ID)
+	assert.True(t, strings.HasSuffix(backend.tasks[4].ID, "/0"))
+	assert.True(t, strings.HasSuffix(backend.tasks[5].ID, "/1"))
+	assert.Equal(t, 5, workerCalls)
+
+	// the counts are saved to the checkpoint, so the executions after resuming don't reuse the previous task ids
+	rounds = 0
+	lr, err = loop.Compile(ctx, WithCheckPointStore(newInMemoryStore()), WithInterruptAfterNodes([]string{"echo"}))
+	assert.NoError(t, err)
+	_, err = lr.Invoke(ctx, "hi", WithCheckPointID("resume"))
+	_, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	out, err = lr.Invoke(ctx, "", WithCheckPointID("resume"))
+	assert.NoError(t, err)
+	assert.Equal(t, "hi", out)
+	assert.Len(t, backend.tasks, 8)
+	assert.True(t, strings.HasSuffix(backend.tasks[6].ID, "/0"))
+	assert.True(t, strings.HasSuffix(backend.tasks[7].ID, "/1"))
+	assert.Equal(t, 7, workerCalls)
+
+	_, err = worker.Handle(ctx, &RemoteTask{NodeKey: "unknown"})
+	assert.ErrorContains(t, err, "remote node[unknown] not registered")
+}
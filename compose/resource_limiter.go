@@ -0,0 +1,195 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"sync"
+)
+
+// ResourceLimiter limits the executions against shared resources keyed by the resource, e.g. "openai:gpt-4o",
+// so that all the nodes and the graphs calling the same provider respect a single quota.
+// NewLocalResourceLimiter enforces the limits in the process, implement it over a shared storage,
+// e.g. Redis, to enforce the limits across processes.
+type ResourceLimiter interface {
+	// Acquire blocks until the execution against the resource is allowed or ctx is done.
+	// release is called once the execution finishes.
+	Acquire(ctx context.Context, resource string) (release func(), err error)
+}
+
+// NewResourceNodeLimiter adapts the limit of the resource to a NodeLimiter,
+// e.g. for WithNodeLimiter, or model.WithLimiter of a RetryingChatModel.
+func NewResourceNodeLimiter(limiter ResourceLimiter, resource string) NodeLimiter {
+	return &resourceNodeLimiter{limiter: limiter, resource: resource}
+}
+
+type resourceNodeLimiter struct {
+	limiter  ResourceLimiter
+	resource string
+}
+
+func (r *resourceNodeLimiter) Acquire(ctx context.Context) (func(), error) {
+	limiter := r.limiter
+	if limiter == nil {
+		limiter = DefaultResourceLimiter()
+	}
+	return limiter.Acquire(ctx, r.resource)
+}
+
+// WithResourceLimit limits the executions of the node by the limit of the resource in the DefaultResourceLimiter,
+// which is shared by all the nodes and the graphs in the process limited by the same resource.
+// It can be set multiple times for the nodes using multiple resources, and works together with WithNodeLimiter.
+// e.g.
+//
+//	limiter := compose.NewLocalResourceLimiter()
+//	limiter.SetRateLimit("openai:gpt-4o", 10, 20)
+//	limiter.SetConcurrencyLimit("openai:gpt-4o", 5)
+//	compose.SetDefaultResourceLimiter(limiter)
+//
+//	graph.AddChatModelNode("model", chatModel, compose.WithResourceLimit("openai:gpt-4o"))
+func WithResourceLimit(resource string) GraphAddNodeOpt {
+	return WithResourceLimiter(nil, resource)
+}
+
+// WithResourceLimiter limits the executions of the node by the limit of the resource in the limiter,
+// e.g. a distributed ResourceLimiter. The DefaultResourceLimiter is used if limiter is nil.
+func WithResourceLimiter(limiter ResourceLimiter, resource string) GraphAddNodeOpt {
+	return func(o *graphAddNodeOpts) {
+		o.nodeOptions.resourceLimiters = append(o.nodeOptions.resourceLimiters, NewResourceNodeLimiter(limiter, resource))
+	}
+}
+
+var (
+	defaultResourceLimiterMu sync.RWMutex
+	defaultResourceLimiter   ResourceLimiter = NewLocalResourceLimiter()
+)
+
+// DefaultResourceLimiter returns the ResourceLimiter used by WithResourceLimit, a *LocalResourceLimiter unless replaced.
+func DefaultResourceLimiter() ResourceLimiter {
+	defaultResourceLimiterMu.RLock()
+	defer defaultResourceLimiterMu.RUnlock()
+	return defaultResourceLimiter
+}
+
+// SetDefaultResourceLimiter replaces the ResourceLimiter used by WithResourceLimit, e.g. by a distributed one,
+// it takes effect on the executions started afterward.
+func SetDefaultResourceLimiter(limiter ResourceLimiter) {
+	defaultResourceLimiterMu.Lock()
+	defer defaultResourceLimiterMu.Unlock()
+	defaultResourceLimiter = limiter
+}
+
+// LocalResourceLimiter is a ResourceLimiter enforcing the limits of the resources in the process.
+// The resources without limits are not limited.
+type LocalResourceLimiter struct {
+	mu       sync.RWMutex
+	limiters map[string]*localLimits
+}
+
+type localLimits struct {
+	rate        NodeLimiter
+	concurrency NodeLimiter
+}
+
+// NewLocalResourceLimiter creates a LocalResourceLimiter without limits.
+func NewLocalResourceLimiter() *LocalResourceLimiter {
+	return &LocalResourceLimiter{limiters: make(map[string]*localLimits)}
+}
+
+// SetRateLimit limits the executions against the resource at the rate of ratePerSecond, with bursts of at most burst executions.
+func (l *LocalResourceLimiter) SetRateLimit(resource string, ratePerSecond float64, burst int) {
+	l.update(resource, func(limits *localLimits) {
+		limits.rate = NewTokenBucketLimiter(ratePerSecond, burst)
+	})
+}
+
+// SetConcurrencyLimit limits the concurrent executions against the resource to at most n.
+func (l *LocalResourceLimiter) SetConcurrencyLimit(resource string, n int) {
+	l.update(resource, func(limits *localLimits) {
+		limits.concurrency = NewSemaphoreLimiter(n)
+	})
+}
+
+// RemoveLimits removes the limits of the resource.
+func (l *LocalResourceLimiter) RemoveLimits(resource string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	delete(l.limiters, resource)
+}
+
+func (l *LocalResourceLimiter) update(resource string, fn func(limits *localLimits)) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	limits := &localLimits{}
+	if old, ok := l.limiters[resource]; ok {
+		*limits = *old
+	}
+	fn(limits)
+	l.limiters[resource] = limits
+}
+
+// Acquire waits for the rate limit of the resource, then for the concurrency limit.
+func (l *LocalResourceLimiter) Acquire(ctx context.Context, resource string) (func(), error) {
+	l.mu.RLock()
+	limits, ok := l.limiters[resource]
+	l.mu.RUnlock()
+	if !ok {
+		return func() {}, nil
+	}
+
+	var chained []NodeLimiter
+	if limits.rate != nil {
+		chained = append(chained, limits.rate)
+	}
+	if limits.concurrency != nil {
+		chained = append(chained, limits.concurrency)
+	}
+	return chainLimiters(chained).Acquire(ctx)
+}
+
+// chainLimiters acquires the limiters in order, and releases them in the reverse order.
+type chainLimiters []NodeLimiter
+
+func (c chainLimiters) Acquire(ctx context.Context) (func(), error) {
+	releases := make([]func(), 0, len(c))
+	releaseAll := func() {
+		for i := len(releases) - 1; i >= 0; i-- {
+			releases[i]()
+		}
+	}
+	for _, limiter := range c {
+		release, err := limiter.Acquire(ctx)
+		if err != nil {
+			releaseAll()
+			return nil, err
+		}
+		releases = append(releases, release)
+	}
+	return releaseAll, nil
+}
+
+// nodeLimiterOf combines the limiter set by WithNodeLimiter and the limiters of the resources, nil if none.
+func nodeLimiterOf(limiter NodeLimiter, resourceLimiters []NodeLimiter) NodeLimiter {
+	if len(resourceLimiters) == 0 {
+		return limiter
+	}
+	var chained chainLimiters
+	if limiter != nil {
+		chained = append(chained, limiter)
+	}
+	return append(chained, resourceLimiters...)
+}
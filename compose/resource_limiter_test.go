@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingResourceLimiter struct {
+	mu        sync.Mutex
+	resources []string
+}
+
+func (r *recordingResourceLimiter) Acquire(_ context.Context, resource string) (func(), error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resources = append(r.resources, resource)
+	return func() {}, nil
+}
+
+func TestResourceLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("shared across graphs", func(t *testing.T) {
+		limiter := NewLocalResourceLimiter()
+		limiter.SetConcurrencyLimit("provider", 2)
+		limiter.SetRateLimit("provider", 1000, 10)
+		old := DefaultResourceLimiter()
+		SetDefaultResourceLimiter(limiter)
+		defer SetDefaultResourceLimiter(old)
+
+		var running, maxRunning int32
+		newRunnable := func() Runnable[string, string] {
+			g := NewGraph[string, string]()
+			assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, in string) (string, error) {
+				cur := atomic.AddInt32(&running, 1)
+				for {
+					m := atomic.LoadInt32(&maxRunning)
+					if cur <= m || atomic.CompareAndSwapInt32(&maxRunning, m, cur) {
+						break
+					}
+				}
+				time.Sleep(20 * time.Millisecond)
+				atomic.AddInt32(&running, -1)
+				return in, nil
+			}), WithResourceLimit("provider"), WithResourceLimit("unlimited")))
+			assert.NoError(t, g.AddEdge(START, "1"))
+			assert.NoError(t, g.AddEdge("1", END))
+			r, err := g.Compile(ctx)
+			assert.NoError(t, err)
+			return r
+		}
+		runnables := []Runnable[string, string]{newRunnable(), newRunnable()}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 6; i++ {
+			wg.Add(1)
+			r := runnables[i%2]
+			go func() {
+				defer wg.Done()
+				out, err := r.Invoke(ctx, "hi")
+				assert.NoError(t, err)
+				assert.Equal(t, "hi", out)
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+
+		limiter.RemoveLimits("provider")
+		release, err := limiter.Acquire(ctx, "provider")
+		assert.NoError(t, err)
+		release()
+	})
+
+	t.Run("custom limiter with node limiter", func(t *testing.T) {
+		limiter := &recordingResourceLimiter{}
+		sem := NewSemaphoreLimiter(1)
+		c := NewChain[string, string]()
+		c.AppendLambda(InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return in, nil
+		}), WithNodeLimiter(sem), WithResourceLimiter(limiter, "search-api"))
+		r, err := c.Compile(ctx)
+		assert.NoError(t, err)
+
+		_, err = r.Invoke(ctx, "hi")
+		assert.NoError(t, err)
+		_, err = r.Invoke(ctx, "hi")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"search-api", "search-api"}, limiter.resources)
+	})
+
+	t.Run("canceled releases the acquired", func(t *testing.T) {
+		limiter := NewLocalResourceLimiter()
+		limiter.SetRateLimit("provider", 0.001, 1)
+		limiter.SetConcurrencyLimit("provider", 1)
+
+		release, err := limiter.Acquire(ctx, "provider")
+		assert.NoError(t, err)
+		release()
+
+		tCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+		_, err = NewResourceNodeLimiter(limiter, "provider").Acquire(tCtx)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
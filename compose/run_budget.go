@@ -0,0 +1,201 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func init() {
+	schema.RegisterName[*RunBudgetUsage]("_eino_compose_run_budget_usage")
+}
+
+// RunBudget is the budget of a graph run, zero value of each field means no limit.
+type RunBudget struct {
+	// MaxTotalTokens limits the total tokens of all the chat models called in the run, including those in nested graphs.
+	MaxTotalTokens int
+	// MaxCost limits the total cost of all the chat models called in the run, computed by CostFunc.
+	MaxCost float64
+	// CostFunc computes the cost of one chat model call. Required if MaxCost is set.
+	CostFunc func(ctx context.Context, output *model.CallbackOutput) float64
+	// MaxDuration limits the wall-clock time of the run.
+	MaxDuration time.Duration
+}
+
+// RunBudgetUsage is the usage of a graph run when the budget has been exceeded.
+type RunBudgetUsage struct {
+	TotalTokens int
+	Cost        float64
+	Duration    time.Duration
+	// Exceeded lists which limits have been exceeded, the values could be "tokens", "cost" and "duration".
+	Exceeded []string
+}
+
+// WithRunBudget sets a budget on the graph run.
+// The cumulative token usage and cost of chat models are collected through callbacks, and the elapsed time is measured from the start of the run.
+// The budget is checked each time some nodes have completed. Once exceeded, the graph is interrupted before the next nodes,
+// with InterruptInfo.BudgetExceeded describing the usage. If a checkpoint id has been set, the run can be resumed from the checkpoint,
+// and the usage is counted from zero again in the resumed run, i.e. resuming grants a new budget.
+// Notice: usage of a stream output is collected when the stream is finished, so it may take effect later than the node completed.
+// The option can only be used in one run.
+// e.g.
+//
+//	_, err := runnable.Invoke(ctx, input, compose.WithCheckPointID("1"), compose.WithRunBudget(&compose.RunBudget{MaxTotalTokens: 10000}))
+//	if info, ok := compose.ExtractInterruptInfo(err); ok && info.BudgetExceeded != nil {
+//		// ask for human confirmation, then resume
+//		out, err := runnable.Invoke(ctx, input, compose.WithCheckPointID("1"), compose.WithRunBudget(&compose.RunBudget{MaxTotalTokens: 10000}))
+//	}
+func WithRunBudget(budget *RunBudget) Option {
+	t := &runBudgetTracker{budget: budget}
+	return Option{
+		handler:          []callbacks.Handler{t.handler()},
+		runBudgetTracker: t,
+	}
+}
+
+type runBudgetTracker struct {
+	budget *RunBudget
+
+	mu          sync.Mutex
+	start       time.Time
+	totalTokens int
+	cost        float64
+}
+
+func getRunBudgetTrackerFromOptions(opts ...Option) *runBudgetTracker {
+	for _, opt := range opts {
+		if opt.runBudgetTracker != nil {
+			return opt.runBudgetTracker
+		}
+	}
+	return nil
+}
+
+func (t *runBudgetTracker) begin() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.start = time.Now()
+	t.totalTokens = 0
+	t.cost = 0
+}
+
+func (t *runBudgetTracker) add(ctx context.Context, output *model.CallbackOutput) {
+	if output == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if output.TokenUsage != nil {
+		t.totalTokens += output.TokenUsage.TotalTokens
+	}
+	if t.budget.CostFunc != nil {
+		t.cost += t.budget.CostFunc(ctx, output)
+	}
+}
+
+// check returns the usage if the budget has been exceeded, otherwise nil.
+func (t *runBudgetTracker) check() *RunBudgetUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	usage := &RunBudgetUsage{
+		TotalTokens: t.totalTokens,
+		Cost:        t.cost,
+		Duration:    time.Since(t.start),
+	}
+	if t.budget.MaxTotalTokens > 0 && usage.TotalTokens > t.budget.MaxTotalTokens {
+		usage.Exceeded = append(usage.Exceeded, "tokens")
+	}
+	if t.budget.MaxCost > 0 && usage.Cost > t.budget.MaxCost {
+		usage.Exceeded = append(usage.Exceeded, "cost")
+	}
+	if t.budget.MaxDuration > 0 && usage.Duration > t.budget.MaxDuration {
+		usage.Exceeded = append(usage.Exceeded, "duration")
+	}
+	if len(usage.Exceeded) == 0 {
+		return nil
+	}
+	return usage
+}
+
+func (t *runBudgetTracker) handler() callbacks.Handler {
+	return callbacks.NewHandlerBuilder().
+		OnEndFn(func(ctx context.Context, info *callbacks.RunInfo, output callbacks.CallbackOutput) context.Context {
+			if info == nil || info.Component != components.ComponentOfChatModel {
+				return ctx
+			}
+			t.add(ctx, convModelCallbackOutput(output))
+			return ctx
+		}).
+		OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+			if info == nil || info.Component != components.ComponentOfChatModel {
+				output.Close()
+				return ctx
+			}
+			go func() {
+				defer output.Close()
+				var last *model.CallbackOutput
+				for {
+					chunk, err := output.Recv()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						return
+					}
+					if o := convModelCallbackOutput(chunk); o != nil && o.TokenUsage != nil {
+						last = o
+					}
+				}
+				t.add(ctx, last)
+			}()
+			return ctx
+		}).Build()
+}
+
+// convModelCallbackOutput converts the output to model callback output, taking token usage from the response meta of the message if absent.
+func convModelCallbackOutput(output callbacks.CallbackOutput) *model.CallbackOutput {
+	o := model.ConvCallbackOutput(output)
+	if o == nil {
+		return nil
+	}
+	if o.TokenUsage == nil && o.Message != nil && o.Message.ResponseMeta != nil && o.Message.ResponseMeta.Usage != nil {
+		u := o.Message.ResponseMeta.Usage
+		nO := *o
+		nO.TokenUsage = &model.TokenUsage{
+			PromptTokens:           u.PromptTokens,
+			PromptTokenDetails:     model.PromptTokenDetails{CachedTokens: u.PromptTokenDetails.CachedTokens},
+			CompletionTokens:       u.CompletionTokens,
+			CompletionTokenDetails: model.CompletionTokenDetails{ReasoningTokens: u.CompletionTokenDetails.ReasoningTokens},
+			TotalTokens:            u.TotalTokens,
+		}
+		return &nO
+	}
+	return o
+}
+
+func (u *RunBudgetUsage) String() string {
+	return fmt.Sprintf("run budget exceeded: %v, total tokens: %d, cost: %f, duration: %s", u.Exceeded, u.TotalTokens, u.Cost, u.Duration)
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+type usageChatModel struct {
+	tokens int
+}
+
+func (u *usageChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	msg := schema.AssistantMessage(input[len(input)-1].Content+"a", nil)
+	msg.ResponseMeta = &schema.ResponseMeta{Usage: &schema.TokenUsage{TotalTokens: u.tokens}}
+	return msg, nil
+}
+
+func (u *usageChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, _ := u.Generate(ctx, input, opts...)
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func TestRunBudget(t *testing.T) {
+	ctx := context.Background()
+
+	cm := &usageChatModel{tokens: 10}
+	g := NewGraph[[]*schema.Message, *schema.Message]()
+	assert.NoError(t, g.AddChatModelNode("model1", cm))
+	assert.NoError(t, g.AddLambdaNode("convert", InvokableLambda(func(ctx context.Context, input *schema.Message) ([]*schema.Message, error) {
+		return []*schema.Message{input}, nil
+	})))
+	assert.NoError(t, g.AddChatModelNode("model2", cm))
+	assert.NoError(t, g.AddEdge(START, "model1"))
+	assert.NoError(t, g.AddEdge("model1", "convert"))
+	assert.NoError(t, g.AddEdge("convert", "model2"))
+	assert.NoError(t, g.AddEdge("model2", END))
+	r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()))
+	assert.NoError(t, err)
+
+	input := []*schema.Message{schema.UserMessage("")}
+
+	// not exceeded
+	out, err := r.Invoke(ctx, input, WithRunBudget(&RunBudget{MaxTotalTokens: 20}))
+	assert.NoError(t, err)
+	assert.Equal(t, "aa", out.Content)
+
+	// exceeded by tokens
+	_, err = r.Invoke(ctx, input, WithCheckPointID("1"), WithRunBudget(&RunBudget{MaxTotalTokens: 5}))
+	info, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"convert"}, info.BeforeNodes)
+	assert.Equal(t, 10, info.BudgetExceeded.TotalTokens)
+	assert.Equal(t, []string{"tokens"}, info.BudgetExceeded.Exceeded)
+
+	// exceeded by cost
+	_, err = r.Invoke(ctx, input, WithCheckPointID("2"), WithRunBudget(&RunBudget{
+		MaxCost: 0.5,
+		CostFunc: func(ctx context.Context, output *model.CallbackOutput) float64 {
+			return float64(output.TokenUsage.TotalTokens) * 0.1
+		},
+	}))
+	info, ok = ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"convert"}, info.BeforeNodes)
+	assert.Equal(t, []string{"cost"}, info.BudgetExceeded.Exceeded)
+	assert.InDelta(t, 1.0, info.BudgetExceeded.Cost, 1e-9)
+
+	// resume with a new budget
+	out, err = r.Invoke(ctx, input, WithCheckPointID("1"), WithRunBudget(&RunBudget{MaxTotalTokens: 5}))
+	assert.NoError(t, err)
+	assert.Equal(t, "aa", out.Content)
+
+	// exceeded by duration in stream mode
+	sr, err := r.Stream(ctx, input, WithCheckPointID("3"), WithRunBudget(&RunBudget{MaxDuration: time.Nanosecond}))
+	assert.Nil(t, sr)
+	info, ok = ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"duration"}, info.BudgetExceeded.Exceeded)
+	sr, err = r.Stream(ctx, input, WithCheckPointID("3"))
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "aa", out.Content)
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/internal"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RunEventType is the type of RunEvent.
+type RunEventType string
+
+const (
+	// RunEventNodeStart is emitted before a node starts to run.
+	RunEventNodeStart RunEventType = "node_start"
+	// RunEventNodeEnd is emitted after a node has finished successfully.
+	// In stream mode, a node is regarded as finished once it has returned its output stream.
+	RunEventNodeEnd RunEventType = "node_end"
+	// RunEventNodeError is emitted after a node has failed, including failures caused by interrupts.
+	RunEventNodeError RunEventType = "node_error"
+	// RunEventBranch is emitted after a branch has decided the successors of a node.
+	RunEventBranch RunEventType = "branch"
+	// RunEventInterrupt is emitted when a graph is interrupted.
+	RunEventInterrupt RunEventType = "interrupt"
+)
+
+// RunEvent describes what happened in a graph run.
+type RunEvent struct {
+	Type RunEventType
+	// Path is the path of the node the event belongs to, starting from the top graph.
+	// For RunEventInterrupt, Path is the path of the interrupted graph, which is empty for the top graph.
+	Path NodePath
+	Time time.Time
+
+	// Err is set for RunEventNodeError.
+	Err error
+	// BranchTargets is set for RunEventBranch, containing the successors selected by the branch.
+	BranchTargets []string
+	// InterruptInfo is set for RunEventInterrupt.
+	InterruptInfo *InterruptInfo
+}
+
+// WithRunEvents returns an option to subscribe the run events of a graph run, and the stream to receive them.
+// Events of nodes in nested graphs are emitted to the same stream, distinguished by RunEvent.Path.
+// The stream is closed when the run finishes, so it can be consumed concurrently with the run,
+// e.g. pushing execution progress to frontends through SSE.
+// The events are buffered, so a slow consumer won't block the graph run.
+// The option can only be used in one run.
+// e.g.
+//
+//	opt, events := compose.WithRunEvents()
+//	go func() {
+//		defer events.Close()
+//		for {
+//			e, err := events.Recv()
+//			if err == io.EOF {
+//				return
+//			}
+//			// push e to frontend
+//		}
+//	}()
+//	out, err := runnable.Invoke(ctx, "input", opt)
+func WithRunEvents() (Option, *schema.StreamReader[*RunEvent]) {
+	e := newRunEventEmitter()
+	sr, sw := schema.Pipe[*RunEvent](0)
+	go func() {
+		defer sw.Close()
+		closed := false
+		for {
+			event, ok := e.ch.Receive()
+			if !ok {
+				return
+			}
+			if !closed {
+				// keep draining after the reader is closed
+				closed = sw.Send(event, nil)
+			}
+		}
+	}()
+	return Option{runEventEmitter: e}, sr
+}
+
+type runEventEmitterKey struct{}
+
+type runEventEmitter struct {
+	ch     *internal.UnboundedChan[*RunEvent]
+	mu     sync.Mutex
+	closed bool
+}
+
+func newRunEventEmitter() *runEventEmitter {
+	return &runEventEmitter{ch: internal.NewUnboundedChan[*RunEvent]()}
+}
+
+func (e *runEventEmitter) emit(event *RunEvent) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return
+	}
+	event.Time = time.Now()
+	e.ch.Send(event)
+}
+
+func (e *runEventEmitter) close() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.closed = true
+	e.ch.Close()
+}
+
+func getRunEventEmitterFromOptions(opts ...Option) *runEventEmitter {
+	for _, opt := range opts {
+		if opt.runEventEmitter != nil {
+			return opt.runEventEmitter
+		}
+	}
+	return nil
+}
+
+func setRunEventEmitter(ctx context.Context, e *runEventEmitter) context.Context {
+	return context.WithValue(ctx, runEventEmitterKey{}, e)
+}
+
+func emitRunEvent(ctx context.Context, event *RunEvent) {
+	e, ok := ctx.Value(runEventEmitterKey{}).(*runEventEmitter)
+	if !ok {
+		return
+	}
+	if path, ok := getNodeKey(ctx); ok && path != nil {
+		event.Path = NodePath{path: append(append([]string{}, path.path...), event.Path.path...)}
+	}
+	e.emit(event)
+}
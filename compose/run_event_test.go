@@ -0,0 +1,115 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunEvents(t *testing.T) {
+	ctx := context.Background()
+
+	sub := NewGraph[string, string]()
+	assert.NoError(t, sub.AddLambdaNode("s", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "s", nil
+	})))
+	assert.NoError(t, sub.AddEdge(START, "s"))
+	assert.NoError(t, sub.AddEdge("s", END))
+
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "1", nil
+	})))
+	assert.NoError(t, g.AddGraphNode("2", sub))
+	assert.NoError(t, g.AddLambdaNode("3", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return "", errors.New("should not run")
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddBranch("1", NewGraphBranch(func(ctx context.Context, in string) (string, error) {
+		return "2", nil
+	}, map[string]bool{"2": true, "3": true})))
+	assert.NoError(t, g.AddEdge("2", END))
+	assert.NoError(t, g.AddEdge("3", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	collect := func(sr interface {
+		Recv() (*RunEvent, error)
+	}) chan []string {
+		ch := make(chan []string, 1)
+		go func() {
+			var events []string
+			for {
+				e, err := sr.Recv()
+				if err == io.EOF {
+					break
+				}
+				assert.NoError(t, err)
+				s := string(e.Type) + ":" + strings.Join(e.Path.GetPath(), "/")
+				if e.Type == RunEventBranch {
+					s += "->" + strings.Join(e.BranchTargets, ",")
+				}
+				events = append(events, s)
+			}
+			ch <- events
+		}()
+		return ch
+	}
+
+	opt, sr := WithRunEvents()
+	ch := collect(sr)
+	out, err := r.Invoke(ctx, "in", opt)
+	assert.NoError(t, err)
+	assert.Equal(t, "in1s", out)
+	assert.Equal(t, []string{
+		"node_start:1",
+		"node_end:1",
+		"branch:1->2",
+		"node_start:2",
+		"node_start:2/s",
+		"node_end:2/s",
+		"node_end:2",
+	}, <-ch)
+
+	// interrupt
+	r, err = g.Compile(ctx, WithInterruptAfterNodes([]string{"1"}))
+	assert.NoError(t, err)
+	opt, sr = WithRunEvents()
+	ch = collect(sr)
+	_, err = r.Stream(ctx, "in", opt)
+	_, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+	assert.Equal(t, []string{
+		"node_start:1",
+		"node_end:1",
+		"branch:1->2",
+		"interrupt:",
+	}, <-ch)
+
+	// reader closed by the consumer won't block the run
+	opt, sr = WithRunEvents()
+	sr.Close()
+	_, err = r.Invoke(ctx, "in", opt)
+	_, ok = ExtractInterruptInfo(err)
+	assert.True(t, ok)
+}
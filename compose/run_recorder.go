@@ -0,0 +1,408 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// NodeRecord is the snapshot of one execution of a node.
+type NodeRecord struct {
+	// Path is the path of the node, starting from the top graph.
+	Path []string
+	// Input and Output are the input and output of the node. In stream mode, they are materialized by concatenating the streams.
+	Input  any
+	Output any
+	// Error is the error message if the node failed.
+	Error string
+
+	StartTime time.Time
+	EndTime   time.Time
+}
+
+// RunRecorder receives the snapshot of every node execution in a graph run, including nodes in nested graphs.
+// Record may be called concurrently.
+type RunRecorder interface {
+	Record(ctx context.Context, record *NodeRecord)
+}
+
+// RecordRedactor redacts a value before it is recorded, e.g. RedactMedia.
+type RecordRedactor func(value any) any
+
+type recordOptions struct {
+	redactor RecordRedactor
+}
+
+// RecordOption is the option for WithRunRecorder.
+type RecordOption func(o *recordOptions)
+
+// WithRecordRedactor sets the redactor applied to the input and output of nodes before recording.
+func WithRecordRedactor(redactor RecordRedactor) RecordOption {
+	return func(o *recordOptions) {
+		o.redactor = redactor
+	}
+}
+
+// WithRunRecorder records the input and output of every node of the run into the recorder.
+// In stream mode, the streams are copied and materialized, and the record is delivered after the output stream is finished.
+// The records can be used to replay the graph with WithReplay.
+// e.g.
+//
+//	recorder := compose.NewInMemoryRunRecorder()
+//	out, err := runnable.Invoke(ctx, input, compose.WithRunRecorder(recorder, compose.WithRecordRedactor(compose.RedactMedia)))
+//	records := recorder.Records()
+func WithRunRecorder(recorder RunRecorder, opts ...RecordOption) Option {
+	o := &recordOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return Option{
+		runRecording: &runRecording{recorder: recorder, redactor: o.redactor},
+	}
+}
+
+// WithReplay re-executes the graph substituting the recorded outputs for the designated nodes, instead of running them.
+// Nodes not designated are executed as usual, which is useful for deterministic regression tests of prompt changes,
+// e.g. replaying the retriever and tool nodes while running the chat model node with a new prompt.
+// If a designated node runs multiple times, e.g. in a loop, the records of the node are used in order.
+// The run fails if a designated node has no more records.
+// e.g.
+//
+//	out, err := runnable.Invoke(ctx, input, compose.WithReplay(records, compose.NewNodePath("retriever")))
+func WithReplay(records []*NodeRecord, nodes ...*NodePath) Option {
+	rp := &runReplay{records: make(map[string][]*NodeRecord)}
+	selected := make(map[string]bool, len(nodes))
+	for _, n := range nodes {
+		selected[pathKey(n.path)] = true
+	}
+	for _, r := range records {
+		key := pathKey(r.Path)
+		if selected[key] {
+			rp.records[key] = append(rp.records[key], r)
+		}
+	}
+	for key := range selected {
+		if _, ok := rp.records[key]; !ok {
+			rp.records[key] = nil
+		}
+	}
+	return Option{
+		runReplay: rp,
+	}
+}
+
+// InMemoryRunRecorder is a RunRecorder keeping records in memory.
+type InMemoryRunRecorder struct {
+	mu      sync.Mutex
+	records []*NodeRecord
+}
+
+// NewInMemoryRunRecorder creates an InMemoryRunRecorder.
+func NewInMemoryRunRecorder() *InMemoryRunRecorder {
+	return &InMemoryRunRecorder{}
+}
+
+// Record implements RunRecorder.
+func (i *InMemoryRunRecorder) Record(_ context.Context, record *NodeRecord) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.records = append(i.records, record)
+}
+
+// Records returns all the records in the order they are recorded.
+func (i *InMemoryRunRecorder) Records() []*NodeRecord {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	ret := make([]*NodeRecord, len(i.records))
+	copy(ret, i.records)
+	return ret
+}
+
+const redactedMedia = "<redacted>"
+
+// RedactMedia is a RecordRedactor replacing the inline media data (base64 data and RFC-2397 data urls) in messages.
+// It supports *schema.Message, []*schema.Message, and map[string]any containing them. Other values are returned as is.
+func RedactMedia(value any) any {
+	switch v := value.(type) {
+	case *schema.Message:
+		return redactMessageMedia(v)
+	case []*schema.Message:
+		ret := make([]*schema.Message, len(v))
+		for i := range v {
+			ret[i] = redactMessageMedia(v[i])
+		}
+		return ret
+	case map[string]any:
+		ret := make(map[string]any, len(v))
+		for k, vv := range v {
+			ret[k] = RedactMedia(vv)
+		}
+		return ret
+	default:
+		return value
+	}
+}
+
+func redactMessageMedia(msg *schema.Message) *schema.Message {
+	if msg == nil {
+		return nil
+	}
+	nMsg := *msg
+	if len(msg.UserInputMultiContent) > 0 {
+		nMsg.UserInputMultiContent = make([]schema.MessageInputPart, len(msg.UserInputMultiContent))
+		for i, part := range msg.UserInputMultiContent {
+			if part.Image != nil {
+				img := *part.Image
+				img.MessagePartCommon = redactPartCommon(img.MessagePartCommon)
+				part.Image = &img
+			}
+			if part.Audio != nil {
+				audio := *part.Audio
+				audio.MessagePartCommon = redactPartCommon(audio.MessagePartCommon)
+				part.Audio = &audio
+			}
+			if part.Video != nil {
+				video := *part.Video
+				video.MessagePartCommon = redactPartCommon(video.MessagePartCommon)
+				part.Video = &video
+			}
+			if part.File != nil {
+				file := *part.File
+				file.MessagePartCommon = redactPartCommon(file.MessagePartCommon)
+				part.File = &file
+			}
+			nMsg.UserInputMultiContent[i] = part
+		}
+	}
+	if len(msg.AssistantGenMultiContent) > 0 {
+		nMsg.AssistantGenMultiContent = make([]schema.MessageOutputPart, len(msg.AssistantGenMultiContent))
+		for i, part := range msg.AssistantGenMultiContent {
+			if part.Image != nil {
+				img := *part.Image
+				img.MessagePartCommon = redactPartCommon(img.MessagePartCommon)
+				part.Image = &img
+			}
+			if part.Audio != nil {
+				audio := *part.Audio
+				audio.MessagePartCommon = redactPartCommon(audio.MessagePartCommon)
+				part.Audio = &audio
+			}
+			if part.Video != nil {
+				video := *part.Video
+				video.MessagePartCommon = redactPartCommon(video.MessagePartCommon)
+				part.Video = &video
+			}
+			nMsg.AssistantGenMultiContent[i] = part
+		}
+	}
+	if len(msg.MultiContent) > 0 {
+		nMsg.MultiContent = make([]schema.ChatMessagePart, len(msg.MultiContent))
+		for i, part := range msg.MultiContent {
+			if part.ImageURL != nil && isDataURL(part.ImageURL.URL) {
+				img := *part.ImageURL
+				img.URL = redactedMedia
+				part.ImageURL = &img
+			}
+			if part.AudioURL != nil && isDataURL(part.AudioURL.URL) {
+				audio := *part.AudioURL
+				audio.URL = redactedMedia
+				part.AudioURL = &audio
+			}
+			if part.VideoURL != nil && isDataURL(part.VideoURL.URL) {
+				video := *part.VideoURL
+				video.URL = redactedMedia
+				part.VideoURL = &video
+			}
+			if part.FileURL != nil && isDataURL(part.FileURL.URL) {
+				file := *part.FileURL
+				file.URL = redactedMedia
+				part.FileURL = &file
+			}
+			nMsg.MultiContent[i] = part
+		}
+	}
+	return &nMsg
+}
+
+func redactPartCommon(c schema.MessagePartCommon) schema.MessagePartCommon {
+	redacted := redactedMedia
+	if c.Base64Data != nil {
+		c.Base64Data = &redacted
+	}
+	if c.URL != nil && isDataURL(*c.URL) {
+		c.URL = &redacted
+	}
+	return c
+}
+
+func isDataURL(url string) bool {
+	return strings.HasPrefix(url, "data:")
+}
+
+type runRecordingKey struct{}
+type runReplayKey struct{}
+
+type runRecording struct {
+	recorder RunRecorder
+	redactor RecordRedactor
+}
+
+type runReplay struct {
+	mu      sync.Mutex
+	records map[string][]*NodeRecord
+}
+
+func pathKey(path []string) string {
+	return strings.Join(path, "\x00")
+}
+
+func setRunRecordingAndReplay(ctx context.Context, opts ...Option) context.Context {
+	for _, opt := range opts {
+		if opt.runRecording != nil {
+			ctx = context.WithValue(ctx, runRecordingKey{}, opt.runRecording)
+		}
+		if opt.runReplay != nil {
+			ctx = context.WithValue(ctx, runReplayKey{}, opt.runReplay)
+		}
+		if opt.traceReplay != nil {
+			ctx = context.WithValue(ctx, traceReplayKey{}, opt.traceReplay)
+		}
+	}
+	return ctx
+}
+
+// take returns the next record of the path, or false if the path is not designated to replay.
+func (r *runReplay) take(path []string) (*NodeRecord, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := pathKey(path)
+	records, ok := r.records[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if len(records) == 0 {
+		return nil, true, fmt.Errorf("no more recorded output to replay for node: %v", path)
+	}
+	r.records[key] = records[1:]
+	return records[0], true, nil
+}
+
+// replayTask substitutes the recorded output for the task if designated, returns false if not designated.
+func replayTask(t *task) bool {
+	rp, ok := t.ctx.Value(runReplayKey{}).(*runReplay)
+	if !ok {
+		return false
+	}
+	path, ok := getNodeKey(t.ctx)
+	if !ok || path == nil {
+		return false
+	}
+	record, ok, err := rp.take(path.path)
+	if !ok {
+		return false
+	}
+	if err != nil {
+		t.err = err
+		return true
+	}
+	if record.Error != "" {
+		t.err = errors.New(record.Error)
+		return true
+	}
+	if _, isStream := t.input.(streamReader); isStream {
+		t.input.(streamReader).close()
+		t.output, t.err = t.call.action.outputStreamConvertPair.restoreStream(record.Output)
+		return true
+	}
+	t.output = record.Output
+	return true
+}
+
+// recordTask wraps the execution of the task to record its input and output.
+func recordTask(t *task, execute func()) {
+	rr, ok := t.ctx.Value(runRecordingKey{}).(*runRecording)
+	if !ok {
+		execute()
+		return
+	}
+	record := &NodeRecord{StartTime: time.Now()}
+	if path, ok := getNodeKey(t.ctx); ok && path != nil {
+		record.Path = append([]string{}, path.path...)
+	}
+
+	var inputCh chan any
+	if sr, isStream := t.input.(streamReader); isStream {
+		srs := sr.copy(2)
+		t.input = srs[0]
+		inputCh = make(chan any, 1)
+		go func() {
+			v, err := t.call.action.inputStreamConvertPair.concatStream(srs[1])
+			if err != nil {
+				v = nil
+			}
+			inputCh <- v
+		}()
+	} else {
+		record.Input = t.input
+	}
+
+	execute()
+
+	finish := func() {
+		record.EndTime = time.Now()
+		if inputCh != nil {
+			record.Input = <-inputCh
+		}
+		if rr.redactor != nil {
+			record.Input = rr.redactor(record.Input)
+			record.Output = rr.redactor(record.Output)
+		}
+		rr.recorder.Record(t.ctx, record)
+	}
+
+	if t.err != nil {
+		record.Error = t.err.Error()
+		if inputCh != nil {
+			go finish()
+		} else {
+			finish()
+		}
+		return
+	}
+	if sr, isStream := t.output.(streamReader); isStream {
+		srs := sr.copy(2)
+		t.output = srs[0]
+		go func() {
+			v, err := t.call.action.outputStreamConvertPair.concatStream(srs[1])
+			if err != nil {
+				record.Error = err.Error()
+			}
+			record.Output = v
+			finish()
+		}()
+		return
+	}
+	record.Output = t.output
+	finish()
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestRunRecorderAndReplay(t *testing.T) {
+	ctx := context.Background()
+
+	fetchCount := 0
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("fetch", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		fetchCount++
+		return fmt.Sprintf("%s-fetch%d", input, fetchCount), nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("gen", StreamableLambda(func(ctx context.Context, input string) (*schema.StreamReader[string], error) {
+		return schema.StreamReaderFromArray([]string{input, "-gen"}), nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "fetch"))
+	assert.NoError(t, g.AddEdge("fetch", "gen"))
+	assert.NoError(t, g.AddEdge("gen", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	recorder := NewInMemoryRunRecorder()
+	out, err := r.Invoke(ctx, "in", WithRunRecorder(recorder))
+	assert.NoError(t, err)
+	assert.Equal(t, "in-fetch1-gen", out)
+	records := recorder.Records()
+	assert.Len(t, records, 2)
+	assert.Equal(t, []string{"fetch"}, records[0].Path)
+	assert.Equal(t, "in", records[0].Input)
+	assert.Equal(t, "in-fetch1", records[0].Output)
+	assert.Equal(t, []string{"gen"}, records[1].Path)
+	assert.Equal(t, "in-fetch1-gen", records[1].Output)
+
+	// replay fetch, run gen as usual
+	out, err = r.Invoke(ctx, "in", WithReplay(records, NewNodePath("fetch")))
+	assert.NoError(t, err)
+	assert.Equal(t, "in-fetch1-gen", out)
+	assert.Equal(t, 1, fetchCount)
+
+	// records are used up
+	opt := WithReplay(records, NewNodePath("fetch"))
+	_, err = r.Invoke(ctx, "in", opt)
+	assert.NoError(t, err)
+	_, err = r.Invoke(ctx, "in", opt)
+	assert.ErrorContains(t, err, "no more recorded output to replay")
+
+	// record and replay in stream mode
+	recorder = NewInMemoryRunRecorder()
+	sr, err := r.Stream(ctx, "in", WithRunRecorder(recorder), WithReplay(records, NewNodePath("fetch")))
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "in-fetch1-gen", out)
+	assert.Equal(t, 1, fetchCount)
+	assert.Eventually(t, func() bool {
+		return len(recorder.Records()) == 2
+	}, time.Second, 10*time.Millisecond)
+	records = recorder.Records()
+	sort.Slice(records, func(i, j int) bool {
+		return strings.Join(records[i].Path, "/") < strings.Join(records[j].Path, "/")
+	})
+	assert.Equal(t, "in", records[0].Input)
+	assert.Equal(t, "in-fetch1", records[0].Output)
+	assert.Equal(t, "in-fetch1", records[1].Input)
+	assert.Equal(t, "in-fetch1-gen", records[1].Output)
+}
+
+func TestRedactMedia(t *testing.T) {
+	data := "aGVsbG8="
+	url := "https://example.com/a.png"
+	dataURL := "data:image/png;base64,aGVsbG8="
+	msg := &schema.Message{
+		Role: schema.User,
+		UserInputMultiContent: []schema.MessageInputPart{
+			{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{MessagePartCommon: schema.MessagePartCommon{Base64Data: &data}}},
+			{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{MessagePartCommon: schema.MessagePartCommon{URL: &url}}},
+		},
+		MultiContent: []schema.ChatMessagePart{
+			{Type: schema.ChatMessagePartTypeImageURL, ImageURL: &schema.ChatMessageImageURL{URL: dataURL}},
+		},
+	}
+
+	redacted := RedactMedia([]*schema.Message{msg}).([]*schema.Message)[0]
+	assert.Equal(t, redactedMedia, *redacted.UserInputMultiContent[0].Image.Base64Data)
+	assert.Equal(t, url, *redacted.UserInputMultiContent[1].Image.URL)
+	assert.Equal(t, redactedMedia, redacted.MultiContent[0].ImageURL.URL)
+	// the original message is not modified
+	assert.Equal(t, data, *msg.UserInputMultiContent[0].Image.Base64Data)
+	assert.Equal(t, dataURL, msg.MultiContent[0].ImageURL.URL)
+
+	assert.Equal(t, "text", RedactMedia("text"))
+}
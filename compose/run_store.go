@@ -0,0 +1,263 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// RunStatus is the status of a persisted run, shared by the graph runs and the agent runs of adk.
+type RunStatus string
+
+const (
+	// RunStatusRunning means the run is in flight, or the process exited before the run ended.
+	RunStatusRunning RunStatus = "running"
+	// RunStatusInterrupted means the run is interrupted and can be resumed from its checkpoint.
+	RunStatusInterrupted RunStatus = "interrupted"
+	// RunStatusCompleted means the run ended without error.
+	RunStatusCompleted RunStatus = "completed"
+	// RunStatusFailed means the run ended with an error, for an agent run, the last event carried an error.
+	RunStatusFailed RunStatus = "failed"
+)
+
+// GraphRunRecord is a persisted graph run.
+type GraphRunRecord struct {
+	ID     string    `json:"id"`
+	Status RunStatus `json:"status"`
+	// Input is the input of the first run, nil if the input is a stream.
+	Input any `json:"input,omitempty"`
+	// Output is the output of the run if completed, nil if the output is a stream.
+	Output any `json:"output,omitempty"`
+	// Err is the error message of the run if failed.
+	Err string `json:"err,omitempty"`
+	// CheckPointID is the checkpoint designated by WithCheckPointID, used to resume an interrupted run.
+	CheckPointID string `json:"check_point_id,omitempty"`
+	// Interrupts are the interrupts of the run, in the order they happened.
+	Interrupts []*GraphRunInterruptRecord `json:"interrupts,omitempty"`
+	// Resumes is the number of times the run has been run again with the same run id, e.g. resumed from its checkpoint.
+	Resumes   int       `json:"resumes,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GraphRunInterruptRecord is an interrupt of a persisted graph run, see InterruptInfo.
+type GraphRunInterruptRecord struct {
+	BeforeNodes []string `json:"before_nodes,omitempty"`
+	AfterNodes  []string `json:"after_nodes,omitempty"`
+	RerunNodes  []string `json:"rerun_nodes,omitempty"`
+	// SubGraphs are the keys of the sub graph nodes interrupted.
+	SubGraphs []string `json:"sub_graphs,omitempty"`
+	// BudgetExceeded is set if the run is interrupted by the budget set by WithRunBudget.
+	BudgetExceeded *RunBudgetUsage `json:"budget_exceeded,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// GraphRunStore persists the graph runs designated by WithRunStore, as an audit trail of the runs.
+// The durable implementations are responsible for serializing the input and the output of the runs, e.g. to JSON.
+type GraphRunStore interface {
+	// SaveRun creates or replaces the run.
+	SaveRun(ctx context.Context, run *GraphRunRecord) error
+	GetRun(ctx context.Context, runID string) (*GraphRunRecord, bool, error)
+	// ListRuns returns the runs matching the query, the latest created first.
+	ListRuns(ctx context.Context, query *GraphRunQuery) ([]*GraphRunRecord, error)
+}
+
+// GraphRunQuery filters the runs of GraphRunStore.ListRuns, zero value of each field matches all the runs.
+type GraphRunQuery struct {
+	// Statuses matches the runs in any of the statuses.
+	Statuses []RunStatus
+	// CreatedAfter and CreatedBefore match the runs created in the time range.
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	// Limit is the max number of the runs returned.
+	Limit int
+}
+
+// Match reports whether the run matches the query, for GraphRunStore implementations.
+func (q *GraphRunQuery) Match(run *GraphRunRecord) bool {
+	if q == nil {
+		return true
+	}
+	if len(q.Statuses) > 0 {
+		matched := false
+		for _, s := range q.Statuses {
+			if s == run.Status {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if !q.CreatedAfter.IsZero() && run.CreatedAt.Before(q.CreatedAfter) {
+		return false
+	}
+	if !q.CreatedBefore.IsZero() && !run.CreatedAt.Before(q.CreatedBefore) {
+		return false
+	}
+	return true
+}
+
+// WithRunStore persists the run to the store with the run id, including the input, the output or the error,
+// and each interrupt of the run. Running a graph again with the same run id, e.g. to resume it from its checkpoint,
+// updates the same run and counts a resume. The option takes effect on the top graph only.
+// For a stream output, the run is completed once the stream is returned. A failure of persisting the run fails the run.
+// e.g.
+//
+//	out, err := runnable.Invoke(ctx, input, compose.WithCheckPointID(id), compose.WithRunStore(store, id))
+//	// later, e.g. in an admin console
+//	runs, err := store.ListRuns(ctx, &compose.GraphRunQuery{Statuses: []compose.RunStatus{compose.RunStatusInterrupted}})
+func WithRunStore(store GraphRunStore, runID string) Option {
+	return Option{
+		runStore: &runStoreOption{store: store, runID: runID},
+	}
+}
+
+type runStoreOption struct {
+	store GraphRunStore
+	runID string
+}
+
+func getRunStoreFromOptions(opts ...Option) *runStoreOption {
+	for _, opt := range opts {
+		if opt.runStore != nil && len(opt.paths) == 0 {
+			return opt.runStore
+		}
+	}
+	return nil
+}
+
+// start persists the run as running, creating it if absent.
+func (o *runStoreOption) start(ctx context.Context, input any, isStream bool, checkPointID *string) (*GraphRunRecord, error) {
+	run, existed, err := o.store.GetRun(ctx, o.runID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get run: %w", err)
+	}
+	now := time.Now()
+	if existed {
+		run.Resumes++
+		run.Err = ""
+		run.Output = nil
+	} else {
+		run = &GraphRunRecord{ID: o.runID, CreatedAt: now}
+		if !isStream {
+			run.Input = input
+		}
+	}
+	run.Status = RunStatusRunning
+	run.UpdatedAt = now
+	if checkPointID != nil {
+		run.CheckPointID = *checkPointID
+	}
+	if err = o.store.SaveRun(ctx, run); err != nil {
+		return nil, fmt.Errorf("failed to save run: %w", err)
+	}
+	return run, nil
+}
+
+// finish persists the result of the run.
+func (o *runStoreOption) finish(ctx context.Context, run *GraphRunRecord, output any, isStream bool, runErr error) error {
+	now := time.Now()
+	if info, ok := ExtractInterruptInfo(runErr); ok {
+		run.Status = RunStatusInterrupted
+		ir := &GraphRunInterruptRecord{
+			BeforeNodes:    info.BeforeNodes,
+			AfterNodes:     info.AfterNodes,
+			RerunNodes:     info.RerunNodes,
+			BudgetExceeded: info.BudgetExceeded,
+			CreatedAt:      now,
+		}
+		for key := range info.SubGraphs {
+			ir.SubGraphs = append(ir.SubGraphs, key)
+		}
+		sort.Strings(ir.SubGraphs)
+		run.Interrupts = append(run.Interrupts, ir)
+	} else if runErr != nil {
+		run.Status = RunStatusFailed
+		run.Err = runErr.Error()
+	} else {
+		run.Status = RunStatusCompleted
+		if !isStream {
+			run.Output = output
+		}
+	}
+	run.UpdatedAt = now
+	if err := o.store.SaveRun(ctx, run); err != nil {
+		return fmt.Errorf("failed to save run: %w", err)
+	}
+	return nil
+}
+
+// NewInMemoryGraphRunStore creates a GraphRunStore which keeps the runs in memory, mostly for tests and demos.
+func NewInMemoryGraphRunStore() GraphRunStore {
+	return &inMemoryGraphRunStore{runs: make(map[string]*GraphRunRecord)}
+}
+
+type inMemoryGraphRunStore struct {
+	mu   sync.RWMutex
+	runs map[string]*GraphRunRecord
+}
+
+func (s *inMemoryGraphRunStore) SaveRun(_ context.Context, run *GraphRunRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.runs[run.ID] = copyGraphRunRecord(run)
+	return nil
+}
+
+func (s *inMemoryGraphRunStore) GetRun(_ context.Context, runID string) (*GraphRunRecord, bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	run, ok := s.runs[runID]
+	if !ok {
+		return nil, false, nil
+	}
+	return copyGraphRunRecord(run), true, nil
+}
+
+func (s *inMemoryGraphRunStore) ListRuns(_ context.Context, query *GraphRunQuery) ([]*GraphRunRecord, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	runs := make([]*GraphRunRecord, 0, len(s.runs))
+	for _, run := range s.runs {
+		if query.Match(run) {
+			runs = append(runs, copyGraphRunRecord(run))
+		}
+	}
+	sort.SliceStable(runs, func(i, j int) bool {
+		return runs[i].CreatedAt.After(runs[j].CreatedAt)
+	})
+	if query != nil && query.Limit > 0 && len(runs) > query.Limit {
+		runs = runs[:query.Limit]
+	}
+	return runs, nil
+}
+
+func copyGraphRunRecord(run *GraphRunRecord) *GraphRunRecord {
+	cp := *run
+	cp.Interrupts = make([]*GraphRunInterruptRecord, len(run.Interrupts))
+	copy(cp.Interrupts, run.Interrupts)
+	return &cp
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStore(t *testing.T) {
+	ctx := context.Background()
+	g := NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("1", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		return input + "1", nil
+	})))
+	assert.NoError(t, g.AddLambdaNode("2", InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		if input == "fail1" {
+			return "", errors.New("node fail")
+		}
+		return input + "2", nil
+	})))
+	assert.NoError(t, g.AddEdge(START, "1"))
+	assert.NoError(t, g.AddEdge("1", "2"))
+	assert.NoError(t, g.AddEdge("2", END))
+	r, err := g.Compile(ctx, WithCheckPointStore(newInMemoryStore()), WithInterruptBeforeNodes([]string{"2"}))
+	assert.NoError(t, err)
+
+	store := NewInMemoryGraphRunStore()
+	_, err = r.Invoke(ctx, "start", WithCheckPointID("cp1"), WithRunStore(store, "run1"))
+	_, ok := ExtractInterruptInfo(err)
+	assert.True(t, ok)
+
+	run, existed, err := store.GetRun(ctx, "run1")
+	assert.NoError(t, err)
+	assert.True(t, existed)
+	assert.Equal(t, RunStatusInterrupted, run.Status)
+	assert.Equal(t, "start", run.Input)
+	assert.Equal(t, "cp1", run.CheckPointID)
+	assert.Len(t, run.Interrupts, 1)
+	assert.Equal(t, []string{"2"}, run.Interrupts[0].BeforeNodes)
+
+	out, err := r.Invoke(ctx, "start", WithCheckPointID("cp1"), WithRunStore(store, "run1"))
+	assert.NoError(t, err)
+	assert.Equal(t, "start12", out)
+	run, _, err = store.GetRun(ctx, "run1")
+	assert.NoError(t, err)
+	assert.Equal(t, RunStatusCompleted, run.Status)
+	assert.Equal(t, "start12", run.Output)
+	assert.Equal(t, 1, run.Resumes)
+	assert.Len(t, run.Interrupts, 1)
+
+	_, err = r.Invoke(ctx, "fail", WithCheckPointID("cp2"), WithRunStore(store, "run2"))
+	assert.Error(t, err)
+	_, err = r.Invoke(ctx, "fail", WithCheckPointID("cp2"), WithRunStore(store, "run2"))
+	assert.Error(t, err)
+	run, _, err = store.GetRun(ctx, "run2")
+	assert.NoError(t, err)
+	assert.Equal(t, RunStatusFailed, run.Status)
+	assert.Contains(t, run.Err, "node fail")
+
+	runs, err := store.ListRuns(ctx, nil)
+	assert.NoError(t, err)
+	assert.Len(t, runs, 2)
+	runs, err = store.ListRuns(ctx, &GraphRunQuery{Statuses: []RunStatus{RunStatusFailed, RunStatusInterrupted}})
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, "run2", runs[0].ID)
+	runs, err = store.ListRuns(ctx, &GraphRunQuery{Limit: 1})
+	assert.NoError(t, err)
+	assert.Len(t, runs, 1)
+}
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/internal/schemavalidate"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ToolArgumentsError describes the invalid arguments of a tool call found by ToolsNodeConfig.ValidateToolArguments.
+type ToolArgumentsError struct {
+	// Name is the name of the tool called.
+	Name string
+	// CallID is the id of the tool call.
+	CallID string
+	// Arguments are the arguments of the tool call, after the repair if ToolsNodeConfig.RepairToolArguments is set.
+	Arguments string
+	// Violations are the problems found in the arguments, e.g. `$.city: missing required property "city"`.
+	Violations []string
+}
+
+func (e *ToolArgumentsError) Error() string {
+	return fmt.Sprintf("invalid arguments of tool[name:%s id:%s]: %s", e.Name, e.CallID, strings.Join(e.Violations, "; "))
+}
+
+func (e *ToolArgumentsError) ErrorCategory() components.ErrorCategory {
+	return components.ErrorCategoryInvalidInput
+}
+
+// defaultInvalidToolArgumentsHandler tells the model what is wrong with the arguments, so that it can call the tool again with corrected ones.
+func defaultInvalidToolArgumentsHandler(_ context.Context, e *ToolArgumentsError) (string, error) {
+	result, err := json.Marshal(struct {
+		Error      string   `json:"error"`
+		Tool       string   `json:"tool"`
+		Violations []string `json:"violations"`
+		Hint       string   `json:"hint"`
+	}{
+		Error:      "invalid_arguments",
+		Tool:       e.Name,
+		Violations: e.Violations,
+		Hint:       "the tool was not called, fix the arguments according to the parameters of the tool and call it again",
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(result), nil
+}
+
+// checkToolArguments repairs and validates the arguments of a tool call according to the config of the ToolsNode.
+// It returns the arguments to call the tool with, or a ToolArgumentsError if they don't conform to the parameters of the tool.
+func (tn *ToolsNode) checkToolArguments(ctx context.Context, info *schema.ToolInfo, callID, arguments string) (string, *ToolArgumentsError, error) {
+	if tn.repairToolArguments {
+		if repaired, ok := repairJSON(arguments); ok {
+			arguments = repaired
+		}
+	}
+	if !tn.validateToolArguments || info == nil || info.ParamsOneOf == nil {
+		return arguments, nil, nil
+	}
+
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to convert the parameters of tool[%s] to json schema: %w", info.Name, err)
+	}
+	if js == nil {
+		return arguments, nil, nil
+	}
+
+	violations := schemavalidate.Validate(js, arguments)
+	if len(violations) == 0 {
+		return arguments, nil, nil
+	}
+	argErr := &ToolArgumentsError{
+		Name:       info.Name,
+		CallID:     callID,
+		Arguments:  arguments,
+		Violations: make([]string, len(violations)),
+	}
+	for i, v := range violations {
+		argErr.Violations[i] = v.Error()
+	}
+	return arguments, argErr, nil
+}
+
+func newInvalidArgumentsTask(argErr *ToolArgumentsError, handler func(ctx context.Context, err *ToolArgumentsError) (string, error)) toolCallTask {
+	endpoint := func(ctx context.Context, _ *ToolInput) (*ToolOutput, error) {
+		result, err := handler(ctx, argErr)
+		if err != nil {
+			return nil, err
+		}
+		return &ToolOutput{
+			Result: result,
+		}, nil
+	}
+	return toolCallTask{
+		endpoint:       endpoint,
+		streamEndpoint: invokableToStreamable(endpoint),
+		meta: &executorMeta{
+			component:                  components.ComponentOfTool,
+			isComponentCallbackEnabled: false,
+			componentImplType:          "InvalidArguments",
+		},
+		name:   argErr.Name,
+		arg:    argErr.Arguments,
+		callID: argErr.CallID,
+	}
+}
+
+// repairJSON fixes the common defects of the JSON generated by models:
+// markdown code fences, empty arguments, trailing commas, and objects, arrays or strings left open by a truncated output.
+// It returns false if the result is still not valid JSON.
+func repairJSON(s string) (string, bool) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "```") {
+		s = strings.TrimSuffix(strings.TrimPrefix(s, "```"), "```")
+		if i := strings.Index(s, "\n"); i >= 0 {
+			// the first line is the fence, with an optional language tag
+			s = s[i+1:]
+		}
+		s = strings.TrimSpace(s)
+	}
+	if s == "" {
+		return "{}", true
+	}
+	if json.Valid([]byte(s)) {
+		return s, true
+	}
+
+	buf := make([]byte, 0, len(s)+8)
+	// closers are the brackets to close the open objects and arrays, the innermost one last
+	var closers []byte
+	inString, escaped := false, false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+			buf = append(buf, c)
+			continue
+		}
+		switch c {
+		case '"':
+			inString = true
+		case '{':
+			closers = append(closers, '}')
+		case '[':
+			closers = append(closers, ']')
+		case '}', ']':
+			buf = trimTrailingComma(buf)
+			if len(closers) > 0 && closers[len(closers)-1] == c {
+				closers = closers[:len(closers)-1]
+			}
+		}
+		buf = append(buf, c)
+	}
+
+	if inString {
+		if escaped {
+			buf = buf[:len(buf)-1]
+		}
+		buf = append(buf, '"')
+	}
+	for i := len(closers) - 1; i >= 0; i-- {
+		buf = trimTrailingComma(buf)
+		if len(buf) > 0 && buf[len(buf)-1] == ':' {
+			buf = append(buf, "null"...)
+		}
+		buf = append(buf, closers[i])
+	}
+
+	if !json.Valid(buf) {
+		return s, false
+	}
+	return string(buf), true
+}
+
+// trimTrailingComma removes the trailing whitespaces, and the comma before them if any.
+func trimTrailingComma(buf []byte) []byte {
+	return bytes.TrimSuffix(bytes.TrimRight(buf, " \t\r\n"), []byte(","))
+}
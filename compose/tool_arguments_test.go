@@ -0,0 +1,149 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestRepairJSON(t *testing.T) {
+	tests := []struct {
+		in, want string
+		ok       bool
+	}{
+		{in: `{"a": 1}`, want: `{"a": 1}`, ok: true},
+		{in: "", want: `{}`, ok: true},
+		{in: "```json\n{\"a\": 1}\n```", want: `{"a": 1}`, ok: true},
+		{in: `{"a": 1,}`, want: `{"a": 1}`, ok: true},
+		{in: `{"a": [1, 2, ], "b": "x,}"}`, want: `{"a": [1, 2], "b": "x,}"}`, ok: true},
+		{in: `{"a": {"b": [1, 2`, want: `{"a": {"b": [1, 2]}}`, ok: true},
+		{in: `{"a": "hello`, want: `{"a": "hello"}`, ok: true},
+		{in: `{"a": "hello\`, want: `{"a": "hello"}`, ok: true},
+		{in: `{"a": 1, "b":`, want: `{"a": 1, "b":null}`, ok: true},
+		{in: `{"a": 1, `, want: `{"a": 1}`, ok: true},
+		{in: `{"a": tru`, want: `{"a": tru`, ok: false},
+		{in: `not json`, want: `not json`, ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := repairJSON(tt.in)
+		assert.Equal(t, tt.ok, ok, tt.in)
+		assert.Equal(t, tt.want, got, tt.in)
+	}
+}
+
+func TestToolsNodeValidateToolArguments(t *testing.T) {
+	ctx := context.Background()
+
+	type weatherRequest struct {
+		City string `json:"city" jsonschema:"required"`
+		Unit string `json:"unit,omitempty" jsonschema:"enum=celsius,enum=fahrenheit"`
+	}
+	var called []weatherRequest
+	weather, err := utils.InferTool("weather", "get the weather of a city", func(ctx context.Context, req weatherRequest) (string, error) {
+		called = append(called, req)
+		return "sunny in " + req.City, nil
+	})
+	assert.NoError(t, err)
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "weather", Arguments: `{"city": "Paris", "unit": "celsius",}`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "weather", Arguments: `{"unit": "kelvin"}`}},
+	})
+
+	t.Run("return violations to the model", func(t *testing.T) {
+		called = nil
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:                 []tool.BaseTool{weather},
+			ValidateToolArguments: true,
+			RepairToolArguments:   true,
+			ExecuteSequentially:   true,
+		})
+		assert.NoError(t, err)
+
+		messages, err := tn.Invoke(ctx, input)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, "sunny in Paris", messages[0].Content)
+		assert.Equal(t, "2", messages[1].ToolCallID)
+		assert.Equal(t, "weather", messages[1].ToolName)
+		assert.Contains(t, messages[1].Content, `"error":"invalid_arguments"`)
+		assert.Contains(t, messages[1].Content, `missing required property \"city\"`)
+		assert.Contains(t, messages[1].Content, `$.unit: value \"kelvin\" is not one of`)
+		assert.Equal(t, []weatherRequest{{City: "Paris", Unit: "celsius"}}, called)
+
+		sr, err := tn.Stream(ctx, input)
+		assert.NoError(t, err)
+		var chunks [][]*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+		messages, err = schema.ConcatMessageArray(chunks)
+		assert.NoError(t, err)
+		assert.Contains(t, messages[1].Content, `"error":"invalid_arguments"`)
+	})
+
+	t.Run("custom handler", func(t *testing.T) {
+		called = nil
+		var argErr *ToolArgumentsError
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:                 []tool.BaseTool{weather},
+			ValidateToolArguments: true,
+			InvalidToolArgumentsHandler: func(ctx context.Context, err *ToolArgumentsError) (string, error) {
+				return "", err
+			},
+		})
+		assert.NoError(t, err)
+
+		_, err = tn.Invoke(ctx, input)
+		assert.True(t, errors.As(err, &argErr))
+		// the trailing comma isn't repaired, so the first call fails as well
+		assert.Equal(t, "1", argErr.CallID)
+		assert.Equal(t, "weather", argErr.Name)
+		assert.Len(t, argErr.Violations, 1)
+		assert.Contains(t, argErr.Violations[0], "invalid JSON")
+		assert.Empty(t, called)
+	})
+
+	t.Run("repair only", func(t *testing.T) {
+		called = nil
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:               []tool.BaseTool{weather},
+			RepairToolArguments: true,
+			ExecuteSequentially: true,
+		})
+		assert.NoError(t, err)
+
+		messages, err := tn.Invoke(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "sunny in Paris", messages[0].Content)
+		assert.Equal(t, "sunny in ", messages[1].Content)
+	})
+}
@@ -0,0 +1,56 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// ToolFilter reports whether the tool is available in a call, see WithToolFilter.
+type ToolFilter func(ctx context.Context, info *schema.ToolInfo) bool
+
+// WithToolFilter filters the tools of the ToolsNode for a single call, e.g. by the entitlements of the user,
+// without rebuilding the ToolsNode or recompiling the graph. It's applied to the tools set by WithToolList as well.
+// The calls to the tools filtered out are handled as the calls to unknown tools, see ToolsNodeConfig.UnknownToolsHandler.
+// Note that the option doesn't change the ToolInfos bound to the chat model, which should be filtered by model.WithTools,
+// or use react.WithToolFilter for the react agent, which filters both.
+func WithToolFilter(filter ToolFilter) ToolsNodeOption {
+	return func(o *toolsNodeOptions) {
+		o.toolFilter = filter
+	}
+}
+
+// filter returns the tuple with the tools passing the filter only.
+// The endpoints are shared with the original tuple, as the tools are looked up by indexes.
+func (t *toolsTuple) filter(ctx context.Context, filter ToolFilter) *toolsTuple {
+	if filter == nil {
+		return t
+	}
+
+	indexes := make(map[string]int, len(t.indexes))
+	for name, index := range t.indexes {
+		if filter(ctx, t.infos[index]) {
+			indexes[name] = index
+		}
+	}
+
+	nt := *t
+	nt.indexes = indexes
+	return &nt
+}
@@ -0,0 +1,62 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestWithToolFilter(t *testing.T) {
+	ctx := context.Background()
+
+	var tools []tool.BaseTool
+	for _, name := range []string{"search", "refund"} {
+		name := name
+		tools = append(tools, utils.NewTool(&schema.ToolInfo{Name: name}, func(ctx context.Context, _ map[string]any) (string, error) {
+			return name + " done", nil
+		}))
+	}
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: tools})
+	assert.NoError(t, err)
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "refund", Arguments: "{}"}},
+	})
+	onlySearch := WithToolFilter(func(ctx context.Context, info *schema.ToolInfo) bool {
+		return info.Name == "search"
+	})
+
+	_, err = tn.Invoke(ctx, input, onlySearch)
+	assert.ErrorContains(t, err, "refund")
+	_, err = tn.Stream(ctx, input, onlySearch)
+	assert.ErrorContains(t, err, "refund")
+
+	// the filter applies to the tools of WithToolList as well
+	_, err = tn.Invoke(ctx, input, WithToolList(tools...), onlySearch)
+	assert.ErrorContains(t, err, "refund")
+
+	messages, err := tn.Invoke(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, "refund done", messages[0].Content)
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type hiddenToolArguments struct {
+	fields map[string]any
+	// toolNames is nil if the fields are injected into all the tools
+	toolNames map[string]bool
+}
+
+// WithHiddenToolArguments merges the fields into the arguments of the tool calls before the tools run,
+// so that per-request values, e.g. the user id or a session handle, reach the tools without being generated by or shown to the model.
+// The fields override the ones of the same names generated by the model, so the model can't forge them.
+// Only the tools in toolNames are affected, or all the tools if toolNames is empty.
+// The option can be used multiple times, the later one wins for the same field.
+// Declare the fields in the arguments struct with the `jsonschema:"-"` tag, to keep them out of the parameters of the tool.
+// Notice that the merged arguments are passed to the tool callbacks as well,
+// use WithToolValues for secrets, e.g. auth tokens, which must not be logged.
+// e.g.
+//
+//	type searchOrdersInput struct {
+//		Keyword string `json:"keyword"`
+//		UserID  string `json:"user_id" jsonschema:"-"`
+//	}
+//
+//	out, err := agent.Generate(ctx, input, agent.WithComposeOptions(compose.WithToolsNodeOption(
+//		compose.WithHiddenToolArguments(map[string]any{"user_id": userID}, "search_orders"))))
+func WithHiddenToolArguments(fields map[string]any, toolNames ...string) ToolsNodeOption {
+	h := &hiddenToolArguments{fields: fields}
+	if len(toolNames) > 0 {
+		h.toolNames = make(map[string]bool, len(toolNames))
+		for _, name := range toolNames {
+			h.toolNames[name] = true
+		}
+	}
+	return func(o *toolsNodeOptions) {
+		o.hiddenArguments = append(o.hiddenArguments, h)
+	}
+}
+
+// WithToolValues makes the values available to the tools called by this run of the ToolsNode, which get them by GetToolValue.
+// Unlike WithHiddenToolArguments, the values are not part of the arguments, so they never appear in the callbacks or messages.
+// e.g.
+//
+//	runnable.Invoke(ctx, input, compose.WithToolsNodeOption(compose.WithToolValues(map[string]any{"auth_token": token})))
+//
+//	// in the tool
+//	token, _ := compose.GetToolValue(ctx, "auth_token")
+func WithToolValues(values map[string]any) ToolsNodeOption {
+	return func(o *toolsNodeOptions) {
+		if o.toolValues == nil {
+			o.toolValues = make(map[string]any, len(values))
+		}
+		for k, v := range values {
+			o.toolValues[k] = v
+		}
+	}
+}
+
+type toolValuesKey struct{}
+
+// GetToolValue returns the value set by WithToolValues for the running tool.
+func GetToolValue(ctx context.Context, key string) (any, bool) {
+	values, _ := ctx.Value(toolValuesKey{}).(map[string]any)
+	v, ok := values[key]
+	return v, ok
+}
+
+func withToolValues(ctx context.Context, values map[string]any) context.Context {
+	if len(values) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, toolValuesKey{}, values)
+}
+
+// injectToolArguments merges the hidden fields for the tool into the arguments, which must be a JSON object.
+func injectToolArguments(name, arguments string, hidden []*hiddenToolArguments) (string, error) {
+	var obj map[string]json.RawMessage
+	for _, h := range hidden {
+		if h.toolNames != nil && !h.toolNames[name] {
+			continue
+		}
+		if obj == nil {
+			obj = make(map[string]json.RawMessage)
+			if strings.TrimSpace(arguments) != "" {
+				if err := json.Unmarshal([]byte(arguments), &obj); err != nil {
+					return "", fmt.Errorf("arguments are not a JSON object: %w", err)
+				}
+				if obj == nil {
+					// the arguments are null
+					obj = make(map[string]json.RawMessage)
+				}
+			}
+		}
+		for k, v := range h.fields {
+			raw, err := json.Marshal(v)
+			if err != nil {
+				return "", fmt.Errorf("failed to marshal hidden argument %s: %w", k, err)
+			}
+			obj[k] = raw
+		}
+	}
+	if obj == nil {
+		return arguments, nil
+	}
+
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,120 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestToolsNodeHiddenArguments(t *testing.T) {
+	ctx := context.Background()
+
+	type searchInput struct {
+		Keyword string `json:"keyword"`
+		UserID  string `json:"user_id" jsonschema:"-"`
+	}
+	search, err := utils.InferTool("search", "search orders", func(ctx context.Context, in *searchInput) (string, error) {
+		token, _ := GetToolValue(ctx, "token")
+		return in.UserID + "/" + in.Keyword + "/" + token.(string), nil
+	})
+	assert.NoError(t, err)
+	other, err := utils.InferTool("other", "other tool", func(ctx context.Context, in map[string]any) (string, error) {
+		_, hasUserID := in["user_id"]
+		if hasUserID {
+			return "leaked", nil
+		}
+		return "ok", nil
+	})
+	assert.NoError(t, err)
+
+	info, err := search.Info(ctx)
+	assert.NoError(t, err)
+	js, err := info.ParamsOneOf.ToJSONSchema()
+	assert.NoError(t, err)
+	_, visible := js.Properties.Get("user_id")
+	assert.False(t, visible)
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{search, other}})
+	assert.NoError(t, err)
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		// the model tries to forge the user id
+		{ID: "1", Function: schema.FunctionCall{Name: "search", Arguments: `{"keyword": "shoes", "user_id": "admin"}`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "other", Arguments: `{}`}},
+	})
+	opts := []ToolsNodeOption{
+		WithHiddenToolArguments(map[string]any{"user_id": "u1"}, "search"),
+		WithToolValues(map[string]any{"token": "t1"}),
+	}
+
+	messages, err := tn.Invoke(ctx, input, opts...)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1/shoes/t1", messages[0].Content)
+	assert.Equal(t, "ok", messages[1].Content)
+
+	sr, err := tn.Stream(ctx, input, opts...)
+	assert.NoError(t, err)
+	var chunks [][]*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	messages, err = schema.ConcatMessageArray(chunks)
+	assert.NoError(t, err)
+	assert.Equal(t, "u1/shoes/t1", messages[0].Content)
+
+	// the fields are injected into all the tools without tool names
+	messages, err = tn.Invoke(ctx, input, WithHiddenToolArguments(map[string]any{"user_id": "u2"}), WithToolValues(map[string]any{"token": "t2"}))
+	assert.NoError(t, err)
+	assert.Equal(t, "u2/shoes/t2", messages[0].Content)
+	assert.Equal(t, "leaked", messages[1].Content)
+
+	_, err = tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "search", Arguments: `["shoes"]`}},
+	}), opts...)
+	assert.ErrorContains(t, err, "arguments are not a JSON object")
+}
+
+func TestInjectToolArguments(t *testing.T) {
+	hidden := []*hiddenToolArguments{
+		{fields: map[string]any{"a": 1, "b": "x"}},
+		{fields: map[string]any{"b": "y"}, toolNames: map[string]bool{"t1": true}},
+	}
+	args, err := injectToolArguments("t1", `{"c": true, "a": 0}`, hidden)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1,"b":"y","c":true}`, args)
+
+	args, err = injectToolArguments("t2", `null`, hidden)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1,"b":"x"}`, args)
+
+	args, err = injectToolArguments("t2", `{"c": 1}`, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"c": 1}`, args)
+}
@@ -34,6 +34,10 @@ type toolsNodeOptions struct {
 	ToolOptions   []tool.Option
 	ToolList      []tool.BaseTool
 	executedTools map[string]string
+
+	hiddenArguments []*hiddenToolArguments
+	toolValues      map[string]any
+	toolFilter      ToolFilter
 }
 
 // ToolsNodeOption is the option func type for ToolsNode.
@@ -64,6 +68,9 @@ func withExecutedTools(executedTools map[string]string) ToolsNodeOption {
 //
 //	Invoke(ctx context.Context, input *schema.Message, opts ...ToolsNodeOption) ([]*schema.Message, error)
 //	Stream(ctx context.Context, input *schema.Message, opts ...ToolsNodeOption) (*schema.StreamReader[[]*schema.Message], error)
+//	Transform(ctx context.Context, input *schema.StreamReader[*schema.Message], opts ...ToolsNodeOption) (*schema.StreamReader[[]*schema.Message], error)
+//
+// Transform is only used by the graph when the ToolsNode can stream arguments to StreamingInputTools, see ToolsNodeConfig.Tools.
 //
 // Input: An AssistantMessage containing ToolCalls
 // Output: An array of ToolMessage where the order of elements corresponds to the order of ToolCalls in the input
@@ -71,9 +78,18 @@ type ToolsNode struct {
 	tuple                     *toolsTuple
 	unknownToolHandler        func(ctx context.Context, name, input string) (string, error)
 	executeSequentially       bool
+	maxConcurrency            int
 	toolArgumentsHandler      func(ctx context.Context, name, input string) (string, error)
 	toolCallMiddlewares       []InvokableToolMiddleware
 	streamToolCallMiddlewares []StreamableToolMiddleware
+	streamOutputTools         map[string]bool
+
+	validateToolArguments       bool
+	repairToolArguments         bool
+	invalidToolArgumentsHandler func(ctx context.Context, err *ToolArgumentsError) (string, error)
+
+	outputLimit  *ToolOutputLimit
+	outputLimits map[string]*ToolOutputLimit
 }
 
 // ToolInput represents the input parameters for a tool call execution.
@@ -122,9 +138,68 @@ type ToolMiddleware struct {
 	Streamable StreamableToolMiddleware
 }
 
+// ToolMiddlewareFrom converts a tool.Middleware to a ToolMiddleware, to apply it to all the tools of a ToolsNode,
+// instead of wrapping each of them by tool.WrapWithMiddleware.
+// e.g.
+//
+//	conf := &compose.ToolsNodeConfig{
+//		Tools:               tools,
+//		ToolCallMiddlewares: []compose.ToolMiddleware{compose.ToolMiddlewareFrom(tool.NewTimeoutMiddleware(time.Minute))},
+//	}
+func ToolMiddlewareFrom(mw tool.Middleware) ToolMiddleware {
+	toToolInput := func(in *tool.CallInput, callID string) *ToolInput {
+		return &ToolInput{Name: in.Name, Arguments: in.Arguments, CallID: callID, CallOptions: in.Options}
+	}
+	toCallInput := func(in *ToolInput) *tool.CallInput {
+		return &tool.CallInput{Name: in.Name, Arguments: in.Arguments, Options: in.CallOptions}
+	}
+
+	var ret ToolMiddleware
+	if mw.Invokable != nil {
+		ret.Invokable = func(next InvokableToolEndpoint) InvokableToolEndpoint {
+			return func(ctx context.Context, input *ToolInput) (*ToolOutput, error) {
+				endpoint := mw.Invokable(func(ctx context.Context, in *tool.CallInput) (string, error) {
+					output, err := next(ctx, toToolInput(in, input.CallID))
+					if err != nil {
+						return "", err
+					}
+					return output.Result, nil
+				})
+				result, err := endpoint(ctx, toCallInput(input))
+				if err != nil {
+					return nil, err
+				}
+				return &ToolOutput{Result: result}, nil
+			}
+		}
+	}
+	if mw.Streamable != nil {
+		ret.Streamable = func(next StreamableToolEndpoint) StreamableToolEndpoint {
+			return func(ctx context.Context, input *ToolInput) (*StreamToolOutput, error) {
+				endpoint := mw.Streamable(func(ctx context.Context, in *tool.CallInput) (*schema.StreamReader[string], error) {
+					output, err := next(ctx, toToolInput(in, input.CallID))
+					if err != nil {
+						return nil, err
+					}
+					return output.Result, nil
+				})
+				result, err := endpoint(ctx, toCallInput(input))
+				if err != nil {
+					return nil, err
+				}
+				return &StreamToolOutput{Result: result}, nil
+			}
+		}
+	}
+	return ret
+}
+
 // ToolsNodeConfig is the config for ToolsNode.
 type ToolsNodeConfig struct {
-	// Tools specify the list of tools can be called which are BaseTool but must implement InvokableTool or StreamableTool.
+	// Tools specify the list of tools can be called which are BaseTool but must implement InvokableTool, StreamableTool or StreamingInputTool.
+	// When the graph runs in stream mode, the arguments of StreamingInputTools are fed to them while the ChatModel is still generating,
+	// unless ExecuteSequentially, ToolArgumentsHandler, ToolCallMiddlewares, ValidateToolArguments or RepairToolArguments is set,
+	// which all need the complete arguments.
 	Tools []tool.BaseTool
 
 	// UnknownToolsHandler handles tool calls for non-existent tools when LLM hallucinates.
@@ -145,6 +220,13 @@ type ToolsNodeConfig struct {
 	// When set to false (default), tool calls will be executed in parallel.
 	ExecuteSequentially bool
 
+	// MaxConcurrency limits the number of tool calls executed at the same time when executing in parallel,
+	// which is useful when the model calls many IO-bound tools at once, e.g. fetching a dozen web pages.
+	// Tool calls are started in the order they appear in the input message, and the results are always
+	// returned in that order, regardless of which call completes first.
+	// Zero or negative means no limit. It's ignored when ExecuteSequentially is true.
+	MaxConcurrency int
+
 	// ToolArgumentsHandler allows handling of tool arguments before execution.
 	// When provided, this function will be called for each tool call to process the arguments.
 	// Parameters:
@@ -161,6 +243,42 @@ type ToolsNodeConfig struct {
 	// Invokable middleware only applies to tools implementing InvokableTool interface.
 	// Streamable middleware only applies to tools implementing StreamableTool interface.
 	ToolCallMiddlewares []ToolMiddleware
+
+	// StreamOutputTools specifies the tools whose output is always produced by StreamableRun,
+	// even if they also implement InvokableTool. The map keys are tool names.
+	// By default, a tool implementing both interfaces is called by InvokableRun when the ToolsNode is invoked,
+	// and by StreamableRun when the ToolsNode is streamed.
+	// For the tools listed here, StreamableRun is called in both cases, so that callback handlers,
+	// e.g. the ones emitting agent events, receive the output chunk by chunk while the tool is still running.
+	// When the ToolsNode is invoked, the chunks are concatenated into the returned ToolMessage.
+	StreamOutputTools map[string]bool
+
+	// ValidateToolArguments makes the ToolsNode validate the arguments of each tool call against the parameters of the tool,
+	// i.e. ToolInfo.ParamsOneOf, before calling the tool. Types, required properties, enums, bounds etc. are checked.
+	// The arguments are validated after ToolArgumentsHandler, so they're exactly what the tool receives.
+	// A tool call with invalid arguments isn't executed, and is handled by InvalidToolArgumentsHandler instead.
+	ValidateToolArguments bool
+
+	// RepairToolArguments makes the ToolsNode try to fix the arguments that are not valid JSON before calling the tool,
+	// e.g. removing trailing commas and markdown code fences, and closing the objects, arrays and strings left open by a truncated output.
+	// Empty arguments are replaced by "{}". The arguments are passed through unchanged if they can't be repaired.
+	// The repair is done before ValidateToolArguments, if both are set.
+	RepairToolArguments bool
+
+	// InvalidToolArgumentsHandler handles the tool calls whose arguments fail ValidateToolArguments.
+	// The returned string is used as the result of the tool call, so that the model can correct the arguments and call the tool again.
+	// Returning an error makes the ToolsNode fail with it.
+	// This field is optional. When not set, the result is a JSON object listing the violations found in the arguments.
+	InvalidToolArgumentsHandler func(ctx context.Context, err *ToolArgumentsError) (string, error)
+
+	// ToolOutputLimit bounds the size of the results of all the tools before they're sent back to the model,
+	// by truncating or summarizing the results exceeding the limit, see ToolOutputLimitStrategy.
+	// This field is optional. When not set, the results are returned as is.
+	ToolOutputLimit *ToolOutputLimit
+
+	// ToolOutputLimits overrides ToolOutputLimit for specific tools. The map keys are tool names.
+	// A nil value, or a value with zero MaxLength, disables the limit for the tool.
+	ToolOutputLimits map[string]*ToolOutputLimit
 }
 
 // NewToolNode creates a new ToolsNode.
@@ -182,18 +300,41 @@ func NewToolNode(ctx context.Context, conf *ToolsNodeConfig) (*ToolsNode, error)
 		}
 	}
 
-	tuple, err := convTools(ctx, conf.Tools, middlewares, streamMiddlewares)
+	tuple, err := convTools(ctx, conf.Tools, middlewares, streamMiddlewares, conf.StreamOutputTools)
 	if err != nil {
 		return nil, err
 	}
 
+	if err = conf.ToolOutputLimit.check(); err != nil {
+		return nil, err
+	}
+	for name, l := range conf.ToolOutputLimits {
+		if err = l.check(); err != nil {
+			return nil, fmt.Errorf("invalid output limit of tool[%s]: %w", name, err)
+		}
+	}
+
+	invalidArgumentsHandler := conf.InvalidToolArgumentsHandler
+	if invalidArgumentsHandler == nil {
+		invalidArgumentsHandler = defaultInvalidToolArgumentsHandler
+	}
+
 	return &ToolsNode{
 		tuple:                     tuple,
 		unknownToolHandler:        conf.UnknownToolsHandler,
 		executeSequentially:       conf.ExecuteSequentially,
+		maxConcurrency:            conf.MaxConcurrency,
 		toolArgumentsHandler:      conf.ToolArgumentsHandler,
 		toolCallMiddlewares:       middlewares,
 		streamToolCallMiddlewares: streamMiddlewares,
+		streamOutputTools:         conf.StreamOutputTools,
+
+		validateToolArguments:       conf.ValidateToolArguments,
+		repairToolArguments:         conf.RepairToolArguments,
+		invalidToolArgumentsHandler: invalidArgumentsHandler,
+
+		outputLimit:  conf.ToolOutputLimit,
+		outputLimits: conf.ToolOutputLimits,
 	}, nil
 }
 
@@ -210,17 +351,24 @@ func init() {
 
 type toolsTuple struct {
 	indexes         map[string]int
+	infos           []*schema.ToolInfo
 	meta            []*executorMeta
 	endpoints       []InvokableToolEndpoint
 	streamEndpoints []StreamableToolEndpoint
+	// inputStreamEndpoints is nil for tools that are not StreamingInputTool
+	inputStreamEndpoints []streamingInputToolEndpoint
 }
 
-func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMiddleware, sms []StreamableToolMiddleware) (*toolsTuple, error) {
+func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMiddleware, sms []StreamableToolMiddleware,
+	streamOutputTools map[string]bool) (*toolsTuple, error) {
+
 	ret := &toolsTuple{
-		indexes:         make(map[string]int),
-		meta:            make([]*executorMeta, len(tools)),
-		endpoints:       make([]InvokableToolEndpoint, len(tools)),
-		streamEndpoints: make([]StreamableToolEndpoint, len(tools)),
+		indexes:              make(map[string]int),
+		infos:                make([]*schema.ToolInfo, len(tools)),
+		meta:                 make([]*executorMeta, len(tools)),
+		endpoints:            make([]InvokableToolEndpoint, len(tools)),
+		streamEndpoints:      make([]StreamableToolEndpoint, len(tools)),
+		inputStreamEndpoints: make([]streamingInputToolEndpoint, len(tools)),
 	}
 	for idx, bt := range tools {
 		tl, err := bt.Info(ctx)
@@ -230,8 +378,9 @@ func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMid
 
 		toolName := tl.Name
 		var (
-			st tool.StreamableTool
-			it tool.InvokableTool
+			st  tool.StreamableTool
+			it  tool.InvokableTool
+			sit tool.StreamingInputTool
 
 			invokable  InvokableToolEndpoint
 			streamable StreamableToolEndpoint
@@ -246,12 +395,20 @@ func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMid
 			streamable = wrapStreamToolCall(st, sms, !meta.isComponentCallbackEnabled)
 		}
 
-		if it, ok = bt.(tool.InvokableTool); ok {
+		if it, ok = bt.(tool.InvokableTool); ok && (st == nil || !streamOutputTools[toolName]) {
 			invokable = wrapToolCall(it, ms, !meta.isComponentCallbackEnabled)
 		}
 
-		if st == nil && it == nil {
-			return nil, fmt.Errorf("tool %s is not invokable or streamable", toolName)
+		if sit, ok = bt.(tool.StreamingInputTool); ok {
+			ret.inputStreamEndpoints[idx] = wrapStreamingInputToolCall(sit, !meta.isComponentCallbackEnabled)
+			if st == nil && it == nil {
+				// the complete arguments are sent as a single chunk
+				invokable = wrapToolCall(&streamingInputToInvokableTool{sit: sit}, ms, !meta.isComponentCallbackEnabled)
+			}
+		}
+
+		if st == nil && it == nil && sit == nil {
+			return nil, fmt.Errorf("tool %s is not invokable, streamable or streaming input", toolName)
 		}
 
 		if streamable == nil {
@@ -262,6 +419,7 @@ func convTools(ctx context.Context, tools []tool.BaseTool, ms []InvokableToolMid
 		}
 
 		ret.indexes[toolName] = idx
+		ret.infos[idx] = tl
 		ret.meta[idx] = meta
 		ret.endpoints[idx] = invokable
 		ret.streamEndpoints[idx] = streamable
@@ -372,7 +530,7 @@ type toolCallTask struct {
 }
 
 func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
-	input *schema.Message, executedTools map[string]string, isStream bool) ([]toolCallTask, error) {
+	input *schema.Message, opt *toolsNodeOptions, isStream bool) ([]toolCallTask, error) {
 
 	if input.Role != schema.Assistant {
 		return nil, fmt.Errorf("expected message role is Assistant, got %s", input.Role)
@@ -387,7 +545,7 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
 
 	for i := 0; i < n; i++ {
 		toolCall := input.ToolCalls[i]
-		if result, executed := executedTools[toolCall.ID]; executed {
+		if result, executed := opt.executedTools[toolCall.ID]; executed {
 			toolCallTasks[i].name = toolCall.Function.Name
 			toolCallTasks[i].arg = toolCall.Function.Arguments
 			toolCallTasks[i].callID = toolCall.ID
@@ -406,20 +564,32 @@ func (tn *ToolsNode) genToolCallTasks(ctx context.Context, tuple *toolsTuple,
 			}
 			toolCallTasks[i] = newUnknownToolTask(toolCall.Function.Name, toolCall.Function.Arguments, toolCall.ID, tn.unknownToolHandler)
 		} else {
-			toolCallTasks[i].endpoint = tuple.endpoints[index]
-			toolCallTasks[i].streamEndpoint = tuple.streamEndpoints[index]
-			toolCallTasks[i].meta = tuple.meta[index]
-			toolCallTasks[i].name = toolCall.Function.Name
-			toolCallTasks[i].callID = toolCall.ID
+			arg := toolCall.Function.Arguments
 			if tn.toolArgumentsHandler != nil {
-				arg, err := tn.toolArgumentsHandler(ctx, toolCall.Function.Name, toolCall.Function.Arguments)
+				var err error
+				arg, err = tn.toolArgumentsHandler(ctx, toolCall.Function.Name, toolCall.Function.Arguments)
 				if err != nil {
 					return nil, fmt.Errorf("failed to executed tool[name:%s arguments:%s] arguments handler: %w", toolCall.Function.Name, toolCall.Function.Arguments, err)
 				}
-				toolCallTasks[i].arg = arg
-			} else {
-				toolCallTasks[i].arg = toolCall.Function.Arguments
 			}
+			arg, argErr, err := tn.checkToolArguments(ctx, tuple.infos[index], toolCall.ID, arg)
+			if err != nil {
+				return nil, err
+			}
+			if argErr != nil {
+				toolCallTasks[i] = newInvalidArgumentsTask(argErr, tn.invalidToolArgumentsHandler)
+				continue
+			}
+			arg, err = injectToolArguments(toolCall.Function.Name, arg, opt.hiddenArguments)
+			if err != nil {
+				return nil, fmt.Errorf("failed to inject hidden arguments of tool[name:%s id:%s]: %w", toolCall.Function.Name, toolCall.ID, err)
+			}
+			toolCallTasks[i].endpoint = tuple.endpoints[index]
+			toolCallTasks[i].streamEndpoint = tuple.streamEndpoints[index]
+			toolCallTasks[i].meta = tuple.meta[index]
+			toolCallTasks[i].name = toolCall.Function.Name
+			toolCallTasks[i].callID = toolCall.ID
+			toolCallTasks[i].arg = arg
 		}
 	}
 
@@ -511,13 +681,18 @@ func sequentialRunToolCall(ctx context.Context,
 
 func parallelRunToolCall(ctx context.Context,
 	run func(ctx2 context.Context, callTask *toolCallTask, opts ...tool.Option),
-	tasks []toolCallTask, opts ...tool.Option) {
+	tasks []toolCallTask, maxConcurrency int, opts ...tool.Option) {
 
 	if len(tasks) == 1 {
 		run(ctx, &tasks[0], opts...)
 		return
 	}
 
+	if maxConcurrency > 0 {
+		limitedParallelRunToolCall(ctx, run, tasks, maxConcurrency, opts...)
+		return
+	}
+
 	var wg sync.WaitGroup
 	for i := 1; i < len(tasks); i++ {
 		if tasks[i].executed {
@@ -543,6 +718,35 @@ func parallelRunToolCall(ctx context.Context,
 	wg.Wait()
 }
 
+// limitedParallelRunToolCall runs at most maxConcurrency tool calls at the same time, starting them in order.
+func limitedParallelRunToolCall(ctx context.Context,
+	run func(ctx2 context.Context, callTask *toolCallTask, opts ...tool.Option),
+	tasks []toolCallTask, maxConcurrency int, opts ...tool.Option) {
+
+	sem := make(chan struct{}, maxConcurrency)
+	var wg sync.WaitGroup
+	for i := range tasks {
+		if tasks[i].executed {
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(ctx_ context.Context, t *toolCallTask, opts ...tool.Option) {
+			defer func() {
+				panicErr := recover()
+				if panicErr != nil {
+					t.err = safe.NewPanicErr(panicErr, debug.Stack())
+				}
+				<-sem
+				wg.Done()
+			}()
+			run(ctx_, t, opts...)
+		}(ctx, &tasks[i], opts...)
+	}
+
+	wg.Wait()
+}
+
 // Invoke calls the tools and collects the results of invokable tools.
 // it's parallel if there are multiple tool calls in the input message.
 func (tn *ToolsNode) Invoke(ctx context.Context, input *schema.Message,
@@ -552,13 +756,15 @@ func (tn *ToolsNode) Invoke(ctx context.Context, input *schema.Message,
 	tuple := tn.tuple
 	if opt.ToolList != nil {
 		var err error
-		tuple, err = convTools(ctx, opt.ToolList, tn.toolCallMiddlewares, tn.streamToolCallMiddlewares)
+		tuple, err = convTools(ctx, opt.ToolList, tn.toolCallMiddlewares, tn.streamToolCallMiddlewares, tn.streamOutputTools)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert tool list from call option: %w", err)
 		}
 	}
 
-	tasks, err := tn.genToolCallTasks(ctx, tuple, input, opt.executedTools, false)
+	tuple = tuple.filter(ctx, opt.toolFilter)
+	ctx = withToolValues(ctx, opt.toolValues)
+	tasks, err := tn.genToolCallTasks(ctx, tuple, input, opt, false)
 	if err != nil {
 		return nil, err
 	}
@@ -566,8 +772,9 @@ func (tn *ToolsNode) Invoke(ctx context.Context, input *schema.Message,
 	if tn.executeSequentially {
 		sequentialRunToolCall(ctx, runToolCallTaskByInvoke, tasks, opt.ToolOptions...)
 	} else {
-		parallelRunToolCall(ctx, runToolCallTaskByInvoke, tasks, opt.ToolOptions...)
+		parallelRunToolCall(ctx, runToolCallTaskByInvoke, tasks, tn.maxConcurrency, opt.ToolOptions...)
 	}
+	tn.limitOutputs(ctx, tasks)
 
 	n := len(tasks)
 	output := make([]*schema.Message, n)
@@ -612,13 +819,15 @@ func (tn *ToolsNode) Stream(ctx context.Context, input *schema.Message,
 	tuple := tn.tuple
 	if opt.ToolList != nil {
 		var err error
-		tuple, err = convTools(ctx, opt.ToolList, tn.toolCallMiddlewares, tn.streamToolCallMiddlewares)
+		tuple, err = convTools(ctx, opt.ToolList, tn.toolCallMiddlewares, tn.streamToolCallMiddlewares, tn.streamOutputTools)
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert tool list from call option: %w", err)
 		}
 	}
 
-	tasks, err := tn.genToolCallTasks(ctx, tuple, input, opt.executedTools, true)
+	tuple = tuple.filter(ctx, opt.toolFilter)
+	ctx = withToolValues(ctx, opt.toolValues)
+	tasks, err := tn.genToolCallTasks(ctx, tuple, input, opt, true)
 	if err != nil {
 		return nil, err
 	}
@@ -626,9 +835,15 @@ func (tn *ToolsNode) Stream(ctx context.Context, input *schema.Message,
 	if tn.executeSequentially {
 		sequentialRunToolCall(ctx, runToolCallTaskByStream, tasks, opt.ToolOptions...)
 	} else {
-		parallelRunToolCall(ctx, runToolCallTaskByStream, tasks, opt.ToolOptions...)
+		parallelRunToolCall(ctx, runToolCallTaskByStream, tasks, tn.maxConcurrency, opt.ToolOptions...)
 	}
+	tn.limitStreamOutputs(ctx, tasks)
+
+	return genStreamOutput(input, tasks)
+}
 
+// genStreamOutput merges the stream outputs of the finished tasks, or returns an interrupt and rerun error if any task asks for it.
+func genStreamOutput(input *schema.Message, tasks []toolCallTask) (*schema.StreamReader[[]*schema.Message], error) {
 	n := len(tasks)
 
 	rerun := false
@@ -22,15 +22,19 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/stretchr/testify/assert"
 
+	"github.com/cloudwego/eino/callbacks"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/components/tool/utils"
 	"github.com/cloudwego/eino/internal"
+	"github.com/cloudwego/eino/internal/generic"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -901,6 +905,269 @@ func TestToolMiddleware(t *testing.T) {
 	assert.Equal(t, "middleware2", messages[1].Content)
 }
 
+func TestToolsNodeMaxConcurrency(t *testing.T) {
+	ctx := context.Background()
+
+	var running, peak int32
+	slow, err := utils.InferTool("slow", "slow tool", func(ctx context.Context, in struct {
+		ID int `json:"id"`
+	}) (string, error) {
+		cur := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if cur <= p || atomic.CompareAndSwapInt32(&peak, p, cur) {
+				break
+			}
+		}
+		// later calls finish first, the results must still be in the order of the calls
+		time.Sleep(time.Duration(10-in.ID) * time.Millisecond)
+		return strconv.Itoa(in.ID), nil
+	})
+	assert.NoError(t, err)
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:          []tool.BaseTool{slow},
+		MaxConcurrency: 2,
+	})
+	assert.NoError(t, err)
+
+	var toolCalls []schema.ToolCall
+	for i := 0; i < 6; i++ {
+		toolCalls = append(toolCalls, schema.ToolCall{
+			ID:       strconv.Itoa(i),
+			Function: schema.FunctionCall{Name: "slow", Arguments: fmt.Sprintf(`{"id": %d}`, i)},
+		})
+	}
+
+	messages, err := tn.Invoke(ctx, schema.AssistantMessage("", toolCalls))
+	assert.NoError(t, err)
+	assert.Len(t, messages, 6)
+	for i, msg := range messages {
+		assert.Equal(t, strconv.Itoa(i), msg.ToolCallID)
+		assert.Equal(t, strconv.Itoa(i), msg.Content)
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&peak))
+
+	atomic.StoreInt32(&peak, 0)
+	sr, err := tn.Stream(ctx, schema.AssistantMessage("", toolCalls))
+	assert.NoError(t, err)
+	var chunks [][]*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	messages, err = schema.ConcatMessageArray(chunks)
+	assert.NoError(t, err)
+	for i, msg := range messages {
+		assert.Equal(t, strconv.Itoa(i), msg.Content)
+	}
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(2))
+}
+
+type dualModeToolForTest struct {
+	invoked, streamed int32
+}
+
+func (d *dualModeToolForTest) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "dual"}, nil
+}
+
+func (d *dualModeToolForTest) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	atomic.AddInt32(&d.invoked, 1)
+	return "hello world", nil
+}
+
+func (d *dualModeToolForTest) StreamableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (*schema.StreamReader[string], error) {
+	atomic.AddInt32(&d.streamed, 1)
+	return schema.StreamReaderFromArray([]string{"hello", " ", "world"}), nil
+}
+
+func TestToolsNodeStreamOutputTools(t *testing.T) {
+	ctx := context.Background()
+	input := schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "dual", Arguments: "{}"}}})
+
+	t.Run("invoke by default", func(t *testing.T) {
+		dual := &dualModeToolForTest{}
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{dual}})
+		assert.NoError(t, err)
+
+		messages, err := tn.Invoke(ctx, input)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", messages[0].Content)
+		assert.Equal(t, int32(1), dual.invoked)
+		assert.Equal(t, int32(0), dual.streamed)
+	})
+
+	t.Run("stream output tool", func(t *testing.T) {
+		dual := &dualModeToolForTest{}
+		tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools:             []tool.BaseTool{dual},
+			StreamOutputTools: map[string]bool{"dual": true},
+		})
+		assert.NoError(t, err)
+
+		var chunks []string
+		done := make(chan struct{})
+		handler := callbacks.NewHandlerBuilder().
+			OnEndWithStreamOutputFn(func(ctx context.Context, info *callbacks.RunInfo, output *schema.StreamReader[callbacks.CallbackOutput]) context.Context {
+				go func() {
+					defer close(done)
+					defer output.Close()
+					for {
+						chunk, err := output.Recv()
+						if err != nil {
+							return
+						}
+						chunks = append(chunks, tool.ConvCallbackOutput(chunk).Response)
+					}
+				}()
+				return ctx
+			}).Build()
+
+		messages, err := tn.Invoke(callbacks.InitCallbacks(ctx, &callbacks.RunInfo{}, handler), input)
+		assert.NoError(t, err)
+		assert.Equal(t, "hello world", messages[0].Content)
+		assert.Equal(t, int32(0), dual.invoked)
+		assert.Equal(t, int32(1), dual.streamed)
+
+		<-done
+		assert.Equal(t, []string{"hello", " ", "world"}, chunks)
+
+		sr, err := tn.Stream(ctx, input)
+		assert.NoError(t, err)
+		var messageChunks [][]*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			messageChunks = append(messageChunks, chunk)
+		}
+		assert.Len(t, messageChunks, 3)
+		assert.Equal(t, int32(2), dual.streamed)
+	})
+}
+
+type streamingInputToolForTest struct {
+	firstFragment chan string
+}
+
+func (s *streamingInputToolForTest) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "run_sql"}, nil
+}
+
+func (s *streamingInputToolForTest) StreamingInputRun(ctx context.Context, arguments *schema.StreamReader[string], opts ...tool.Option) (string, error) {
+	defer arguments.Close()
+	sb := strings.Builder{}
+	for {
+		fragment, err := arguments.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if sb.Len() == 0 && s.firstFragment != nil {
+			s.firstFragment <- fragment
+		}
+		sb.WriteString(fragment)
+	}
+	return "executed: " + sb.String() + " id: " + GetToolCallID(ctx), nil
+}
+
+func TestToolsNodeStreamingInputTool(t *testing.T) {
+	ctx := context.Background()
+
+	greet, err := utils.InferTool("greet", "greet tool", func(ctx context.Context, in struct {
+		Name string `json:"name"`
+	}) (string, error) {
+		return "hello " + in.Name, nil
+	})
+	assert.NoError(t, err)
+
+	sit := &streamingInputToolForTest{firstFragment: make(chan string, 1)}
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools: []tool.BaseTool{greet, sit},
+	})
+	assert.NoError(t, err)
+
+	t.Run("arguments are fed before the message is complete", func(t *testing.T) {
+		g := NewGraph[string, []*schema.Message]()
+		assert.NoError(t, g.AddLambdaNode("model", StreamableLambda(func(ctx context.Context, _ string) (*schema.StreamReader[*schema.Message], error) {
+			sr, sw := schema.Pipe[*schema.Message](0)
+			go func() {
+				defer sw.Close()
+				sw.Send(&schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{
+					{Index: generic.PtrOf(0), ID: "call_1", Function: schema.FunctionCall{Name: "run_sql", Arguments: `{"sql": "select`}},
+				}}, nil)
+				// the rest of the arguments are only generated after the tool has started
+				select {
+				case fragment := <-sit.firstFragment:
+					assert.Equal(t, `{"sql": "select`, fragment)
+				case <-time.After(5 * time.Second):
+					sw.Send(nil, fmt.Errorf("streaming input tool isn't started before the arguments are complete"))
+					return
+				}
+				sw.Send(&schema.Message{Role: schema.Assistant, ToolCalls: []schema.ToolCall{
+					{Index: generic.PtrOf(0), Function: schema.FunctionCall{Arguments: ` 1"}`}},
+					{Index: generic.PtrOf(1), ID: "call_2", Function: schema.FunctionCall{Name: "greet", Arguments: `{"name": "max"}`}},
+				}}, nil)
+			}()
+			return sr, nil
+		})))
+		assert.NoError(t, g.AddToolsNode("tools", tn))
+		assert.NoError(t, g.AddEdge(START, "model"))
+		assert.NoError(t, g.AddEdge("model", "tools"))
+		assert.NoError(t, g.AddEdge("tools", END))
+		r, err := g.Compile(ctx)
+		assert.NoError(t, err)
+
+		sr, err := r.Stream(ctx, "")
+		assert.NoError(t, err)
+		var chunks [][]*schema.Message
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			assert.NoError(t, err)
+			chunks = append(chunks, chunk)
+		}
+		messages, err := schema.ConcatMessageArray(chunks)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, `executed: {"sql": "select 1"} id: call_1`, findMsgByToolCallID(messages, "call_1").Content)
+		assert.Equal(t, "hello max", findMsgByToolCallID(messages, "call_2").Content)
+	})
+
+	t.Run("complete arguments in invoke mode", func(t *testing.T) {
+		messages, err := tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+			{ID: "call_1", Function: schema.FunctionCall{Name: "run_sql", Arguments: `{"sql": "select 1"}`}},
+		}))
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, `executed: {"sql": "select 1"} id: call_1`, messages[0].Content)
+	})
+
+	t.Run("no streaming with arguments handler", func(t *testing.T) {
+		handled, err := NewToolNode(ctx, &ToolsNodeConfig{
+			Tools: []tool.BaseTool{sit},
+			ToolArgumentsHandler: func(ctx context.Context, name, arguments string) (string, error) {
+				return arguments, nil
+			},
+		})
+		assert.NoError(t, err)
+		assert.False(t, handled.canStreamArguments(handled.tuple))
+		assert.True(t, tn.canStreamArguments(tn.tuple))
+	})
+}
+
 type myTool1 struct {
 	times uint
 }
@@ -962,3 +1229,38 @@ func (m *myTool4) StreamableRun(ctx context.Context, argumentsInJSON string, opt
 	m.times++
 	return schema.StreamReaderFromArray([]string{"tool4 input: ", argumentsInJSON}), nil
 }
+
+func TestToolMiddlewareFrom(t *testing.T) {
+	ctx := context.Background()
+
+	var names []string
+	mw := tool.Middleware{
+		Invokable: func(next tool.InvokableEndpoint) tool.InvokableEndpoint {
+			return func(ctx context.Context, input *tool.CallInput) (string, error) {
+				names = append(names, input.Name+":"+GetToolCallID(ctx))
+				input.Arguments = `{"id": 1}`
+				output, err := next(ctx, input)
+				return "wrapped " + output, err
+			}
+		},
+	}
+	echo, err := utils.InferTool("echo", "echo the id", func(ctx context.Context, in struct {
+		ID int `json:"id"`
+	}) (string, error) {
+		return strconv.Itoa(in.ID), nil
+	})
+	assert.NoError(t, err)
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:               []tool.BaseTool{echo},
+		ToolCallMiddlewares: []ToolMiddleware{ToolMiddlewareFrom(mw)},
+	})
+	assert.NoError(t, err)
+
+	messages, err := tn.Invoke(ctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "call_1", Function: schema.FunctionCall{Name: "echo", Arguments: `{"id": 0}`}},
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "wrapped 1", messages[0].Content)
+	assert.Equal(t, []string{"echo:call_1"}, names)
+}
@@ -0,0 +1,282 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"sync"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+type streamingInputToolEndpoint func(ctx context.Context, arguments *schema.StreamReader[string], opts ...tool.Option) (string, error)
+
+func wrapStreamingInputToolCall(sit tool.StreamingInputTool, needCallback bool) streamingInputToolEndpoint {
+	if needCallback {
+		return streamingInputToolEndpoint(collectWithCallbacks[string, string, tool.Option](sit.StreamingInputRun))
+	}
+	return sit.StreamingInputRun
+}
+
+// streamingInputToInvokableTool calls the StreamingInputTool with the complete arguments as a single chunk.
+type streamingInputToInvokableTool struct {
+	sit tool.StreamingInputTool
+}
+
+func (s *streamingInputToInvokableTool) Info(ctx context.Context) (*schema.ToolInfo, error) {
+	return s.sit.Info(ctx)
+}
+
+func (s *streamingInputToInvokableTool) InvokableRun(ctx context.Context, argumentsInJSON string, opts ...tool.Option) (string, error) {
+	return s.sit.StreamingInputRun(ctx, schema.StreamReaderFromArray([]string{argumentsInJSON}), opts...)
+}
+
+// canStreamArguments reports whether the arguments can be fed to StreamingInputTools before they are complete.
+func (tn *ToolsNode) canStreamArguments(tuple *toolsTuple) bool {
+	if tuple == nil || tn.executeSequentially || tn.toolArgumentsHandler != nil ||
+		len(tn.toolCallMiddlewares) > 0 || len(tn.streamToolCallMiddlewares) > 0 ||
+		tn.validateToolArguments || tn.repairToolArguments {
+		return false
+	}
+	for _, e := range tuple.inputStreamEndpoints {
+		if e != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Transform calls the tools with the stream of an assistant message.
+// The arguments of StreamingInputTools are fed to them as soon as they arrive,
+// the other tools are called after the message is complete, the same as Stream.
+// Tool calls without Index or ID in the stream can't be started early, so they are called with the complete arguments.
+func (tn *ToolsNode) Transform(ctx context.Context, input *schema.StreamReader[*schema.Message],
+	opts ...ToolsNodeOption) (*schema.StreamReader[[]*schema.Message], error) {
+
+	opt := getToolsNodeOptions(opts...)
+	tuple := tn.tuple
+	if opt.ToolList != nil {
+		var err error
+		tuple, err = convTools(ctx, opt.ToolList, tn.toolCallMiddlewares, tn.streamToolCallMiddlewares, tn.streamOutputTools)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert tool list from call option: %w", err)
+		}
+	}
+	tuple = tuple.filter(ctx, opt.toolFilter)
+
+	// the hidden arguments can only be merged into the complete arguments
+	if len(opt.executedTools) > 0 || len(opt.hiddenArguments) > 0 || !tn.canStreamArguments(tuple) {
+		msg, err := defaultImplConcatStreamReader(input)
+		if err != nil {
+			return nil, err
+		}
+		return tn.Stream(ctx, msg, opts...)
+	}
+
+	ctx = withToolValues(ctx, opt.toolValues)
+	d := &argumentsDispatcher{
+		ctx:     ctx,
+		tuple:   tuple,
+		opts:    opt.ToolOptions,
+		streams: make(map[int]*argumentsStream),
+	}
+	msg, err := d.dispatch(input)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := tn.genToolCallTasks(ctx, tuple, msg, opt, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []int
+	for i, tc := range msg.ToolCalls {
+		if tc.Index != nil {
+			if s, ok := d.streams[*tc.Index]; ok && s.started {
+				if s.err != nil {
+					tasks[i].err = s.err
+				} else {
+					tasks[i].sOutput = schema.StreamReaderFromArray([]string{s.output})
+					tasks[i].executed = true
+				}
+				continue
+			}
+		}
+		pending = append(pending, i)
+	}
+
+	if len(pending) > 0 {
+		pendingTasks := make([]toolCallTask, len(pending))
+		for i, idx := range pending {
+			pendingTasks[i] = tasks[idx]
+		}
+		parallelRunToolCall(ctx, runToolCallTaskByStream, pendingTasks, tn.maxConcurrency, opt.ToolOptions...)
+		for i, idx := range pending {
+			tasks[idx] = pendingTasks[i]
+		}
+	}
+	tn.limitStreamOutputs(ctx, tasks)
+
+	return genStreamOutput(msg, tasks)
+}
+
+// argumentsDispatcher reads the assistant message stream, starts the StreamingInputTools once their names and ids are known,
+// and feeds them with the fragments of their arguments.
+type argumentsDispatcher struct {
+	ctx   context.Context
+	tuple *toolsTuple
+	opts  []tool.Option
+
+	// streams are keyed by the index of the tool call
+	streams map[int]*argumentsStream
+	wg      sync.WaitGroup
+}
+
+type argumentsStream struct {
+	name, callID string
+	// skipped means the tool isn't a StreamingInputTool, so it will be called with the complete arguments later
+	skipped bool
+	started bool
+	// buffered holds the fragments received before the tool is started
+	buffered []string
+	sw       *schema.StreamWriter[string]
+
+	output string
+	err    error
+}
+
+// dispatch consumes the whole input, waits for the started tools to finish, and returns the complete message.
+func (d *argumentsDispatcher) dispatch(input *schema.StreamReader[*schema.Message]) (*schema.Message, error) {
+	defer input.Close()
+
+	var chunks []*schema.Message
+	for {
+		chunk, err := input.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			if _, ok := schema.GetSourceName(err); ok {
+				continue
+			}
+			d.closeAll(err)
+			return nil, newStreamReadError(err)
+		}
+		chunks = append(chunks, chunk)
+		if chunk != nil {
+			d.feed(chunk.ToolCalls)
+		}
+	}
+	d.closeAll(nil)
+
+	return defaultImplConcatStreamReader(schema.StreamReaderFromArray(chunks))
+}
+
+func (d *argumentsDispatcher) feed(toolCalls []schema.ToolCall) {
+	for _, tc := range toolCalls {
+		if tc.Index == nil {
+			continue
+		}
+		s, ok := d.streams[*tc.Index]
+		if !ok {
+			s = &argumentsStream{}
+			d.streams[*tc.Index] = s
+		}
+		if s.skipped {
+			continue
+		}
+		if s.name == "" {
+			s.name = tc.Function.Name
+		}
+		if s.callID == "" {
+			s.callID = tc.ID
+		}
+		if len(tc.Function.Arguments) > 0 {
+			if s.started {
+				s.sw.Send(tc.Function.Arguments, nil)
+			} else {
+				s.buffered = append(s.buffered, tc.Function.Arguments)
+			}
+		}
+		if !s.started && s.name != "" && s.callID != "" {
+			d.start(s)
+		}
+	}
+}
+
+func (d *argumentsDispatcher) start(s *argumentsStream) {
+	index, ok := d.tuple.indexes[s.name]
+	if !ok || d.tuple.inputStreamEndpoints[index] == nil {
+		s.skipped = true
+		s.buffered = nil
+		return
+	}
+	endpoint := d.tuple.inputStreamEndpoints[index]
+	meta := d.tuple.meta[index]
+
+	sr, sw := schema.Pipe[string](len(s.buffered) + 10)
+	// the tool closes it as well
+	sr.SetAutomaticClose()
+	for _, fragment := range s.buffered {
+		sw.Send(fragment, nil)
+	}
+	s.buffered = nil
+	s.sw = sw
+	s.started = true
+
+	ctx := callbacks.ReuseHandlers(d.ctx, &callbacks.RunInfo{
+		Name:      s.name,
+		Type:      meta.componentImplType,
+		Component: meta.component,
+	})
+	ctx = setToolCallInfo(ctx, &toolCallInfo{toolCallID: s.callID})
+
+	d.wg.Add(1)
+	go func() {
+		defer func() {
+			panicErr := recover()
+			if panicErr != nil {
+				s.err = safe.NewPanicErr(panicErr, debug.Stack())
+			}
+			// unblock the dispatcher if the tool returns without reading all the arguments
+			sr.Close()
+			d.wg.Done()
+		}()
+		s.output, s.err = endpoint(ctx, sr, d.opts...)
+	}()
+}
+
+// closeAll ends the arguments of the started tools, with err if the input fails, and waits for the tools to finish.
+func (d *argumentsDispatcher) closeAll(err error) {
+	for _, s := range d.streams {
+		if !s.started {
+			continue
+		}
+		if err != nil {
+			s.sw.Send("", err)
+		}
+		s.sw.Close()
+	}
+	d.wg.Wait()
+}
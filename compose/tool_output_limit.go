@@ -0,0 +1,252 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime/debug"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ToolOutputLimitStrategy decides how to shorten a tool result exceeding ToolOutputLimit.MaxLength.
+type ToolOutputLimitStrategy string
+
+const (
+	// KeepHead keeps the beginning of the result, and replaces the rest with a marker telling how many characters are truncated.
+	// The output of streamable tools is still streamed, until the limit is reached.
+	KeepHead ToolOutputLimitStrategy = "keep_head"
+	// KeepHeadAndTail keeps the beginning and the end of the result, and replaces the middle with the marker,
+	// which suits the outputs ending with the conclusion, e.g. the logs of a build or the stack of an error.
+	KeepHeadAndTail ToolOutputLimitStrategy = "keep_head_and_tail"
+	// Summarize replaces the result with the summary generated by ToolOutputLimit.Summarizer.
+	// If the summarizer fails, the result is shortened by KeepHeadAndTail instead.
+	Summarize ToolOutputLimitStrategy = "summarize"
+)
+
+// ToolOutputLimit bounds the size of a tool result before it's sent back to the model,
+// so that a single verbose tool can't blow the context window.
+type ToolOutputLimit struct {
+	// MaxLength is the max length of the result in characters, results not longer than it are kept as is.
+	// Zero means no limit.
+	MaxLength int
+	// Strategy decides how to shorten the results exceeding MaxLength, KeepHead by default.
+	Strategy ToolOutputLimitStrategy
+	// Summarizer generates the summary of the result for Summarize. Required if Strategy is Summarize.
+	Summarizer model.BaseChatModel
+}
+
+func (l *ToolOutputLimit) check() error {
+	if l == nil {
+		return nil
+	}
+	if l.MaxLength < 0 {
+		return fmt.Errorf("invalid tool output max length: %d", l.MaxLength)
+	}
+	switch l.Strategy {
+	case "", KeepHead, KeepHeadAndTail:
+	case Summarize:
+		if l.Summarizer == nil {
+			return errors.New("summarizer is required for the summarize tool output limit strategy")
+		}
+	default:
+		return fmt.Errorf("unknown tool output limit strategy: %s", l.Strategy)
+	}
+	return nil
+}
+
+func (tn *ToolsNode) toolOutputLimit(name string) *ToolOutputLimit {
+	l := tn.outputLimit
+	if pl, ok := tn.outputLimits[name]; ok {
+		l = pl
+	}
+	if l == nil || l.MaxLength == 0 {
+		return nil
+	}
+	return l
+}
+
+// limitOutputs bounds the outputs of the successful tasks of invoke.
+func (tn *ToolsNode) limitOutputs(ctx context.Context, tasks []toolCallTask) {
+	for i := range tasks {
+		if tasks[i].err != nil || !tasks[i].executed {
+			continue
+		}
+		if l := tn.toolOutputLimit(tasks[i].name); l != nil {
+			tasks[i].output = l.limit(ctx, tasks[i].name, tasks[i].output)
+		}
+	}
+}
+
+// limitStreamOutputs bounds the output streams of the successful tasks of stream.
+func (tn *ToolsNode) limitStreamOutputs(ctx context.Context, tasks []toolCallTask) {
+	for i := range tasks {
+		if tasks[i].err != nil || tasks[i].sOutput == nil {
+			continue
+		}
+		if l := tn.toolOutputLimit(tasks[i].name); l != nil {
+			tasks[i].sOutput = l.limitStream(ctx, tasks[i].name, tasks[i].sOutput)
+		}
+	}
+}
+
+// truncationMarker replaces the truncated part of the result.
+func truncationMarker(truncated int) string {
+	return fmt.Sprintf("\n...[%d characters truncated]...\n", truncated)
+}
+
+// limit shortens the output to at most MaxLength characters, including the marker,
+// so applying the limit again to the output doesn't change it.
+func (l *ToolOutputLimit) limit(ctx context.Context, name, output string) string {
+	total := utf8.RuneCountInString(output)
+	if total <= l.MaxLength {
+		return output
+	}
+
+	switch l.Strategy {
+	case Summarize:
+		summary, err := l.summarize(ctx, name, output)
+		if err == nil {
+			if utf8.RuneCountInString(summary) > l.MaxLength {
+				return keepHead(summary, utf8.RuneCountInString(summary), l.MaxLength)
+			}
+			return summary
+		}
+		return keepHeadAndTail(output, total, l.MaxLength)
+	case KeepHeadAndTail:
+		return keepHeadAndTail(output, total, l.MaxLength)
+	default:
+		return keepHead(output, total, l.MaxLength)
+	}
+}
+
+func keepHead(output string, total, maxLength int) string {
+	keep := maxLength - utf8.RuneCountInString(truncationMarker(total))
+	if keep < 0 {
+		keep = 0
+	}
+	runes := []rune(output)
+	return string(runes[:keep]) + truncationMarker(total-keep)
+}
+
+func keepHeadAndTail(output string, total, maxLength int) string {
+	keep := maxLength - utf8.RuneCountInString(truncationMarker(total))
+	if keep < 0 {
+		keep = 0
+	}
+	head := (keep + 1) / 2
+	tail := keep - head
+	runes := []rune(output)
+	return string(runes[:head]) + truncationMarker(total-keep) + string(runes[total-tail:])
+}
+
+func (l *ToolOutputLimit) summarize(ctx context.Context, name, output string) (string, error) {
+	msg, err := l.Summarizer.Generate(ctx, []*schema.Message{
+		schema.SystemMessage(fmt.Sprintf("Summarize the output of the tool '%s' below for the assistant who called it. "+
+			"Keep the key facts, numbers, identifiers and errors, and keep the summary within %d characters.", name, l.MaxLength)),
+		schema.UserMessage(output),
+	})
+	if err != nil {
+		return "", err
+	}
+	return msg.Content, nil
+}
+
+// limitStream bounds the output stream. KeepHead passes the chunks through until the limit is reached,
+// the other strategies need the complete output, which is read in a separate goroutine.
+func (l *ToolOutputLimit) limitStream(ctx context.Context, name string, sr *schema.StreamReader[string]) *schema.StreamReader[string] {
+	out, sw := schema.Pipe[string](0)
+	go func() {
+		defer func() {
+			if panicErr := recover(); panicErr != nil {
+				sw.Send("", safe.NewPanicErr(panicErr, debug.Stack()))
+			}
+			sw.Close()
+			sr.Close()
+		}()
+
+		if l.Strategy == "" || l.Strategy == KeepHead {
+			l.keepStreamHead(sr, sw)
+			return
+		}
+
+		var sb strings.Builder
+		for {
+			chunk, err := sr.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				sw.Send("", err)
+				return
+			}
+			sb.WriteString(chunk)
+		}
+		sw.Send(l.limit(ctx, name, sb.String()), nil)
+	}()
+	return out
+}
+
+func (l *ToolOutputLimit) keepStreamHead(sr *schema.StreamReader[string], sw *schema.StreamWriter[string]) {
+	// the total length is unknown until the end, so reserve the space for the longest marker
+	keep := l.MaxLength - utf8.RuneCountInString(truncationMarker(math.MaxInt32))
+	if keep < 0 {
+		keep = 0
+	}
+
+	var sent, truncated int
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			sw.Send("", err)
+			return
+		}
+		n := utf8.RuneCountInString(chunk)
+		if truncated > 0 {
+			truncated += n
+			continue
+		}
+		if sent+n <= keep {
+			sent += n
+			if closed := sw.Send(chunk, nil); closed {
+				return
+			}
+			continue
+		}
+		head := keep - sent
+		truncated = n - head
+		if head > 0 {
+			if closed := sw.Send(string([]rune(chunk)[:head]), nil); closed {
+				return
+			}
+		}
+	}
+	if truncated > 0 {
+		sw.Send(truncationMarker(truncated), nil)
+	}
+}
@@ -0,0 +1,171 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestToolsNodeOutputLimit(t *testing.T) {
+	ctx := context.Background()
+
+	verbose := strings.Repeat("a", 100) + strings.Repeat("b", 100)
+	logTool, err := utils.InferTool("log", "read logs", func(ctx context.Context, in map[string]any) (string, error) {
+		return verbose, nil
+	})
+	assert.NoError(t, err)
+	listTool, err := utils.InferTool("list", "list files", func(ctx context.Context, in map[string]any) (string, error) {
+		return verbose, nil
+	})
+	assert.NoError(t, err)
+	shortTool, err := utils.InferTool("short", "short output", func(ctx context.Context, in map[string]any) (string, error) {
+		return "ok", nil
+	})
+	assert.NoError(t, err)
+	streamTool, err := utils.InferStreamTool("tail", "tail logs", func(ctx context.Context, in map[string]any) (*schema.StreamReader[string], error) {
+		chunks := make([]string, 10)
+		for i := range chunks {
+			chunks[i] = strings.Repeat(fmt.Sprint(i), 20)
+		}
+		return schema.StreamReaderFromArray(chunks), nil
+	})
+	assert.NoError(t, err)
+
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:           []tool.BaseTool{logTool, listTool, shortTool, streamTool},
+		ToolOutputLimit: &ToolOutputLimit{MaxLength: 80},
+		ToolOutputLimits: map[string]*ToolOutputLimit{
+			"log":  {MaxLength: 80, Strategy: KeepHeadAndTail},
+			"list": nil,
+		},
+	})
+	assert.NoError(t, err)
+
+	input := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "log", Arguments: `{}`}},
+		{ID: "2", Function: schema.FunctionCall{Name: "list", Arguments: `{}`}},
+		{ID: "3", Function: schema.FunctionCall{Name: "short", Arguments: `{}`}},
+	})
+	messages, err := tn.Invoke(ctx, input)
+	assert.NoError(t, err)
+	assert.Equal(t, 80, utf8.RuneCountInString(messages[0].Content))
+	assert.True(t, strings.HasPrefix(messages[0].Content, "aaa"))
+	assert.True(t, strings.HasSuffix(messages[0].Content, "bbb"))
+	assert.Contains(t, messages[0].Content, "characters truncated")
+	assert.Equal(t, verbose, messages[1].Content)
+	assert.Equal(t, "ok", messages[2].Content)
+
+	sr, err := tn.Stream(ctx, schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "tail", Arguments: `{}`}},
+	}))
+	assert.NoError(t, err)
+	var chunks [][]*schema.Message
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+	messages, err = schema.ConcatMessageArray(chunks)
+	assert.NoError(t, err)
+	content := messages[0].Content
+	assert.LessOrEqual(t, utf8.RuneCountInString(content), 80)
+	assert.True(t, strings.HasPrefix(content, strings.Repeat("0", 20)))
+	kept := strings.Index(content, "\n")
+	assert.Equal(t, truncationMarker(200-kept), content[kept:])
+
+	_, err = NewToolNode(ctx, &ToolsNodeConfig{
+		Tools:            []tool.BaseTool{logTool},
+		ToolOutputLimits: map[string]*ToolOutputLimit{"log": {MaxLength: 80, Strategy: Summarize}},
+	})
+	assert.ErrorContains(t, err, "summarizer is required")
+}
+
+func TestToolOutputLimitSummarize(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	summarizer := model.NewMockChatModel(ctrl)
+
+	l := &ToolOutputLimit{MaxLength: 60, Strategy: Summarize, Summarizer: summarizer}
+	verbose := strings.Repeat("a", 100) + strings.Repeat("b", 100)
+
+	summarizer.EXPECT().Generate(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...any) (*schema.Message, error) {
+			assert.Contains(t, input[0].Content, "'log'")
+			assert.Equal(t, verbose, input[1].Content)
+			return schema.AssistantMessage("100 a and 100 b", nil), nil
+		})
+	assert.Equal(t, "100 a and 100 b", l.limit(ctx, "log", verbose))
+
+	// the summary exceeding the limit is truncated
+	summarizer.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(schema.AssistantMessage(verbose, nil), nil)
+	summary := l.limit(ctx, "log", verbose)
+	assert.Equal(t, 60, utf8.RuneCountInString(summary))
+	assert.True(t, strings.HasPrefix(summary, "aaa"))
+
+	// falls back to keeping the head and the tail if the summarizer fails
+	summarizer.EXPECT().Generate(gomock.Any(), gomock.Any()).Return(nil, errors.New("unavailable"))
+	fallback := l.limit(ctx, "log", verbose)
+	assert.Equal(t, 60, utf8.RuneCountInString(fallback))
+	assert.True(t, strings.HasSuffix(fallback, "bbb"))
+
+	// limiting a limited output changes nothing, so the results restored on resume are kept as is
+	assert.Equal(t, fallback, l.limit(ctx, "log", fallback))
+
+	sr := l.limitStream(ctx, "log", schema.StreamReaderFromArray([]string{"short"}))
+	out, err := concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "short", out)
+}
+
+func TestToolOutputLimitKeepHead(t *testing.T) {
+	ctx := context.Background()
+	l := &ToolOutputLimit{MaxLength: 50}
+
+	output := strings.Repeat("中", 100)
+	limited := l.limit(ctx, "t", output)
+	assert.LessOrEqual(t, utf8.RuneCountInString(limited), 50)
+	kept := strings.Index(limited, "\n")
+	assert.Equal(t, strings.Repeat("中", kept/len("中")), limited[:kept])
+	assert.Equal(t, truncationMarker(100-kept/len("中")), limited[kept:])
+	assert.Equal(t, limited, l.limit(ctx, "t", limited))
+
+	// a limit shorter than the marker keeps the marker only
+	l = &ToolOutputLimit{MaxLength: 5}
+	assert.Equal(t, truncationMarker(100), l.limit(ctx, "t", output))
+
+	sr := l.limitStream(ctx, "t", schema.StreamReaderFromArray([]string{"a", "b", "c", "d", "e", "f"}))
+	out, err := concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, truncationMarker(6), out)
+}
@@ -0,0 +1,208 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrReplayMiss is matched by the error of a chat model or tools node whose input isn't found in the entries of WithTraceReplay.
+var ErrReplayMiss = errors.New("no recorded execution to replay")
+
+// ReplayEntry is a recorded execution of a chat model or tools node, replayed by WithTraceReplay.
+// The entries are usually extracted from the exported trace by trace.ReplayEntries.
+type ReplayEntry struct {
+	// Path is the path of the node, starting from the top graph.
+	Path []string `json:"path"`
+	// InputHash is the hash of the input of the node, see HashReplayInput.
+	InputHash string `json:"input_hash"`
+	// Output is the output of the node in JSON, i.e. the *schema.Message of a chat model node,
+	// or the []*schema.Message of a tools node.
+	Output json.RawMessage `json:"output,omitempty"`
+	// Error is the error message if the node failed.
+	Error string `json:"error,omitempty"`
+}
+
+// HashReplayInput returns the hash of the input of a node matching the ReplayEntry, i.e. the SHA-256 of its JSON.
+// The input of a chat model node is the []*schema.Message, and the input of a tools node is the *schema.Message.
+func HashReplayInput(input any) (string, error) {
+	data, err := json.Marshal(input)
+	if err != nil {
+		return "", fmt.Errorf("marshal input fail: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+type traceReplayOptions struct {
+	fallThrough bool
+}
+
+// TraceReplayOption is the option for WithTraceReplay.
+type TraceReplayOption func(o *traceReplayOptions)
+
+// WithReplayFallThrough runs the nodes whose input isn't found in the entries, instead of failing them with ErrReplayMiss.
+func WithReplayFallThrough() TraceReplayOption {
+	return func(o *traceReplayOptions) {
+		o.fallThrough = true
+	}
+}
+
+// WithTraceReplay satisfies the chat model and tools nodes from the recorded entries instead of calling the models and the tools,
+// so that a run observed in production can be reproduced and stepped through locally.
+// A node is matched by its path and the hash of its input, the other nodes run as usual.
+// If a node runs with the same input multiple times, e.g. retried, the entries are used in order, and the last one is reused once they are used up.
+// A node not matched fails with ErrReplayMiss, unless WithReplayFallThrough is set.
+// Nodes with input or output keys always run, as their inputs and outputs aren't the ones recorded.
+// e.g.
+//
+//	runs, err := trace.Load(f)
+//	entries, err := trace.ReplayEntries(runs[0])
+//	input, err := trace.Decode[[]*schema.Message](runs[0].Roots[0].Input)
+//	out, err := runnable.Invoke(ctx, input, compose.WithTraceReplay(entries))
+func WithTraceReplay(entries []*ReplayEntry, opts ...TraceReplayOption) Option {
+	o := &traceReplayOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	tr := &traceReplay{entries: make(map[string][]*ReplayEntry), fallThrough: o.fallThrough}
+	for _, e := range entries {
+		key := traceReplayKeyOf(e.Path, e.InputHash)
+		tr.entries[key] = append(tr.entries[key], e)
+	}
+	return Option{
+		traceReplay: tr,
+	}
+}
+
+type traceReplayKey struct{}
+
+type traceReplay struct {
+	mu          sync.Mutex
+	entries     map[string][]*ReplayEntry
+	fallThrough bool
+}
+
+func traceReplayKeyOf(path []string, inputHash string) string {
+	return pathKey(path) + "\x01" + inputHash
+}
+
+// take returns the next entry of the path and the input hash, or false if not found.
+func (r *traceReplay) take(path []string, inputHash string) (*ReplayEntry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key := traceReplayKeyOf(path, inputHash)
+	entries := r.entries[key]
+	if len(entries) == 0 {
+		return nil, false
+	}
+	if len(entries) > 1 {
+		r.entries[key] = entries[1:]
+	}
+	return entries[0], true
+}
+
+var (
+	replayMessagesType = reflect.TypeOf([]*schema.Message{})
+	replayMessageType  = reflect.TypeOf(&schema.Message{})
+)
+
+// isTraceReplayable tells whether the node is a chat model or tools node whose input and output are the ones recorded.
+func isTraceReplayable(action *composableRunnable) bool {
+	if action.meta == nil {
+		return false
+	}
+	switch action.meta.component {
+	case components.ComponentOfChatModel:
+		return action.inputType == replayMessagesType && action.outputType == replayMessageType
+	case ComponentOfToolsNode:
+		return action.inputType == replayMessageType && action.outputType == replayMessagesType
+	default:
+		return false
+	}
+}
+
+// traceReplayTask substitutes the recorded output for the task if matched, returns false if the task should run.
+func traceReplayTask(t *task) bool {
+	tr, ok := t.ctx.Value(traceReplayKey{}).(*traceReplay)
+	if !ok {
+		return false
+	}
+	path, ok := getNodeKey(t.ctx)
+	if !ok || path == nil || !isTraceReplayable(t.call.action) {
+		return false
+	}
+
+	input := t.input
+	sr, isStream := t.input.(streamReader)
+	if isStream {
+		srs := sr.copy(2)
+		t.input = srs[0]
+		var err error
+		input, err = t.call.action.inputStreamConvertPair.concatStream(srs[1])
+		if err != nil {
+			srs[0].close()
+			t.err = fmt.Errorf("concat input of node[%v] to replay fail: %w", path.path, err)
+			return true
+		}
+	}
+
+	hash, err := HashReplayInput(input)
+	if err != nil {
+		if isStream {
+			t.input.(streamReader).close()
+		}
+		t.err = fmt.Errorf("hash input of node[%v] to replay fail: %w", path.path, err)
+		return true
+	}
+	entry, ok := tr.take(path.path, hash)
+	if !ok && tr.fallThrough {
+		return false
+	}
+	if isStream {
+		t.input.(streamReader).close()
+	}
+	if !ok {
+		t.err = fmt.Errorf("%w, node: %v, input hash: %s", ErrReplayMiss, path.path, hash)
+		return true
+	}
+	if entry.Error != "" {
+		t.err = errors.New(entry.Error)
+		return true
+	}
+
+	output := reflect.New(t.call.action.outputType)
+	if err = json.Unmarshal(entry.Output, output.Interface()); err != nil {
+		t.err = fmt.Errorf("unmarshal replayed output of node[%v] fail: %w", path.path, err)
+		return true
+	}
+	if isStream {
+		t.output, t.err = t.call.action.outputStreamConvertPair.restoreStream(output.Elem().Interface())
+		return true
+	}
+	t.output = output.Elem().Interface()
+	return true
+}
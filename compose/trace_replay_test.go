@@ -0,0 +1,98 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model/modeltest"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestWithTraceReplay(t *testing.T) {
+	ctx := context.Background()
+
+	toolCalls := 0
+	search := utils.NewTool(&schema.ToolInfo{Name: "search"}, func(ctx context.Context, _ map[string]any) (string, error) {
+		toolCalls++
+		return "live result", nil
+	})
+	tn, err := NewToolNode(ctx, &ToolsNodeConfig{Tools: []tool.BaseTool{search}})
+	assert.NoError(t, err)
+	cm := modeltest.New(modeltest.When(modeltest.Any(), modeltest.Text("live answer")).Repeatedly())
+
+	g := NewGraph[[]*schema.Message, []*schema.Message]()
+	assert.NoError(t, g.AddChatModelNode("model", cm))
+	assert.NoError(t, g.AddToolsNode("tools", tn))
+	assert.NoError(t, g.AddEdge(START, "model"))
+	assert.NoError(t, g.AddEdge("model", "tools"))
+	assert.NoError(t, g.AddEdge("tools", END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	input := []*schema.Message{schema.UserMessage("find it")}
+	recordedCall := schema.AssistantMessage("", []schema.ToolCall{
+		{ID: "1", Function: schema.FunctionCall{Name: "search", Arguments: "{}"}},
+	})
+	entry := func(path string, in, out any) *ReplayEntry {
+		hash, err := HashReplayInput(in)
+		assert.NoError(t, err)
+		data, err := json.Marshal(out)
+		assert.NoError(t, err)
+		return &ReplayEntry{Path: []string{path}, InputHash: hash, Output: data}
+	}
+	entries := []*ReplayEntry{
+		entry("model", input, recordedCall),
+		entry("tools", recordedCall, []*schema.Message{schema.ToolMessage("recorded result", "1")}),
+	}
+
+	out, err := r.Invoke(ctx, input, WithTraceReplay(entries))
+	assert.NoError(t, err)
+	assert.Equal(t, "recorded result", out[0].Content)
+	assert.Len(t, cm.Calls(), 0)
+	assert.Equal(t, 0, toolCalls)
+
+	sr, err := r.Stream(ctx, input, WithTraceReplay(entries))
+	assert.NoError(t, err)
+	out, err = concatStreamReader(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, "recorded result", out[0].Content)
+	assert.Len(t, cm.Calls(), 0)
+
+	// the input of the model differs from the recorded one
+	_, err = r.Invoke(ctx, []*schema.Message{schema.UserMessage("other")}, WithTraceReplay(entries))
+	assert.True(t, errors.Is(err, ErrReplayMiss))
+	assert.ErrorContains(t, err, "[model]")
+
+	// the model runs live, the tools node doesn't match the live tool call and runs live as well
+	_, err = r.Invoke(ctx, []*schema.Message{schema.UserMessage("other")}, WithTraceReplay(entries, WithReplayFallThrough()))
+	assert.ErrorContains(t, err, "no tool call")
+	assert.Len(t, cm.Calls(), 1)
+
+	// the recorded error is replayed
+	failed := entry("model", input, nil)
+	failed.Error = "model unavailable"
+	_, err = r.Invoke(ctx, input, WithTraceReplay([]*ReplayEntry{failed}))
+	assert.ErrorContains(t, err, "model unavailable")
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/cloudwego/eino/components/document"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/internal/generic"
+)
+
+// NodeRef refers to a node whose component accepts the call options of type TOption, e.g. model.Option of a ChatModel node.
+// Designating the options by DesignateOptions with a NodeRef makes the options of another component a compile error,
+// and WithNodeRefs verifies the referred nodes when the graph compiles.
+// e.g.
+//
+//	modelNode := compose.ChatModelNodeRef("model")
+//	r, err := graph.Compile(ctx, compose.WithNodeRefs(modelNode))
+//	out, err := r.Invoke(ctx, input, compose.DesignateOptions(modelNode, model.WithTemperature(0.7)))
+//	// compile error: embedding.Option doesn't match model.Option
+//	out, err = r.Invoke(ctx, input, compose.DesignateOptions(modelNode, embedding.WithModel("text-embedding-3-small")))
+type NodeRef[TOption any] struct {
+	path *NodePath
+}
+
+// NewNodeRef refers to the node of the path whose component accepts the call options of type TOption,
+// e.g. the Lambda node created with the option type. The path starts from the top graph.
+func NewNodeRef[TOption any](path ...string) NodeRef[TOption] {
+	return NodeRef[TOption]{path: NewNodePath(path...)}
+}
+
+// ChatModelNodeRef refers to the ChatModel node of the path.
+func ChatModelNodeRef(path ...string) NodeRef[model.Option] {
+	return NewNodeRef[model.Option](path...)
+}
+
+// ChatTemplateNodeRef refers to the ChatTemplate node of the path.
+func ChatTemplateNodeRef(path ...string) NodeRef[prompt.Option] {
+	return NewNodeRef[prompt.Option](path...)
+}
+
+// EmbeddingNodeRef refers to the Embedding node of the path.
+func EmbeddingNodeRef(path ...string) NodeRef[embedding.Option] {
+	return NewNodeRef[embedding.Option](path...)
+}
+
+// RetrieverNodeRef refers to the Retriever node of the path.
+func RetrieverNodeRef(path ...string) NodeRef[retriever.Option] {
+	return NewNodeRef[retriever.Option](path...)
+}
+
+// RerankerNodeRef refers to the Reranker node of the path.
+func RerankerNodeRef(path ...string) NodeRef[reranker.Option] {
+	return NewNodeRef[reranker.Option](path...)
+}
+
+// IndexerNodeRef refers to the Indexer node of the path.
+func IndexerNodeRef(path ...string) NodeRef[indexer.Option] {
+	return NewNodeRef[indexer.Option](path...)
+}
+
+// LoaderNodeRef refers to the Loader node of the path.
+func LoaderNodeRef(path ...string) NodeRef[document.LoaderOption] {
+	return NewNodeRef[document.LoaderOption](path...)
+}
+
+// DocumentTransformerNodeRef refers to the DocumentTransformer node of the path.
+func DocumentTransformerNodeRef(path ...string) NodeRef[document.TransformerOption] {
+	return NewNodeRef[document.TransformerOption](path...)
+}
+
+// ToolsNodeRef refers to the ToolsNode of the path.
+func ToolsNodeRef(path ...string) NodeRef[ToolsNodeOption] {
+	return NewNodeRef[ToolsNodeOption](path...)
+}
+
+// Path returns the path of the referred node.
+func (r NodeRef[TOption]) Path() *NodePath {
+	return r.path
+}
+
+func (r NodeRef[TOption]) nodeRef() (*NodePath, reflect.Type) {
+	return r.path, generic.TypeOf[TOption]()
+}
+
+// AnyNodeRef is a NodeRef of any option type, see WithNodeRefs.
+type AnyNodeRef interface {
+	nodeRef() (*NodePath, reflect.Type)
+}
+
+// DesignateOptions designates the options to the referred node, the options must be of the type the node accepts.
+func DesignateOptions[TOption any](node NodeRef[TOption], opts ...TOption) Option {
+	return withComponentOption(opts...).DesignateNodeWithPath(node.path)
+}
+
+// WithNodeRefs verifies the referred nodes when the graph compiles, the compilation fails with the path of the node
+// if a node doesn't exist, or doesn't accept the options of the NodeRef.
+func WithNodeRefs(refs ...AnyNodeRef) GraphCompileOption {
+	return func(o *graphCompileOptions) {
+		o.nodeRefs = append(o.nodeRefs, refs...)
+	}
+}
+
+// validateNodeRef verifies the node of the path exists and accepts the options of optionType.
+func (g *graph) validateNodeRef(path *NodePath, optionType reflect.Type) error {
+	if path == nil || len(path.path) == 0 {
+		return errors.New("node ref has an empty path")
+	}
+	cur := g
+	for i, key := range path.path {
+		gn, ok := cur.nodes[key]
+		if !ok {
+			return fmt.Errorf("node%v referred by the option of %s not found", path.path, optionType)
+		}
+		if i < len(path.path)-1 {
+			sub, ok := gn.g.(interface{ inner() *graph })
+			if !ok {
+				return fmt.Errorf("node%v isn't a sub graph, the node%v referred by the option of %s not found",
+					path.path[:i+1], path.path, optionType)
+			}
+			cur = sub.inner()
+			continue
+		}
+		if gn.g != nil || gn.cr == nil || gn.cr.optionType == nil {
+			return fmt.Errorf("node%v is a sub graph, which doesn't accept the option of %s", path.path, optionType)
+		}
+		if gn.cr.optionType != optionType {
+			return fmt.Errorf("node%v expects the option of %s, but is referred by the option of %s", path.path, gn.cr.optionType, optionType)
+		}
+	}
+	return nil
+}
+
+func (g *graph) inner() *graph {
+	return g
+}
+
+func (c *Chain[I, O]) inner() *graph {
+	return c.gg.graph
+}
+
+func (wf *Workflow[I, O]) inner() *graph {
+	return wf.g
+}
@@ -0,0 +1,64 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compose
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/model/modeltest"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestNodeRef(t *testing.T) {
+	ctx := context.Background()
+
+	cm := modeltest.New(modeltest.When(modeltest.Any(), modeltest.Text("ok")).Repeatedly())
+	sub := NewGraph[[]*schema.Message, *schema.Message]()
+	assert.NoError(t, sub.AddChatModelNode("model", cm))
+	assert.NoError(t, sub.AddEdge(START, "model"))
+	assert.NoError(t, sub.AddEdge("model", END))
+
+	newGraph := func() *Graph[[]*schema.Message, *schema.Message] {
+		g := NewGraph[[]*schema.Message, *schema.Message]()
+		assert.NoError(t, g.AddGraphNode("agent", sub))
+		assert.NoError(t, g.AddEdge(START, "agent"))
+		assert.NoError(t, g.AddEdge("agent", END))
+		return g
+	}
+
+	modelNode := ChatModelNodeRef("agent", "model")
+	r, err := newGraph().Compile(ctx, WithNodeRefs(modelNode))
+	assert.NoError(t, err)
+	_, err = r.Invoke(ctx, []*schema.Message{schema.UserMessage("hi")}, DesignateOptions(modelNode, model.WithTemperature(0.5)))
+	assert.NoError(t, err)
+	calls := cm.Calls()
+	assert.Len(t, calls, 1)
+	assert.Equal(t, float32(0.5), *calls[0].Options.Temperature)
+
+	_, err = newGraph().Compile(ctx, WithNodeRefs(EmbeddingNodeRef("agent", "model")))
+	assert.ErrorContains(t, err, "node[agent model] expects the option of model.Option, but is referred by the option of embedding.Option")
+	_, err = newGraph().Compile(ctx, WithNodeRefs(ChatModelNodeRef("agent", "missing")))
+	assert.ErrorContains(t, err, "node[agent missing] referred by the option of model.Option not found")
+	_, err = newGraph().Compile(ctx, WithNodeRefs(ChatModelNodeRef("agent")))
+	assert.ErrorContains(t, err, "node[agent] is a sub graph")
+	_, err = newGraph().Compile(ctx, WithNodeRefs(ChatModelNodeRef("agent", "model", "inner")))
+	assert.ErrorContains(t, err, "node[agent model] isn't a sub graph")
+}
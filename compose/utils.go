@@ -161,13 +161,18 @@ func initGraphCallbacks(ctx context.Context, info *nodeInfo, meta *executorMeta,
 	}
 
 	var cbs []callbacks.Handler
+	overridden := false
 	for i := range opts {
 		if len(opts[i].handler) != 0 && len(opts[i].paths) == 0 {
 			cbs = append(cbs, opts[i].handler...)
 		}
+		if len(opts[i].suppressedGlobalHandlers) > 0 || len(opts[i].replacedGlobalHandlers) > 0 {
+			ctx = icb.WithGlobalHandlerOverrides(ctx, opts[i].suppressedGlobalHandlers, opts[i].replacedGlobalHandlers)
+			overridden = true
+		}
 	}
 
-	if len(cbs) == 0 {
+	if len(cbs) == 0 && !overridden {
 		return icb.ReuseHandlers(ctx, ri)
 	}
 
@@ -183,6 +188,7 @@ func initNodeCallbacks(ctx context.Context, key string, info *nodeInfo, meta *ex
 
 	if info != nil {
 		ri.Name = info.name
+		ctx = icb.WithLabels(ctx, info.labels...)
 	}
 
 	var cbs []callbacks.Handler
@@ -283,6 +289,10 @@ func extractOption(nodes map[string]*chanCall, opts ...Option) (map[string][]any
 
 			if len(path.path) == 1 {
 				if len(opt.options) == 0 {
+					if opt.maxRunSteps > 0 && curNode.action.optionType == nil {
+						// forward runtime max steps to the designated sub graph, which may run in a different mode from the current graph
+						optMap[curNodeKey] = append(optMap[curNodeKey], Option{maxRunSteps: opt.maxRunSteps})
+					}
 					// sub graph common callbacks has been added to ctx in initNodeCallback and won't be passed to subgraph only pass options
 					// node callback also won't be passed
 					continue
@@ -26,6 +26,7 @@ import (
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/schema"
 )
@@ -102,6 +103,11 @@ func (wf *Workflow[I, O]) AddRetrieverNode(key string, retriever retriever.Retri
 	return wf.initNode(key)
 }
 
+func (wf *Workflow[I, O]) AddRerankerNode(key string, reranker reranker.Reranker, opts ...GraphAddNodeOpt) *WorkflowNode {
+	_ = wf.g.AddRerankerNode(key, reranker, opts...)
+	return wf.initNode(key)
+}
+
 func (wf *Workflow[I, O]) AddEmbeddingNode(key string, embedding embedding.Embedder, opts ...GraphAddNodeOpt) *WorkflowNode {
 	_ = wf.g.AddEmbeddingNode(key, embedding, opts...)
 	return wf.initNode(key)
@@ -27,9 +27,12 @@ import (
 	"go.uber.org/mock/gomock"
 
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
 	"github.com/cloudwego/eino/internal/mock/components/embedding"
 	"github.com/cloudwego/eino/internal/mock/components/indexer"
 	"github.com/cloudwego/eino/internal/mock/components/model"
+	mockReranker "github.com/cloudwego/eino/internal/mock/components/reranker"
+	"github.com/cloudwego/eino/internal/mock/components/retriever"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -1601,3 +1604,37 @@ func TestIndirectDependencyWithBranch(t *testing.T) {
 		assert.Equal(t, out, map[string]any{"output": 2, "static": 2})
 	})
 }
+
+func TestWorkflowReranker(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+
+	docs := []*schema.Document{{ID: "1", Content: "apple"}, {ID: "2", Content: "banana"}, {ID: "3", Content: "cherry"}}
+	r := retriever.NewMockRetriever(ctrl)
+	r.EXPECT().Retrieve(gomock.Any(), "yellow fruit", gomock.Any()).Return(docs, nil)
+
+	rr := mockReranker.NewMockReranker(ctrl)
+	rr.EXPECT().Rerank(gomock.Any(), "yellow fruit", docs, gomock.Any()).DoAndReturn(
+		func(ctx context.Context, query string, docs []*schema.Document, opts ...reranker.Option) ([]*schema.Document, error) {
+			topN := *reranker.GetCommonOptions(nil, opts...).TopN
+			return []*schema.Document{docs[1].WithScore(0.9), docs[0].WithScore(0.2)}[:topN], nil
+		})
+
+	type query struct {
+		Query string
+	}
+	wf := NewWorkflow[*query, []*schema.Document]()
+	wf.AddRetrieverNode("retriever", r).AddInput(START, FromField("Query"))
+	wf.AddRerankerNode("reranker", rr).
+		AddInput(START, MapFields("Query", "Query")).
+		AddInput("retriever", ToField("Docs"))
+	wf.End().AddInput("reranker")
+	run, err := wf.Compile(ctx)
+	assert.NoError(t, err)
+
+	out, err := run.Invoke(ctx, &query{Query: "yellow fruit"}, WithRerankerOption(reranker.WithTopN(1)))
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Equal(t, "banana", out[0].Content)
+	assert.Equal(t, 0.9, out[0].Score())
+}
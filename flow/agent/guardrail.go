@@ -0,0 +1,41 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"github.com/cloudwego/eino/components/model"
+)
+
+// DefaultGuardrailRefusal is the content of the refusal message when GuardrailViolation.Refusal is empty.
+const DefaultGuardrailRefusal = model.DefaultGuardrailRefusal
+
+// Guardrail inspects the messages sent to and received from the chat model. See model.Guardrail for details.
+type Guardrail = model.Guardrail
+
+// GuardrailViolation is the result of a guardrail blocking the model input or output.
+type GuardrailViolation = model.GuardrailViolation
+
+// AsGuardrailViolation finds the GuardrailViolation in the error chain.
+func AsGuardrailViolation(err error) (*GuardrailViolation, bool) {
+	return model.AsGuardrailViolation(err)
+}
+
+// NewGuardedChatModel wraps the chat model with guardrails, which are applied in order.
+// See model.NewGuardedChatModel for details.
+func NewGuardedChatModel(m model.BaseChatModel, guardrails ...Guardrail) model.BaseChatModel {
+	return model.NewGuardedChatModel(m, guardrails...)
+}
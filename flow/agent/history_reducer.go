@@ -0,0 +1,204 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// HistoryReducer reduces the accumulated messages before they are sent to the model, to keep them within the context window.
+// e.g. dropping the outputs of old tool calls, dropping the oldest messages beyond a token budget, or summarizing them.
+// The leading system messages are always kept by the reducers in this package,
+// and an assistant message calling tools is always kept or dropped together with its tool messages.
+// It can be used as react.AgentConfig.HistoryReducer, or as adk.AgentMiddleware.RewriteModelInput after conversion.
+type HistoryReducer func(ctx context.Context, messages []*schema.Message) ([]*schema.Message, error)
+
+// ChainHistoryReducers applies the reducers in order.
+func ChainHistoryReducers(reducers ...HistoryReducer) HistoryReducer {
+	return func(ctx context.Context, messages []*schema.Message) ([]*schema.Message, error) {
+		var err error
+		for _, r := range reducers {
+			messages, err = r(ctx, messages)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return messages, nil
+	}
+}
+
+// KeepLastToolTurns keeps only the last n tool turns, i.e. the assistant messages calling tools along with their tool messages.
+// The other messages, e.g. user messages and the final answers of assistant, are kept.
+func KeepLastToolTurns(n int) HistoryReducer {
+	return func(ctx context.Context, messages []*schema.Message) ([]*schema.Message, error) {
+		head, units := splitHistory(messages)
+		toolTurns := 0
+		for _, u := range units {
+			if u.isToolTurn() {
+				toolTurns++
+			}
+		}
+
+		ret := append(make([]*schema.Message, 0, len(messages)), head...)
+		for _, u := range units {
+			if u.isToolTurn() {
+				toolTurns--
+				if toolTurns >= n {
+					continue
+				}
+			}
+			ret = append(ret, u...)
+		}
+		return ret, nil
+	}
+}
+
+// TokenCounter estimates the number of tokens of a message.
+type TokenCounter func(msg *schema.Message) int
+
+// TokenBudget drops the oldest messages until the estimated tokens of the messages are within maxTokens.
+// The latest turn is always kept, even if it alone exceeds the budget.
+// Optional counter, defaults to a rough estimate of 4 characters per token.
+func TokenBudget(maxTokens int, counter TokenCounter) HistoryReducer {
+	if counter == nil {
+		counter = estimateTokens
+	}
+	return func(ctx context.Context, messages []*schema.Message) ([]*schema.Message, error) {
+		head, units := splitHistory(messages)
+		total := 0
+		for _, msg := range messages {
+			total += counter(msg)
+		}
+
+		drop := 0
+		for ; drop < len(units)-1 && total > maxTokens; drop++ {
+			for _, msg := range units[drop] {
+				total -= counter(msg)
+			}
+		}
+
+		ret := append(make([]*schema.Message, 0, len(messages)), head...)
+		for _, u := range units[drop:] {
+			ret = append(ret, u...)
+		}
+		return ret, nil
+	}
+}
+
+// SummaryPrompt is the system prompt for the model to summarize the conversation.
+var SummaryPrompt = `Summarize the conversation below, which will replace it in the context of the assistant.
+Keep the user's goals and requirements, the important facts and results from the tool calls, the decisions made and the work left to do.
+Be concise, and reply with the summary only.`
+
+// Summarize replaces the older messages with a summary generated by the model, when there are more than maxMessages messages.
+// The latest keepLast messages, extended to the start of their turn, are kept as they are.
+// The summary is put in a user message, which is summarized along with the other messages next time.
+func Summarize(m model.BaseChatModel, maxMessages, keepLast int) HistoryReducer {
+	return func(ctx context.Context, messages []*schema.Message) ([]*schema.Message, error) {
+		head, units := splitHistory(messages)
+		if len(messages)-len(head) <= maxMessages {
+			return messages, nil
+		}
+
+		// the units from split on are kept
+		split, kept := len(units), 0
+		for split > 0 && kept < keepLast {
+			split--
+			kept += len(units[split])
+		}
+		if split == 0 {
+			return messages, nil
+		}
+
+		var sb strings.Builder
+		for _, u := range units[:split] {
+			for _, msg := range u {
+				writeTranscript(&sb, msg)
+			}
+		}
+		summary, err := m.Generate(ctx, []*schema.Message{
+			schema.SystemMessage(SummaryPrompt),
+			schema.UserMessage(sb.String()),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to summarize history: %w", err)
+		}
+
+		ret := append(make([]*schema.Message, 0, len(head)+1+kept), head...)
+		ret = append(ret, schema.UserMessage("[Summary of the earlier conversation]\n"+summary.Content))
+		for _, u := range units[split:] {
+			ret = append(ret, u...)
+		}
+		return ret, nil
+	}
+}
+
+// historyUnit is a message, or an assistant message calling tools followed by its tool messages.
+type historyUnit []*schema.Message
+
+func (u historyUnit) isToolTurn() bool {
+	return len(u[0].ToolCalls) > 0
+}
+
+// splitHistory splits the messages into the leading system messages and the units after them.
+func splitHistory(messages []*schema.Message) (head []*schema.Message, units []historyUnit) {
+	i := 0
+	for i < len(messages) && messages[i].Role == schema.System {
+		i++
+	}
+	head = messages[:i]
+
+	for ; i < len(messages); i++ {
+		msg := messages[i]
+		if msg.Role == schema.Tool && len(units) > 0 && units[len(units)-1].isToolTurn() {
+			units[len(units)-1] = append(units[len(units)-1], msg)
+			continue
+		}
+		units = append(units, historyUnit{msg})
+	}
+	return head, units
+}
+
+func estimateTokens(msg *schema.Message) int {
+	n := len(msg.Content) + len(msg.ReasoningContent)
+	for _, tc := range msg.ToolCalls {
+		n += len(tc.Function.Name) + len(tc.Function.Arguments)
+	}
+	// the overhead of the role and format
+	return n/4 + 4
+}
+
+func writeTranscript(sb *strings.Builder, msg *schema.Message) {
+	switch {
+	case msg.Role == schema.Tool:
+		sb.WriteString(fmt.Sprintf("tool[%s] result: %s\n", msg.ToolName, msg.Content))
+	case len(msg.ToolCalls) > 0:
+		if msg.Content != "" {
+			sb.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, msg.Content))
+		}
+		for _, tc := range msg.ToolCalls {
+			sb.WriteString(fmt.Sprintf("%s called tool[%s] with: %s\n", msg.Role, tc.Function.Name, tc.Function.Arguments))
+		}
+	default:
+		sb.WriteString(fmt.Sprintf("%s: %s\n", msg.Role, msg.Content))
+	}
+}
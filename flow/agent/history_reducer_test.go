@@ -0,0 +1,118 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package agent
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/model"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func historyForTest() []*schema.Message {
+	toolTurn := func(id string) []*schema.Message {
+		return []*schema.Message{
+			schema.AssistantMessage("", []schema.ToolCall{{ID: id, Function: schema.FunctionCall{Name: "search", Arguments: `{"q":"` + id + `"}`}}}),
+			schema.ToolMessage("result "+id, id, schema.WithToolName("search")),
+		}
+	}
+	msgs := []*schema.Message{schema.SystemMessage("system"), schema.UserMessage("question")}
+	msgs = append(msgs, toolTurn("1")...)
+	msgs = append(msgs, toolTurn("2")...)
+	msgs = append(msgs, schema.AssistantMessage("answer", nil), schema.UserMessage("more"))
+	return append(msgs, toolTurn("3")...)
+}
+
+func contents(msgs []*schema.Message) []string {
+	ret := make([]string, 0, len(msgs))
+	for _, m := range msgs {
+		if len(m.ToolCalls) > 0 {
+			ret = append(ret, "call "+m.ToolCalls[0].ID)
+			continue
+		}
+		ret = append(ret, m.Content)
+	}
+	return ret
+}
+
+func TestKeepLastToolTurns(t *testing.T) {
+	ctx := context.Background()
+
+	msgs, err := KeepLastToolTurns(1)(ctx, historyForTest())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"system", "question", "answer", "more", "call 3", "result 3"}, contents(msgs))
+
+	msgs, err = KeepLastToolTurns(5)(ctx, historyForTest())
+	assert.NoError(t, err)
+	assert.Equal(t, historyForTest(), msgs)
+}
+
+func TestTokenBudget(t *testing.T) {
+	ctx := context.Background()
+	oneEach := func(*schema.Message) int { return 1 }
+
+	msgs, err := TokenBudget(5, oneEach)(ctx, historyForTest())
+	assert.NoError(t, err)
+	// the tool turns are dropped as a whole
+	assert.Equal(t, []string{"system", "answer", "more", "call 3", "result 3"}, contents(msgs))
+
+	msgs, err = TokenBudget(1, oneEach)(ctx, historyForTest())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"system", "call 3", "result 3"}, contents(msgs))
+
+	msgs, err = TokenBudget(10000, nil)(ctx, historyForTest())
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 10)
+}
+
+func TestSummarize(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			assert.Equal(t, SummaryPrompt, input[0].Content)
+			transcript := input[1].Content
+			assert.True(t, strings.HasPrefix(transcript, "user: question\n"))
+			assert.Contains(t, transcript, "assistant called tool[search] with: {\"q\":\"1\"}\n")
+			assert.Contains(t, transcript, "tool[search] result: result 2\n")
+			assert.Contains(t, transcript, "assistant: answer\n")
+			assert.NotContains(t, transcript, "more")
+			return schema.AssistantMessage("user asked a question, searched twice", nil), nil
+		}).Times(1)
+
+	reducer := Summarize(cm, 6, 3)
+
+	// within the limit
+	msgs, err := reducer(ctx, historyForTest()[:5])
+	assert.NoError(t, err)
+	assert.Len(t, msgs, 5)
+
+	msgs, err = reducer(ctx, historyForTest())
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"system",
+		"[Summary of the earlier conversation]\nuser asked a question, searched twice",
+		"more", "call 3", "result 3",
+	}, contents(msgs))
+}
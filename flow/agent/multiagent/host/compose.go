@@ -39,6 +39,8 @@ const (
 type state struct {
 	msgs              []*schema.Message
 	isMultipleIntents bool
+	// specialists is the snapshot of the specialists taken before calling the host
+	specialists *specialistSnapshot
 }
 
 // NewMultiAgent creates a new host multi-agent system.
@@ -81,22 +83,11 @@ func NewMultiAgent(ctx context.Context, config *MultiAgentConfig) (*MultiAgent,
 		return nil, err
 	}
 
-	agentTools := make([]*schema.ToolInfo, 0, len(config.Specialists))
+	registry := newSpecialistRegistry(config.Specialists)
 	agentMap := make(map[string]bool, len(config.Specialists)+1)
 	for i := range config.Specialists {
 		specialist := config.Specialists[i]
 
-		agentTools = append(agentTools, &schema.ToolInfo{
-			Name: specialist.Name,
-			Desc: specialist.IntendedUse,
-			ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
-				"reason": {
-					Type: schema.String,
-					Desc: "the reason to call this tool",
-				},
-			}),
-		})
-
 		if err := addSpecialistAgent(specialist, g); err != nil {
 			return nil, err
 		}
@@ -104,12 +95,17 @@ func NewMultiAgent(ctx context.Context, config *MultiAgentConfig) (*MultiAgent,
 		agentMap[specialist.Name] = true
 	}
 
-	chatModel, err := agent.ChatModelWithTools(config.Host.ChatModel, config.Host.ToolCallingModel, agentTools)
+	if err := addDynamicSpecialistsNode(g); err != nil {
+		return nil, err
+	}
+	agentMap[dynamicSpecialistsNodeKey] = true
+
+	chatModel, err := agent.ChatModelWithTools(config.Host.ChatModel, config.Host.ToolCallingModel, registry.load().tools)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = addHostAgent(chatModel, hostPrompt, g, hostKeyName); err != nil {
+	if err = addHostAgent(&dynamicToolsChatModel{inner: chatModel, registry: registry}, hostPrompt, registry, g, hostKeyName); err != nil {
 		return nil, err
 	}
 
@@ -148,6 +144,7 @@ func NewMultiAgent(ctx context.Context, config *MultiAgentConfig) (*MultiAgent,
 		runnable:         r,
 		graph:            g,
 		graphAddNodeOpts: []compose.GraphAddNodeOpt{compose.WithGraphCompileOptions(compileOpts...)},
+		registry:         registry,
 	}, nil
 }
 
@@ -184,9 +181,10 @@ func addSpecialistAgent(specialist *Specialist, g *compose.Graph[[]*schema.Messa
 	return g.AddEdge(specialist.Name, specialistsAnswersCollectorNodeKey)
 }
 
-func addHostAgent(model model.BaseChatModel, prompt string, g *compose.Graph[[]*schema.Message, *schema.Message], hostNodeName string) error {
+func addHostAgent(model model.BaseChatModel, prompt string, registry *specialistRegistry, g *compose.Graph[[]*schema.Message, *schema.Message], hostNodeName string) error {
 	preHandler := func(_ context.Context, input []*schema.Message, state *state) ([]*schema.Message, error) {
 		state.msgs = input
+		state.specialists = registry.load()
 		if len(prompt) == 0 {
 			return input, nil
 		}
@@ -226,15 +224,24 @@ func addMultiSpecialistsBranch(convertorName string, agentMap map[string]bool, g
 		}
 
 		results := map[string]bool{}
-		for _, toolCall := range input[0].ToolCalls {
-			results[toolCall.Function.Name] = true
-		}
-
-		if len(results) > 1 {
-			_ = compose.ProcessState(ctx, func(_ context.Context, state *state) error {
-				state.isMultipleIntents = true
-				return nil
-			})
+		err := compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+			names := map[string]bool{}
+			for _, toolCall := range input[0].ToolCalls {
+				name := toolCall.Function.Name
+				names[name] = true
+				if state.specialists.static[name] {
+					results[name] = true
+				} else if _, ok := state.specialists.dynamic[name]; ok {
+					results[dynamicSpecialistsNodeKey] = true
+				} else {
+					return fmt.Errorf("host agent called unknown specialist: %s", name)
+				}
+			}
+			state.isMultipleIntents = len(names) > 1
+			return nil
+		})
+		if err != nil {
+			return nil, err
 		}
 
 		return results, nil
@@ -25,6 +25,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	model2 "github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/flow/agent"
@@ -601,3 +602,79 @@ func newMockAgentCallback(expects int) *mockAgentCallback {
 	m.wg.Add(expects)
 	return m
 }
+
+func TestHostMultiAgentDynamicSpecialists(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	mockHostLLM := model.NewMockToolCallingChatModel(ctrl)
+	mockHostLLM.EXPECT().WithTools(gomock.Any()).Return(mockHostLLM, nil).AnyTimes()
+
+	newSpecialist := func(name string) *Specialist {
+		return &Specialist{
+			AgentMeta: AgentMeta{Name: name, IntendedUse: "answer as " + name},
+			Invokable: func(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
+				return schema.AssistantMessage(name+" answer to "+input[0].Content, nil), nil
+			},
+		}
+	}
+
+	hostMA, err := NewMultiAgent(ctx, &MultiAgentConfig{
+		Host:        Host{ToolCallingModel: mockHostLLM},
+		Specialists: []*Specialist{newSpecialist("static")},
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, hostMA.AddSpecialist(ctx, newSpecialist("plugin")))
+	assert.ErrorContains(t, hostMA.AddSpecialist(ctx, newSpecialist("plugin")), "already exists")
+	assert.Equal(t, []string{"static", "plugin"}, hostMA.Specialists())
+
+	var hostTools [][]string
+	handOff := func(names ...string) func(ctx context.Context, input []*schema.Message, opts ...model2.Option) (*schema.Message, error) {
+		return func(ctx context.Context, input []*schema.Message, opts ...model2.Option) (*schema.Message, error) {
+			var tools []string
+			for _, ti := range model2.GetCommonOptions(nil, opts...).Tools {
+				tools = append(tools, ti.Name)
+			}
+			hostTools = append(hostTools, tools)
+			var toolCalls []schema.ToolCall
+			for i, name := range names {
+				toolCalls = append(toolCalls, schema.ToolCall{Index: generic.PtrOf(i), ID: name, Function: schema.FunctionCall{Name: name, Arguments: "{}"}})
+			}
+			return schema.AssistantMessage("", toolCalls), nil
+		}
+	}
+
+	t.Run("generate by added specialist", func(t *testing.T) {
+		mockHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(handOff("plugin")).Times(1)
+		out, err := hostMA.Generate(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+		assert.Equal(t, "plugin answer to q", out.Content)
+	})
+
+	t.Run("stream by static and added specialists", func(t *testing.T) {
+		mockHostLLM.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, input []*schema.Message, opts ...model2.Option) (*schema.StreamReader[*schema.Message], error) {
+				msg, err := handOff("static", "plugin")(ctx, input, opts...)
+				return schema.StreamReaderFromArray([]*schema.Message{msg}), err
+			}).Times(1)
+		sr, err := hostMA.Stream(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.NoError(t, err)
+		out, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Contains(t, out.Content, "static answer to q")
+		assert.Contains(t, out.Content, "plugin answer to q")
+	})
+
+	t.Run("removed specialist", func(t *testing.T) {
+		assert.NoError(t, hostMA.RemoveSpecialist("static"))
+		assert.Equal(t, []string{"plugin"}, hostMA.Specialists())
+		assert.ErrorContains(t, hostMA.RemoveSpecialist("plugin"), "last specialist")
+		assert.ErrorContains(t, hostMA.AddSpecialist(ctx, newSpecialist("static")), "conflicts")
+
+		mockHostLLM.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(handOff("static")).Times(1)
+		_, err := hostMA.Generate(ctx, []*schema.Message{schema.UserMessage("q")})
+		assert.ErrorContains(t, err, "unknown specialist: static")
+	})
+
+	assert.Equal(t, [][]string{{"static", "plugin"}, {"static", "plugin"}, {"plugin"}}, hostTools)
+}
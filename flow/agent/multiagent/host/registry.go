@@ -0,0 +1,369 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package host
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+const dynamicSpecialistsNodeKey = "dynamic_specialists"
+
+// AddSpecialist registers a specialist to the multi-agent without recompiling the graph.
+// The specialist is visible to the host from the next run on, runs in progress are not affected.
+// The name of the specialist must be unique among all the specialists of the multi-agent.
+func (ma *MultiAgent) AddSpecialist(ctx context.Context, specialist *Specialist) error {
+	if ma.registry == nil {
+		return errors.New("multi agent is not created by NewMultiAgent")
+	}
+	if specialist == nil {
+		return errors.New("specialist is nil")
+	}
+	if err := specialist.validate(); err != nil {
+		return err
+	}
+
+	r, err := compileSpecialist(ctx, specialist)
+	if err != nil {
+		return fmt.Errorf("failed to compile specialist %s: %w", specialist.Name, err)
+	}
+
+	return ma.registry.add(specialist, r)
+}
+
+// RemoveSpecialist unregisters the specialist of the name, which can be either added by AddSpecialist
+// or configured in MultiAgentConfig.Specialists.
+// The specialist is hidden from the host from the next run on, runs in progress are not affected.
+func (ma *MultiAgent) RemoveSpecialist(name string) error {
+	if ma.registry == nil {
+		return errors.New("multi agent is not created by NewMultiAgent")
+	}
+	return ma.registry.remove(name)
+}
+
+// Specialists returns the names of the specialists currently available to the host, in the order of registration.
+func (ma *MultiAgent) Specialists() []string {
+	if ma.registry == nil {
+		return nil
+	}
+	snapshot := ma.registry.load()
+	names := make([]string, 0, len(snapshot.tools))
+	for _, t := range snapshot.tools {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// specialistRegistry holds the specialists available to the host.
+// Every change replaces the snapshot as a whole, and each run takes the snapshot once before calling the host,
+// so that the tool list of the host and the routing to the specialists are always consistent within a run.
+type specialistRegistry struct {
+	mu       sync.RWMutex
+	snapshot *specialistSnapshot
+	// modified is set once the specialists are changed after the creation of the multi-agent,
+	// before that, the host model only sees the tools bound at creation.
+	modified bool
+}
+
+type specialistSnapshot struct {
+	tools []*schema.ToolInfo
+	// static are the specialists added as graph nodes by NewMultiAgent
+	static map[string]bool
+	// dynamic are the specialists added by AddSpecialist, which run within the dynamic specialists node
+	dynamic map[string]compose.Runnable[[]*schema.Message, *schema.Message]
+}
+
+func newSpecialistRegistry(specialists []*Specialist) *specialistRegistry {
+	snapshot := &specialistSnapshot{
+		static:  make(map[string]bool, len(specialists)),
+		dynamic: make(map[string]compose.Runnable[[]*schema.Message, *schema.Message]),
+	}
+	for _, s := range specialists {
+		snapshot.tools = append(snapshot.tools, specialistToolInfo(s))
+		snapshot.static[s.Name] = true
+	}
+	return &specialistRegistry{snapshot: snapshot}
+}
+
+func (r *specialistRegistry) load() *specialistSnapshot {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.snapshot
+}
+
+func (r *specialistRegistry) isModified() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.modified
+}
+
+func (r *specialistRegistry) add(specialist *Specialist, runnable compose.Runnable[[]*schema.Message, *schema.Message]) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.snapshot.tools {
+		if t.Name == specialist.Name {
+			return fmt.Errorf("specialist %s already exists", specialist.Name)
+		}
+	}
+	if _, ok := r.snapshot.static[specialist.Name]; ok {
+		// the node of a removed static specialist still exists in the graph
+		return fmt.Errorf("specialist %s conflicts with a removed specialist of the multi-agent", specialist.Name)
+	}
+
+	next := r.snapshot.clone()
+	next.tools = append(next.tools, specialistToolInfo(specialist))
+	next.dynamic[specialist.Name] = runnable
+	r.snapshot = next
+	r.modified = true
+	return nil
+}
+
+func (r *specialistRegistry) remove(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	idx := -1
+	for i, t := range r.snapshot.tools {
+		if t.Name == name {
+			idx = i
+			break
+		}
+	}
+	if idx < 0 {
+		return fmt.Errorf("specialist %s not found", name)
+	}
+	if len(r.snapshot.tools) == 1 {
+		return fmt.Errorf("cannot remove the last specialist %s", name)
+	}
+
+	next := r.snapshot.clone()
+	next.tools = append(next.tools[:idx:idx], next.tools[idx+1:]...)
+	delete(next.dynamic, name)
+	// the static node is kept in the graph, but it's no longer routed to
+	if _, ok := next.static[name]; ok {
+		next.static[name] = false
+	}
+	r.snapshot = next
+	r.modified = true
+	return nil
+}
+
+func (s *specialistSnapshot) clone() *specialistSnapshot {
+	c := &specialistSnapshot{
+		tools:   make([]*schema.ToolInfo, len(s.tools)),
+		static:  make(map[string]bool, len(s.static)),
+		dynamic: make(map[string]compose.Runnable[[]*schema.Message, *schema.Message], len(s.dynamic)),
+	}
+	copy(c.tools, s.tools)
+	for k, v := range s.static {
+		c.static[k] = v
+	}
+	for k, v := range s.dynamic {
+		c.dynamic[k] = v
+	}
+	return c
+}
+
+func specialistToolInfo(specialist *Specialist) *schema.ToolInfo {
+	return &schema.ToolInfo{
+		Name: specialist.Name,
+		Desc: specialist.IntendedUse,
+		ParamsOneOf: schema.NewParamsOneOfByParams(map[string]*schema.ParameterInfo{
+			"reason": {
+				Type: schema.String,
+				Desc: "the reason to call this tool",
+			},
+		}),
+	}
+}
+
+// compileSpecialist compiles the specialist into a runnable, the same as the specialist node added by NewMultiAgent.
+func compileSpecialist(ctx context.Context, specialist *Specialist) (compose.Runnable[[]*schema.Message, *schema.Message], error) {
+	chain := compose.NewChain[[]*schema.Message, *schema.Message]()
+	if specialist.Invokable != nil || specialist.Streamable != nil {
+		lambda, err := compose.AnyLambda(specialist.Invokable, specialist.Streamable, nil, nil, compose.WithLambdaType("Specialist"))
+		if err != nil {
+			return nil, err
+		}
+		chain.AppendLambda(lambda, compose.WithNodeName(specialist.Name))
+	} else {
+		prompt := specialist.SystemPrompt
+		chain.AppendLambda(compose.InvokableLambda(func(_ context.Context, input []*schema.Message) ([]*schema.Message, error) {
+			if len(prompt) > 0 {
+				return append([]*schema.Message{schema.SystemMessage(prompt)}, input...), nil
+			}
+			return input, nil
+		}))
+		chain.AppendChatModel(specialist.ChatModel, compose.WithNodeName(specialist.Name))
+	}
+	return chain.Compile(ctx, compose.WithGraphName(specialist.Name))
+}
+
+// addDynamicSpecialistsNode adds the node running the specialists added by AddSpecialist,
+// its output is keyed by the names of the specialists, the same as the specialist nodes.
+func addDynamicSpecialistsNode(g *compose.Graph[[]*schema.Message, *schema.Message]) error {
+	chosen := func(ctx context.Context, input []*schema.Message) (msgs []*schema.Message, runnables map[string]compose.Runnable[[]*schema.Message, *schema.Message], err error) {
+		err = compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+			msgs = state.msgs
+			runnables = make(map[string]compose.Runnable[[]*schema.Message, *schema.Message])
+			for _, msg := range input {
+				for _, toolCall := range msg.ToolCalls {
+					if r, ok := state.specialists.dynamic[toolCall.Function.Name]; ok {
+						runnables[toolCall.Function.Name] = r
+					}
+				}
+			}
+			return nil
+		})
+		return msgs, runnables, err
+	}
+
+	invoke := func(ctx context.Context, input []*schema.Message, _ ...any) (map[string]any, error) {
+		msgs, runnables, err := chosen(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		var (
+			wg     sync.WaitGroup
+			mu     sync.Mutex
+			output = make(map[string]any, len(runnables))
+			errs   []error
+		)
+		for name, r := range runnables {
+			name, r := name, r
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				defer func() {
+					if panicErr := recover(); panicErr != nil {
+						mu.Lock()
+						errs = append(errs, safe.NewPanicErr(panicErr, debug.Stack()))
+						mu.Unlock()
+					}
+				}()
+				out, err := r.Invoke(ctx, msgs)
+				mu.Lock()
+				defer mu.Unlock()
+				if err != nil {
+					errs = append(errs, fmt.Errorf("specialist %s failed: %w", name, err))
+					return
+				}
+				output[name] = out
+			}()
+		}
+		wg.Wait()
+		if len(errs) > 0 {
+			return nil, errs[0]
+		}
+		return output, nil
+	}
+
+	stream := func(ctx context.Context, input []*schema.Message, _ ...any) (*schema.StreamReader[map[string]any], error) {
+		msgs, runnables, err := chosen(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		srs := make([]*schema.StreamReader[map[string]any], 0, len(runnables))
+		for name, r := range runnables {
+			sr, err := r.Stream(ctx, msgs)
+			if err != nil {
+				for _, s := range srs {
+					s.Close()
+				}
+				return nil, fmt.Errorf("specialist %s failed: %w", name, err)
+			}
+			key := name
+			srs = append(srs, schema.StreamReaderWithConvert(sr, func(msg *schema.Message) (map[string]any, error) {
+				return map[string]any{key: msg}, nil
+			}))
+		}
+		return schema.MergeStreamReaders(srs), nil
+	}
+
+	lambda, err := compose.AnyLambda(invoke, stream, nil, nil, compose.WithLambdaType("Specialist"))
+	if err != nil {
+		return err
+	}
+	if err = g.AddLambdaNode(dynamicSpecialistsNodeKey, lambda, compose.WithNodeName(dynamicSpecialistsNodeKey)); err != nil {
+		return err
+	}
+	return g.AddEdge(dynamicSpecialistsNodeKey, specialistsAnswersCollectorNodeKey)
+}
+
+// dynamicToolsChatModel sends the specialists of the current snapshot to the host model as tools,
+// once the specialists are changed after the creation of the multi-agent.
+type dynamicToolsChatModel struct {
+	inner    model.BaseChatModel
+	registry *specialistRegistry
+}
+
+func (m *dynamicToolsChatModel) GetType() string {
+	if typ, ok := components.GetType(m.inner); ok {
+		return typ
+	}
+	return "HostChatModel"
+}
+
+func (m *dynamicToolsChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(m.inner)
+}
+
+func (m *dynamicToolsChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	opts, err := m.withTools(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return m.inner.Generate(ctx, input, opts...)
+}
+
+func (m *dynamicToolsChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	opts, err := m.withTools(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return m.inner.Stream(ctx, input, opts...)
+}
+
+func (m *dynamicToolsChatModel) withTools(ctx context.Context, opts []model.Option) ([]model.Option, error) {
+	if !m.registry.isModified() {
+		return opts, nil
+	}
+	var tools []*schema.ToolInfo
+	err := compose.ProcessState(ctx, func(_ context.Context, state *state) error {
+		tools = state.specialists.tools
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	nOpts := make([]model.Option, 0, len(opts)+1)
+	nOpts = append(nOpts, model.WithTools(tools))
+	// tools passed by the caller take precedence
+	return append(nOpts, opts...), nil
+}
@@ -36,6 +36,7 @@ type MultiAgent struct {
 	runnable         compose.Runnable[[]*schema.Message, *schema.Message]
 	graph            *compose.Graph[[]*schema.Message, *schema.Message]
 	graphAddNodeOpts []compose.GraphAddNodeOpt
+	registry         *specialistRegistry
 }
 
 func (ma *MultiAgent) Generate(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
@@ -71,7 +72,9 @@ func (ma *MultiAgent) HostNodeKey() string {
 
 // MultiAgentConfig is the config for host multi-agent system.
 type MultiAgentConfig struct {
-	Host        Host
+	Host Host
+	// Specialists are the specialists available when the multi-agent is created,
+	// more can be added or removed later by MultiAgent.AddSpecialist and MultiAgent.RemoveSpecialist.
 	Specialists []*Specialist
 
 	Name         string // the name of the host multi-agent
@@ -112,11 +115,7 @@ func (conf *MultiAgentConfig) validate() error {
 	}
 
 	for _, s := range conf.Specialists {
-		if s.ChatModel == nil && s.Invokable == nil && s.Streamable == nil {
-			return fmt.Errorf("specialist %s has no chat model or Invokable or Streamable", s.Name)
-		}
-
-		if err := s.AgentMeta.validate(); err != nil {
+		if err := s.validate(); err != nil {
 			return err
 		}
 	}
@@ -169,6 +168,14 @@ type Specialist struct {
 	Streamable compose.Stream[[]*schema.Message, *schema.Message, agent.AgentOption]
 }
 
+func (s *Specialist) validate() error {
+	if s.ChatModel == nil && s.Invokable == nil && s.Streamable == nil {
+		return fmt.Errorf("specialist %s has no chat model or Invokable or Streamable", s.Name)
+	}
+
+	return s.AgentMeta.validate()
+}
+
 type Summarizer struct {
 	ChatModel    model.BaseChatModel
 	SystemPrompt string
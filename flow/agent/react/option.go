@@ -106,6 +106,21 @@ func WithTools(ctx context.Context, tools ...tool.BaseTool) ([]agent.AgentOption
 	return opts, nil
 }
 
+// WithToolFilter returns an agent option that filters the tools of the agent for a single call, e.g. by the entitlements of the user,
+// without creating a new agent. Both the ToolInfos passed to the chat model and the tools the ToolsNode can run are filtered,
+// including the ones set by WithTools.
+// e.g.
+//
+//	msg, err := agent.Generate(ctx, messages, react.WithToolFilter(func(ctx context.Context, info *schema.ToolInfo) bool {
+//		return entitlements.Allow(ctx, info.Name)
+//	}))
+func WithToolFilter(filter compose.ToolFilter) agent.AgentOption {
+	return agent.WithComposeOptions(
+		compose.WithChatModelOption(withModelToolFilter(filter)),
+		compose.WithToolsNodeOption(compose.WithToolFilter(filter)),
+	)
+}
+
 type Iterator[T any] struct {
 	ch *internal.UnboundedChan[item[T]]
 }
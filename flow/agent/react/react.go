@@ -19,16 +19,21 @@ package react
 import (
 	"context"
 	"io"
+	"runtime/debug"
 
 	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
 	"github.com/cloudwego/eino/flow/agent"
+	"github.com/cloudwego/eino/internal/safe"
 	"github.com/cloudwego/eino/schema"
 )
 
 type state struct {
 	Messages                 []*schema.Message
 	ReturnDirectlyToolCallID string
+	// Step is the number of model calls in the current run.
+	Step int
 }
 
 func init() {
@@ -53,8 +58,23 @@ type AgentConfig struct {
 	Model model.ChatModel
 
 	// ToolsConfig is the config for tools node.
+	// Multiple tool calls in a model response are executed in parallel by default,
+	// use ToolsConfig.MaxConcurrency to cap it, or ToolsConfig.ExecuteSequentially to disable it.
+	// When the agent is streaming, tools implementing tool.StreamingInputTool receive their arguments while the model is still generating them,
+	// unless ToolErrorPolicy or ToolErrorPolicies is set, see compose.ToolsNodeConfig.Tools.
+	// Use ToolsConfig.StreamOutputTools to have the tool callbacks receive the output of a tool chunk by chunk in Generate as well.
+	// Set ToolsConfig.ValidateToolArguments and ToolsConfig.RepairToolArguments to check the arguments generated by the model before calling the tools,
+	// by default the violations are returned to the model as the tool results, so that it can correct the calls in the next step.
 	ToolsConfig compose.ToolsNodeConfig
 
+	// ToolErrorPolicy decides how failed tool calls are handled: aborting the run, converting the error into the tool message
+	// so that the model can recover, or retrying with backoff.
+	// Optional. By default, a failed tool call aborts the run.
+	ToolErrorPolicy *ToolErrorPolicy
+	// ToolErrorPolicies are the policies of specific tools by name, which take precedence over ToolErrorPolicy.
+	// Optional.
+	ToolErrorPolicies map[string]*ToolErrorPolicy
+
 	// MessageModifier.
 	// modify the input messages before the model is called, it's useful when you want to add some system prompt or other messages.
 	MessageModifier MessageModifier
@@ -66,6 +86,13 @@ type AgentConfig struct {
 	// NOTE: if both MessageModifier and MessageRewriter are set, MessageRewriter will be called before MessageModifier.
 	MessageRewriter MessageModifier
 
+	// HistoryReducer reduces the accumulated messages before each ChatModel call, to keep them within the context window,
+	// e.g. agent.KeepLastToolTurns, agent.TokenBudget or agent.Summarize.
+	// It's called after MessageRewriter and before MessageModifier, and the reduced messages are kept in the state,
+	// so that e.g. a summary is generated once instead of before every call.
+	// Optional. By default, the entire accumulated messages are sent.
+	HistoryReducer agent.HistoryReducer
+
 	// MaxStep.
 	// default 12 of steps in pregel (node num + 10).
 	MaxStep int `json:"max_step"`
@@ -98,6 +125,17 @@ type AgentConfig struct {
 	// ToolsNodeName is the node name of the tools node in the ReAct Agent graph.
 	// Optional. Default `Tools`.
 	ToolsNodeName string
+
+	// ToolChoicePolicy forces or forbids tools in specific steps, e.g. forcing a "plan" tool in the first step,
+	// it's translated into the tools and tool choice options of the model call, see ToolChoicePolicy.
+	// Note that forbidden tools are only hidden from the model, they are still executed if the model calls them anyway.
+	// Optional.
+	ToolChoicePolicy ToolChoicePolicy
+
+	// Guardrails check the messages sent to and received from the model, see agent.Guardrail.
+	// When a guardrail is violated, Generate and Stream return the refusal message of the violation instead of an error.
+	// Optional.
+	Guardrails []agent.Guardrail
 }
 
 // Deprecated: This approach of adding persona involves unnecessary slice copying overhead.
@@ -226,8 +264,21 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 	if chatModel, err = agent.ChatModelWithTools(config.Model, config.ToolCallingModel, toolInfos); err != nil {
 		return nil, err
 	}
-
-	if toolsNode, err = compose.NewToolNode(ctx, &config.ToolsConfig); err != nil {
+	if config.ToolChoicePolicy != nil {
+		chatModel = &toolChoiceChatModel{inner: chatModel, tools: toolInfos, policy: config.ToolChoicePolicy}
+	}
+	// the tools are filtered before the tool choice is applied
+	chatModel = &toolFilterChatModel{inner: chatModel, tools: toolInfos}
+	chatModel = agent.NewGuardedChatModel(chatModel, config.Guardrails...)
+
+	toolsConfig := config.ToolsConfig
+	if config.ToolErrorPolicy != nil || len(config.ToolErrorPolicies) > 0 {
+		// the outermost middleware, so that retries go through the other middlewares as well
+		toolsConfig.ToolCallMiddlewares = append([]compose.ToolMiddleware{
+			newToolErrorMiddleware(config.ToolErrorPolicy, config.ToolErrorPolicies),
+		}, config.ToolsConfig.ToolCallMiddlewares...)
+	}
+	if toolsNode, err = compose.NewToolNode(ctx, &toolsConfig); err != nil {
 		return nil, err
 	}
 
@@ -237,11 +288,20 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 
 	modelPreHandle := func(ctx context.Context, input []*schema.Message, state *state) ([]*schema.Message, error) {
 		state.Messages = append(state.Messages, input...)
+		state.Step++
 
 		if config.MessageRewriter != nil {
 			state.Messages = config.MessageRewriter(ctx, state.Messages)
 		}
 
+		if config.HistoryReducer != nil {
+			reduced, err := config.HistoryReducer(ctx, state.Messages)
+			if err != nil {
+				return nil, err
+			}
+			state.Messages = reduced
+		}
+
 		if messageModifier == nil {
 			return state.Messages, nil
 		}
@@ -267,7 +327,12 @@ func NewAgent(ctx context.Context, config *AgentConfig) (_ *Agent, err error) {
 		state.ReturnDirectlyToolCallID = getReturnDirectlyToolCallID(input, config.ToolReturnDirectly)
 		return input, nil
 	}
-	if err = graph.AddToolsNode(nodeKeyTools, toolsNode, compose.WithStatePreHandler(toolsNodePreHandle), compose.WithNodeName(toolsNodeName)); err != nil {
+	toolsNodePreHandler := compose.WithStatePreHandler(toolsNodePreHandle)
+	if hasStreamingInputTool(config.ToolsConfig.Tools) {
+		// the message must not be concatenated before the tools node, so that the arguments can be streamed to the tools
+		toolsNodePreHandler = compose.WithStreamStatePreHandler(toolsNodeStreamPreHandler(config.ToolReturnDirectly))
+	}
+	if err = graph.AddToolsNode(nodeKeyTools, toolsNode, toolsNodePreHandler, compose.WithNodeName(toolsNodeName)); err != nil {
 		return nil, err
 	}
 
@@ -367,6 +432,73 @@ func genToolInfos(ctx context.Context, config compose.ToolsNodeConfig) ([]*schem
 	return toolInfos, nil
 }
 
+func hasStreamingInputTool(tools []tool.BaseTool) bool {
+	for _, t := range tools {
+		if _, ok := t.(tool.StreamingInputTool); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// toolsNodeStreamPreHandler forwards the model output to the tools node chunk by chunk,
+// and saves the complete message to the state before the end of the stream,
+// the tools node reads the stream to the end before it finishes, so the state is always updated before the next model call.
+func toolsNodeStreamPreHandler(toolReturnDirectly map[string]struct{}) compose.StreamStatePreHandler[*schema.Message, *state] {
+	return func(ctx context.Context, input *schema.StreamReader[*schema.Message], _ *state) (*schema.StreamReader[*schema.Message], error) {
+		sr, sw := schema.Pipe[*schema.Message](1)
+		go func() {
+			defer func() {
+				panicErr := recover()
+				if panicErr != nil {
+					sw.Send(nil, safe.NewPanicErr(panicErr, debug.Stack()))
+				}
+				sw.Close()
+			}()
+			defer input.Close()
+
+			var chunks []*schema.Message
+			for {
+				chunk, err := input.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					sw.Send(nil, err)
+					return
+				}
+				chunks = append(chunks, chunk)
+				if closed := sw.Send(chunk, nil); closed {
+					return
+				}
+			}
+
+			var resumed *schema.Message
+			err := compose.ProcessState[*state](ctx, func(_ context.Context, st *state) error {
+				if len(chunks) == 0 {
+					resumed = st.Messages[len(st.Messages)-1] // used for rerun interrupt resume
+					return nil
+				}
+				msg, err := schema.ConcatMessages(chunks)
+				if err != nil {
+					return err
+				}
+				st.Messages = append(st.Messages, msg)
+				st.ReturnDirectlyToolCallID = getReturnDirectlyToolCallID(msg, toolReturnDirectly)
+				return nil
+			})
+			if err != nil {
+				sw.Send(nil, err)
+				return
+			}
+			if resumed != nil {
+				sw.Send(resumed, nil)
+			}
+		}()
+		return sr, nil
+	}
+}
+
 func getReturnDirectlyToolCallID(input *schema.Message, toolReturnDirectly map[string]struct{}) string {
 	if len(toolReturnDirectly) == 0 {
 		return ""
@@ -383,12 +515,20 @@ func getReturnDirectlyToolCallID(input *schema.Message, toolReturnDirectly map[s
 
 // Generate generates a response from the agent.
 func (r *Agent) Generate(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (*schema.Message, error) {
-	return r.runnable.Invoke(ctx, input, agent.GetComposeOptions(opts...)...)
+	output, err := r.runnable.Invoke(ctx, input, agent.GetComposeOptions(opts...)...)
+	if v, ok := agent.AsGuardrailViolation(err); ok {
+		return v.RefusalMessage(), nil
+	}
+	return output, err
 }
 
 // Stream calls the agent and returns a stream response.
 func (r *Agent) Stream(ctx context.Context, input []*schema.Message, opts ...agent.AgentOption) (output *schema.StreamReader[*schema.Message], err error) {
-	return r.runnable.Stream(ctx, input, agent.GetComposeOptions(opts...)...)
+	output, err = r.runnable.Stream(ctx, input, agent.GetComposeOptions(opts...)...)
+	if v, ok := agent.AsGuardrailViolation(err); ok {
+		return schema.StreamReaderFromArray([]*schema.Message{v.RefusalMessage()}), nil
+	}
+	return output, err
 }
 
 // ExportGraph exports the underlying graph from Agent, along with the []compose.GraphAddNodeOpt to be used when adding this graph to another graph.
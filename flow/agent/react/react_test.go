@@ -23,6 +23,7 @@ import (
 	"io"
 	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/bytedance/sonic"
 	"github.com/stretchr/testify/assert"
@@ -460,6 +461,134 @@ func TestReactWithModifier(t *testing.T) {
 	}
 }
 
+func TestReactWithHistoryReducer(t *testing.T) {
+	ctx := context.Background()
+
+	fakeTool := &fakeToolGreetForTest{}
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockChatModel(ctrl)
+
+	var inputLens []int
+	times := 0
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			times++
+			inputLens = append(inputLens, len(input))
+			if times <= 3 {
+				info, _ := fakeTool.Info(ctx)
+				return schema.AssistantMessage("hello max",
+					[]schema.ToolCall{
+						{
+							ID: randStr(),
+							Function: schema.FunctionCall{
+								Name:      info.Name,
+								Arguments: fmt.Sprintf(`{"name": "%s", "hh": "123"}`, randStr()),
+							},
+						},
+					}), nil
+			}
+			return schema.AssistantMessage("bye", nil), nil
+		}).Times(4)
+	cm.EXPECT().BindTools(gomock.Any()).Return(nil).AnyTimes()
+
+	a, err := NewAgent(ctx, &AgentConfig{
+		Model: cm,
+		ToolsConfig: compose.ToolsNodeConfig{
+			Tools: []tool.BaseTool{fakeTool},
+		},
+		HistoryReducer: agent.KeepLastToolTurns(1),
+		MaxStep:        40,
+	})
+	assert.Nil(t, err)
+
+	out, err := a.Generate(ctx, []*schema.Message{schema.UserMessage("hello")})
+	assert.Nil(t, err)
+	assert.Equal(t, "bye", out.Content)
+	// user message + the last assistant/tool turn at most
+	assert.Equal(t, []int{1, 3, 3, 3}, inputLens)
+}
+
+type fakeStreamingInputToolForTest struct {
+	firstFragment chan string
+}
+
+func (t *fakeStreamingInputToolForTest) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "run_sql"}, nil
+}
+
+func (t *fakeStreamingInputToolForTest) StreamingInputRun(_ context.Context, arguments *schema.StreamReader[string], _ ...tool.Option) (string, error) {
+	defer arguments.Close()
+	var args string
+	for {
+		fragment, err := arguments.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if len(args) == 0 {
+			t.firstFragment <- fragment
+		}
+		args += fragment
+	}
+	return "executed " + args, nil
+}
+
+func TestReactStreamingInputTool(t *testing.T) {
+	ctx := context.Background()
+
+	sit := &fakeStreamingInputToolForTest{firstFragment: make(chan string, 1)}
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+
+	times := 0
+	cm.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+			times++
+			if times > 1 {
+				assert.Len(t, input, 3)
+				assert.Equal(t, `executed {"sql": "select 1"}`, input[2].Content)
+				return schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("done", nil)}), nil
+			}
+
+			sr, sw := schema.Pipe[*schema.Message](0)
+			go func() {
+				defer sw.Close()
+				index := 0
+				sw.Send(schema.AssistantMessage("", []schema.ToolCall{
+					{Index: &index, ID: "call_1", Function: schema.FunctionCall{Name: "run_sql", Arguments: `{"sql": `}},
+				}), nil)
+				select {
+				case <-sit.firstFragment:
+				case <-time.After(5 * time.Second):
+					sw.Send(nil, errors.New("the tool isn't started before the arguments are complete"))
+					return
+				}
+				sw.Send(schema.AssistantMessage("", []schema.ToolCall{
+					{Index: &index, Function: schema.FunctionCall{Arguments: `"select 1"}`}},
+				}), nil)
+			}()
+			return sr, nil
+		}).Times(2)
+
+	a, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig: compose.ToolsNodeConfig{
+			Tools: []tool.BaseTool{sit},
+		},
+		MaxStep: 10,
+	})
+	assert.NoError(t, err)
+
+	out, err := a.Stream(ctx, []*schema.Message{schema.UserMessage("run select 1")})
+	assert.NoError(t, err)
+	msg, err := schema.ConcatMessageStream(out)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", msg.Content)
+}
+
 func TestAgentInGraph(t *testing.T) {
 	t.Run("agent generate in chain", func(t *testing.T) {
 		ctx := context.Background()
@@ -820,3 +949,39 @@ func randStr() string {
 }
 
 var callbackForTest = BuildAgentCallback(&template.ModelCallbackHandler{}, &template.ToolCallbackHandler{})
+
+func TestReactWithGuardrails(t *testing.T) {
+	ctx := context.Background()
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.AssistantMessage("the password is 123456", nil), nil).Times(1)
+	cm.EXPECT().Stream(gomock.Any(), gomock.Any(), gomock.Any()).
+		Return(schema.StreamReaderFromArray([]*schema.Message{schema.AssistantMessage("the password is 123456", nil)}), nil).Times(1)
+
+	a, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig: compose.ToolsNodeConfig{
+			Tools: []tool.BaseTool{&fakeToolGreetForTest{tarCount: 1}},
+		},
+		Guardrails: []agent.Guardrail{{
+			Name: "no_password",
+			CheckOutput: func(ctx context.Context, output *schema.Message) (*schema.Message, *agent.GuardrailViolation, error) {
+				return nil, &agent.GuardrailViolation{Reason: "password leaked"}, nil
+			},
+		}},
+	})
+	assert.NoError(t, err)
+
+	out, err := a.Generate(ctx, []*schema.Message{schema.UserMessage("what's the password?")})
+	assert.NoError(t, err)
+	assert.Equal(t, agent.DefaultGuardrailRefusal, out.Content)
+
+	sr, err := a.Stream(ctx, []*schema.Message{schema.UserMessage("what's the password?")})
+	assert.NoError(t, err)
+	out, err = schema.ConcatMessageStream(sr)
+	assert.NoError(t, err)
+	assert.Equal(t, agent.DefaultGuardrailRefusal, out.Content)
+}
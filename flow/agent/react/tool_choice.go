@@ -0,0 +1,181 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// StepInfo is the information of the step passed to ToolChoicePolicy.
+type StepInfo struct {
+	// Step is the number of the model call in the current run, starting from 1.
+	Step int
+	// Messages are the messages accumulated in the state, before MessageModifier is applied.
+	Messages []*schema.Message
+}
+
+// StepToolChoice is the tool choice of a step, which is translated into the model.WithTools and model.WithToolChoice options of the model call.
+type StepToolChoice struct {
+	// ToolChoice is the tool choice of the model call, e.g. schema.ToolChoiceForced to make the model call one of the tools.
+	// Optional. By default, the tool choice of the model is unchanged.
+	ToolChoice schema.ToolChoice
+	// AllowedTools limits the tools sent to the model to the named ones.
+	// Optional. By default, all the tools are sent.
+	AllowedTools []string
+	// ForbiddenTools are the names of the tools hidden from the model.
+	// Optional.
+	ForbiddenTools []string
+}
+
+// ForceTool makes the model call the named tool in the step.
+func ForceTool(name string) *StepToolChoice {
+	return &StepToolChoice{
+		ToolChoice:   schema.ToolChoiceForced,
+		AllowedTools: []string{name},
+	}
+}
+
+// ForbidTools hides the named tools from the model in the step.
+func ForbidTools(names ...string) *StepToolChoice {
+	return &StepToolChoice{
+		ForbiddenTools: names,
+	}
+}
+
+// ToolChoicePolicy decides the tools the model can call in each step, it's called before each model call.
+// Returning nil leaves the step unchanged.
+// e.g. force a "plan" tool in the first step, and forbid "finish" before any retrieval:
+//
+//	func(ctx context.Context, step *react.StepInfo) (*react.StepToolChoice, error) {
+//		if step.Step == 1 {
+//			return react.ForceTool("plan"), nil
+//		}
+//		if !hasCalled(step.Messages, "retrieve") {
+//			return react.ForbidTools("finish"), nil
+//		}
+//		return nil, nil
+//	}
+type ToolChoicePolicy func(ctx context.Context, step *StepInfo) (*StepToolChoice, error)
+
+// toolChoiceChatModel applies the ToolChoicePolicy to each call of the inner model.
+type toolChoiceChatModel struct {
+	inner  model.BaseChatModel
+	tools  []*schema.ToolInfo
+	policy ToolChoicePolicy
+}
+
+func (m *toolChoiceChatModel) GetType() string {
+	if typ, ok := components.GetType(m.inner); ok {
+		return typ
+	}
+	return "ToolChoiceChatModel"
+}
+
+func (m *toolChoiceChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(m.inner)
+}
+
+func (m *toolChoiceChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	opts, err := m.stepOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return m.inner.Generate(ctx, input, opts...)
+}
+
+func (m *toolChoiceChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	opts, err := m.stepOptions(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return m.inner.Stream(ctx, input, opts...)
+}
+
+func (m *toolChoiceChatModel) stepOptions(ctx context.Context, opts []model.Option) ([]model.Option, error) {
+	var step *StepInfo
+	err := compose.ProcessState[*state](ctx, func(_ context.Context, st *state) error {
+		messages := make([]*schema.Message, len(st.Messages))
+		copy(messages, st.Messages)
+		step = &StepInfo{Step: st.Step, Messages: messages}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	choice, err := m.policy(ctx, step)
+	if err != nil {
+		return nil, err
+	}
+	if choice == nil {
+		return opts, nil
+	}
+
+	// the tools of the call options, e.g. set by WithTools, take precedence over the configured ones
+	tools := model.GetCommonOptions(&model.Options{Tools: m.tools}, opts...).Tools
+	tools, err = filterTools(tools, choice)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply tool choice of step %d: %w", step.Step, err)
+	}
+	if choice.ToolChoice == schema.ToolChoiceForced && len(tools) == 0 {
+		return nil, fmt.Errorf("failed to apply tool choice of step %d: no tool left to force", step.Step)
+	}
+
+	nOpts := make([]model.Option, 0, len(opts)+2)
+	nOpts = append(nOpts, opts...)
+	nOpts = append(nOpts, model.WithTools(tools))
+	if choice.ToolChoice != "" {
+		nOpts = append(nOpts, model.WithToolChoice(choice.ToolChoice))
+	}
+	return nOpts, nil
+}
+
+func filterTools(tools []*schema.ToolInfo, choice *StepToolChoice) ([]*schema.ToolInfo, error) {
+	exists := make(map[string]bool, len(tools))
+	for _, t := range tools {
+		exists[t.Name] = true
+	}
+	allowed := make(map[string]bool, len(choice.AllowedTools))
+	for _, name := range choice.AllowedTools {
+		if !exists[name] {
+			return nil, fmt.Errorf("allowed tool[%s] not found", name)
+		}
+		allowed[name] = true
+	}
+	forbidden := make(map[string]bool, len(choice.ForbiddenTools))
+	for _, name := range choice.ForbiddenTools {
+		forbidden[name] = true
+	}
+
+	ret := make([]*schema.ToolInfo, 0, len(tools))
+	for _, t := range tools {
+		if len(allowed) > 0 && !allowed[t.Name] {
+			continue
+		}
+		if forbidden[t.Name] {
+			continue
+		}
+		ret = append(ret, t)
+	}
+	return ret, nil
+}
@@ -0,0 +1,114 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestToolChoicePolicy(t *testing.T) {
+	ctx := context.Background()
+
+	var tools []tool.BaseTool
+	for _, name := range []string{"plan", "retrieve", "finish"} {
+		tools = append(tools, utils.NewTool(&schema.ToolInfo{Name: name}, func(ctx context.Context, _ map[string]any) (string, error) {
+			return "ok", nil
+		}))
+	}
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+
+	var callOptions []*model.Options
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			callOptions = append(callOptions, model.GetCommonOptions(nil, opts...))
+			switch len(callOptions) {
+			case 1:
+				return schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "plan", Arguments: "{}"}}}), nil
+			case 2:
+				return schema.AssistantMessage("", []schema.ToolCall{{ID: "2", Function: schema.FunctionCall{Name: "retrieve", Arguments: "{}"}}}), nil
+			default:
+				return schema.AssistantMessage("done", nil), nil
+			}
+		}).Times(3)
+
+	var steps []int
+	a, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig:      compose.ToolsNodeConfig{Tools: tools},
+		ToolChoicePolicy: func(ctx context.Context, step *StepInfo) (*StepToolChoice, error) {
+			steps = append(steps, step.Step)
+			if step.Step == 1 {
+				return ForceTool("plan"), nil
+			}
+			for _, msg := range step.Messages {
+				for _, tc := range msg.ToolCalls {
+					if tc.Function.Name == "retrieve" {
+						return nil, nil
+					}
+				}
+			}
+			return ForbidTools("finish"), nil
+		},
+	})
+	assert.NoError(t, err)
+
+	out, err := a.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out.Content)
+	assert.Equal(t, []int{1, 2, 3}, steps)
+
+	toolNames := func(o *model.Options) []string {
+		var names []string
+		for _, ti := range o.Tools {
+			names = append(names, ti.Name)
+		}
+		return names
+	}
+	assert.Equal(t, schema.ToolChoiceForced, *callOptions[0].ToolChoice)
+	assert.Equal(t, []string{"plan"}, toolNames(callOptions[0]))
+	assert.Nil(t, callOptions[1].ToolChoice)
+	assert.Equal(t, []string{"plan", "retrieve"}, toolNames(callOptions[1]))
+	assert.Nil(t, callOptions[2].ToolChoice)
+	assert.Nil(t, callOptions[2].Tools)
+
+	t.Run("unknown forced tool", func(t *testing.T) {
+		a, err := NewAgent(ctx, &AgentConfig{
+			ToolCallingModel: cm,
+			ToolsConfig:      compose.ToolsNodeConfig{Tools: tools},
+			ToolChoicePolicy: func(ctx context.Context, step *StepInfo) (*StepToolChoice, error) {
+				return ForceTool("unknown"), nil
+			},
+		})
+		assert.NoError(t, err)
+		_, err = a.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.ErrorContains(t, err, "allowed tool[unknown] not found")
+	})
+}
@@ -0,0 +1,192 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ToolErrorStrategy is how the agent handles a failed tool call.
+type ToolErrorStrategy string
+
+const (
+	// ToolErrorAbort aborts the run with the error of the tool. It's the default strategy.
+	ToolErrorAbort ToolErrorStrategy = "abort"
+	// ToolErrorToMessage converts the error into the tool message, so that the model can see it and recover,
+	// e.g. by fixing the arguments or calling another tool.
+	ToolErrorToMessage ToolErrorStrategy = "to_message"
+	// ToolErrorRetry retries the tool call after a backoff, and aborts the run when the retries are exhausted.
+	ToolErrorRetry ToolErrorStrategy = "retry"
+)
+
+// ToolErrorInfo is the information of a failed tool call.
+type ToolErrorInfo struct {
+	ToolName  string
+	CallID    string
+	Arguments string
+	Err       error
+	// Retries is the number of retries taken before this failure.
+	Retries int
+}
+
+// ToolErrorPolicy decides how the agent handles failed tool calls.
+// Interrupts of tools and errors caused by the cancellation of the context are never handled, they always abort the run.
+// For streaming tools, only the errors returned when starting the stream are handled,
+// the errors within the stream are delivered to the model node as they are.
+type ToolErrorPolicy struct {
+	// Strategy is the strategy for all the errors if Classifier is not set.
+	// Optional. Defaults to ToolErrorAbort.
+	Strategy ToolErrorStrategy
+
+	// Classifier decides the strategy for each error, e.g. retrying on timeouts, and converting invalid arguments to messages.
+	// Optional. If not provided, Strategy is used.
+	Classifier func(ctx context.Context, info *ToolErrorInfo) ToolErrorStrategy
+
+	// MaxRetries is the maximum number of retries of a tool call with ToolErrorRetry.
+	// When they are exhausted, the run is aborted, unless Classifier decides otherwise based on ToolErrorInfo.Retries.
+	// Optional. Defaults to 2.
+	MaxRetries int
+
+	// Backoff returns the waiting time before the retry-th retry, starting from 1.
+	// Optional. Defaults to 100ms doubled for each retry.
+	Backoff func(ctx context.Context, retry int) time.Duration
+
+	// ErrorMessage formats the error into the content of the tool message for ToolErrorToMessage.
+	// Optional. Defaults to "failed to call tool[<name>]: <error>".
+	ErrorMessage func(ctx context.Context, info *ToolErrorInfo) string
+}
+
+func (p *ToolErrorPolicy) strategy(ctx context.Context, info *ToolErrorInfo) ToolErrorStrategy {
+	var s ToolErrorStrategy
+	if p.Classifier != nil {
+		s = p.Classifier(ctx, info)
+	} else {
+		s = p.Strategy
+	}
+
+	if s == ToolErrorRetry {
+		maxRetries := p.MaxRetries
+		if maxRetries <= 0 {
+			maxRetries = 2
+		}
+		if info.Retries >= maxRetries {
+			return ToolErrorAbort
+		}
+	}
+	if s == "" {
+		return ToolErrorAbort
+	}
+	return s
+}
+
+func (p *ToolErrorPolicy) backoff(ctx context.Context, retry int) time.Duration {
+	if p.Backoff != nil {
+		return p.Backoff(ctx, retry)
+	}
+	return 100 * time.Millisecond << (retry - 1)
+}
+
+func (p *ToolErrorPolicy) errorMessage(ctx context.Context, info *ToolErrorInfo) string {
+	if p.ErrorMessage != nil {
+		return p.ErrorMessage(ctx, info)
+	}
+	return fmt.Sprintf("failed to call tool[%s]: %v", info.ToolName, info.Err)
+}
+
+// newToolErrorMiddleware builds the middleware handling the tool errors by the policy of each tool, falling back to the default policy.
+func newToolErrorMiddleware(defaultPolicy *ToolErrorPolicy, policies map[string]*ToolErrorPolicy) compose.ToolMiddleware {
+	policyOf := func(name string) *ToolErrorPolicy {
+		if p, ok := policies[name]; ok {
+			return p
+		}
+		return defaultPolicy
+	}
+
+	// handle calls the tool until it succeeds, or an error is converted to a message (returned as the string) or aborts the run.
+	handle := func(ctx context.Context, input *compose.ToolInput, call func() error) (string, bool, error) {
+		p := policyOf(input.Name)
+		for retries := 0; ; retries++ {
+			err := call()
+			if err == nil || p == nil {
+				return "", false, err
+			}
+			if _, ok := compose.IsInterruptRerunError(err); ok || ctx.Err() != nil {
+				return "", false, err
+			}
+
+			info := &ToolErrorInfo{
+				ToolName:  input.Name,
+				CallID:    input.CallID,
+				Arguments: input.Arguments,
+				Err:       err,
+				Retries:   retries,
+			}
+			switch p.strategy(ctx, info) {
+			case ToolErrorToMessage:
+				return p.errorMessage(ctx, info), true, nil
+			case ToolErrorRetry:
+				select {
+				case <-ctx.Done():
+					return "", false, err
+				case <-time.After(p.backoff(ctx, retries+1)):
+				}
+			default:
+				return "", false, err
+			}
+		}
+	}
+
+	return compose.ToolMiddleware{
+		Invokable: func(next compose.InvokableToolEndpoint) compose.InvokableToolEndpoint {
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.ToolOutput, error) {
+				var output *compose.ToolOutput
+				msg, converted, err := handle(ctx, input, func() (err error) {
+					output, err = next(ctx, input)
+					return err
+				})
+				if err != nil {
+					return nil, err
+				}
+				if converted {
+					return &compose.ToolOutput{Result: msg}, nil
+				}
+				return output, nil
+			}
+		},
+		Streamable: func(next compose.StreamableToolEndpoint) compose.StreamableToolEndpoint {
+			return func(ctx context.Context, input *compose.ToolInput) (*compose.StreamToolOutput, error) {
+				var output *compose.StreamToolOutput
+				msg, converted, err := handle(ctx, input, func() (err error) {
+					output, err = next(ctx, input)
+					return err
+				})
+				if err != nil {
+					return nil, err
+				}
+				if converted {
+					return &compose.StreamToolOutput{Result: schema.StreamReaderFromArray([]string{msg})}, nil
+				}
+				return output, nil
+			}
+		},
+	}
+}
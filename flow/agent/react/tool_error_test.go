@@ -0,0 +1,126 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestToolErrorPolicy(t *testing.T) {
+	ctx := context.Background()
+	errTimeout := errors.New("timeout")
+
+	flakyCalls := 0
+	flaky := utils.NewTool(&schema.ToolInfo{Name: "flaky"}, func(ctx context.Context, _ map[string]any) (string, error) {
+		flakyCalls++
+		if flakyCalls <= 2 {
+			return "", errTimeout
+		}
+		return "flaky ok", nil
+	})
+	broken := utils.NewTool(&schema.ToolInfo{Name: "broken"}, func(ctx context.Context, _ map[string]any) (string, error) {
+		return "", errors.New("invalid argument")
+	})
+
+	newAgent := func(t *testing.T, maxRetries int, validate func(input []*schema.Message)) *Agent {
+		ctrl := gomock.NewController(t)
+		cm := mockModel.NewMockToolCallingChatModel(ctrl)
+		cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+		times := 0
+		cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+				times++
+				if times == 1 {
+					return schema.AssistantMessage("", []schema.ToolCall{
+						{ID: "1", Function: schema.FunctionCall{Name: "flaky", Arguments: "{}"}},
+						{ID: "2", Function: schema.FunctionCall{Name: "broken", Arguments: "{}"}},
+					}), nil
+				}
+				validate(input)
+				return schema.AssistantMessage("done", nil), nil
+			}).AnyTimes()
+
+		var retried []int
+		a, err := NewAgent(ctx, &AgentConfig{
+			ToolCallingModel: cm,
+			ToolsConfig: compose.ToolsNodeConfig{
+				Tools:               []tool.BaseTool{flaky, broken},
+				ExecuteSequentially: true,
+			},
+			ToolErrorPolicy: &ToolErrorPolicy{
+				Classifier: func(ctx context.Context, info *ToolErrorInfo) ToolErrorStrategy {
+					if errors.Is(info.Err, errTimeout) {
+						return ToolErrorRetry
+					}
+					return ToolErrorAbort
+				},
+				MaxRetries: maxRetries,
+				Backoff: func(ctx context.Context, retry int) time.Duration {
+					retried = append(retried, retry)
+					return time.Millisecond
+				},
+			},
+			ToolErrorPolicies: map[string]*ToolErrorPolicy{
+				"broken": {Strategy: ToolErrorToMessage},
+			},
+		})
+		assert.NoError(t, err)
+		t.Cleanup(func() {
+			if maxRetries >= 2 {
+				assert.Equal(t, []int{1, 2}, retried)
+			}
+		})
+		return a
+	}
+
+	t.Run("recover", func(t *testing.T) {
+		flakyCalls = 0
+		a := newAgent(t, 2, func(input []*schema.Message) {
+			assert.Len(t, input, 4)
+			assert.Equal(t, "flaky ok", input[2].Content)
+			assert.Contains(t, input[3].Content, "failed to call tool[broken]: ")
+			assert.Contains(t, input[3].Content, "invalid argument")
+		})
+		msg, err := a.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.NoError(t, err)
+		assert.Equal(t, "done", msg.Content)
+		assert.Equal(t, 3, flakyCalls)
+	})
+
+	t.Run("retries exhausted", func(t *testing.T) {
+		flakyCalls = 0
+		a := newAgent(t, 1, func(input []*schema.Message) {
+			assert.Fail(t, "the model should not be called again")
+		})
+		_, err := a.Generate(ctx, []*schema.Message{schema.UserMessage("hi")})
+		assert.ErrorIs(t, err, errTimeout)
+		assert.Equal(t, 2, flakyCalls)
+	})
+}
@@ -0,0 +1,81 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type toolFilterOptions struct {
+	filter compose.ToolFilter
+}
+
+func withModelToolFilter(filter compose.ToolFilter) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *toolFilterOptions) {
+		o.filter = filter
+	})
+}
+
+// toolFilterChatModel filters the ToolInfos passed to the inner model by the filter of WithToolFilter.
+type toolFilterChatModel struct {
+	inner model.BaseChatModel
+	tools []*schema.ToolInfo
+}
+
+func (m *toolFilterChatModel) GetType() string {
+	if typ, ok := components.GetType(m.inner); ok {
+		return typ
+	}
+	return "ToolFilterChatModel"
+}
+
+func (m *toolFilterChatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(m.inner)
+}
+
+func (m *toolFilterChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return m.inner.Generate(ctx, input, m.filterOptions(ctx, opts)...)
+}
+
+func (m *toolFilterChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	return m.inner.Stream(ctx, input, m.filterOptions(ctx, opts)...)
+}
+
+func (m *toolFilterChatModel) filterOptions(ctx context.Context, opts []model.Option) []model.Option {
+	filter := model.GetImplSpecificOptions(&toolFilterOptions{}, opts...).filter
+	if filter == nil {
+		return opts
+	}
+
+	// the tools of the call options, e.g. set by WithTools, are filtered as well
+	tools := model.GetCommonOptions(&model.Options{Tools: m.tools}, opts...).Tools
+	filtered := make([]*schema.ToolInfo, 0, len(tools))
+	for _, t := range tools {
+		if filter(ctx, t) {
+			filtered = append(filtered, t)
+		}
+	}
+
+	nOpts := make([]model.Option, 0, len(opts)+1)
+	nOpts = append(nOpts, opts...)
+	return append(nOpts, model.WithTools(filtered))
+}
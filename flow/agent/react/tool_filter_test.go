@@ -0,0 +1,92 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package react
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/components/tool/utils"
+	"github.com/cloudwego/eino/compose"
+	mockModel "github.com/cloudwego/eino/internal/mock/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestWithToolFilter(t *testing.T) {
+	ctx := context.Background()
+
+	var tools []tool.BaseTool
+	for _, name := range []string{"search", "refund"} {
+		name := name
+		tools = append(tools, utils.NewTool(&schema.ToolInfo{Name: name}, func(ctx context.Context, _ map[string]any) (string, error) {
+			return name + " done", nil
+		}))
+	}
+
+	ctrl := gomock.NewController(t)
+	cm := mockModel.NewMockToolCallingChatModel(ctrl)
+	cm.EXPECT().WithTools(gomock.Any()).Return(cm, nil).AnyTimes()
+
+	var toolNames [][]string
+	var toolResults []string
+	cm.EXPECT().Generate(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+			var names []string
+			for _, ti := range model.GetCommonOptions(nil, opts...).Tools {
+				names = append(names, ti.Name)
+			}
+			toolNames = append(toolNames, names)
+			if last := input[len(input)-1]; last.Role == schema.Tool {
+				toolResults = append(toolResults, last.Content)
+				return schema.AssistantMessage("done", nil), nil
+			}
+			// the model calls the refund tool anyway
+			return schema.AssistantMessage("", []schema.ToolCall{{ID: "1", Function: schema.FunctionCall{Name: "refund", Arguments: "{}"}}}), nil
+		}).AnyTimes()
+
+	a, err := NewAgent(ctx, &AgentConfig{
+		ToolCallingModel: cm,
+		ToolsConfig: compose.ToolsNodeConfig{
+			Tools: tools,
+			UnknownToolsHandler: func(ctx context.Context, name, input string) (string, error) {
+				return "tool " + name + " is not available", nil
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	onlySearch := WithToolFilter(func(ctx context.Context, info *schema.ToolInfo) bool {
+		return info.Name == "search"
+	})
+	out, err := a.Generate(ctx, []*schema.Message{schema.UserMessage("refund my order")}, onlySearch)
+	assert.NoError(t, err)
+	assert.Equal(t, "done", out.Content)
+	assert.Equal(t, [][]string{{"search"}, {"search"}}, toolNames)
+	assert.Equal(t, []string{"tool refund is not available"}, toolResults)
+
+	// the filter only applies to the call
+	toolNames, toolResults = nil, nil
+	_, err = a.Generate(ctx, []*schema.Message{schema.UserMessage("refund my order")})
+	assert.NoError(t, err)
+	assert.Nil(t, toolNames[0])
+	assert.Equal(t, []string{"refund done"}, toolResults)
+}
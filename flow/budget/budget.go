@@ -0,0 +1,177 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package budget tracks the token and cost consumption per key, e.g. a user, a tenant or an API key, across runs,
+// and rejects the work once the quota of the key is exhausted.
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/cloudwego/eino/components/model"
+)
+
+// ErrExhausted is matched by the errors of the exhausted quotas, see ExhaustedError.
+var ErrExhausted = model.ErrBudgetExhausted
+
+// Usage is the consumption charged to a key.
+type Usage = model.BudgetUsage
+
+// Quota is the limit of the consumption of a key, zero value of each field means no limit.
+type Quota = model.BudgetQuota
+
+// ExhaustedError is returned when the quota of the key has been exhausted. errors.Is(err, ErrExhausted) reports true for it.
+type ExhaustedError = model.BudgetExhaustedError
+
+// Manager tracks the consumption per key across runs, and enforces the quotas of the keys.
+// It's shared by all the runs in the process, or across processes with a distributed Store.
+type Manager = model.BudgetManager
+
+// exceededLimits returns which limits of the quota the usage has reached, the values could be "tokens" and "cost".
+func exceededLimits(q Quota, u Usage) []string {
+	var exceeded []string
+	if q.MaxTokens > 0 && u.Tokens >= q.MaxTokens {
+		exceeded = append(exceeded, "tokens")
+	}
+	if q.MaxCost > 0 && u.Cost >= q.MaxCost {
+		exceeded = append(exceeded, "cost")
+	}
+	return exceeded
+}
+
+// Store keeps the usage of the keys for the Manager created by NewManager.
+// Implement it over a shared storage, e.g. the atomic increments of Redis, to enforce the quotas across processes.
+type Store interface {
+	// Add adds the usage to the key atomically, and returns the total usage of the key.
+	Add(ctx context.Context, key string, usage Usage) (Usage, error)
+	// Get returns the total usage of the key, zero if absent.
+	Get(ctx context.Context, key string) (Usage, error)
+	// Reset clears the usage of the key.
+	Reset(ctx context.Context, key string) error
+}
+
+// Config is the config of NewManager.
+type Config struct {
+	// Quota returns the quota of the key, the key without a quota is tracked but never exhausted.
+	// Required.
+	Quota func(ctx context.Context, key string) (quota Quota, ok bool)
+	// Store keeps the usage of the keys. Optional, defaults to NewInMemoryStore.
+	Store Store
+}
+
+// NewManager creates a Manager enforcing the quotas of Config.Quota over the usage kept in Config.Store.
+// e.g.
+//
+//	mgr, err := budget.NewManager(&budget.Config{
+//		Quota: func(ctx context.Context, key string) (budget.Quota, bool) {
+//			return budget.Quota{MaxTokens: 1_000_000}, true
+//		},
+//	})
+func NewManager(config *Config) (Manager, error) {
+	if config == nil || config.Quota == nil {
+		return nil, errors.New("quota is required")
+	}
+	store := config.Store
+	if store == nil {
+		store = NewInMemoryStore()
+	}
+	return &manager{quota: config.Quota, store: store}, nil
+}
+
+type manager struct {
+	quota func(ctx context.Context, key string) (Quota, bool)
+	store Store
+}
+
+func (m *manager) Check(ctx context.Context, key string) error {
+	quota, ok := m.quota(ctx, key)
+	if !ok {
+		return nil
+	}
+	usage, err := m.store.Get(ctx, key)
+	if err != nil {
+		return fmt.Errorf("get usage of key[%s] fail: %w", key, err)
+	}
+	if exceeded := exceededLimits(quota, usage); len(exceeded) > 0 {
+		return &ExhaustedError{Key: key, Usage: usage, Quota: quota, Exceeded: exceeded}
+	}
+	return nil
+}
+
+func (m *manager) Charge(ctx context.Context, key string, usage Usage) (Usage, error) {
+	total, err := m.store.Add(ctx, key, usage)
+	if err != nil {
+		return Usage{}, fmt.Errorf("charge key[%s] fail: %w", key, err)
+	}
+	return total, nil
+}
+
+func (m *manager) Usage(ctx context.Context, key string) (Usage, error) {
+	return m.store.Get(ctx, key)
+}
+
+func (m *manager) Reset(ctx context.Context, key string) error {
+	return m.store.Reset(ctx, key)
+}
+
+// NewInMemoryStore creates a Store keeping the usage in memory, the quotas are enforced in the process only.
+func NewInMemoryStore() Store {
+	return &inMemoryStore{usages: make(map[string]Usage)}
+}
+
+type inMemoryStore struct {
+	mu     sync.Mutex
+	usages map[string]Usage
+}
+
+func (s *inMemoryStore) Add(_ context.Context, key string, usage Usage) (Usage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	u := s.usages[key]
+	u.Tokens += usage.Tokens
+	u.Cost += usage.Cost
+	s.usages[key] = u
+	return u, nil
+}
+
+func (s *inMemoryStore) Get(_ context.Context, key string) (Usage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.usages[key], nil
+}
+
+func (s *inMemoryStore) Reset(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.usages, key)
+	return nil
+}
+
+type keyCtxKey struct{}
+
+// WithKey returns a context in which the consumption is charged to the key, and the work is rejected once its quota is exhausted.
+func WithKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, keyCtxKey{}, key)
+}
+
+// KeyFromContext returns the key set by WithKey.
+func KeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(keyCtxKey{}).(string)
+	return key, ok
+}
@@ -0,0 +1,165 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package budget
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/model/modeltest"
+	"github.com/cloudwego/eino/schema"
+)
+
+func withUsage(msg *schema.Message, tokens int) *schema.Message {
+	msg.ResponseMeta = &schema.ResponseMeta{Usage: &schema.TokenUsage{PromptTokens: tokens / 2, CompletionTokens: tokens - tokens/2, TotalTokens: tokens}}
+	return msg
+}
+
+func newTestManager(t *testing.T) Manager {
+	mgr, err := NewManager(&Config{
+		Quota: func(_ context.Context, key string) (Quota, bool) {
+			if key == "free" {
+				return Quota{MaxTokens: 100, MaxCost: 1}, true
+			}
+			return Quota{}, false
+		},
+	})
+	assert.NoError(t, err)
+	return mgr
+}
+
+func TestManager(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewManager(nil)
+	assert.Error(t, err)
+
+	mgr := newTestManager(t)
+	assert.NoError(t, mgr.Check(ctx, "free"))
+
+	total, err := mgr.Charge(ctx, "free", Usage{Tokens: 60, Cost: 0.5})
+	assert.NoError(t, err)
+	assert.Equal(t, Usage{Tokens: 60, Cost: 0.5}, total)
+	assert.NoError(t, mgr.Check(ctx, "free"))
+
+	_, err = mgr.Charge(ctx, "free", Usage{Tokens: 40, Cost: 0.1})
+	assert.NoError(t, err)
+	err = mgr.Check(ctx, "free")
+	assert.ErrorIs(t, err, ErrExhausted)
+	var ee *ExhaustedError
+	assert.True(t, errors.As(err, &ee))
+	assert.Equal(t, "free", ee.Key)
+	assert.Equal(t, []string{"tokens"}, ee.Exceeded)
+	assert.Equal(t, components.ErrorCategoryRateLimited, components.ClassifyError(err))
+
+	// the key without a quota is tracked but never exhausted
+	_, err = mgr.Charge(ctx, "paid", Usage{Tokens: 1000})
+	assert.NoError(t, err)
+	assert.NoError(t, mgr.Check(ctx, "paid"))
+	u, err := mgr.Usage(ctx, "paid")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1000), u.Tokens)
+
+	assert.NoError(t, mgr.Reset(ctx, "free"))
+	assert.NoError(t, mgr.Check(ctx, "free"))
+}
+
+func TestChatModel(t *testing.T) {
+	ctx := context.Background()
+	mgr := newTestManager(t)
+
+	_, err := NewChatModel(modeltest.New(), nil)
+	assert.Error(t, err)
+
+	inner := modeltest.New(modeltest.When(modeltest.Any(),
+		&modeltest.Response{Message: withUsage(schema.AssistantMessage("a", nil), 60)},
+		&modeltest.Response{Chunks: []*schema.Message{
+			schema.AssistantMessage("b", nil),
+			withUsage(schema.AssistantMessage("", nil), 50),
+		}},
+		&modeltest.Response{Message: withUsage(schema.AssistantMessage("c", nil), 10)},
+	))
+	var charged []float64
+	cm, err := NewChatModel(inner, &ModelConfig{
+		Manager: mgr,
+		CostFunc: func(_ context.Context, output *model.CallbackOutput) float64 {
+			cost := float64(output.TokenUsage.TotalTokens) / 1000
+			charged = append(charged, cost)
+			return cost
+		},
+	})
+	assert.NoError(t, err)
+
+	input := []*schema.Message{schema.UserMessage("hi")}
+	keyCtx := WithKey(ctx, "free")
+	_, err = cm.Generate(keyCtx, input)
+	assert.NoError(t, err)
+
+	sr, err := cm.Stream(keyCtx, input)
+	assert.NoError(t, err)
+	for {
+		_, err = sr.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+	}
+	sr.Close()
+	assert.Eventually(t, func() bool {
+		u, _ := mgr.Usage(ctx, "free")
+		return u.Tokens == 110
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, []float64{0.06, 0.05}, charged)
+
+	_, err = cm.Generate(keyCtx, input)
+	assert.ErrorIs(t, err, ErrExhausted)
+	assert.Len(t, inner.Calls(), 2)
+
+	// the calls without a key pass through
+	_, err = cm.Generate(ctx, input)
+	assert.NoError(t, err)
+	assert.Len(t, inner.Calls(), 3)
+}
+
+func TestHandler(t *testing.T) {
+	ctx := context.Background()
+	mgr := newTestManager(t)
+	h, err := NewHandler(&ModelConfig{Manager: mgr})
+	assert.NoError(t, err)
+
+	cbCtx := callbacks.InitCallbacks(WithKey(ctx, "free"), &callbacks.RunInfo{Component: components.ComponentOfChatModel}, h)
+	callbacks.OnEnd(cbCtx, &model.CallbackOutput{TokenUsage: &model.TokenUsage{TotalTokens: 30}})
+
+	sr, sw := schema.Pipe[*model.CallbackOutput](2)
+	sw.Send(&model.CallbackOutput{Message: schema.AssistantMessage("a", nil)}, nil)
+	sw.Send(&model.CallbackOutput{Message: withUsage(schema.AssistantMessage("", nil), 20)}, nil)
+	sw.Close()
+	_, sr = callbacks.OnEndWithStreamOutput(cbCtx, sr)
+	sr.Close()
+
+	assert.Eventually(t, func() bool {
+		u, _ := mgr.Usage(ctx, "free")
+		return u.Tokens == 50
+	}, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,236 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package budget
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/cloudwego/eino/callbacks"
+	"github.com/cloudwego/eino/components"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	ub "github.com/cloudwego/eino/utils/callbacks"
+)
+
+// ModelConfig is the config of NewChatModel and NewHandler.
+type ModelConfig struct {
+	// Manager tracks and enforces the quotas of the keys.
+	// Required.
+	Manager Manager
+	// CostFunc computes the cost of a model call, the cost isn't charged without it. Optional.
+	CostFunc func(ctx context.Context, output *model.CallbackOutput) float64
+	// OnChargeError is called when the usage of a call fails to be charged, e.g. the Store is unavailable,
+	// the call itself isn't failed by it. Optional.
+	OnChargeError func(ctx context.Context, key string, err error)
+}
+
+// NewChatModel wraps the model to enforce the quota of the key set by WithKey.
+// Each call is rejected with an *ExhaustedError without calling the model once the quota of the key is exhausted,
+// otherwise the usage of the call is charged to the key, after the output stream is fully received in stream mode.
+// The calls without a key pass through.
+// e.g.
+//
+//	cm, err := budget.NewChatModel(chatModel, &budget.ModelConfig{Manager: mgr})
+//	out, err := runnable.Invoke(budget.WithKey(ctx, tenantID), input)
+//	if errors.Is(err, budget.ErrExhausted) {
+//		// reject the request of the tenant
+//	}
+func NewChatModel(m model.BaseChatModel, config *ModelConfig) (model.ToolCallingChatModel, error) {
+	if m == nil {
+		return nil, errors.New("model is required")
+	}
+	if config == nil || config.Manager == nil {
+		return nil, errors.New("manager is required")
+	}
+	return &chatModel{inner: m, config: config}, nil
+}
+
+type chatModel struct {
+	inner  model.BaseChatModel
+	config *ModelConfig
+}
+
+func (c *chatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	key, ok := KeyFromContext(ctx)
+	if !ok {
+		return c.inner.Generate(ctx, input, opts...)
+	}
+	if err := c.config.Manager.Check(ctx, key); err != nil {
+		return nil, err
+	}
+
+	out, err := c.inner.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.config.charge(ctx, key, &model.CallbackOutput{Message: out, Config: modelConfig(opts)})
+	return out, nil
+}
+
+func (c *chatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	key, ok := KeyFromContext(ctx)
+	if !ok {
+		return c.inner.Stream(ctx, input, opts...)
+	}
+	if err := c.config.Manager.Check(ctx, key); err != nil {
+		return nil, err
+	}
+
+	sr, err := c.inner.Stream(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	srs := sr.Copy(2)
+	go func() {
+		defer srs[1].Close()
+		var last *schema.Message
+		for {
+			chunk, err := srs[1].Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return
+			}
+			if chunk != nil && chunk.ResponseMeta != nil && chunk.ResponseMeta.Usage != nil {
+				last = chunk
+			}
+		}
+		if last != nil {
+			c.config.charge(ctx, key, &model.CallbackOutput{Message: last, Config: modelConfig(opts)})
+		}
+	}()
+	return srs[0], nil
+}
+
+func (c *chatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	tcm, ok := c.inner.(model.ToolCallingChatModel)
+	if !ok {
+		return nil, fmt.Errorf("model[%T] isn't a ToolCallingChatModel", c.inner)
+	}
+	inner, err := tcm.WithTools(tools)
+	if err != nil {
+		return nil, err
+	}
+	return &chatModel{inner: inner, config: c.config}, nil
+}
+
+func (c *chatModel) GetType() string {
+	if typ, ok := components.GetType(c.inner); ok {
+		return typ
+	}
+	return "BudgetChatModel"
+}
+
+func (c *chatModel) IsCallbacksEnabled() bool {
+	return components.IsCallbacksEnabled(c.inner)
+}
+
+// NewHandler creates a callback handler charging the usage of the chat models to the key set by WithKey,
+// for the models not wrapped by NewChatModel, e.g. by compose.WithCallbacks or callbacks.RegisterGlobalHandler.
+// It only tracks the usage, use Manager.Check to reject the work before it starts.
+// Don't use it together with NewChatModel, or the calls are charged twice.
+func NewHandler(config *ModelConfig) (callbacks.Handler, error) {
+	if config == nil || config.Manager == nil {
+		return nil, errors.New("manager is required")
+	}
+	h := &ub.ModelCallbackHandler{
+		OnEnd: func(ctx context.Context, _ *callbacks.RunInfo, output *model.CallbackOutput) context.Context {
+			if key, ok := KeyFromContext(ctx); ok {
+				config.charge(ctx, key, output)
+			}
+			return ctx
+		},
+		OnEndWithStreamOutput: func(ctx context.Context, _ *callbacks.RunInfo, output *schema.StreamReader[*model.CallbackOutput]) context.Context {
+			key, ok := KeyFromContext(ctx)
+			if !ok {
+				output.Close()
+				return ctx
+			}
+			go func() {
+				defer output.Close()
+				var last *model.CallbackOutput
+				for {
+					chunk, err := output.Recv()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						return
+					}
+					if tokenUsageOf(chunk) != nil {
+						last = chunk
+					}
+				}
+				if last != nil {
+					config.charge(ctx, key, last)
+				}
+			}()
+			return ctx
+		},
+	}
+	return ub.NewHandlerHelper().ChatModel(h).Handler(), nil
+}
+
+// charge charges the usage of the model call to the key.
+func (c *ModelConfig) charge(ctx context.Context, key string, output *model.CallbackOutput) {
+	tu := tokenUsageOf(output)
+	if tu == nil {
+		return
+	}
+	usage := Usage{Tokens: int64(tu.TotalTokens)}
+	if c.CostFunc != nil {
+		o := *output
+		o.TokenUsage = tu
+		usage.Cost = c.CostFunc(ctx, &o)
+	}
+	if _, err := c.Manager.Charge(ctx, key, usage); err != nil && c.OnChargeError != nil {
+		c.OnChargeError(ctx, key, err)
+	}
+}
+
+func modelConfig(opts []model.Option) *model.Config {
+	o := model.GetCommonOptions(nil, opts...)
+	if o.Model == nil {
+		return nil
+	}
+	return &model.Config{Model: *o.Model}
+}
+
+// tokenUsageOf returns the token usage of the output, taking it from the response meta of the message if absent.
+func tokenUsageOf(output *model.CallbackOutput) *model.TokenUsage {
+	if output == nil {
+		return nil
+	}
+	if output.TokenUsage != nil {
+		return output.TokenUsage
+	}
+	if output.Message == nil || output.Message.ResponseMeta == nil || output.Message.ResponseMeta.Usage == nil {
+		return nil
+	}
+	u := output.Message.ResponseMeta.Usage
+	return &model.TokenUsage{
+		PromptTokens:           u.PromptTokens,
+		PromptTokenDetails:     model.PromptTokenDetails{CachedTokens: u.PromptTokenDetails.CachedTokens},
+		CompletionTokens:       u.CompletionTokens,
+		CompletionTokenDetails: model.CompletionTokenDetails{ReasoningTokens: u.CompletionTokenDetails.ReasoningTokens},
+		TotalTokens:            u.TotalTokens,
+	}
+}
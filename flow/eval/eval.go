@@ -0,0 +1,328 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package eval runs a compiled Runnable or an agent over a dataset, scores the outputs, and reports the metrics,
+// so that the changes of prompts and graphs can be regression tested.
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/internal/safe"
+)
+
+const defaultConcurrency = 4
+
+// Case is a case of the dataset.
+type Case[I, O any] struct {
+	// ID identifies the case in the report, the index in the dataset by default.
+	ID    string
+	Input I
+	// Expected is the expected output, compared with the output by the scorers like ExactMatch.
+	Expected O
+	// Metadata is reported with the case, e.g. the category of the case.
+	Metadata map[string]any
+}
+
+// Dataset provides the cases to evaluate, it's read by one goroutine only.
+type Dataset[I, O any] interface {
+	// Next returns the next case, or io.EOF once all the cases have been returned.
+	Next(ctx context.Context) (*Case[I, O], error)
+}
+
+// DatasetFunc is a Dataset loading the cases on demand, e.g. from a file or a database.
+type DatasetFunc[I, O any] func(ctx context.Context) (*Case[I, O], error)
+
+func (f DatasetFunc[I, O]) Next(ctx context.Context) (*Case[I, O], error) {
+	return f(ctx)
+}
+
+// FromSlice returns a Dataset of the cases.
+func FromSlice[I, O any](cases []*Case[I, O]) Dataset[I, O] {
+	i := 0
+	return DatasetFunc[I, O](func(context.Context) (*Case[I, O], error) {
+		if i >= len(cases) {
+			return nil, io.EOF
+		}
+		i++
+		return cases[i-1], nil
+	})
+}
+
+// Target is what to evaluate, see RunnableTarget and AgentTarget.
+type Target[I, O any] func(ctx context.Context, input I) (O, error)
+
+// Score is the score of an output given by a Scorer.
+type Score struct {
+	// Value is the score, in [0, 1] for the builtin scorers.
+	Value  float64 `json:"value"`
+	Passed bool    `json:"passed"`
+	// Reason explains the score, e.g. given by the judge model.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Scorer scores the output of a case.
+type Scorer[I, O any] interface {
+	// Name is the name of the metric in the report.
+	Name() string
+	Score(ctx context.Context, c *Case[I, O], output O) (*Score, error)
+}
+
+// Config is the config of Run.
+type Config[I, O any] struct {
+	// Target is evaluated with the input of each case.
+	// Required.
+	Target Target[I, O]
+	// Scorers score the output of each case successfully run.
+	// Required.
+	Scorers []Scorer[I, O]
+	// Concurrency is the max number of cases run at the same time. Optional, defaults to 4.
+	Concurrency int
+	// CaseTimeout limits the time of running and scoring each case. Optional.
+	CaseTimeout time.Duration
+}
+
+// Report is the machine-readable result of an evaluation, write it by WriteJSON.
+type Report struct {
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	// Cases are the results of the cases, in the order of the dataset.
+	Cases []*CaseResult `json:"cases"`
+	// Errors is the number of cases failed to run.
+	Errors int `json:"errors"`
+	// Metrics are the aggregated scores keyed by the names of the scorers.
+	Metrics map[string]*Metric `json:"metrics"`
+	// Latency is the latency of the target over the cases run successfully.
+	Latency *LatencyStats `json:"latency"`
+}
+
+// CaseResult is the result of a case.
+type CaseResult struct {
+	ID       string         `json:"id"`
+	Input    any            `json:"input"`
+	Expected any            `json:"expected,omitempty"`
+	Output   any            `json:"output,omitempty"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+	// Err is the error of running the target, the case isn't scored if set.
+	Err     string        `json:"err,omitempty"`
+	Latency time.Duration `json:"latency"`
+	// Scores are keyed by the names of the scorers, missing if the scorer fails, see ScoreErrors.
+	Scores      map[string]*Score `json:"scores,omitempty"`
+	ScoreErrors map[string]string `json:"score_errors,omitempty"`
+}
+
+// Metric aggregates the scores of a scorer.
+type Metric struct {
+	// Count is the number of the cases scored.
+	Count    int     `json:"count"`
+	Mean     float64 `json:"mean"`
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	PassRate float64 `json:"pass_rate"`
+	// Errors is the number of the cases the scorer failed on.
+	Errors int `json:"errors"`
+}
+
+// LatencyStats is the statistics of the latency.
+type LatencyStats struct {
+	Mean time.Duration `json:"mean"`
+	P50  time.Duration `json:"p50"`
+	P95  time.Duration `json:"p95"`
+	Max  time.Duration `json:"max"`
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *Report) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// Run runs the target over the dataset concurrently, scores the outputs by the scorers, and aggregates the scores.
+// The failures of the target and the scorers are recorded in the report instead of failing the evaluation,
+// an error is returned only if the config is invalid, reading the dataset fails or ctx is done.
+// e.g.
+//
+//	report, err := eval.Run(ctx, eval.FromSlice(cases), &eval.Config[string, string]{
+//		Target:  eval.RunnableTarget(runnable),
+//		Scorers: []eval.Scorer[string, string]{eval.ExactMatch[string, string](), eval.LLMJudge[string, string](judgeModel, nil)},
+//	})
+//	err = report.WriteJSON(os.Stdout)
+func Run[I, O any](ctx context.Context, dataset Dataset[I, O], config *Config[I, O]) (*Report, error) {
+	if dataset == nil {
+		return nil, errors.New("dataset is required")
+	}
+	if config == nil || config.Target == nil {
+		return nil, errors.New("target is required")
+	}
+	if len(config.Scorers) == 0 {
+		return nil, errors.New("scorers are required")
+	}
+	concurrency := config.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+
+	report := &Report{StartedAt: time.Now()}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var readErr error
+	for i := 0; ; i++ {
+		c, err := dataset.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			readErr = fmt.Errorf("read dataset fail: %w", err)
+			break
+		}
+
+		result := &CaseResult{ID: c.ID, Input: c.Input, Expected: c.Expected, Metadata: c.Metadata}
+		if result.ID == "" {
+			result.ID = fmt.Sprintf("%d", i)
+		}
+		report.Cases = append(report.Cases, result)
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			readErr = ctx.Err()
+		}
+		if readErr != nil {
+			break
+		}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				if e := recover(); e != nil {
+					result.Err = safe.NewPanicErr(e, debug.Stack()).Error()
+				}
+				<-sem
+				wg.Done()
+			}()
+			runCase(ctx, config, c, result)
+		}()
+	}
+	wg.Wait()
+	if readErr != nil {
+		return nil, readErr
+	}
+
+	report.EndedAt = time.Now()
+	aggregate(report, config.Scorers)
+	return report, nil
+}
+
+func runCase[I, O any](ctx context.Context, config *Config[I, O], c *Case[I, O], result *CaseResult) {
+	if config.CaseTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.CaseTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	output, err := config.Target(ctx, c.Input)
+	result.Latency = time.Since(start)
+	if err != nil {
+		result.Err = err.Error()
+		return
+	}
+	result.Output = output
+
+	for _, scorer := range config.Scorers {
+		score, err := scorer.Score(ctx, c, output)
+		if err != nil {
+			if result.ScoreErrors == nil {
+				result.ScoreErrors = make(map[string]string)
+			}
+			result.ScoreErrors[scorer.Name()] = err.Error()
+			continue
+		}
+		if result.Scores == nil {
+			result.Scores = make(map[string]*Score)
+		}
+		result.Scores[scorer.Name()] = score
+	}
+}
+
+func aggregate[I, O any](report *Report, scorers []Scorer[I, O]) {
+	report.Metrics = make(map[string]*Metric, len(scorers))
+	for _, scorer := range scorers {
+		report.Metrics[scorer.Name()] = &Metric{Min: math.Inf(1), Max: math.Inf(-1)}
+	}
+
+	var latencies []time.Duration
+	for _, result := range report.Cases {
+		if result.Err != "" {
+			report.Errors++
+			continue
+		}
+		latencies = append(latencies, result.Latency)
+		for name, score := range result.Scores {
+			m := report.Metrics[name]
+			m.Count++
+			m.Mean += score.Value
+			m.Min = math.Min(m.Min, score.Value)
+			m.Max = math.Max(m.Max, score.Value)
+			if score.Passed {
+				m.PassRate++
+			}
+		}
+		for name := range result.ScoreErrors {
+			report.Metrics[name].Errors++
+		}
+	}
+	for _, m := range report.Metrics {
+		if m.Count == 0 {
+			m.Min, m.Max = 0, 0
+			continue
+		}
+		m.Mean /= float64(m.Count)
+		m.PassRate /= float64(m.Count)
+	}
+
+	report.Latency = &LatencyStats{}
+	if len(latencies) == 0 {
+		return
+	}
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	var total time.Duration
+	for _, l := range latencies {
+		total += l
+	}
+	report.Latency.Mean = total / time.Duration(len(latencies))
+	report.Latency.P50 = percentile(latencies, 0.5)
+	report.Latency.P95 = percentile(latencies, 0.95)
+	report.Latency.Max = latencies[len(latencies)-1]
+}
+
+// percentile returns the nearest-rank percentile of the sorted durations.
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
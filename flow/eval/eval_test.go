@@ -0,0 +1,212 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eval
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model/modeltest"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type fakeEmbedder struct{}
+
+func (fakeEmbedder) EmbedStrings(_ context.Context, texts []string, _ ...embedding.Option) ([][]float64, error) {
+	vectors := make([][]float64, len(texts))
+	for i, text := range texts {
+		switch {
+		case strings.Contains(text, "Paris"):
+			vectors[i] = []float64{1, 0, 0}
+		case strings.Contains(text, "Rome"):
+			vectors[i] = []float64{0, 1, 0}
+		default:
+			vectors[i] = []float64{0, 0, 1}
+		}
+	}
+	return vectors, nil
+}
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+
+	g := compose.NewGraph[string, string]()
+	assert.NoError(t, g.AddLambdaNode("answer", compose.InvokableLambda(func(ctx context.Context, input string) (string, error) {
+		switch input {
+		case "capital of france":
+			return "Paris", nil
+		case "capital of italy":
+			return "Milan", nil
+		default:
+			return "", errors.New("unknown question")
+		}
+	})))
+	assert.NoError(t, g.AddEdge(compose.START, "answer"))
+	assert.NoError(t, g.AddEdge("answer", compose.END))
+	r, err := g.Compile(ctx)
+	assert.NoError(t, err)
+
+	judge := modeltest.New(
+		modeltest.When(modeltest.LastMessageContains("Answer: Paris"),
+			modeltest.Text("```json\n{\"score\": 0.9, \"reason\": \"correct\"}\n```")).Repeatedly(),
+		modeltest.When(modeltest.Any(), modeltest.Text(`{"score": 0.1, "reason": "wrong city"}`)).Repeatedly(),
+	)
+
+	cases := []*Case[string, string]{
+		{ID: "fr", Input: "capital of france", Expected: "Paris", Metadata: map[string]any{"region": "eu"}},
+		{Input: "capital of italy", Expected: "Rome"},
+		{Input: "capital of mars", Expected: "none"},
+	}
+	report, err := Run(ctx, FromSlice(cases), &Config[string, string]{
+		Target: RunnableTarget[string, string](r),
+		Scorers: []Scorer[string, string]{
+			ExactMatch[string, string](),
+			EmbeddingSimilarity[string, string](fakeEmbedder{}, 0),
+			LLMJudge[string, string](judge, nil),
+		},
+		Concurrency: 2,
+	})
+	assert.NoError(t, err)
+
+	assert.Len(t, report.Cases, 3)
+	assert.Equal(t, "fr", report.Cases[0].ID)
+	assert.Equal(t, "1", report.Cases[1].ID)
+	assert.Equal(t, "Paris", report.Cases[0].Output)
+	assert.Equal(t, "correct", report.Cases[0].Scores["llm_judge"].Reason)
+	assert.Contains(t, report.Cases[2].Err, "unknown question")
+	assert.Nil(t, report.Cases[2].Scores)
+	assert.Equal(t, 1, report.Errors)
+
+	em := report.Metrics["exact_match"]
+	assert.Equal(t, 2, em.Count)
+	assert.Equal(t, 0.5, em.Mean)
+	assert.Equal(t, 0.5, em.PassRate)
+	assert.Equal(t, 0.0, em.Min)
+	assert.Equal(t, 1.0, em.Max)
+	assert.Equal(t, 0.5, report.Metrics["embedding_similarity"].PassRate)
+	judged := report.Metrics["llm_judge"]
+	assert.InDelta(t, 0.5, judged.Mean, 1e-9)
+	assert.Equal(t, 0.5, judged.PassRate)
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, report.WriteJSON(buf))
+	decoded := map[string]any{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Contains(t, decoded, "metrics")
+	assert.Contains(t, decoded, "latency")
+}
+
+func TestRunConcurrency(t *testing.T) {
+	ctx := context.Background()
+
+	var running, maxRunning int32
+	target := func(ctx context.Context, input int) (int, error) {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		defer atomic.AddInt32(&running, -1)
+		if input == 3 {
+			panic("boom")
+		}
+		return input * 2, nil
+	}
+
+	i := 0
+	dataset := DatasetFunc[int, int](func(context.Context) (*Case[int, int], error) {
+		if i == 10 {
+			return nil, io.EOF
+		}
+		i++
+		return &Case[int, int]{Input: i, Expected: i * 2}, nil
+	})
+	report, err := Run[int, int](ctx, dataset, &Config[int, int]{
+		Target:      target,
+		Scorers:     []Scorer[int, int]{ExactMatch[int, int]()},
+		Concurrency: 3,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, report.Cases, 10)
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxRunning), int32(3))
+	assert.Equal(t, 1, report.Errors)
+	assert.Contains(t, report.Cases[2].Err, "boom")
+	assert.Equal(t, 1.0, report.Metrics["exact_match"].PassRate)
+	assert.Equal(t, 9, report.Metrics["exact_match"].Count)
+
+	_, err = Run[int, int](ctx, DatasetFunc[int, int](func(context.Context) (*Case[int, int], error) {
+		return nil, errors.New("broken")
+	}), &Config[int, int]{Target: target, Scorers: []Scorer[int, int]{ExactMatch[int, int]()}})
+	assert.ErrorContains(t, err, "broken")
+
+	_, err = Run(ctx, FromSlice[int, int](nil), &Config[int, int]{Target: target})
+	assert.Error(t, err)
+}
+
+func TestScorerErrors(t *testing.T) {
+	ctx := context.Background()
+
+	judge := modeltest.New(modeltest.When(modeltest.Any(), modeltest.Text("I think it's fine")).Repeatedly())
+	report, err := Run(ctx, FromSlice([]*Case[string, string]{{Input: "q", Expected: "a"}}), &Config[string, string]{
+		Target:  func(ctx context.Context, input string) (string, error) { return "a", nil },
+		Scorers: []Scorer[string, string]{ExactMatch[string, string](), LLMJudge[string, string](judge, nil)},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Errors)
+	assert.Contains(t, report.Cases[0].ScoreErrors["llm_judge"], "isn't a JSON object")
+	assert.Equal(t, 1, report.Metrics["llm_judge"].Errors)
+	assert.Equal(t, 0, report.Metrics["llm_judge"].Count)
+	assert.Equal(t, 1.0, report.Metrics["exact_match"].Mean)
+}
+
+func TestAgentTarget(t *testing.T) {
+	ctx := context.Background()
+
+	cm := modeltest.New(modeltest.When(modeltest.LastMessageContains("hello"), modeltest.Text("hi there")).Repeatedly())
+	agent, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "greeter",
+		Description: "greets",
+		Model:       cm,
+	})
+	assert.NoError(t, err)
+
+	report, err := Run(ctx, FromSlice([]*Case[[]*schema.Message, *schema.Message]{
+		{Input: []*schema.Message{schema.UserMessage("hello")}, Expected: schema.AssistantMessage("hi there", nil)},
+		{Input: []*schema.Message{schema.UserMessage("bye")}, Expected: schema.AssistantMessage("bye", nil)},
+	}), &Config[[]*schema.Message, *schema.Message]{
+		Target:  AgentTarget(agent),
+		Scorers: []Scorer[[]*schema.Message, *schema.Message]{ExactMatch[[]*schema.Message, *schema.Message]()},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "hi there", report.Cases[0].Output.(*schema.Message).Content)
+	assert.Equal(t, 1.0, report.Cases[0].Scores["exact_match"].Value)
+	assert.NotEmpty(t, report.Cases[1].Err)
+	assert.Equal(t, 1, report.Errors)
+}
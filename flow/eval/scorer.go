@@ -0,0 +1,210 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultSimilarityThreshold = 0.8
+	defaultJudgeThreshold      = 0.5
+)
+
+var defaultJudgePrompt = `You are an impartial judge evaluating the answer to a question against the criteria below.
+Criteria: {criteria}
+Reply a JSON object only, in the format {{"score": <a number from 0 to 1>, "reason": "<a short explanation>"}}.`
+
+const defaultCriteria = "The answer is correct, complete and consistent with the reference answer if provided."
+
+// ScorerFunc creates a Scorer with the name and the function, e.g. for the domain specific metrics.
+func ScorerFunc[I, O any](name string, fn func(ctx context.Context, c *Case[I, O], output O) (*Score, error)) Scorer[I, O] {
+	return &scorerFunc[I, O]{name: name, fn: fn}
+}
+
+type scorerFunc[I, O any] struct {
+	name string
+	fn   func(ctx context.Context, c *Case[I, O], output O) (*Score, error)
+}
+
+func (s *scorerFunc[I, O]) Name() string {
+	return s.name
+}
+
+func (s *scorerFunc[I, O]) Score(ctx context.Context, c *Case[I, O], output O) (*Score, error) {
+	return s.fn(ctx, c, output)
+}
+
+// ExactMatch scores 1 if the text of the output equals the text of the expected output, ignoring the leading and trailing spaces.
+// The text of a *schema.Message is its content, and the other values are formatted by fmt.Sprint.
+func ExactMatch[I, O any]() Scorer[I, O] {
+	return ScorerFunc("exact_match", func(_ context.Context, c *Case[I, O], output O) (*Score, error) {
+		if strings.TrimSpace(textOf(output)) == strings.TrimSpace(textOf(c.Expected)) {
+			return &Score{Value: 1, Passed: true}, nil
+		}
+		return &Score{Value: 0}, nil
+	})
+}
+
+// EmbeddingSimilarity scores the cosine similarity of the embeddings of the output and the expected output,
+// the case passes if the similarity reaches the threshold. The threshold is 0.8 if it's not positive.
+func EmbeddingSimilarity[I, O any](embedder embedding.Embedder, threshold float64) Scorer[I, O] {
+	if threshold <= 0 {
+		threshold = defaultSimilarityThreshold
+	}
+	return ScorerFunc("embedding_similarity", func(ctx context.Context, c *Case[I, O], output O) (*Score, error) {
+		vectors, err := embedder.EmbedStrings(ctx, []string{textOf(output), textOf(c.Expected)})
+		if err != nil {
+			return nil, fmt.Errorf("embed fail: %w", err)
+		}
+		if len(vectors) != 2 {
+			return nil, fmt.Errorf("embedding returns %d vectors for 2 texts", len(vectors))
+		}
+		similarity := cosineSimilarity(vectors[0], vectors[1])
+		return &Score{Value: similarity, Passed: similarity >= threshold}, nil
+	})
+}
+
+// JudgeConfig is the config of LLMJudge.
+type JudgeConfig struct {
+	// Name is the name of the metric. Optional, defaults to "llm_judge".
+	Name string
+	// Criteria tells the judge how to score the output, the correctness against the expected output by default.
+	Criteria string
+	// Template formats the messages to the judge with the variables "criteria", "input", "expected" and "output",
+	// we provide a default one so you can leave it blank.
+	// The judge should reply a JSON object like {"score": 0.8, "reason": "..."}, in which the score is from 0 to 1.
+	Template prompt.ChatTemplate
+	// Threshold is the min score of a passed case. Optional, defaults to 0.5.
+	Threshold float64
+}
+
+// LLMJudge scores the output by asking the chat model, with the input, the expected output and the output of the case.
+// e.g.
+//
+//	scorer := eval.LLMJudge[[]*schema.Message, *schema.Message](judgeModel, &eval.JudgeConfig{
+//		Criteria: "The answer is polite and cites the documents.",
+//	})
+func LLMJudge[I, O any](chatModel model.BaseChatModel, config *JudgeConfig) Scorer[I, O] {
+	conf := JudgeConfig{}
+	if config != nil {
+		conf = *config
+	}
+	if conf.Name == "" {
+		conf.Name = "llm_judge"
+	}
+	if conf.Criteria == "" {
+		conf.Criteria = defaultCriteria
+	}
+	if conf.Template == nil {
+		conf.Template = prompt.FromMessages(schema.FString,
+			schema.SystemMessage(defaultJudgePrompt),
+			schema.UserMessage("Question: {input}\n\nReference answer: {expected}\n\nAnswer: {output}"))
+	}
+	if conf.Threshold <= 0 {
+		conf.Threshold = defaultJudgeThreshold
+	}
+
+	return ScorerFunc(conf.Name, func(ctx context.Context, c *Case[I, O], output O) (*Score, error) {
+		if chatModel == nil {
+			return nil, errors.New("chat model is required")
+		}
+		msgs, err := conf.Template.Format(ctx, map[string]any{
+			"criteria": conf.Criteria,
+			"input":    textOf(c.Input),
+			"expected": textOf(c.Expected),
+			"output":   textOf(output),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("format judge prompt fail: %w", err)
+		}
+		reply, err := chatModel.Generate(ctx, msgs)
+		if err != nil {
+			return nil, fmt.Errorf("judge fail: %w", err)
+		}
+		verdict, err := parseVerdict(reply.Content)
+		if err != nil {
+			return nil, err
+		}
+		return &Score{Value: verdict.Score, Passed: verdict.Score >= conf.Threshold, Reason: verdict.Reason}, nil
+	})
+}
+
+type verdict struct {
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// parseVerdict parses the JSON object in the reply of the judge, the text around it, e.g. a markdown code fence, is ignored.
+func parseVerdict(reply string) (*verdict, error) {
+	start, end := strings.Index(reply, "{"), strings.LastIndex(reply, "}")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("judge reply isn't a JSON object: %s", reply)
+	}
+	v := &verdict{}
+	if err := json.Unmarshal([]byte(reply[start:end+1]), v); err != nil {
+		return nil, fmt.Errorf("unmarshal judge reply fail: %w", err)
+	}
+	v.Score = math.Max(0, math.Min(1, v.Score))
+	return v, nil
+}
+
+// textOf returns the text of the value to compare, the content of a message or fmt.Sprint of the others.
+func textOf(v any) string {
+	switch t := v.(type) {
+	case string:
+		return t
+	case *schema.Message:
+		if t == nil {
+			return ""
+		}
+		return t.Content
+	case []*schema.Message:
+		texts := make([]string, 0, len(t))
+		for _, m := range t {
+			if m != nil {
+				texts = append(texts, fmt.Sprintf("%s: %s", m.Role, m.Content))
+			}
+		}
+		return strings.Join(texts, "\n")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package eval
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RunnableTarget evaluates the compiled Runnable by Invoke with the options.
+func RunnableTarget[I, O any](r compose.Runnable[I, O], opts ...compose.Option) Target[I, O] {
+	return func(ctx context.Context, input I) (O, error) {
+		return r.Invoke(ctx, input, opts...)
+	}
+}
+
+// AgentTarget evaluates the agent by running it with the input messages, the output is the last message the agent emits.
+// An error event of the run, or an interrupt of the run, fails the case.
+func AgentTarget(agent adk.Agent, opts ...adk.AgentRunOption) Target[[]*schema.Message, *schema.Message] {
+	return func(ctx context.Context, input []*schema.Message) (*schema.Message, error) {
+		runner := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent})
+		iter := runner.Run(ctx, input, opts...)
+
+		var last *schema.Message
+		for {
+			event, ok := iter.Next()
+			if !ok {
+				break
+			}
+			if event.Err != nil {
+				return nil, event.Err
+			}
+			if event.Action != nil && event.Action.Interrupted != nil {
+				return nil, errors.New("agent interrupted")
+			}
+			msg, _, err := adk.GetMessage(event)
+			if err != nil {
+				return nil, err
+			}
+			if msg != nil {
+				last = msg
+			}
+		}
+		if last == nil {
+			return nil, errors.New("agent emits no message")
+		}
+		return last, nil
+	}
+}
@@ -0,0 +1,227 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incremental
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RecordStore keeps the content hashes of the documents in the index, grouped by namespaces, e.g. the sources of the documents.
+type RecordStore interface {
+	// List returns the content hashes of the documents in the namespace, keyed by the document IDs.
+	List(ctx context.Context, namespace string) (map[string]string, error)
+	// Update sets the content hashes of the documents in the namespace, keyed by the document IDs.
+	Update(ctx context.Context, namespace string, hashes map[string]string) error
+	// Delete deletes the records of the documents in the namespace.
+	Delete(ctx context.Context, namespace string, ids []string) error
+}
+
+// NewInMemoryRecordStore creates a RecordStore in memory, which is useful for tests and the indexes rebuilt on startup.
+func NewInMemoryRecordStore() RecordStore {
+	return &inMemoryRecordStore{records: make(map[string]map[string]string)}
+}
+
+type inMemoryRecordStore struct {
+	mu      sync.RWMutex
+	records map[string]map[string]string
+}
+
+func (s *inMemoryRecordStore) List(_ context.Context, namespace string) (map[string]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ret := make(map[string]string, len(s.records[namespace]))
+	for id, hash := range s.records[namespace] {
+		ret[id] = hash
+	}
+	return ret, nil
+}
+
+func (s *inMemoryRecordStore) Update(_ context.Context, namespace string, hashes map[string]string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.records[namespace] == nil {
+		s.records[namespace] = make(map[string]string, len(hashes))
+	}
+	for id, hash := range hashes {
+		s.records[namespace][id] = hash
+	}
+	return nil
+}
+
+func (s *inMemoryRecordStore) Delete(_ context.Context, namespace string, ids []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		delete(s.records[namespace], id)
+	}
+	return nil
+}
+
+// Config is the config of the incremental indexer.
+type Config struct {
+	// Indexer stores the documents. Required.
+	// It should support indexer.WithUpsert to replace the updated documents, and implement indexer.Deleter to delete the removed ones.
+	Indexer indexer.Indexer
+	// RecordStore keeps the content hashes of the indexed documents. Required.
+	RecordStore RecordStore
+	// Hash returns the content hash of a document, which decides whether the document has changed.
+	// Optional. Default is the SHA-256 of the content and the metadata.
+	Hash func(doc *schema.Document) (string, error)
+}
+
+// SyncResult is the IDs of the documents changed by a Sync, each sorted.
+type SyncResult struct {
+	Added     []string
+	Updated   []string
+	Unchanged []string
+	Deleted   []string
+}
+
+// NewIndexer creates an incremental indexer, which keeps the documents of a namespace in the index in sync with a changing source,
+// storing only the added and updated documents and deleting the removed ones by diffing the content hashes.
+// e.g.
+//
+//	idx, err := incremental.NewIndexer(ctx, &incremental.Config{
+//		Indexer:     vectorIndexer,
+//		RecordStore: incremental.NewInMemoryRecordStore(),
+//	})
+//	docs, err := loader.Load(ctx, document.Source{URI: uri})
+//	result, err := idx.Sync(ctx, uri, docs)
+func NewIndexer(ctx context.Context, config *Config) (*Indexer, error) {
+	if config.Indexer == nil {
+		return nil, errors.New("indexer is required")
+	}
+	if config.RecordStore == nil {
+		return nil, errors.New("record store is required")
+	}
+	hash := config.Hash
+	if hash == nil {
+		hash = defaultHash
+	}
+	return &Indexer{indexer: config.Indexer, records: config.RecordStore, hash: hash}, nil
+}
+
+// Indexer is the incremental indexer, see NewIndexer.
+type Indexer struct {
+	indexer indexer.Indexer
+	records RecordStore
+	hash    func(doc *schema.Document) (string, error)
+}
+
+// Sync makes the documents of the namespace in the index the same as docs.
+// Documents are identified by their IDs, a document without ID takes its content hash as the ID,
+// so that changing it is treated as deleting the old one and adding a new one.
+// The added and updated documents are stored with indexer.WithUpsert and the options, then the removed ones are deleted.
+// The records are updated after the index, so a failed Sync can be retried.
+func (i *Indexer) Sync(ctx context.Context, namespace string, docs []*schema.Document, opts ...indexer.Option) (*SyncResult, error) {
+	existing, err := i.records.List(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("list records fail: %w", err)
+	}
+
+	result := &SyncResult{}
+	var changed []*schema.Document
+	hashes := make(map[string]string, len(docs))
+	for _, doc := range docs {
+		hash, err := i.hash(doc)
+		if err != nil {
+			return nil, fmt.Errorf("hash document fail: %w", err)
+		}
+		id := doc.ID
+		if id == "" {
+			id = hash
+		}
+		if _, ok := hashes[id]; ok {
+			return nil, fmt.Errorf("duplicated document id: %s", id)
+		}
+		hashes[id] = hash
+
+		old, ok := existing[id]
+		switch {
+		case !ok:
+			result.Added = append(result.Added, id)
+		case old != hash:
+			result.Updated = append(result.Updated, id)
+		default:
+			result.Unchanged = append(result.Unchanged, id)
+			continue
+		}
+		cp := *doc
+		cp.ID = id
+		changed = append(changed, &cp)
+	}
+	for id := range existing {
+		if _, ok := hashes[id]; !ok {
+			result.Deleted = append(result.Deleted, id)
+		}
+	}
+
+	deleter, ok := i.indexer.(indexer.Deleter)
+	if len(result.Deleted) > 0 && !ok {
+		return nil, fmt.Errorf("indexer[%T] doesn't implement indexer.Deleter to delete %d document(s)", i.indexer, len(result.Deleted))
+	}
+
+	if len(changed) > 0 {
+		if _, err = i.indexer.Store(ctx, changed, append(opts, indexer.WithUpsert())...); err != nil {
+			return nil, fmt.Errorf("store documents fail: %w", err)
+		}
+		changedHashes := make(map[string]string, len(changed))
+		for _, doc := range changed {
+			changedHashes[doc.ID] = hashes[doc.ID]
+		}
+		if err = i.records.Update(ctx, namespace, changedHashes); err != nil {
+			return nil, fmt.Errorf("update records fail: %w", err)
+		}
+	}
+	if len(result.Deleted) > 0 {
+		sort.Strings(result.Deleted)
+		if err = deleter.Delete(ctx, result.Deleted, opts...); err != nil {
+			return nil, fmt.Errorf("delete documents fail: %w", err)
+		}
+		if err = i.records.Delete(ctx, namespace, result.Deleted); err != nil {
+			return nil, fmt.Errorf("delete records fail: %w", err)
+		}
+	}
+
+	sort.Strings(result.Added)
+	sort.Strings(result.Updated)
+	sort.Strings(result.Unchanged)
+	return result, nil
+}
+
+func defaultHash(doc *schema.Document) (string, error) {
+	// json sorts the keys of the metadata
+	b, err := json.Marshal(struct {
+		Content  string         `json:"content"`
+		MetaData map[string]any `json:"meta_data"`
+	}{doc.Content, doc.MetaData})
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package incremental
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/indexer"
+	"github.com/cloudwego/eino/schema"
+)
+
+type mapIndexer struct {
+	docs   map[string]string
+	stores int
+}
+
+func (m *mapIndexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) ([]string, error) {
+	m.stores++
+	ids := make([]string, len(docs))
+	for i, doc := range docs {
+		if _, ok := m.docs[doc.ID]; ok && !indexer.GetCommonOptions(nil, opts...).Upsert {
+			return nil, assert.AnError
+		}
+		m.docs[doc.ID] = doc.Content
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
+
+func (m *mapIndexer) Delete(ctx context.Context, ids []string, opts ...indexer.Option) error {
+	for _, id := range ids {
+		delete(m.docs, id)
+	}
+	return nil
+}
+
+type storeOnlyIndexer struct {
+	inner *mapIndexer
+}
+
+func (s *storeOnlyIndexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) ([]string, error) {
+	return s.inner.Store(ctx, docs, opts...)
+}
+
+func TestIncrementalIndexer(t *testing.T) {
+	ctx := context.Background()
+	idx := &mapIndexer{docs: map[string]string{}}
+	records := NewInMemoryRecordStore()
+	inc, err := NewIndexer(ctx, &Config{Indexer: idx, RecordStore: records})
+	assert.NoError(t, err)
+
+	result, err := inc.Sync(ctx, "src", []*schema.Document{{ID: "a", Content: "1"}, {ID: "b", Content: "2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &SyncResult{Added: []string{"a", "b"}}, result)
+
+	noID := &schema.Document{Content: "3"}
+	result, err = inc.Sync(ctx, "src", []*schema.Document{{ID: "b", Content: "2"}, {ID: "a", Content: "1'"}, noID})
+	assert.NoError(t, err)
+	hash, _ := defaultHash(noID)
+	assert.Equal(t, &SyncResult{Added: []string{hash}, Updated: []string{"a"}, Unchanged: []string{"b"}}, result)
+	assert.Equal(t, map[string]string{"a": "1'", "b": "2", hash: "3"}, idx.docs)
+	// the documents passed in are not modified
+	assert.Empty(t, noID.ID)
+
+	result, err = inc.Sync(ctx, "src", []*schema.Document{{ID: "b", Content: "2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &SyncResult{Unchanged: []string{"b"}, Deleted: []string{hash, "a"}}, result)
+	assert.Equal(t, map[string]string{"b": "2"}, idx.docs)
+
+	// nothing changed, nothing stored
+	stores := idx.stores
+	_, err = inc.Sync(ctx, "src", []*schema.Document{{ID: "b", Content: "2"}})
+	assert.NoError(t, err)
+	assert.Equal(t, stores, idx.stores)
+
+	// namespaces are independent
+	result, err = inc.Sync(ctx, "other", []*schema.Document{{ID: "c", Content: "4"}})
+	assert.NoError(t, err)
+	assert.Equal(t, &SyncResult{Added: []string{"c"}}, result)
+	recs, err := records.List(ctx, "src")
+	assert.NoError(t, err)
+	assert.Len(t, recs, 1)
+
+	_, err = inc.Sync(ctx, "src", []*schema.Document{{ID: "d", Content: "5"}, {ID: "d", Content: "6"}})
+	assert.ErrorContains(t, err, "duplicated document id: d")
+
+	t.Run("indexer without delete", func(t *testing.T) {
+		storeOnly := &storeOnlyIndexer{inner: &mapIndexer{docs: map[string]string{}}}
+		inc, err := NewIndexer(ctx, &Config{Indexer: storeOnly, RecordStore: NewInMemoryRecordStore()})
+		assert.NoError(t, err)
+		_, err = inc.Sync(ctx, "src", []*schema.Document{{ID: "a", Content: "1"}})
+		assert.NoError(t, err)
+		_, err = inc.Sync(ctx, "src", nil)
+		assert.ErrorContains(t, err, "doesn't implement indexer.Deleter")
+	})
+}
@@ -22,6 +22,7 @@ import (
 
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/indexer"
+	retrieverparent "github.com/cloudwego/eino/flow/retriever/parent"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -57,6 +58,14 @@ type Config struct {
 	//   - []string: slice of generated sub-document IDs
 	//   - error: any error encountered during ID generation
 	SubIDGenerator func(ctx context.Context, parentID string, num int) ([]string, error)
+
+	// ChunkIndexKey specifies the metadata key used to store the index of each sub-document in its parent document, starting from 0.
+	// Optional. It's required by the parent retriever to expand the retrieved chunks with their neighbors.
+	ChunkIndexKey string
+
+	// DocStore stores the original documents before they are split, so that the parent retriever can get them by ids.
+	// Optional. For example: store the parent documents in Redis while the chunks are indexed in a vector database.
+	DocStore retrieverparent.DocStore
 }
 
 // NewIndexer creates a new parent indexer that handles document splitting and sub-document management.
@@ -99,6 +108,8 @@ func NewIndexer(ctx context.Context, config *Config) (indexer.Indexer, error) {
 		transformer:    config.Transformer,
 		parentIDKey:    config.ParentIDKey,
 		subIDGenerator: config.SubIDGenerator,
+		chunkIndexKey:  config.ChunkIndexKey,
+		docStore:       config.DocStore,
 	}, nil
 }
 
@@ -107,9 +118,16 @@ type parentIndexer struct {
 	transformer    document.Transformer
 	parentIDKey    string
 	subIDGenerator func(ctx context.Context, parentID string, num int) ([]string, error)
+	chunkIndexKey  string
+	docStore       retrieverparent.DocStore
 }
 
 func (p *parentIndexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) ([]string, error) {
+	if p.docStore != nil {
+		if err := p.docStore.MSet(ctx, docs); err != nil {
+			return nil, fmt.Errorf("store parent docs fail: %w", err)
+		}
+	}
 	subDocs, err := p.transformer.Transform(ctx, docs)
 	if err != nil {
 		return nil, fmt.Errorf("transform docs fail: %w", err)
@@ -139,6 +157,7 @@ func (p *parentIndexer) Store(ctx context.Context, docs []*schema.Document, opts
 		}
 		for j := startIdx; j < i; j++ {
 			subDocs[j].ID = subIDs[j-startIdx]
+			p.setChunkIndex(subDocs[j], j-startIdx)
 		}
 		startIdx = i
 		currentID = subDoc.ID
@@ -153,7 +172,14 @@ func (p *parentIndexer) Store(ctx context.Context, docs []*schema.Document, opts
 	}
 	for j := startIdx; j < len(subDocs); j++ {
 		subDocs[j].ID = subIDs[j-startIdx]
+		p.setChunkIndex(subDocs[j], j-startIdx)
 	}
 
 	return p.indexer.Store(ctx, subDocs, opts...)
 }
+
+func (p *parentIndexer) setChunkIndex(subDoc *schema.Document, index int) {
+	if p.chunkIndexKey != "" {
+		subDoc.MetaData[p.chunkIndexKey] = index
+	}
+}
@@ -24,8 +24,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/cloudwego/eino/components/document"
 	"github.com/cloudwego/eino/components/indexer"
+	retrieverparent "github.com/cloudwego/eino/flow/retriever/parent"
 	"github.com/cloudwego/eino/schema"
 )
 
@@ -112,6 +115,57 @@ func TestParentIndexer(t *testing.T) {
 	}
 }
 
+type recordIndexer struct {
+	docs []*schema.Document
+}
+
+func (r *recordIndexer) Store(ctx context.Context, docs []*schema.Document, opts ...indexer.Option) (ids []string, err error) {
+	r.docs = append(r.docs, docs...)
+	for _, d := range docs {
+		ids = append(ids, d.ID)
+	}
+	return ids, nil
+}
+
+func TestParentIndexerWithDocStore(t *testing.T) {
+	ctx := context.Background()
+	store := retrieverparent.NewInMemoryDocStore()
+	rec := &recordIndexer{}
+	index, err := NewIndexer(ctx, &Config{
+		Indexer:     rec,
+		Transformer: &testTransformer{},
+		ParentIDKey: "parent",
+		SubIDGenerator: func(ctx context.Context, parentID string, num int) ([]string, error) {
+			ret := make([]string, num)
+			for i := range ret {
+				ret[i] = parentID + strconv.Itoa(i)
+			}
+			return ret, nil
+		},
+		ChunkIndexKey: "index",
+		DocStore:      store,
+	})
+	assert.NoError(t, err)
+
+	_, err = index.Store(ctx, []*schema.Document{
+		{ID: "id", Content: "1234567890", MetaData: map[string]interface{}{}},
+		{ID: "ID", Content: "0987654321", MetaData: map[string]interface{}{}},
+	})
+	assert.NoError(t, err)
+
+	var indexes []any
+	for _, d := range rec.docs {
+		indexes = append(indexes, d.MetaData["index"])
+	}
+	assert.Equal(t, []any{0, 1, 0, 1}, indexes)
+
+	parents, err := store.MGet(ctx, []string{"ID", "id"})
+	assert.NoError(t, err)
+	assert.Len(t, parents, 2)
+	assert.Equal(t, "0987654321", parents[0].Content)
+	assert.Equal(t, "1234567890", parents[1].Content)
+}
+
 func deepCopyMap(in map[string]interface{}) map[string]interface{} {
 	out := make(map[string]interface{})
 	for k, v := range in {
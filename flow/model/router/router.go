@@ -0,0 +1,319 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package router provides a chat model that routes each request to one of the registered models.
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// ErrNoCandidate is returned when none of the candidates satisfies the request.
+var ErrNoCandidate = errors.New("no candidate model satisfies the request")
+
+// Capabilities declares what a candidate model supports.
+type Capabilities struct {
+	// Vision means the model accepts image and video input.
+	Vision bool
+	// ToolCalling means the model supports tool calling.
+	ToolCalling bool
+	// ContextWindow is the max number of tokens of the prompt and the output together.
+	// Zero means unlimited.
+	ContextWindow int
+}
+
+// Candidate is a model registered in the router.
+type Candidate struct {
+	// Name identifies the candidate, e.g. in errors and in Policy.
+	Name string
+	// Model is the model called when the candidate is selected.
+	Model model.BaseChatModel
+
+	Capabilities
+
+	// InputPrice and OutputPrice are the prices per million prompt and output tokens, used to estimate the cost of a request.
+	InputPrice  float64
+	OutputPrice float64
+	// Latency is the expected latency of a request, used by Fastest.
+	Latency time.Duration
+}
+
+// EstimateCost returns the estimated cost of the request on the candidate.
+func (c *Candidate) EstimateCost(req *Request) float64 {
+	return (float64(req.PromptTokens)*c.InputPrice + float64(req.OutputTokens)*c.OutputPrice) / 1e6
+}
+
+// Request describes the requirements of a model call, it's derived from the input messages and the call options.
+type Request struct {
+	Messages []*schema.Message
+	// Tools are the tools of the call, either bound by WithTools or passed by model.WithTools.
+	Tools []*schema.ToolInfo
+	// Vision means the messages contain image or video input.
+	Vision bool
+	// PromptTokens is the estimated number of tokens of the messages.
+	PromptTokens int
+	// OutputTokens is the max number of output tokens, from model.WithMaxTokens or Config.DefaultOutputTokens.
+	OutputTokens int
+}
+
+// Policy selects a model among the candidates satisfying the request, which are in the order of registration and never empty.
+type Policy func(ctx context.Context, req *Request, candidates []*Candidate) (*Candidate, error)
+
+// InOrder selects the first candidate satisfying the request, i.e. candidates are registered in the order of priority.
+func InOrder() Policy {
+	return func(_ context.Context, _ *Request, candidates []*Candidate) (*Candidate, error) {
+		return candidates[0], nil
+	}
+}
+
+// Cheapest selects the candidate with the lowest estimated cost, the earlier registered one wins a tie.
+func Cheapest() Policy {
+	return func(_ context.Context, req *Request, candidates []*Candidate) (*Candidate, error) {
+		return minBy(candidates, func(c *Candidate) float64 { return c.EstimateCost(req) }), nil
+	}
+}
+
+// Fastest selects the candidate with the lowest expected latency, the earlier registered one wins a tie.
+func Fastest() Policy {
+	return func(_ context.Context, _ *Request, candidates []*Candidate) (*Candidate, error) {
+		return minBy(candidates, func(c *Candidate) float64 { return float64(c.Latency) }), nil
+	}
+}
+
+func minBy(candidates []*Candidate, score func(c *Candidate) float64) *Candidate {
+	sorted := append([]*Candidate{}, candidates...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return score(sorted[i]) < score(sorted[j])
+	})
+	return sorted[0]
+}
+
+// Config is the config of the router chat model.
+type Config struct {
+	// Candidates are the models to route to.
+	// Required.
+	Candidates []*Candidate
+	// Policy selects a model among the candidates satisfying the request.
+	// Optional. Defaults to InOrder.
+	Policy Policy
+	// TokenCounter estimates the number of tokens of a message.
+	// Optional. Defaults to a rough estimate of 4 characters per token.
+	TokenCounter func(msg *schema.Message) int
+	// DefaultOutputTokens is the number of output tokens reserved when model.WithMaxTokens is not set,
+	// used to check the context window and estimate the cost.
+	// Optional.
+	DefaultOutputTokens int
+	// MaxCost excludes the candidates whose estimated cost of the request exceeds it.
+	// Optional. Zero means unlimited.
+	MaxCost float64
+	// MaxLatency excludes the candidates whose expected latency exceeds it.
+	// Optional. Zero means unlimited.
+	MaxLatency time.Duration
+}
+
+type options struct {
+	policy Policy
+}
+
+// WithPolicy overrides the Policy of the router for a single call.
+func WithPolicy(policy Policy) model.Option {
+	return model.WrapImplSpecificOptFn(func(o *options) {
+		o.policy = policy
+	})
+}
+
+// NewChatModel creates a chat model that selects one of the candidates per request,
+// by the capabilities required by the request, the estimated prompt tokens, and a cost or latency policy.
+// It can replace a chat model node of an existing graph directly.
+// e.g.
+//
+//	cm, err := router.NewChatModel(ctx, &router.Config{
+//		Candidates: []*router.Candidate{
+//			{Name: "small", Model: small, Capabilities: router.Capabilities{ToolCalling: true, ContextWindow: 8000}, InputPrice: 0.1, OutputPrice: 0.4},
+//			{Name: "large", Model: large, Capabilities: router.Capabilities{Vision: true, ToolCalling: true, ContextWindow: 128000}, InputPrice: 2, OutputPrice: 8},
+//		},
+//		Policy: router.Cheapest(),
+//	})
+func NewChatModel(_ context.Context, config *Config) (model.ToolCallingChatModel, error) {
+	if config == nil {
+		return nil, errors.New("router config is nil")
+	}
+	if len(config.Candidates) == 0 {
+		return nil, errors.New("candidates are empty")
+	}
+	names := make(map[string]bool, len(config.Candidates))
+	for _, c := range config.Candidates {
+		if c == nil || c.Model == nil {
+			return nil, errors.New("candidate or its model is nil")
+		}
+		if names[c.Name] {
+			return nil, fmt.Errorf("duplicate candidate name: %s", c.Name)
+		}
+		names[c.Name] = true
+	}
+
+	r := &routerChatModel{
+		candidates:   config.Candidates,
+		policy:       config.Policy,
+		tokenCounter: config.TokenCounter,
+		outputTokens: config.DefaultOutputTokens,
+		maxCost:      config.MaxCost,
+		maxLatency:   config.MaxLatency,
+	}
+	if r.policy == nil {
+		r.policy = InOrder()
+	}
+	if r.tokenCounter == nil {
+		r.tokenCounter = estimateTokens
+	}
+	return r, nil
+}
+
+type routerChatModel struct {
+	candidates   []*Candidate
+	policy       Policy
+	tokenCounter func(msg *schema.Message) int
+	outputTokens int
+	maxCost      float64
+	maxLatency   time.Duration
+
+	tools []*schema.ToolInfo
+}
+
+func (r *routerChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	c, opts, err := r.route(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.Model.Generate(ctx, input, opts...)
+}
+
+func (r *routerChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	c, opts, err := r.route(ctx, input, opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.Model.Stream(ctx, input, opts...)
+}
+
+func (r *routerChatModel) WithTools(tools []*schema.ToolInfo) (model.ToolCallingChatModel, error) {
+	nr := *r
+	nr.tools = tools
+	return &nr, nil
+}
+
+func (r *routerChatModel) GetType() string {
+	return "Router"
+}
+
+func (r *routerChatModel) route(ctx context.Context, input []*schema.Message, opts []model.Option) (*Candidate, []model.Option, error) {
+	common := model.GetCommonOptions(&model.Options{Tools: r.tools}, opts...)
+	if len(r.tools) > 0 {
+		// the tools passed by option override the bound ones
+		opts = append([]model.Option{model.WithTools(r.tools)}, opts...)
+	}
+
+	req := &Request{
+		Messages:     input,
+		Tools:        common.Tools,
+		Vision:       hasVisionInput(input),
+		OutputTokens: r.outputTokens,
+	}
+	if common.MaxTokens != nil {
+		req.OutputTokens = *common.MaxTokens
+	}
+	for _, msg := range input {
+		req.PromptTokens += r.tokenCounter(msg)
+	}
+
+	var candidates []*Candidate
+	for _, c := range r.candidates {
+		if r.satisfies(c, req) {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("%w: vision=%v, tools=%d, prompt tokens=%d, output tokens=%d",
+			ErrNoCandidate, req.Vision, len(req.Tools), req.PromptTokens, req.OutputTokens)
+	}
+
+	policy := model.GetImplSpecificOptions(&options{policy: r.policy}, opts...).policy
+	c, err := policy(ctx, req, candidates)
+	if err != nil {
+		return nil, nil, err
+	}
+	if c == nil {
+		return nil, nil, errors.New("router policy selected no candidate")
+	}
+	return c, opts, nil
+}
+
+func (r *routerChatModel) satisfies(c *Candidate, req *Request) bool {
+	if req.Vision && !c.Vision {
+		return false
+	}
+	if len(req.Tools) > 0 && !c.ToolCalling {
+		return false
+	}
+	if c.ContextWindow > 0 && req.PromptTokens+req.OutputTokens > c.ContextWindow {
+		return false
+	}
+	if r.maxCost > 0 && c.EstimateCost(req) > r.maxCost {
+		return false
+	}
+	if r.maxLatency > 0 && c.Latency > r.maxLatency {
+		return false
+	}
+	return true
+}
+
+func hasVisionInput(input []*schema.Message) bool {
+	isVision := func(t schema.ChatMessagePartType) bool {
+		return t == schema.ChatMessagePartTypeImageURL || t == schema.ChatMessagePartTypeVideoURL
+	}
+	for _, msg := range input {
+		for _, part := range msg.UserInputMultiContent {
+			if isVision(part.Type) {
+				return true
+			}
+		}
+		for _, part := range msg.MultiContent {
+			if isVision(part.Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func estimateTokens(msg *schema.Message) int {
+	n := len(msg.Content) + len(msg.ReasoningContent)
+	for _, part := range msg.UserInputMultiContent {
+		n += len(part.Text)
+	}
+	for _, tc := range msg.ToolCalls {
+		n += len(tc.Function.Name) + len(tc.Function.Arguments)
+	}
+	// the overhead of the role and format
+	return n/4 + 4
+}
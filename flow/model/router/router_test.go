@@ -0,0 +1,158 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package router
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type namedModel struct {
+	name  string
+	tools [][]*schema.ToolInfo
+}
+
+func (n *namedModel) Generate(_ context.Context, _ []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	n.tools = append(n.tools, model.GetCommonOptions(nil, opts...).Tools)
+	return schema.AssistantMessage(n.name, nil), nil
+}
+
+func (n *namedModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := n.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func TestRouterChatModel(t *testing.T) {
+	ctx := context.Background()
+	small := &Candidate{
+		Name:         "small",
+		Model:        &namedModel{name: "small"},
+		Capabilities: Capabilities{ContextWindow: 100},
+		InputPrice:   1,
+		OutputPrice:  2,
+		Latency:      time.Second,
+	}
+	tool := &Candidate{
+		Name:         "tool",
+		Model:        &namedModel{name: "tool"},
+		Capabilities: Capabilities{ToolCalling: true, ContextWindow: 1000},
+		InputPrice:   3,
+		OutputPrice:  1,
+		Latency:      2 * time.Second,
+	}
+	large := &Candidate{
+		Name:         "large",
+		Model:        &namedModel{name: "large"},
+		Capabilities: Capabilities{Vision: true, ToolCalling: true},
+		InputPrice:   10,
+		OutputPrice:  10,
+		Latency:      500 * time.Millisecond,
+	}
+	cm, err := NewChatModel(ctx, &Config{Candidates: []*Candidate{small, tool, large}})
+	assert.NoError(t, err)
+
+	generate := func(cm model.BaseChatModel, input []*schema.Message, opts ...model.Option) string {
+		msg, err := cm.Generate(ctx, input, opts...)
+		assert.NoError(t, err)
+		return msg.Content
+	}
+
+	t.Run("capabilities", func(t *testing.T) {
+		short := []*schema.Message{schema.UserMessage("hi")}
+		assert.Equal(t, "small", generate(cm, short))
+
+		// context window
+		long := []*schema.Message{schema.UserMessage(strings.Repeat("a", 800))}
+		assert.Equal(t, "tool", generate(cm, long))
+		assert.Equal(t, "tool", generate(cm, short, model.WithMaxTokens(200)))
+
+		// tool calling
+		tools := []*schema.ToolInfo{{Name: "search"}}
+		assert.Equal(t, "tool", generate(cm, short, model.WithTools(tools)))
+		bound, err := cm.WithTools(tools)
+		assert.NoError(t, err)
+		assert.Equal(t, "tool", generate(bound, short))
+		assert.Equal(t, tools, tool.Model.(*namedModel).tools[len(tool.Model.(*namedModel).tools)-1])
+
+		// vision
+		image := []*schema.Message{{
+			Role: schema.User,
+			UserInputMultiContent: []schema.MessageInputPart{
+				{Type: schema.ChatMessagePartTypeText, Text: "what is it"},
+				{Type: schema.ChatMessagePartTypeImageURL, Image: &schema.MessageInputImage{}},
+			},
+		}}
+		assert.Equal(t, "large", generate(cm, image))
+	})
+
+	t.Run("policy", func(t *testing.T) {
+		long := []*schema.Message{schema.UserMessage(strings.Repeat("a", 400))}
+		// prompt tokens: 104, output tokens: 100, small is excluded by the context window
+		cheapest, err := NewChatModel(ctx, &Config{
+			Candidates:          []*Candidate{small, tool, large},
+			Policy:              Cheapest(),
+			DefaultOutputTokens: 100,
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "tool", generate(cheapest, long))
+		assert.Equal(t, "large", generate(cheapest, long, WithPolicy(Fastest())))
+		// prompt tokens: 4, small costs 4*1+100*2, tool costs 4*3+100*1
+		assert.Equal(t, "tool", generate(cheapest, []*schema.Message{schema.UserMessage("hi")}))
+		assert.Equal(t, "small", generate(cheapest, []*schema.Message{schema.UserMessage("hi")}, model.WithMaxTokens(1)))
+
+		limited, err := NewChatModel(ctx, &Config{
+			Candidates: []*Candidate{large, tool},
+			MaxLatency: time.Second,
+			MaxCost:    0.001,
+		})
+		assert.NoError(t, err)
+		_, err = limited.Generate(ctx, long)
+		assert.True(t, errors.Is(err, ErrNoCandidate))
+		assert.Equal(t, "large", generate(limited, []*schema.Message{schema.UserMessage("hi")}))
+	})
+
+	t.Run("graph", func(t *testing.T) {
+		r, err := compose.NewChain[[]*schema.Message, *schema.Message]().
+			AppendChatModel(cm).
+			Compile(ctx)
+		assert.NoError(t, err)
+		sr, err := r.Stream(ctx, []*schema.Message{schema.UserMessage("hi")}, compose.WithChatModelOption(model.WithTools([]*schema.ToolInfo{{Name: "search"}})))
+		assert.NoError(t, err)
+		msg, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "tool", msg.Content)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewChatModel(ctx, &Config{})
+		assert.Error(t, err)
+		_, err = NewChatModel(ctx, &Config{Candidates: []*Candidate{small, small}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,273 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package rag provides prebuilt retrieval-augmented generation flows.
+package rag
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/flow/retriever/multiquery"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	defaultQueryCount = 3
+	defaultRRFK       = 60
+
+	defaultQueryPrompt = `Generate {count} different versions of the following question to retrieve relevant documents from a vector store.
+Write them from different perspectives, to overcome the limitations of similarity search.
+Only provide the queries, one per line.
+
+question: {question}`
+	defaultAnswerPrompt = `Answer the question based on the following documents. If the documents don't contain the answer, just say that you don't know.
+
+{documents}`
+
+	keyQuestion  = "question"
+	keyDocuments = "documents"
+)
+
+// MultiQueryConfig is the config of the multi-query RAG flow.
+type MultiQueryConfig struct {
+	// QueryModel expands the question into multiple queries.
+	// Required unless QueryGenerator is set.
+	QueryModel model.BaseChatModel
+	// QueryPrompt is the FString prompt sent to QueryModel, with the variables {question} and {count}.
+	// The queries are expected one per line in the output.
+	// Optional. There's a default prompt.
+	QueryPrompt string
+	// QueryGenerator generates the queries by custom logic instead of QueryModel.
+	// Optional.
+	QueryGenerator func(ctx context.Context, question string, count int) ([]string, error)
+	// QueryCount is the number of queries generated from the question, excess queries are truncated.
+	// Optional. Defaults to 3.
+	QueryCount int
+	// ExcludeQuestion excludes the question itself from the retrieval, only the generated queries are used.
+	ExcludeQuestion bool
+
+	// Retriever retrieves the documents of each query, queries are retrieved in parallel.
+	// Required.
+	Retriever retriever.Retriever
+
+	// FusionWeights are the weights of the results of each query in the Reciprocal Rank Fusion,
+	// in the order of the question (unless ExcludeQuestion) and then the generated queries, e.g. []float64{2} favors the question.
+	// Optional. Missing weights default to 1.
+	FusionWeights []float64
+	// RRFK is the k constant of the Reciprocal Rank Fusion, a larger k flattens the differences between ranks.
+	// Optional. Defaults to 60.
+	RRFK int
+	// TopK is the max number of fused documents sent to the generation prompt.
+	// Optional. Zero means all the documents.
+	TopK int
+
+	// ChatModel generates the answer.
+	// Required.
+	ChatModel model.BaseChatModel
+	// ChatTemplate formats the generation prompt with the variables "question" and "documents",
+	// where "documents" is the string formatted by DocumentsFormatter.
+	// Optional. There's a default template answering based on the documents.
+	ChatTemplate prompt.ChatTemplate
+	// DocumentsFormatter formats the fused documents for ChatTemplate.
+	// Optional. By default, the documents are numbered and separated by blank lines.
+	DocumentsFormatter func(ctx context.Context, docs []*schema.Document) (string, error)
+}
+
+// NewMultiQuery creates a multi-query RAG flow, which expands the question into multiple queries,
+// retrieves the documents of each query in parallel, deduplicates and fuses them by Reciprocal Rank Fusion,
+// and answers the question based on the fused documents.
+// e.g.
+//
+//	r, err := rag.NewMultiQuery(ctx, &rag.MultiQueryConfig{
+//		QueryModel: queryModel,
+//		Retriever:  retriever,
+//		ChatModel:  chatModel,
+//		TopK:       5,
+//	})
+//	answer, err := r.Invoke(ctx, "how to build agent with eino")
+func NewMultiQuery(ctx context.Context, config *MultiQueryConfig) (compose.Runnable[string, *schema.Message], error) {
+	if config == nil {
+		return nil, errors.New("multi query rag config is nil")
+	}
+	if config.QueryModel == nil && config.QueryGenerator == nil {
+		return nil, errors.New("at least one of QueryModel and QueryGenerator must not be empty")
+	}
+	if config.Retriever == nil {
+		return nil, errors.New("Retriever is required")
+	}
+	if config.ChatModel == nil {
+		return nil, errors.New("ChatModel is required")
+	}
+
+	queryCount := config.QueryCount
+	if queryCount <= 0 {
+		queryCount = defaultQueryCount
+	}
+
+	generate := config.QueryGenerator
+	if generate == nil {
+		var err error
+		generate, err = newQueryGenerator(ctx, config.QueryModel, config.QueryPrompt)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	maxQueries := queryCount
+	if !config.ExcludeQuestion {
+		maxQueries++
+	}
+	mqRetriever, err := multiquery.NewRetriever(ctx, &multiquery.Config{
+		RewriteHandler: func(ctx context.Context, question string) ([]string, error) {
+			queries, err := generate(ctx, question, queryCount)
+			if err != nil {
+				return nil, err
+			}
+			if len(queries) > queryCount {
+				queries = queries[:queryCount]
+			}
+			if config.ExcludeQuestion {
+				return queries, nil
+			}
+			return append([]string{question}, queries...), nil
+		},
+		MaxQueriesNum: maxQueries,
+		OrigRetriever: config.Retriever,
+		FusionFunc:    NewRRFFusion(config.RRFK, config.FusionWeights, config.TopK),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	formatter := config.DocumentsFormatter
+	if formatter == nil {
+		formatter = formatDocuments
+	}
+	tpl := config.ChatTemplate
+	if tpl == nil {
+		tpl = prompt.FromMessages(schema.FString,
+			schema.SystemMessage(defaultAnswerPrompt),
+			schema.UserMessage("{"+keyQuestion+"}"))
+	}
+
+	chain := compose.NewChain[string, *schema.Message]()
+	chain.
+		AppendParallel(compose.NewParallel().
+			AddLambda(keyQuestion, compose.InvokableLambda(func(_ context.Context, question string) (string, error) {
+				return question, nil
+			})).
+			AddRetriever(keyDocuments, mqRetriever, compose.WithNodeName("MultiQueryRetriever"))).
+		AppendLambda(compose.InvokableLambda(func(ctx context.Context, input map[string]any) (map[string]any, error) {
+			docs, _ := input[keyDocuments].([]*schema.Document)
+			documents, err := formatter(ctx, docs)
+			if err != nil {
+				return nil, err
+			}
+			return map[string]any{
+				keyQuestion:  input[keyQuestion],
+				keyDocuments: documents,
+			}, nil
+		}), compose.WithNodeName("DocumentsFormatter")).
+		AppendChatTemplate(tpl).
+		AppendChatModel(config.ChatModel)
+
+	return chain.Compile(ctx, compose.WithGraphName("MultiQueryRAG"))
+}
+
+func newQueryGenerator(ctx context.Context, m model.BaseChatModel, queryPrompt string) (func(ctx context.Context, question string, count int) ([]string, error), error) {
+	if queryPrompt == "" {
+		queryPrompt = defaultQueryPrompt
+	}
+	r, err := compose.NewChain[map[string]any, *schema.Message]().
+		AppendChatTemplate(prompt.FromMessages(schema.FString, schema.UserMessage(queryPrompt))).
+		AppendChatModel(m).
+		Compile(ctx, compose.WithGraphName("QueryExpansion"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, question string, count int) ([]string, error) {
+		msg, err := r.Invoke(ctx, map[string]any{keyQuestion: question, "count": count})
+		if err != nil {
+			return nil, err
+		}
+		var queries []string
+		for _, line := range strings.Split(msg.Content, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				queries = append(queries, line)
+			}
+		}
+		return queries, nil
+	}, nil
+}
+
+// NewRRFFusion creates a fusion function for multiquery.Config.FusionFunc, which deduplicates the documents by ID
+// and ranks them by the weighted Reciprocal Rank Fusion: score(doc) = sum(weight[i] / (k + rank[i])), where rank[i] starts from 1.
+// k defaults to 60 when it's not positive, missing weights default to 1, and topK limits the number of documents returned if it's positive.
+func NewRRFFusion(k int, weights []float64, topK int) func(ctx context.Context, docs [][]*schema.Document) ([]*schema.Document, error) {
+	if k <= 0 {
+		k = defaultRRFK
+	}
+	return func(_ context.Context, docs [][]*schema.Document) ([]*schema.Document, error) {
+		scores := make(map[string]float64)
+		var fused []*schema.Document
+		for i := range docs {
+			weight := 1.0
+			if i < len(weights) {
+				weight = weights[i]
+			}
+			seen := make(map[string]bool, len(docs[i]))
+			for rank, doc := range docs[i] {
+				if seen[doc.ID] {
+					continue
+				}
+				seen[doc.ID] = true
+				if _, ok := scores[doc.ID]; !ok {
+					fused = append(fused, doc)
+				}
+				scores[doc.ID] += weight / float64(k+rank+1)
+			}
+		}
+
+		// stable, so that documents of the same score keep the order in which they are first retrieved
+		sort.SliceStable(fused, func(i, j int) bool {
+			return scores[fused[i].ID] > scores[fused[j].ID]
+		})
+		if topK > 0 && len(fused) > topK {
+			fused = fused[:topK]
+		}
+		return fused, nil
+	}
+}
+
+func formatDocuments(_ context.Context, docs []*schema.Document) (string, error) {
+	sb := strings.Builder{}
+	for i, doc := range docs {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		sb.WriteString(fmt.Sprintf("[%d] %s", i+1, doc.Content))
+	}
+	return sb.String(), nil
+}
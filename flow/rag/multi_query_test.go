@@ -0,0 +1,164 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rag
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+type digitRetriever struct{}
+
+func (d *digitRetriever) Retrieve(_ context.Context, query string, _ ...retriever.Option) ([]*schema.Document, error) {
+	var docs []*schema.Document
+	for _, c := range query {
+		if c >= '0' && c <= '9' {
+			docs = append(docs, &schema.Document{ID: string(c), Content: "doc" + string(c)})
+		}
+	}
+	return docs, nil
+}
+
+type fakeModel struct {
+	content string
+	inputs  [][]*schema.Message
+}
+
+func (f *fakeModel) Generate(_ context.Context, input []*schema.Message, _ ...model.Option) (*schema.Message, error) {
+	f.inputs = append(f.inputs, input)
+	return schema.AssistantMessage(f.content, nil), nil
+}
+
+func (f *fakeModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	msg, err := f.Generate(ctx, input, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return schema.StreamReaderFromArray([]*schema.Message{msg}), nil
+}
+
+func TestMultiQuery(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("query model", func(t *testing.T) {
+		queryModel := &fakeModel{content: "2 3\n\n  3 4  \n4\n5"}
+		chatModel := &fakeModel{content: "answer"}
+		r, err := NewMultiQuery(ctx, &MultiQueryConfig{
+			QueryModel: queryModel,
+			Retriever:  &digitRetriever{},
+			TopK:       3,
+			ChatModel:  chatModel,
+		})
+		assert.NoError(t, err)
+
+		out, err := r.Invoke(ctx, "question 1 2")
+		assert.NoError(t, err)
+		assert.Equal(t, "answer", out.Content)
+
+		assert.Len(t, queryModel.inputs, 1)
+		assert.Contains(t, queryModel.inputs[0][0].Content, "Generate 3 different versions")
+		assert.Contains(t, queryModel.inputs[0][0].Content, "question: question 1 2")
+
+		// queries: "question 1 2", "2 3", "3 4", "4", the 4th generated query is truncated.
+		// doc2, doc3 and doc4 are each ranked first once and second once, so they tie and keep the retrieved order, beating doc1.
+		assert.Len(t, chatModel.inputs, 1)
+		assert.Equal(t, "[1] doc2\n\n[2] doc3\n\n[3] doc4", strings.SplitN(chatModel.inputs[0][0].Content, "\n\n", 2)[1])
+		assert.Equal(t, "question 1 2", chatModel.inputs[0][1].Content)
+	})
+
+	t.Run("fusion weights", func(t *testing.T) {
+		chatModel := &fakeModel{content: "answer"}
+		r, err := NewMultiQuery(ctx, &MultiQueryConfig{
+			QueryGenerator: func(_ context.Context, question string, count int) ([]string, error) {
+				assert.Equal(t, 2, count)
+				return []string{"3", "3 1"}, nil
+			},
+			QueryCount:      2,
+			ExcludeQuestion: true,
+			Retriever:       &digitRetriever{},
+			FusionWeights:   []float64{0.1},
+			ChatModel:       chatModel,
+			DocumentsFormatter: func(_ context.Context, docs []*schema.Document) (string, error) {
+				ids := make([]string, 0, len(docs))
+				for _, doc := range docs {
+					ids = append(ids, doc.ID)
+				}
+				return strings.Join(ids, ","), nil
+			},
+		})
+		assert.NoError(t, err)
+
+		sr, err := r.Stream(ctx, "1")
+		assert.NoError(t, err)
+		out, err := schema.ConcatMessageStream(sr)
+		assert.NoError(t, err)
+		assert.Equal(t, "answer", out.Content)
+		assert.True(t, strings.HasSuffix(chatModel.inputs[0][0].Content, "\n\n3,1"))
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewMultiQuery(ctx, nil)
+		assert.Error(t, err)
+		_, err = NewMultiQuery(ctx, &MultiQueryConfig{Retriever: &digitRetriever{}, ChatModel: &fakeModel{}})
+		assert.Error(t, err)
+		_, err = NewMultiQuery(ctx, &MultiQueryConfig{QueryModel: &fakeModel{}, ChatModel: &fakeModel{}})
+		assert.Error(t, err)
+		_, err = NewMultiQuery(ctx, &MultiQueryConfig{QueryModel: &fakeModel{}, Retriever: &digitRetriever{}})
+		assert.Error(t, err)
+	})
+}
+
+func TestRRFFusion(t *testing.T) {
+	docs := func(ids ...string) []*schema.Document {
+		ret := make([]*schema.Document, 0, len(ids))
+		for _, id := range ids {
+			ret = append(ret, &schema.Document{ID: id})
+		}
+		return ret
+	}
+	ids := func(docs []*schema.Document) []string {
+		ret := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			ret = append(ret, doc.ID)
+		}
+		return ret
+	}
+
+	fused, err := NewRRFFusion(0, nil, 0)(context.Background(), [][]*schema.Document{
+		docs("a", "b", "c"),
+		docs("c", "b", "b"),
+	})
+	assert.NoError(t, err)
+	// b: 1/62+1/62, c: 1/61+1/63, the duplicated b of the second list is ignored
+	assert.Equal(t, []string{"c", "b", "a"}, ids(fused))
+
+	fused, err = NewRRFFusion(1, []float64{3}, 2)(context.Background(), [][]*schema.Document{
+		docs("a", "b"),
+		docs("b", "c"),
+		docs("c"),
+	})
+	assert.NoError(t, err)
+	// a: 3/2, b: 3/3+1/2, c: 1/3+1/2, a and b are tied and keep the retrieved order
+	assert.Equal(t, []string{"a", "b"}, ids(fused))
+}
@@ -0,0 +1,353 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compression
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/internal/safe"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// MetaKeyOriginalID is the metadata key of the ID of the original document a compressed document is extracted from.
+	MetaKeyOriginalID = "_original_id"
+	// MetaKeyOriginalDocument is the metadata key of the original *schema.Document.
+	MetaKeyOriginalDocument = "_original_document"
+	// MetaKeySpans is the metadata key of the byte offsets [start, end) of the extracted passages in the original content, as [][2]int.
+	// Passages rephrased by the chat model can't be located, so they have no spans.
+	MetaKeySpans = "_spans"
+)
+
+const (
+	// NoOutput is the reply of the chat model when nothing in the document is relevant to the query.
+	NoOutput = "NO_OUTPUT"
+
+	defaultSimilarityThreshold = 0.75
+	defaultMaxConcurrency      = 4
+)
+
+var defaultExtractPrompt = `Given the question and the context below, extract verbatim any parts of the context that are relevant to answering the question.
+Don't rephrase or add anything. If none of the context is relevant, reply ` + NoOutput + ` only.`
+
+var defaultSeparators = []string{"\n", ". ", "? ", "! ", "。", "？", "！"}
+
+// Config is the config of the contextual compression retriever.
+type Config struct {
+	// Retriever retrieves the documents to compress. Required.
+	Retriever retriever.Retriever
+
+	// Compress with a chat model, or an embedder, exactly one of them is required.
+	// 1. ChatModel extracts the relevant parts of each document, or replies NoOutput to drop the document.
+	ChatModel model.BaseChatModel
+	//	Template formats the messages to the chat model with the variables "query" and "context", we provide a default one so you can leave it blank.
+	Template prompt.ChatTemplate
+	// 2. Embedding embeds the query and the passages of the documents, passages not similar enough to the query are dropped.
+	Embedding embedding.Embedder
+	//	SimilarityThreshold is the min cosine similarity of a kept passage to the query, 0.75 by default.
+	SimilarityThreshold float64
+	//	Separators split the documents into passages, newlines and the common sentence endings by default.
+	Separators []string
+
+	// TokenBudget limits the estimated tokens of all the compressed documents if it's positive.
+	// The documents are kept in the retrieved order, and the ones exceeding the remaining budget are dropped.
+	TokenBudget int
+	// TokenCounter estimates the tokens of a text. Optional, defaults to a rough estimate of 4 characters per token.
+	TokenCounter func(text string) int
+	// MaxConcurrency is the max number of documents compressed by the chat model at the same time, 4 by default.
+	MaxConcurrency int
+}
+
+// NewRetriever creates a contextual compression retriever, which retrieves with the Retriever,
+// then keeps only the parts of the documents relevant to the query, so that more documents fit in the context of the model.
+// A compressed document keeps the ID and the metadata of its original document,
+// with MetaKeyOriginalID, MetaKeyOriginalDocument and MetaKeySpans pointing to where it comes from.
+// Documents without any relevant part are dropped.
+// eg.
+//
+//	r, err := compression.NewRetriever(ctx, &compression.Config{
+//		Retriever:   vectorRetriever,
+//		ChatModel:   chatModel,
+//		TokenBudget: 2000,
+//	})
+//	docs, err := r.Retrieve(ctx, "how to build agent with eino")
+func NewRetriever(ctx context.Context, config *Config) (retriever.Retriever, error) {
+	if config.Retriever == nil {
+		return nil, errors.New("retriever is required")
+	}
+	if (config.ChatModel == nil) == (config.Embedding == nil) {
+		return nil, errors.New("exactly one of chat model and embedding is required")
+	}
+
+	c := &compressionRetriever{
+		retriever:    config.Retriever,
+		chatModel:    config.ChatModel,
+		template:     config.Template,
+		embedding:    config.Embedding,
+		threshold:    config.SimilarityThreshold,
+		separators:   config.Separators,
+		tokenBudget:  config.TokenBudget,
+		tokenCounter: config.TokenCounter,
+		concurrency:  config.MaxConcurrency,
+	}
+	if c.template == nil {
+		c.template = prompt.FromMessages(schema.FString,
+			schema.SystemMessage(defaultExtractPrompt),
+			schema.UserMessage("Question: {query}\n\nContext:\n{context}"))
+	}
+	if c.threshold == 0 {
+		c.threshold = defaultSimilarityThreshold
+	}
+	if len(c.separators) == 0 {
+		c.separators = defaultSeparators
+	}
+	if c.tokenCounter == nil {
+		c.tokenCounter = estimateTokens
+	}
+	if c.concurrency <= 0 {
+		c.concurrency = defaultMaxConcurrency
+	}
+	return c, nil
+}
+
+type compressionRetriever struct {
+	retriever retriever.Retriever
+
+	chatModel model.BaseChatModel
+	template  prompt.ChatTemplate
+
+	embedding  embedding.Embedder
+	threshold  float64
+	separators []string
+
+	tokenBudget  int
+	tokenCounter func(text string) int
+	concurrency  int
+}
+
+// Retrieve retrieves documents and compresses them.
+func (c *compressionRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	docs, err := c.retriever.Retrieve(ctx, query, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return docs, nil
+	}
+
+	var compressed []*schema.Document
+	if c.chatModel != nil {
+		compressed, err = c.extract(ctx, query, docs)
+	} else {
+		compressed, err = c.filter(ctx, query, docs)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*schema.Document
+	remaining := c.tokenBudget
+	for _, doc := range compressed {
+		if doc == nil {
+			continue
+		}
+		if c.tokenBudget > 0 {
+			tokens := c.tokenCounter(doc.Content)
+			if tokens > remaining {
+				continue
+			}
+			remaining -= tokens
+		}
+		ret = append(ret, doc)
+	}
+	return ret, nil
+}
+
+// GetType returns the type of the retriever (ContextualCompression).
+func (c *compressionRetriever) GetType() string {
+	return "ContextualCompression"
+}
+
+// extract asks the chat model to extract the relevant parts of each document concurrently, the dropped documents are nil.
+func (c *compressionRetriever) extract(ctx context.Context, query string, docs []*schema.Document) ([]*schema.Document, error) {
+	ret := make([]*schema.Document, len(docs))
+	errs := make([]error, len(docs))
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	for i := range docs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				if e := recover(); e != nil {
+					errs[i] = safe.NewPanicErr(e, debug.Stack())
+				}
+				<-sem
+				wg.Done()
+			}()
+			ret[i], errs[i] = c.extractOne(ctx, query, docs[i])
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
+func (c *compressionRetriever) extractOne(ctx context.Context, query string, doc *schema.Document) (*schema.Document, error) {
+	messages, err := c.template.Format(ctx, map[string]any{"query": query, "context": doc.Content})
+	if err != nil {
+		return nil, fmt.Errorf("format extract prompt fail: %w", err)
+	}
+	msg, err := c.chatModel.Generate(ctx, messages)
+	if err != nil {
+		return nil, fmt.Errorf("extract relevant content of document[%s] fail: %w", doc.ID, err)
+	}
+
+	content := strings.TrimSpace(msg.Content)
+	if content == "" || content == NoOutput {
+		return nil, nil
+	}
+	var spans [][2]int
+	if start := strings.Index(doc.Content, content); start >= 0 {
+		spans = [][2]int{{start, start + len(content)}}
+	}
+	return newCompressed(doc, content, spans), nil
+}
+
+// filter keeps the passages of the documents similar enough to the query, the dropped documents are nil.
+func (c *compressionRetriever) filter(ctx context.Context, query string, docs []*schema.Document) ([]*schema.Document, error) {
+	// embed the query and all the passages in one call
+	texts := []string{query}
+	passages := make([][][2]int, len(docs))
+	for i, doc := range docs {
+		passages[i] = splitPassages(doc.Content, c.separators)
+		for _, p := range passages[i] {
+			texts = append(texts, doc.Content[p[0]:p[1]])
+		}
+	}
+	vectors, err := c.embedding.EmbedStrings(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embed passages fail: %w", err)
+	}
+	if len(vectors) != len(texts) {
+		return nil, fmt.Errorf("unexpected number of vectors, expected: %d, actual: %d", len(texts), len(vectors))
+	}
+
+	queryVector := vectors[0]
+	vectors = vectors[1:]
+	ret := make([]*schema.Document, len(docs))
+	for i, doc := range docs {
+		var kept [][2]int
+		var contents []string
+		for j, p := range passages[i] {
+			if cosineSimilarity(queryVector, vectors[j]) >= c.threshold {
+				kept = append(kept, p)
+				contents = append(contents, doc.Content[p[0]:p[1]])
+			}
+		}
+		vectors = vectors[len(passages[i]):]
+		if len(kept) > 0 {
+			ret[i] = newCompressed(doc, strings.Join(contents, "\n"), kept)
+		}
+	}
+	return ret, nil
+}
+
+func newCompressed(orig *schema.Document, content string, spans [][2]int) *schema.Document {
+	metaData := make(map[string]any, len(orig.MetaData)+3)
+	for k, v := range orig.MetaData {
+		metaData[k] = v
+	}
+	metaData[MetaKeyOriginalID] = orig.ID
+	metaData[MetaKeyOriginalDocument] = orig
+	metaData[MetaKeySpans] = spans
+	return &schema.Document{ID: orig.ID, Content: content, MetaData: metaData}
+}
+
+// splitPassages splits the content at the separators, returns the byte offsets of the non-blank passages with the surrounding whitespaces trimmed.
+func splitPassages(content string, separators []string) [][2]int {
+	var ret [][2]int
+	appendPassage := func(start, end int) {
+		for start < end && isSpace(content[start]) {
+			start++
+		}
+		for end > start && isSpace(content[end-1]) {
+			end--
+		}
+		if start < end {
+			ret = append(ret, [2]int{start, end})
+		}
+	}
+
+	start := 0
+	for i := 0; i < len(content); {
+		matched := 0
+		for _, sep := range separators {
+			if sep != "" && strings.HasPrefix(content[i:], sep) {
+				matched = len(sep)
+				break
+			}
+		}
+		if matched == 0 {
+			_, size := utf8.DecodeRuneInString(content[i:])
+			i += size
+			continue
+		}
+		i += matched
+		appendPassage(start, i)
+		start = i
+	}
+	appendPassage(start, len(content))
+	return ret
+}
+
+func isSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
@@ -0,0 +1,146 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package compression
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/embedding"
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+type mockRetriever struct {
+	docs []*schema.Document
+}
+
+func (m *mockRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	return m.docs, nil
+}
+
+// extractModel extracts the lines of the context containing "eino".
+type extractModel struct{}
+
+func (e *extractModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	content := input[len(input)-1].Content
+	context := content[strings.Index(content, "Context:\n")+len("Context:\n"):]
+	var lines []string
+	for _, line := range strings.Split(context, "\n") {
+		if strings.Contains(line, "eino") {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) == 0 {
+		return schema.AssistantMessage(NoOutput, nil), nil
+	}
+	return schema.AssistantMessage(strings.Join(lines, "\n"), nil), nil
+}
+
+func (e *extractModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	panic("implement me")
+}
+
+// wordEmbedder embeds whether the text mentions eino and go.
+type wordEmbedder struct{}
+
+func (w *wordEmbedder) EmbedStrings(ctx context.Context, texts []string, opts ...embedding.Option) ([][]float64, error) {
+	ret := make([][]float64, len(texts))
+	for i, text := range texts {
+		v := []float64{0, 0, 0.1}
+		if strings.Contains(text, "eino") {
+			v[0] = 1
+		}
+		if strings.Contains(text, "go") {
+			v[1] = 1
+		}
+		ret[i] = v
+	}
+	return ret, nil
+}
+
+func TestCompressionRetriever(t *testing.T) {
+	ctx := context.Background()
+	docs := []*schema.Document{
+		{ID: "1", Content: "eino is a framework.\nIt is written in go.\nThe weather is fine.", MetaData: map[string]any{"source": "a"}},
+		{ID: "2", Content: "Nothing relevant here."},
+		{ID: "3", Content: "Build agents with eino."},
+	}
+	r := &mockRetriever{docs: docs}
+
+	t.Run("chat model", func(t *testing.T) {
+		cr, err := NewRetriever(ctx, &Config{Retriever: r, ChatModel: &extractModel{}})
+		assert.NoError(t, err)
+		ret, err := cr.Retrieve(ctx, "what is eino")
+		assert.NoError(t, err)
+		assert.Len(t, ret, 2)
+
+		assert.Equal(t, "1", ret[0].ID)
+		assert.Equal(t, "eino is a framework.", ret[0].Content)
+		assert.Equal(t, "a", ret[0].MetaData["source"])
+		assert.Equal(t, "1", ret[0].MetaData[MetaKeyOriginalID])
+		assert.Same(t, docs[0], ret[0].MetaData[MetaKeyOriginalDocument])
+		assert.Equal(t, [][2]int{{0, 20}}, ret[0].MetaData[MetaKeySpans])
+		assert.Equal(t, "Build agents with eino.", ret[1].Content)
+
+		// the original documents are not modified
+		assert.Equal(t, map[string]any{"source": "a"}, docs[0].MetaData)
+	})
+
+	t.Run("embedding", func(t *testing.T) {
+		cr, err := NewRetriever(ctx, &Config{Retriever: r, Embedding: &wordEmbedder{}, SimilarityThreshold: 0.5})
+		assert.NoError(t, err)
+		ret, err := cr.Retrieve(ctx, "eino")
+		assert.NoError(t, err)
+		assert.Len(t, ret, 2)
+		assert.Equal(t, "eino is a framework.", ret[0].Content)
+		assert.Equal(t, [][2]int{{0, 20}}, ret[0].MetaData[MetaKeySpans])
+
+		ret, err = cr.Retrieve(ctx, "eino go")
+		assert.NoError(t, err)
+		assert.Equal(t, "eino is a framework.\nIt is written in go.", ret[0].Content)
+		assert.Equal(t, [][2]int{{0, 20}, {21, 41}}, ret[0].MetaData[MetaKeySpans])
+	})
+
+	t.Run("token budget", func(t *testing.T) {
+		cr, err := NewRetriever(ctx, &Config{
+			Retriever:    r,
+			ChatModel:    &extractModel{},
+			TokenBudget:  22,
+			TokenCounter: func(text string) int { return len(text) },
+		})
+		assert.NoError(t, err)
+		ret, err := cr.Retrieve(ctx, "eino")
+		assert.NoError(t, err)
+		// the second one exceeds the remaining budget
+		assert.Len(t, ret, 1)
+		assert.Equal(t, "1", ret[0].ID)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewRetriever(ctx, &Config{Retriever: r})
+		assert.Error(t, err)
+		_, err = NewRetriever(ctx, &Config{Retriever: r, ChatModel: &extractModel{}, Embedding: &wordEmbedder{}})
+		assert.Error(t, err)
+		_, err = NewRetriever(ctx, &Config{ChatModel: &extractModel{}})
+		assert.Error(t, err)
+	})
+}
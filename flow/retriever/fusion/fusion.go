@@ -0,0 +1,226 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fusion
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/flow/retriever/utils"
+	"github.com/cloudwego/eino/schema"
+)
+
+const defaultRRFK = 60
+
+// Strategy is the way to merge the rankings of the retrievers.
+type Strategy string
+
+const (
+	// ReciprocalRank ranks the documents by the weighted Reciprocal Rank Fusion:
+	// score(doc) = sum(weight[i] / (k + rank[i])), where rank[i] starts from 1.
+	// It only depends on the ranks, so it suits retrievers whose scores are not comparable, e.g. BM25 and cosine similarity.
+	ReciprocalRank Strategy = "rrf"
+	// WeightedScore ranks the documents by the weighted sum of their scores, min-max normalized to [0, 1] within the results of each retriever:
+	// score(doc) = sum(weight[i] * normalized_score[i]). A document not retrieved by a retriever gets 0 from it.
+	WeightedScore Strategy = "weighted_score"
+)
+
+// Config is the config for fusion retriever.
+type Config struct {
+	// Retrievers are the retrievers to fuse, e.g. a BM25 retriever and a vector retriever. Required.
+	Retrievers []retriever.Retriever
+	// Weights are the weights of the retrievers, in the order of Retrievers. Missing weights default to 1.
+	Weights []float64
+	// Strategy is the way to merge the rankings, ReciprocalRank by default.
+	Strategy Strategy
+	// RRFK is the k constant of ReciprocalRank, a larger k flattens the differences between ranks. 60 by default.
+	RRFK int
+	// TopK limits the number of the fused documents if it's positive.
+	// Note that retriever.WithTopK passed to Retrieve limits the documents of each retriever instead.
+	TopK int
+	// DedupKey returns the key identifying the same document retrieved by different retrievers.
+	// Optional. By default, it's the ID of the document, or the SHA-256 of the content if the ID is empty.
+	DedupKey func(doc *schema.Document) string
+}
+
+// NewRetriever creates a fusion retriever, which retrieves with all the retrievers in parallel,
+// deduplicates the documents and merges the rankings into one, e.g. for hybrid BM25 and vector search.
+// The documents returned carry the fused scores, which can be got by Document.Score.
+// eg.
+//
+//	hybrid, err := fusion.NewRetriever(ctx, &fusion.Config{
+//		Retrievers: []retriever.Retriever{bm25Retriever, vectorRetriever},
+//		Weights:    []float64{0.4, 0.6},
+//		Strategy:   fusion.WeightedScore,
+//		TopK:       10,
+//	})
+//	docs, err := hybrid.Retrieve(ctx, "how to build agent with eino")
+func NewRetriever(ctx context.Context, config *Config) (retriever.Retriever, error) {
+	if len(config.Retrievers) == 0 {
+		return nil, fmt.Errorf("retrievers is empty")
+	}
+
+	strategy := config.Strategy
+	if strategy == "" {
+		strategy = ReciprocalRank
+	}
+	if strategy != ReciprocalRank && strategy != WeightedScore {
+		return nil, fmt.Errorf("unknown fusion strategy: %s", strategy)
+	}
+
+	weights := make([]float64, len(config.Retrievers))
+	for i := range weights {
+		weights[i] = 1
+		if i < len(config.Weights) {
+			weights[i] = config.Weights[i]
+		}
+	}
+
+	k := config.RRFK
+	if k <= 0 {
+		k = defaultRRFK
+	}
+
+	dedupKey := config.DedupKey
+	if dedupKey == nil {
+		dedupKey = defaultDedupKey
+	}
+
+	return &fusionRetriever{
+		retrievers: config.Retrievers,
+		weights:    weights,
+		strategy:   strategy,
+		k:          k,
+		topK:       config.TopK,
+		dedupKey:   dedupKey,
+	}, nil
+}
+
+type fusionRetriever struct {
+	retrievers []retriever.Retriever
+	weights    []float64
+	strategy   Strategy
+	k          int
+	topK       int
+	dedupKey   func(doc *schema.Document) string
+}
+
+// Retrieve retrieves documents from the fusion retriever.
+func (f *fusionRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	tasks := make([]*utils.RetrieveTask, len(f.retrievers))
+	for i := range f.retrievers {
+		tasks[i] = &utils.RetrieveTask{
+			Name:            fmt.Sprintf("%d", i),
+			Retriever:       f.retrievers[i],
+			Query:           query,
+			RetrieveOptions: opts,
+		}
+	}
+	utils.ConcurrentRetrieveWithCallback(ctx, tasks)
+
+	scores := make(map[string]float64)
+	var fused []*schema.Document
+	var keys []string
+	for i := range tasks {
+		if tasks[i].Err != nil {
+			return nil, tasks[i].Err
+		}
+
+		var normalize func(score float64) float64
+		if f.strategy == WeightedScore {
+			normalize = minMaxNormalizer(tasks[i].Result)
+		}
+
+		seen := make(map[string]bool, len(tasks[i].Result))
+		rank := 0
+		for _, doc := range tasks[i].Result {
+			key := f.dedupKey(doc)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			rank++
+
+			if _, ok := scores[key]; !ok {
+				fused = append(fused, doc)
+				keys = append(keys, key)
+			}
+			if f.strategy == WeightedScore {
+				scores[key] += f.weights[i] * normalize(doc.Score())
+			} else {
+				scores[key] += f.weights[i] / float64(f.k+rank)
+			}
+		}
+	}
+
+	ret := make([]*schema.Document, len(fused))
+	for i := range fused {
+		// copy the document, as the score is set to the metadata shared with the result of the retriever
+		doc := *fused[i]
+		doc.MetaData = make(map[string]any, len(fused[i].MetaData)+1)
+		for mk, mv := range fused[i].MetaData {
+			doc.MetaData[mk] = mv
+		}
+		ret[i] = doc.WithScore(scores[keys[i]])
+	}
+
+	// stable, so that documents of the same score keep the order in which they are first retrieved
+	sort.SliceStable(ret, func(i, j int) bool {
+		return ret[i].Score() > ret[j].Score()
+	})
+	if f.topK > 0 && len(ret) > f.topK {
+		ret = ret[:f.topK]
+	}
+	return ret, nil
+}
+
+// GetType returns the type of the retriever (Fusion).
+func (f *fusionRetriever) GetType() string { return "Fusion" }
+
+func minMaxNormalizer(docs []*schema.Document) func(score float64) float64 {
+	if len(docs) == 0 {
+		return func(float64) float64 { return 0 }
+	}
+	lo, hi := docs[0].Score(), docs[0].Score()
+	for _, doc := range docs[1:] {
+		s := doc.Score()
+		if s < lo {
+			lo = s
+		}
+		if s > hi {
+			hi = s
+		}
+	}
+	if hi == lo {
+		return func(float64) float64 { return 1 }
+	}
+	return func(score float64) float64 {
+		return (score - lo) / (hi - lo)
+	}
+}
+
+func defaultDedupKey(doc *schema.Document) string {
+	if doc.ID != "" {
+		return doc.ID
+	}
+	sum := sha256.Sum256([]byte(doc.Content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package fusion
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/components/retriever"
+	"github.com/cloudwego/eino/schema"
+)
+
+type mockRetriever struct {
+	docs []*schema.Document
+	err  error
+}
+
+func (m *mockRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	return m.docs, m.err
+}
+
+func (m *mockRetriever) GetType() string {
+	return "Mock"
+}
+
+func doc(id string, score float64) *schema.Document {
+	return (&schema.Document{ID: id, Content: "content " + id}).WithScore(score)
+}
+
+func ids(docs []*schema.Document) []string {
+	ret := make([]string, len(docs))
+	for i := range docs {
+		ret[i] = docs[i].ID
+	}
+	return ret
+}
+
+func TestFusionRetriever(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("rrf", func(t *testing.T) {
+		bm25 := &mockRetriever{docs: []*schema.Document{doc("1", 12), doc("2", 8), doc("3", 1)}}
+		vector := &mockRetriever{docs: []*schema.Document{doc("3", 0.9), doc("2", 0.8), doc("4", 0.1)}}
+
+		r, err := NewRetriever(ctx, &Config{Retrievers: []retriever.Retriever{bm25, vector}})
+		assert.NoError(t, err)
+		docs, err := r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		// 3: 1/63+1/61, 2: 1/62+1/62, 1: 1/61, 4: 1/63
+		assert.Equal(t, []string{"3", "2", "1", "4"}, ids(docs))
+		assert.InDelta(t, 1.0/63+1.0/61, docs[0].Score(), 1e-9)
+
+		// the documents of the retrievers are not modified
+		assert.Equal(t, 0.9, vector.docs[0].Score())
+	})
+
+	t.Run("weighted rrf with top k", func(t *testing.T) {
+		bm25 := &mockRetriever{docs: []*schema.Document{doc("1", 12), doc("2", 8)}}
+		vector := &mockRetriever{docs: []*schema.Document{doc("2", 0.9), doc("1", 0.8)}}
+
+		r, err := NewRetriever(ctx, &Config{
+			Retrievers: []retriever.Retriever{bm25, vector},
+			Weights:    []float64{1, 2},
+			RRFK:       1,
+			TopK:       1,
+		})
+		assert.NoError(t, err)
+		docs, err := r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"2"}, ids(docs))
+		assert.InDelta(t, 1.0/3+2.0/2, docs[0].Score(), 1e-9)
+	})
+
+	t.Run("weighted score", func(t *testing.T) {
+		bm25 := &mockRetriever{docs: []*schema.Document{doc("1", 20), doc("2", 10), doc("3", 0)}}
+		vector := &mockRetriever{docs: []*schema.Document{doc("3", 0.9), doc("2", 0.5), doc("1", 0.1)}}
+
+		r, err := NewRetriever(ctx, &Config{
+			Retrievers: []retriever.Retriever{bm25, vector},
+			Weights:    []float64{0.3, 0.7},
+			Strategy:   WeightedScore,
+		})
+		assert.NoError(t, err)
+		docs, err := r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"3", "2", "1"}, ids(docs))
+		assert.InDelta(t, 0.7, docs[0].Score(), 1e-9)
+		assert.InDelta(t, 0.3*0.5+0.7*0.5, docs[1].Score(), 1e-9)
+		assert.InDelta(t, 0.3, docs[2].Score(), 1e-9)
+	})
+
+	t.Run("dedup by content", func(t *testing.T) {
+		r1 := &mockRetriever{docs: []*schema.Document{{Content: "a"}, {Content: "b"}, {Content: "a"}}}
+		r2 := &mockRetriever{docs: []*schema.Document{{Content: "b"}}}
+
+		r, err := NewRetriever(ctx, &Config{Retrievers: []retriever.Retriever{r1, r2}})
+		assert.NoError(t, err)
+		docs, err := r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		assert.Len(t, docs, 2)
+		assert.Equal(t, "b", docs[0].Content)
+		assert.Equal(t, "a", docs[1].Content)
+	})
+
+	t.Run("retriever error", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &Config{Retrievers: []retriever.Retriever{
+			&mockRetriever{docs: []*schema.Document{doc("1", 1)}},
+			&mockRetriever{err: errors.New("mock error")},
+		}})
+		assert.NoError(t, err)
+		_, err = r.Retrieve(ctx, "query")
+		assert.ErrorContains(t, err, "mock error")
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewRetriever(ctx, &Config{})
+		assert.Error(t, err)
+		_, err = NewRetriever(ctx, &Config{Retrievers: []retriever.Retriever{&mockRetriever{}}, Strategy: "unknown"})
+		assert.Error(t, err)
+	})
+}
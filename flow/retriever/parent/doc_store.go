@@ -0,0 +1,65 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package parent
+
+import (
+	"context"
+	"sync"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// DocStore stores documents by their IDs, e.g. the parent documents of the chunks indexed in a vector database,
+// or the chunks themselves when neighboring chunks are expanded.
+// It can be backed by any key-value storage, such as Redis, MySQL or an object storage.
+type DocStore interface {
+	// MSet stores the documents, overwriting the ones with the same IDs.
+	MSet(ctx context.Context, docs []*schema.Document) error
+	// MGet returns the documents of the ids in order. IDs not found are skipped.
+	MGet(ctx context.Context, ids []string) ([]*schema.Document, error)
+}
+
+// NewInMemoryDocStore creates a DocStore keeping the documents in memory, which is useful for tests and small corpora.
+func NewInMemoryDocStore() DocStore {
+	return &inMemoryDocStore{docs: make(map[string]*schema.Document)}
+}
+
+type inMemoryDocStore struct {
+	mu   sync.RWMutex
+	docs map[string]*schema.Document
+}
+
+func (s *inMemoryDocStore) MSet(_ context.Context, docs []*schema.Document) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, doc := range docs {
+		s.docs[doc.ID] = doc
+	}
+	return nil
+}
+
+func (s *inMemoryDocStore) MGet(_ context.Context, ids []string) ([]*schema.Document, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	ret := make([]*schema.Document, 0, len(ids))
+	for _, id := range ids {
+		if doc, ok := s.docs[id]; ok {
+			ret = append(ret, doc)
+		}
+	}
+	return ret, nil
+}
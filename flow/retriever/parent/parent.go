@@ -19,6 +19,8 @@ package parent
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/schema"
@@ -44,10 +46,28 @@ type Config struct {
 	// For example: if sub-documents with parent IDs ["doc_1", "doc_2"] are retrieved,
 	// OrigDocGetter will be called to fetch the original documents with these IDs.
 	OrigDocGetter func(ctx context.Context, ids []string) ([]*schema.Document, error)
+	// DocStore is the store of the original documents, used to get them by ids if OrigDocGetter is not set.
+	// For example: the DocStore passed to the parent indexer, which keeps the parent documents when indexing the chunks.
+	DocStore DocStore
+
+	// NeighborWindow enables the small-to-big retrieval with neighboring chunks if it's positive.
+	// Instead of the whole parent documents, each retrieved chunk is expanded with NeighborWindow chunks before and after it
+	// in the same parent document, and overlapping windows are merged into one document.
+	// In this case, the documents got by ids are the chunks rather than the parents, so ChunkIndexKey and ChunkIDGetter are required.
+	NeighborWindow int
+	// ChunkIndexKey specifies the key used in the sub-document metadata to store the index of the chunk in its parent document,
+	// e.g. the ChunkIndexKey of the parent indexer.
+	ChunkIndexKey string
+	// ChunkIDGetter returns the ID of the chunk at the index of the parent document, which should match the SubIDGenerator of the parent indexer.
+	// Chunks that don't exist, e.g. the ones after the last chunk, are skipped when got by ids.
+	ChunkIDGetter func(ctx context.Context, parentID string, index int) string
+	// NeighborSeparator joins the contents of the chunks in a window, "\n" by default.
+	NeighborSeparator string
 }
 
 // NewRetriever creates a new parent retriever that handles retrieving original documents
 // based on sub-document search results.
+// With NeighborWindow set, it returns the retrieved chunks expanded with their neighboring chunks instead of the whole parent documents.
 //
 // Parameters:
 //   - ctx: context for the operation
@@ -70,13 +90,33 @@ func NewRetriever(ctx context.Context, config *Config) (retriever.Retriever, err
 	if config.Retriever == nil {
 		return nil, fmt.Errorf("retriever is required")
 	}
-	if config.OrigDocGetter == nil {
-		return nil, fmt.Errorf("orig doc getter is required")
+	origDocGetter := config.OrigDocGetter
+	if origDocGetter == nil {
+		if config.DocStore == nil {
+			return nil, fmt.Errorf("orig doc getter or doc store is required")
+		}
+		origDocGetter = config.DocStore.MGet
+	}
+	if config.NeighborWindow > 0 {
+		if config.ChunkIndexKey == "" {
+			return nil, fmt.Errorf("chunk index key is required when neighbor window is set")
+		}
+		if config.ChunkIDGetter == nil {
+			return nil, fmt.Errorf("chunk id getter is required when neighbor window is set")
+		}
+	}
+	separator := config.NeighborSeparator
+	if separator == "" {
+		separator = "\n"
 	}
 	return &parentRetriever{
-		retriever:     config.Retriever,
-		parentIDKey:   config.ParentIDKey,
-		origDocGetter: config.OrigDocGetter,
+		retriever:      config.Retriever,
+		parentIDKey:    config.ParentIDKey,
+		origDocGetter:  origDocGetter,
+		neighborWindow: config.NeighborWindow,
+		chunkIndexKey:  config.ChunkIndexKey,
+		chunkIDGetter:  config.ChunkIDGetter,
+		separator:      separator,
 	}, nil
 }
 
@@ -84,6 +124,11 @@ type parentRetriever struct {
 	retriever     retriever.Retriever
 	parentIDKey   string
 	origDocGetter func(ctx context.Context, ids []string) ([]*schema.Document, error)
+
+	neighborWindow int
+	chunkIndexKey  string
+	chunkIDGetter  func(ctx context.Context, parentID string, index int) string
+	separator      string
 }
 
 func (p *parentRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
@@ -91,6 +136,9 @@ func (p *parentRetriever) Retrieve(ctx context.Context, query string, opts ...re
 	if err != nil {
 		return nil, err
 	}
+	if p.neighborWindow > 0 {
+		return p.expandNeighbors(ctx, subDocs)
+	}
 	ids := make([]string, 0, len(subDocs))
 	for _, subDoc := range subDocs {
 		if k, ok := subDoc.MetaData[p.parentIDKey]; ok {
@@ -102,6 +150,99 @@ func (p *parentRetriever) Retrieve(ctx context.Context, query string, opts ...re
 	return p.origDocGetter(ctx, ids)
 }
 
+// expandNeighbors expands each chunk with its neighboring chunks, merging the overlapping windows of the same parent document.
+// The documents are ordered by the first retrieved chunk of their parent documents, then by their positions in the parent documents.
+func (p *parentRetriever) expandNeighbors(ctx context.Context, subDocs []*schema.Document) ([]*schema.Document, error) {
+	var parentIDs []string
+	indexes := make(map[string][]int)
+	for _, subDoc := range subDocs {
+		parentID, ok := subDoc.MetaData[p.parentIDKey].(string)
+		if !ok {
+			continue
+		}
+		index, ok := toInt(subDoc.MetaData[p.chunkIndexKey])
+		if !ok {
+			continue
+		}
+		if _, ok = indexes[parentID]; !ok {
+			parentIDs = append(parentIDs, parentID)
+		}
+		indexes[parentID] = append(indexes[parentID], index)
+	}
+
+	var ret []*schema.Document
+	for _, parentID := range parentIDs {
+		for _, w := range mergeWindows(indexes[parentID], p.neighborWindow) {
+			ids := make([]string, 0, w[1]-w[0]+1)
+			for i := w[0]; i <= w[1]; i++ {
+				ids = append(ids, p.chunkIDGetter(ctx, parentID, i))
+			}
+			chunks, err := p.origDocGetter(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			if len(chunks) == 0 {
+				continue
+			}
+
+			contents := make([]string, len(chunks))
+			for i := range chunks {
+				contents[i] = chunks[i].Content
+			}
+			metaData := make(map[string]any, len(chunks[0].MetaData))
+			for k, v := range chunks[0].MetaData {
+				metaData[k] = v
+			}
+			ret = append(ret, &schema.Document{
+				ID:       chunks[0].ID,
+				Content:  strings.Join(contents, p.separator),
+				MetaData: metaData,
+			})
+		}
+	}
+	return ret, nil
+}
+
+// mergeWindows returns the sorted windows [start, end] of the chunk indexes expanded by the window size,
+// with the overlapping or adjacent ones merged.
+func mergeWindows(indexes []int, window int) [][2]int {
+	sorted := make([]int, len(indexes))
+	copy(sorted, indexes)
+	sort.Ints(sorted)
+
+	var ret [][2]int
+	for _, index := range sorted {
+		start, end := index-window, index+window
+		if start < 0 {
+			start = 0
+		}
+		if len(ret) > 0 && start <= ret[len(ret)-1][1]+1 {
+			if end > ret[len(ret)-1][1] {
+				ret[len(ret)-1][1] = end
+			}
+			continue
+		}
+		ret = append(ret, [2]int{start, end})
+	}
+	return ret
+}
+
+// toInt converts the chunk index in the metadata, which may be decoded from json as float64.
+func toInt(v any) (int, bool) {
+	switch i := v.(type) {
+	case int:
+		return i, true
+	case int32:
+		return int(i), true
+	case int64:
+		return int(i), true
+	case float64:
+		return int(i), true
+	default:
+		return 0, false
+	}
+}
+
 func inList(elem string, list []string) bool {
 	for _, v := range list {
 		if v == elem {
@@ -19,8 +19,11 @@ package parent
 import (
 	"context"
 	"reflect"
+	"strconv"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/schema"
 )
@@ -86,3 +89,77 @@ func TestParentRetriever(t *testing.T) {
 		})
 	}
 }
+
+func TestParentRetrieverWithDocStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewInMemoryDocStore()
+	chunkID := func(ctx context.Context, parentID string, index int) string {
+		return parentID + "_" + strconv.Itoa(index)
+	}
+	var chunks []*schema.Document
+	for _, parentID := range []string{"a", "b"} {
+		for i := 0; i < 6; i++ {
+			chunks = append(chunks, &schema.Document{
+				ID:       chunkID(ctx, parentID, i),
+				Content:  parentID + strconv.Itoa(i),
+				MetaData: map[string]any{"parent": parentID, "index": i},
+			})
+		}
+	}
+	assert.NoError(t, store.MSet(ctx, append(chunks, &schema.Document{ID: "a", Content: "parent a"})))
+
+	hits := &mockRetriever{docs: []*schema.Document{
+		chunks[10], // b_4
+		chunks[0],  // a_0
+		chunks[4],  // a_4
+		chunks[1],  // a_1
+		// float64 index decoded from json
+		{ID: "b_0", MetaData: map[string]any{"parent": "b", "index": float64(0)}},
+		{ID: "no_index", MetaData: map[string]any{"parent": "b"}},
+	}}
+
+	t.Run("parent", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &Config{Retriever: hits, ParentIDKey: "parent", DocStore: store})
+		assert.NoError(t, err)
+		docs, err := r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		// parent b is not in the store
+		assert.Equal(t, []*schema.Document{{ID: "a", Content: "parent a"}}, docs)
+	})
+
+	t.Run("neighbors", func(t *testing.T) {
+		r, err := NewRetriever(ctx, &Config{
+			Retriever:      hits,
+			ParentIDKey:    "parent",
+			DocStore:       store,
+			NeighborWindow: 1,
+			ChunkIndexKey:  "index",
+			ChunkIDGetter:  chunkID,
+		})
+		assert.NoError(t, err)
+		docs, err := r.Retrieve(ctx, "query")
+		assert.NoError(t, err)
+		var contents []string
+		for _, doc := range docs {
+			contents = append(contents, doc.Content)
+		}
+		assert.Equal(t, []string{"b0\nb1", "b3\nb4\nb5", "a0\na1\na2\na3\na4\na5"}, contents)
+		assert.Equal(t, "b_3", docs[1].ID)
+		assert.Equal(t, 3, docs[1].MetaData["index"])
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		_, err := NewRetriever(ctx, &Config{Retriever: hits, ParentIDKey: "parent"})
+		assert.Error(t, err)
+		_, err = NewRetriever(ctx, &Config{Retriever: hits, ParentIDKey: "parent", DocStore: store, NeighborWindow: 1})
+		assert.Error(t, err)
+	})
+}
+
+type mockRetriever struct {
+	docs []*schema.Document
+}
+
+func (m *mockRetriever) Retrieve(ctx context.Context, query string, opts ...retriever.Option) ([]*schema.Document, error) {
+	return m.docs, nil
+}
@@ -0,0 +1,288 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/cloudwego/eino/compose"
+)
+
+// RunnableConfig is the config of NewRunnableHandler.
+type RunnableConfig[I, O any] struct {
+	// Runnable is compiled from a Graph, a Chain or a Workflow. Required.
+	Runnable compose.Runnable[I, O]
+	// EnableCheckPoint indicates the Runnable is compiled with a CheckPointStore,
+	// so that a checkpoint ID is generated for the runs without one, and the interrupted runs can be resumed.
+	// Optional, defaults to false, in which case only the runs designating a checkpoint ID are saved.
+	EnableCheckPoint bool
+	// Options returns the call options of the request, e.g. the callbacks tagged with the user of the request.
+	// The request fails with http.StatusBadRequest if an error is returned. Optional.
+	Options func(r *http.Request) ([]compose.Option, error)
+	// ResumeOptions converts ResumeRequest.ResumeData to the call options resuming the run,
+	// e.g. a compose.WithStateModifier writing the human input to the state of the graph. Optional.
+	ResumeOptions func(ctx context.Context, resumeData json.RawMessage) ([]compose.Option, error)
+}
+
+// RunRequest is the request body of the invoke and the stream endpoints of NewRunnableHandler.
+type RunRequest[I any] struct {
+	Input I `json:"input"`
+	// CheckPointID designates the checkpoint the run is saved to when interrupted. Optional.
+	CheckPointID string `json:"check_point_id,omitempty"`
+}
+
+// ResumeRequest is the request body of the resume endpoint of NewRunnableHandler.
+type ResumeRequest struct {
+	// CheckPointID is GraphInterrupt.CheckPointID of the interrupted run. Required.
+	CheckPointID string `json:"check_point_id"`
+	// ResumeData is passed to RunnableConfig.ResumeOptions, e.g. the human input. Optional.
+	ResumeData json.RawMessage `json:"resume_data,omitempty"`
+	// Stream streams the output as the stream endpoint does, instead of responding once the run ends.
+	Stream bool `json:"stream,omitempty"`
+}
+
+// InvokeResponse is the response body of the invoke endpoint of NewRunnableHandler.
+type InvokeResponse[O any] struct {
+	Output O `json:"output,omitempty"`
+	// Interrupt is set if the run is interrupted, in which case Output is the zero value.
+	Interrupt *GraphInterrupt `json:"interrupt,omitempty"`
+}
+
+// GraphInterrupt is the wire form of compose.InterruptInfo.
+// The state of the graph isn't exposed, pass the info to the clients by compose.NewInterruptAndRerunErr,
+// which is exposed by RerunNodesExtra.
+type GraphInterrupt struct {
+	// CheckPointID resumes the run, empty if the run isn't saved to a checkpoint.
+	CheckPointID    string                     `json:"check_point_id,omitempty"`
+	BeforeNodes     []string                   `json:"before_nodes,omitempty"`
+	AfterNodes      []string                   `json:"after_nodes,omitempty"`
+	RerunNodes      []string                   `json:"rerun_nodes,omitempty"`
+	RerunNodesExtra map[string]any             `json:"rerun_nodes_extra,omitempty"`
+	SubGraphs       map[string]*GraphInterrupt `json:"sub_graphs,omitempty"`
+}
+
+// NodeEvent is the wire form of compose.RunEvent.
+type NodeEvent struct {
+	Type compose.RunEventType `json:"type"`
+	Path []string             `json:"path,omitempty"`
+	Time time.Time            `json:"time"`
+
+	Err           string   `json:"err,omitempty"`
+	BranchTargets []string `json:"branch_targets,omitempty"`
+}
+
+// NewRunnableHandler serves the Runnable by the endpoints:
+//   - POST /invoke: runs with RunRequest, and responds InvokeResponse.
+//   - POST /stream: runs with RunRequest, and streams EventOutput and EventNode, then EventInterrupt or EventError if any, then EventDone.
+//   - POST /resume: resumes the interrupted run with ResumeRequest, and responds as /invoke, or /stream if ResumeRequest.Stream.
+//
+// e.g.
+//
+//	r, err := graph.Compile(ctx, compose.WithCheckPointStore(store), compose.WithInterruptBeforeNodes([]string{"pay"}))
+//	h, err := server.NewRunnableHandler(&server.RunnableConfig[[]*schema.Message, *schema.Message]{
+//		Runnable:         r,
+//		EnableCheckPoint: true,
+//	})
+//	http.Handle("/graph/", http.StripPrefix("/graph", h))
+func NewRunnableHandler[I, O any](conf *RunnableConfig[I, O]) (http.Handler, error) {
+	if conf == nil || conf.Runnable == nil {
+		return nil, errors.New("runnable is required")
+	}
+	h := &runnableHandler[I, O]{conf: conf}
+	mux := http.NewServeMux()
+	mux.Handle("/invoke", post(h.invoke))
+	mux.Handle("/stream", post(h.stream))
+	mux.Handle("/resume", post(h.resume))
+	return mux, nil
+}
+
+type runnableHandler[I, O any] struct {
+	conf *RunnableConfig[I, O]
+}
+
+func (h *runnableHandler[I, O]) invoke(w http.ResponseWriter, r *http.Request, req *RunRequest[I]) {
+	checkPointID := h.checkPointID(req.CheckPointID)
+	opts, err := h.options(r, checkPointID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	h.invokeRun(w, r, req.Input, checkPointID, opts)
+}
+
+func (h *runnableHandler[I, O]) stream(w http.ResponseWriter, r *http.Request, req *RunRequest[I]) {
+	checkPointID := h.checkPointID(req.CheckPointID)
+	opts, err := h.options(r, checkPointID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	h.streamRun(w, r, req.Input, checkPointID, opts)
+}
+
+func (h *runnableHandler[I, O]) resume(w http.ResponseWriter, r *http.Request, req *ResumeRequest) {
+	if req.CheckPointID == "" {
+		writeError(w, http.StatusBadRequest, errors.New("check_point_id is required"))
+		return
+	}
+	opts, err := h.options(r, req.CheckPointID)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if h.conf.ResumeOptions != nil {
+		resumeOpts, err := h.conf.ResumeOptions(r.Context(), req.ResumeData)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("resume options fail: %w", err))
+			return
+		}
+		opts = append(opts, resumeOpts...)
+	}
+
+	// the input is ignored when the run is restored from the checkpoint
+	var input I
+	if req.Stream {
+		h.streamRun(w, r, input, req.CheckPointID, opts)
+	} else {
+		h.invokeRun(w, r, input, req.CheckPointID, opts)
+	}
+}
+
+func (h *runnableHandler[I, O]) invokeRun(w http.ResponseWriter, r *http.Request, input I, checkPointID string, opts []compose.Option) {
+	out, err := h.conf.Runnable.Invoke(r.Context(), input, opts...)
+	if err != nil {
+		if info, ok := compose.ExtractInterruptInfo(err); ok {
+			writeJSON(w, http.StatusOK, &InvokeResponse[O]{Interrupt: toGraphInterrupt(info, checkPointID)})
+			return
+		}
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, &InvokeResponse[O]{Output: out})
+}
+
+func (h *runnableHandler[I, O]) streamRun(w http.ResponseWriter, r *http.Request, input I, checkPointID string, opts []compose.Option) {
+	sw, err := newSSEWriter(w)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer sw.done()
+
+	eventOpt, events := compose.WithRunEvents()
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer events.Close()
+		for {
+			e, err := events.Recv()
+			if err != nil {
+				return
+			}
+			sw.send(EventNode, toNodeEvent(e))
+		}
+	}()
+
+	err = h.pipeOutput(sw, r, input, append(opts, eventOpt)...)
+	// the run events are closed when the run returns, send them before the interrupt or the error
+	wg.Wait()
+	if err != nil {
+		if info, ok := compose.ExtractInterruptInfo(err); ok {
+			sw.send(EventInterrupt, toGraphInterrupt(info, checkPointID))
+		} else {
+			sw.sendError(err)
+		}
+	}
+}
+
+func (h *runnableHandler[I, O]) pipeOutput(sw *sseWriter, r *http.Request, input I, opts ...compose.Option) error {
+	sr, err := h.conf.Runnable.Stream(r.Context(), input, opts...)
+	if err != nil {
+		return err
+	}
+	defer sr.Close()
+	for {
+		chunk, err := sr.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		sw.send(EventOutput, chunk)
+	}
+}
+
+func (h *runnableHandler[I, O]) checkPointID(checkPointID string) string {
+	if checkPointID == "" && h.conf.EnableCheckPoint {
+		return newCheckPointID()
+	}
+	return checkPointID
+}
+
+func (h *runnableHandler[I, O]) options(r *http.Request, checkPointID string) ([]compose.Option, error) {
+	var opts []compose.Option
+	if h.conf.Options != nil {
+		o, err := h.conf.Options(r)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, o...)
+	}
+	if checkPointID != "" {
+		opts = append(opts, compose.WithCheckPointID(checkPointID))
+	}
+	return opts, nil
+}
+
+func toGraphInterrupt(info *compose.InterruptInfo, checkPointID string) *GraphInterrupt {
+	gi := &GraphInterrupt{
+		CheckPointID:    checkPointID,
+		BeforeNodes:     info.BeforeNodes,
+		AfterNodes:      info.AfterNodes,
+		RerunNodes:      info.RerunNodes,
+		RerunNodesExtra: info.RerunNodesExtra,
+	}
+	if len(info.SubGraphs) > 0 {
+		gi.SubGraphs = make(map[string]*GraphInterrupt, len(info.SubGraphs))
+		for key, sub := range info.SubGraphs {
+			gi.SubGraphs[key] = toGraphInterrupt(sub, "")
+		}
+	}
+	return gi
+}
+
+func toNodeEvent(e *compose.RunEvent) *NodeEvent {
+	ne := &NodeEvent{
+		Type:          e.Type,
+		Path:          e.Path.GetPath(),
+		Time:          e.Time,
+		BranchTargets: e.BranchTargets,
+	}
+	if e.Err != nil {
+		ne.Err = e.Err.Error()
+	}
+	return ne
+}
@@ -0,0 +1,306 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/schema"
+)
+
+// RunnerConfig is the config of NewRunnerHandler.
+type RunnerConfig struct {
+	// Runner is the runner of the agent to serve, resuming requires its CheckPointStore or RunStore. Required.
+	Runner *adk.Runner
+	// EnableCheckPoint indicates the Runner is configured with a CheckPointStore,
+	// so that a checkpoint ID is generated for the runs without one, and the interrupted runs can be resumed by it.
+	// Optional, defaults to false, in which case the interrupted runs are resumed by their run IDs.
+	EnableCheckPoint bool
+	// Options returns the run options of the request, e.g. adk.WithSession of the user of the request.
+	// The request fails with http.StatusBadRequest if an error is returned. Optional.
+	Options func(r *http.Request) ([]adk.AgentRunOption, error)
+}
+
+// AgentRunRequest is the request body of the run endpoint of NewRunnerHandler.
+type AgentRunRequest struct {
+	// Messages is the input of the run, followed by Query as a user message if set.
+	// At least one of Messages and Query is required.
+	Messages []adk.Message `json:"messages,omitempty"`
+	Query    string        `json:"query,omitempty"`
+	// RunID designates the run persisted to the RunStore of the Runner, see adk.WithRunID. Optional.
+	RunID string `json:"run_id,omitempty"`
+	// CheckPointID designates the checkpoint the run is saved to when interrupted.
+	// Optional, generated if empty and RunnerConfig.EnableCheckPoint is set.
+	CheckPointID string `json:"check_point_id,omitempty"`
+}
+
+// AgentResumeRequest is the request body of the resume endpoint of NewRunnerHandler.
+// One of CheckPointID and RunID is required.
+type AgentResumeRequest struct {
+	// CheckPointID is AgentInterrupt.CheckPointID of the interrupted run, resumed by adk.Runner.Resume.
+	CheckPointID string `json:"check_point_id,omitempty"`
+	// RunID is the persisted run resumed by adk.Runner.ResumeRun if CheckPointID is empty,
+	// otherwise the following events are appended to the run.
+	RunID string `json:"run_id,omitempty"`
+
+	// ToolApprovals are the decisions on AgentInterrupt.ToolApprovals, keyed by tool call ID.
+	ToolApprovals map[string]*adk.ToolApprovalResponse `json:"tool_approvals,omitempty"`
+	// ToolResults are the results of AgentInterrupt.PendingToolCalls, keyed by tool call ID, e.g. the human input.
+	ToolResults map[string]string `json:"tool_results,omitempty"`
+}
+
+// AgentInterrupt is the data of EventInterrupt sent when the agent run is interrupted.
+type AgentInterrupt struct {
+	// CheckPointID resumes the run, empty if the run isn't saved to a checkpoint, in which case it's resumed by RunID.
+	CheckPointID string `json:"check_point_id,omitempty"`
+	RunID        string `json:"run_id,omitempty"`
+	AgentName    string `json:"agent_name,omitempty"`
+
+	// ToolApprovals are the tool calls waiting for the approvals, see adk.GetToolApprovalRequests.
+	ToolApprovals []*adk.ToolApprovalRequest `json:"tool_approvals,omitempty"`
+	// PendingToolCalls are the tool calls waiting for the results, see adk.GetPendingToolCalls.
+	PendingToolCalls []*adk.PendingToolCall `json:"pending_tool_calls,omitempty"`
+	// BudgetExceeded are the tool calls exceeding the budget of the run, see adk.GetRunBudgetExceeded.
+	BudgetExceeded []*adk.RunBudgetExceeded `json:"budget_exceeded,omitempty"`
+}
+
+// MessageDelta is the data of EventDelta, a chunk of the message streamed by the agent.
+type MessageDelta struct {
+	// Seq is the sequence of the event the chunk belongs to, i.e. adk.RunEventRecord.Seq of the following EventAgent.
+	Seq       int         `json:"seq"`
+	AgentName string      `json:"agent_name,omitempty"`
+	Message   adk.Message `json:"message"`
+}
+
+// NewRunnerHandler serves the Runner by the endpoints:
+//   - POST /run: runs with AgentRunRequest.
+//   - POST /resume: resumes the interrupted run with AgentResumeRequest.
+//
+// Both stream EventDelta for each chunk of the streamed messages, EventAgent for each AgentEvent,
+// then EventInterrupt if the run is interrupted, then EventDone.
+// The errors of the agents are carried by adk.RunEventRecord.Err.
+// e.g.
+//
+//	runner := adk.NewRunner(ctx, adk.RunnerConfig{Agent: agent, EnableStreaming: true, CheckPointStore: store})
+//	h, err := server.NewRunnerHandler(&server.RunnerConfig{Runner: runner, EnableCheckPoint: true})
+//	http.Handle("/agent/", http.StripPrefix("/agent", h))
+//
+//	// POST /agent/run {"query": "delete the logs of last year"}
+//	// => event: interrupt, data: {"check_point_id": "...", "tool_approvals": [{"ToolCallID": "call_1", ...}]}
+//	// POST /agent/resume {"check_point_id": "...", "tool_approvals": {"call_1": {"Approved": true}}}
+func NewRunnerHandler(conf *RunnerConfig) (http.Handler, error) {
+	if conf == nil || conf.Runner == nil {
+		return nil, errors.New("runner is required")
+	}
+	h := &runnerHandler{conf: conf}
+	mux := http.NewServeMux()
+	mux.Handle("/run", post(h.run))
+	mux.Handle("/resume", post(h.resume))
+	return mux, nil
+}
+
+type runnerHandler struct {
+	conf *RunnerConfig
+}
+
+func (h *runnerHandler) run(w http.ResponseWriter, r *http.Request, req *AgentRunRequest) {
+	messages := req.Messages
+	if req.Query != "" {
+		messages = append(messages, schema.UserMessage(req.Query))
+	}
+	if len(messages) == 0 {
+		writeError(w, http.StatusBadRequest, errors.New("messages or query is required"))
+		return
+	}
+	opts, err := h.options(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	checkPointID := req.CheckPointID
+	if checkPointID == "" && h.conf.EnableCheckPoint {
+		checkPointID = newCheckPointID()
+	}
+	if checkPointID != "" {
+		opts = append(opts, adk.WithCheckPointID(checkPointID))
+	}
+	if req.RunID != "" {
+		opts = append(opts, adk.WithRunID(req.RunID))
+	}
+
+	sw, err := newSSEWriter(w)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer sw.done()
+	pipeEvents(sw, h.conf.Runner.Run(r.Context(), messages, opts...), checkPointID, req.RunID)
+}
+
+func (h *runnerHandler) resume(w http.ResponseWriter, r *http.Request, req *AgentResumeRequest) {
+	if req.CheckPointID == "" && req.RunID == "" {
+		writeError(w, http.StatusBadRequest, errors.New("check_point_id or run_id is required"))
+		return
+	}
+	opts, err := h.options(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if len(req.ToolApprovals) > 0 {
+		opts = append(opts, adk.WithToolApprovals(req.ToolApprovals))
+	}
+	if len(req.ToolResults) > 0 {
+		opts = append(opts, adk.WithToolResults(req.ToolResults))
+	}
+
+	var iter *adk.AsyncIterator[*adk.AgentEvent]
+	if req.CheckPointID != "" {
+		if req.RunID != "" {
+			opts = append(opts, adk.WithRunID(req.RunID))
+		}
+		iter, err = h.conf.Runner.Resume(r.Context(), req.CheckPointID, opts...)
+	} else {
+		iter, err = h.conf.Runner.ResumeRun(r.Context(), req.RunID, opts...)
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("resume fail: %w", err))
+		return
+	}
+
+	sw, err := newSSEWriter(w)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	defer sw.done()
+	pipeEvents(sw, iter, req.CheckPointID, req.RunID)
+}
+
+func (h *runnerHandler) options(r *http.Request) ([]adk.AgentRunOption, error) {
+	if h.conf.Options == nil {
+		return nil, nil
+	}
+	return h.conf.Options(r)
+}
+
+// pipeEvents sends the events of the iterator until it ends, the streamed messages are sent chunk by chunk,
+// and then concatenated in the event record.
+func pipeEvents(sw *sseWriter, iter *adk.AsyncIterator[*adk.AgentEvent], checkPointID, runID string) {
+	var interrupt *AgentInterrupt
+	for seq := 0; ; seq++ {
+		event, ok := iter.Next()
+		if !ok {
+			break
+		}
+
+		rec := toEventRecord(event)
+		rec.Seq = seq
+		if mv := messageOutput(event); mv != nil {
+			if mv.IsStreaming {
+				rec.Message, rec.Err = pipeMessageStream(sw, mv.MessageStream, seq, event.AgentName)
+			} else {
+				rec.Message = mv.Message
+			}
+		}
+		if event.Err != nil {
+			rec.Err = event.Err.Error()
+		}
+		rec.CreatedAt = time.Now()
+		sw.send(EventAgent, rec)
+
+		interrupt = nil
+		if event.Action != nil && event.Action.Interrupted != nil {
+			info := event.Action.Interrupted
+			interrupt = &AgentInterrupt{
+				CheckPointID:     checkPointID,
+				RunID:            runID,
+				AgentName:        event.AgentName,
+				ToolApprovals:    adk.GetToolApprovalRequests(info),
+				PendingToolCalls: adk.GetPendingToolCalls(info),
+				BudgetExceeded:   adk.GetRunBudgetExceeded(info),
+			}
+		}
+	}
+	if interrupt != nil {
+		sw.send(EventInterrupt, interrupt)
+	}
+}
+
+// pipeMessageStream sends the chunks of the stream, and returns the concatenated message with the error of the stream if any.
+func pipeMessageStream(sw *sseWriter, stream adk.MessageStream, seq int, agentName string) (adk.Message, string) {
+	defer stream.Close()
+	var chunks []adk.Message
+	for {
+		chunk, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			msg, _ := concatChunks(chunks)
+			return msg, err.Error()
+		}
+		chunks = append(chunks, chunk)
+		sw.send(EventDelta, &MessageDelta{Seq: seq, AgentName: agentName, Message: chunk})
+	}
+	msg, err := concatChunks(chunks)
+	if err != nil {
+		return nil, err.Error()
+	}
+	return msg, ""
+}
+
+func concatChunks(chunks []adk.Message) (adk.Message, error) {
+	if len(chunks) == 0 {
+		return nil, nil
+	}
+	return schema.ConcatMessages(chunks)
+}
+
+func messageOutput(event *adk.AgentEvent) *adk.MessageVariant {
+	if event.Output == nil {
+		return nil
+	}
+	return event.Output.MessageOutput
+}
+
+func toEventRecord(event *adk.AgentEvent) *adk.RunEventRecord {
+	rec := &adk.RunEventRecord{
+		AgentName: event.AgentName,
+		RunPath:   make([]string, 0, len(event.RunPath)),
+	}
+	for i := range event.RunPath {
+		rec.RunPath = append(rec.RunPath, event.RunPath[i].String())
+	}
+	if mv := messageOutput(event); mv != nil {
+		rec.Role = mv.Role
+		rec.ToolName = mv.ToolName
+	}
+	if event.Action != nil {
+		rec.Exit = event.Action.Exit
+		rec.Interrupted = event.Action.Interrupted != nil
+		if event.Action.TransferToAgent != nil {
+			rec.TransferToAgent = event.Action.TransferToAgent.DestAgentName
+		}
+	}
+	return rec
+}
@@ -0,0 +1,139 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package server exposes a compiled Runnable or an adk Runner over HTTP,
+// streaming the output and the events to the clients by server-sent events (SSE),
+// and resuming the interrupted runs, e.g. with the tool approvals or the human input.
+// The handlers serve the paths relative to where they are mounted.
+// e.g.
+//
+//	h, err := server.NewRunnerHandler(&server.RunnerConfig{Runner: runner})
+//	mux.Handle("/agent/", http.StripPrefix("/agent", h))
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// The SSE events sent by the handlers, the data of each event is a JSON object.
+const (
+	// EventOutput carries a chunk of the output of a streamed Runnable.
+	EventOutput = "output"
+	// EventNode carries a NodeEvent, i.e. the progress of a graph run.
+	EventNode = "node"
+	// EventDelta carries a MessageDelta, i.e. a chunk of a message streamed by an agent.
+	EventDelta = "delta"
+	// EventAgent carries an adk.RunEventRecord, i.e. an AgentEvent, in which a streamed message is concatenated.
+	EventAgent = "event"
+	// EventInterrupt carries a GraphInterrupt or an AgentInterrupt, the run can be resumed by the resume endpoint.
+	EventInterrupt = "interrupt"
+	// EventError carries an ErrorResponse, e.g. the run fails.
+	EventError = "error"
+	// EventDone is the last event of each stream, carrying an empty object.
+	EventDone = "done"
+)
+
+// ErrorResponse is the body of the failed requests, and the data of EventError.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// sseWriter writes the server-sent events, it can be used concurrently.
+type sseWriter struct {
+	mu      sync.Mutex
+	w       http.ResponseWriter
+	flusher http.Flusher
+	err     error
+}
+
+func newSSEWriter(w http.ResponseWriter) (*sseWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, errors.New("response writer doesn't support flushing")
+	}
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return &sseWriter{w: w, flusher: flusher}, nil
+}
+
+// send writes the event with data in JSON. Once a write fails, e.g. the client disconnects,
+// the following events are dropped, so that the run can be drained.
+func (s *sseWriter) send(event string, data any) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return
+	}
+	b, err := json.Marshal(data)
+	if err != nil {
+		b, _ = json.Marshal(&ErrorResponse{Error: fmt.Sprintf("marshal %s event fail: %v", event, err)})
+		event = EventError
+	}
+	if _, err = fmt.Fprintf(s.w, "event: %s\ndata: %s\n\n", event, b); err != nil {
+		s.err = err
+		return
+	}
+	s.flusher.Flush()
+}
+
+func (s *sseWriter) sendError(err error) {
+	s.send(EventError, &ErrorResponse{Error: err.Error()})
+}
+
+func (s *sseWriter) done() {
+	s.send(EventDone, struct{}{})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, &ErrorResponse{Error: err.Error()})
+}
+
+// post wraps the handler accepting only POST requests with the JSON body of type T.
+func post[T any](fn func(w http.ResponseWriter, r *http.Request, req *T)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			writeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method %s not allowed", r.Method))
+			return
+		}
+		req := new(T)
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("decode request fail: %w", err))
+			return
+		}
+		fn(w, r, req)
+	}
+}
+
+func newCheckPointID() string {
+	return uuid.NewString()
+}
@@ -0,0 +1,278 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/components/model/modeltest"
+	"github.com/cloudwego/eino/components/tool"
+	"github.com/cloudwego/eino/compose"
+	"github.com/cloudwego/eino/schema"
+)
+
+type inMemoryStore struct {
+	mu sync.Mutex
+	m  map[string][]byte
+}
+
+func newInMemoryStore() *inMemoryStore {
+	return &inMemoryStore{m: make(map[string][]byte)}
+}
+
+func (s *inMemoryStore) Get(_ context.Context, checkPointID string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.m[checkPointID]
+	return v, ok, nil
+}
+
+func (s *inMemoryStore) Set(_ context.Context, checkPointID string, checkPoint []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[checkPointID] = checkPoint
+	return nil
+}
+
+type sseEvent struct {
+	event string
+	data  string
+}
+
+func doPost(t *testing.T, url, body string) (int, string) {
+	resp, err := http.Post(url, "application/json", strings.NewReader(body))
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	return resp.StatusCode, string(b)
+}
+
+func postSSE(t *testing.T, url, body string) []*sseEvent {
+	status, data := doPost(t, url, body)
+	assert.Equal(t, http.StatusOK, status, data)
+	var events []*sseEvent
+	for _, block := range strings.Split(strings.TrimSpace(data), "\n\n") {
+		e := &sseEvent{}
+		for _, line := range strings.Split(block, "\n") {
+			if v := strings.TrimPrefix(line, "event: "); v != line {
+				e.event = v
+			} else if v = strings.TrimPrefix(line, "data: "); v != line {
+				e.data = v
+			}
+		}
+		events = append(events, e)
+	}
+	return events
+}
+
+func filterEvents(events []*sseEvent, event string) []string {
+	var data []string
+	for _, e := range events {
+		if e.event == event {
+			data = append(data, e.data)
+		}
+	}
+	return data
+}
+
+func TestRunnableHandler(t *testing.T) {
+	ctx := context.Background()
+
+	newHandler := func(opts ...compose.GraphCompileOption) http.Handler {
+		g := compose.NewGraph[string, string]()
+		assert.NoError(t, g.AddLambdaNode("upper", compose.InvokableLambda(func(ctx context.Context, in string) (string, error) {
+			return strings.ToUpper(in), nil
+		})))
+		assert.NoError(t, g.AddEdge(compose.START, "upper"))
+		assert.NoError(t, g.AddEdge("upper", compose.END))
+		r, err := g.Compile(ctx, opts...)
+		assert.NoError(t, err)
+		h, err := NewRunnableHandler(&RunnableConfig[string, string]{
+			Runnable:         r,
+			EnableCheckPoint: len(opts) > 0,
+		})
+		assert.NoError(t, err)
+		return h
+	}
+
+	_, err := NewRunnableHandler(&RunnableConfig[string, string]{})
+	assert.ErrorContains(t, err, "runnable is required")
+
+	t.Run("invoke and stream", func(t *testing.T) {
+		srv := httptest.NewServer(newHandler())
+		defer srv.Close()
+
+		status, body := doPost(t, srv.URL+"/invoke", `{"input":"hi"}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"output":"HI"}`, body)
+
+		events := postSSE(t, srv.URL+"/stream", `{"input":"hi"}`)
+		assert.Equal(t, []string{`"HI"`}, filterEvents(events, EventOutput))
+		nodes := filterEvents(events, EventNode)
+		assert.NotEmpty(t, nodes)
+		var ne NodeEvent
+		assert.NoError(t, json.Unmarshal([]byte(nodes[0]), &ne))
+		assert.Equal(t, compose.RunEventNodeStart, ne.Type)
+		assert.Equal(t, []string{"upper"}, ne.Path)
+		assert.Equal(t, EventDone, events[len(events)-1].event)
+
+		resp, err := http.Get(srv.URL + "/invoke")
+		assert.NoError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusMethodNotAllowed, resp.StatusCode)
+		status, _ = doPost(t, srv.URL+"/invoke", `{"input":1}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+		status, _ = doPost(t, srv.URL+"/resume", `{}`)
+		assert.Equal(t, http.StatusBadRequest, status)
+	})
+
+	t.Run("interrupt and resume", func(t *testing.T) {
+		srv := httptest.NewServer(newHandler(compose.WithCheckPointStore(newInMemoryStore()),
+			compose.WithInterruptBeforeNodes([]string{"upper"})))
+		defer srv.Close()
+
+		status, body := doPost(t, srv.URL+"/invoke", `{"input":"hi"}`)
+		assert.Equal(t, http.StatusOK, status)
+		var resp InvokeResponse[string]
+		assert.NoError(t, json.Unmarshal([]byte(body), &resp))
+		assert.NotNil(t, resp.Interrupt)
+		assert.NotEmpty(t, resp.Interrupt.CheckPointID)
+		assert.Equal(t, []string{"upper"}, resp.Interrupt.BeforeNodes)
+
+		events := postSSE(t, srv.URL+"/resume", `{"check_point_id":"`+resp.Interrupt.CheckPointID+`","stream":true}`)
+		assert.Equal(t, []string{`"HI"`}, filterEvents(events, EventOutput))
+		assert.Empty(t, filterEvents(events, EventInterrupt))
+
+		events = postSSE(t, srv.URL+"/stream", `{"input":"hello","check_point_id":"2"}`)
+		interrupts := filterEvents(events, EventInterrupt)
+		assert.Len(t, interrupts, 1)
+		assert.JSONEq(t, `{"check_point_id":"2","before_nodes":["upper"]}`, interrupts[0])
+		assert.Equal(t, EventDone, events[len(events)-1].event)
+
+		status, body = doPost(t, srv.URL+"/resume", `{"check_point_id":"2"}`)
+		assert.Equal(t, http.StatusOK, status)
+		assert.JSONEq(t, `{"output":"HELLO"}`, body)
+	})
+}
+
+type deleteFileTool struct{}
+
+func (d *deleteFileTool) Info(_ context.Context) (*schema.ToolInfo, error) {
+	return &schema.ToolInfo{Name: "delete_file", Desc: "delete the file"}, nil
+}
+
+func (d *deleteFileTool) InvokableRun(_ context.Context, _ string, _ ...tool.Option) (string, error) {
+	return "deleted", nil
+}
+
+func TestRunnerHandler(t *testing.T) {
+	ctx := context.Background()
+
+	cm := modeltest.New(
+		modeltest.When(modeltest.HasToolResult("delete_file"), modeltest.Stream(0, "do", "ne")).Repeatedly(),
+		modeltest.When(modeltest.Any(), modeltest.ToolCalls(modeltest.ToolCall("delete_file", `{"path":"a.txt"}`))).Repeatedly(),
+	)
+	a, err := adk.NewChatModelAgent(ctx, &adk.ChatModelAgentConfig{
+		Name:        "assistant",
+		Description: "description",
+		Model:       cm,
+		ToolsConfig: adk.ToolsConfig{
+			ToolsNodeConfig: compose.ToolsNodeConfig{Tools: []tool.BaseTool{&deleteFileTool{}}},
+			RequireApproval: map[string]bool{"delete_file": true},
+		},
+	})
+	assert.NoError(t, err)
+	runner := adk.NewRunner(ctx, adk.RunnerConfig{Agent: a, EnableStreaming: true, CheckPointStore: newInMemoryStore()})
+
+	_, err = NewRunnerHandler(&RunnerConfig{})
+	assert.ErrorContains(t, err, "runner is required")
+	h, err := NewRunnerHandler(&RunnerConfig{Runner: runner})
+	assert.NoError(t, err)
+	noCheckPointSrv := httptest.NewServer(h)
+	defer noCheckPointSrv.Close()
+	events := postSSE(t, noCheckPointSrv.URL+"/run", `{"query":"delete a.txt","run_id":"1"}`)
+	interrupts := filterEvents(events, EventInterrupt)
+	assert.Len(t, interrupts, 1)
+	var interrupt AgentInterrupt
+	assert.NoError(t, json.Unmarshal([]byte(interrupts[0]), &interrupt))
+	assert.Empty(t, interrupt.CheckPointID)
+	assert.Equal(t, "1", interrupt.RunID)
+
+	h, err = NewRunnerHandler(&RunnerConfig{Runner: runner, EnableCheckPoint: true})
+	assert.NoError(t, err)
+	srv := httptest.NewServer(h)
+	defer srv.Close()
+
+	events = postSSE(t, srv.URL+"/run", `{"query":"delete a.txt"}`)
+	records := filterEvents(events, EventAgent)
+	assert.Len(t, records, 2)
+	var rec adk.RunEventRecord
+	assert.NoError(t, json.Unmarshal([]byte(records[0]), &rec))
+	assert.Equal(t, "assistant", rec.AgentName)
+	assert.Equal(t, []string{"assistant"}, rec.RunPath)
+	assert.Equal(t, "delete_file", rec.Message.ToolCalls[0].Function.Name)
+	assert.NoError(t, json.Unmarshal([]byte(records[1]), &rec))
+	assert.Equal(t, 1, rec.Seq)
+	assert.True(t, rec.Interrupted)
+
+	interrupts = filterEvents(events, EventInterrupt)
+	assert.Len(t, interrupts, 1)
+	assert.NoError(t, json.Unmarshal([]byte(interrupts[0]), &interrupt))
+	assert.NotEmpty(t, interrupt.CheckPointID)
+	assert.Equal(t, []*adk.ToolApprovalRequest{{ToolCallID: "call_delete_file", ToolName: "delete_file", Arguments: `{"path":"a.txt"}`}},
+		interrupt.ToolApprovals)
+	assert.Equal(t, EventDone, events[len(events)-1].event)
+
+	events = postSSE(t, srv.URL+"/resume",
+		`{"check_point_id":"`+interrupt.CheckPointID+`","tool_approvals":{"call_delete_file":{"Approved":true}}}`)
+	assert.Empty(t, filterEvents(events, EventInterrupt))
+	var contents []string
+	for _, data := range filterEvents(events, EventDelta) {
+		var delta MessageDelta
+		assert.NoError(t, json.Unmarshal([]byte(data), &delta))
+		assert.Equal(t, 1, delta.Seq)
+		contents = append(contents, delta.Message.Content)
+	}
+	assert.Equal(t, []string{"do", "ne"}, contents)
+	records = filterEvents(events, EventAgent)
+	assert.Len(t, records, 2)
+	assert.NoError(t, json.Unmarshal([]byte(records[0]), &rec))
+	assert.Equal(t, "deleted", rec.Message.Content)
+	assert.Equal(t, "delete_file", rec.ToolName)
+	assert.NoError(t, json.Unmarshal([]byte(records[1]), &rec))
+	assert.Equal(t, 1, rec.Seq)
+	assert.Equal(t, "done", rec.Message.Content)
+
+	status, _ := doPost(t, srv.URL+"/run", `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, _ = doPost(t, srv.URL+"/resume", `{}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	status, body := doPost(t, srv.URL+"/resume", `{"check_point_id":"missing"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "is not existed")
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+type registeredHandler struct {
+	name      string
+	handler   Handler
+	order     int
+	mandatory bool
+	seq       int
+}
+
+var registry struct {
+	mu       sync.RWMutex
+	seq      int
+	handlers []*registeredHandler // sorted by order, then seq
+}
+
+// RegisterGlobalHandler registers the named handler, replacing the one registered with the same name.
+func RegisterGlobalHandler(name string, handler Handler, order int, mandatory bool) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	registry.seq++
+	rh := &registeredHandler{name: name, handler: handler, order: order, mandatory: mandatory, seq: registry.seq}
+
+	hs := make([]*registeredHandler, 0, len(registry.handlers)+1)
+	for _, h := range registry.handlers {
+		if h.name != name {
+			hs = append(hs, h)
+		}
+	}
+	hs = append(hs, rh)
+	sort.SliceStable(hs, func(i, j int) bool {
+		if hs[i].order != hs[j].order {
+			return hs[i].order < hs[j].order
+		}
+		return hs[i].seq < hs[j].seq
+	})
+	registry.handlers = hs
+}
+
+// UnregisterGlobalHandler removes the named handler, returns false if it's not registered.
+func UnregisterGlobalHandler(name string) bool {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	for i, h := range registry.handlers {
+		if h.name == name {
+			hs := make([]*registeredHandler, 0, len(registry.handlers)-1)
+			hs = append(hs, registry.handlers[:i]...)
+			registry.handlers = append(hs, registry.handlers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// CtxGlobalHandlerOverridesKey is the key of the *GlobalHandlerOverrides of the run in the context.
+type CtxGlobalHandlerOverridesKey struct{}
+
+// GlobalHandlerOverrides are the per-run changes of the registered global handlers.
+type GlobalHandlerOverrides struct {
+	// Suppressed are the names of the handlers not to run.
+	Suppressed map[string]bool
+	// Replaced are the handlers to run in place of the named ones.
+	Replaced map[string]Handler
+}
+
+// WithGlobalHandlerOverrides returns a context with the overrides merged into the overrides in the ctx.
+func WithGlobalHandlerOverrides(ctx context.Context, suppressed []string, replaced map[string]Handler) context.Context {
+	if len(suppressed) == 0 && len(replaced) == 0 {
+		return ctx
+	}
+
+	n := &GlobalHandlerOverrides{
+		Suppressed: map[string]bool{},
+		Replaced:   map[string]Handler{},
+	}
+	if prev, ok := ctx.Value(CtxGlobalHandlerOverridesKey{}).(*GlobalHandlerOverrides); ok && prev != nil {
+		for name := range prev.Suppressed {
+			n.Suppressed[name] = true
+		}
+		for name, h := range prev.Replaced {
+			n.Replaced[name] = h
+		}
+	}
+	for _, name := range suppressed {
+		n.Suppressed[name] = true
+		delete(n.Replaced, name)
+	}
+	for name, h := range replaced {
+		n.Replaced[name] = h
+		delete(n.Suppressed, name)
+	}
+	return context.WithValue(ctx, CtxGlobalHandlerOverridesKey{}, n)
+}
+
+// HasGlobalHandlerOverrides reports whether the ctx carries any overrides of the global handlers.
+func HasGlobalHandlerOverrides(ctx context.Context) bool {
+	o, ok := ctx.Value(CtxGlobalHandlerOverridesKey{}).(*GlobalHandlerOverrides)
+	return ok && o != nil
+}
+
+// globalHandlers returns GlobalHandlers followed by the registered handlers, with the overrides in the ctx applied.
+func globalHandlers(ctx context.Context) []Handler {
+	registry.mu.RLock()
+	registered := registry.handlers
+	registry.mu.RUnlock()
+
+	var overrides *GlobalHandlerOverrides
+	if ctx != nil {
+		overrides, _ = ctx.Value(CtxGlobalHandlerOverridesKey{}).(*GlobalHandlerOverrides)
+	}
+
+	hs := make([]Handler, 0, len(GlobalHandlers)+len(registered))
+	hs = append(hs, GlobalHandlers...)
+	for _, rh := range registered {
+		if overrides == nil || rh.mandatory {
+			hs = append(hs, rh.handler)
+			continue
+		}
+		if overrides.Suppressed[rh.name] {
+			continue
+		}
+		if h, ok := overrides.Replaced[rh.name]; ok {
+			if h != nil {
+				hs = append(hs, h)
+			}
+			continue
+		}
+		hs = append(hs, rh.handler)
+	}
+	return hs
+}
@@ -25,7 +25,7 @@ import (
 )
 
 func InitCallbacks(ctx context.Context, info *RunInfo, handlers ...Handler) context.Context {
-	mgr, ok := newManager(info, handlers...)
+	mgr, ok := newManager(ctx, info, handlers...)
 	if ok {
 		return ctxWithManager(ctx, mgr)
 	}
@@ -91,11 +91,20 @@ func On[T any](ctx context.Context, inOut T, handle Handle[T], timing CallbackTi
 		}
 	}
 
+	if start {
+		ctx = startTiming(ctx)
+	} else {
+		ctx = endTiming(ctx)
+	}
+
 	hs := make([]Handler, 0, len(nMgr.handlers)+len(nMgr.globalHandlers))
 	for _, handler := range append(nMgr.handlers, nMgr.globalHandlers...) {
 		timingChecker, ok_ := handler.(TimingChecker)
 		if !ok_ || timingChecker.Needed(ctx, info, timing) {
 			hs = append(hs, handler)
+		} else if timing == TimingOnEndWithStreamOutput && needStreamMetrics(ctx, info, handler) {
+			// OnEndWithStreamOutputHandle tells the handlers only measuring the stream
+			hs = append(hs, handler)
 		}
 	}
 
@@ -164,6 +173,23 @@ func OnStartWithStreamInputHandle[T any](ctx context.Context, input *schema.Stre
 func OnEndWithStreamOutputHandle[T any](ctx context.Context, output *schema.StreamReader[T],
 	runInfo *RunInfo, handlers []Handler) (context.Context, *schema.StreamReader[T]) {
 
+	var streamHandlers []Handler
+	var metricsHandlers []StreamMetricsHandler
+	for _, handler := range handlers {
+		if checker, ok := handler.(TimingChecker); !ok || checker.Needed(ctx, runInfo, TimingOnEndWithStreamOutput) {
+			streamHandlers = append(streamHandlers, handler)
+		}
+		if needStreamMetrics(ctx, runInfo, handler) {
+			metricsHandlers = append(metricsHandlers, handler.(StreamMetricsHandler))
+		}
+	}
+
+	if len(metricsHandlers) > 0 {
+		srs := output.Copy(2)
+		output = srs[0]
+		measureStream(ctx, runInfo, srs[1], metricsHandlers)
+	}
+
 	cpy := output.Copy
 
 	handle := func(ctx context.Context, handler Handler, out *schema.StreamReader[T]) context.Context {
@@ -173,12 +199,25 @@ func OnEndWithStreamOutputHandle[T any](ctx context.Context, output *schema.Stre
 		return handler.OnEndWithStreamOutput(ctx, runInfo, out_)
 	}
 
-	return OnWithStreamHandle(ctx, output, handlers, cpy, handle)
+	return OnWithStreamHandle(ctx, output, streamHandlers, cpy, handle)
+}
+
+// CtxErrorCategoryKey is the key of the components.ErrorCategory of the error in the context, set on OnError.
+type CtxErrorCategoryKey struct{}
+
+// GetErrorCategory returns the category of the error in OnError.
+func GetErrorCategory(ctx context.Context) (components.ErrorCategory, bool) {
+	c, ok := ctx.Value(CtxErrorCategoryKey{}).(components.ErrorCategory)
+	return c, ok
 }
 
 func OnErrorHandle(ctx context.Context, err error,
 	runInfo *RunInfo, handlers []Handler) (context.Context, error) {
 
+	if len(handlers) > 0 {
+		ctx = context.WithValue(ctx, CtxErrorCategoryKey{}, components.ClassifyError(err))
+	}
+
 	for _, handler := range handlers {
 		ctx = handler.OnError(ctx, runInfo, err)
 	}
@@ -49,6 +49,15 @@ type Handler interface {
 
 type CallbackTiming uint8
 
+const (
+	TimingOnStart CallbackTiming = iota
+	TimingOnEnd
+	TimingOnError
+	TimingOnStartWithStreamInput
+	TimingOnEndWithStreamOutput
+	TimingOnStreamMetrics
+)
+
 type TimingChecker interface {
 	Needed(ctx context.Context, info *RunInfo, timing CallbackTiming) bool
 }
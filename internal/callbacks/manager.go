@@ -29,14 +29,12 @@ type manager struct {
 
 var GlobalHandlers []Handler
 
-func newManager(runInfo *RunInfo, handlers ...Handler) (*manager, bool) {
-	if len(handlers)+len(GlobalHandlers) == 0 {
+func newManager(ctx context.Context, runInfo *RunInfo, handlers ...Handler) (*manager, bool) {
+	hs := globalHandlers(ctx)
+	if len(handlers)+len(hs) == 0 {
 		return nil, false
 	}
 
-	hs := make([]Handler, len(GlobalHandlers))
-	copy(hs, GlobalHandlers)
-
 	return &manager{
 		globalHandlers: hs,
 		handlers:       handlers,
@@ -68,3 +66,36 @@ func managerFromCtx(ctx context.Context) (*manager, bool) {
 
 	return nil, false
 }
+
+// CtxNodePathKey is the key of the path of the running graph node in the context, set by compose,
+// and the value implements GetPath() []string.
+type CtxNodePathKey struct{}
+
+// CtxLabelsKey is the key of the labels of the running component in the context.
+type CtxLabelsKey struct{}
+
+// WithLabels returns a context with the labels appended to the labels in the ctx.
+func WithLabels(ctx context.Context, labels ...string) context.Context {
+	if len(labels) == 0 {
+		return ctx
+	}
+	prev := LabelsFromCtx(ctx)
+	nl := make([]string, 0, len(prev)+len(labels))
+	nl = append(nl, prev...)
+	nl = append(nl, labels...)
+	return context.WithValue(ctx, CtxLabelsKey{}, nl)
+}
+
+// LabelsFromCtx returns the labels in the ctx.
+func LabelsFromCtx(ctx context.Context) []string {
+	labels, _ := ctx.Value(CtxLabelsKey{}).([]string)
+	return labels
+}
+
+// NodePathFromCtx returns the path of the running graph node in the ctx.
+func NodePathFromCtx(ctx context.Context) []string {
+	if p, ok := ctx.Value(CtxNodePathKey{}).(interface{ GetPath() []string }); ok && p != nil {
+		return p.GetPath()
+	}
+	return nil
+}
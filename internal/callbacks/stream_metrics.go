@@ -0,0 +1,121 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+type StreamMetrics struct {
+	// StartTime is the time the component started, i.e. the time of OnStart,
+	// or the time the stream output was returned if OnStart isn't called.
+	StartTime time.Time
+	// TimeToFirstChunk is the duration from StartTime to the first chunk, 0 if there's no chunk.
+	TimeToFirstChunk time.Duration
+	// Duration is the duration from StartTime to the end of the stream.
+	Duration time.Duration
+	// ChunkCount is the number of the chunks.
+	ChunkCount int
+	// Bytes is the total size of the chunks, see StreamChunkSizer.
+	Bytes int
+	// Err is the error ending the stream, nil if the stream ends with io.EOF.
+	Err error
+}
+
+type StreamMetricsHandler interface {
+	OnStreamMetrics(ctx context.Context, info *RunInfo, metrics *StreamMetrics)
+}
+
+type StreamChunkSizer interface {
+	StreamChunkSize() int
+}
+
+func needStreamMetrics(ctx context.Context, info *RunInfo, handler Handler) bool {
+	if _, ok := handler.(StreamMetricsHandler); !ok {
+		return false
+	}
+	checker, ok := handler.(TimingChecker)
+	return !ok || checker.Needed(ctx, info, TimingOnStreamMetrics)
+}
+
+// measureStream reads the stream to the end in a goroutine, and delivers the metrics to the handlers.
+func measureStream[T any](ctx context.Context, info *RunInfo, sr *schema.StreamReader[T], handlers []StreamMetricsHandler) {
+	startTime := time.Now()
+	if t, ok := GetRunTiming(ctx); ok {
+		startTime = t.StartTime
+	}
+
+	go func() {
+		defer func() {
+			// a panicking handler must not crash the process, as it's out of the goroutine of the component
+			_ = recover()
+		}()
+		defer sr.Close()
+
+		metrics := &StreamMetrics{StartTime: startTime}
+		for {
+			chunk, err := sr.Recv()
+			if err != nil {
+				if err != io.EOF {
+					metrics.Err = err
+				}
+				break
+			}
+			if metrics.ChunkCount == 0 {
+				metrics.TimeToFirstChunk = time.Since(startTime)
+			}
+			metrics.ChunkCount++
+			metrics.Bytes += streamChunkSize(chunk)
+		}
+		metrics.Duration = time.Since(startTime)
+
+		for _, h := range handlers {
+			h.OnStreamMetrics(ctx, info, metrics)
+		}
+	}()
+}
+
+func streamChunkSize(chunk any) int {
+	switch c := chunk.(type) {
+	case StreamChunkSizer:
+		return c.StreamChunkSize()
+	case string:
+		return len(c)
+	case []byte:
+		return len(c)
+	case *schema.Message:
+		return MessageSize(c)
+	default:
+		return 0
+	}
+}
+
+// MessageSize returns the size of the texts of the message, i.e. the content, the reasoning content and the arguments of the tool calls.
+func MessageSize(m *schema.Message) int {
+	if m == nil {
+		return 0
+	}
+	size := len(m.Content) + len(m.ReasoningContent)
+	for _, tc := range m.ToolCalls {
+		size += len(tc.Function.Arguments)
+	}
+	return size
+}
@@ -0,0 +1,94 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package callbacks
+
+import (
+	"context"
+	"time"
+)
+
+// CtxRunTimingKey is the key of the *RunTiming of the running component in the context, set on OnStart.
+type CtxRunTimingKey struct{}
+
+type ctxPendingTimingKey struct{}
+
+type RunTiming struct {
+	// StartTime is the time of OnStart.
+	StartTime time.Time
+	// Duration is the duration from OnStart to OnEnd or OnError, 0 on OnStart.
+	// For the stream output, it's the duration until the stream is returned.
+	Duration time.Duration
+	// Attempt is the 1-based number of the attempt if the component is retried, 0 if unknown.
+	Attempt int
+	// QueueWait is the time waited before the component started, e.g. for a limiter.
+	QueueWait time.Duration
+}
+
+// pendingTiming is the timing reported before the component starts, consumed by the next OnStart.
+type pendingTiming struct {
+	attempt   int
+	queueWait time.Duration
+}
+
+// WithRunAttempt reports the attempt number of the component to start with the ctx.
+func WithRunAttempt(ctx context.Context, attempt int) context.Context {
+	p := getPendingTiming(ctx)
+	p.attempt = attempt
+	return context.WithValue(ctx, ctxPendingTimingKey{}, &p)
+}
+
+// WithQueueWait adds the time waited to the queue wait of the component to start with the ctx.
+func WithQueueWait(ctx context.Context, d time.Duration) context.Context {
+	p := getPendingTiming(ctx)
+	p.queueWait += d
+	return context.WithValue(ctx, ctxPendingTimingKey{}, &p)
+}
+
+func getPendingTiming(ctx context.Context) pendingTiming {
+	if p, ok := ctx.Value(ctxPendingTimingKey{}).(*pendingTiming); ok && p != nil {
+		return *p
+	}
+	return pendingTiming{}
+}
+
+// GetRunTiming returns the timing of the running component.
+func GetRunTiming(ctx context.Context) (*RunTiming, bool) {
+	t, ok := ctx.Value(CtxRunTimingKey{}).(*RunTiming)
+	return t, ok && t != nil
+}
+
+// startTiming stamps the timing on OnStart, consuming the pending timing so the nested components don't inherit it.
+func startTiming(ctx context.Context) context.Context {
+	t := &RunTiming{StartTime: time.Now()}
+	if p, ok := ctx.Value(ctxPendingTimingKey{}).(*pendingTiming); ok && p != nil {
+		t.Attempt = p.attempt
+		t.QueueWait = p.queueWait
+		ctx = context.WithValue(ctx, ctxPendingTimingKey{}, (*pendingTiming)(nil))
+	}
+	return context.WithValue(ctx, CtxRunTimingKey{}, t)
+}
+
+// endTiming returns the ctx with the duration measured on OnEnd and OnError.
+func endTiming(ctx context.Context) context.Context {
+	t, ok := GetRunTiming(ctx)
+	if !ok {
+		return ctx
+	}
+	ended := *t
+	ended.Duration = time.Since(t.StartTime)
+	return context.WithValue(ctx, CtxRunTimingKey{}, &ended)
+}
@@ -0,0 +1,432 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schemavalidate
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/eino-contrib/jsonschema"
+)
+
+// ValidationError is a violation of the schema found in the JSON data.
+type ValidationError struct {
+	// Path locates the violating value in the data, e.g. "$.items[0].name".
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validate checks the JSON data against the schema, and returns all the violations found.
+// It supports the commonly used keywords of JSON schema draft 2020-12, including types, enum, const,
+// object properties, array items, numeric and length bounds, patterns, local $ref and the combining keywords.
+// Annotations such as format are not checked.
+func Validate(s *jsonschema.Schema, data string) []*ValidationError {
+	dec := json.NewDecoder(strings.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return []*ValidationError{{Path: "$", Message: fmt.Sprintf("invalid JSON: %v", err)}}
+	}
+	if dec.More() {
+		return []*ValidationError{{Path: "$", Message: "invalid JSON: unexpected data after the top-level value"}}
+	}
+
+	vd := &validator{root: s, boolSchemas: make(map[*jsonschema.Schema]*bool)}
+	return vd.validate(s, v, "$", 0)
+}
+
+// maxRefDepth stops infinite recursion of self-referencing schemas.
+const maxRefDepth = 64
+
+type validator struct {
+	root        *jsonschema.Schema
+	boolSchemas map[*jsonschema.Schema]*bool
+}
+
+func (vd *validator) validate(s *jsonschema.Schema, v any, path string, depth int) (errs []*ValidationError) {
+	if s == nil {
+		return nil
+	}
+	if b := vd.boolSchema(s); b != nil {
+		if !*b {
+			return []*ValidationError{{Path: path, Message: "no value is allowed"}}
+		}
+		return nil
+	}
+
+	fail := func(format string, args ...any) {
+		errs = append(errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.Ref != "" {
+		if depth >= maxRefDepth {
+			fail("$ref %s nests too deep", s.Ref)
+			return errs
+		}
+		ref, ok := vd.resolve(s.Ref)
+		if !ok {
+			fail("unresolvable $ref %s", s.Ref)
+			return errs
+		}
+		errs = append(errs, vd.validate(ref, v, path, depth+1)...)
+	}
+
+	types := s.TypeEnhanced
+	if s.Type != "" {
+		types = []string{s.Type}
+	}
+	if len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if isType(v, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("expected %s, got %s", strings.Join(types, " or "), typeOf(v))
+			// the other keywords are meaningless for a value of a wrong type
+			return errs
+		}
+	}
+
+	if len(s.Enum) > 0 {
+		found := false
+		for _, e := range s.Enum {
+			if jsonEqual(v, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			fail("value %s is not one of %s", marshal(v), marshal(s.Enum))
+		}
+	}
+	if s.Const != nil && !jsonEqual(v, s.Const) {
+		fail("value %s is not %s", marshal(v), marshal(s.Const))
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		errs = append(errs, vd.validateObject(s, val, path, depth)...)
+	case []any:
+		errs = append(errs, vd.validateArray(s, val, path, depth)...)
+	case string:
+		n := uint64(utf8.RuneCountInString(val))
+		if s.MinLength != nil && n < *s.MinLength {
+			fail("length %d is less than minLength %d", n, *s.MinLength)
+		}
+		if s.MaxLength != nil && n > *s.MaxLength {
+			fail("length %d is greater than maxLength %d", n, *s.MaxLength)
+		}
+		if s.Pattern != "" {
+			if re, err := regexp.Compile(s.Pattern); err == nil && !re.MatchString(val) {
+				fail("%q doesn't match pattern %s", val, s.Pattern)
+			}
+		}
+	case json.Number:
+		errs = append(errs, validateNumber(s, val, path)...)
+	}
+
+	for _, sub := range s.AllOf {
+		errs = append(errs, vd.validate(sub, v, path, depth)...)
+	}
+	if len(s.AnyOf) > 0 {
+		matched := false
+		for _, sub := range s.AnyOf {
+			if len(vd.validate(sub, v, path, depth)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("value doesn't match any schema of anyOf")
+		}
+	}
+	if len(s.OneOf) > 0 {
+		matched := 0
+		for _, sub := range s.OneOf {
+			if len(vd.validate(sub, v, path, depth)) == 0 {
+				matched++
+			}
+		}
+		if matched != 1 {
+			fail("value matches %d schemas of oneOf, expected exactly 1", matched)
+		}
+	}
+	if s.Not != nil && len(vd.validate(s.Not, v, path, depth)) == 0 {
+		fail("value must not match the schema of not")
+	}
+	if s.If != nil {
+		if len(vd.validate(s.If, v, path, depth)) == 0 {
+			errs = append(errs, vd.validate(s.Then, v, path, depth)...)
+		} else {
+			errs = append(errs, vd.validate(s.Else, v, path, depth)...)
+		}
+	}
+
+	return errs
+}
+
+func (vd *validator) validateObject(s *jsonschema.Schema, obj map[string]any, path string, depth int) (errs []*ValidationError) {
+	fail := func(format string, args ...any) {
+		errs = append(errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, key := range s.Required {
+		if _, ok := obj[key]; !ok {
+			fail("missing required property %q", key)
+		}
+	}
+	for key, deps := range s.DependentRequired {
+		if _, ok := obj[key]; !ok {
+			continue
+		}
+		for _, dep := range deps {
+			if _, ok := obj[dep]; !ok {
+				fail("property %q is required when %q is present", dep, key)
+			}
+		}
+	}
+	n := uint64(len(obj))
+	if s.MinProperties != nil && n < *s.MinProperties {
+		fail("has %d properties, less than minProperties %d", n, *s.MinProperties)
+	}
+	if s.MaxProperties != nil && n > *s.MaxProperties {
+		fail("has %d properties, more than maxProperties %d", n, *s.MaxProperties)
+	}
+
+	for _, key := range sortedKeys(obj) {
+		val := obj[key]
+		propPath := path + "." + key
+		matched := false
+		if s.Properties != nil {
+			if prop, ok := s.Properties.Get(key); ok {
+				matched = true
+				errs = append(errs, vd.validate(prop, val, propPath, depth)...)
+			}
+		}
+		for pattern, prop := range s.PatternProperties {
+			if re, err := regexp.Compile(pattern); err == nil && re.MatchString(key) {
+				matched = true
+				errs = append(errs, vd.validate(prop, val, propPath, depth)...)
+			}
+		}
+		if matched || s.AdditionalProperties == nil {
+			continue
+		}
+		if b := vd.boolSchema(s.AdditionalProperties); b != nil && !*b {
+			fail("additional property %q is not allowed", key)
+			continue
+		}
+		errs = append(errs, vd.validate(s.AdditionalProperties, val, propPath, depth)...)
+	}
+
+	return errs
+}
+
+func (vd *validator) validateArray(s *jsonschema.Schema, arr []any, path string, depth int) (errs []*ValidationError) {
+	fail := func(format string, args ...any) {
+		errs = append(errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+
+	n := uint64(len(arr))
+	if s.MinItems != nil && n < *s.MinItems {
+		fail("has %d items, less than minItems %d", n, *s.MinItems)
+	}
+	if s.MaxItems != nil && n > *s.MaxItems {
+		fail("has %d items, more than maxItems %d", n, *s.MaxItems)
+	}
+	if s.UniqueItems {
+	outer:
+		for i := range arr {
+			for j := i + 1; j < len(arr); j++ {
+				if jsonEqual(arr[i], arr[j]) {
+					fail("items %d and %d are equal, but items must be unique", i, j)
+					break outer
+				}
+			}
+		}
+	}
+
+	for i, item := range arr {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		if i < len(s.PrefixItems) {
+			errs = append(errs, vd.validate(s.PrefixItems[i], item, itemPath, depth)...)
+			continue
+		}
+		errs = append(errs, vd.validate(s.Items, item, itemPath, depth)...)
+	}
+	if s.Contains != nil {
+		matched := false
+		for _, item := range arr {
+			if len(vd.validate(s.Contains, item, path, depth)) == 0 {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			fail("no item matches the schema of contains")
+		}
+	}
+
+	return errs
+}
+
+func validateNumber(s *jsonschema.Schema, n json.Number, path string) (errs []*ValidationError) {
+	f, err := n.Float64()
+	if err != nil {
+		return []*ValidationError{{Path: path, Message: fmt.Sprintf("invalid number %s", n)}}
+	}
+	check := func(bound json.Number, violated func(b float64) bool, format string) {
+		if bound == "" {
+			return
+		}
+		b, err := bound.Float64()
+		if err != nil || !violated(b) {
+			return
+		}
+		errs = append(errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, n, bound)})
+	}
+	check(s.Minimum, func(b float64) bool { return f < b }, "%s is less than minimum %s")
+	check(s.Maximum, func(b float64) bool { return f > b }, "%s is greater than maximum %s")
+	check(s.ExclusiveMinimum, func(b float64) bool { return f <= b }, "%s is not greater than exclusiveMinimum %s")
+	check(s.ExclusiveMaximum, func(b float64) bool { return f >= b }, "%s is not less than exclusiveMaximum %s")
+	check(s.MultipleOf, func(b float64) bool {
+		if b == 0 {
+			return false
+		}
+		q := f / b
+		return math.Abs(q-math.Round(q)) > 1e-9
+	}, "%s is not a multiple of %s")
+	return errs
+}
+
+// resolve resolves the local references to the root schema or its definitions.
+func (vd *validator) resolve(ref string) (*jsonschema.Schema, bool) {
+	if ref == "#" {
+		return vd.root, true
+	}
+	for _, prefix := range []string{"#/$defs/", "#/definitions/"} {
+		if strings.HasPrefix(ref, prefix) {
+			s, ok := vd.root.Definitions[strings.TrimPrefix(ref, prefix)]
+			return s, ok && s != nil
+		}
+	}
+	return nil, false
+}
+
+// boolSchema returns the value of a boolean schema, e.g. `"additionalProperties": false`, or nil if it's not one.
+func (vd *validator) boolSchema(s *jsonschema.Schema) *bool {
+	if b, ok := vd.boolSchemas[s]; ok {
+		return b
+	}
+	var b *bool
+	switch data, _ := json.Marshal(s); string(data) {
+	case "true":
+		b = &[]bool{true}[0]
+	case "false":
+		b = &[]bool{false}[0]
+	}
+	vd.boolSchemas[s] = b
+	return b
+}
+
+func isType(v any, t string) bool {
+	switch t {
+	case "object":
+		_, ok := v.(map[string]any)
+		return ok
+	case "array":
+		_, ok := v.([]any)
+		return ok
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "number":
+		_, ok := v.(json.Number)
+		return ok
+	case "integer":
+		n, ok := v.(json.Number)
+		if !ok {
+			return false
+		}
+		f, err := n.Float64()
+		return err == nil && f == math.Trunc(f)
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "null":
+		return v == nil
+	}
+	return false
+}
+
+func typeOf(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case json.Number:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+// jsonEqual compares two values by their JSON representations, so that numbers of different go types can be compared.
+func jsonEqual(a, b any) bool {
+	var na, nb any
+	if json.Unmarshal([]byte(marshal(a)), &na) != nil || json.Unmarshal([]byte(marshal(b)), &nb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(na, nb)
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func marshal(v any) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(data)
+}
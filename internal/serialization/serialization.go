@@ -151,6 +151,13 @@ func unmarshal(data []byte, t reflect.Type) (any, error) {
 	return internalUnmarshal(is, t)
 }
 
+// Envelope is the JSON structure InternalSerializer marshals a value to, e.g. a checkpoint,
+// in which each value carries its type so that it can be restored to the registered Go type.
+type Envelope = internalStruct
+
+// EnvelopeType is the type of a value in the Envelope.
+type EnvelopeType = valueType
+
 type internalStruct struct {
 	Type *valueType `json:",omitempty"`
 
@@ -0,0 +1,100 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nikolalohinski/gonja"
+	"github.com/nikolalohinski/gonja/config"
+)
+
+// Jinja2Loader loads the templates referenced by the include, extends, import and from statements of Jinja2 templates by their names.
+// These statements are disabled unless a loader is set by WithJinja2Loader, so that templates can't read arbitrary files.
+type Jinja2Loader interface {
+	Load(name string) (string, error)
+}
+
+// Jinja2LoaderFunc is the function adapter of Jinja2Loader.
+type Jinja2LoaderFunc func(name string) (string, error)
+
+// Load calls f(name).
+func (f Jinja2LoaderFunc) Load(name string) (string, error) {
+	return f(name)
+}
+
+// MapJinja2Loader loads the templates from a map of the names to the sources, e.g. a prompt library embedded in the binary.
+type MapJinja2Loader map[string]string
+
+// Load returns the source of the template, or an error if it's absent.
+func (m MapJinja2Loader) Load(name string) (string, error) {
+	source, ok := m[name]
+	if !ok {
+		return "", fmt.Errorf("jinja2 template[%s] not found", name)
+	}
+	return source, nil
+}
+
+type jinja2LoaderKey struct{}
+
+// WithJinja2Loader returns a context with the loader, which enables the include, extends, import and from statements
+// of the Jinja2 templates formatted with the context, e.g. by Message.Format.
+// e.g.
+//
+//	loader := schema.MapJinja2Loader{
+//		"base.j2":  "You are {{ role }}. {% block rules %}{% endblock %}",
+//		"macros.j2": "{% macro item(x) %}- {{ x }}{% endmacro %}",
+//	}
+//	msg := schema.SystemMessage(`{% extends "base.j2" %}{% block rules %}{% from "macros.j2" import item %}{% for r in rules %}{{ item(r) }}{% endfor %}{% endblock %}`)
+//	msgs, err := msg.Format(schema.WithJinja2Loader(ctx, loader), vs, schema.Jinja2)
+func WithJinja2Loader(ctx context.Context, loader Jinja2Loader) context.Context {
+	return context.WithValue(ctx, jinja2LoaderKey{}, loader)
+}
+
+func getJinja2Loader(ctx context.Context) Jinja2Loader {
+	if ctx == nil {
+		return nil
+	}
+	loader, _ := ctx.Value(jinja2LoaderKey{}).(Jinja2Loader)
+	return loader
+}
+
+// newJinjaEnvWithLoader creates an environment resolving the referenced templates by the loader.
+// The environment caches the parsed templates, so it's created per format to pick up the changes of the loader.
+func newJinjaEnvWithLoader(loader Jinja2Loader) *gonja.Environment {
+	return gonja.NewEnvironment(config.DefaultConfig, &gonjaLoader{loader: loader})
+}
+
+// gonjaLoader adapts Jinja2Loader to the loader of gonja, with the names of the templates as their paths.
+type gonjaLoader struct {
+	loader Jinja2Loader
+}
+
+func (g *gonjaLoader) Get(path string) (io.Reader, error) {
+	source, err := g.loader.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return strings.NewReader(source), nil
+}
+
+func (g *gonjaLoader) Path(path string) (string, error) {
+	return path, nil
+}
@@ -0,0 +1,89 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJinja2Loader(t *testing.T) {
+	ctx := context.Background()
+	vs := map[string]any{"role": "a reviewer", "rules": []string{"be kind", "be brief"}, "strict": true}
+
+	t.Run("control structures without loader", func(t *testing.T) {
+		msg := UserMessage(`{% macro item(x) %}- {{ x | upper }}{% endmacro %}{% for r in rules %}{{ item(r) }}{% if not loop.last %}
+{% endif %}{% endfor %}{% if strict %} (strict){% else %} (lenient){% endif %}`)
+		msgs, err := msg.Format(ctx, vs, Jinja2)
+		assert.NoError(t, err)
+		assert.Equal(t, "- BE KIND\n- BE BRIEF (strict)", msgs[0].Content)
+	})
+
+	loader := MapJinja2Loader{
+		"base.j2":   "You are {{ role }}.{% block rules %}{% endblock %}",
+		"macros.j2": "{% macro item(x) %} [{{ x }}]{% endmacro %}",
+		"footer.j2": " Reply in {{ lang | default('English') }}.",
+	}
+
+	for name, tc := range map[string]struct {
+		content string
+		want    string
+	}{
+		"extends": {
+			content: `{% extends "base.j2" %}{% block rules %} Rules:{% for r in rules %} {{ r }};{% endfor %}{% endblock %}`,
+			want:    "You are a reviewer. Rules: be kind; be brief;",
+		},
+		"include": {
+			content: `Hi.{% include "footer.j2" %}`,
+			want:    "Hi. Reply in English.",
+		},
+		"import": {
+			content: `{% import "macros.j2" as m %}Rules:{% for r in rules %}{{ m.item(r) }}{% endfor %}`,
+			want:    "Rules: [be kind] [be brief]",
+		},
+		"from": {
+			content: `{% from "macros.j2" import item %}Rules:{{ item(rules[0]) }}`,
+			want:    "Rules: [be kind]",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			msgs, err := SystemMessage(tc.content).Format(WithJinja2Loader(ctx, loader), vs, Jinja2)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, msgs[0].Content)
+
+			// disabled without loader
+			_, err = SystemMessage(tc.content).Format(ctx, vs, Jinja2)
+			assert.ErrorContains(t, err, "has been disabled")
+		})
+	}
+
+	t.Run("missing template", func(t *testing.T) {
+		_, err := UserMessage(`{% include "absent.j2" %}`).Format(WithJinja2Loader(ctx, loader), vs, Jinja2)
+		assert.ErrorContains(t, err, "absent.j2")
+	})
+
+	t.Run("loader func", func(t *testing.T) {
+		l := Jinja2LoaderFunc(func(name string) (string, error) {
+			return "<" + name + ">", nil
+		})
+		msgs, err := UserMessage(`{% include "x" %}`).Format(WithJinja2Loader(ctx, l), vs, Jinja2)
+		assert.NoError(t, err)
+		assert.Equal(t, "<x>", msgs[0].Content)
+	})
+}
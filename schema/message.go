@@ -88,6 +88,7 @@ const (
 	// GoTemplate https://pkg.go.dev/text/template.
 	GoTemplate FormatType = 1
 	// Jinja2 Supported by gonja(github.com/nikolalohinski/gonja), which is a implementation of https://jinja.palletsprojects.com/en/3.1.x/templates/.
+	// The include, extends, import and from statements are enabled by WithJinja2Loader.
 	Jinja2 FormatType = 2
 )
 
@@ -474,15 +475,23 @@ type TokenUsage struct {
 	PromptTokenDetails PromptTokenDetails `json:"prompt_token_details"`
 	// CompletionTokens is the number of completion tokens.
 	CompletionTokens int `json:"completion_tokens"`
+	// CompletionTokenDetails is a breakdown of the completion tokens.
+	CompletionTokenDetails CompletionTokenDetails `json:"completion_token_details"`
 	// TotalTokens is the total number of tokens.
 	TotalTokens int `json:"total_tokens"`
 }
 
 type PromptTokenDetails struct {
-	// Cached tokens present in the prompt.
+	// Cached tokens present in the prompt, which are counted in PromptTokens as well.
+	// For providers reporting the cache reads apart from the input tokens, implementations add them up to PromptTokens.
 	CachedTokens int `json:"cached_tokens"`
 }
 
+type CompletionTokenDetails struct {
+	// Reasoning tokens present in the completion.
+	ReasoningTokens int `json:"reasoning_tokens,omitempty"`
+}
+
 var _ MessagesTemplate = &Message{}
 var _ MessagesTemplate = MessagesPlaceholder("", false)
 
@@ -552,7 +561,7 @@ func (p *messagesPlaceholder) Format(_ context.Context, vs map[string]any, _ For
 	return msgs, nil
 }
 
-func formatContent(content string, vs map[string]any, formatType FormatType) (string, error) {
+func formatContent(ctx context.Context, content string, vs map[string]any, formatType FormatType) (string, error) {
 	switch formatType {
 	case FString:
 		return pyfmt.Fmt(content, vs)
@@ -574,6 +583,9 @@ func formatContent(content string, vs map[string]any, formatType FormatType) (st
 		if err != nil {
 			return "", err
 		}
+		if loader := getJinja2Loader(ctx); loader != nil {
+			env = newJinjaEnvWithLoader(loader)
+		}
 		tpl, err := env.FromString(content)
 		if err != nil {
 			return "", err
@@ -594,8 +606,8 @@ func formatContent(content string, vs map[string]any, formatType FormatType) (st
 //	msg := schema.UserMessage("hello world, {name}")
 //	msgs, err := msg.Format(ctx, map[string]any{"name": "eino"}, schema.FString) // <= this will render the content of msg by pyfmt
 //	// msgs[0].Content will be "hello world, eino"
-func (m *Message) Format(_ context.Context, vs map[string]any, formatType FormatType) ([]*Message, error) {
-	c, err := formatContent(m.Content, vs, formatType)
+func (m *Message) Format(ctx context.Context, vs map[string]any, formatType FormatType) ([]*Message, error) {
+	c, err := formatContent(ctx, m.Content, vs, formatType)
 	if err != nil {
 		return nil, err
 	}
@@ -603,14 +615,14 @@ func (m *Message) Format(_ context.Context, vs map[string]any, formatType Format
 	copied.Content = c
 
 	if len(m.MultiContent) > 0 {
-		copied.MultiContent, err = formatMultiContent(m.MultiContent, vs, formatType)
+		copied.MultiContent, err = formatMultiContent(ctx, m.MultiContent, vs, formatType)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	if len(m.UserInputMultiContent) > 0 {
-		copied.UserInputMultiContent, err = formatUserInputMultiContent(m.UserInputMultiContent, vs, formatType)
+		copied.UserInputMultiContent, err = formatUserInputMultiContent(ctx, m.UserInputMultiContent, vs, formatType)
 		if err != nil {
 			return nil, err
 		}
@@ -619,14 +631,14 @@ func (m *Message) Format(_ context.Context, vs map[string]any, formatType Format
 	return []*Message{&copied}, nil
 }
 
-func formatMultiContent(multiContent []ChatMessagePart, vs map[string]any, formatType FormatType) ([]ChatMessagePart, error) {
+func formatMultiContent(ctx context.Context, multiContent []ChatMessagePart, vs map[string]any, formatType FormatType) ([]ChatMessagePart, error) {
 	copiedMC := make([]ChatMessagePart, len(multiContent))
 	copy(copiedMC, multiContent)
 
 	for i, mc := range copiedMC {
 		switch mc.Type {
 		case ChatMessagePartTypeText:
-			nmc, err := formatContent(mc.Text, vs, formatType)
+			nmc, err := formatContent(ctx, mc.Text, vs, formatType)
 			if err != nil {
 				return nil, err
 			}
@@ -635,7 +647,7 @@ func formatMultiContent(multiContent []ChatMessagePart, vs map[string]any, forma
 			if mc.ImageURL == nil {
 				continue
 			}
-			url, err := formatContent(mc.ImageURL.URL, vs, formatType)
+			url, err := formatContent(ctx, mc.ImageURL.URL, vs, formatType)
 			if err != nil {
 				return nil, err
 			}
@@ -644,7 +656,7 @@ func formatMultiContent(multiContent []ChatMessagePart, vs map[string]any, forma
 			if mc.AudioURL == nil {
 				continue
 			}
-			url, err := formatContent(mc.AudioURL.URL, vs, formatType)
+			url, err := formatContent(ctx, mc.AudioURL.URL, vs, formatType)
 			if err != nil {
 				return nil, err
 			}
@@ -653,7 +665,7 @@ func formatMultiContent(multiContent []ChatMessagePart, vs map[string]any, forma
 			if mc.VideoURL == nil {
 				continue
 			}
-			url, err := formatContent(mc.VideoURL.URL, vs, formatType)
+			url, err := formatContent(ctx, mc.VideoURL.URL, vs, formatType)
 			if err != nil {
 				return nil, err
 			}
@@ -662,7 +674,7 @@ func formatMultiContent(multiContent []ChatMessagePart, vs map[string]any, forma
 			if mc.FileURL == nil {
 				continue
 			}
-			url, err := formatContent(mc.FileURL.URL, vs, formatType)
+			url, err := formatContent(ctx, mc.FileURL.URL, vs, formatType)
 			if err != nil {
 				return nil, err
 			}
@@ -670,17 +682,17 @@ func formatMultiContent(multiContent []ChatMessagePart, vs map[string]any, forma
 		}
 	}
 
-	return copiedMC, nil
+	return expandChatMessagePartsPlaceholders(copiedMC, vs)
 }
 
-func formatUserInputMultiContent(userInputMultiContent []MessageInputPart, vs map[string]any, formatType FormatType) ([]MessageInputPart, error) {
+func formatUserInputMultiContent(ctx context.Context, userInputMultiContent []MessageInputPart, vs map[string]any, formatType FormatType) ([]MessageInputPart, error) {
 	copiedUIMC := make([]MessageInputPart, len(userInputMultiContent))
 	copy(copiedUIMC, userInputMultiContent)
 
 	for i, uimc := range copiedUIMC {
 		switch uimc.Type {
 		case ChatMessagePartTypeText:
-			text, err := formatContent(uimc.Text, vs, formatType)
+			text, err := formatContent(ctx, uimc.Text, vs, formatType)
 			if err != nil {
 				return nil, err
 			}
@@ -690,14 +702,14 @@ func formatUserInputMultiContent(userInputMultiContent []MessageInputPart, vs ma
 				continue
 			}
 			if uimc.Image.URL != nil && *uimc.Image.URL != "" {
-				url, err := formatContent(*uimc.Image.URL, vs, formatType)
+				url, err := formatContent(ctx, *uimc.Image.URL, vs, formatType)
 				if err != nil {
 					return nil, err
 				}
 				copiedUIMC[i].Image.URL = &url
 			}
 			if uimc.Image.Base64Data != nil && *uimc.Image.Base64Data != "" {
-				base64data, err := formatContent(*uimc.Image.Base64Data, vs, formatType)
+				base64data, err := formatContent(ctx, *uimc.Image.Base64Data, vs, formatType)
 				if err != nil {
 					return nil, err
 				}
@@ -708,14 +720,14 @@ func formatUserInputMultiContent(userInputMultiContent []MessageInputPart, vs ma
 				continue
 			}
 			if uimc.Audio.URL != nil && *uimc.Audio.URL != "" {
-				url, err := formatContent(*uimc.Audio.URL, vs, formatType)
+				url, err := formatContent(ctx, *uimc.Audio.URL, vs, formatType)
 				if err != nil {
 					return nil, err
 				}
 				copiedUIMC[i].Audio.URL = &url
 			}
 			if uimc.Audio.Base64Data != nil && *uimc.Audio.Base64Data != "" {
-				base64data, err := formatContent(*uimc.Audio.Base64Data, vs, formatType)
+				base64data, err := formatContent(ctx, *uimc.Audio.Base64Data, vs, formatType)
 				if err != nil {
 					return nil, err
 				}
@@ -726,14 +738,14 @@ func formatUserInputMultiContent(userInputMultiContent []MessageInputPart, vs ma
 				continue
 			}
 			if uimc.Video.URL != nil && *uimc.Video.URL != "" {
-				url, err := formatContent(*uimc.Video.URL, vs, formatType)
+				url, err := formatContent(ctx, *uimc.Video.URL, vs, formatType)
 				if err != nil {
 					return nil, err
 				}
 				copiedUIMC[i].Video.URL = &url
 			}
 			if uimc.Video.Base64Data != nil && *uimc.Video.Base64Data != "" {
-				base64data, err := formatContent(*uimc.Video.Base64Data, vs, formatType)
+				base64data, err := formatContent(ctx, *uimc.Video.Base64Data, vs, formatType)
 				if err != nil {
 					return nil, err
 				}
@@ -744,14 +756,14 @@ func formatUserInputMultiContent(userInputMultiContent []MessageInputPart, vs ma
 				continue
 			}
 			if uimc.File.URL != nil && *uimc.File.URL != "" {
-				url, err := formatContent(*uimc.File.URL, vs, formatType)
+				url, err := formatContent(ctx, *uimc.File.URL, vs, formatType)
 				if err != nil {
 					return nil, err
 				}
 				copiedUIMC[i].File.URL = &url
 			}
 			if uimc.File.Base64Data != nil && *uimc.File.Base64Data != "" {
-				base64data, err := formatContent(*uimc.File.Base64Data, vs, formatType)
+				base64data, err := formatContent(ctx, *uimc.File.Base64Data, vs, formatType)
 				if err != nil {
 					return nil, err
 				}
@@ -760,7 +772,7 @@ func formatUserInputMultiContent(userInputMultiContent []MessageInputPart, vs ma
 		}
 	}
 
-	return copiedUIMC, nil
+	return expandInputPartsPlaceholders(copiedUIMC, vs)
 }
 
 // String returns the string representation of the message.
@@ -1186,6 +1198,10 @@ func ConcatMessages(msgs []*Message) (*Message, error) {
 				if msg.ResponseMeta.Usage.PromptTokenDetails.CachedTokens > ret.ResponseMeta.Usage.PromptTokenDetails.CachedTokens {
 					ret.ResponseMeta.Usage.PromptTokenDetails.CachedTokens = msg.ResponseMeta.Usage.PromptTokenDetails.CachedTokens
 				}
+
+				if msg.ResponseMeta.Usage.CompletionTokenDetails.ReasoningTokens > ret.ResponseMeta.Usage.CompletionTokenDetails.ReasoningTokens {
+					ret.ResponseMeta.Usage.CompletionTokenDetails.ReasoningTokens = msg.ResponseMeta.Usage.CompletionTokenDetails.ReasoningTokens
+				}
 			}
 
 			if msg.ResponseMeta.LogProbs != nil {
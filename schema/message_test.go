@@ -159,6 +159,9 @@ func TestConcatMessage(t *testing.T) {
 					PromptTokenDetails: PromptTokenDetails{
 						CachedTokens: 15,
 					},
+					CompletionTokenDetails: CompletionTokenDetails{
+						ReasoningTokens: 5,
+					},
 					TotalTokens: 45,
 				},
 			},
@@ -197,6 +200,9 @@ func TestConcatMessage(t *testing.T) {
 						PromptTokenDetails: PromptTokenDetails{
 							CachedTokens: 15,
 						},
+						CompletionTokenDetails: CompletionTokenDetails{
+							ReasoningTokens: 5,
+						},
 						TotalTokens: 45,
 					},
 				},
@@ -906,7 +912,7 @@ func TestFormatMultiContent(t *testing.T) {
 	}
 
 	t.Run("empty input", func(t *testing.T) {
-		out, err := formatMultiContent(nil, vs, FString)
+		out, err := formatMultiContent(context.Background(), nil, vs, FString)
 		assert.NoError(t, err)
 		assert.Equal(t, []ChatMessagePart{}, out)
 	})
@@ -920,7 +926,7 @@ func TestFormatMultiContent(t *testing.T) {
 			{Type: ChatMessagePartTypeFileURL, FileURL: &ChatMessageFileURL{URL: "http://file/{id}.txt"}},
 		}
 
-		out, err := formatMultiContent(in, vs, FString)
+		out, err := formatMultiContent(context.Background(), in, vs, FString)
 		assert.NoError(t, err)
 		if assert.Len(t, out, len(in)) {
 			assert.Equal(t, "hello eino", out[0].Text)
@@ -938,14 +944,14 @@ func TestFormatMultiContent(t *testing.T) {
 			{Type: ChatMessagePartTypeVideoURL, VideoURL: nil},
 			{Type: ChatMessagePartTypeFileURL, FileURL: nil},
 		}
-		out, err := formatMultiContent(in, vs, FString)
+		out, err := formatMultiContent(context.Background(), in, vs, FString)
 		assert.NoError(t, err)
 		assert.Equal(t, in, out)
 	})
 
 	t.Run("missing var should error in GoTemplate", func(t *testing.T) {
 		in := []ChatMessagePart{{Type: ChatMessagePartTypeText, Text: "hi {{.who}}"}}
-		_, err := formatMultiContent(in, map[string]any{"name": "x"}, GoTemplate)
+		_, err := formatMultiContent(context.Background(), in, map[string]any{"name": "x"}, GoTemplate)
 		assert.Error(t, err)
 	})
 
@@ -964,7 +970,7 @@ func TestFormatUserInputMultiContent(t *testing.T) {
 	}
 
 	t.Run("empty input", func(t *testing.T) {
-		out, err := formatUserInputMultiContent(nil, vs, FString)
+		out, err := formatUserInputMultiContent(context.Background(), nil, vs, FString)
 		assert.NoError(t, err)
 		assert.Equal(t, []MessageInputPart{}, out)
 	})
@@ -978,7 +984,7 @@ func TestFormatUserInputMultiContent(t *testing.T) {
 			{Type: ChatMessagePartTypeFileURL, File: &MessageInputFile{MessagePartCommon: MessagePartCommon{URL: makeStrPtr("/f/{file}.txt"), Base64Data: makeStrPtr("{b64}")}}},
 		}
 
-		out, err := formatUserInputMultiContent(in, vs, FString)
+		out, err := formatUserInputMultiContent(context.Background(), in, vs, FString)
 		assert.NoError(t, err)
 		if assert.Len(t, out, len(in)) {
 			assert.Equal(t, "hello world", out[0].Text)
@@ -998,7 +1004,7 @@ func TestFormatUserInputMultiContent(t *testing.T) {
 		in := []MessageInputPart{
 			{Type: ChatMessagePartTypeImageURL, Image: &MessageInputImage{MessagePartCommon: MessagePartCommon{URL: &empty, Base64Data: &empty}}},
 		}
-		out, err := formatUserInputMultiContent(in, vs, FString)
+		out, err := formatUserInputMultiContent(context.Background(), in, vs, FString)
 		assert.NoError(t, err)
 		if assert.Len(t, out, 1) {
 			assert.NotNil(t, out[0].Image.URL)
@@ -0,0 +1,148 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const (
+	partTypePlaceholder         ChatMessagePartType = "_placeholder"
+	partTypeOptionalPlaceholder ChatMessagePartType = "_optional_placeholder"
+)
+
+// MessageInputPartsPlaceholder creates a part of the UserInputMultiContent of a message template,
+// which is replaced by the parts of the variable key when formatting, e.g. the images of a vision prompt.
+// The variable can be a string (a text part), *MessageInputImage, *MessageInputAudio, *MessageInputVideo, *MessageInputFile,
+// MessageInputPart, or []MessageInputPart. A missing or nil variable expands to no part if optional, or fails the format.
+// e.g.
+//
+//	template := &schema.Message{
+//		Role: schema.User,
+//		UserInputMultiContent: []schema.MessageInputPart{
+//			{Type: schema.ChatMessagePartTypeText, Text: "what's the difference between the pictures of {product}?"},
+//			schema.MessageInputPartsPlaceholder("pictures", false),
+//		},
+//	}
+//	msgs, err := template.Format(ctx, map[string]any{
+//		"product": "eino",
+//		"pictures": []schema.MessageInputPart{imagePart1, imagePart2},
+//	}, schema.FString)
+func MessageInputPartsPlaceholder(key string, optional bool) MessageInputPart {
+	return MessageInputPart{Type: placeholderPartType(optional), Text: key}
+}
+
+// Deprecated: This function is deprecated as the MultiContent field is deprecated, use MessageInputPartsPlaceholder instead.
+// ChatMessagePartsPlaceholder creates a part of the MultiContent of a message template,
+// which is replaced by the parts of the variable key when formatting.
+// The variable can be a string (a text part), *ChatMessageImageURL, *ChatMessageAudioURL, *ChatMessageVideoURL, *ChatMessageFileURL,
+// ChatMessagePart, or []ChatMessagePart. A missing or nil variable expands to no part if optional, or fails the format.
+func ChatMessagePartsPlaceholder(key string, optional bool) ChatMessagePart {
+	return ChatMessagePart{Type: placeholderPartType(optional), Text: key}
+}
+
+func placeholderPartType(optional bool) ChatMessagePartType {
+	if optional {
+		return partTypeOptionalPlaceholder
+	}
+	return partTypePlaceholder
+}
+
+func isPlaceholderPartType(t ChatMessagePartType) bool {
+	return t == partTypePlaceholder || t == partTypeOptionalPlaceholder
+}
+
+// lookupPartsVariable returns the variable of the placeholder part, nil if it's optional and missing.
+func lookupPartsVariable(t ChatMessagePartType, key string, vs map[string]any) (any, error) {
+	v, ok := vs[key]
+	if !ok || v == nil {
+		if t == partTypeOptionalPlaceholder {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("parts placeholder format: %s not found", key)
+	}
+	return v, nil
+}
+
+func expandInputPartsPlaceholders(parts []MessageInputPart, vs map[string]any) ([]MessageInputPart, error) {
+	ret := make([]MessageInputPart, 0, len(parts))
+	for _, part := range parts {
+		if !isPlaceholderPartType(part.Type) {
+			ret = append(ret, part)
+			continue
+		}
+		v, err := lookupPartsVariable(part.Type, part.Text, vs)
+		if err != nil {
+			return nil, err
+		}
+		switch pv := v.(type) {
+		case nil:
+		case string:
+			ret = append(ret, MessageInputPart{Type: ChatMessagePartTypeText, Text: pv})
+		case *MessageInputImage:
+			ret = append(ret, MessageInputPart{Type: ChatMessagePartTypeImageURL, Image: pv})
+		case *MessageInputAudio:
+			ret = append(ret, MessageInputPart{Type: ChatMessagePartTypeAudioURL, Audio: pv})
+		case *MessageInputVideo:
+			ret = append(ret, MessageInputPart{Type: ChatMessagePartTypeVideoURL, Video: pv})
+		case *MessageInputFile:
+			ret = append(ret, MessageInputPart{Type: ChatMessagePartTypeFileURL, File: pv})
+		case MessageInputPart:
+			ret = append(ret, pv)
+		case []MessageInputPart:
+			ret = append(ret, pv...)
+		default:
+			return nil, fmt.Errorf("unsupported type of parts placeholder, key: %v, actual type: %v", part.Text, reflect.TypeOf(v))
+		}
+	}
+	return ret, nil
+}
+
+func expandChatMessagePartsPlaceholders(parts []ChatMessagePart, vs map[string]any) ([]ChatMessagePart, error) {
+	ret := make([]ChatMessagePart, 0, len(parts))
+	for _, part := range parts {
+		if !isPlaceholderPartType(part.Type) {
+			ret = append(ret, part)
+			continue
+		}
+		v, err := lookupPartsVariable(part.Type, part.Text, vs)
+		if err != nil {
+			return nil, err
+		}
+		switch pv := v.(type) {
+		case nil:
+		case string:
+			ret = append(ret, ChatMessagePart{Type: ChatMessagePartTypeText, Text: pv})
+		case *ChatMessageImageURL:
+			ret = append(ret, ChatMessagePart{Type: ChatMessagePartTypeImageURL, ImageURL: pv})
+		case *ChatMessageAudioURL:
+			ret = append(ret, ChatMessagePart{Type: ChatMessagePartTypeAudioURL, AudioURL: pv})
+		case *ChatMessageVideoURL:
+			ret = append(ret, ChatMessagePart{Type: ChatMessagePartTypeVideoURL, VideoURL: pv})
+		case *ChatMessageFileURL:
+			ret = append(ret, ChatMessagePart{Type: ChatMessagePartTypeFileURL, FileURL: pv})
+		case ChatMessagePart:
+			ret = append(ret, pv)
+		case []ChatMessagePart:
+			ret = append(ret, pv...)
+		default:
+			return nil, fmt.Errorf("unsupported type of parts placeholder, key: %v, actual type: %v", part.Text, reflect.TypeOf(v))
+		}
+	}
+	return ret, nil
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageInputPartsPlaceholder(t *testing.T) {
+	ctx := context.Background()
+	url1, url2 := "https://example.com/1.png", "https://example.com/2.png"
+	image1 := &MessageInputImage{MessagePartCommon: MessagePartCommon{URL: &url1}}
+	image2 := &MessageInputImage{MessagePartCommon: MessagePartCommon{URL: &url2}}
+
+	template := &Message{
+		Role: User,
+		UserInputMultiContent: []MessageInputPart{
+			{Type: ChatMessagePartTypeText, Text: "compare the pictures of {product}"},
+			MessageInputPartsPlaceholder("pictures", false),
+			MessageInputPartsPlaceholder("note", true),
+		},
+	}
+
+	msgs, err := template.Format(ctx, map[string]any{
+		"product":  "eino",
+		"pictures": []MessageInputPart{{Type: ChatMessagePartTypeImageURL, Image: image1}, {Type: ChatMessagePartTypeImageURL, Image: image2}},
+	}, FString)
+	assert.NoError(t, err)
+	assert.Equal(t, []MessageInputPart{
+		{Type: ChatMessagePartTypeText, Text: "compare the pictures of eino"},
+		{Type: ChatMessagePartTypeImageURL, Image: image1},
+		{Type: ChatMessagePartTypeImageURL, Image: image2},
+	}, msgs[0].UserInputMultiContent)
+	assert.Len(t, template.UserInputMultiContent, 3)
+
+	msgs, err = template.Format(ctx, map[string]any{
+		"product":  "eino",
+		"pictures": image1,
+		"note":     "{not formatted}",
+	}, FString)
+	assert.NoError(t, err)
+	assert.Equal(t, []MessageInputPart{
+		{Type: ChatMessagePartTypeText, Text: "compare the pictures of eino"},
+		{Type: ChatMessagePartTypeImageURL, Image: image1},
+		{Type: ChatMessagePartTypeText, Text: "{not formatted}"},
+	}, msgs[0].UserInputMultiContent)
+
+	_, err = template.Format(ctx, map[string]any{"product": "eino"}, FString)
+	assert.ErrorContains(t, err, "pictures not found")
+	_, err = template.Format(ctx, map[string]any{"product": "eino", "pictures": 1}, FString)
+	assert.ErrorContains(t, err, "unsupported type")
+
+	t.Run("multi content", func(t *testing.T) {
+		template := &Message{
+			Role: User,
+			MultiContent: []ChatMessagePart{
+				ChatMessagePartsPlaceholder("audio", false),
+				{Type: ChatMessagePartTypeText, Text: "transcribe it in {{.lang}}"},
+			},
+		}
+		audio := &ChatMessageAudioURL{URL: "https://example.com/1.wav"}
+		msgs, err := template.Format(ctx, map[string]any{"audio": audio, "lang": "en"}, GoTemplate)
+		assert.NoError(t, err)
+		assert.Equal(t, []ChatMessagePart{
+			{Type: ChatMessagePartTypeAudioURL, AudioURL: audio},
+			{Type: ChatMessagePartTypeText, Text: "transcribe it in en"},
+		}, msgs[0].MultiContent)
+	})
+}
@@ -0,0 +1,356 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"fmt"
+	"strings"
+	"text/template/parse"
+
+	"github.com/nikolalohinski/gonja/tokens"
+)
+
+// TemplateVariable is a reference to a variable in a message template.
+type TemplateVariable struct {
+	// Name is the name of the variable, i.e. the key in the variables of the format.
+	Name string
+	// Field is where the variable is referenced, e.g. "content", "user_input_multi_content[1].text", or "placeholder".
+	Field string
+	// Position is the byte offset of the reference in the text of the field.
+	Position int
+	// Optional means the variable may be missing, e.g. the variable of an optional placeholder.
+	Optional bool
+}
+
+// VariablesTemplate is a MessagesTemplate able to report the variables it references before formatting,
+// implemented by *Message, MessagesPlaceholder and the parts placeholders.
+type VariablesTemplate interface {
+	MessagesTemplate
+	Variables(formatType FormatType) ([]TemplateVariable, error)
+}
+
+// Variables returns the variables referenced by the message in the formatType, in the order of the fields.
+// The variables of Jinja2 are found on a best-effort basis, the names bound by the statements in the template,
+// e.g. the targets of for, set and macro, are not reported.
+// e.g.
+//
+//	msg := schema.UserMessage("{greeting}, {user.name}")
+//	vars, err := msg.Variables(schema.FString) // <= vars: greeting at 0, user at 12
+func (m *Message) Variables(formatType FormatType) ([]TemplateVariable, error) {
+	var ret []TemplateVariable
+	add := func(field, text string) error {
+		vars, err := contentVariables(text, formatType)
+		if err != nil {
+			return fmt.Errorf("parse variables of %s fail: %w", field, err)
+		}
+		for _, v := range vars {
+			v.Field = field
+			ret = append(ret, v)
+		}
+		return nil
+	}
+	addPlaceholder := func(field string, t ChatMessagePartType, key string) {
+		ret = append(ret, TemplateVariable{Name: key, Field: field, Optional: t == partTypeOptionalPlaceholder})
+	}
+
+	if err := add("content", m.Content); err != nil {
+		return nil, err
+	}
+	for i, part := range m.MultiContent {
+		field := fmt.Sprintf("multi_content[%d]", i)
+		var err error
+		switch {
+		case isPlaceholderPartType(part.Type):
+			addPlaceholder(field, part.Type, part.Text)
+		case part.Type == ChatMessagePartTypeText:
+			err = add(field+".text", part.Text)
+		case part.Type == ChatMessagePartTypeImageURL && part.ImageURL != nil:
+			err = add(field+".url", part.ImageURL.URL)
+		case part.Type == ChatMessagePartTypeAudioURL && part.AudioURL != nil:
+			err = add(field+".url", part.AudioURL.URL)
+		case part.Type == ChatMessagePartTypeVideoURL && part.VideoURL != nil:
+			err = add(field+".url", part.VideoURL.URL)
+		case part.Type == ChatMessagePartTypeFileURL && part.FileURL != nil:
+			err = add(field+".url", part.FileURL.URL)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i, part := range m.UserInputMultiContent {
+		field := fmt.Sprintf("user_input_multi_content[%d]", i)
+		var common *MessagePartCommon
+		switch {
+		case isPlaceholderPartType(part.Type):
+			addPlaceholder(field, part.Type, part.Text)
+		case part.Type == ChatMessagePartTypeText:
+			if err := add(field+".text", part.Text); err != nil {
+				return nil, err
+			}
+		case part.Type == ChatMessagePartTypeImageURL && part.Image != nil:
+			common = &part.Image.MessagePartCommon
+		case part.Type == ChatMessagePartTypeAudioURL && part.Audio != nil:
+			common = &part.Audio.MessagePartCommon
+		case part.Type == ChatMessagePartTypeVideoURL && part.Video != nil:
+			common = &part.Video.MessagePartCommon
+		case part.Type == ChatMessagePartTypeFileURL && part.File != nil:
+			common = &part.File.MessagePartCommon
+		}
+		if common == nil {
+			continue
+		}
+		if common.URL != nil {
+			if err := add(field+".url", *common.URL); err != nil {
+				return nil, err
+			}
+		}
+		if common.Base64Data != nil {
+			if err := add(field+".base64data", *common.Base64Data); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return ret, nil
+}
+
+// Variables returns the variable of the placeholder.
+func (p *messagesPlaceholder) Variables(_ FormatType) ([]TemplateVariable, error) {
+	return []TemplateVariable{{Name: p.key, Field: "placeholder", Optional: p.optional}}, nil
+}
+
+func contentVariables(content string, formatType FormatType) ([]TemplateVariable, error) {
+	switch formatType {
+	case FString:
+		return fStringVariables(content)
+	case GoTemplate:
+		return goTemplateVariables(content)
+	case Jinja2:
+		return jinja2Variables(content)
+	default:
+		return nil, fmt.Errorf("unknown format type: %v", formatType)
+	}
+}
+
+// fStringVariables finds the replacement fields of pyfmt, e.g. {name}, {user.name}, {items[0]} and {score:.2f},
+// the empty field {} renders all the variables, and is not reported.
+func fStringVariables(content string) ([]TemplateVariable, error) {
+	var ret []TemplateVariable
+	for i := 0; i < len(content); i++ {
+		switch content[i] {
+		case '{':
+			if i+1 < len(content) && content[i+1] == '{' {
+				i++
+				continue
+			}
+			end := strings.IndexByte(content[i+1:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed '{' at %d", i)
+			}
+			name := content[i+1 : i+1+end]
+			if j := strings.IndexAny(name, ".[:"); j >= 0 {
+				name = name[:j]
+			}
+			if name != "" {
+				ret = append(ret, TemplateVariable{Name: name, Position: i})
+			}
+			i += end + 1
+		case '}':
+			if i+1 < len(content) && content[i+1] == '}' {
+				i++
+				continue
+			}
+			return nil, fmt.Errorf("single '}' at %d", i)
+		}
+	}
+	return ret, nil
+}
+
+// goTemplateVariables finds the fields of the dot, e.g. {{.name}}, and the fields of the root, e.g. {{$.name}},
+// the fields in the bodies of range and with are of the element, and not reported.
+func goTemplateVariables(content string) ([]TemplateVariable, error) {
+	tree := parse.New("template")
+	tree.Mode = parse.SkipFuncCheck
+	if _, err := tree.Parse(content, "", "", make(map[string]*parse.Tree)); err != nil {
+		return nil, err
+	}
+	var ret []TemplateVariable
+	var walk func(node parse.Node, dotIsRoot bool)
+	walk = func(node parse.Node, dotIsRoot bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, dotIsRoot)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, dotIsRoot)
+		case *parse.TemplateNode:
+			walk(n.Pipe, dotIsRoot)
+		case *parse.IfNode:
+			walk(n.Pipe, dotIsRoot)
+			walk(n.List, dotIsRoot)
+			walk(n.ElseList, dotIsRoot)
+		case *parse.RangeNode:
+			walk(n.Pipe, dotIsRoot)
+			walk(n.List, false)
+			walk(n.ElseList, dotIsRoot)
+		case *parse.WithNode:
+			walk(n.Pipe, dotIsRoot)
+			walk(n.List, false)
+			walk(n.ElseList, dotIsRoot)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd, dotIsRoot)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, dotIsRoot)
+			}
+		case *parse.ChainNode:
+			walk(n.Node, dotIsRoot)
+		case *parse.FieldNode:
+			if dotIsRoot {
+				ret = append(ret, TemplateVariable{Name: n.Ident[0], Position: int(n.Pos)})
+			}
+		case *parse.VariableNode:
+			if n.Ident[0] == "$" && len(n.Ident) > 1 {
+				ret = append(ret, TemplateVariable{Name: n.Ident[1], Position: int(n.Pos)})
+			}
+		}
+	}
+	walk(tree.Root, true)
+	return ret, nil
+}
+
+var jinja2Keywords = map[string]bool{
+	"if": true, "elif": true, "else": true, "endif": true, "for": true, "endfor": true, "in": true, "not": true,
+	"and": true, "or": true, "is": true, "set": true, "endset": true, "macro": true, "endmacro": true, "call": true,
+	"endcall": true, "with": true, "endwith": true, "block": true, "endblock": true, "extends": true, "include": true,
+	"import": true, "from": true, "as": true, "filter": true, "endfilter": true, "autoescape": true, "endautoescape": true,
+	"raw": true, "endraw": true, "recursive": true, "ignore": true, "missing": true, "context": true, "without": true,
+	"true": true, "false": true, "none": true, "True": true, "False": true, "None": true,
+	"loop": true, "super": true, "self": true, "caller": true, "varargs": true, "kwargs": true,
+	"range": true, "dict": true, "lipsum": true, "cycler": true, "joiner": true, "namespace": true,
+}
+
+// jinja2Variables finds the names in the expressions of the tags, except the attributes, filters, tests, keyword arguments,
+// and the names bound by the statements.
+func jinja2Variables(content string) ([]TemplateVariable, error) {
+	// the tokens of each tag, without whitespaces
+	var tags [][]*tokens.Token
+	var tag []*tokens.Token
+	stream := tokens.Lex(content)
+	for ; !stream.End(); stream.Next() {
+		tok := stream.Current()
+		switch tok.Type {
+		case tokens.VariableBegin, tokens.BlockBegin:
+			tag = []*tokens.Token{tok}
+		case tokens.VariableEnd, tokens.BlockEnd:
+			if tag != nil {
+				tags = append(tags, append(tag, tok))
+			}
+			tag = nil
+		default:
+			if tag != nil {
+				tag = append(tag, tok)
+			}
+		}
+	}
+	if stream.IsError() {
+		return nil, fmt.Errorf("invalid template at %d: %s", stream.Current().Pos, stream.Current().Val)
+	}
+
+	bound := make(map[string]bool)
+	for _, tag := range tags {
+		if tag[0].Type != tokens.BlockBegin || len(tag) < 3 {
+			continue
+		}
+		body := tag[1 : len(tag)-1]
+		switch body[0].Val {
+		case "for":
+			for _, tok := range body[1:] {
+				if tok.Type == tokens.In {
+					break
+				}
+				if tok.Type == tokens.Name {
+					bound[tok.Val] = true
+				}
+			}
+		case "set", "macro":
+			for i, tok := range body[1:] {
+				if tok.Type == tokens.Assign && body[0].Val == "set" {
+					break
+				}
+				if tok.Type == tokens.Name && body[i].Type != tokens.Assign {
+					bound[tok.Val] = true
+				}
+			}
+		case "with":
+			for i, tok := range body[1:] {
+				if tok.Type == tokens.Name && i+2 < len(body) && body[i+2].Type == tokens.Assign {
+					bound[tok.Val] = true
+				}
+			}
+		case "import", "from":
+			for i, tok := range body[1:] {
+				if tok.Type == tokens.Name && (body[i].Val == "as" || body[i].Val == "import" || body[i].Type == tokens.Comma) {
+					bound[tok.Val] = true
+				}
+			}
+		}
+	}
+
+	var ret []TemplateVariable
+	for _, tag := range tags {
+		body := tag[1 : len(tag)-1]
+		if tag[0].Type == tokens.BlockBegin && len(body) > 0 {
+			switch body[0].Val {
+			case "block", "endblock", "filter", "import", "from", "extends", "include":
+				continue
+			}
+		}
+		parens := 0
+		for i, tok := range body {
+			switch tok.Type {
+			case tokens.Lparen:
+				parens++
+			case tokens.Rparen:
+				parens--
+			}
+			if tok.Type != tokens.Name || jinja2Keywords[tok.Val] || bound[tok.Val] {
+				continue
+			}
+			if i > 0 {
+				prev := body[i-1]
+				if prev.Type == tokens.Dot || prev.Type == tokens.Pipe || prev.Type == tokens.Is ||
+					(prev.Type == tokens.Not && i > 1 && body[i-2].Type == tokens.Is) {
+					continue
+				}
+			}
+			if parens > 0 && i+1 < len(body) && body[i+1].Type == tokens.Assign {
+				continue
+			}
+			ret = append(ret, TemplateVariable{Name: tok.Val, Position: tok.Pos})
+		}
+	}
+	return ret, nil
+}
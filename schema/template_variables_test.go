@@ -0,0 +1,90 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateVariables(t *testing.T) {
+	names := func(vars []TemplateVariable) []string {
+		ret := make([]string, len(vars))
+		for i, v := range vars {
+			ret[i] = v.Name
+		}
+		return ret
+	}
+
+	t.Run("fstring", func(t *testing.T) {
+		vars, err := contentVariables("{greeting}, {user.name}! {{literal}} {items[0]} {score:.2f} {}", FString)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"greeting", "user", "items", "score"}, names(vars))
+		assert.Equal(t, 0, vars[0].Position)
+		assert.Equal(t, 12, vars[1].Position)
+
+		_, err = contentVariables("{unclosed", FString)
+		assert.Error(t, err)
+		_, err = contentVariables("single }", FString)
+		assert.Error(t, err)
+	})
+
+	t.Run("go template", func(t *testing.T) {
+		vars, err := contentVariables(`{{.greeting}} {{if eq .lang "en"}}{{.name}}{{end}}{{range .items}}{{.title}}{{$.sep}}{{end}}`, GoTemplate)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"greeting", "lang", "name", "items", "sep"}, names(vars))
+		assert.Equal(t, 2, vars[0].Position)
+
+		_, err = contentVariables("{{.unclosed", GoTemplate)
+		assert.Error(t, err)
+	})
+
+	t.Run("jinja2", func(t *testing.T) {
+		vars, err := contentVariables(
+			"{{ greeting | upper }} {% for item in items %}{{ item.title }}{{ loop.index }}{% endfor %}"+
+				"{% set total = items | length %}{{ total }}{% if user.vip is defined and not banned %}{{ format(x, width=2) }}{% endif %}", Jinja2)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"greeting", "items", "items", "user", "banned", "format", "x"}, names(vars))
+		assert.Equal(t, 3, vars[0].Position)
+	})
+
+	t.Run("message", func(t *testing.T) {
+		url := "https://example.com/{image}.png"
+		msg := &Message{
+			Role:    User,
+			Content: "{question}",
+			UserInputMultiContent: []MessageInputPart{
+				{Type: ChatMessagePartTypeText, Text: "describe {subject}"},
+				{Type: ChatMessagePartTypeImageURL, Image: &MessageInputImage{MessagePartCommon: MessagePartCommon{URL: &url}}},
+				MessageInputPartsPlaceholder("pictures", true),
+			},
+		}
+		vars, err := msg.Variables(FString)
+		assert.NoError(t, err)
+		assert.Equal(t, []TemplateVariable{
+			{Name: "question", Field: "content"},
+			{Name: "subject", Field: "user_input_multi_content[0].text", Position: 9},
+			{Name: "image", Field: "user_input_multi_content[1].url", Position: 20},
+			{Name: "pictures", Field: "user_input_multi_content[2]", Optional: true},
+		}, vars)
+
+		vars, err = MessagesPlaceholder("history", false).(VariablesTemplate).Variables(FString)
+		assert.NoError(t, err)
+		assert.Equal(t, []TemplateVariable{{Name: "history", Field: "placeholder"}}, vars)
+	})
+}
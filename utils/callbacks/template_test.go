@@ -30,6 +30,7 @@ import (
 	"github.com/cloudwego/eino/components/indexer"
 	"github.com/cloudwego/eino/components/model"
 	"github.com/cloudwego/eino/components/prompt"
+	"github.com/cloudwego/eino/components/reranker"
 	"github.com/cloudwego/eino/components/retriever"
 	"github.com/cloudwego/eino/components/tool"
 	"github.com/cloudwego/eino/compose"
@@ -288,3 +289,39 @@ func TestNewComponentTemplate(t *testing.T) {
 		assert.Equal(t, 30, cnt)
 	})
 }
+
+type fakeReranker struct{}
+
+func (f *fakeReranker) Rerank(ctx context.Context, query string, docs []*schema.Document, opts ...reranker.Option) ([]*schema.Document, error) {
+	return []*schema.Document{docs[1], docs[0]}, nil
+}
+
+func TestRerankerCallbackHandler(t *testing.T) {
+	ctx := context.Background()
+
+	var input *reranker.CallbackInput
+	var output *reranker.CallbackOutput
+	handler := NewHandlerHelper().Reranker(&RerankerCallbackHandler{
+		OnStart: func(ctx context.Context, runInfo *callbacks.RunInfo, in *reranker.CallbackInput) context.Context {
+			assert.Equal(t, components.ComponentOfReranker, runInfo.Component)
+			input = in
+			return ctx
+		},
+		OnEnd: func(ctx context.Context, runInfo *callbacks.RunInfo, out *reranker.CallbackOutput) context.Context {
+			output = out
+			return ctx
+		},
+	}).Handler()
+
+	r, err := compose.NewChain[*reranker.Input, []*schema.Document]().
+		AppendReranker(&fakeReranker{}).
+		Compile(ctx)
+	assert.NoError(t, err)
+
+	docs := []*schema.Document{{ID: "1"}, {ID: "2"}}
+	out, err := r.Invoke(ctx, &reranker.Input{Query: "q", Docs: docs}, compose.WithCallbacks(handler))
+	assert.NoError(t, err)
+	assert.Equal(t, "2", out[0].ID)
+	assert.Equal(t, &reranker.CallbackInput{Query: "q", Docs: docs}, input)
+	assert.Equal(t, out, output.Docs)
+}
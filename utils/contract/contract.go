@@ -0,0 +1,211 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+// Package contract exports the JSON Schemas and the sample payloads of the core types serialized by eino,
+// so that the clients in other languages, e.g. TypeScript or Python frontends, can validate the payloads
+// and generate their types against a canonical contract.
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"time"
+
+	"github.com/eino-contrib/jsonschema"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/internal/serialization"
+	"github.com/cloudwego/eino/schema"
+)
+
+const (
+	// TypeMessage is the name of schema.Message.
+	TypeMessage = "Message"
+	// TypeAgentEvent is the name of the serialized adk.AgentEvent, i.e. adk.RunEventRecord.
+	TypeAgentEvent = "AgentEvent"
+	// TypeCheckpointEnvelope is the name of the envelope of the checkpoints serialized by the default serializer of compose,
+	// in which each value carries its Go type.
+	TypeCheckpointEnvelope = "CheckpointEnvelope"
+)
+
+// Type is a core type exported by the contract.
+type Type struct {
+	// Name is the name of the type, e.g. TypeMessage.
+	Name   string
+	Schema *jsonschema.Schema
+	// Sample is a sample payload of the type, which is valid against the Schema.
+	Sample json.RawMessage
+}
+
+// Types returns the JSON Schemas and the sample payloads of Message, AgentEvent and CheckpointEnvelope.
+// The schemas allow additional properties, so that the payloads of the later versions stay valid.
+func Types() ([]*Type, error) {
+	envelope, err := sampleEnvelope()
+	if err != nil {
+		return nil, err
+	}
+
+	defs := []struct {
+		name        string
+		description string
+		v           any
+		sample      any
+	}{
+		{TypeMessage, "A message of a conversation, the input and the output of a chat model.",
+			&schema.Message{}, sampleMessage()},
+		{TypeAgentEvent, "An event emitted by an agent run, in which a streamed message is concatenated.",
+			&adk.RunEventRecord{}, sampleEvent()},
+		{TypeCheckpointEnvelope, "A value serialized by the default checkpoint serializer, in which each value carries its Go type.",
+			&serialization.Envelope{}, envelope},
+	}
+
+	types := make([]*Type, 0, len(defs))
+	for _, d := range defs {
+		sample, err := json.MarshalIndent(d.sample, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("marshal sample of %s fail: %w", d.name, err)
+		}
+		s := reflector().Reflect(d.v)
+		if d.name == TypeCheckpointEnvelope {
+			nullableValues(s.Definitions[TypeCheckpointEnvelope])
+		}
+		s.Title = d.name
+		s.Description = d.description
+		types = append(types, &Type{Name: d.name, Schema: s, Sample: sample})
+	}
+	return types, nil
+}
+
+// Generate writes the schema of each type to "<name>.schema.json" and the sample to "<name>.sample.json" in dir.
+// e.g.
+//
+//	err := contract.Generate("./contract")
+//	// then generate the client types, e.g. json2ts contract/Message.schema.json > message.d.ts
+func Generate(dir string) error {
+	types, err := Types()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("create dir fail: %w", err)
+	}
+	for _, t := range types {
+		data, err := json.MarshalIndent(t.Schema, "", "  ")
+		if err != nil {
+			return fmt.Errorf("marshal schema of %s fail: %w", t.Name, err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, t.Name+".schema.json"), data, 0o644); err != nil {
+			return fmt.Errorf("write schema of %s fail: %w", t.Name, err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, t.Name+".sample.json"), t.Sample, 0o644); err != nil {
+			return fmt.Errorf("write sample of %s fail: %w", t.Name, err)
+		}
+	}
+	return nil
+}
+
+func reflector() *jsonschema.Reflector {
+	return &jsonschema.Reflector{
+		AllowAdditionalProperties: true,
+		Namer:                     typeName,
+		Mapper:                    mapType,
+	}
+}
+
+var (
+	eventType        = reflect.TypeOf(adk.RunEventRecord{})
+	envelopeType     = reflect.TypeOf(serialization.Envelope{})
+	envelopeTypeType = reflect.TypeOf(serialization.EnvelopeType{})
+	roleType         = reflect.TypeOf(schema.RoleType(""))
+)
+
+// typeName names the definitions, the types are renamed to the contract names, e.g. RunEventRecord to AgentEvent.
+// The root of each schema refers to the definition of the type, as the envelope refers to itself.
+func typeName(t reflect.Type) string {
+	switch t {
+	case eventType:
+		return TypeAgentEvent
+	case envelopeType:
+		return TypeCheckpointEnvelope
+	case envelopeTypeType:
+		return "CheckpointValueType"
+	default:
+		return t.Name()
+	}
+}
+
+func mapType(t reflect.Type) *jsonschema.Schema {
+	if t == roleType {
+		return &jsonschema.Schema{
+			Type: "string",
+			Enum: []any{schema.Assistant, schema.User, schema.System, schema.Tool},
+		}
+	}
+	return nil
+}
+
+// nullableValues allows the nil values in the maps and the slices of the envelope, e.g. a nil field of a struct.
+func nullableValues(envelope *jsonschema.Schema) {
+	nullable := func(s *jsonschema.Schema) *jsonschema.Schema {
+		return &jsonschema.Schema{AnyOf: []*jsonschema.Schema{{Ref: s.Ref}, {Type: "null"}}}
+	}
+	if p, ok := envelope.Properties.Get("MapValues"); ok && p.AdditionalProperties != nil {
+		p.AdditionalProperties = nullable(p.AdditionalProperties)
+	}
+	if p, ok := envelope.Properties.Get("SliceValues"); ok && p.Items != nil {
+		p.Items = nullable(p.Items)
+	}
+}
+
+var sampleTime = time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func sampleMessage() *schema.Message {
+	msg := schema.AssistantMessage("", []schema.ToolCall{{
+		ID:       "call_1",
+		Type:     "function",
+		Function: schema.FunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+	}})
+	msg.ResponseMeta = &schema.ResponseMeta{
+		FinishReason: "tool_calls",
+		Usage:        &schema.TokenUsage{PromptTokens: 20, CompletionTokens: 10, TotalTokens: 30},
+	}
+	return msg
+}
+
+func sampleEvent() *adk.RunEventRecord {
+	return &adk.RunEventRecord{
+		Seq:       1,
+		AgentName: "weather_agent",
+		RunPath:   []string{"weather_agent"},
+		Message:   schema.ToolMessage("sunny, 25°C", "call_1", schema.WithToolName("get_weather")),
+		Role:      schema.Tool,
+		ToolName:  "get_weather",
+		CreatedAt: sampleTime,
+	}
+}
+
+func sampleEnvelope() (json.RawMessage, error) {
+	data, err := (&serialization.InternalSerializer{}).Marshal(map[string]any{
+		"messages": []*schema.Message{schema.UserMessage("what's the weather in Paris?"), sampleMessage()},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshal sample envelope fail: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,80 @@
+/*
+ * Copyright 2025 CloudWeGo Authors
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package contract
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/cloudwego/eino/adk"
+	"github.com/cloudwego/eino/internal/schemavalidate"
+	"github.com/cloudwego/eino/internal/serialization"
+	"github.com/cloudwego/eino/schema"
+)
+
+func TestTypes(t *testing.T) {
+	types, err := Types()
+	assert.NoError(t, err)
+	assert.Len(t, types, 3)
+
+	for _, typ := range types {
+		assert.Equal(t, typ.Name, typ.Schema.Title)
+		assert.Empty(t, schemavalidate.Validate(typ.Schema, string(typ.Sample)), typ.Name)
+	}
+
+	msgSchema := types[0].Schema
+	assert.Empty(t, schemavalidate.Validate(msgSchema, `{"role":"user","content":"hi","extra_field":1}`))
+	assert.NotEmpty(t, schemavalidate.Validate(msgSchema, `{"role":"robot","content":"hi"}`))
+	assert.NotEmpty(t, schemavalidate.Validate(msgSchema, `{"role":"user","content":1}`))
+
+	assert.NotEmpty(t, schemavalidate.Validate(types[2].Schema, `{"MapValues":[]}`))
+
+	var msg schema.Message
+	assert.NoError(t, json.Unmarshal(types[0].Sample, &msg))
+	assert.Equal(t, "get_weather", msg.ToolCalls[0].Function.Name)
+
+	var event adk.RunEventRecord
+	assert.NoError(t, json.Unmarshal(types[1].Sample, &event))
+	assert.Equal(t, "weather_agent", event.AgentName)
+
+	var v map[string]any
+	assert.NoError(t, (&serialization.InternalSerializer{}).Unmarshal(types[2].Sample, &v))
+	msgs := v["messages"].([]*schema.Message)
+	assert.Len(t, msgs, 2)
+	assert.Equal(t, "call_1", msgs[1].ToolCalls[0].ID)
+}
+
+func TestGenerate(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "contract")
+	assert.NoError(t, Generate(dir))
+
+	for _, name := range []string{TypeMessage, TypeAgentEvent, TypeCheckpointEnvelope} {
+		data, err := os.ReadFile(filepath.Join(dir, name+".schema.json"))
+		assert.NoError(t, err)
+		var s map[string]any
+		assert.NoError(t, json.Unmarshal(data, &s))
+		assert.Equal(t, name, s["title"])
+
+		data, err = os.ReadFile(filepath.Join(dir, name+".sample.json"))
+		assert.NoError(t, err)
+		assert.True(t, json.Valid(data))
+	}
+}